@@ -0,0 +1,18 @@
+package action
+
+import (
+	"github.com/gopasspw/gopass/internal/create"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Add runs the guided wizard for adding a new login.
+func (s *Action) Add(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	out.Printf(ctx, "🌟 Welcome to gopass add! Answer a few questions to store a new login.")
+	out.Printf(ctx, "🧪 Hint: Use 'gopass create' for other kinds of secrets.")
+
+	return create.Add(ctx, c, s.Store, s.createPrintOrCopy)
+}
@@ -0,0 +1,62 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdd(t *testing.T) { //nolint:paralleltest
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, true)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	termio.Stderr = &bytes.Buffer{}
+	defer func() {
+		out.Stdout = os.Stdout
+		termio.Stdin = os.Stdin
+		termio.Stderr = os.Stderr
+	}()
+
+	// url, username, generate, no passphrase, length, no symbols, no strict
+	// rules, otp seed, tags.
+	termio.Stdin = strings.NewReader("https://www.example.org/login\njohn\ny\nn\n16\nn\nn\nJBSWY3DPEHPK3PXP\nwork, mail\n")
+	require.NoError(t, act.Add(gptest.CliCtxWithFlags(ctx, t, map[string]string{"print": "true"})))
+	assert.Contains(t, buf.String(), "The generated password for websites/www.example.org/john is:")
+
+	sec, err := act.Store.Get(ctx, "websites/www.example.org/john")
+	require.NoError(t, err)
+	assert.Len(t, sec.Password(), 16)
+
+	for k, v := range map[string]string{
+		"url":      "https://www.example.org/login",
+		"username": "john",
+		"totp":     "JBSWY3DPEHPK3PXP",
+		"tags":     "mail, work",
+	} {
+		got, found := sec.Get(k)
+		assert.True(t, found, k)
+		assert.Equal(t, v, got, k)
+	}
+
+	// an invalid OTP seed aborts before anything is written.
+	termio.Stdin = strings.NewReader("example.com\njane\ny\nn\n16\nn\nn\nnot-a-seed!\n\n")
+	assert.Error(t, act.Add(gptest.CliCtx(ctx, t)))
+	assert.False(t, act.Store.Exists(ctx, "websites/example.com/jane"))
+}
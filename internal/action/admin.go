@@ -0,0 +1,107 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// AdminRevokeUser removes a recipient from all stores and folders, re-encrypts
+// the secrets they could read and flags these for rotation.
+func (s *Action) AdminRevokeUser(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	rec := c.Args().First()
+	if rec == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s admin revoke-user <recipient>", s.Name)
+	}
+
+	if kl, err := s.Store.Crypto(ctx, "").FindIdentities(ctx, rec); err == nil && len(kl) > 0 {
+		out.Warningf(ctx, "%s is one of your own identities", rec)
+	}
+
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Remove %s from all stores and re-encrypt all secrets they could read?", rec)) {
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	tag := c.String("tag")
+	started := time.Now()
+	revs, err := s.Store.RevokeRecipient(ctx, rec, tag)
+	if err != nil {
+		out.Errorf(ctx, "%s", err)
+	}
+
+	if len(revs) < 1 {
+		if err != nil {
+			return exit.Error(exit.Recipients, err, "failed to revoke %s: %s", rec, err)
+		}
+
+		return exit.Error(exit.NotFound, nil, "%s is not a recipient of any store", rec)
+	}
+
+	render := func(w io.Writer) error {
+		return renderRevocation(w, s.Name, rec, tag, started, revs)
+	}
+	if fn := c.String("output-file"); fn != "" {
+		if err := saveReport(ctx, render, fn, "txt"); err != nil {
+			return err
+		}
+	} else if err := render(stdout); err != nil {
+		return exit.Error(exit.IO, err, "failed to write report: %s", err)
+	}
+
+	if err != nil {
+		return exit.Error(exit.Recipients, err, "failed to revoke %s from some stores: %s", rec, err)
+	}
+
+	return nil
+}
+
+// renderRevocation writes the offboarding report.
+func renderRevocation(w io.Writer, name, rec, tag string, started time.Time, revs map[string]*leaf.Revocation) error {
+	var exposed int
+	for _, rev := range revs {
+		exposed += len(rev.Exposed)
+	}
+
+	fmt.Fprintf(w, "Offboarding report for %s\n", rec)
+	fmt.Fprintf(w, "Date: %s\n", started.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Stores: %d, secrets to rotate: %d\n", len(revs), exposed)
+
+	for _, alias := range set.SortedKeys(revs) {
+		rev := revs[alias]
+		label := alias
+		if label == "" {
+			label = "<root>"
+		}
+
+		fmt.Fprintf(w, "\nStore %s\n", label)
+		fmt.Fprintln(w, "  Removed from:")
+		for _, idf := range rev.IDFiles {
+			fmt.Fprintf(w, "    %s\n", idf)
+		}
+
+		fmt.Fprintf(w, "  Re-encrypted and to be rotated (%d):\n", len(rev.Exposed))
+		exp := append([]string{}, rev.Exposed...)
+		sort.Strings(exp)
+		for _, e := range exp {
+			fmt.Fprintf(w, "    %s\n", e)
+		}
+	}
+
+	fmt.Fprintln(w, "\nThe recipient can still read any copy and any old revision of these secrets.")
+	if tag != "" {
+		fmt.Fprintf(w, "All of them are tagged %q. List them with '%s ls --tag %s'.\n", tag, name, tag)
+	}
+	_, err := fmt.Fprintf(w, "Rotate each with '%s rotate --staged <secret>'.\n", name)
+
+	return err
+}
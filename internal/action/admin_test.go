@@ -0,0 +1,87 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminRevokeUser(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	assert.Error(t, act.AdminRevokeUser(gptest.CliCtx(ctx, t)))
+
+	require.NoError(t, act.RecipientsAdd(gptest.CliCtx(ctx, t, "0xFEEDBEEF")))
+
+	// a folder only readable by 0xFEEDBEEF and us.
+	require.NoError(t, os.MkdirAll(filepath.Join(u.StoreDir(""), "team"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(u.StoreDir(""), "team", plain.IDFile), []byte("0xDEADBEEF\n0xFEEDBEEF\n"), 0o600))
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, act.Store.Set(ctx, "team/db", sec))
+	buf.Reset()
+
+	t.Run("revoke unknown recipient", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.AdminRevokeUser(gptest.CliCtx(ctx, t, "0xBADC0FFEE")))
+	})
+
+	t.Run("revoke 0xFEEDBEEF", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.AdminRevokeUser(gptest.CliCtxWithFlags(ctx, t, map[string]string{"tag": "needs-rotation"}, "0xFEEDBEEF")))
+
+		report := buf.String()
+		assert.Contains(t, report, "Offboarding report for 0xFEEDBEEF")
+		assert.Contains(t, report, "    "+plain.IDFile+"\n")
+		assert.Contains(t, report, "    team/"+plain.IDFile+"\n")
+		assert.Contains(t, report, "    team/db\n")
+		assert.Contains(t, report, "ls --tag needs-rotation")
+
+		assert.NotContains(t, act.Store.ListRecipients(ctx, ""), "0xFEEDBEEF")
+		idf, err := os.ReadFile(filepath.Join(u.StoreDir(""), "team", plain.IDFile))
+		require.NoError(t, err)
+		assert.Equal(t, "0xDEADBEEF\n", string(idf))
+
+		sec, err := act.Store.Get(ctx, "team/db")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+		assert.Contains(t, root.Tags(sec), "needs-rotation")
+	})
+
+	t.Run("revoke again", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.AdminRevokeUser(gptest.CliCtx(ctx, t, "0xFEEDBEEF")))
+	})
+}
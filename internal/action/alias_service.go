@@ -0,0 +1,215 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/emailalias"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// defaultAliasTokenEntry holds the API token of the email alias service
+// unless alias-service.token-entry is set.
+const defaultAliasTokenEntry = "alias-service/token"
+
+func aliasTokenEntry(ctx context.Context) string {
+	if e := config.String(ctx, "alias-service.token-entry"); e != "" {
+		return e
+	}
+
+	return defaultAliasTokenEntry
+}
+
+// AliasService prints the configuration of the email alias service.
+func (s *Action) AliasService(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	p := emailalias.Provider(ctx)
+	if p == "" {
+		out.Noticef(ctx, "No email alias service configured. Run '%s alias-service setup' first", s.Name)
+
+		return nil
+	}
+
+	te := aliasTokenEntry(ctx)
+	token := "missing"
+	if s.Store.Exists(ctx, te) {
+		token = "ok"
+	}
+
+	fmt.Fprintf(stdout, "Provider:    %s\n", p)
+	fmt.Fprintf(stdout, "URL:         %s\n", emailalias.URL(ctx))
+	fmt.Fprintf(stdout, "Token entry: %s (%s)\n", te, token)
+
+	return nil
+}
+
+// AliasServiceSetup configures the email alias service and stores its API
+// token in the store.
+func (s *Action) AliasServiceSetup(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	p := strings.ToLower(c.String("provider"))
+	if p == "" {
+		act, sel := cui.GetSelection(ctx, "Which email alias service do you use?", emailalias.Providers())
+		if act != "default" {
+			return exit.Error(exit.Usage, nil, "Usage: %s alias-service setup --provider <%s>", s.Name, strings.Join(emailalias.Providers(), "|"))
+		}
+		p = emailalias.Providers()[sel]
+	}
+	if !set.Contains(emailalias.Providers(), p) {
+		return exit.Error(exit.Usage, nil, "Unknown email alias service %q. Supported services: %s", p, strings.Join(emailalias.Providers(), ", "))
+	}
+
+	te := c.String("token-entry")
+	if te == "" {
+		te = aliasTokenEntry(ctx)
+	}
+
+	token, err := termio.AskForPassword(ctx, fmt.Sprintf("the API token of %s", p), false)
+	if err != nil {
+		return exit.Error(exit.Aborted, err, "failed to read the API token: %s", err)
+	}
+	if token == "" {
+		return exit.Error(exit.Usage, nil, "The API token must not be empty")
+	}
+
+	sec := secrets.NewAKV()
+	sec.SetPassword(token)
+	_ = sec.Set("provider", p)
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Saved email alias service token"), te, sec); err != nil {
+		return exit.Error(exit.Encrypt, err, "failed to save the API token in %s: %s", te, err)
+	}
+
+	for k, v := range map[string]string{
+		"alias-service.provider":    p,
+		"alias-service.url":         c.String("url"),
+		"alias-service.domain":      c.String("domain"),
+		"alias-service.token-entry": c.String("token-entry"),
+	} {
+		if v == "" {
+			continue
+		}
+
+		if err := s.cfg.Set("", k, v); err != nil {
+			return exit.Error(exit.Config, err, "failed to set %s: %s", k, err)
+		}
+	}
+
+	out.OKf(ctx, "Configured %s. Use '%s generate --email-alias' to request an alias for new entries", p, s.Name)
+
+	return nil
+}
+
+// AliasServiceNew requests a new email alias and prints it.
+func (s *Action) AliasServiceNew(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	alias, err := s.newEmailAlias(ctx, strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to request an email alias: %s", err)
+	}
+
+	fmt.Fprintln(stdout, alias.Email)
+
+	return nil
+}
+
+// aliasToken reads the API token of the email alias service.
+func (s *Action) aliasToken(ctx context.Context) (string, error) {
+	if emailalias.Provider(ctx) == "" {
+		return "", emailalias.ErrNotConfigured
+	}
+
+	te := aliasTokenEntry(ctx)
+	sec, err := s.Store.Get(ctx, te)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the API token from %s: %w", te, err)
+	}
+
+	return sec.Password(), nil
+}
+
+// newEmailAlias requests a new alias from the configured service. The note
+// is shown next to the alias by the service.
+func (s *Action) newEmailAlias(ctx context.Context, note string) (emailalias.Alias, error) {
+	token, err := s.aliasToken(ctx)
+	if err != nil {
+		return emailalias.Alias{}, err
+	}
+
+	return emailalias.New(ctx, token, note)
+}
+
+// generateEmailAlias requests a new email alias for the entry and adds it to
+// kvps as email. It's used as the username, too, unless there is one. The
+// name of the entry is only sent to the service if alias-service.note is
+// enabled. It returns the new alias, if any.
+func (s *Action) generateEmailAlias(ctx context.Context, c *cli.Context, name string, kvps map[string]string) (*emailalias.Alias, error) {
+	if _, found := kvps["email"]; found {
+		return nil, nil
+	}
+
+	hasUsername := c.Bool("username")
+	if s.Store.Exists(ctx, name) && !c.Bool("force-regen") {
+		if sec, err := s.Store.Get(ctx, name); err == nil {
+			if e, found := sec.Get("email"); found && e != "" {
+				out.Noticef(ctx, "Keeping the email address %s of %s", e, name)
+
+				return nil, nil
+			}
+
+			if u, found := sec.Get("username"); found && u != "" {
+				hasUsername = true
+			}
+		}
+	}
+
+	note := ""
+	if config.Bool(ctx, "alias-service.note") {
+		note = name
+	}
+
+	alias, err := s.newEmailAlias(ctx, note)
+	if err != nil {
+		return nil, exit.Error(exit.Unknown, err, "failed to request an email alias: %s", err)
+	}
+	kvps["email"] = alias.Email
+
+	if _, found := kvps["username"]; !found && !hasUsername {
+		kvps["username"] = alias.Email
+	}
+
+	out.OKf(ctx, "Email alias for entry %q created: %s", name, alias.Email)
+
+	return &alias, nil
+}
+
+// discardEmailAlias removes an alias that was created for an entry that
+// could not be written.
+func (s *Action) discardEmailAlias(ctx context.Context, alias *emailalias.Alias) {
+	if alias == nil {
+		return
+	}
+
+	token, err := s.aliasToken(ctx)
+	if err == nil {
+		err = emailalias.Delete(ctx, token, *alias)
+	}
+	if err != nil {
+		out.Errorf(ctx, "Failed to remove the unused email alias %s: %s", alias.Email, err)
+
+		return
+	}
+
+	out.Noticef(ctx, "Removed the unused email alias %s", alias.Email)
+}
@@ -0,0 +1,127 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/emailalias"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasService(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	var note, deleted string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authentication") != "sl-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+		case r.URL.Path == "/api/alias/random/new":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			note = body["note"]
+			fmt.Fprint(w, `{"id": 7, "email": "quiet.heron@simplelogin.com"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, false)
+	ctx = termio.WithPassPromptFunc(ctx, func(context.Context, string) (string, error) {
+		return "sl-token", nil
+	})
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	assert.Error(t, act.AliasServiceNew(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.AliasServiceSetup(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.AliasServiceSetup(gptest.CliCtxWithFlags(ctx, t, map[string]string{"provider": "mailinator"})))
+	buf.Reset()
+
+	t.Run("setup", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.AliasServiceSetup(gptest.CliCtxWithFlags(ctx, t, map[string]string{"provider": "simplelogin", "url": ts.URL})))
+		sec, err := act.Store.Get(ctx, "alias-service/token")
+		require.NoError(t, err)
+		assert.Equal(t, "sl-token", sec.Password())
+
+		require.NoError(t, act.AliasService(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "Provider:    simplelogin\n")
+		assert.Contains(t, buf.String(), "Token entry: alias-service/token (ok)\n")
+	})
+
+	t.Run("new", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.AliasServiceNew(gptest.CliCtx(ctx, t, "forum")))
+		assert.Equal(t, "quiet.heron@simplelogin.com\n", buf.String())
+	})
+
+	t.Run("generate --email-alias", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"email-alias": "true"}, "anon/forum", "16")))
+		sec, err := act.Store.Get(ctx, "anon/forum")
+		require.NoError(t, err)
+		email, _ := sec.Get("email")
+		assert.Equal(t, "quiet.heron@simplelogin.com", email)
+		username, _ := sec.Get("username")
+		assert.Equal(t, "quiet.heron@simplelogin.com", username)
+
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"email-alias": "true", "username": "true"}, "anon/shop", "16")))
+		sec, err = act.Store.Get(ctx, "anon/shop")
+		require.NoError(t, err)
+		username, _ = sec.Get("username")
+		assert.NotEqual(t, "quiet.heron@simplelogin.com", username)
+		assert.Equal(t, "", note)
+
+		require.NoError(t, act.cfg.Set("", "alias-service.note", "true"))
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"email-alias": "true"}, "anon/blog", "16")))
+		assert.Equal(t, "anon/blog", note)
+	})
+
+	t.Run("unused aliases are removed", func(t *testing.T) {
+		defer buf.Reset()
+
+		act.discardEmailAlias(ctx, &emailalias.Alias{Email: "quiet.heron@simplelogin.com", ID: "7"})
+		assert.Equal(t, "/api/aliases/7", deleted)
+		assert.Contains(t, buf.String(), "Removed the unused email alias")
+	})
+}
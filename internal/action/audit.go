@@ -9,6 +9,7 @@ import (
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/audit"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -39,6 +40,15 @@ func (s *Action) Audit(c *cli.Context) error {
 
 	list := t.List(tree.INF)
 
+	tagged, ok, err := s.taggedEntries(ctx, c)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list tagged entries: %s", err)
+	}
+	if ok {
+		want := set.Map(tagged)
+		list = set.SortedFiltered(list, func(e string) bool { return want[e] })
+	}
+
 	if len(list) < 1 {
 		out.Printf(ctx, "No secrets found")
 
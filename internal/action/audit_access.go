@@ -0,0 +1,107 @@
+package action
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// AuditAccess reconstructs who could decrypt an entry during which time
+// ranges.
+func (s *Action) AuditAccess(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s audit access <entry>", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) {
+		// the entry might have been deleted. That's fine as long as there
+		// is some history left.
+		out.Warningf(ctx, "%q does not exist (anymore). Reconstructing access from the history", name)
+	}
+
+	periods, err := s.Store.AccessHistory(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to reconstruct access history of %q: %s", name, err)
+	}
+
+	crypto := s.Store.Crypto(ctx, name)
+	keyName := func(id string) string {
+		if crypto == nil {
+			return id
+		}
+
+		return crypto.FormatKey(ctx, id, "")
+	}
+
+	switch c.String("format") {
+	case "csv":
+		render := func(w io.Writer) error {
+			return renderAccessCSV(w, periods, keyName)
+		}
+		if fn := c.String("output-file"); fn != "" {
+			return saveReport(ctx, render, fn, "csv")
+		}
+
+		return render(stdout)
+	default:
+		printAccess(ctx, name, periods, keyName)
+	}
+
+	return nil
+}
+
+func printAccess(ctx context.Context, name string, periods []leaf.AccessPeriod, keyName func(string) string) {
+	if len(periods) < 1 {
+		out.Printf(ctx, "Nobody could decrypt %s", name)
+
+		return
+	}
+
+	for _, ap := range periods {
+		out.Printf(ctx, "%s - %s: %s (%s..%s, %s)", accessTime(ap.From), accessTime(ap.Until), keyName(ap.Recipient), ap.FirstRevision, ap.LastRevision, ap.Source)
+	}
+}
+
+func renderAccessCSV(w io.Writer, periods []leaf.AccessPeriod, keyName func(string) string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"recipient", "name", "from", "until", "first_revision", "last_revision", "source"}); err != nil {
+		return err
+	}
+
+	for _, ap := range periods {
+		if err := cw.Write([]string{
+			ap.Recipient,
+			keyName(ap.Recipient),
+			accessTime(ap.From),
+			accessTime(ap.Until),
+			ap.FirstRevision,
+			ap.LastRevision,
+			ap.Source,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// accessTime formats the bounds of an access period. The zero time means the
+// period is still open.
+func accessTime(ts time.Time) string {
+	if ts.IsZero() {
+		return "now"
+	}
+
+	return ts.Format(time.RFC3339)
+}
@@ -58,3 +58,41 @@ func TestAudit(t *testing.T) {
 		buf.Reset()
 	})
 }
+
+func TestAuditAccess(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	t.Run("no entry", func(t *testing.T) {
+		assert.Error(t, act.AuditAccess(gptest.CliCtx(ctx, t)))
+		buf.Reset()
+	})
+
+	t.Run("text", func(t *testing.T) {
+		assert.NoError(t, act.AuditAccess(gptest.CliCtx(ctx, t, "foo")))
+		assert.Contains(t, buf.String(), "now: ")
+		buf.Reset()
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "csv"}, "foo")
+		assert.NoError(t, act.AuditAccess(c))
+		assert.Contains(t, buf.String(), "recipient,name,from,until,first_revision,last_revision,source\n")
+		assert.Contains(t, buf.String(), ",now,latest,latest,ciphertext\n")
+		buf.Reset()
+	})
+}
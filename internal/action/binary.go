@@ -13,6 +13,7 @@ import (
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/fsutil"
@@ -196,7 +197,7 @@ func (s *Action) binaryCopyFromStoreToFile(ctx context.Context, from, to string,
 	if err := s.binaryValidate(ctx, buf, from); err != nil {
 		return fmt.Errorf("failed to validate the written data: %w", err)
 	}
-	if err := s.Store.Delete(ctx, from); err != nil {
+	if err := s.Store.Delete(leaf.WithNoDeleteEvent(ctx, true), from); err != nil {
 		return fmt.Errorf("failed to delete %q from the store: %w", from, err)
 	}
 
@@ -264,6 +265,10 @@ func (s *Action) binaryGet(ctx context.Context, name string) ([]byte, error) {
 // Sum decodes binary content and computes the SHA256 checksum.
 func (s *Action) Sum(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
+	if consumer := c.String("consumer"); consumer != "" {
+		return s.sumHMAC(ctx, c, consumer)
+	}
+
 	name := c.Args().First()
 	if name == "" {
 		return exit.Error(exit.Usage, nil, "Usage: %s sha256 name", c.App.Name)
@@ -0,0 +1,88 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/blueprint"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// BlueprintDiff shows how a store deviates from a blueprint.
+func (s *Action) BlueprintDiff(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s blueprint diff [--store <store>] <file>", s.Name)
+	}
+
+	bp, err := readBlueprint(fn)
+	if err != nil {
+		return exit.Error(exit.IO, err, "Failed to read blueprint: %s", err)
+	}
+
+	diffs, err := bp.Diff(ctx, s.Store, c.String("store"))
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "Failed to compare store with blueprint %s: %s", bp.Name, err)
+	}
+
+	if len(diffs) < 1 {
+		out.OKf(ctx, "Store matches blueprint %s", bp.Name)
+
+		return nil
+	}
+
+	for _, d := range diffs {
+		out.Printf(ctx, "%s", d)
+	}
+
+	return exit.Error(exit.Unknown, nil, "Store deviates from blueprint %s in %d places", bp.Name, len(diffs))
+}
+
+// initBlueprint creates the mounts of the blueprint and applies it to the
+// newly initialized store.
+func (s *Action) initBlueprint(ctx context.Context, bp *blueprint.Blueprint, alias string) error {
+	mounts := s.Store.Mounts()
+	for _, m := range bp.Mounts {
+		mp := path.Join(alias, m.Alias)
+		if _, found := mounts[mp]; found {
+			debug.Log("mount %s already exists", mp)
+
+			continue
+		}
+
+		keys := m.Recipients
+		if len(keys) < 1 {
+			keys = bp.Recipients
+		}
+
+		out.Printf(ctx, "🍭 Initializing mount %s from blueprint %s ...", mp, bp.Name)
+		if err := s.init(ctx, mp, m.Path, keys...); err != nil {
+			return fmt.Errorf("failed to initialize mount %s: %w", mp, err)
+		}
+	}
+
+	n, err := bp.Apply(ctx, s.Store, alias)
+	if err != nil {
+		return err
+	}
+
+	out.OKf(ctx, "Applied blueprint %s (%d changes)", bp.Name, n)
+
+	return nil
+}
+
+func readBlueprint(fn string) (*blueprint.Blueprint, error) {
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprint.Parse(buf)
+}
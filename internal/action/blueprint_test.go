@@ -0,0 +1,85 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprint(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	fn := filepath.Join(t.TempDir(), "blueprint.yml")
+	require.NoError(t, os.WriteFile(fn, []byte(`name: team
+folders:
+  - path: web
+    info: Credentials of the web servers
+    owners: [alice@example.org]
+    schema:
+      required: [username]
+templates:
+  web: "{{ .Content }}\nusername: \n"
+`), 0o600))
+
+	require.NoError(t, act.Store.Set(ctx, "web/prod", secrets.ParseAKV([]byte("secret\n"))))
+
+	t.Run("diff", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.BlueprintDiff(gptest.CliCtx(ctx, t, fn)))
+		assert.Contains(t, buf.String(), "web: folder note is missing")
+		assert.Contains(t, buf.String(), "web: owners are none instead of alice@example.org")
+		assert.Contains(t, buf.String(), "web: template is missing")
+		assert.Contains(t, buf.String(), "web/prod: required key username is missing")
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		defer buf.Reset()
+
+		// changes to owned folders wait for review, so fix the entry first.
+		require.NoError(t, act.Store.Set(ctx, "web/prod", secrets.ParseAKV([]byte("secret\nusername: bob\n"))))
+
+		bp, err := readBlueprint(fn)
+		require.NoError(t, err)
+		require.NoError(t, act.initBlueprint(ctx, bp, ""))
+
+		buf.Reset()
+		assert.NoError(t, act.BlueprintDiff(gptest.CliCtx(ctx, t, fn)))
+		assert.Contains(t, buf.String(), "Store matches blueprint team")
+		assert.Equal(t, []string{"alice@example.org"}, act.Store.FolderOwners(ctx, "web"))
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.BlueprintDiff(gptest.CliCtx(ctx, t)))
+	})
+}
@@ -0,0 +1,208 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/bugreport"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/selftest"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// bugreportLogLines is the number of lines taken from the end of the debug
+// log.
+const bugreportLogLines = 500
+
+// bugreportEnv are the environment variables, besides GOPASS_*, that change
+// the behavior of gopass or its backends.
+var bugreportEnv = []string{
+	"DISPLAY",
+	"EDITOR",
+	"GNUPGHOME",
+	"GPG_TTY",
+	"LANG",
+	"PAGER",
+	"PASSWORD_STORE_DIR",
+	"SHELL",
+	"TERM",
+	"WAYLAND_DISPLAY",
+	"XDG_SESSION_TYPE",
+}
+
+// Bugreport collects sanitized diagnostics into a zip archive that can be
+// attached to an issue: versions, backends, config, environment, the output
+// of the self test and, with --include-log, the end of the debug log. Secret
+// config values and the home directory are redacted.
+func (s *Action) Bugreport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	fn := c.String("output")
+	if fn == "" {
+		fn = fmt.Sprintf("gopass-bugreport-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	r := bugreport.New()
+	r.Add("version.txt", s.bugreportVersion())
+	r.Add("backends.txt", s.bugreportBackends(ctx))
+	r.Add("config.txt", s.bugreportConfig())
+	r.Add("env.txt", bugreportEnvironment())
+
+	if c.Bool("selftest") {
+		out.Printf(ctx, "Running the self test. Disable it with --selftest=false ...")
+		r.Add("selftest.txt", s.bugreportSelftest(ctx))
+	}
+
+	// the debug log isn't redacted, so it's only included on request.
+	lf := os.Getenv("GOPASS_DEBUG_LOG")
+	switch {
+	case lf == "":
+		out.Noticef(ctx, "No debug log found. Reproduce the issue with GOPASS_DEBUG_LOG=/tmp/gopass.log and run bugreport --include-log to include it")
+	case !c.Bool("include-log"):
+		out.Noticef(ctx, "The debug log %s is not included. Use --include-log to add its end after reviewing it", lf)
+	default:
+		excerpt, err := bugreport.Tail(lf, bugreportLogLines)
+		if err != nil {
+			out.Warningf(ctx, "Failed to read the debug log %s: %s", lf, err)
+		} else {
+			out.Warningf(ctx, "Including the last %d lines of the debug log %s. Entry names and other details in it are not redacted", bugreportLogLines, lf)
+			r.Add("debug.log", excerpt)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteZip(buf); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to create the bug report: %s", err)
+	}
+
+	if err := os.WriteFile(fn, buf.Bytes(), 0o600); err != nil {
+		return exit.Error(exit.IO, err, "failed to write %s: %s", fn, err)
+	}
+
+	out.OKf(ctx, "Wrote the bug report to %s with %s", fn, strings.Join(r.Names(), ", "))
+	out.Noticef(ctx, "Please review it before attaching it to an issue")
+
+	return nil
+}
+
+// bugreportVersion returns the versions of gopass and Go and the platform.
+func (s *Action) bugreportVersion() string {
+	return fmt.Sprintf("gopass %s\n%s %s/%s\n", s.version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+// bugreportBackends returns the backends of every mount with their versions,
+// e.g. of gpg and git.
+func (s *Action) bugreportBackends(ctx context.Context) string {
+	var sb strings.Builder
+
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	sort.Strings(mps)
+
+	for _, mp := range mps {
+		name := mp
+		if name == "" {
+			name = "<root>"
+		}
+
+		fmt.Fprintf(&sb, "%s:\n", name)
+		if crypto := s.Store.Crypto(ctx, mp); crypto != nil {
+			fmt.Fprintf(&sb, "  crypto:  %s %s\n", crypto.Name(), crypto.Version(ctx))
+		}
+		if storage := s.Store.Storage(ctx, mp); storage != nil {
+			fmt.Fprintf(&sb, "  storage: %s %s\n", storage.Name(), storage.Version(ctx))
+		}
+	}
+
+	return sb.String()
+}
+
+// bugreportConfig returns the config of the root store and every mount with
+// secret values redacted.
+func (s *Action) bugreportConfig() string {
+	var sb strings.Builder
+
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	sort.Strings(mps)
+
+	for _, mp := range mps {
+		if mp != "" {
+			fmt.Fprintf(&sb, "\n[%s]\n", mp)
+		}
+
+		keys := s.cfg.Keys(mp)
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "%s = %s\n", k, bugreport.Redact(k, s.cfg.GetM(mp, k)))
+		}
+	}
+
+	return sb.String()
+}
+
+// bugreportEnvironment returns the environment variables that affect gopass
+// with secret values redacted.
+func bugreportEnvironment() string {
+	var lines []string
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(k, "GOPASS_") && !contains(bugreportEnv, k) {
+			continue
+		}
+
+		lines = append(lines, k+"="+bugreport.Redact(k, v))
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// bugreportSelftest runs the self test with the backends of the root store
+// and returns its results.
+func (s *Action) bugreportSelftest(ctx context.Context) string {
+	ctx = queue.WithQueue(ctx, nil)
+
+	ctx, storageBe, recipient, err := s.selftestSetup(ctx, "", "", "")
+	if err != nil {
+		return fmt.Sprintf("self test not run: %s\n", err)
+	}
+
+	dir, err := os.MkdirTemp("", "gopass-selftest-")
+	if err != nil {
+		return fmt.Sprintf("self test not run: %s\n", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "crypto %s, storage %s\n", backend.CryptoBackendName(backend.GetCryptoBackend(ctx)), storageBe)
+
+	for _, r := range selftest.Run(ctx, dir, recipient) {
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(&sb, "%-10s FAILED after %s: %s\n", r.Step, r.Duration.Round(time.Millisecond), r.Err)
+		case r.Skipped:
+			fmt.Fprintf(&sb, "%-10s skipped\n", r.Step)
+		default:
+			fmt.Fprintf(&sb, "%-10s OK %s\n", r.Step, r.Duration.Round(time.Millisecond))
+		}
+
+		for _, line := range strings.Split(r.Diag, "\n") {
+			if line != "" {
+				fmt.Fprintf(&sb, "           %s\n", line)
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package action
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugreport(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	t.Setenv("GOPASS_DEBUG_LOG", "")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	require.NoError(t, act.cfg.SetEnv("alias-service.token", "hunter2"))
+
+	fn := filepath.Join(t.TempDir(), "report.zip")
+	require.NoError(t, act.Bugreport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"output": fn, "selftest": "false"})))
+
+	files := readZip(t, fn)
+	assert.Len(t, files, 4)
+	assert.Contains(t, files["version.txt"], "gopass ")
+	assert.Contains(t, files["backends.txt"], "<root>:\n  crypto:  plain")
+	assert.Contains(t, files["config.txt"], "alias-service.token = *****")
+	assert.NotContains(t, files["config.txt"], "hunter2")
+	assert.NotContains(t, files["config.txt"], u.Dir)
+
+	t.Run("debug log", func(t *testing.T) {
+		defer buf.Reset()
+
+		lf := filepath.Join(t.TempDir(), "gopass.log")
+		require.NoError(t, os.WriteFile(lf, []byte("decrypting bank/checking\n"), 0o600))
+		t.Setenv("GOPASS_DEBUG_LOG", lf)
+
+		require.NoError(t, act.Bugreport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"output": fn, "selftest": "false"})))
+		assert.NotContains(t, readZip(t, fn), "debug.log")
+		assert.Contains(t, buf.String(), "--include-log")
+
+		require.NoError(t, act.Bugreport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"output": fn, "selftest": "false", "include-log": "true"})))
+		assert.Contains(t, readZip(t, fn)["debug.log"], "bank/checking")
+		assert.Contains(t, buf.String(), "not redacted")
+	})
+}
+
+func readZip(t *testing.T, fn string) map[string]string {
+	t.Helper()
+
+	zr, err := zip.OpenReader(fn)
+	require.NoError(t, err)
+	defer zr.Close() //nolint:errcheck
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		fh, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(fh)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
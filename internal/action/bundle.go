@@ -0,0 +1,153 @@
+package action
+
+import (
+	"errors"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/fips"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// airGappedKey marks stores that are synced with bundles only.
+const airGappedKey = "core.airgapped"
+
+// BundleExport writes the history of a store to a bundle file, e.g. on
+// removable media.
+func (s *Action) BundleExport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s bundle export [--store <store>] [--full] [--encrypt|--passphrase] <file>", s.Name)
+	}
+
+	if c.Bool("encrypt") && c.Bool("passphrase") {
+		return exit.Error(exit.Usage, nil, "--encrypt and --passphrase are mutually exclusive")
+	}
+
+	sub, err := s.bundleStore(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("passphrase") {
+		// the key is derived with argon2id.
+		if fips.Enabled(ctx) {
+			return exit.Error(exit.Unsupported, fips.ErrNotApproved, "passphrase protected bundles use argon2id: %s", fips.ErrNotApproved)
+		}
+
+		pw, err := termio.AskForPassword(ctx, "the passphrase for the bundle", true)
+		if err != nil {
+			return exit.Error(exit.Aborted, err, "failed to read passphrase: %s", err)
+		}
+
+		if pw == "" {
+			return exit.Error(exit.Usage, nil, "the passphrase must not be empty")
+		}
+
+		ctx = leaf.WithBundlePassphrase(ctx, pw)
+	}
+
+	fn, err = sub.ExportBundle(ctx, fn, c.Bool("full"), c.Bool("encrypt"))
+	if err != nil {
+		if errors.Is(err, store.ErrEmptyBundle) {
+			out.Noticef(ctx, "Nothing to export: %s. Use --full to export the whole history", err)
+
+			return nil
+		}
+
+		return exit.Error(exit.Git, err, "failed to export bundle: %s", err)
+	}
+
+	out.OKf(ctx, "Exported bundle to %s", fn)
+
+	return nil
+}
+
+// BundleImport verifies a bundle and merges it into a store.
+func (s *Action) BundleImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s bundle import [--store <store>] <file>", s.Name)
+	}
+
+	sub, err := s.bundleStore(c)
+	if err != nil {
+		return err
+	}
+
+	if err := sub.ImportBundle(ctx, fn); err != nil {
+		return exit.Error(exit.Git, err, "failed to import bundle %s: %s", fn, err)
+	}
+
+	out.OKf(ctx, "Imported bundle %s", fn)
+
+	return nil
+}
+
+// BundleAck records that an exported bundle was imported on the other side,
+// so the next export only contains the changes after it.
+func (s *Action) BundleAck(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s bundle ack [--store <store>] <file>", s.Name)
+	}
+
+	sub, err := s.bundleStore(c)
+	if err != nil {
+		return err
+	}
+
+	if err := sub.AckBundle(ctx, fn); err != nil {
+		return exit.Error(exit.Git, err, "failed to acknowledge bundle %s: %s", fn, err)
+	}
+
+	out.OKf(ctx, "Acknowledged bundle %s. The next export starts after it", fn)
+
+	return nil
+}
+
+// BundleVerify checks that a bundle is intact and can be imported.
+func (s *Action) BundleVerify(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s bundle verify [--store <store>] <file>", s.Name)
+	}
+
+	sub, err := s.bundleStore(c)
+	if err != nil {
+		return err
+	}
+
+	if err := sub.VerifyBundle(ctx, fn); err != nil {
+		return exit.Error(exit.Git, err, "bundle %s can not be imported: %s", fn, err)
+	}
+
+	out.OKf(ctx, "Bundle %s is valid and can be imported", fn)
+
+	return nil
+}
+
+func (s *Action) bundleStore(c *cli.Context) (*leaf.Store, error) {
+	name := c.String("store")
+
+	sub, err := s.Store.GetSubStore(name)
+	if err != nil {
+		return nil, exit.Error(exit.NotFound, err, "mount %q not found: %s", name, err)
+	}
+
+	return sub, nil
+}
+
+// isAirGapped returns true if the store with the given mount point is only
+// synced with bundles.
+func (s *Action) isAirGapped(mp string) bool {
+	return s.cfg.GetM(mp, airGappedKey) == "true"
+}
@@ -0,0 +1,72 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	fn := filepath.Join(t.TempDir(), "store.bundle")
+
+	t.Run("export without file", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleExport(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("export to unsupported storage", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleExport(gptest.CliCtx(ctx, t, fn)))
+	})
+
+	t.Run("encrypt and passphrase are exclusive", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"encrypt": "true", "passphrase": "true"}, fn)))
+		assert.NoFileExists(t, fn)
+	})
+
+	t.Run("verify unknown store", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleVerify(gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": "foo"}, fn)))
+	})
+
+	t.Run("sync skips air-gapped stores", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.cfg.Set("", airGappedKey, "true"))
+		defer func() {
+			_ = act.cfg.Set("", airGappedKey, "false")
+		}()
+
+		ms, err := act.syncMount(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, "air-gapped", ms.Status)
+		assert.Contains(t, buf.String(), "gopass bundle")
+	})
+}
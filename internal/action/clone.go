@@ -87,6 +87,14 @@ func (s *Action) Clone(c *cli.Context) error {
 		return nil
 	}
 
+	// import and pin the public keys shipped with the store so we don't
+	// have to rely on keyservers.
+	if sub, err := s.Store.GetSubStore(mount); err == nil && sub != nil {
+		if err := sub.ImportMissingPublicKeys(ctx); err != nil {
+			out.Warningf(ctx, "Failed to import public keys: %s", err)
+		}
+	}
+
 	if !c.Bool("check-keys") {
 		return nil
 	}
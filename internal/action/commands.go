@@ -18,6 +18,24 @@ func ShowFlags() []cli.Flag {
 			Aliases: []string{"y"},
 			Usage:   "Always answer yes to yes/no questions",
 		},
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "Wait for other gopass processes to release the store lock instead of failing",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Only print errors, as JSON on stderr. Hides notices, status messages and desktop notifications",
+		},
+		&cli.BoolFlag{
+			Name:    "verbose",
+			Aliases: []string{"v"},
+			Usage:   "Print more details. Use -vv to enable debug logging, too",
+		},
+		&cli.BoolFlag{
+			Name:  "demo",
+			Usage: "Replace entry names and secrets with generated placeholders in the output, e.g. for screenshots and bug reports",
+		},
 		&cli.BoolFlag{
 			Name:    "clip",
 			Aliases: []string{"c"},
@@ -42,6 +60,10 @@ func ShowFlags() []cli.Flag {
 			Aliases: []string{"o"},
 			Usage:   "Display only the password. Takes precedence over all other flags.",
 		},
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Print exactly the password without a trailing newline and fail if it's ambiguous, e.g. if a password key differs from the first line. Implies --password",
+		},
 		&cli.StringFlag{
 			Name:    "revision",
 			Aliases: []string{"r"},
@@ -60,18 +82,142 @@ func ShowFlags() []cli.Flag {
 			Name:  "chars",
 			Usage: "Print specific characters from the secret",
 		},
+		&cli.BoolFlag{
+			Name:  "spell",
+			Usage: "Spell out the password using the NATO phonetic alphabet, e.g. to read it over the phone",
+		},
+		&cli.StringFlag{
+			Name:      "render",
+			Usage:     "Render the secret through this Go template file, e.g. to print a kubeconfig or a .netrc stanza",
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:  "as",
+			Usage: "Print the secret (or all secrets of a folder) as credentials for netrc, pgpass or mysql",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print one or more secrets as a JSON object mapping their names to their content. Use with --password to only print the passwords",
+		},
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "With --json: print all secrets below the given folders",
+		},
 	}
 }
 
 // GetCommands returns the cli commands exported by this module.
 func (s *Action) GetCommands() []*cli.Command {
 	cmds := []*cli.Command{
+		{
+			Name:      "add",
+			Usage:     "Add a new login with a guided wizard",
+			ArgsUsage: "[secret]",
+			Description: "" +
+				"This command asks step by step for the website, the username, whether to " +
+				"generate a password (following the password rules of the website) or enter " +
+				"one, an optional OTP seed and tags, and stores a complete entry. " +
+				"Unless a name is given the entry is stored as websites/<host>/<username>.",
+			Before: s.IsInitialized,
+			Action: s.Add,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "store",
+					Aliases: []string{"s"},
+					Usage:   "Which store to use",
+				},
+				&cli.BoolFlag{
+					Name:  "print",
+					Usage: "Print the generated password instead of copying it to the clipboard",
+				},
+			},
+		},
+		{
+			Name:  "admin",
+			Usage: "Administrative commands for team stores",
+			Description: "" +
+				"These commands help to manage stores shared by several users.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "revoke-user",
+					Usage:     "Remove a recipient from all stores",
+					ArgsUsage: "[recipient]",
+					Description: "" +
+						"This command removes a recipient from all stores, including the recipients " +
+						"of sub folders, and re-encrypts every secret the recipient could read. " +
+						"These secrets are tagged for rotation and listed in an offboarding report.",
+					Before: s.IsInitialized,
+					Action: s.AdminRevokeUser,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "tag",
+							Usage: "Tag the secrets the recipient could read. Set to an empty string to disable.",
+							Value: "needs-rotation",
+						},
+						&cli.StringFlag{
+							Name:    "output-file",
+							Aliases: []string{"o"},
+							Usage:   "Write the report to this file. Default: stdout",
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:        "alias",
 			Usage:       "Print domain aliases",
 			Description: "Print defined domain aliases.",
 			Action:      s.AliasesPrint,
 		},
+		{
+			Name:  "alias-service",
+			Usage: "Manage the email alias service",
+			Description: "" +
+				"gopass can request a fresh email alias from SimpleLogin or addy.io " +
+				"for new entries, see 'gopass generate --email-alias'. The API token " +
+				"is stored in the password store. " +
+				"Without a subcommand the current configuration is shown.",
+			Before: s.IsInitialized,
+			Action: s.AliasService,
+			Subcommands: []*cli.Command{
+				{
+					Name:  "setup",
+					Usage: "Configure the email alias service",
+					Description: "" +
+						"This command asks for the API token of the service and stores it " +
+						"in the password store.",
+					Before: s.IsInitialized,
+					Action: s.AliasServiceSetup,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "provider",
+							Usage: "The alias service: simplelogin or addy",
+						},
+						&cli.StringFlag{
+							Name:  "url",
+							Usage: "API endpoint of a self-hosted instance",
+						},
+						&cli.StringFlag{
+							Name:  "domain",
+							Usage: "Domain of new aliases. Only used by addy",
+						},
+						&cli.StringFlag{
+							Name:  "token-entry",
+							Usage: "Store the API token in this entry. Default: alias-service/token",
+						},
+					},
+				},
+				{
+					Name:      "new",
+					Usage:     "Request a new email alias",
+					ArgsUsage: "[note]",
+					Description: "" +
+						"This command requests a new email alias and prints it.",
+					Before: s.IsInitialized,
+					Action: s.AliasServiceNew,
+				},
+			},
+		},
 		{
 			Name:      "audit",
 			Usage:     "Decrypt all secrets and scan for weak or leaked passwords",
@@ -100,6 +246,179 @@ func (s *Action) GetCommands() []*cli.Command {
 					Name:  "failed",
 					Usage: "Report only entries that failed validation. Default: false (reports all)",
 				},
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Usage: "Only audit entries with this tag. Can be given multiple times.",
+				},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:      "access",
+					Usage:     "Show who could decrypt an entry over time",
+					ArgsUsage: "[secret]",
+					Description: "" +
+						"This command reconstructs from the history of the store which recipients " +
+						"could decrypt the given entry during which time ranges. The recipients are " +
+						"read from the encrypted revisions if possible and from the history of the " +
+						"recipients file otherwise.",
+					Before:       s.IsInitialized,
+					Action:       s.AuditAccess,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "Output format. text or csv. Default: text",
+							Value: "text",
+						},
+						&cli.StringFlag{
+							Name:    "output-file",
+							Aliases: []string{"o"},
+							Usage:   "Output filename. Used for csv. Default: stdout",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "blueprint",
+			Usage: "Compare stores with blueprints",
+			Description: "" +
+				"A blueprint is a YAML file describing the recipients, folders, templates and " +
+				"mounts of a store. Use 'gopass init --from-blueprint' to create a store from it.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "diff",
+					Usage:     "Show how a store deviates from a blueprint",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command lists the recipients, mounts, templates, folder notes and " +
+						"owners that are missing or differ from the blueprint and the entries " +
+						"that lack keys required by the blueprint.",
+					Before: s.IsInitialized,
+					Action: s.BlueprintDiff,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "bugreport",
+			Usage: "Create an archive with diagnostics for a bug report",
+			Description: "" +
+				"This command collects the versions of gopass and its backends, the config, " +
+				"the relevant environment variables, the output of the self test and, with " +
+				"--include-log, the end of the debug log (GOPASS_DEBUG_LOG) into a zip archive " +
+				"that can be attached to an issue. Secret config values and the home directory " +
+				"are redacted, the debug log is not. " +
+				"Review the archive before sharing it.",
+			Action: s.Bugreport,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "Write the archive to this file. Defaults to gopass-bugreport-<date>.zip",
+				},
+				&cli.BoolFlag{
+					Name:  "selftest",
+					Usage: "Include the output of gopass selftest. Use --selftest=false to skip it",
+					Value: true,
+				},
+				&cli.BoolFlag{
+					Name:  "include-log",
+					Usage: "Include the end of the debug log. It's not redacted, review it first",
+				},
+			},
+		},
+		{
+			Name:  "bundle",
+			Usage: "Sync stores with bundles",
+			Description: "" +
+				"Air-gapped stores are synced with bundle files on removable media instead " +
+				"of a git remote. Bundles contain the changes since the last imported or " +
+				"acknowledged bundle and can optionally be encrypted for the recipients of the store.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "export",
+					Usage:     "Write the changes of a store to a bundle",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command writes all commits since the last imported or acknowledged " +
+						"bundle to a bundle file.",
+					Before: s.IsInitialized,
+					Action: s.BundleExport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+						&cli.BoolFlag{
+							Name:  "full",
+							Usage: "Export the whole history",
+						},
+						&cli.BoolFlag{
+							Name:  "encrypt",
+							Usage: "Encrypt the bundle for the recipients of the store",
+						},
+						&cli.BoolFlag{
+							Name:  "passphrase",
+							Usage: "Protect the bundle with a passphrase instead, e.g. for offline backups",
+						},
+					},
+				},
+				{
+					Name:      "import",
+					Usage:     "Merge a bundle into a store",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command verifies a bundle and merges it into a store. Encrypted " +
+						"bundles are decrypted first. For passphrase protected bundles the " +
+						"passphrase is asked for.",
+					Before: s.IsInitialized,
+					Action: s.BundleImport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+				{
+					Name:      "ack",
+					Usage:     "Acknowledge that an exported bundle was imported",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command records that a bundle exported from this store was " +
+						"imported on the other side. The next export only contains the commits " +
+						"after it.",
+					Before: s.IsInitialized,
+					Action: s.BundleAck,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+				{
+					Name:      "verify",
+					Usage:     "Check a bundle",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command checks that a bundle is intact and that the store has all " +
+						"commits it builds on.",
+					Before: s.IsInitialized,
+					Action: s.BundleVerify,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
 			},
 		},
 		{
@@ -191,6 +510,10 @@ func (s *Action) GetCommands() []*cli.Command {
 					Name:  "storage",
 					Usage: fmt.Sprintf("Which storage backend? %v", backend.StorageRegistry.BackendNames()),
 				},
+				&cli.StringFlag{
+					Name:  "layout",
+					Usage: "Which layout? pack (many entries per file) or loose (one file per entry)",
+				},
 			},
 		},
 		{
@@ -316,7 +639,9 @@ func (s *Action) GetCommands() []*cli.Command {
 			Description: "" +
 				"This command will first attempt a simple pattern match on the name of the " +
 				"secret.  If there is an exact match it will be shown directly; if there are " +
-				"multiple matches, a selection will be shown.",
+				"multiple matches, a selection will be shown. With --actions a menu offers " +
+				"to copy the password or username, show the entry or its OTP, open its URL, " +
+				"edit or rotate it.",
 			Before:       s.IsInitialized,
 			Action:       s.Find,
 			Aliases:      []string{"search"},
@@ -327,6 +652,16 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"u", "force", "f"},
 					Usage:   "In the case of an exact match, display the password even if safecontent is enabled",
 				},
+				&cli.BoolFlag{
+					Name:    "interactive",
+					Aliases: []string{"i"},
+					Usage:   "Select an entry from the results instead of printing them",
+				},
+				&cli.BoolFlag{
+					Name:    "actions",
+					Aliases: []string{"a"},
+					Usage:   "Offer quick actions (copy, copy-user, show, otp, open, edit, rotate) for the selected entry. See find.default-action",
+				},
 			},
 		},
 		{
@@ -376,6 +711,41 @@ func (s *Action) GetCommands() []*cli.Command {
 			Action:       s.BinaryMove,
 			BashComplete: s.Complete,
 		},
+		{
+			Name:  "gc",
+			Usage: "Permanently remove deleted secrets from the history of a store",
+			Description: "" +
+				"Rewrite the git history of a store to permanently remove all secrets that were " +
+				"deleted more than --days ago and compact the repository. The rewritten history must be " +
+				"force pushed with --push. All other clones must adopt it with 'gopass gc --adopt'.",
+			Before:       s.IsInitialized,
+			Action:       s.GC,
+			BashComplete: s.MountsComplete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "store",
+					Usage: "Select the store to rewrite",
+				},
+				&cli.IntFlag{
+					Name:  "days",
+					Usage: "Only remove secrets deleted more than this many days ago",
+					Value: 30,
+				},
+				&cli.BoolFlag{
+					Name:  "push",
+					Usage: "Force push the rewritten history to the remote",
+				},
+				&cli.BoolFlag{
+					Name:  "adopt",
+					Usage: "Replace the local history with the rewritten history of the remote",
+				},
+				&cli.BoolFlag{
+					Name:    "force",
+					Aliases: []string{"f"},
+					Usage:   "Do not ask for confirmation",
+				},
+			},
+		},
 		{
 			Name:      "generate",
 			Usage:     "Generate a new password",
@@ -405,7 +775,7 @@ func (s *Action) GetCommands() []*cli.Command {
 				&cli.BoolFlag{
 					Name:    "edit",
 					Aliases: []string{"e"},
-					Usage:   "Open secret for editing after generating a password",
+					Usage:   "Ask for additional data (e.g. username, URL or tags) after generating a password",
 				},
 				&cli.BoolFlag{
 					Name:    "symbols",
@@ -415,17 +785,77 @@ func (s *Action) GetCommands() []*cli.Command {
 				&cli.StringFlag{
 					Name:    "generator",
 					Aliases: []string{"g"},
-					Usage:   "Choose a password generator, use one of: cryptic, memorable, xkcd or external. Default: cryptic",
+					Usage:   "Choose a password generator, use one of: cryptic, memorable, xkcd, external or the token formats uuid, hex, base64 or b58. Default: cryptic",
+				},
+				&cli.BoolFlag{
+					Name:  "username",
+					Usage: "Also generate a random but human-looking username, e.g. brave-otter-42, and store it under the username key",
+				},
+				&cli.BoolFlag{
+					Name:  "email-alias",
+					Usage: "Request a new email alias from the configured alias service (see 'gopass alias-service') and store it under the email key",
+				},
+				&cli.IntFlag{
+					Name:  "security-questions",
+					Usage: "Generate pronounceable fake answers to this many security questions and store them as question-N and answer-N. Asks for the questions",
+				},
+				&cli.StringFlag{
+					Name:  "username-style",
+					Usage: "Style of generated usernames: kebab, snake, camel or plain. Default: Value of generate.username-style or kebab",
+				},
+				&cli.StringFlag{
+					Name:  "preset",
+					Usage: "Use the options of this generation preset (see 'gopass presets'). Flags given on the command line take precedence",
 				},
 				&cli.BoolFlag{
 					Name:  "strict",
 					Usage: "Require strict character class rules",
 				},
+				&cli.StringFlag{
+					Name:  "prefix",
+					Usage: "Prepend this prefix to the generated password, e.g. sk_live_ for API keys. It does not count towards the length",
+				},
+				&cli.BoolFlag{
+					Name:  "spell",
+					Usage: "Print the generated password spelled out using the NATO phonetic alphabet. Implies --print",
+				},
+				&cli.BoolFlag{
+					Name:  "no-ambiguous",
+					Usage: "Avoid visually ambiguous characters like O and 0 or l, 1 and I. Default: Value of generate.no-ambiguous",
+				},
+				&cli.StringFlag{
+					Name:  "layout-safe",
+					Usage: "Only use characters that are typed the same on a US keyboard and the given layouts, e.g. de or fr,uk. Useful for console, BIOS or KVM passwords",
+				},
+				&cli.IntFlag{
+					Name:  "keep-old",
+					Usage: "When regenerating the password of an existing entry, keep the previous N passwords under old-password-<timestamp> keys",
+				},
+				&cli.BoolFlag{
+					Name:  "insecure-rng-ok",
+					Usage: "Generate passwords even if the system random number generator looks unsafe",
+				},
+				&cli.BoolFlag{
+					Name:  "check-breached",
+					Usage: "Generate a new password if the generated one is contained in the HIBP dump (audit.hibp-dump-file)",
+				},
+				&cli.BoolFlag{
+					Name:  "backup-old",
+					Usage: "If the key already exists, preserve its current value under a timestamped key (<key>-old-<timestamp>)",
+				},
 				&cli.BoolFlag{
 					Name:    "force-regen",
 					Aliases: []string{"t"},
 					Usage:   "Force full re-generation, incl. evaluation of templates. Will overwrite the entire secret!",
 				},
+				&cli.StringFlag{
+					Name:  "template",
+					Usage: "Use this template (see 'gopass templates') instead of the one matching the secret name",
+				},
+				&cli.BoolFlag{
+					Name:  "ignore-template",
+					Usage: "Do not apply any template, only store the password",
+				},
 				&cli.StringFlag{
 					Name:    "sep",
 					Aliases: []string{"xkcdsep", "xs"},
@@ -435,8 +865,41 @@ func (s *Action) GetCommands() []*cli.Command {
 				&cli.StringFlag{
 					Name:    "lang",
 					Aliases: []string{"xkcdlang", "xl"},
-					Usage:   "Language to generate password from, currently only en (english, default) or de are supported",
-					Value:   "en",
+					Usage:   "Language to generate password from: de, en, es, fr, it, nl or pt. Default: The language of the locale, if supported, or en",
+				},
+				&cli.BoolFlag{
+					Name:    "capitalize",
+					Aliases: []string{"xkcdcapitalize", "xc"},
+					Usage:   "Capitalize the first letter of each word of generated xkcd style passwords",
+				},
+				&cli.BoolFlag{
+					Name:    "ascii",
+					Aliases: []string{"xkcdascii", "xa"},
+					Usage:   "Replace letters with diacritics, e.g. é by e, in generated xkcd style passwords",
+				},
+			},
+		},
+		{
+			Name:      "graph",
+			Usage:     "Show relationships between secrets",
+			ArgsUsage: "[folder]",
+			Description: "" +
+				"This command decrypts all secrets, optionally limited to a folder, and " +
+				"prints a graph of their relationships: duplicated content (e.g. links or " +
+				"copies), shared passwords, references to other entries and entries for " +
+				"domains that are aliases of each other. The output can be rendered with " +
+				"Graphviz, e.g. 'gopass graph | dot -Tsvg > store.svg'.",
+			Before: s.IsInitialized,
+			Action: s.Graph,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Output format: dot or json",
+					Value: "dot",
+				},
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "Include secrets without any relationship",
 				},
 			},
 		},
@@ -446,7 +909,7 @@ func (s *Action) GetCommands() []*cli.Command {
 			ArgsUsage: "[needle]",
 			Description: "" +
 				"This command decrypts all secrets and performs a pattern matching on the " +
-				"content.",
+				"content. With --replace matches can be replaced after confirmation.",
 			Before: s.IsInitialized,
 			Action: s.Grep,
 			Flags: []cli.Flag{
@@ -455,6 +918,10 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"r"},
 					Usage:   "Interpret pattern as RE2 regular expression",
 				},
+				&cli.StringFlag{
+					Name:  "replace",
+					Usage: "Replace all matches with this string. Asks for confirmation for each matching secret unless --yes is given. Use $1 to refer to regexp capture groups",
+				},
 			},
 		},
 		{
@@ -476,23 +943,97 @@ func (s *Action) GetCommands() []*cli.Command {
 			},
 		},
 		{
-			Name:      "init",
-			Usage:     "Initialize new password store.",
-			ArgsUsage: "[gpg-id]",
+			Name:  "import",
+			Usage: "Import secrets from other password managers",
 			Description: "" +
-				"Initialize new password storage and use gpg-id for encryption.",
-			Action: s.Init,
-			Flags: []cli.Flag{
-				&cli.StringFlag{
-					Name:    "path",
-					Aliases: []string{"p"},
-					Usage:   "Set the sub-store path to operate on",
-				},
-				&cli.StringFlag{
-					Name:    "store",
-					Aliases: []string{"s"},
-					Usage:   "Set the name of the sub-store",
-				},
+				"These commands import secrets from other password managers into the store.",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "keychain",
+					Usage: "Import web credentials from the OS password manager",
+					Description: "" +
+						"This command reads the web credentials stored in the macOS Keychain, " +
+						"the Secret Service (libsecret) on Linux or the Windows Credential Manager " +
+						"and adds them to the store as <prefix>/<server>/<user>. gopass asks " +
+						"before reading any credentials. Existing entries are skipped unless " +
+						"--force is given.",
+					Before: s.IsInitialized,
+					Action: s.ImportKeychain,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "prefix",
+							Usage: "Folder for the imported entries",
+							Value: "keychain",
+						},
+						&cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "Only list the credentials that would be imported",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Replace existing entries",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "info",
+			Usage:     "Display folder notes",
+			ArgsUsage: "[folder]",
+			Description: "" +
+				"Display the encrypted folder note (e.g. owner team, on-call contact or " +
+				"rotation policy) of the given folder. If the folder has no note of its own " +
+				"the note of the nearest parent folder is shown.",
+			Before:       s.IsInitialized,
+			Action:       s.InfoPrint,
+			BashComplete: s.Complete,
+			Subcommands: []*cli.Command{
+				{
+					Name:         "edit",
+					Usage:        "Edit folder notes",
+					Description:  "Edit an existing or new folder note",
+					Aliases:      []string{"create", "new"},
+					Before:       s.IsInitialized,
+					Action:       s.InfoEdit,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "editor",
+							Aliases: []string{"e"},
+							Usage:   "Use this editor binary",
+						},
+					},
+				},
+				{
+					Name:         "remove",
+					Aliases:      []string{"rm"},
+					Usage:        "Remove folder notes",
+					Description:  "Remove an existing folder note",
+					Before:       s.IsInitialized,
+					Action:       s.InfoRemove,
+					BashComplete: s.Complete,
+				},
+			},
+		},
+		{
+			Name:      "init",
+			Usage:     "Initialize new password store.",
+			ArgsUsage: "[gpg-id]",
+			Description: "" +
+				"Initialize new password storage and use gpg-id for encryption.",
+			Action: s.Init,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "path",
+					Aliases: []string{"p"},
+					Usage:   "Set the sub-store path to operate on",
+				},
+				&cli.StringFlag{
+					Name:    "store",
+					Aliases: []string{"s"},
+					Usage:   "Set the name of the sub-store",
+				},
 				&cli.StringFlag{
 					Name:  "crypto",
 					Usage: fmt.Sprintf("Select crypto backend %v", backend.CryptoRegistry.BackendNames()),
@@ -503,6 +1044,14 @@ func (s *Action) GetCommands() []*cli.Command {
 					Usage: fmt.Sprintf("Select storage backend %v", backend.StorageRegistry.BackendNames()),
 					Value: "gitfs",
 				},
+				&cli.BoolFlag{
+					Name:  "air-gapped",
+					Usage: "Sync this store with bundles on removable media only",
+				},
+				&cli.StringFlag{
+					Name:  "from-blueprint",
+					Usage: "Create folders, templates and mounts from this blueprint file",
+				},
 			},
 		},
 		{
@@ -535,7 +1084,74 @@ func (s *Action) GetCommands() []*cli.Command {
 				&cli.BoolFlag{
 					Name:    "append",
 					Aliases: []string{"a"},
-					Usage:   "Append data read from STDIN to existing data",
+					Usage:   "Append data read from STDIN to existing data. With a key, add another value to the key instead of replacing it",
+				},
+			},
+		},
+		{
+			Name:  "journal",
+			Usage: "Review the journal of mutating commands",
+			Description: "" +
+				"Every command that changes secrets, e.g. insert, generate, edit, move, copy, " +
+				"delete or link, is recorded in a local journal together with the entries it " +
+				"touched and the resulting commits. The journal spans all mounts, is never " +
+				"synced and is kept separate from the git history. " +
+				"Set core.journal to false to disable it.",
+			Before: s.IsInitialized,
+			Action: s.JournalShow,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "since",
+					Usage: "Only show commands since this duration (e.g. 24h) or date (YYYY-MM-DD)",
+				},
+				&cli.StringFlag{
+					Name:  "store",
+					Usage: "Only show changes below this mount or folder",
+				},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  "show",
+					Usage: "Show the journal",
+					Description: "" +
+						"Show the recorded commands, oldest first, with the entries they touched " +
+						"and the commit each entry ended up in.",
+					Before: s.IsInitialized,
+					Action: s.JournalShow,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "since",
+							Usage: "Only show commands since this duration (e.g. 24h) or date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Only show changes below this mount or folder",
+						},
+					},
+				},
+				{
+					Name:  "export",
+					Usage: "Export the journal",
+					Description: "" +
+						"Write the recorded commands to stdout as JSON or CSV, e.g. for " +
+						"an audit or to attach to a change request.",
+					Before: s.IsInitialized,
+					Action: s.JournalExport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "Output format: json or csv",
+							Value: "json",
+						},
+						&cli.StringFlag{
+							Name:  "since",
+							Usage: "Only export commands since this duration (e.g. 24h) or date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Only export changes below this mount or folder",
+						},
+					},
 				},
 			},
 		},
@@ -584,6 +1200,57 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"s"},
 					Usage:   "Strip this prefix from filtered entries",
 				},
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Usage: "Only list entries with this tag. Can be given multiple times. Note: This needs to decrypt all entries",
+				},
+				&cli.StringFlag{
+					Name:  "icons",
+					Usage: "Show the icons of entries and folders: emoji, ascii or none. Overrides list.icons. Note: This needs to decrypt all entries that are not cached",
+				},
+			},
+		},
+		{
+			Name:      "local",
+			Usage:     "Keep entries on this device only",
+			ArgsUsage: "[entry|folder/]",
+			Description: "" +
+				"This command marks an entry or a folder as local-only. Local-only entries " +
+				"are encrypted like any other entry, but kept in a directory outside of the " +
+				"repository of the store, so they are never committed or pushed. " +
+				"Use it for machine specific credentials that must not reach the shared remote. " +
+				"Entries already in the repository are removed from it, but not from its history. " +
+				"Without an entry the local-only entries and folders of all stores are listed.",
+			Before:       s.IsInitialized,
+			Action:       s.Local,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "unset",
+					Usage: "Add the entry or folder to the repository again",
+				},
+			},
+		},
+		{
+			Name:  "lock",
+			Usage: "Wipe cached passphrases and keys",
+			Description: "" +
+				"This command makes the crypto backends of all mounted stores forget the " +
+				"cached passphrases and keys, e.g. gpg-agent or the OS keyring used by age. " +
+				"The next command that decrypts a secret asks for the passphrase again. " +
+				"With core.autolock set gopass does this automatically after the given " +
+				"minutes of inactivity. Use --watch to lock whenever the screen locks.",
+			Before: s.IsInitialized,
+			Action: s.Lock,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "status",
+					Usage: "Show the lock state and the auto-lock timeout",
+				},
+				&cli.BoolFlag{
+					Name:  "watch",
+					Usage: "Keep running and lock when the screen locks or gopass is idle for too long",
+				},
 			},
 		},
 		{
@@ -634,6 +1301,46 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "mobile",
+			Usage: "Set up a pass compatible mobile app",
+			Description: "" +
+				"This command provisions everything a pass compatible mobile app, e.g. " +
+				"Android Password Store, needs to sync a store: a dedicated gpg or age key " +
+				"for the device that is only a recipient of --folder, a read-only SSH " +
+				"deploy key for the git remote and QR codes to import both on the device. " +
+				"The deploy key is added through the API of Gitea if --gitea-token-entry " +
+				"is given, otherwise you have to add it manually.",
+			Before: s.IsInitialized,
+			Action: s.Mobile,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "Name of the device",
+					Value: "phone",
+				},
+				&cli.StringFlag{
+					Name:  "folder",
+					Usage: "Only share this folder with the device",
+				},
+				&cli.StringFlag{
+					Name:  "store",
+					Usage: "Store to share with the device if no folder is given",
+				},
+				&cli.StringFlag{
+					Name:  "gitea-token-entry",
+					Usage: "Entry with a Gitea API token to add the deploy key",
+				},
+				&cli.StringFlag{
+					Name:  "gitea-url",
+					Usage: "Address of the Gitea instance if it differs from the host of the remote",
+				},
+				&cli.StringFlag{
+					Name:  "output-dir",
+					Usage: "Directory for the temporary key files of the device",
+				},
+			},
+		},
 		{
 			Name:  "mounts",
 			Usage: "Edit mounted stores",
@@ -683,17 +1390,41 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:      "open",
+			Usage:     "Open the URL of an entry in the browser",
+			ArgsUsage: "<entry>",
+			Description: "" +
+				"This command opens the 'url' of the entry with the platform browser or browser.command. " +
+				"With --copy it copies the username to the clipboard first and the password after a delay.",
+			Before:       s.IsInitialized,
+			Action:       s.Open,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:    "copy",
+					Aliases: []string{"c"},
+					Usage:   "Copy the username and then the password to the clipboard",
+				},
+				&cli.IntFlag{
+					Name:  "delay",
+					Usage: "Seconds to wait before copying the password after the username",
+					Value: 10,
+				},
+			},
+		},
 		{
 			Name:      "otp",
 			Usage:     "Generate time- or hmac-based tokens",
-			ArgsUsage: "[secret]",
+			ArgsUsage: "[secret] [key]",
 			Aliases:   []string{"totp", "hotp"},
 			Description: "" +
 				"Tries to parse an OTP URL (otpauth://). URL can be TOTP or HOTP. " +
-				"The URL can be provided on its own line or on a key value line with a key named 'totp'.",
+				"The URL can be provided on its own line or on a key value line with a key named 'totp'. " +
+				"If a key is given, the OTP URL or the TOTP secret is read from this key.",
 			Before:       s.IsInitialized,
 			Action:       s.OTP,
-			BashComplete: s.Complete,
+			BashComplete: s.CompleteKeys,
 			Flags: []cli.Flag{
 				&cli.BoolFlag{
 					Name:    "clip",
@@ -716,6 +1447,159 @@ func (s *Action) GetCommands() []*cli.Command {
 					Usage:   "Scan screen content to insert a OTP QR code into provided entry",
 				},
 			},
+			Subcommands: []*cli.Command{
+				{
+					Name:      "export",
+					Usage:     "Export OTP seeds for an authenticator app",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command writes the OTP seeds of all entries to the backup format of " +
+						"an authenticator app. andOTP and Aegis backups are encrypted with a password.",
+					Before: s.IsInitialized,
+					Action: s.OTPExport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "Backup format: andotp, aegis or freeotp",
+						},
+						&cli.StringFlag{
+							Name:  "folder",
+							Usage: "Only export the entries in this folder",
+						},
+					},
+				},
+				{
+					Name:      "import",
+					Usage:     "Import OTP seeds from an authenticator app",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command adds the OTP seeds from the backup of an authenticator app " +
+						"to the store, as <folder>/<issuer>/<account>. Entries that already have " +
+						"a different seed are skipped unless --force is given.",
+					Before: s.IsInitialized,
+					Action: s.OTPImport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "Backup format: andotp, aegis or freeotp",
+						},
+						&cli.StringFlag{
+							Name:  "folder",
+							Usage: "Folder for the imported entries",
+							Value: "otp",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Replace existing OTP seeds",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "pin",
+			Usage:     "Protect entries from changes",
+			ArgsUsage: "[entry]",
+			Description: "" +
+				"This command pins an entry. Pinned entries can't be edited, " +
+				"regenerated, moved or removed until they are unpinned with --unpin. " +
+				"The pins are stored inside of the password store, so they apply to " +
+				"every device and team member. " +
+				"Without an entry the pinned entries of all stores are listed.",
+			Before:       s.IsInitialized,
+			Action:       s.Pin,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "unpin",
+					Usage: "Unpin the entry",
+				},
+			},
+		},
+		{
+			Name:  "policy",
+			Usage: "Manage password policy packs",
+			Description: "" +
+				"Policy packs are JSON bundles of password rules, e.g. a minimum length, " +
+				"required character classes or denied patterns. They are stored and " +
+				"versioned inside of the password store. audit reports any violations and " +
+				"generate creates compliant passwords. " +
+				"Without a subcommand the policy packs of all stores are listed.",
+			Before: s.IsInitialized,
+			Action: s.PolicyList,
+			Subcommands: []*cli.Command{
+				{
+					Name:      "import",
+					Usage:     "Add or update a policy pack",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command adds a policy pack to a store or replaces an older " +
+						"version of it. Use - to read the pack from stdin.",
+					Before: s.IsInitialized,
+					Action: s.PolicyImport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Replace a newer version of the pack",
+						},
+					},
+				},
+				{
+					Name:      "remove",
+					Aliases:   []string{"rm"},
+					Usage:     "Remove a policy pack",
+					ArgsUsage: "[name]",
+					Description: "" +
+						"This command removes a policy pack from a store.",
+					Before: s.IsInitialized,
+					Action: s.PolicyRemove,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "presets",
+			Usage: "Manage password generation presets",
+			Description: "" +
+				"Presets are named bundles of generate options, e.g. the length, symbols, " +
+				"generator, charset or strict. They are stored in the config as " +
+				"preset.<name>.<option> and used with 'gopass generate --preset <name>'. " +
+				"Without a subcommand all presets are listed.",
+			Action: s.PresetsList,
+			Subcommands: []*cli.Command{
+				{
+					Name:  "list",
+					Usage: "List all presets",
+					Description: "" +
+						"This command lists all presets and their options.",
+					Action: s.PresetsList,
+				},
+				{
+					Name:      "edit",
+					Usage:     "Create or change a preset",
+					ArgsUsage: "[name]",
+					Description: "" +
+						"This command opens the options of the preset in the editor. " +
+						"Removing all options removes the preset.",
+					Action: s.PresetsEdit,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "editor",
+							Aliases: []string{"e"},
+							Usage:   "Use this editor binary",
+						},
+					},
+				},
+			},
 		},
 		{
 			Name:  "process",
@@ -726,6 +1610,104 @@ func (s *Action) GetCommands() []*cli.Command {
 			Before: s.IsInitialized,
 			Action: s.Process,
 		},
+		{
+			Name:  "profile",
+			Usage: "Share the client configuration of a team",
+			Description: "" +
+				"A profile is a signed file describing the stores with their remotes, the " +
+				"templates, folder schemas and policy settings of a team. It never contains " +
+				"secrets. Importing it gives every member of the team the same configuration.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "export",
+					Usage:     "Write the configuration to a signed profile",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command writes the mounts, their remotes and backends, all templates " +
+						"and the shareable settings to a profile and signs it with the key in the file " +
+						"profile.signing-key, which is kept outside of the stores. A new key is created " +
+						"if there is none. Use - to write the profile to stdout.",
+					Before: s.IsInitialized,
+					Action: s.ProfileExport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "Name of the profile",
+							Value: "team",
+						},
+						&cli.StringFlag{
+							Name:  "description",
+							Usage: "Description of the profile",
+						},
+						&cli.StringFlag{
+							Name:  "blueprint",
+							Usage: "Include the folder schemas of this blueprint",
+						},
+						&cli.StringFlag{
+							Name:  "signing-key",
+							Usage: "File holding the signing key",
+						},
+					},
+				},
+				{
+					Name:      "import",
+					Usage:     "Apply a signed profile",
+					ArgsUsage: "[file]",
+					Description: "" +
+						"This command verifies the signature of the profile, clones the stores " +
+						"that are missing, writes the templates and sets the shared settings. " +
+						"Entries that don't match the folder schemas are reported. Profiles must " +
+						"be signed by a key listed in profile.trusted-keys, given with --key or " +
+						"confirmed interactively.",
+					Action: s.ProfileImport,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "key",
+							Usage: "Trust this signing key",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "protect",
+			Usage:     "Require a FIDO2 token tap to reveal an entry",
+			ArgsUsage: "[secret]",
+			Description: "" +
+				"This command wraps the entry with a key derived from the hmac-secret " +
+				"extension of a FIDO2 token. Revealing the entry then always requires a " +
+				"tap on the token, even if the passphrase of the crypto backend is cached " +
+				"by an agent. A credential is created on the token on first use. " +
+				"Requires the fido2-tools of libfido2.",
+			Before:       s.IsInitialized,
+			Action:       s.Protect,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "remove",
+					Usage: "Remove the protection",
+				},
+			},
+		},
+		{
+			Name:  "pwrules",
+			Usage: "Inspect the password rules database",
+			Description: "" +
+				"gopass knows the password rules of many sites and uses them when " +
+				"generating passwords for entries named after a site.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "show",
+					Usage:     "Show the password rule of a domain",
+					ArgsUsage: "[domain]",
+					Description: "" +
+						"This command prints the password rule, the aliases and the URL to " +
+						"change the password for a domain, including custom aliases from the " +
+						"config. Use it to find out why generate produced a certain shape of password.",
+					Action: s.PwRulesShow,
+				},
+			},
+		},
 		{
 			Name:      "rcs",
 			Usage:     "Run a RCS command inside a password store",
@@ -797,11 +1779,13 @@ func (s *Action) GetCommands() []*cli.Command {
 				{
 					Name:    "ack",
 					Aliases: []string{"acknowledge"},
-					Usage:   "Update recipients.hash",
+					Usage:   "Update recipients.hash and confirm access",
 					Description: "" +
 						"This command updates the value of recipients.hash. " +
 						"This should only be run after manually validating any " +
-						"changes to the recipients list. ",
+						"changes to the recipients list. " +
+						"It also decrypts the challenge of each store and commits an acknowledgement " +
+						"that you can still decrypt it. See 'gopass recipients status'.",
 					Before: s.IsInitialized,
 					Action: s.RecipientsAck,
 					Flags: []cli.Flag{
@@ -834,6 +1818,19 @@ func (s *Action) GetCommands() []*cli.Command {
 						},
 					},
 				},
+				{
+					Name:      "redact",
+					Usage:     "Show or set how a folder is listed for non-recipients",
+					ArgsUsage: "<folder> [names|count|none]",
+					Description: "" +
+						"Folders can be marked as visible but redacted for users who can't decrypt them. " +
+						"These users still see that the entries exist when running 'gopass list', " +
+						"either their names (names) or only their number (count), so they can ask for " +
+						"access instead of creating duplicates. The mode is stored in a plaintext " +
+						".gopass-redact file in the folder. Use none to remove the redaction.",
+					Before: s.IsInitialized,
+					Action: s.RecipientsRedact,
+				},
 				{
 					Name:    "remove",
 					Aliases: []string{"rm", "deauthorize"},
@@ -861,6 +1858,218 @@ func (s *Action) GetCommands() []*cli.Command {
 						},
 					},
 				},
+				{
+					Name:  "status",
+					Usage: "Show who confirmed access",
+					Description: "" +
+						"This command shows which recipients confirmed with 'gopass recipients ack' " +
+						"that they can decrypt the store since the recipients were changed last. " +
+						"Recipients that didn't might be locked out silently.",
+					Before: s.IsInitialized,
+					Action: s.RecipientsStatus,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "restructure",
+			Usage:     "Move entries for websites into another layout",
+			ArgsUsage: "[folder]",
+			Description: "" +
+				"This command proposes new names for all entries for websites, optionally " +
+				"limited to a folder, in the given layout: flat (<folder>/<user>@<domain>), " +
+				"folder-per-site (<folder>/<domain>/<user>) or domain-user (<domain>/<user>). " +
+				"The plan is opened in the editor before all entries are renamed. " +
+				"The last run can be reverted with --undo.",
+			Before: s.IsInitialized,
+			Action: s.Restructure,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "Target layout: flat, folder-per-site or domain-user",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only print the plan",
+				},
+				&cli.BoolFlag{
+					Name:  "undo",
+					Usage: "Revert the last restructure",
+				},
+			},
+		},
+		{
+			Name:  "review",
+			Usage: "Review changes to owned folders",
+			Description: "" +
+				"Folders can be owned by a set of recipients listed in an OWNERS file. " +
+				"Changes to these folders by anyone else are recorded as pending changes " +
+				"that need to be approved by one of the owners. " +
+				"Pushes to protected stores are staged until they are approved on another device or by someone else. " +
+				"Without a subcommand all pending changes and staged pushes are listed.",
+			Before: s.IsInitialized,
+			Action: s.ReviewList,
+			Subcommands: []*cli.Command{
+				{
+					Name:      "approve",
+					Usage:     "Apply a pending change",
+					ArgsUsage: "[id]",
+					Description: "" +
+						"This command applies a pending change. Only owners of the folder can approve it. " +
+						"Staged pushes to protected stores are merged once they have enough approvals.",
+					Before: s.IsInitialized,
+					Action: s.ReviewApprove,
+				},
+				{
+					Name:      "reject",
+					Usage:     "Discard a pending change",
+					ArgsUsage: "[id]",
+					Description: "" +
+						"This command discards a pending change. Owners and the author of the change can reject it.",
+					Before: s.IsInitialized,
+					Action: s.ReviewReject,
+				},
+				{
+					Name:      "show",
+					Usage:     "Display a pending change",
+					ArgsUsage: "[id]",
+					Description: "" +
+						"This command displays a pending change including the proposed content.",
+					Before: s.IsInitialized,
+					Action: s.ReviewShow,
+				},
+			},
+		},
+		{
+			Name:      "rotate",
+			Usage:     "Rotate a password in two phases",
+			ArgsUsage: "[secret [length]]",
+			Description: "" +
+				"Two-phase password rotation to prevent lockouts. '--staged' generates a new password " +
+				"and stores it in the 'pending-password' key of the entry while the current password stays in place. " +
+				"Once the new password was applied upstream '--commit' promotes it to the password. " +
+				"'--abort' discards the pending password. With '--tag' all entries with the given tags are rotated.",
+			Before:       s.IsInitialized,
+			Action:       s.Rotate,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Usage: "Rotate all entries with this tag. Can be given multiple times. Note: This needs to decrypt all entries",
+				},
+				&cli.BoolFlag{
+					Name:  "staged",
+					Usage: "Generate a new password and store it as pending password",
+				},
+				&cli.BoolFlag{
+					Name:  "commit",
+					Usage: "Promote the pending password to the password",
+				},
+				&cli.BoolFlag{
+					Name:  "abort",
+					Usage: "Discard the pending password",
+				},
+				&cli.IntFlag{
+					Name:  "keep-old",
+					Usage: "When committing, keep the previous N passwords under old-password-<timestamp> keys",
+				},
+				&cli.BoolFlag{
+					Name:    "clip",
+					Aliases: []string{"c"},
+					Usage:   "Copy the staged password to the clipboard",
+				},
+				&cli.BoolFlag{
+					Name:    "print",
+					Aliases: []string{"p"},
+					Usage:   "Print the staged password to the terminal",
+				},
+				&cli.BoolFlag{
+					Name:    "symbols",
+					Aliases: []string{"s"},
+					Usage:   "Use symbols in the password",
+				},
+				&cli.StringFlag{
+					Name:    "generator",
+					Aliases: []string{"g"},
+					Usage:   "Choose a password generator, use one of: cryptic, memorable, xkcd, external or the token formats uuid, hex, base64 or b58. Default: cryptic",
+				},
+				&cli.BoolFlag{
+					Name:  "insecure-rng-ok",
+					Usage: "Generate passwords even if the system random number generator looks unsafe",
+				},
+				&cli.BoolFlag{
+					Name:  "check-breached",
+					Usage: "Generate a new password if the generated one is contained in the HIBP dump (audit.hibp-dump-file)",
+				},
+				&cli.BoolFlag{
+					Name:  "strict",
+					Usage: "Require strict character class rules",
+				},
+				&cli.StringFlag{
+					Name:  "prefix",
+					Usage: "Prepend this prefix to the generated password, e.g. sk_live_ for API keys. It does not count towards the length",
+				},
+				&cli.BoolFlag{
+					Name:  "no-ambiguous",
+					Usage: "Avoid visually ambiguous characters like O and 0 or l, 1 and I. Default: Value of generate.no-ambiguous",
+				},
+				&cli.StringFlag{
+					Name:  "layout-safe",
+					Usage: "Only use characters that are typed the same on a US keyboard and the given layouts, e.g. de or fr,uk. Useful for console, BIOS or KVM passwords",
+				},
+				&cli.StringFlag{
+					Name:    "sep",
+					Aliases: []string{"xkcdsep", "xs"},
+					Usage:   "Word separator for generated passwords. If no separator is specified, the words are combined without spaces/separator and the first character of words is capitalised.",
+				},
+				&cli.StringFlag{
+					Name:    "lang",
+					Aliases: []string{"xkcdlang", "xl"},
+					Usage:   "Language to generate password from: de, en, es, fr, it, nl or pt. Default: The language of the locale, if supported, or en",
+				},
+				&cli.BoolFlag{
+					Name:    "capitalize",
+					Aliases: []string{"xkcdcapitalize", "xc"},
+					Usage:   "Capitalize the first letter of each word of generated xkcd style passwords",
+				},
+				&cli.BoolFlag{
+					Name:    "ascii",
+					Aliases: []string{"xkcdascii", "xa"},
+					Usage:   "Replace letters with diacritics, e.g. é by e, in generated xkcd style passwords",
+				},
+			},
+		},
+		{
+			Name:  "selftest",
+			Usage: "Test the configured backends end to end",
+			Description: "" +
+				"This command creates a throwaway store with the crypto and storage backends " +
+				"of the root store and generates, inserts, shows, moves, re-encrypts and syncs " +
+				"secrets in it. Failed steps are reported with diagnostics. Your stores are " +
+				"not touched.",
+			Action: s.Selftest,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "crypto",
+					Usage: fmt.Sprintf("Select crypto backend %v", backend.CryptoRegistry.BackendNames()),
+				},
+				&cli.StringFlag{
+					Name:  "storage",
+					Usage: fmt.Sprintf("Select storage backend %v", backend.StorageRegistry.BackendNames()),
+				},
+				&cli.StringFlag{
+					Name:  "recipient",
+					Usage: "Encrypt for this key. Default: the first private key",
+				},
+				&cli.BoolFlag{
+					Name:  "keep",
+					Usage: "Keep the test store for inspection",
+				},
 			},
 		},
 		{
@@ -902,16 +2111,36 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "shell",
+			Usage: "Start the built-in shell",
+			Description: "" +
+				"This command starts an interactive shell with completion of commands and " +
+				"entries, like running gopass without arguments. With --restricted, or if " +
+				"shell.restricted is set, only the read-only commands in " +
+				"shell.restricted-commands are allowed and only on the entries below the " +
+				"folders in shell.restricted-paths. Use it as the login shell of operators " +
+				"on shared hosts that should only see a narrow slice of the store.",
+			Before: s.IsInitialized,
+			Action: s.REPL,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "restricted",
+					Usage: "Only allow the configured read-only commands and folders",
+				},
+			},
+		},
 		{
 			Name:      "show",
 			Usage:     "Display the content of a secret",
 			ArgsUsage: "[secret]",
 			Description: "" +
 				"Show an existing secret and optionally put its first line on the clipboard. " +
-				"If put on the clipboard, it will be cleared after 45 seconds.",
+				"If put on the clipboard, it will be cleared after 45 seconds. " +
+				"Use --json to print several secrets at once.",
 			Before:       s.IsInitialized,
 			Action:       s.Show,
-			BashComplete: s.Complete,
+			BashComplete: s.CompleteKeys,
 			Flags:        ShowFlags(),
 		},
 		{
@@ -921,18 +2150,59 @@ func (s *Action) GetCommands() []*cli.Command {
 			Description: "" +
 				"This command decodes an Base64 encoded secret and computes the SHA256 checksum " +
 				"over the decoded data. This is useful to verify the integrity of an " +
-				"inserted secret.",
+				"inserted secret. " +
+				"With --consumer it prints a HMAC-SHA256 fingerprint of each given entry " +
+				"instead, so external systems can detect changed credentials without ever " +
+				"seeing them. Every consumer gets its own HMAC key, stored below " +
+				"sum.key-prefix.",
 			Aliases:      []string{"sha", "sha256"},
 			Before:       s.IsInitialized,
 			Action:       s.Sum,
 			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "consumer",
+					Usage: "Print HMAC fingerprints with the key of this consumer",
+				},
+				&cli.StringFlag{
+					Name:  "field",
+					Usage: "Only fingerprint the password or the value of this key",
+				},
+			},
+		},
+		{
+			Name:      "summarize",
+			Usage:     "Print a redacted inventory of a folder",
+			ArgsUsage: "[folder]",
+			Description: "" +
+				"This command lists the entries of a folder with their usernames, URLs, " +
+				"owners, tags and when their password was last rotated, but without any " +
+				"secret values. Use it for team handovers and audits.",
+			Before:       s.IsInitialized,
+			Action:       s.Summarize,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Output format. markdown or csv. Default: markdown",
+					Value: "markdown",
+				},
+				&cli.StringFlag{
+					Name:    "output-file",
+					Aliases: []string{"o"},
+					Usage:   "Output filename. Default: stdout",
+				},
+			},
 		},
 		{
 			Name:  "sync",
 			Usage: "Sync all local stores with their remotes",
 			Description: "" +
 				"Sync all local stores with their git remotes, if any, and check " +
-				"any possibly affected gpg keys.",
+				"any possibly affected gpg keys. Afterwards a summary of the added, " +
+				"changed and removed entries of each store is printed. " +
+				"On slow links the transfer can be limited with --max-bandwidth. Before " +
+				"syncing a throttled store gopass shows an estimate of the transfer size.",
 			Before: s.IsInitialized,
 			Action: s.Sync,
 			Flags: []cli.Flag{
@@ -941,6 +2211,62 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"s"},
 					Usage:   "Select the store to sync",
 				},
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Output format of the summary. text or json. Default: text",
+					Value: "text",
+				},
+				&cli.StringFlag{
+					Name:  "max-bandwidth",
+					Usage: "Limit the transfer to this many bytes per second, e.g. 64k. Overrides network.max-bandwidth",
+				},
+				&cli.BoolFlag{
+					Name:  "estimate",
+					Usage: "Only show the estimated transfer size, don't sync",
+				},
+			},
+		},
+		{
+			Name:      "tag",
+			Usage:     "Manage entry tags",
+			ArgsUsage: "[entry]",
+			Description: "" +
+				"Tags are stored in the 'tags' key of an entry, separated by commas. " +
+				"They can be used to select entries for bulk operations, e.g. 'gopass audit --tag prod' " +
+				"or 'gopass list --tag prod'.",
+			Before:       s.IsInitialized,
+			Action:       s.TagList,
+			BashComplete: s.Complete,
+			Subcommands: []*cli.Command{
+				{
+					Name:         "add",
+					Usage:        "Add tags to an entry",
+					ArgsUsage:    "[entry] [tag]...",
+					Description:  "Add one or more tags to an entry",
+					Before:       s.IsInitialized,
+					Action:       s.TagAdd,
+					BashComplete: s.Complete,
+				},
+				{
+					Name:         "list",
+					Aliases:      []string{"ls"},
+					Usage:        "List the tags of an entry",
+					ArgsUsage:    "[entry]",
+					Description:  "List the tags of an entry",
+					Before:       s.IsInitialized,
+					Action:       s.TagList,
+					BashComplete: s.Complete,
+				},
+				{
+					Name:         "remove",
+					Aliases:      []string{"rm"},
+					Usage:        "Remove tags from an entry",
+					ArgsUsage:    "[entry] [tag]...",
+					Description:  "Remove one or more tags from an entry",
+					Before:       s.IsInitialized,
+					Action:       s.TagRemove,
+					BashComplete: s.Complete,
+				},
 			},
 		},
 		{
@@ -996,16 +2322,48 @@ func (s *Action) GetCommands() []*cli.Command {
 					Name:  "force",
 					Usage: "Clear clipboard even if checksum mismatches",
 				},
+				&cli.BoolFlag{
+					Name:   "serve",
+					Usage:  "Serve the Wayland clipboard read from stdin until the timeout expires",
+					Hidden: true,
+				},
 			},
 		},
+		{
+			Name:  "unlock",
+			Usage: "Ask for the passphrases of all stores now",
+			Description: "" +
+				"This command decrypts one secret of every mounted store, so the crypto " +
+				"backends cache the passphrases and later commands don't have to ask.",
+			Before: s.IsInitialized,
+			Action: s.Unlock,
+		},
 		{
 			Name:  "update",
 			Usage: "Check for updates",
 			Description: "" +
 				"This command checks for gopass updates at GitHub and automatically " +
-				"downloads and installs any missing update.",
+				"downloads and installs any missing update. The release is verified " +
+				"before installing it. Set updater.notifyonly to only report new releases.",
 			Action: s.Update,
 		},
+		{
+			Name:  "updatedb",
+			Usage: "Update the password rules",
+			Description: "" +
+				"This command fetches the latest password rules, change password URLs " +
+				"and domain aliases from a signed feed (updatedb.url) into a local " +
+				"database that takes precedence over the ones built into gopass. The " +
+				"feed is checked at most once an hour. Set updatedb.auto to refresh it " +
+				"in the background when generating passwords.",
+			Action: s.UpdateDB,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Check the feed even if it was checked less than an hour ago",
+				},
+			},
+		},
 		{
 			Name:  "version",
 			Usage: "Display version",
@@ -9,7 +9,6 @@ import (
 	fishcomp "github.com/gopasspw/gopass/internal/completion/fish"
 	zshcomp "github.com/gopasspw/gopass/internal/completion/zsh"
 	"github.com/gopasspw/gopass/internal/out"
-	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/urfave/cli/v2"
 )
@@ -44,7 +43,7 @@ func (s *Action) Complete(c *cli.Context) {
 
 		return
 	}
-	list, err := s.Store.List(ctx, tree.INF)
+	list, err := s.Store.CachedList(ctx)
 	if err != nil {
 		return
 	}
@@ -54,6 +53,20 @@ func (s *Action) Complete(c *cli.Context) {
 	}
 }
 
+// CompleteKeys completes entry names and, once an entry was given, the names
+// of its keys. The key names are taken from the key index, so this doesn't
+// decrypt anything.
+func (s *Action) CompleteKeys(c *cli.Context) {
+	switch c.Args().Len() {
+	case 0:
+		s.Complete(c)
+	case 1:
+		for _, k := range s.Store.CachedKeys(c.Args().First()) {
+			fmt.Fprintln(stdout, bashEscape(k))
+		}
+	}
+}
+
 // CompletionOpenBSDKsh returns an OpenBSD ksh script used for auto completion.
 func (s *Action) CompletionOpenBSDKsh(a *cli.App) error {
 	out := `
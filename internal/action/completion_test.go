@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -70,6 +72,29 @@ func TestComplete(t *testing.T) {
 		assert.Equal(t, "foo\n", buf.String())
 	})
 
+	t.Run("complete keys", func(t *testing.T) {
+		defer buf.Reset()
+
+		sec := secrets.New()
+		sec.SetPassword("secret")
+		require.NoError(t, sec.Set("username", "admin"))
+		require.NoError(t, act.Store.Set(root.WithKeyIndex(ctx, true), "bar", sec))
+		buf.Reset()
+
+		act.CompleteKeys(gptest.CliCtx(ctx, t))
+		assert.Equal(t, "bar\nfoo\n", buf.String())
+		buf.Reset()
+
+		act.CompleteKeys(gptest.CliCtx(ctx, t, "bar"))
+		assert.Equal(t, "username\n", buf.String())
+		buf.Reset()
+
+		act.CompleteKeys(gptest.CliCtx(ctx, t, "bar", "username"))
+		assert.Equal(t, "", buf.String())
+
+		require.NoError(t, act.Store.Delete(ctx, "bar"))
+	})
+
 	t.Run("bash completion", func(t *testing.T) {
 		defer buf.Reset()
 
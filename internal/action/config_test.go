@@ -42,6 +42,7 @@ core.autoimport = true
 core.autopush = true
 core.autosync = true
 core.cliptimeout = 45
+core.completionindex = true
 core.exportkeys = true
 core.nopager = true
 core.notifications = true
@@ -83,6 +84,7 @@ core.autoimport = true
 core.autopush = true
 core.autosync = true
 core.cliptimeout = 45
+core.completionindex = true
 core.exportkeys = true
 core.nopager = true
 core.notifications = true
@@ -116,6 +118,7 @@ core.autoimport
 core.autopush
 core.autosync
 core.cliptimeout
+core.completionindex
 core.exportkeys
 core.nopager
 core.notifications
@@ -13,6 +13,13 @@ const (
 	ctxKeyOnlyClip
 	ctxKeyAlsoClip
 	ctxKeyPrintChars
+	ctxKeyEstimateOnly
+	ctxKeyRender
+	ctxKeyRenderAs
+	ctxKeySpell
+	ctxKeyStrict
+	ctxKeyPreset
+	ctxKeyRestrictedShell
 )
 
 // WithClip returns a context with the value for clip (for copy to clipboard)
@@ -115,6 +122,75 @@ func GetRevision(ctx context.Context) string {
 	return sv
 }
 
+// WithRender returns a context with the template to render the secret
+// through set.
+func WithRender(ctx context.Context, fn string) context.Context {
+	return context.WithValue(ctx, ctxKeyRender, fn)
+}
+
+// HasRender returns true if a template to render the secret was set.
+func HasRender(ctx context.Context) bool {
+	return GetRender(ctx) != ""
+}
+
+// GetRender returns the template to render the secret through or an empty
+// string.
+func GetRender(ctx context.Context) string {
+	sv, ok := ctx.Value(ctxKeyRender).(string)
+	if !ok {
+		return ""
+	}
+
+	return sv
+}
+
+// WithRenderAs returns a context with the built-in render target set.
+func WithRenderAs(ctx context.Context, target string) context.Context {
+	return context.WithValue(ctx, ctxKeyRenderAs, target)
+}
+
+// GetRenderAs returns the built-in render target or an empty string.
+func GetRenderAs(ctx context.Context) string {
+	sv, ok := ctx.Value(ctxKeyRenderAs).(string)
+	if !ok {
+		return ""
+	}
+
+	return sv
+}
+
+// WithSpell returns the context with the value for spell (spell out the
+// password) set.
+func WithSpell(ctx context.Context, bv bool) context.Context {
+	return context.WithValue(ctx, ctxKeySpell, bv)
+}
+
+// IsSpell returns the value of spell or the default (false).
+func IsSpell(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeySpell).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
+
+// WithStrict returns the context with the value for strict (print only the
+// password and fail if it's ambiguous) set.
+func WithStrict(ctx context.Context, bv bool) context.Context {
+	return context.WithValue(ctx, ctxKeyStrict, bv)
+}
+
+// IsStrict returns the value of strict or the default (false).
+func IsStrict(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyStrict).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
+
 // WithKey returns a context with the key set.
 func WithKey(ctx context.Context, sv string) context.Context {
 	return context.WithValue(ctx, ctxKeyKey, sv)
@@ -151,3 +227,52 @@ func GetPrintChars(ctx context.Context) []int {
 
 	return mv
 }
+
+// WithEstimateOnly returns the context with the value for estimate only
+// (show the sync estimate but don't sync) set.
+func WithEstimateOnly(ctx context.Context, bv bool) context.Context {
+	return context.WithValue(ctx, ctxKeyEstimateOnly, bv)
+}
+
+// IsEstimateOnly returns the value of estimate only or the default (false).
+func IsEstimateOnly(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyEstimateOnly).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
+
+// withPreset returns the context with the generation preset set.
+func withPreset(ctx context.Context, p preset) context.Context {
+	return context.WithValue(ctx, ctxKeyPreset, p)
+}
+
+// getPreset returns the generation preset or an empty one.
+func getPreset(ctx context.Context) preset {
+	p, ok := ctx.Value(ctxKeyPreset).(preset)
+	if !ok {
+		return preset{}
+	}
+
+	return p
+}
+
+// WithRestrictedShell returns a context that marks commands run from the
+// restricted shell.
+func WithRestrictedShell(ctx context.Context, rs bool) context.Context {
+	return context.WithValue(ctx, ctxKeyRestrictedShell, rs)
+}
+
+// IsRestrictedShell returns true if the command was run from the restricted
+// shell. Commands must not look for entries other than the one they were
+// given then, since the shell only checked that one.
+func IsRestrictedShell(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyRestrictedShell).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
@@ -1,6 +1,8 @@
 package action
 
 import (
+	"context"
+
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/backend/crypto/age"
@@ -54,6 +56,16 @@ func (s *Action) Convert(c *cli.Context) error {
 		}
 	}
 
+	if layout := c.String("layout"); layout != "" {
+		if err := s.convertLayout(ctx, store, layout); err != nil {
+			return err
+		}
+
+		if oldCrypto == crypto.String() && oldStorage == storage.String() {
+			return nil
+		}
+	}
+
 	if oldCrypto == crypto.String() && oldStorage == storage.String() {
 		out.Notice(ctx, "No conversion needed. Source and destination match.")
 
@@ -93,3 +105,31 @@ func (s *Action) Convert(c *cli.Context) error {
 
 	return nil
 }
+
+// convertLayout converts a store to the pack or the loose (one file per entry)
+// layout.
+func (s *Action) convertLayout(ctx context.Context, store, layout string) error {
+	if layout != "pack" && layout != "loose" {
+		return exit.Error(exit.Usage, nil, "unknown layout %q. Use pack or loose", layout)
+	}
+
+	sub, err := s.Store.GetSubStore(store)
+	if err != nil {
+		return exit.Error(exit.NotFound, err, "mount %q not found: %s", store, err)
+	}
+
+	packed := layout == "pack"
+	if sub.IsPacked() == packed {
+		out.Noticef(ctx, "Store %q already uses the %s layout", store, layout)
+
+		return nil
+	}
+
+	if err := sub.SetPacked(ctx, packed); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to convert store %q to the %s layout: %s", store, layout, err)
+	}
+
+	out.OKf(ctx, "Converted %q to the %s layout", store, layout)
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/create"
 	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/hook"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/clipboard"
@@ -40,6 +41,8 @@ func (s *Action) Create(c *cli.Context) error {
 
 // createPrintOrCopy will display the created password (or copy to clipboard).
 func (s *Action) createPrintOrCopy(ctx context.Context, c *cli.Context, name, password string, genPw bool) error {
+	s.publishEntries(ctx, event.EntryCreated, name)
+
 	if !genPw {
 		return nil
 	}
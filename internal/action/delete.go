@@ -55,6 +55,12 @@ func (s *Action) Delete(c *cli.Context) error {
 		return exit.Error(exit.NotFound, nil, "Secret %q does not exist", name)
 	}
 
+	for _, name := range names {
+		if err := s.checkPinned(ctx, name); err != nil {
+			return err
+		}
+	}
+
 	if !c.Bool("force") { // don't check if it's force anyway.
 		qStr := fmt.Sprintf("☠ Are you sure you would like to delete %q?", names)
 		if key != "" {
@@ -0,0 +1,48 @@
+package action
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/demo"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// demoTree returns a copy of the tree with placeholders for all entries and
+// folders. Mounts become regular folders, their location on disk is private,
+// too.
+func demoTree(l *tree.Root) *tree.Root {
+	d := tree.New(l.Name)
+	for _, e := range l.List(tree.INF) {
+		if err := d.AddFile(demo.Name(e), "text/plain"); err != nil {
+			debug.Log("failed to add %s to the demo tree: %s", e, err)
+		}
+	}
+
+	return d
+}
+
+// demoName returns the placeholder for the name in demo mode and the name
+// itself otherwise.
+func demoName(ctx context.Context, name string) string {
+	if !ctxutil.IsDemo(ctx) {
+		return name
+	}
+
+	return demo.Name(name)
+}
+
+// demoNames is like demoName for a list of names.
+func demoNames(ctx context.Context, names []string) []string {
+	if !ctxutil.IsDemo(ctx) {
+		return names
+	}
+
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		out = append(out, demo.Name(n))
+	}
+
+	return out
+}
@@ -0,0 +1,72 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/demo"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemoMode(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("hunter2-secret")
+	require.NoError(t, sec.Set("username", "alice"))
+	require.NoError(t, act.Store.Set(ctx, "bank/checking", sec))
+
+	ctx = ctxutil.WithDemo(ctx, true)
+
+	t.Run("flat list", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"flat": "true"})))
+		assert.ElementsMatch(t, []string{demo.Name("bank/checking"), demo.Name("foo")}, strings.Fields(buf.String()))
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.List(gptest.CliCtx(ctx, t)))
+		assert.NotContains(t, buf.String(), "bank")
+		assert.NotContains(t, buf.String(), "checking")
+		assert.Contains(t, buf.String(), demo.Name("bank")+"/")
+	})
+
+	t.Run("show", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unsafe": "true"}, "bank/checking")))
+		assert.NotContains(t, buf.String(), "hunter2-secret")
+		assert.NotContains(t, buf.String(), "alice")
+		assert.Contains(t, buf.String(), "username: ")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/audit"
@@ -13,17 +14,22 @@ import (
 	"github.com/gopasspw/gopass/internal/hook"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/pkg/pwgen"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
 )
 
 // Edit the content of a password file.
 func (s *Action) Edit(c *cli.Context) error {
-	ctx := ctxutil.WithGlobalFlags(c)
+	// detect changes made by someone else while the editor is open.
+	ctx := leaf.WithConflictCheck(root.WithKeyIndex(ctxutil.WithGlobalFlags(c), true), true)
 	name := c.Args().First()
 	if name == "" {
 		return exit.Error(exit.Usage, nil, "Usage: %s edit secret", s.Name)
@@ -37,6 +43,10 @@ func (s *Action) Edit(c *cli.Context) error {
 		return exit.Error(exit.Recipients, err, "Invalid recipients detected: %s", err)
 	}
 
+	if err := s.checkPinned(ctx, name); err != nil {
+		return err
+	}
+
 	if err := s.edit(ctx, c, name); err != nil {
 		return err
 	}
@@ -70,6 +80,10 @@ func (s *Action) editUpdate(ctx context.Context, name string, content, nContent
 
 	nSec := secrets.ParseAKV(nContent)
 
+	if err := editCheckKeys(ctx, content, nSec); err != nil {
+		return err
+	}
+
 	// if the secret has a password, we check its strength.
 	if pw := nSec.Password(); pw != "" {
 		audit.Single(ctx, pw)
@@ -86,6 +100,39 @@ func (s *Action) editUpdate(ctx context.Context, name string, content, nContent
 	return nil
 }
 
+// editCheckKeys checks the well-known keys that were changed and asks
+// whether to save anyway if any of them has an invalid value.
+func editCheckKeys(ctx context.Context, content []byte, nSec gopass.Secret) error {
+	old := secrets.ParseAKV(content)
+	changed := secrets.NewAKV()
+
+	for _, k := range nSec.Keys() {
+		nv, _ := nSec.Values(k)
+		if ov, _ := old.Values(k); slices.Equal(nv, ov) {
+			continue
+		}
+
+		for _, v := range nv {
+			_ = changed.Add(k, v)
+		}
+	}
+
+	err := secrets.Validate(changed)
+	if err == nil {
+		return nil
+	}
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		out.Warning(ctx, line)
+	}
+
+	if !termio.AskForConfirmation(ctx, "Save anyway?") {
+		return exit.Error(exit.Aborted, err, "not saving invalid values")
+	}
+
+	return nil
+}
+
 func (s *Action) editGetContent(ctx context.Context, name string, create bool) (string, []byte, bool, error) {
 	if !s.Store.Exists(ctx, name) && !create && !config.Bool(ctx, "edit.auto-create") {
 		var err error
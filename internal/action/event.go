@@ -0,0 +1,20 @@
+package action
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/event"
+)
+
+// publishEntries publishes the event for entries of the same mount.
+func (s *Action) publishEntries(ctx context.Context, typ string, names ...string) {
+	if len(names) < 1 {
+		return
+	}
+
+	event.Publish(ctx, event.Event{
+		Type:    typ,
+		Store:   s.Store.MountPoint(names[0]),
+		Entries: names,
+	})
+}
@@ -41,7 +41,12 @@ func (s *Action) findCmd(c *cli.Context, cb showFunc, fuzzy bool) error {
 		return exit.Error(exit.Usage, nil, "Usage: %s find <pattern>", s.Name)
 	}
 
-	return s.find(ctx, c, c.Args().First(), cb, fuzzy)
+	// --interactive lets find pick an entry like search does.
+	if cb == nil && c.Bool("interactive") {
+		cb = s.show
+	}
+
+	return s.find(ctx, c, c.Args().First(), s.findCallback(ctx, c, cb), fuzzy)
 }
 
 // see action.show - context, cli context, name, key, rescurse.
@@ -61,11 +66,11 @@ func (s *Action) find(ctx context.Context, c *cli.Context, needle string, cb sho
 	// if we have an exact match print it.
 	if len(choices) == 1 {
 		if cb == nil {
-			out.Printf(ctx, choices[0])
+			out.Printf(ctx, demoName(ctx, choices[0]))
 
 			return nil
 		}
-		out.OKf(ctx, "Found exact match in %q", choices[0])
+		out.OKf(ctx, "Found exact match in %q", demoName(ctx, choices[0]))
 
 		return cb(ctx, c, choices[0], false)
 	}
@@ -84,8 +89,8 @@ func (s *Action) find(ctx context.Context, c *cli.Context, needle string, cb sho
 
 	// do not invoke wizard if not printing to terminal or if
 	// gopass find/search was invoked directly (for scripts).
-	if !ctxutil.IsTerminal(ctx) || (c != nil && c.Command.Name == "find") {
-		for _, value := range choices {
+	if !ctxutil.IsTerminal(ctx) || (c != nil && c.Command.Name == "find" && cb == nil) {
+		for _, value := range demoNames(ctx, choices) {
 			out.Printf(ctx, value)
 		}
 
@@ -105,23 +110,24 @@ func (s *Action) findSelection(ctx context.Context, c *cli.Context, choices []st
 	}
 
 	sort.Strings(choices)
-	act, sel := cui.GetSelection(ctx, "Found secrets - Please select an entry", choices)
+	labels := demoNames(ctx, choices)
+	act, sel := cui.GetSelection(ctx, "Found secrets - Please select an entry", labels)
 	debug.Log("Action: %s - Selection: %d", act, sel)
 
 	switch act {
 	case "default":
 		// display or copy selected entry.
-		fmt.Fprintln(stdout, choices[sel])
+		fmt.Fprintln(stdout, labels[sel])
 
 		return cb(ctx, c, choices[sel], false)
 	case "copy":
 		// display selected entry.
-		fmt.Fprintln(stdout, choices[sel])
+		fmt.Fprintln(stdout, labels[sel])
 
 		return cb(WithClip(ctx, true), c, choices[sel], false)
 	case "show":
 		// display selected entry.
-		fmt.Fprintln(stdout, choices[sel])
+		fmt.Fprintln(stdout, labels[sel])
 
 		return cb(WithClip(ctx, false), c, choices[sel], false)
 	case "sync":
@@ -133,7 +139,7 @@ func (s *Action) findSelection(ctx context.Context, c *cli.Context, choices []st
 		return cb(ctx, c, needle, true)
 	case "edit":
 		// edit selected entry.
-		fmt.Fprintln(stdout, choices[sel])
+		fmt.Fprintln(stdout, labels[sel])
 
 		return s.edit(ctx, c, choices[sel])
 	default:
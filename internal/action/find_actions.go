@@ -0,0 +1,214 @@
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/browser"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/clipboard"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/urfave/cli/v2"
+)
+
+// findActionMenu is the find.default-action that always shows the quick
+// action menu.
+const findActionMenu = "menu"
+
+// findAction is a quick action offered after an entry was found.
+type findAction struct {
+	name  string
+	label string
+	// avail returns false if the action doesn't apply to the secret.
+	avail func(gopass.Secret) bool
+	run   func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error
+}
+
+// findActions returns the quick actions in menu order.
+func findActions() []findAction {
+	return []findAction{
+		{
+			name:  "copy",
+			label: "Copy password",
+			avail: func(sec gopass.Secret) bool { return sec.Password() != "" },
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return clipboard.CopyTo(ctx, name, []byte(sec.Password()), s.cfg.GetInt("core.cliptimeout"))
+			},
+		},
+		{
+			name:  "copy-user",
+			label: "Copy username",
+			avail: func(sec gopass.Secret) bool { return findUsername(sec) != "" },
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return clipboard.CopyTo(ctx, name+" username", []byte(findUsername(sec)), s.cfg.GetInt("core.cliptimeout"))
+			},
+		},
+		{
+			name:  "show",
+			label: "Show",
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return s.show(WithClip(ctx, false), c, name, false)
+			},
+		},
+		{
+			name:  "otp",
+			label: "Show OTP",
+			avail: otp.Has,
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return s.otp(ctx, name, "", "", false, false, false)
+			},
+		},
+		{
+			name:  "open",
+			label: "Open URL in browser",
+			avail: func(sec gopass.Secret) bool { return findURL(sec) != "" },
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return browser.Open(ctx, findURL(sec))
+			},
+		},
+		{
+			name:  "edit",
+			label: "Edit",
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return s.edit(ctx, c, name)
+			},
+		},
+		{
+			name:  "rotate",
+			label: "Rotate password",
+			run: func(s *Action, ctx context.Context, c *cli.Context, name string, sec gopass.Secret) error {
+				return s.rotateStage(ctx, c, name, "")
+			},
+		},
+	}
+}
+
+// findUsername returns the login name of the secret, if any.
+func findUsername(sec gopass.Secret) string {
+	for _, k := range usernameKeys {
+		if v, found := sec.Get(k); found && v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// findURL returns the first URL of the secret, if any.
+func findURL(sec gopass.Secret) string {
+	v, _ := sec.Get("url")
+
+	return v
+}
+
+// findDefaultAction returns the action configured with find.default-action.
+// It defaults to show.
+func findDefaultAction(ctx context.Context) string {
+	act := config.String(ctx, "find.default-action")
+	if act == "" || act == findActionMenu {
+		return act
+	}
+
+	for _, fa := range findActions() {
+		if fa.name == act {
+			return act
+		}
+	}
+
+	out.Warningf(ctx, "Unknown find.default-action %q. Using show", act)
+
+	return ""
+}
+
+// findCallback returns the callback that handles the entry picked by find.
+// With --actions or find.default-action=menu it offers the quick action
+// menu, otherwise it runs the configured default action or cb.
+func (s *Action) findCallback(ctx context.Context, c *cli.Context, cb showFunc) showFunc {
+	def := findDefaultAction(ctx)
+	if c != nil && c.Bool("actions") {
+		return s.findQuickActions(def, true)
+	}
+
+	switch {
+	case cb == nil:
+		return nil
+	case def == findActionMenu:
+		return s.findQuickActions("", true)
+	case def != "" && def != "show":
+		return s.findQuickActions(def, false)
+	default:
+		return cb
+	}
+}
+
+// findQuickActions returns a callback that runs the quick action def on the
+// found entry. With menu set it lets the user choose the action, def being
+// preselected.
+func (s *Action) findQuickActions(def string, menu bool) showFunc {
+	var fn showFunc
+	fn = func(ctx context.Context, c *cli.Context, name string, recurse bool) error {
+		// the sync action of the selection re-runs the search.
+		if recurse && !s.Store.Exists(ctx, name) {
+			return s.find(ctx, c, name, fn, true)
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+
+		want := def
+		if want == "" {
+			want = "show"
+		}
+
+		all := findActions()
+		actions := make([]findAction, 0, len(all))
+		sel := -1
+		for _, fa := range all {
+			if fa.avail != nil && !fa.avail(sec) {
+				continue
+			}
+			if fa.name == want {
+				sel = len(actions)
+			}
+			actions = append(actions, fa)
+		}
+
+		if !menu && sel < 0 {
+			return exit.Error(exit.Unsupported, nil, "%s can not be used with %s", want, name)
+		}
+
+		if menu {
+			if sel < 0 {
+				sel = 0
+			}
+
+			labels := make([]string, 0, len(actions))
+			for _, fa := range actions {
+				labels = append(labels, fa.label)
+			}
+
+			act, i := cui.GetSelectionDefault(ctx, fmt.Sprintf("What do you want to do with %s?", demoName(ctx, name)), labels, sel)
+			debug.Log("Action: %s - Selection: %d", act, i)
+
+			switch act {
+			case "default", "impossible":
+				sel = i
+			default:
+				return exit.Error(exit.Aborted, nil, "user aborted")
+			}
+		}
+
+		debug.Log("running quick action %s on %s", actions[sel].name, name)
+
+		return actions[sel].run(s, ctx, c, name, sec)
+	}
+
+	return fn
+}
@@ -121,3 +121,66 @@ func TestFind(t *testing.T) {
 	c = gptest.CliCtx(ctx, t)
 	assert.Error(t, act.findSelection(ctx, c, nil, "fo", func(_ context.Context, _ *cli.Context, _ string, _ bool) error { return nil }))
 }
+
+func TestFindActions(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithTerminal(ctx, false)
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+	require.NoError(t, act.cfg.SetEnv("browser.command", "true"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("hunter2")
+	require.NoError(t, sec.Set("url", "https://example.org"))
+	require.NoError(t, act.Store.Set(ctx, "web/example.org", sec))
+
+	t.Run("menu preselects show", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"actions": "true"}, "example")
+		assert.NoError(t, act.Find(c))
+		assert.Contains(t, buf.String(), "hunter2")
+	})
+
+	t.Run("default action", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.SetEnv("find.default-action", "open"))
+		defer func() {
+			require.NoError(t, act.cfg.SetEnv("find.default-action", ""))
+		}()
+
+		c := gptest.CliCtx(ctx, t, "example")
+		assert.NoError(t, act.FindFuzzy(c))
+		assert.NotContains(t, buf.String(), "hunter2")
+
+		// foo has no URL.
+		c = gptest.CliCtx(ctx, t, "foo")
+		assert.Error(t, act.FindFuzzy(c))
+	})
+
+	t.Run("plain find prints matches", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtx(ctx, t, "example")
+		assert.NoError(t, act.Find(c))
+		assert.Equal(t, "web/example.org", strings.TrimSpace(buf.String()))
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/config/legacy"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/internal/tree"
@@ -63,6 +64,8 @@ func (s *Action) Fsck(c *cli.Context) error {
 
 	// the main work in done by the sub stores.
 	if err := s.Store.Fsck(ctx, filter); err != nil {
+		event.Publish(ctx, event.Event{Type: event.FsckFailed, Store: filter, Message: err.Error()})
+
 		return exit.Error(exit.Fsck, err, "fsck found errors: %s", err)
 	}
 	bar.Done()
@@ -0,0 +1,119 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// historyScrubber is implemented by storage backends that can permanently
+// remove files from their history.
+type historyScrubber interface {
+	DeletedBefore(ctx context.Context, before time.Time) ([]string, error)
+	Scrub(ctx context.Context, files []string) error
+	ForcePush(ctx context.Context, remote, branch string) error
+	Adopt(ctx context.Context, remote, branch string) error
+}
+
+// GC permanently removes secrets that were deleted more than --days ago from
+// the history of a store.
+func (s *Action) GC(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.String("store")
+
+	sub, err := s.Store.GetSubStore(name)
+	if err != nil {
+		return exit.Error(exit.NotFound, err, "mount %q not found: %s", name, err)
+	}
+
+	hs, ok := local.Unwrap(sub.Storage()).(historyScrubber)
+	if !ok {
+		return exit.Error(exit.Unsupported, nil, "The storage backend %s of %q does not support rewriting its history", sub.Storage().Name(), name)
+	}
+
+	release, err := sub.Acquire(ctx)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to lock %q: %s", name, err)
+	}
+	defer release()
+
+	if c.Bool("adopt") {
+		return s.gcAdopt(ctx, hs, name)
+	}
+
+	days := c.Int("days")
+	if days < 0 {
+		return exit.Error(exit.Usage, nil, "--days must not be negative")
+	}
+
+	files, err := hs.DeletedBefore(ctx, time.Now().Add(-time.Duration(days)*24*time.Hour))
+	if err != nil {
+		return exit.Error(exit.Git, err, "failed to find deleted secrets: %s", err)
+	}
+
+	if len(files) < 1 {
+		out.OKf(ctx, "No secrets deleted more than %d days ago found in the history of %q", days, name)
+
+		return nil
+	}
+
+	sort.Strings(files)
+	out.Printf(ctx, "The following files were deleted more than %d days ago and will be removed from the history of %q:", days, name)
+	for _, f := range files {
+		out.Printf(ctx, "  - %s", f)
+	}
+	out.Warning(ctx, "This rewrites the whole history of the store. All clones must run 'gopass gc --adopt' afterwards.")
+
+	if !c.Bool("force") {
+		if ok, err := termio.AskForBool(ctx, "Rewrite history?", false); err != nil || !ok {
+			return exit.Error(exit.Aborted, err, "user aborted")
+		}
+	}
+
+	if err := hs.Scrub(ctx, files); err != nil {
+		return exit.Error(exit.Git, err, "failed to scrub history of %q: %s", name, err)
+	}
+	out.OKf(ctx, "Removed %d files from the history of %q", len(files), name)
+
+	if !c.Bool("push") {
+		out.Noticef(ctx, "Run 'gopass gc --push --store %q' to replace the history of the remote", name)
+
+		return nil
+	}
+
+	if err := hs.ForcePush(ctx, "", ""); err != nil {
+		if errors.Is(err, store.ErrGitNoRemote) {
+			return nil
+		}
+
+		return exit.Error(exit.Git, err, "failed to push the rewritten history of %q: %s", name, err)
+	}
+	out.OKf(ctx, "Pushed the rewritten history. Ask your teammates to run 'gopass gc --adopt'")
+
+	return nil
+}
+
+// gcAdopt replaces the local history with the rewritten one from the remote.
+func (s *Action) gcAdopt(ctx context.Context, hs historyScrubber, name string) error {
+	out.Warningf(ctx, "This replaces the local history of %q with the one of its remote. Local changes that were not pushed, yet, are lost.", name)
+	if ok, err := termio.AskForBool(ctx, "Adopt remote history?", false); err != nil || !ok {
+		return exit.Error(exit.Aborted, err, "user aborted")
+	}
+
+	if err := hs.Adopt(ctx, "", ""); err != nil {
+		return exit.Error(exit.Git, err, "failed to adopt the history of the remote of %q: %s", name, err)
+	}
+
+	out.OKf(ctx, "Adopted the history of the remote of %q", name)
+
+	return nil
+}
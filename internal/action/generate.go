@@ -5,17 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/audit"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/create"
+	"github.com/gopasspw/gopass/internal/emailalias"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/sandbox"
 	"github.com/gopasspw/gopass/internal/store"
-	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/pkg/clipboard"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -63,7 +71,8 @@ var reNumber = regexp.MustCompile(`^\d+$`)
 
 // Generate and save a password.
 func (s *Action) Generate(c *cli.Context) error {
-	ctx := ctxutil.WithGlobalFlags(c)
+	// updating an existing entry writes back what was read before.
+	ctx := leaf.WithConflictCheck(root.WithKeyIndex(ctxutil.WithGlobalFlags(c), true), true)
 	ctx = WithClip(ctx, c.Bool("clip"))
 	force := c.Bool("force")
 	edit := c.Bool("edit") // nolint:ifshort
@@ -74,6 +83,22 @@ func (s *Action) Generate(c *cli.Context) error {
 
 	ctx = ctxutil.WithForce(ctx, force)
 
+	if err := validateMetadata(kvps); err != nil {
+		return err
+	}
+
+	if pn := c.String("preset"); pn != "" {
+		p, err := loadPreset(ctx, pn)
+		if err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+		ctx = withPreset(ctx, p)
+
+		if length == "" {
+			length = p.opts["length"]
+		}
+	}
+
 	// ask for name of the secret if it wasn't provided already.
 	if name == "" {
 		var err error
@@ -83,6 +108,15 @@ func (s *Action) Generate(c *cli.Context) error {
 		}
 	}
 
+	name, err := s.normalizeName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkPinned(ctx, name); err != nil {
+		return err
+	}
+
 	// ask for confirmation before overwriting existing entry.
 	if !force { // don't check if it's force anyway.
 		if s.Store.Exists(ctx, name) && key == "" && !termio.AskForConfirmation(ctx, fmt.Sprintf("An entry already exists for %s. Overwrite the current password?", name)) {
@@ -90,27 +124,73 @@ func (s *Action) Generate(c *cli.Context) error {
 		}
 	}
 
+	// ask what to do with the current value of an existing key.
+	backup, err := s.generateKeyConflict(ctx, c, name, key)
+	if err != nil {
+		return err
+	}
+	for k, v := range backup {
+		kvps[k] = v
+	}
+
 	// generate password.
 	password, err := s.generatePassword(ctx, c, length, name)
 	if err != nil {
 		return err
 	}
 
+	if key != "" {
+		if err := secrets.ValidateKey(key, password); err != nil {
+			return exit.Error(exit.Usage, err, "can not generate %s: %s", key, err)
+		}
+	}
+
+	// generate a username for pseudonymous accounts.
+	if c.Bool("username") {
+		if err := s.generateUsername(ctx, c, name, kvps); err != nil {
+			return err
+		}
+	}
+
+	if c.IsSet("security-questions") {
+		if err := s.generateSecurityQuestions(ctx, c, name, kvps); err != nil {
+			return err
+		}
+	}
+
 	// display or copy to clipboard.
 	if err := s.generateCopyOrPrint(ctx, c, name, key, password); err != nil {
 		return err
 	}
 
+	// the alias is requested last, so it's only left behind if the entry
+	// can't be written.
+	var alias *emailalias.Alias
+	if c.Bool("email-alias") {
+		alias, err = s.generateEmailAlias(ctx, c, name, kvps)
+		if err != nil {
+			return err
+		}
+	}
+
 	// write generated password to store.
-	ctx, err = s.generateSetPassword(ctx, name, key, password, kvps, c.Bool("force-regen"))
+	existed := s.Store.Exists(ctx, name)
+	ctx, err = s.generateSetPassword(ctx, c, name, key, password, kvps)
 	if err != nil {
+		s.discardEmailAlias(ctx, alias)
+
 		return err
 	}
 
-	// if requested launch editor to add more data to the generated secret.
+	if existed {
+		s.publishEntries(ctx, event.EntryRotated, name)
+	} else {
+		s.publishEntries(ctx, event.EntryCreated, name)
+	}
+
+	// if requested ask for more data to add to the generated secret.
 	if edit && termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to add more data for %s?", name)) {
-		c.Context = ctx
-		if err := s.Edit(c); err != nil {
+		if err := s.generatePrompt(ctx, name); err != nil {
 			return exit.Error(exit.Unknown, err, "failed to edit %q: %s", name, err)
 		}
 	}
@@ -118,6 +198,33 @@ func (s *Action) Generate(c *cli.Context) error {
 	return nil
 }
 
+// generatePrompt asks for the attributes defined by the create template
+// matching the name and adds them to the secret.
+func (s *Action) generatePrompt(ctx context.Context, name string) error {
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	tpl := create.LookupTemplate(ctx, s.Store.Storage(ctx, name), name)
+	if tpl.Welcome != "" {
+		out.Print(ctx, tpl.Welcome)
+	}
+
+	if err := tpl.Prompt(ctx, sec); err != nil {
+		return err
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Added data to generated secret"), name, sec); err != nil {
+		if !errors.Is(err, store.ErrMeaninglessWrite) {
+			return err
+		}
+		debug.Log("no new data for %s", name)
+	}
+
+	return nil
+}
+
 func keyAndLength(args argList) (string, string) {
 	key := args.Get(1)
 	length := args.Get(2)
@@ -134,6 +241,84 @@ func keyAndLength(args argList) (string, string) {
 	return key, length
 }
 
+// generateUsername adds a random username to kvps unless one was given on
+// the command line or the entry already has one.
+func (s *Action) generateUsername(ctx context.Context, c *cli.Context, name string, kvps map[string]string) error {
+	if _, found := kvps["username"]; found {
+		return nil
+	}
+
+	if s.Store.Exists(ctx, name) && !c.Bool("force-regen") {
+		if sec, err := s.Store.Get(ctx, name); err == nil {
+			if u, found := sec.Get("username"); found && u != "" {
+				out.Noticef(ctx, "Keeping the username %s of %s", u, name)
+
+				return nil
+			}
+		}
+	}
+
+	style := config.String(ctx, "generate.username-style")
+	if c.IsSet("username-style") {
+		style = c.String("username-style")
+	}
+
+	username, err := pwgen.GenerateUsername(style)
+	if err != nil {
+		return exit.Error(exit.Usage, err, "%s", err)
+	}
+	kvps["username"] = username
+
+	out.OKf(ctx, "Username for entry %q generated: %s", name, username)
+
+	return nil
+}
+
+// maxSecurityQuestions is the most security questions generated at once.
+const maxSecurityQuestions = 10
+
+// generateSecurityQuestions adds fake answers to security questions to kvps
+// as question-N and answer-N pairs, asking for the text of the questions.
+// Fake answers can't be researched or phished like real ones. The pairs of an
+// existing entry are kept and the new ones numbered after them.
+func (s *Action) generateSecurityQuestions(ctx context.Context, c *cli.Context, name string, kvps map[string]string) error {
+	n := c.Int("security-questions")
+	if n < 1 || n > maxSecurityQuestions {
+		return exit.Error(exit.Usage, nil, "--security-questions must be between 1 and %d", maxSecurityQuestions)
+	}
+
+	first := 1
+	if s.Store.Exists(ctx, name) && !c.Bool("force-regen") {
+		if sec, err := s.Store.Get(ctx, name); err == nil {
+			for {
+				if _, found := sec.Get(fmt.Sprintf("answer-%d", first)); !found {
+					break
+				}
+				first++
+			}
+		}
+	}
+
+	for i := first; i < first+n; i++ {
+		qk := fmt.Sprintf("question-%d", i)
+		if _, found := kvps[qk]; !found {
+			q, err := termio.AskForString(ctx, fmt.Sprintf("Security question %d?", i), "")
+			if err != nil {
+				return exit.Error(exit.Aborted, err, "failed to read the security question: %s", err)
+			}
+			if q != "" {
+				kvps[qk] = q
+			}
+		}
+
+		kvps[fmt.Sprintf("answer-%d", i)] = pwgen.GenerateAnswer()
+	}
+
+	out.OKf(ctx, "Generated %d security question answers for entry %q. Show them with '%s show %s answer-%d'", n, name, s.Name, name, first)
+
+	return nil
+}
+
 // generateCopyOrPrint will print the password to the screen or copy to the
 // clipboard.
 func (s *Action) generateCopyOrPrint(ctx context.Context, c *cli.Context, name, key, password string) error {
@@ -154,14 +339,14 @@ func (s *Action) generateCopyOrPrint(ctx context.Context, c *cli.Context, name,
 		// if autoclip is on and we're not printing the password to the terminal
 		// at least leave a notice that we did indeed copy it.
 		if s.cfg.GetBool("core.autoclip") && !c.Bool("print") {
-			out.Print(ctx, "Copied to clipboard")
+			out.Info(ctx, "Copied to clipboard")
 
 			return nil
 		}
 	}
 
-	if !c.Bool("print") {
-		out.Printf(ctx, "Not printing secrets by default. Use 'gopass show %s' to display the password.", entry)
+	if !c.Bool("print") && !c.Bool("spell") {
+		out.Infof(ctx, "Not printing secrets by default. Use 'gopass show %s' to display the password.", entry)
 
 		return nil
 	}
@@ -178,10 +363,16 @@ func (s *Action) generateCopyOrPrint(ctx context.Context, c *cli.Context, name,
 		out.Secret(password),
 	)
 
+	if c.Bool("spell") {
+		printSpelled(ctx, password)
+	}
+
 	return nil
 }
 
 func hasPwRuleForSecret(ctx context.Context, name string) (string, pwrules.Rule) {
+	autoUpdateDB(ctx)
+
 	for name != "" && name != "." {
 		d := path.Base(name)
 		if r, found := pwrules.LookupRule(ctx, d); found {
@@ -195,14 +386,59 @@ func hasPwRuleForSecret(ctx context.Context, name string) (string, pwrules.Rule)
 
 // generatePassword will run through the password generation steps.
 func (s *Action) generatePassword(ctx context.Context, c *cli.Context, length, name string) (string, error) {
-	if domain, rule := hasPwRuleForSecret(ctx, name); domain != "" && !c.Bool("force") {
+	if err := checkRNG(ctx, c); err != nil {
+		return "", err
+	}
+
+	checkBreached := c.Bool("check-breached") || config.Bool(ctx, "generate.check-breached")
+
+	for i := 0; ; i++ {
+		pw, err := s.generatePasswordFor(ctx, c, length, name)
+		if err != nil {
+			return "", err
+		}
+		pw = c.String("prefix") + pw
+
+		// the other generators know nothing about the policy, so their
+		// passwords might not comply.
+		if err := s.Store.CheckPolicy(ctx, name, pw); err != nil && !c.Bool("force") {
+			return "", exit.Error(exit.Usage, err, "The generated password does not comply with the password policy: %s. Use --force to ignore", err)
+		}
+
+		if !checkBreached {
+			return pw, nil
+		}
+
+		breached, err := audit.Breached(ctx, pw)
+		if err != nil {
+			return "", exit.Error(exit.Config, err, "Can not check the generated password against the HIBP dump: %s", err)
+		}
+
+		if !breached {
+			return pw, nil
+		}
+
+		if i+1 >= breachedRetries {
+			return "", exit.Error(exit.Unknown, nil, "All %d generated passwords were found in the HIBP dump. Use a longer or stronger password", breachedRetries)
+		}
+
+		out.Warningf(ctx, "The generated password was found in the HIBP dump. Generating a new one ...")
+	}
+}
+
+func (s *Action) generatePasswordFor(ctx context.Context, c *cli.Context, length, name string) (string, error) {
+	// tokens have a fixed format, site rules don't apply to them.
+	if domain, rule := hasPwRuleForSecret(ctx, name); domain != "" && !c.Bool("force") && !pwgen.IsTokenFormat(c.String("generator")) {
 		return s.generatePasswordForRule(ctx, c, length, name, domain, rule)
 	}
 
 	cfg := config.FromContext(ctx)
+	p := getPreset(ctx)
 	symbols := false
 	if c.IsSet("symbols") {
 		symbols = c.Bool("symbols")
+	} else if bv, found := p.bool("symbols"); found {
+		symbols = bv
 	} else {
 		if cfg.IsSet("generate.symbols") {
 			symbols = cfg.GetBool("generate.symbols")
@@ -210,14 +446,33 @@ func (s *Action) generatePassword(ctx context.Context, c *cli.Context, length, n
 	}
 
 	generator := cfg.Get("generate.generator")
+	if pg := p.opts["generator"]; pg != "" {
+		generator = pg
+	}
 	if c.IsSet("generator") {
 		generator = c.String("generator")
 	}
 
+	strict := c.Bool("strict")
+	if bv, found := p.bool("strict"); found && !c.IsSet("strict") {
+		strict = bv
+	}
+
+	restrict := getCharRestrictions(c, cfg)
+	if generator != "" && generator != "cryptic" {
+		if err := restrict.checkFlags(c); err != nil {
+			return "", err
+		}
+	}
+
 	if generator == "xkcd" {
 		return s.generatePasswordXKCD(ctx, c, length)
 	}
 
+	if generator == pwgen.TokenUUID {
+		return pwgen.GenerateUUID()
+	}
+
 	var pwlen int
 	if length == "" {
 		pwlength, err := getPwLengthFromEnvOrAskUser(ctx)
@@ -239,15 +494,27 @@ func (s *Action) generatePassword(ctx context.Context, c *cli.Context, length, n
 
 	switch generator {
 	case "memorable":
-		if c.Bool("strict") {
-			return pwgen.GenerateMemorablePassword(pwlen, symbols, true), nil
-		}
-
-		return pwgen.GenerateMemorablePassword(pwlen, symbols, false), nil
+		return pwgen.GenerateMemorablePassword(pwlen, symbols, strict), nil
+	case pwgen.TokenHex, pwgen.TokenBase64, pwgen.TokenBase58:
+		return pwgen.GenerateToken(generator, pwlen)
 	case "external":
-		return pwgen.GenerateExternal(pwlen)
+		return pwgen.GenerateExternalWith(pwlen, func(name string, args ...string) *exec.Cmd {
+			return sandbox.Command(ctx, name, args...)
+		})
 	default:
-		if c.Bool("strict") {
+		if gen := s.Store.PolicyGenerator(ctx, name, pwlen, symbols); gen != nil && !c.Bool("force") {
+			if err := restrict.apply(gen); err != nil {
+				return "", err
+			}
+
+			return generatePasswordForPolicy(ctx, gen, pwlen)
+		}
+
+		if restrict.isSet() || p.opts["charset"] != "" {
+			return generatePasswordRestricted(pwlen, symbols, strict, p.opts["charset"], restrict)
+		}
+
+		if strict {
 			return pwgen.GeneratePasswordWithAllClasses(pwlen, symbols)
 		}
 
@@ -289,6 +556,31 @@ func clamp(min, max, value int) int {
 	return value
 }
 
+// checkRNG refuses to generate passwords if the system CSPRNG looks unsafe,
+// e.g. in freshly booted VMs or broken containers.
+func checkRNG(ctx context.Context, c *cli.Context) error {
+	err := pwgen.CheckRNG()
+	if err == nil {
+		return nil
+	}
+
+	if c.Bool("insecure-rng-ok") {
+		out.Warningf(ctx, "%s. Generating anyway since --insecure-rng-ok is set", err)
+
+		return nil
+	}
+
+	return exit.Error(exit.Unsupported, err, "Refusing to generate a password: %s. Use --insecure-rng-ok to override", err)
+}
+
+// breachedRetries is the number of attempts to generate a password that is
+// not contained in the HIBP dump.
+const breachedRetries = 8
+
+// pwRuleRetries is the number of attempts to generate a password that
+// complies with the password rules of a site.
+const pwRuleRetries = 16
+
 func (s *Action) generatePasswordForRule(ctx context.Context, c *cli.Context, length, name, domain string, rule pwrules.Rule) (string, error) {
 	out.Noticef(ctx, "Using password rules for %s ...", domain)
 
@@ -306,9 +598,131 @@ func (s *Action) generatePasswordForRule(ctx context.Context, c *cli.Context, le
 
 	iv = clamp(rule.Minlen, rule.Maxlen, iv)
 
-	pw := pwgen.NewCrypticForDomain(ctx, iv, domain).Password()
+	gen := pwgen.NewCrypticForDomain(ctx, iv, domain)
+	if err := getCharRestrictions(c, config.FromContext(ctx)).apply(gen); err != nil {
+		return "", err
+	}
+
+	// don't trust the generator, the rules are parsed on a best effort basis.
+	var lastErr error
+	for i := 0; i < pwRuleRetries; i++ {
+		pw := gen.Password()
+		if pw == "" {
+			lastErr = fmt.Errorf("no password satisfied all validators")
+
+			continue
+		}
+
+		if err := pwgen.ValidateRule(rule, pw); err != nil {
+			debug.Log("generated password for %s violates the rule: %s", domain, err)
+			lastErr = err
+
+			continue
+		}
+
+		return pw, nil
+	}
+
+	return "", exit.Error(exit.Unknown, lastErr, "failed to generate a password that complies with the password rules for %s after %d attempts: %s. Use --force to ignore the rules", domain, pwRuleRetries, lastErr)
+}
+
+// charRestrictions limit the characters of cryptic passwords.
+type charRestrictions struct {
+	// layouts are keyboard layouts the password must be easy to type on.
+	layouts []string
+	// noAmbiguous excludes visually ambiguous characters.
+	noAmbiguous bool
+}
+
+func getCharRestrictions(c *cli.Context, cfg *config.Config) charRestrictions {
+	r := charRestrictions{
+		noAmbiguous: cfg.GetBool("generate.no-ambiguous"),
+	}
+
+	if c.IsSet("no-ambiguous") {
+		r.noAmbiguous = c.Bool("no-ambiguous")
+	}
+
+	for _, l := range strings.Split(c.String("layout-safe"), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			r.layouts = append(r.layouts, l)
+		}
+	}
+
+	return r
+}
+
+func (r charRestrictions) isSet() bool {
+	return len(r.layouts) > 0 || r.noAmbiguous
+}
+
+// checkFlags returns an error if restrictions were requested on the command
+// line. Other generators than cryptic can't honor them. Configured defaults
+// are ignored for those.
+func (r charRestrictions) checkFlags(c *cli.Context) error {
+	for _, flag := range []string{"layout-safe", "no-ambiguous"} {
+		if c.IsSet(flag) {
+			return exit.Error(exit.Usage, nil, "--%s is only supported by the cryptic generator", flag)
+		}
+	}
+
+	return nil
+}
+
+// apply removes the restricted characters from the generator.
+func (r charRestrictions) apply(gen *pwgen.Cryptic) error {
+	if len(r.layouts) > 0 {
+		if err := gen.RestrictToLayouts(r.layouts...); err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+	}
+
+	if r.noAmbiguous {
+		if err := gen.NoAmbiguous(); err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+	}
+
+	return nil
+}
+
+// generatePasswordRestricted generates a cryptic password that only contains
+// characters that are easy to type or read, e.g. for consoles or KVM switches
+// that assume a different keyboard layout, or the characters of a preset.
+func generatePasswordRestricted(pwlen int, symbols, strict bool, charset string, r charRestrictions) (string, error) {
+	gen := pwgen.NewCryptic(pwlen, symbols)
+	if strict {
+		gen = pwgen.NewCrypticWithAllClasses(pwlen, symbols)
+	} else if cs := os.Getenv("GOPASS_CHARACTER_SET"); cs != "" {
+		gen.Chars = cs
+	}
+
+	if charset != "" {
+		gen.Chars = charset
+	}
+
+	if err := r.apply(gen); err != nil {
+		return "", err
+	}
+
+	pw := gen.Password()
+	if pw == "" {
+		return "", exit.Error(exit.Unknown, nil, "failed to generate a password from the restricted character set")
+	}
+
+	return pw, nil
+}
+
+// generatePasswordForPolicy generates a password that complies with the
+// policy packs of the store.
+func generatePasswordForPolicy(ctx context.Context, gen *pwgen.Cryptic, pwlen int) (string, error) {
+	if gen.Length != pwlen {
+		out.Noticef(ctx, "Using a length of %d to comply with the password policy", gen.Length)
+	}
+
+	pw := gen.Password()
 	if pw == "" {
-		return "", fmt.Errorf("failed to generate password for %s", domain)
+		return "", exit.Error(exit.Unknown, nil, "failed to generate a password that complies with the password policy")
 	}
 
 	return pw, nil
@@ -343,11 +757,74 @@ func (s *Action) generatePasswordXKCD(ctx context.Context, c *cli.Context, lengt
 		return "", exit.Error(exit.Usage, nil, "password length must not be zero")
 	}
 
-	return xkcdgen.RandomLengthDelim(pwlen, xkcdSeparator, c.String("lang"))
+	return xkcdgen.RandomLengthOpts(pwlen, xkcdgen.Options{
+		Delim:      xkcdSeparator,
+		Lang:       c.String("lang"),
+		Capitalize: c.Bool("capitalize"),
+		ASCII:      c.Bool("ascii"),
+	})
+}
+
+// generateKeyConflict checks if the key to be generated already exists in the
+// entry. If so it either preserves the current value under a new key (with
+// --backup-old or if the user chooses to keep both) or overwrites it. The
+// returned map contains the value to preserve, if any.
+func (s *Action) generateKeyConflict(ctx context.Context, c *cli.Context, name, key string) (map[string]string, error) {
+	if key == "" || !s.Store.Exists(ctx, name) {
+		return nil, nil
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		// reported when writing the new value.
+		debug.Log("failed to read %q: %s", name, err)
+
+		return nil, nil
+	}
+
+	old, found := sec.Get(key)
+	if !found {
+		return nil, nil
+	}
+
+	if c.Bool("backup-old") {
+		bk := key + "-old-" + time.Now().Format("20060102150405")
+		out.Noticef(ctx, "Preserving the current value of %q as %q", key, bk)
+
+		return map[string]string{bk: old}, nil
+	}
+
+	if c.Bool("force") || ctxutil.IsAlwaysYes(ctx) || !ctxutil.IsInteractive(ctx) {
+		return nil, nil
+	}
+
+	bk := key + "-old"
+	if _, found := sec.Get(bk); found {
+		bk += "-" + time.Now().Format("20060102150405")
+	}
+
+	for i := 0; i < 3; i++ {
+		choice, err := termio.AskForString(ctx, fmt.Sprintf("The key %q of %s already exists. (o)verwrite it, (k)eep the current value as %q or (a)bort?", key, name, bk), "k")
+		if err != nil {
+			return nil, exit.Error(exit.IO, err, "failed to read user input: %s", err)
+		}
+
+		switch strings.ToLower(choice) {
+		case "o", "overwrite":
+			return nil, nil
+		case "k", "keep":
+			return map[string]string{bk: old}, nil
+		case "a", "abort", "q":
+			return nil, exit.Error(exit.Aborted, nil, "user aborted. not overwriting the current value of %q", key)
+		}
+		out.Warningf(ctx, "Unknown answer %q", choice)
+	}
+
+	return nil, exit.Error(exit.Aborted, nil, "no valid answer. not overwriting the current value of %q", key)
 }
 
 // generateSetPassword will update or create a secret.
-func (s *Action) generateSetPassword(ctx context.Context, name, key, password string, kvps map[string]string, regen bool) (context.Context, error) {
+func (s *Action) generateSetPassword(ctx context.Context, c *cli.Context, name, key, password string, kvps map[string]string) (context.Context, error) {
 	// set a single key in an entry.
 	if key != "" {
 		sec, err := s.Store.Get(ctx, name)
@@ -369,8 +846,8 @@ func (s *Action) generateSetPassword(ctx context.Context, name, key, password st
 
 	// replace password in existing secret. we might be asked to skip the
 	// check to enforce possibly re-evaluating templates.
-	if !regen && s.Store.Exists(ctx, name) {
-		ctx, err := s.generateReplaceExisting(ctx, name, key, password, kvps)
+	if !c.Bool("force-regen") && s.Store.Exists(ctx, name) {
+		ctx, err := s.generateReplaceExisting(ctx, name, key, password, c.Int("keep-old"), kvps)
 		if err == nil {
 			return ctx, nil
 		}
@@ -386,15 +863,22 @@ func (s *Action) generateSetPassword(ctx context.Context, name, key, password st
 		_ = sec.Set("password-change-url", u)
 	}
 
-	if content, found := s.renderTemplate(ctx, name, []byte(password)); found {
-		nSec := secrets.NewAKV()
-		if _, err := nSec.Write(content); err == nil {
+	if !c.Bool("ignore-template") {
+		content, found, err := s.executeTemplate(ctx, name, c.String("template"), []byte(password))
+		if err != nil {
+			return ctx, exit.Error(exit.Unknown, err, "%s. Use --ignore-template to store the password only", err)
+		}
+		if found {
+			nSec := secrets.NewAKV()
+			if _, err := nSec.Write(content); err != nil {
+				return ctx, exit.Error(exit.Unknown, err, "failed to parse the rendered template for %q: %s. Use --ignore-template to store the password only", name, err)
+			}
 			sec = nSec
-		} else {
-			debug.Log("failed to handle template: %s", err)
 		}
 	}
 
+	setMetadata(sec, kvps)
+
 	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Generated Password"), name, sec); err != nil {
 		if !errors.Is(err, store.ErrMeaninglessWrite) {
 			return ctx, exit.Error(exit.Encrypt, err, "failed to create %q: %s", name, err)
@@ -416,12 +900,13 @@ func hasChangeURL(ctx context.Context, name string) string {
 	return ""
 }
 
-func (s *Action) generateReplaceExisting(ctx context.Context, name, key, password string, kvps map[string]string) (context.Context, error) {
+func (s *Action) generateReplaceExisting(ctx context.Context, name, key, password string, keepOld int, kvps map[string]string) (context.Context, error) {
 	sec, err := s.Store.Get(ctx, name)
 	if err != nil {
 		return ctx, exit.Error(exit.Encrypt, err, "failed to set key %q of %q: %s", key, name, err)
 	}
 
+	keepOldPasswords(sec, keepOld)
 	setMetadata(sec, kvps)
 	sec.SetPassword(password)
 	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Generated password for YAML key"), name, sec); err != nil {
@@ -434,6 +919,55 @@ func (s *Action) generateReplaceExisting(ctx context.Context, name, key, passwor
 	return ctx, nil
 }
 
+// oldPasswordPrefix is the prefix of the keys that hold previous passwords.
+const oldPasswordPrefix = "old-password-"
+
+// keepOldPasswords preserves the current password of the secret under a
+// timestamped key and removes all but the n most recent old passwords. Nothing
+// happens if n is zero. Passwords kept within the same second get a sequence
+// suffix, e.g. old-password-20240101120000-02.
+func keepOldPasswords(sec gopass.Secret, n int) {
+	if n < 1 || sec.Password() == "" {
+		return
+	}
+
+	base := oldPasswordPrefix + time.Now().Format("20060102150405")
+	key := base
+	for i := 2; ; i++ {
+		if _, found := sec.Get(key); !found {
+			break
+		}
+		key = fmt.Sprintf("%s-%02d", base, i)
+	}
+	_ = sec.Set(key, sec.Password())
+
+	var old []string
+	for _, k := range sec.Keys() {
+		if strings.HasPrefix(k, oldPasswordPrefix) {
+			old = append(old, k)
+		}
+	}
+
+	// the timestamps sort chronologically.
+	sort.Strings(old)
+	for len(old) > n {
+		debug.Log("removing %s", old[0])
+		_ = sec.Del(old[0])
+		old = old[1:]
+	}
+}
+
+// validateMetadata checks the values given for well-known keys.
+func validateMetadata(kvps map[string]string) error {
+	for k, v := range kvps {
+		if err := secrets.ValidateKey(k, v); err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+	}
+
+	return nil
+}
+
 func setMetadata(sec gopass.Secret, kvps map[string]string) {
 	for k, v := range kvps {
 		debug.Log("setting %s to %s", k, v)
@@ -444,6 +978,10 @@ func setMetadata(sec gopass.Secret, kvps map[string]string) {
 // CompleteGenerate implements the completion heuristic for the generate command.
 func (s *Action) CompleteGenerate(c *cli.Context) {
 	ctx := ctxutil.WithGlobalFlags(c)
+	if completePresets(ctx) {
+		return
+	}
+
 	if c.Args().Len() < 1 {
 		return
 	}
@@ -456,7 +994,7 @@ func (s *Action) CompleteGenerate(c *cli.Context) {
 		return
 	}
 
-	list, err := s.Store.List(ctx, tree.INF)
+	list, err := s.Store.CachedList(ctx)
 	if err != nil {
 		return
 	}
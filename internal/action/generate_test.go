@@ -6,14 +6,20 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/hashsum"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,6 +31,52 @@ func TestRuleLookup(t *testing.T) {
 	assert.Equal(t, "", domain)
 }
 
+func TestGeneratePwRules(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	t.Run("password complies with the rule", func(t *testing.T) {
+		defer buf.Reset()
+
+		for i := 0; i < 8; i++ {
+			require.NoError(t, act.Generate(gptest.CliCtx(ctx, t, "web/dkb.de", "12")))
+			sec, err := act.Store.Get(ctx, "web/dkb.de")
+			require.NoError(t, err)
+
+			rule, found := pwrules.LookupRule(ctx, "dkb.de")
+			require.True(t, found)
+			assert.NoError(t, pwgen.ValidateRule(rule, sec.Password()))
+		}
+	})
+
+	t.Run("unsatisfiable rule fails loudly", func(t *testing.T) {
+		defer buf.Reset()
+
+		// AZERTY layouts exclude all digits, but the rule requires one.
+		err := act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"layout-safe": "fr"}, "web/1800flowers.com", "12"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "password rules for 1800flowers.com")
+		assert.False(t, act.Store.Exists(ctx, "web/1800flowers.com"))
+	})
+}
+
 func TestGenerate(t *testing.T) {
 	u := gptest.NewUnitTester(t)
 
@@ -177,6 +229,115 @@ func TestGenerate(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("generate --force --symbols --layout-safe de,fr foobar 32", func(t *testing.T) {
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "print": "true", "symbols": "true", "layout-safe": "de,fr"}, "foobar", "32")))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		pw := lines[len(lines)-1]
+		assert.Len(t, pw, 32)
+		assert.False(t, strings.ContainsAny(pw, "yzaqwmYZAQWM0123456789@#[]{}"), pw)
+		buf.Reset()
+	})
+
+	t.Run("generate --force --generator b58 --prefix sk_live_ foobar 24", func(t *testing.T) {
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "print": "true", "generator": "b58", "prefix": "sk_live_"}, "foobar", "24")))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Regexp(t, `^sk_live_[1-9A-HJ-NP-Za-km-z]{24}$`, lines[len(lines)-1])
+		buf.Reset()
+	})
+
+	t.Run("generate --force --generator uuid foobar", func(t *testing.T) {
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "print": "true", "generator": "uuid"}, "foobar")))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, lines[len(lines)-1])
+		buf.Reset()
+	})
+
+	t.Run("generate --force --spell foobar 8", func(t *testing.T) {
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "spell": "true", "symbols": "false"}, "foobar", "8")))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Greater(t, len(lines), 9)
+		assert.Regexp(t, `^1  [A-Za-z0-9]  [A-Za-z -]+$`, lines[len(lines)-9])
+		assert.Regexp(t, `^8  [A-Za-z0-9]  [A-Za-z -]+$`, lines[len(lines)-1])
+		buf.Reset()
+	})
+
+	t.Run("generate --force --no-ambiguous foobar 64", func(t *testing.T) {
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "print": "true", "no-ambiguous": "true"}, "foobar", "64")))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		pw := lines[len(lines)-1]
+		assert.Len(t, pw, 64)
+		assert.False(t, strings.ContainsAny(pw, pwgen.Ambiq), pw)
+		assert.NotContains(t, pw, "rn")
+		buf.Reset()
+	})
+
+	t.Run("generate.no-ambiguous is ignored by xkcd", func(t *testing.T) {
+		require.NoError(t, act.cfg.Set("", "generate.no-ambiguous", "true"))
+		defer func() {
+			require.NoError(t, act.cfg.Set("", "generate.no-ambiguous", "false"))
+		}()
+
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "generator": "xkcd", "lang": "en"}, "foobar", "4")))
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "generator": "xkcd", "no-ambiguous": "true"}, "foobar", "4")))
+		buf.Reset()
+	})
+
+	t.Run("generate --force --generator xkcd --layout-safe de foobar", func(t *testing.T) {
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "generator": "xkcd", "layout-safe": "de"}, "foobar")))
+		buf.Reset()
+	})
+
+	t.Run("generate --force --layout-safe klingon foobar", func(t *testing.T) {
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "layout-safe": "klingon"}, "foobar", "24")))
+		buf.Reset()
+	})
+
+	t.Run("generate --username --username-style snake anon/forum", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"username": "true", "username-style": "snake"}, "anon/forum", "16")))
+		sec, err := act.Store.Get(ctx, "anon/forum")
+		require.NoError(t, err)
+		username, found := sec.Get("username")
+		assert.True(t, found)
+		assert.Regexp(t, `^[a-z]+_[a-z]+_[0-9]+$`, username)
+
+		// the username of existing accounts is kept.
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "username": "true"}, "anon/forum", "16")))
+		sec, err = act.Store.Get(ctx, "anon/forum")
+		require.NoError(t, err)
+		got, _ := sec.Get("username")
+		assert.Equal(t, username, got)
+
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"username": "true", "username-style": "leet"}, "anon/other", "16")))
+	})
+
+	t.Run("generate --security-questions 2 bank/online", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"security-questions": "2"}, "bank/online", "question-1=First car?", "16")))
+		sec, err := act.Store.Get(ctx, "bank/online")
+		require.NoError(t, err)
+		q, _ := sec.Get("question-1")
+		assert.Equal(t, "First car?", q)
+		for _, k := range []string{"answer-1", "answer-2"} {
+			a, found := sec.Get(k)
+			assert.True(t, found, k)
+			assert.Regexp(t, `^[a-z]+ [a-z]+ [a-z]+$`, a)
+		}
+
+		// new questions are added after the existing ones.
+		assert.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true", "security-questions": "1"}, "bank/online", "16")))
+		sec, err = act.Store.Get(ctx, "bank/online")
+		require.NoError(t, err)
+		_, found := sec.Get("answer-3")
+		assert.True(t, found)
+		q, _ = sec.Get("question-1")
+		assert.Equal(t, "First car?", q)
+
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"security-questions": "0"}, "bank/other", "16")))
+	})
+
 	// generate --force foobar w/ pw length set via env variable (42 chars)
 	t.Run("generate --force foobar", func(t *testing.T) {
 		t.Setenv("GOPASS_PW_DEFAULT_LENGTH", "42")
@@ -379,3 +540,221 @@ func TestDefaultLengthFromEnv(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateTemplate(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	require.NoError(t, act.Store.SetTemplate(ctx, "broken", []byte("{{ .Content")))
+	require.NoError(t, act.Store.SetTemplate(ctx, "good", []byte("{{ .Content }}\nuser: john")))
+
+	t.Run("template errors are surfaced", func(t *testing.T) {
+		assert.Error(t, act.Generate(gptest.CliCtx(ctx, t, "broken/foo")))
+		assert.False(t, act.Store.Exists(ctx, "broken/foo"))
+	})
+
+	t.Run("--ignore-template", func(t *testing.T) {
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"ignore-template": "true"}, "broken/bar")))
+		sec, err := act.Store.Get(ctx, "broken/bar")
+		require.NoError(t, err)
+		assert.Empty(t, sec.Keys())
+	})
+
+	t.Run("--template", func(t *testing.T) {
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"template": "good"}, "other/foo")))
+		sec, err := act.Store.Get(ctx, "other/foo")
+		require.NoError(t, err)
+		user, found := sec.Get("user")
+		assert.True(t, found)
+		assert.Equal(t, "john", user)
+	})
+
+	t.Run("--template unknown", func(t *testing.T) {
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"template": "missing"}, "other/bar")))
+	})
+}
+
+func TestGenerateKeyConflict(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	termio.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		termio.Stderr = os.Stderr
+		termio.Stdin = os.Stdin
+	}()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("api", "oldtoken"))
+	require.NoError(t, act.Store.Set(ctx, "foo", sec))
+
+	t.Run("new key", func(t *testing.T) {
+		backup, err := act.generateKeyConflict(ctx, gptest.CliCtx(ctx, t), "foo", "other")
+		require.NoError(t, err)
+		assert.Empty(t, backup)
+	})
+
+	t.Run("non-interactive overwrites", func(t *testing.T) {
+		backup, err := act.generateKeyConflict(ctx, gptest.CliCtx(ctx, t), "foo", "api")
+		require.NoError(t, err)
+		assert.Empty(t, backup)
+	})
+
+	t.Run("backup-old", func(t *testing.T) {
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"backup-old": "true"}, "foo", "api")
+		backup, err := act.generateKeyConflict(ctx, c, "foo", "api")
+		require.NoError(t, err)
+		require.Len(t, backup, 1)
+		for k, v := range backup {
+			assert.True(t, strings.HasPrefix(k, "api-old-"), k)
+			assert.Equal(t, "oldtoken", v)
+		}
+	})
+
+	ictx := ctxutil.WithInteractive(ctx, true)
+	for _, tc := range []struct {
+		answer string
+		want   map[string]string
+		err    bool
+	}{
+		{answer: "k\n", want: map[string]string{"api-old": "oldtoken"}},
+		{answer: "\n", want: map[string]string{"api-old": "oldtoken"}},
+		{answer: "o\n"},
+		{answer: "a\n", err: true},
+	} {
+		tc := tc
+		t.Run("answer "+strings.TrimSpace(tc.answer), func(t *testing.T) {
+			termio.Stdin = strings.NewReader(tc.answer)
+			backup, err := act.generateKeyConflict(ictx, gptest.CliCtx(ictx, t), "foo", "api")
+			if tc.err {
+				assert.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, backup)
+		})
+	}
+}
+
+func TestKeepOldPasswords(t *testing.T) {
+	t.Parallel()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("current")
+	require.NoError(t, sec.Set("old-password-20200101000000", "oldest"))
+	require.NoError(t, sec.Set("old-password-20210101000000", "older"))
+	require.NoError(t, sec.Set("user", "admin"))
+
+	keepOldPasswords(sec, 0)
+	assert.Len(t, sec.Keys(), 3)
+
+	keepOldPasswords(sec, 2)
+
+	var old []string
+	for _, k := range sec.Keys() {
+		if strings.HasPrefix(k, oldPasswordPrefix) {
+			v, _ := sec.Get(k)
+			old = append(old, v)
+		}
+	}
+	assert.Equal(t, []string{"older", "current"}, old)
+
+	// rotations within the same second don't overwrite each other.
+	sec.SetPassword("next")
+	keepOldPasswords(sec, 5)
+	sec.SetPassword("last")
+	keepOldPasswords(sec, 5)
+
+	old = old[:0]
+	for _, k := range sec.Keys() {
+		if strings.HasPrefix(k, oldPasswordPrefix) {
+			v, _ := sec.Get(k)
+			old = append(old, v)
+		}
+	}
+	assert.Equal(t, []string{"older", "current", "next", "last"}, old)
+
+	v, found := sec.Get("user")
+	assert.True(t, found)
+	assert.Equal(t, "admin", v)
+}
+
+func TestGenerateCheckBreached(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	t.Setenv(pwgen.UnsafeDeterministicEnv, "1")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	defer pwgen.ResetSource()
+
+	// the first password generated with this seed.
+	require.NoError(t, pwgen.Seed(42))
+	breached, err := act.generatePassword(ctx, gptest.CliCtx(ctx, t), "12", "foo")
+	require.NoError(t, err)
+
+	t.Run("no dump", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, err := act.generatePassword(ctx, gptest.CliCtxWithFlags(ctx, t, map[string]string{"check-breached": "true"}, "foo"), "12", "foo")
+		assert.Error(t, err)
+	})
+
+	fn := filepath.Join(t.TempDir(), "hibp.txt")
+	require.NoError(t, os.WriteFile(fn, []byte(strings.ToUpper(hashsum.SHA1Hex(breached))+":3\n"), 0o600))
+	require.NoError(t, act.cfg.Set("", "audit.hibp-dump-file", fn))
+
+	t.Run("regenerates breached passwords", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, pwgen.Seed(42))
+		pw, err := act.generatePassword(ctx, gptest.CliCtxWithFlags(ctx, t, map[string]string{"check-breached": "true"}, "foo"), "12", "foo")
+		require.NoError(t, err)
+		assert.NotEqual(t, breached, pw)
+		assert.Len(t, pw, 12)
+		assert.Contains(t, buf.String(), "found in the HIBP dump")
+	})
+}
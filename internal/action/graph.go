@@ -0,0 +1,84 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/canary"
+	"github.com/gopasspw/gopass/internal/graph"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/urfave/cli/v2"
+)
+
+// Graph prints the relationships between entries, i.e. duplicates, shared
+// passwords, references and aliased domains, as DOT or JSON.
+func (s *Action) Graph(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	// every entry is decrypted, so canaries would always trigger.
+	ctx = canary.WithSuppressed(ctx, true)
+
+	format := c.String("format")
+	switch format {
+	case "":
+		format = "dot"
+	case "dot", "json":
+	default:
+		return exit.Error(exit.Usage, nil, "unknown format %q. Use dot or json", format)
+	}
+
+	names, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	prefix := strings.TrimSuffix(c.Args().First(), "/")
+
+	b := graph.New(func(domain string) []string {
+		return pwrules.LookupAliases(ctx, domain)
+	})
+
+	var failed int
+	sp := out.NewSpinner(ctx, "Decrypting")
+	for i, name := range names {
+		sp.Step("%d/%d", i+1, len(names))
+		if prefix != "" && name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			continue
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			out.Errorf(ctx, "failed to decrypt %s: %v", name, err)
+			failed++
+
+			continue
+		}
+		b.Add(name, sec)
+	}
+	sp.Done()
+
+	if failed > 0 {
+		out.Warningf(ctx, "%d secrets failed to decrypt", failed)
+	}
+
+	g := b.Graph(c.Bool("all"))
+
+	if format == "dot" {
+		fmt.Fprint(stdout, g.DOT())
+
+		return nil
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(g); err != nil {
+		return exit.Error(exit.IO, err, "failed to encode graph: %s", err)
+	}
+
+	return nil
+}
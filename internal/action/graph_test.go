@@ -0,0 +1,76 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	// foo has the same password.
+	sec := secrets.NewAKV()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("parent", "foo"))
+	require.NoError(t, act.Store.Set(ctx, "web/example.org", sec))
+
+	t.Run("dot", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Graph(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "dot"})))
+		assert.Contains(t, buf.String(), `"foo" -- "web/example.org" [label="shared-password"];`)
+		assert.Contains(t, buf.String(), `"web/example.org" -- "foo" [label="reference", dir=forward];`)
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Graph(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "json"})))
+		assert.Contains(t, buf.String(), `"kind": "reference"`)
+	})
+
+	t.Run("folder", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Graph(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "dot", "all": "true"}, "web")))
+		assert.Contains(t, buf.String(), `"web/example.org";`)
+		assert.NotContains(t, buf.String(), `"foo"`)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.Graph(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "svg"})))
+	})
+}
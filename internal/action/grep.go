@@ -1,14 +1,23 @@
 package action
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/urfave/cli/v2"
 )
 
@@ -30,6 +39,9 @@ func (s *Action) Grep(c *cli.Context) error {
 	matchFn := func(haystack string) bool {
 		return strings.Contains(haystack, needle)
 	}
+	replaceFn := func(haystack, repl string) string {
+		return strings.ReplaceAll(haystack, needle, repl)
+	}
 
 	if c.Bool("regexp") {
 		re, err := regexp.Compile(needle)
@@ -37,27 +49,208 @@ func (s *Action) Grep(c *cli.Context) error {
 			return exit.Error(exit.Usage, err, "failed to compile regexp %q: %s", needle, err)
 		}
 		matchFn = re.MatchString
+		replaceFn = re.ReplaceAllString
+	}
+
+	var r *grepReplacer
+	if c.IsSet("replace") {
+		r = &grepReplacer{
+			repl:    c.String("replace"),
+			replace: replaceFn,
+			all:     ctxutil.IsAlwaysYes(ctx),
+			stores:  map[string]backend.Storage{},
+		}
+		// all changes are committed at once at the end.
+		ctx = ctxutil.WithGitCommit(ctx, false)
 	}
 
 	var matches int
-	var errors int
-	for _, v := range haystack {
+	var failed int
+	var replaceErr error
+	var sp *out.Spinner
+	if r == nil {
+		// the spinner would interfere with the prompts.
+		sp = out.NewSpinner(ctx, "Searching")
+	}
+	for i, v := range haystack {
+		sp.Step("%d/%d", i+1, len(haystack))
 		sec, err := s.Store.Get(ctx, v)
 		if err != nil {
 			out.Errorf(ctx, "failed to decrypt %s: %v", v, err)
+			failed++
 
 			continue
 		}
 
-		if matchFn(string(sec.Bytes())) {
+		if !matchFn(string(sec.Bytes())) {
+			continue
+		}
+
+		matches++
+		if r == nil {
 			out.Printf(ctx, "%s matches", color.BlueString(v))
+
+			continue
+		}
+
+		if err := s.grepReplace(ctx, r, v, sec.Bytes()); err != nil {
+			// the replacements so far are still committed below.
+			if !errors.Is(err, termio.ErrAborted) {
+				replaceErr = err
+			}
+
+			break
+		}
+	}
+
+	sp.Done()
+
+	if failed > 0 {
+		out.Warningf(ctx, "%d secrets failed to decrypt", failed)
+	}
+	out.Printf(ctx, "\nScanned %d secrets. %d matches, %d errors", len(haystack), matches, failed)
+
+	if r == nil {
+		return nil
+	}
+
+	out.Printf(ctx, "Replaced %q with %q in %d secrets", needle, r.repl, r.replaced)
+
+	err = r.commit(ctx, fmt.Sprintf("Replaced %q in %d secrets", needle, r.replaced))
+	if replaceErr == nil {
+		return err
+	}
+
+	if err != nil {
+		out.Errorf(ctx, "%s", err)
+	}
+
+	return replaceErr
+}
+
+// grepReplacer holds the state of an interactive grep --replace run.
+type grepReplacer struct {
+	repl     string
+	replace  func(string, string) string
+	all      bool
+	replaced int
+	// stores that were modified, by path.
+	stores map[string]backend.Storage
+}
+
+func (s *Action) grepReplace(ctx context.Context, r *grepReplacer, name string, content []byte) error {
+	nContent := r.replace(string(content), r.repl)
+	if nContent == string(content) {
+		return nil
+	}
+
+	if !r.all {
+		out.Printf(ctx, "%s:", color.BlueString(name))
+		grepPrintDiff(ctx, string(content), nContent)
+
+		ok, err := r.ask(ctx, name)
+		if err != nil {
+			return err
 		}
+		if !ok {
+			return nil
+		}
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Replaced with grep"), name, secrets.ParseAKV([]byte(nContent))); err != nil {
+		if !errors.Is(err, store.ErrMeaninglessWrite) {
+			return exit.Error(exit.Encrypt, err, "failed to save %q: %s", name, err)
+		}
+	}
+
+	st := s.Store.Storage(ctx, name)
+	r.stores[st.Path()] = st
+	r.replaced++
+
+	return nil
+}
+
+// ask asks whether to replace the matches in the given entry. Answering
+// "a" replaces all remaining matches without asking.
+func (r *grepReplacer) ask(ctx context.Context, name string) (bool, error) {
+	for i := 0; i < 3; i++ {
+		choice, err := termio.AskForString(ctx, fmt.Sprintf("Replace in %s? (y)es, (n)o, (a)ll remaining or (q)uit", name), "n")
+		if err != nil {
+			return false, exit.Error(exit.IO, err, "failed to read user input: %s", err)
+		}
+
+		switch strings.ToLower(choice) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		case "a", "all":
+			r.all = true
+
+			return true, nil
+		case "q", "quit":
+			return false, termio.ErrAborted
+		}
+		out.Warningf(ctx, "Unknown answer %q", choice)
 	}
 
-	if errors > 0 {
-		out.Warningf(ctx, "%d secrets failed to decrypt", errors)
+	return false, nil
+}
+
+// grepPrintDiff prints the lines that differ between before and after.
+func grepPrintDiff(ctx context.Context, before, after string) {
+	ol := strings.Split(before, "\n")
+	nl := strings.Split(after, "\n")
+
+	for i := 0; i < len(ol) || i < len(nl); i++ {
+		var o, n string
+		if i < len(ol) {
+			o = ol[i]
+		}
+		if i < len(nl) {
+			n = nl[i]
+		}
+		if o == n {
+			continue
+		}
+		out.Printf(ctx, "  %s", color.RedString("- %s", o))
+		out.Printf(ctx, "  %s", color.GreenString("+ %s", n))
+	}
+}
+
+// commit commits the changes to all modified stores and pushes them if
+// autopush is enabled.
+func (r *grepReplacer) commit(ctx context.Context, msg string) error {
+	for p, st := range r.stores {
+		if err := st.Commit(ctx, msg); err != nil {
+			switch {
+			case errors.Is(err, store.ErrGitNotInit):
+				debug.Log("skipping git commit - git not initialized in %s", p)
+
+				continue
+			case errors.Is(err, store.ErrGitNothingToCommit):
+				debug.Log("skipping git commit - nothing to commit in %s", p)
+
+				continue
+			default:
+				return exit.Error(exit.Git, err, "failed to commit changes to git (%s): %s", p, err)
+			}
+		}
+
+		if !config.Bool(ctx, "core.autopush") {
+			continue
+		}
+
+		if err := st.Push(ctx, "", ""); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) || errors.Is(err, store.ErrGitNoRemote) {
+				debug.Log("skipping git push in %s: %s", p, err)
+
+				continue
+			}
+
+			return exit.Error(exit.Git, err, "failed to push changes to git remote (%s): %s", p, err)
+		}
 	}
-	out.Printf(ctx, "\nScanned %d secrets. %d matches, %d errors", len(haystack), matches, errors)
 
 	return nil
 }
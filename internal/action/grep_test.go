@@ -57,3 +57,67 @@ func TestGrep(t *testing.T) {
 		assert.NoError(t, act.Grep(c))
 	})
 }
+
+func TestGrepReplace(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	for _, name := range []string{"web/a", "web/b"} {
+		sec := secrets.NewAKV()
+		sec.SetPassword("secret")
+		_, err := sec.Write([]byte("user: jdoe@example.com\n"))
+		require.NoError(t, err)
+		require.NoError(t, act.Store.Set(ctx, name, sec))
+	}
+
+	t.Run("decline", func(t *testing.T) {
+		defer buf.Reset()
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"replace": "example.org"}, "example.com")
+		assert.NoError(t, act.Grep(c))
+
+		sec, err := act.Store.Get(ctx, "web/a")
+		require.NoError(t, err)
+		assert.Contains(t, string(sec.Bytes()), "example.com")
+	})
+
+	t.Run("regexp replace all", func(t *testing.T) {
+		defer buf.Reset()
+		ctx := ctxutil.WithAlwaysYes(ctx, true)
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"regexp": "true", "replace": "$1@example.org"}, `(\w+)@example\.com`)
+		assert.NoError(t, act.Grep(c))
+
+		for _, name := range []string{"web/a", "web/b"} {
+			sec, err := act.Store.Get(ctx, name)
+			require.NoError(t, err)
+			assert.Equal(t, "secret", sec.Password())
+			v, found := sec.Get("user")
+			assert.True(t, found)
+			assert.Equal(t, "jdoe@example.org", v)
+		}
+		assert.Contains(t, buf.String(), "in 2 secrets")
+	})
+	t.Run("partial failure", func(t *testing.T) {
+		defer buf.Reset()
+		ctx := ctxutil.WithAlwaysYes(ctx, true)
+		require.NoError(t, act.Store.Pin(ctx, "web/b"))
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"replace": "example.net"}, "example.org")
+		assert.Error(t, act.Grep(c))
+
+		sec, err := act.Store.Get(ctx, "web/a")
+		require.NoError(t, err)
+		assert.Contains(t, string(sec.Bytes()), "example.net")
+		assert.Contains(t, buf.String(), "in 1 secrets")
+	})
+}
@@ -0,0 +1,110 @@
+package action
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/keychain"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// keychainList reads the credentials from the OS password manager. It's
+// replaced in tests.
+var keychainList = keychain.List
+
+// ImportKeychain adds the web credentials stored in the password manager of
+// the operating system to the store. Nothing is read without the consent of
+// the user.
+func (s *Action) ImportKeychain(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	prefix := strings.Trim(c.String("prefix"), "/")
+
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("gopass will read all web credentials from %s. Continue?", keychain.Source)) {
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	creds, err := keychainList(ctx)
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to read credentials from %s: %s", keychain.Source, err)
+	}
+
+	if len(creds) < 1 {
+		out.Noticef(ctx, "No web credentials found in %s", keychain.Source)
+
+		return nil
+	}
+
+	out.Printf(ctx, "Found %d credentials:", len(creds))
+	for _, cred := range creds {
+		out.Printf(ctx, "  %s", keychainImportName(prefix, cred))
+	}
+
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Import %d credentials into %s?", len(creds), prefix)) {
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Imported credentials from the OS password manager")
+
+	var added, skipped int
+	for _, cred := range creds {
+		name, err := s.normalizeName(ctx, keychainImportName(prefix, cred))
+		if err != nil {
+			return err
+		}
+
+		if s.Store.Exists(ctx, name) && !c.Bool("force") {
+			out.Warningf(ctx, "%s already exists. Use --force to replace it", name)
+			skipped++
+
+			continue
+		}
+
+		sec := secrets.NewAKV()
+		sec.SetPassword(cred.Password)
+		if cred.User != "" {
+			_ = sec.Set("username", cred.User)
+		}
+		_ = sec.Set("url", "https://"+cred.Server)
+
+		if err := s.Store.Set(ctx, name, sec); err != nil {
+			return exit.Error(exit.Encrypt, err, "failed to write %s: %s", name, err)
+		}
+
+		out.Printf(ctx, "Imported %s", name)
+		added++
+	}
+
+	out.OKf(ctx, "Imported %d credentials, %d were skipped", added, skipped)
+
+	return nil
+}
+
+// keychainImportName returns the name of the entry for imported credentials,
+// i.e. <prefix>/<server>/<user>.
+func keychainImportName(prefix string, cred keychain.Credential) string {
+	clean := func(s string) string {
+		s = strings.TrimSpace(strings.ReplaceAll(s, "/", "_"))
+		if s == "." || s == ".." {
+			return "_"
+		}
+
+		return s
+	}
+
+	user := clean(cred.User)
+	if user == "" {
+		user = "unnamed"
+	}
+
+	return path.Join(prefix, clean(cred.Server), user)
+}
@@ -0,0 +1,88 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/keychain"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportKeychain(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		keychainList = keychain.List
+	}()
+	color.NoColor = true
+
+	keychainList = func(context.Context) ([]keychain.Credential, error) {
+		return []keychain.Credential{
+			{Server: "github.com", User: "alice", Password: "hunter2"},
+			{Server: "example.org", Password: "s3cret"},
+		}, nil
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.ImportKeychain(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "web", "dry-run": "true"})))
+		assert.Contains(t, buf.String(), "web/github.com/alice")
+		assert.False(t, act.Store.Exists(ctx, "web/github.com/alice"))
+	})
+
+	t.Run("import", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.ImportKeychain(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "web"})))
+
+		sec, err := act.Store.Get(ctx, "web/github.com/alice")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", sec.Password())
+		user, _ := sec.Get("username")
+		assert.Equal(t, "alice", user)
+		url, _ := sec.Get("url")
+		assert.Equal(t, "https://github.com", url)
+
+		assert.True(t, act.Store.Exists(ctx, "web/example.org/unnamed"))
+	})
+
+	t.Run("existing entries are skipped", func(t *testing.T) {
+		defer buf.Reset()
+
+		keychainList = func(context.Context) ([]keychain.Credential, error) {
+			return []keychain.Credential{{Server: "github.com", User: "alice", Password: "changed"}}, nil
+		}
+
+		require.NoError(t, act.ImportKeychain(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "web"})))
+		sec, err := act.Store.Get(ctx, "web/github.com/alice")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", sec.Password())
+
+		require.NoError(t, act.ImportKeychain(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "web", "force": "true"})))
+		sec, err = act.Store.Get(ctx, "web/github.com/alice")
+		require.NoError(t, err)
+		assert.Equal(t, "changed", sec.Password())
+	})
+}
@@ -0,0 +1,101 @@
+package action
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/editor"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	infoExample = `Short description of this folder
+owner:
+contact:
+rotation:
+
+# Anything below the key-value pairs is free-form text.
+`
+)
+
+// InfoPrint will print the folder note of the given folder. If the folder
+// has no note of its own the nearest note of a parent folder is shown.
+func (s *Action) InfoPrint(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+
+	dir, sec, err := s.Store.LookupInfo(ctx, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return exit.Error(exit.NotFound, err, "no folder note found for %q. Use '%s info edit %s' to create one", name, s.Name, name)
+		}
+
+		return exit.Error(exit.Decrypt, err, "failed to read folder note for %q: %s", name, err)
+	}
+
+	if ctxutil.IsTerminal(ctx) {
+		header := fmt.Sprintf("Folder: %s", dir)
+		if dir != name {
+			header += " (inherited)"
+		}
+		out.Print(ctx, header)
+	}
+
+	out.Print(ctx, string(sec.Bytes()))
+
+	return nil
+}
+
+// InfoEdit will load an existing or new folder note into an editor.
+func (s *Action) InfoEdit(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+
+	content := []byte(infoExample)
+	if s.Store.HasInfo(ctx, name) {
+		sec, err := s.Store.GetInfo(ctxutil.WithShowParsing(ctx, false), name)
+		if err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to read folder note for %q: %s", name, err)
+		}
+		content = sec.Bytes()
+	}
+
+	ed := editor.Path(c)
+	nContent, err := editor.Invoke(ctx, ed, content)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to invoke editor %s: %s", ed, err)
+	}
+
+	// If content is equal, nothing changed, exiting.
+	if bytes.Equal(content, nContent) {
+		return nil
+	}
+
+	if err := s.Store.SetInfo(ctxutil.WithCommitMessage(ctx, "Edited folder note"), name, secrets.ParseAKV(nContent)); err != nil {
+		return exit.Error(exit.Encrypt, err, "failed to write folder note for %q: %s", name, err)
+	}
+
+	return nil
+}
+
+// InfoRemove will remove the folder note of the given folder.
+func (s *Action) InfoRemove(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+
+	if !s.Store.HasInfo(ctx, name) {
+		return exit.Error(exit.NotFound, nil, "no folder note found for %q", name)
+	}
+
+	if err := s.Store.RemoveInfo(ctx, name); err != nil {
+		return exit.Error(exit.IO, err, "failed to remove folder note for %q: %s", name, err)
+	}
+
+	return nil
+}
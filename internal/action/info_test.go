@@ -0,0 +1,70 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	t.Run("no folder note", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.InfoPrint(gptest.CliCtx(ctx, t, "team")))
+	})
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("Team folder")
+	require.NoError(t, sec.Set("owner", "ops"))
+	require.NoError(t, act.Store.SetInfo(ctx, "team", sec))
+	require.NoError(t, act.Store.Set(ctx, "team/db", secrets.New()))
+
+	t.Run("print inherited folder note", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.InfoPrint(gptest.CliCtx(ctx, t, "team/db")))
+		assert.Contains(t, buf.String(), "Team folder")
+		assert.Contains(t, buf.String(), "owner: ops")
+	})
+
+	t.Run("folder note is hidden from listings", func(t *testing.T) {
+		l, err := act.Store.List(ctx, tree.INF)
+		require.NoError(t, err)
+		assert.NotContains(t, l, "team/.info")
+		assert.Contains(t, l, "team/db")
+	})
+
+	t.Run("remove folder note", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.InfoRemove(gptest.CliCtx(ctx, t, "team")))
+		assert.Error(t, act.InfoRemove(gptest.CliCtx(ctx, t, "team")))
+	})
+}
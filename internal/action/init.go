@@ -6,6 +6,7 @@ import (
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/blueprint"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/cui"
 	"github.com/gopasspw/gopass/internal/out"
@@ -36,7 +37,13 @@ func (s *Action) IsInitialized(c *cli.Context) error {
 
 	if inited {
 		debug.Log("Store is fully initialized and ready to go\n\nAll systems go. 🚀\n")
+		if err := s.initDetectPass(ctx); err != nil {
+			return err
+		}
 		s.printReminder(ctx)
+		if c.Command.Name != "lock" {
+			s.autoLock(ctx)
+		}
 		if c.Command.Name != "sync" && !c.Bool("nosync") {
 			_ = s.autoSync(ctx)
 		}
@@ -83,10 +90,51 @@ func (s *Action) Init(c *cli.Context) error {
 		out.Errorf(ctx, "Store is already initialized!")
 	}
 
-	if err := s.init(ctx, alias, path, c.Args().Slice()...); err != nil {
+	keys := c.Args().Slice()
+
+	var bp *blueprint.Blueprint
+	if fn := c.String("from-blueprint"); fn != "" {
+		bp, err = readBlueprint(fn)
+		if err != nil {
+			return exit.Error(exit.IO, err, "Failed to read blueprint: %s", err)
+		}
+
+		if len(keys) < 1 {
+			keys = bp.Recipients
+		}
+	}
+
+	if err := s.init(ctx, alias, path, keys...); err != nil {
 		return exit.Error(exit.Unknown, err, "Failed to initialize store: %s", err)
 	}
 
+	if bp != nil {
+		if err := s.initBlueprint(ctx, bp, alias); err != nil {
+			return exit.Error(exit.Unknown, err, "Failed to apply blueprint %s: %s", bp.Name, err)
+		}
+	}
+
+	if c.Bool("air-gapped") {
+		return s.initAirGapped(ctx, alias)
+	}
+
+	return nil
+}
+
+// initAirGapped marks the store as air-gapped, i.e. it is only synced with
+// bundles on removable media.
+func (s *Action) initAirGapped(ctx context.Context, alias string) error {
+	mp := alias
+	if mp == "" {
+		mp = "<root>"
+	}
+
+	if err := s.cfg.Set(mp, airGappedKey, "true"); err != nil {
+		return exit.Error(exit.Config, err, "Failed to mark store as air-gapped: %s", err)
+	}
+
+	out.Noticef(ctx, "The store is air-gapped. Sync it with 'gopass bundle export' and 'gopass bundle import'")
+
 	return nil
 }
 
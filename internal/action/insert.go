@@ -6,23 +6,29 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/audit"
 	"github.com/gopasspw/gopass/internal/editor"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
 )
 
 // Insert a string as content to a secret file.
 func (s *Action) Insert(c *cli.Context) error {
-	ctx := ctxutil.WithGlobalFlags(c)
+	// appending and setting keys write back what was read before.
+	ctx := leaf.WithConflictCheck(root.WithKeyIndex(ctxutil.WithGlobalFlags(c), true), true)
 	echo := c.Bool("echo")
 	multiline := c.Bool("multiline")
 	force := c.Bool("force")
@@ -36,7 +42,28 @@ func (s *Action) Insert(c *cli.Context) error {
 		return exit.Error(exit.NoName, nil, "Usage: %s insert name", s.Name)
 	}
 
-	return s.insert(ctx, c, name, key, echo, multiline, force, appending, kvps)
+	if err := validateMetadata(kvps); err != nil {
+		return err
+	}
+
+	name, err := s.normalizeName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	existed := s.Store.Exists(ctx, name)
+	if err := s.insert(ctx, c, name, key, echo, multiline, force, appending, kvps); err != nil {
+		return err
+	}
+
+	switch {
+	case !existed:
+		s.publishEntries(ctx, event.EntryCreated, name)
+	case key == "" && !appending:
+		s.publishEntries(ctx, event.EntryRotated, name)
+	}
+
+	return nil
 }
 
 func (s *Action) insert(ctx context.Context, c *cli.Context, name, key string, echo, multiline, force, appending bool, kvps map[string]string) error {
@@ -55,7 +82,7 @@ func (s *Action) insert(ctx context.Context, c *cli.Context, name, key string, e
 
 	// update to a single YAML entry.
 	if key != "" {
-		return s.insertYAML(ctx, name, key, content, kvps)
+		return s.insertYAML(ctx, name, key, content, appending, kvps)
 	}
 
 	if ctxutil.IsStdin(ctx) {
@@ -183,8 +210,9 @@ func (s *Action) insertGetSecret(ctx context.Context, name, pw string) (gopass.S
 	return sec, nil
 }
 
-// insertYAML will overwrite existing keys.
-func (s *Action) insertYAML(ctx context.Context, name, key string, content []byte, kvps map[string]string) error {
+// insertYAML will overwrite existing keys. With appending the value is added
+// to the existing values of the key instead.
+func (s *Action) insertYAML(ctx context.Context, name, key string, content []byte, appending bool, kvps map[string]string) error {
 	debug.Log("insertYAML: %s - %s -> %s", name, key, content)
 	if ctxutil.IsInteractive(ctx) {
 		pw, err := termio.AskForString(ctx, name+":"+key, "")
@@ -207,14 +235,34 @@ func (s *Action) insertYAML(ctx context.Context, name, key string, content []byt
 		debug.Log("creating new secret %s", name)
 	}
 
+	if err := secrets.ValidateKey(key, strings.TrimRight(string(content), "\r\n")); err != nil {
+		return exit.Error(exit.Usage, err, "%s", err)
+	}
+
 	setMetadata(sec, kvps)
 
-	debug.Log("setting %s to %s", key, string(content))
-	if err := sec.Set(key, string(content)); err != nil {
-		return exit.Error(exit.Usage, err, "failed set key %q of %q: %q", key, name, err)
+	msg := "Inserted YAML value from STDIN"
+	if appending {
+		value := strings.TrimRight(string(content), "\r\n")
+		if values, _ := sec.Values(key); slices.Contains(values, value) {
+			out.Noticef(ctx, "%s already contains this value for %s", name, key)
+
+			return nil
+		}
+
+		debug.Log("adding %s to %s", string(content), key)
+		if err := sec.Add(key, value); err != nil {
+			return exit.Error(exit.Usage, err, "failed to add to key %q of %q: %q", key, name, err)
+		}
+		msg = "Appended YAML value from STDIN"
+	} else {
+		debug.Log("setting %s to %s", key, string(content))
+		if err := sec.Set(key, string(content)); err != nil {
+			return exit.Error(exit.Usage, err, "failed set key %q of %q: %q", key, name, err)
+		}
 	}
 
-	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Inserted YAML value from STDIN"), name, sec); err != nil {
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, msg), name, sec); err != nil {
 		if !errors.Is(err, store.ErrMeaninglessWrite) {
 			return exit.Error(exit.Encrypt, err, "failed to set key %q of %q: %s", key, name, err)
 		}
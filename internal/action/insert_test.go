@@ -85,7 +85,7 @@ func TestInsert(t *testing.T) {
 	t.Run("insert zab#key", func(t *testing.T) {
 		ctx = ctxutil.WithInteractive(ctx, false)
 		require.NoError(t, act.cfg.Set("", "core.showsafecontent", "true"))
-		assert.NoError(t, act.insertYAML(ctx, "zabkey", "key", []byte("foobar"), nil))
+		assert.NoError(t, act.insertYAML(ctx, "zabkey", "key", []byte("foobar"), false, nil))
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "zabkey", false))
 		assert.Contains(t, buf.String(), "key: foobar")
 		buf.Reset()
@@ -103,6 +103,15 @@ func TestInsert(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("insert invalid well-known keys", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Insert(gptest.CliCtxWithFlags(ctx, t, nil, "keyvaltest", "email:not-an-address")))
+		assert.Error(t, act.Insert(gptest.CliCtxWithFlags(ctx, t, nil, "keyvaltest", "gopass.internal:foo")))
+		assert.Error(t, act.insertYAML(ctx, "keyvaltest", "expires", []byte("next week\n"), false, nil))
+		assert.NoError(t, act.insertYAML(ctx, "keyvaltest", "expires", []byte("2030-01-31\n"), false, nil))
+		assert.NoError(t, act.insertYAML(ctx, "keyvaltest", "acme.expires", []byte("next week\n"), false, nil))
+	})
+
 	t.Run("insert baz via stdin w/ yaml and input parsing and safecontent", func(t *testing.T) {
 		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: 0123"), false))
 		buf.Reset()
@@ -177,3 +186,45 @@ func TestInsertStdin(t *testing.T) {
 	ibuf.Reset()
 	buf.Reset()
 }
+
+func TestInsertAppendKey(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	require.NoError(t, act.insertYAML(ctx, "acme", "recovery", []byte("a@example.org"), false, nil))
+	require.NoError(t, act.insertYAML(ctx, "acme", "recovery", []byte("b@example.org\n"), true, nil))
+	// the same value is not added twice.
+	require.NoError(t, act.insertYAML(ctx, "acme", "recovery", []byte("b@example.org"), true, nil))
+
+	sec, err := act.Store.Get(ctx, "acme")
+	require.NoError(t, err)
+	values, _ := sec.Values("recovery")
+	assert.Equal(t, []string{"a@example.org", "b@example.org"}, values)
+	buf.Reset()
+
+	require.NoError(t, act.Show(gptest.CliCtx(ctx, t, "acme", "recovery")))
+	assert.Equal(t, "a@example.org\nb@example.org", buf.String())
+	buf.Reset()
+
+	require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true"}, "acme")))
+	assert.Contains(t, buf.String(), `"recovery": [
+        "a@example.org",
+        "b@example.org"
+      ]`)
+}
@@ -0,0 +1,237 @@
+package action
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/sessionlog"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// redacted replaces arguments in the journal.
+const redacted = "*****"
+
+// FlushJournal records the changes made by the current command in the
+// journal. It is queued behind the pending commits, so the recorded commits
+// are the ones created by this command.
+func (s *Action) FlushJournal(c *cli.Context) error {
+	ctx := c.Context
+	if s.cfg.IsSet("core.journal") && !s.cfg.GetBool("core.journal") {
+		return nil
+	}
+
+	var cmds []*cli.Command
+	if c.App != nil {
+		cmds = c.App.Commands
+	}
+	command := journalCommand(cmds, os.Args[1:])
+
+	// all changes of a store are committed together, so looking up the
+	// current commit once per mount is enough.
+	heads := make(map[string]string)
+	head := func(ctx context.Context, name string) string {
+		mp := s.Store.MountPoint(name)
+		if h, found := heads[mp]; found {
+			return h
+		}
+
+		h := s.Store.HeadCommit(ctx, name)
+		heads[mp] = h
+
+		return h
+	}
+
+	t := queue.GetQueue(ctx).Add(func(ctx context.Context) (context.Context, error) {
+		if err := sessionlog.Flush(ctx, command, head); err != nil {
+			debug.Log("failed to write journal: %s", err)
+		}
+
+		return ctx, nil
+	})
+
+	_, err := t(ctx)
+
+	return err
+}
+
+// journalCommand returns the command line recorded in the journal. Only the
+// names of the (sub)commands and flags are kept. Arguments and flag values
+// may contain secrets, e.g. config values, and are replaced.
+func journalCommand(cmds []*cli.Command, args []string) []string {
+	res := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			name, _, _ := strings.Cut(arg, "=")
+			res = append(res, name)
+
+			continue
+		}
+
+		if cmd := findCommand(cmds, arg); cmd != nil {
+			res = append(res, arg)
+			cmds = cmd.Subcommands
+
+			continue
+		}
+
+		// no more subcommands after the first argument.
+		cmds = nil
+		res = append(res, redacted)
+	}
+
+	return res
+}
+
+func findCommand(cmds []*cli.Command, name string) *cli.Command {
+	for _, cmd := range cmds {
+		if cmd.HasName(name) {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// JournalShow prints the journal of mutating commands.
+func (s *Action) JournalShow(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	recs, err := s.journalRecords(c)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range recs {
+		out.Printf(ctx, "%s - %s", r.Time.Local().Format(time.RFC3339), strings.Join(r.Command, " "))
+		for _, ch := range r.Changes {
+			line := fmt.Sprintf("  %-6s %s", ch.Op, ch.Entry)
+			if ch.From != "" {
+				line += " (from " + ch.From + ")"
+			}
+			if ch.Commit != "" {
+				line += " @ " + shortHash(ch.Commit)
+			}
+			out.Print(ctx, line)
+		}
+	}
+
+	return nil
+}
+
+// JournalExport writes the journal in a machine readable format.
+func (s *Action) JournalExport(c *cli.Context) error {
+	recs, err := s.journalRecords(c)
+	if err != nil {
+		return err
+	}
+
+	switch format := c.String("format"); format {
+	case "json", "":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		if recs == nil {
+			recs = []sessionlog.Record{}
+		}
+
+		if err := enc.Encode(recs); err != nil {
+			return exit.Error(exit.IO, err, "failed to encode journal: %s", err)
+		}
+	case "csv":
+		w := csv.NewWriter(stdout)
+		_ = w.Write([]string{"time", "command", "op", "entry", "from", "commit"})
+
+		for _, r := range recs {
+			for _, ch := range r.Changes {
+				_ = w.Write([]string{r.Time.Format(time.RFC3339), strings.Join(r.Command, " "), ch.Op, ch.Entry, ch.From, ch.Commit})
+			}
+		}
+
+		w.Flush()
+
+		if err := w.Error(); err != nil {
+			return exit.Error(exit.IO, err, "failed to write journal: %s", err)
+		}
+	default:
+		return exit.Error(exit.Usage, nil, "unknown format %q. Use json or csv", format)
+	}
+
+	return nil
+}
+
+// journalRecords returns the records matching the --since and --store flags.
+func (s *Action) journalRecords(c *cli.Context) ([]sessionlog.Record, error) {
+	since, err := parseSince(c.String("since"), time.Now())
+	if err != nil {
+		return nil, exit.Error(exit.Usage, err, "invalid --since %q: %s", c.String("since"), err)
+	}
+
+	recs, err := sessionlog.Read(since)
+	if err != nil {
+		return nil, exit.Error(exit.IO, err, "failed to read journal: %s", err)
+	}
+
+	prefix := c.String("store")
+	if prefix == "" {
+		return recs, nil
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	filtered := make([]sessionlog.Record, 0, len(recs))
+
+	for _, r := range recs {
+		changes := make([]sessionlog.Change, 0, len(r.Changes))
+		for _, ch := range r.Changes {
+			if strings.HasPrefix(ch.Entry, prefix) || strings.HasPrefix(ch.From, prefix) {
+				changes = append(changes, ch)
+			}
+		}
+
+		if len(changes) < 1 {
+			continue
+		}
+
+		r.Changes = changes
+		filtered = append(filtered, r)
+	}
+
+	return filtered, nil
+}
+
+// parseSince accepts either a duration, e.g. 24h, or a date, e.g. 2006-01-02.
+// An empty string selects the whole journal.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("neither a duration nor a date (YYYY-MM-DD)")
+	}
+
+	return t, nil
+}
+
+func shortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+
+	return h
+}
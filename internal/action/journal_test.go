@@ -0,0 +1,135 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/sessionlog"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestJournal(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	ctx := context.Background()
+
+	// discard the changes recorded by other tests.
+	t.Setenv("GOPASS_STATE_DIR", t.TempDir())
+	require.NoError(t, sessionlog.Flush(ctx, nil, nil))
+	t.Setenv("GOPASS_STATE_DIR", t.TempDir())
+
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	sessionlog.Add(sessionlog.OpSet, "foo", "")
+	sessionlog.Add(sessionlog.OpMove, "work/bar", "bar")
+	require.NoError(t, act.FlushJournal(gptest.CliCtx(ctx, t)))
+
+	t.Run("show", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.JournalShow(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "set    foo")
+		assert.Contains(t, buf.String(), "move   work/bar (from bar)")
+	})
+
+	t.Run("show store", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.JournalShow(gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": "work"})))
+		assert.NotContains(t, buf.String(), "set    foo")
+		assert.Contains(t, buf.String(), "work/bar")
+	})
+
+	t.Run("export json", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.JournalExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"since": "1h"})))
+
+		var recs []sessionlog.Record
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &recs))
+		require.Len(t, recs, 1)
+		assert.Len(t, recs[0].Changes, 2)
+	})
+
+	t.Run("export csv", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.JournalExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "csv"})))
+		assert.Contains(t, buf.String(), "time,command,op,entry,from,commit\n")
+		assert.Contains(t, buf.String(), ",move,work/bar,bar,")
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		assert.Error(t, act.JournalShow(gptest.CliCtxWithFlags(ctx, t, map[string]string{"since": "yesterday"})))
+	})
+}
+
+func TestJournalCommand(t *testing.T) {
+	t.Parallel()
+
+	cmds := []*cli.Command{
+		{Name: "config"},
+		{Name: "generate", Aliases: []string{"gen"}},
+		{Name: "recipients", Subcommands: []*cli.Command{{Name: "add"}}},
+	}
+
+	for _, tc := range []struct {
+		in   []string
+		want []string
+	}{
+		{
+			in:   []string{"config", "webhook.secret", "s3cret"},
+			want: []string{"config", redacted, redacted},
+		},
+		{
+			in:   []string{"gen", "--symbols=true", "-n", "config", "24"},
+			want: []string{"gen", "--symbols", "-n", redacted, redacted},
+		},
+		{
+			in:   []string{"recipients", "add", "0xDEADBEEF"},
+			want: []string{"recipients", "add", redacted},
+		},
+	} {
+		assert.Equal(t, tc.want, journalCommand(cmds, tc.in), tc.in)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	ts, err := parseSince("", now)
+	require.NoError(t, err)
+	assert.True(t, ts.IsZero())
+
+	ts, err = parseSince("24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), ts)
+
+	ts, err = parseSince("2023-01-30", now)
+	require.NoError(t, err)
+	assert.Equal(t, 30, ts.Day())
+
+	_, err = parseSince("soon", now)
+	assert.Error(t, err)
+}
@@ -12,9 +12,11 @@ import (
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/demo"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
 	shellquote "github.com/kballard/go-shellquote"
 	"github.com/noborus/ov/oviewer"
 	"github.com/urfave/cli/v2"
@@ -32,7 +34,7 @@ func (s *Action) List(c *cli.Context) error {
 
 	// print the path if the argument is a direct hit.
 	if s.Store.Exists(ctx, filter) && !s.Store.IsDir(ctx, filter) {
-		fmt.Println(filter)
+		fmt.Println(demoName(ctx, filter))
 
 		return nil
 	}
@@ -42,11 +44,42 @@ func (s *Action) List(c *cli.Context) error {
 		flat = true
 	}
 
-	l, err := s.Store.Tree(ctx)
+	var l *tree.Root
+	var err error
+	if tags := c.StringSlice("tag"); len(tags) > 0 {
+		l, err = s.Store.TaggedTree(ctx, tags...)
+	} else {
+		l, err = s.Store.Tree(ctx)
+	}
 	if err != nil {
 		return exit.Error(exit.List, err, "failed to list store: %s", err)
 	}
 
+	icons := c.String("icons")
+	if icons == "" {
+		icons = config.String(ctx, "list.icons")
+	}
+	demoMode := ctxutil.IsDemo(ctx)
+	if demoMode {
+		l = demoTree(l)
+		filter = demo.Name(filter)
+	} else {
+		s.redactTree(ctx, l)
+		s.markLocal(l)
+	}
+
+	switch icons {
+	case "", "none", "false":
+	case "emoji", "true", "ascii":
+		// the flat lists are meant for scripts, so they never show icons.
+		// The icons of the demo tree would point to the real entries.
+		if !flat && !demoMode {
+			s.setIcons(ctx, l, icons == "ascii")
+		}
+	default:
+		return exit.Error(exit.Usage, nil, "unknown icon mode %q. Use emoji, ascii or none", icons)
+	}
+
 	// set limit to infinite by default unless it's set with the flag
 	limit := tree.INF
 	if c.IsSet("limit") {
@@ -56,6 +89,25 @@ func (s *Action) List(c *cli.Context) error {
 	return s.listFiltered(ctx, l, limit, flat, folders, stripPrefix, filter)
 }
 
+// setIcons adds the icons of all entries and folders to the tree.
+func (s *Action) setIcons(ctx context.Context, l *tree.Root, ascii bool) {
+	for p, icon := range s.Store.Icons(ctx, l) {
+		if err := l.SetIcon(p, tree.RenderIcon(icon, ascii)); err != nil {
+			debug.Log("failed to set icon of %s: %s", p, err)
+		}
+	}
+}
+
+// redactTree marks the folders we can't decrypt and collapses the ones that
+// should only show the number of entries.
+func (s *Action) redactTree(ctx context.Context, l *tree.Root) {
+	for p, mode := range s.Store.Redactions(ctx) {
+		if err := l.Redact(p, mode == leaf.RedactCount); err != nil {
+			debug.Log("failed to redact %s: %s", p, err)
+		}
+	}
+}
+
 func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat, folders, stripPrefix bool, filter string) error {
 	sep := leaf.Sep
 
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
@@ -213,6 +217,122 @@ foo2/bar2
 	})
 }
 
+func TestListIcons(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("123")
+	require.NoError(t, sec.Set("icon", "bank"))
+	require.NoError(t, act.Store.Set(ctx, "bank/checking", sec))
+
+	note := secrets.NewAKV()
+	require.NoError(t, note.Set("icon", "🔑"))
+	require.NoError(t, act.Store.SetInfo(ctx, "bank", note))
+
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"icons": "emoji"})))
+	want := `gopass
+├── 🔑 bank/
+│   └── 🏦 checking
+└── foo
+
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	require.NoError(t, act.cfg.SetEnv("list.icons", "ascii"))
+	assert.NoError(t, act.List(gptest.CliCtx(ctx, t, "bank")))
+	want = `bank/
+└── [bank] checking
+
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"flat": "true"})))
+	want = `bank/checking
+foo
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	assert.Error(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"icons": "sparkles"})))
+}
+
+func TestListRedacted(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("123")
+	for _, name := range []string{"ops/db", "ops/ssh", "team/wifi"} {
+		require.NoError(t, act.Store.Set(ctx, name, sec))
+	}
+
+	// we are not a recipient of these folders.
+	for _, dir := range []string{"ops", "team"} {
+		require.NoError(t, os.WriteFile(filepath.Join(u.StoreDir(""), dir, plain.IDFile), []byte("0xBADC0FFEE\n"), 0o600))
+	}
+	require.NoError(t, act.Store.SetFolderRedaction(ctx, "ops", leaf.RedactCount))
+	require.NoError(t, act.Store.SetFolderRedaction(ctx, "team", leaf.RedactNames))
+
+	assert.NoError(t, act.List(gptest.CliCtx(ctx, t)))
+	want := `gopass
+├── foo
+├── ops/ (2 entries, no access)
+└── team/ (no access)
+    └── wifi
+
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"flat": "true"})))
+	want = `foo
+team/wifi
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	// demo mode doesn't show real entries anyway.
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"demo": "true", "flat": "true"})))
+	assert.Len(t, strings.Fields(buf.String()), 4)
+}
+
 func TestRedirectPager(t *testing.T) {
 	ctx := context.Background()
 
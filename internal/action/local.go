@@ -0,0 +1,58 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// Local marks an entry or folder as local-only or lists the local-only
+// entries.
+func (s *Action) Local(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+
+	if name == "" {
+		if c.Bool("unset") {
+			return exit.Error(exit.Usage, nil, "Usage: %s local [--unset] <entry|folder/>", s.Name)
+		}
+
+		for _, l := range s.Store.LocalOnly() {
+			fmt.Fprintln(stdout, l)
+		}
+
+		return nil
+	}
+
+	if c.Bool("unset") {
+		if err := s.Store.SetLocalOnly(ctx, name, false); err != nil {
+			return exit.Error(exit.Unknown, err, "failed to add %s to the repository: %s", name, err)
+		}
+
+		out.OKf(ctx, "%s is synced again", name)
+
+		return nil
+	}
+
+	if err := s.Store.SetLocalOnly(ctx, name, true); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to make %s local-only: %s", name, err)
+	}
+
+	out.OKf(ctx, "%s is local-only. It's kept on this device and never committed or pushed", name)
+
+	return nil
+}
+
+// markLocal marks the local-only entries and folders in the tree.
+func (s *Action) markLocal(l *tree.Root) {
+	for _, p := range s.Store.LocalOnly() {
+		if err := l.SetLocal(p); err != nil {
+			debug.Log("failed to mark %s as local-only: %s", p, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("123")
+	require.NoError(t, act.Store.Set(ctx, "device/vpn", sec))
+
+	assert.Error(t, act.Local(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unset": "true"})))
+	require.NoError(t, act.Local(gptest.CliCtx(ctx, t, "device/")))
+	require.NoError(t, act.Local(gptest.CliCtx(ctx, t, "foo")))
+	buf.Reset()
+
+	t.Run("entries leave the repository", func(t *testing.T) {
+		assert.NoFileExists(t, filepath.Join(u.StoreDir(""), "device", "vpn."+plain.Ext))
+		assert.NoFileExists(t, filepath.Join(u.StoreDir(""), "foo."+plain.Ext))
+		assert.FileExists(t, filepath.Join(local.Dir("", u.StoreDir("")), "device", "vpn."+plain.Ext))
+		assert.True(t, act.Store.IsLocalOnly(ctx, "foo"))
+
+		got, err := act.Store.Get(ctx, "device/vpn")
+		require.NoError(t, err)
+		assert.Equal(t, "123", got.Password())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Local(gptest.CliCtx(ctx, t)))
+		assert.Equal(t, "device/\nfoo\n", buf.String())
+		buf.Reset()
+
+		require.NoError(t, act.List(gptest.CliCtx(ctx, t)))
+		assert.Equal(t, `gopass
+├── device/ (local)
+│   └── vpn
+└── foo (local)
+
+`, buf.String())
+	})
+
+	t.Run("new entries in local-only folders", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Store.Set(ctx, "device/ssh", sec))
+		assert.NoFileExists(t, filepath.Join(u.StoreDir(""), "device", "ssh."+plain.Ext))
+		assert.FileExists(t, filepath.Join(local.Dir("", u.StoreDir("")), "device", "ssh."+plain.Ext))
+	})
+
+	t.Run("chunks stay out of the repository", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.SetEnv("storage.chunk-size", "1"))
+		defer func() {
+			require.NoError(t, act.cfg.SetEnv("storage.chunk-size", "0"))
+		}()
+
+		large := secrets.ParseAKV([]byte("cert\n" + strings.Repeat("0123456789abcdef", 256)))
+		require.NoError(t, act.Store.Set(ctx, "big", large))
+		require.NoError(t, act.Store.Set(ctx, "device/cert", large))
+		assert.DirExists(t, filepath.Join(u.StoreDir(""), ".gopass-chunks"))
+
+		require.NoError(t, act.Local(gptest.CliCtx(ctx, t, "big")))
+		chunks, err := filepath.Glob(filepath.Join(u.StoreDir(""), ".gopass-chunks", "*", "*"))
+		require.NoError(t, err)
+		assert.Empty(t, chunks)
+
+		for _, name := range []string{"big", "device/cert"} {
+			got, err := act.Store.Get(ctx, name)
+			require.NoError(t, err)
+			assert.Equal(t, string(large.Bytes()), string(got.Bytes()), name)
+		}
+
+		require.NoError(t, act.Local(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unset": "true"}, "big")))
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Local(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unset": "true"}, "device/")))
+		require.NoError(t, act.Local(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unset": "true"}, "foo")))
+		assert.FileExists(t, filepath.Join(u.StoreDir(""), "device", "vpn."+plain.Ext))
+		assert.FileExists(t, filepath.Join(u.StoreDir(""), "foo."+plain.Ext))
+		assert.False(t, local.Enabled(local.Dir("", u.StoreDir(""))))
+		assert.Empty(t, act.Store.LocalOnly())
+	})
+}
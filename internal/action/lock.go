@@ -0,0 +1,157 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/autolock"
+	"github.com/gopasspw/gopass/internal/canary"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// Lock wipes the passphrases and keys cached by the crypto backends of all
+// mounted stores.
+func (s *Action) Lock(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if c.Bool("status") {
+		return s.lockStatus(ctx)
+	}
+
+	if c.Bool("watch") {
+		return s.lockWatch(ctx)
+	}
+
+	if err := s.lock(ctx); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to lock: %s", err)
+	}
+
+	out.OKf(ctx, "Locked. Cached passphrases and keys were wiped")
+
+	return nil
+}
+
+// Unlock asks for the passphrases of all mounted stores now, so the
+// following commands don't have to.
+func (s *Action) Unlock(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	entries, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	// one entry per mount is enough to load the keys.
+	seen := make(map[string]bool, len(entries))
+	for _, name := range entries {
+		mp := s.Store.MountPoint(name)
+		if seen[mp] {
+			continue
+		}
+		seen[mp] = true
+
+		// unlocking is not reading the entry.
+		if _, err := s.Store.Get(canary.WithSuppressed(ctx, true), name); err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to unlock %s: %s", name, err)
+		}
+	}
+
+	if err := autolock.Touch(); err != nil {
+		debug.Log("failed to record unlock: %s", err)
+	}
+
+	out.OKf(ctx, "Unlocked %d stores", len(seen))
+
+	return nil
+}
+
+// lock wipes the caches of the crypto backends and records the lock.
+func (s *Action) lock(ctx context.Context) error {
+	if err := s.Store.Lock(ctx); err != nil {
+		return err
+	}
+
+	return autolock.MarkLocked()
+}
+
+// autoLock wipes the caches if gopass was idle for longer than core.autolock
+// and records the current use.
+func (s *Action) autoLock(ctx context.Context) {
+	st, err := autolock.Load()
+	if err != nil {
+		debug.Log("failed to load lock state: %s", err)
+
+		return
+	}
+
+	if autolock.Due(ctx, st) {
+		if err := s.lock(ctx); err != nil {
+			out.Warningf(ctx, "Failed to lock after %s of inactivity: %s", autolock.Timeout(ctx), err)
+		} else {
+			out.Noticef(ctx, "Locked after %s of inactivity", autolock.Timeout(ctx))
+		}
+	}
+
+	if err := autolock.Touch(); err != nil {
+		debug.Log("failed to record use: %s", err)
+	}
+}
+
+func (s *Action) lockStatus(ctx context.Context) error {
+	st, err := autolock.Load()
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to load lock state: %s", err)
+	}
+
+	state := "unlocked"
+	if st.IsLocked() {
+		state = "locked since " + st.Locked.Format(time.RFC3339)
+	}
+
+	lastUsed := "never"
+	if !st.LastUsed.IsZero() {
+		lastUsed = fmt.Sprintf("%s (%s ago)", st.LastUsed.Format(time.RFC3339), time.Since(st.LastUsed).Round(time.Second))
+	}
+
+	autoLock := "disabled"
+	if to := autolock.Timeout(ctx); to > 0 {
+		autoLock = "after " + to.String() + " of inactivity"
+	}
+
+	fmt.Fprintf(stdout, "State:     %s\n", state)
+	fmt.Fprintf(stdout, "Last used: %s\n", lastUsed)
+	fmt.Fprintf(stdout, "Auto-lock: %s\n", autoLock)
+
+	return nil
+}
+
+// lockWatch locks whenever the screen locks or gopass is idle for too long
+// until it's interrupted.
+func (s *Action) lockWatch(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	out.Printf(ctx, "Watching the screen lock. Press Ctrl+C to stop")
+
+	err := autolock.Watch(ctx, func(reason string) {
+		if err := s.lock(ctx); err != nil {
+			out.Errorf(ctx, "Failed to lock: %s", err)
+
+			return
+		}
+		out.OKf(ctx, "Locked because %s", reason)
+	})
+	if err != nil {
+		return exit.Error(exit.Unsupported, err, "failed to watch the screen lock: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/autolock"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	t.Run("lock", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Lock(gptest.CliCtx(ctx, t)))
+		st, err := autolock.Load()
+		require.NoError(t, err)
+		assert.True(t, st.IsLocked())
+	})
+
+	t.Run("status", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.Set("", "core.autolock", "15"))
+		require.NoError(t, act.Lock(gptest.CliCtxWithFlags(ctx, t, map[string]string{"status": "true"})))
+		assert.Contains(t, buf.String(), "State:     locked since")
+		assert.Contains(t, buf.String(), "Auto-lock: after 15m0s of inactivity")
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Unlock(gptest.CliCtx(ctx, t)))
+		st, err := autolock.Load()
+		require.NoError(t, err)
+		assert.False(t, st.IsLocked())
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
@@ -103,7 +104,7 @@ func (s *Action) Merge(c *cli.Context) error {
 			continue
 		}
 		debug.Log("deleting merged entry %s", old)
-		if err := s.Store.Delete(ctx, old); err != nil {
+		if err := s.Store.Delete(leaf.WithNoDeleteEvent(ctx, true), old); err != nil {
 			return exit.Error(exit.Unknown, err, "failed to delete %s: %s", old, err)
 		}
 	}
@@ -0,0 +1,308 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/mobile"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/gopasspw/gopass/pkg/qrcon"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// newDeployKey creates the SSH key pair of the device. It's replaced in
+// tests.
+var newDeployKey = mobile.NewDeployKey
+
+// privateKeyExporter is implemented by crypto backends that can export the
+// secret part of an identity and remove it from the keyring, i.e. gpg.
+type privateKeyExporter interface {
+	ExportPrivateKey(ctx context.Context, id string) ([]byte, error)
+	DeletePrivateKey(ctx context.Context, id string) error
+}
+
+// gitConfigGetter is implemented by the git storage backends.
+type gitConfigGetter interface {
+	ConfigGet(ctx context.Context, key string) (string, error)
+}
+
+// mobileIdentity is the key pair of a mobile device.
+type mobileIdentity struct {
+	recipient string
+	// secret is the private key, armored for gpg.
+	secret []byte
+	// passphrase protects the gpg key. Empty for age.
+	passphrase string
+}
+
+// Mobile provisions everything a pass compatible mobile app needs to sync a
+// store in one guided flow: a dedicated key pair for the device that is only
+// a recipient of the shared folder, a read-only SSH deploy key for the git
+// remote and the transfer of both to the device with QR codes.
+func (s *Action) Mobile(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	device := c.String("name")
+	folder := strings.Trim(c.String("folder"), "/")
+	mount := c.String("store")
+	if folder != "" {
+		mount = s.Store.MountPoint(folder)
+	}
+
+	remoteURL, remote, err := s.mobileRemote(ctx, mount)
+	if err != nil {
+		return err
+	}
+
+	crypto := s.Store.Crypto(ctx, mount)
+	if crypto == nil {
+		return exit.Error(exit.NotFound, nil, "store %q not found", mount)
+	}
+
+	scope := "the whole store"
+	if folder != "" {
+		scope = folder
+	}
+	out.Printf(ctx, "This sets up %q to sync %s from %s:", device, scope, remoteURL)
+	out.Printf(ctx, "  1. Create a %s key and a read-only SSH deploy key for the device", crypto.Name())
+	out.Printf(ctx, "  2. Add the deploy key to the git remote")
+	out.Printf(ctx, "  3. Show both keys as QR codes to import them on the device")
+	out.Printf(ctx, "  4. Add the key of the device as a recipient of %s", scope)
+	if !termio.AskForConfirmation(ctx, "Continue?") {
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	// the secret key of the device must not stay on this machine, even if
+	// the setup fails. The public key stays in the keyring, it's needed to
+	// encrypt for the device.
+	id, removeKey, err := s.newMobileIdentity(ctx, crypto, device)
+	defer removeKey()
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to create a key for %s: %s", device, err)
+	}
+
+	pub, priv, err := newDeployKey(ctx, "gopass-mobile-"+device)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to create the deploy key: %s", err)
+	}
+
+	if err := s.mobileAddDeployKey(ctx, c, remote, device, pub); err != nil {
+		return err
+	}
+
+	// the device only becomes a recipient once it has its keys. Otherwise an
+	// aborted handover would leave a recipient behind nobody has the key of.
+	if err := s.mobileHandover(ctx, c.String("output-dir"), device, mobileCloneURL(remoteURL, remote), id, priv); err != nil {
+		return err
+	}
+
+	if folder != "" {
+		affected, err := s.Store.AddFolderRecipient(ctx, folder, id.recipient)
+		if err != nil {
+			return exit.Error(exit.Recipients, err, "failed to add %s to %s: %s", id.recipient, folder, err)
+		}
+		out.OKf(ctx, "Added the key of %s to %s (%d secrets)", device, folder, len(affected))
+	} else {
+		if err := s.Store.AddRecipient(ctx, mount, id.recipient); err != nil {
+			return exit.Error(exit.Recipients, err, "failed to add recipient %s: %s", id.recipient, err)
+		}
+		out.OKf(ctx, "Added the key of %s to the store", device)
+	}
+
+	out.OKf(ctx, "%s is ready", device)
+
+	return nil
+}
+
+// mobileRemote returns the URL of the git remote of the store.
+func (s *Action) mobileRemote(ctx context.Context, mount string) (string, mobile.Remote, error) {
+	g, ok := local.Unwrap(s.Store.Storage(ctx, mount)).(gitConfigGetter)
+	if !ok {
+		return "", mobile.Remote{}, exit.Error(exit.Unsupported, nil, "the store %q is not synced with git", mount)
+	}
+
+	remoteURL, err := g.ConfigGet(ctx, "remote.origin.url")
+	if err != nil || remoteURL == "" {
+		return "", mobile.Remote{}, exit.Error(exit.Unsupported, err, "the store %q has no git remote. Add one with '%s git remote add origin <url>' first", mount, s.Name)
+	}
+
+	remote, err := mobile.ParseRemote(remoteURL)
+	if err != nil {
+		return "", mobile.Remote{}, exit.Error(exit.Unsupported, err, "%s", err)
+	}
+
+	return remoteURL, remote, nil
+}
+
+// newMobileIdentity creates a new key pair for the device. age identities are
+// created in memory, gpg keys in the keyring of gpg, protected by a generated
+// passphrase. The returned function removes the secret key from the keyring.
+// It must be called even if an error is returned, since the key may already
+// have been created.
+func (s *Action) newMobileIdentity(ctx context.Context, crypto backend.Crypto, device string) (*mobileIdentity, func(), error) {
+	removeKey := func() {}
+
+	switch crypto.Name() {
+	case "age":
+		id, err := age.GenerateX25519Identity()
+		if err != nil {
+			return nil, removeKey, err
+		}
+
+		return &mobileIdentity{
+			recipient: id.Recipient().String(),
+			secret:    []byte(id.String()),
+		}, removeKey, nil
+	case "gpg":
+		exp, ok := crypto.(privateKeyExporter)
+		if !ok {
+			return nil, removeKey, fmt.Errorf("%s can't export private keys", crypto.Name())
+		}
+
+		email := device + "@gopass.mobile"
+		passphrase := pwgen.GenerateMemorablePassword(24, false, true)
+		if err := crypto.GenerateIdentity(ctx, "gopass mobile "+device, email, passphrase); err != nil {
+			return nil, removeKey, err
+		}
+
+		key := email
+		removeKey = func() {
+			if err := exp.DeletePrivateKey(ctx, key); err != nil {
+				out.Errorf(ctx, "Failed to remove the secret key of %s from the keyring: %s", device, err)
+			}
+		}
+
+		ids, err := crypto.FindIdentities(ctx, email)
+		if err != nil || len(ids) < 1 {
+			return nil, removeKey, fmt.Errorf("failed to find the new key %s: %w", email, err)
+		}
+		key = ids[0]
+
+		out.Noticef(ctx, "Enter the passphrase %q to export the new key", passphrase)
+		secret, err := exp.ExportPrivateKey(ctx, ids[0])
+		if err != nil {
+			return nil, removeKey, err
+		}
+
+		return &mobileIdentity{
+			recipient:  ids[0],
+			secret:     secret,
+			passphrase: passphrase,
+		}, removeKey, nil
+	default:
+		return nil, removeKey, fmt.Errorf("pass compatible mobile apps need a gpg or age store, not %s", crypto.Name())
+	}
+}
+
+// mobileAddDeployKey adds the deploy key through the Gitea API if a token is
+// available or explains how to add it manually.
+func (s *Action) mobileAddDeployKey(ctx context.Context, c *cli.Context, remote mobile.Remote, device string, pub []byte) error {
+	te := c.String("gitea-token-entry")
+	if te == "" {
+		out.Printf(ctx, "Add this public key as a read-only deploy key of %s/%s, e.g. in the settings of the repository on Gitea, GitHub or GitLab. Don't allow write access:", remote.Owner, remote.Repo)
+		out.Printf(ctx, "  %s", pub)
+
+		if !termio.AskForConfirmation(ctx, "Added the deploy key?") {
+			return exit.Error(exit.Aborted, nil, "user aborted")
+		}
+
+		return nil
+	}
+
+	sec, err := s.Store.Get(ctx, te)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read the Gitea token from %s: %s", te, err)
+	}
+
+	baseURL := c.String("gitea-url")
+	if baseURL == "" {
+		baseURL = "https://" + remote.Host
+	}
+
+	if err := mobile.AddGiteaDeployKey(ctx, baseURL, sec.Password(), remote, "gopass mobile "+device, pub); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to add the deploy key on %s: %s", baseURL, err)
+	}
+	out.OKf(ctx, "Added a read-only deploy key to %s/%s on %s", remote.Owner, remote.Repo, baseURL)
+
+	return nil
+}
+
+// mobileQR is a QR code shown during the handover.
+type mobileQR struct {
+	title   string
+	content string
+}
+
+// mobileHandover writes the keys of the device to a private temporary
+// directory and shows them as QR codes. The directory is removed once the
+// handover is done, so the keys don't stay on this machine.
+func (s *Action) mobileHandover(ctx context.Context, parent, device, cloneURL string, id *mobileIdentity, deployKey []byte) error {
+	dir, err := os.MkdirTemp(parent, "gopass-mobile-")
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to create a temporary directory: %s", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			out.Errorf(ctx, "Failed to remove the keys of %s in %s: %s", device, dir, err)
+		}
+	}()
+
+	keyFile := filepath.Join(dir, device+"-identity.txt")
+	if id.passphrase != "" {
+		keyFile = filepath.Join(dir, device+"-key.asc")
+	}
+	deployFile := filepath.Join(dir, device+"-deploy-key")
+
+	for fn, buf := range map[string][]byte{keyFile: id.secret, deployFile: deployKey} {
+		if err := os.WriteFile(fn, buf, 0o600); err != nil {
+			return exit.Error(exit.IO, err, "failed to write %s: %s", fn, err)
+		}
+	}
+	out.Printf(ctx, "The keys were written to %s and %s. Copy them to the device now, they are removed after the handover.", keyFile, deployFile)
+
+	qrs := []mobileQR{
+		{"Repository URL", cloneURL},
+		{"SSH deploy key", string(deployKey)},
+	}
+	if id.passphrase != "" {
+		qrs = append(qrs, mobileQR{"Passphrase of the gpg key in " + keyFile, id.passphrase})
+	} else {
+		qrs = append(qrs, mobileQR{"age identity", string(id.secret)})
+	}
+
+	for _, q := range qrs {
+		qr, err := qrcon.QRCode(q.content)
+		if err != nil {
+			return exit.Error(exit.Unknown, err, "failed to encode the %s as QR: %s", q.title, err)
+		}
+
+		out.Printf(ctx, "%s:", q.title)
+		fmt.Fprintln(stdout, qr)
+
+		if !termio.AskForConfirmation(ctx, "Next?") {
+			return exit.Error(exit.Aborted, nil, "user aborted")
+		}
+	}
+
+	return nil
+}
+
+// mobileCloneURL returns the SSH URL of the repository. The deploy key only
+// works over SSH.
+func mobileCloneURL(remoteURL string, r mobile.Remote) string {
+	if strings.HasPrefix(remoteURL, "http://") || strings.HasPrefix(remoteURL, "https://") {
+		return fmt.Sprintf("git@%s:%s/%s.git", r.Host, r.Owner, r.Repo)
+	}
+
+	return remoteURL
+}
@@ -0,0 +1,101 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/mobile"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMobileNoGit(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	err = act.Mobile(gptest.CliCtxWithFlags(ctx, t, map[string]string{"folder": "foo"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not synced with git")
+}
+
+func TestNewMobileIdentityUnsupported(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	_, removeKey, err := act.newMobileIdentity(ctx, act.Store.Crypto(ctx, ""), "phone")
+	removeKey()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "need a gpg or age store")
+}
+
+func TestMobileCloneURL(t *testing.T) {
+	t.Parallel()
+
+	r := mobile.Remote{Host: "git.example.org", Owner: "alice", Repo: "pass"}
+
+	for in, want := range map[string]string{
+		"https://git.example.org/alice/pass.git": "git@git.example.org:alice/pass.git",
+		"http://git.example.org/alice/pass":      "git@git.example.org:alice/pass.git",
+		"git@git.example.org:alice/pass.git":     "git@git.example.org:alice/pass.git",
+		"ssh://git@git.example.org/alice/pass":   "ssh://git@git.example.org/alice/pass",
+	} {
+		assert.Equal(t, want, mobileCloneURL(in, r), in)
+	}
+}
+
+func TestMobileHandover(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	parent := t.TempDir()
+	id := &mobileIdentity{recipient: "age1phone", secret: []byte("AGE-SECRET-KEY-1PHONE")}
+	require.NoError(t, act.mobileHandover(ctx, parent, "phone", "git@git.example.org:alice/pass.git", id, []byte("deploy")))
+	assert.Contains(t, buf.String(), "phone-identity.txt")
+
+	// the keys must not stay on this machine.
+	left, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+}
@@ -0,0 +1,65 @@
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/names"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// normalizeName applies the name rules of the store to the name of a new
+// entry. If the store already has an entry that only differs in case, a www.
+// prefix or spaces it offers to use that one instead, see names.collisions.
+func (s *Action) normalizeName(ctx context.Context, name string) (string, error) {
+	rules := names.RulesFor(ctx, s.Store.MountPoint(name))
+	if !rules.Enabled() || s.Store.Exists(ctx, name) {
+		return name, nil
+	}
+
+	if n := rules.Normalize(name); n != name {
+		out.Noticef(ctx, "Using %s instead of %s", n, name)
+		name = n
+	}
+
+	if s.Store.Exists(ctx, name) {
+		return name, nil
+	}
+
+	entries, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return name, exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	dups := names.Collisions(name, entries)
+	if len(dups) < 1 {
+		return name, nil
+	}
+	debug.Log("%s collides with %v", name, dups)
+
+	switch rules.Collisions {
+	case names.CollisionIgnore:
+		out.Warningf(ctx, "%s looks like the existing entry %s", name, dups[0])
+
+		return name, nil
+	case names.CollisionExisting:
+		out.Noticef(ctx, "Using the existing entry %s instead of %s", dups[0], name)
+
+		return dups[0], nil
+	}
+
+	use, err := termio.AskForBool(ctx, fmt.Sprintf("%s looks like the existing entry %s. Use %s instead?", name, dups[0], dups[0]), true)
+	if err != nil {
+		return name, exit.Error(exit.Aborted, err, "user aborted: %s", err)
+	}
+
+	if use {
+		return dups[0], nil
+	}
+
+	return name, nil
+}
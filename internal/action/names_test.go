@@ -0,0 +1,90 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("secret")
+	require.NoError(t, act.Store.Set(ctx, "websites/GitHub.com", sec))
+
+	// no rules, no changes.
+	name, err := act.normalizeName(ctx, "websites/www.github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "websites/www.github.com", name)
+
+	require.NoError(t, act.cfg.SetEnv("names.lowercase-domains", "true"))
+	require.NoError(t, act.cfg.SetEnv("names.strip-www", "true"))
+	require.NoError(t, act.cfg.SetEnv("names.spaces", "-"))
+
+	name, err = act.normalizeName(ctx, "websites/www.GitLab.com")
+	require.NoError(t, err)
+	assert.Equal(t, "websites/gitlab.com", name)
+
+	name, err = act.normalizeName(ctx, "personal/my bank")
+	require.NoError(t, err)
+	assert.Equal(t, "personal/my-bank", name)
+
+	// existing entries are never renamed.
+	name, err = act.normalizeName(ctx, "websites/GitHub.com")
+	require.NoError(t, err)
+	assert.Equal(t, "websites/GitHub.com", name)
+
+	// the prompt defaults to the existing entry.
+	name, err = act.normalizeName(ctx, "websites/www.github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "websites/GitHub.com", name)
+
+	require.NoError(t, act.cfg.SetEnv("names.collisions", "ignore"))
+	name, err = act.normalizeName(ctx, "websites/www.github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "websites/github.com", name)
+	assert.Contains(t, buf.String(), "looks like the existing entry websites/GitHub.com")
+
+	t.Run("insert", func(t *testing.T) {
+		require.NoError(t, act.cfg.SetEnv("names.collisions", "existing"))
+		ctx := ctxutil.WithStdin(ctx, true)
+
+		stdin = bytes.NewBufferString("new-secret")
+		defer func() {
+			stdin = os.Stdin
+		}()
+
+		assert.NoError(t, act.Insert(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "websites/www.github.com")))
+		assert.False(t, act.Store.Exists(ctx, "websites/github.com"))
+
+		sec, err := act.Store.Get(ctx, "websites/GitHub.com")
+		require.NoError(t, err)
+		assert.Equal(t, "new-secret", sec.Password())
+	})
+}
@@ -0,0 +1,73 @@
+package action
+
+import (
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/browser"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/clipboard"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Open opens the URL of an entry in the browser. With --copy it copies the
+// username to the clipboard and the password after --delay seconds, so both
+// can be pasted into the login form.
+func (s *Action) Open(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s open <entry>", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) {
+		return exit.Error(exit.NotFound, nil, "Entry %q not found", name)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	u := findURL(sec)
+	if u == "" {
+		return exit.Error(exit.NotFound, nil, "%s has no url", name)
+	}
+
+	if err := browser.Open(ctx, u); err != nil {
+		return exit.Error(exit.IO, err, "failed to open %s: %s", u, err)
+	}
+
+	if !c.Bool("copy") {
+		return nil
+	}
+
+	timeout := s.cfg.GetInt("core.cliptimeout")
+
+	if user := findUsername(sec); user != "" {
+		if err := clipboard.CopyTo(ctx, name+" username", []byte(user), timeout); err != nil {
+			return exit.Error(exit.IO, err, "failed to copy to clipboard: %s", err)
+		}
+
+		delay := time.Duration(c.Int("delay")) * time.Second
+		out.Noticef(ctx, "Copying the password in %s", delay)
+
+		select {
+		case <-ctx.Done():
+			return exit.Error(exit.Aborted, ctx.Err(), "aborted")
+		case <-time.After(delay):
+		}
+	}
+
+	if sec.Password() == "" {
+		return nil
+	}
+
+	if err := clipboard.CopyTo(ctx, name, []byte(sec.Password()), timeout); err != nil {
+		return exit.Error(exit.IO, err, "failed to copy to clipboard: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/atotto/clipboard"
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	ov := clipboard.Unsupported
+	defer func() {
+		clipboard.Unsupported = ov
+	}()
+	clipboard.Unsupported = true
+
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.SetEnv("browser.command", "true"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("hunter2")
+	require.NoError(t, sec.Set("username", "gopher"))
+	require.NoError(t, sec.Set("url", "https://example.org/login"))
+	require.NoError(t, act.Store.Set(ctx, "web/example.org", sec))
+
+	assert.Error(t, act.Open(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.Open(gptest.CliCtx(ctx, t, "web/missing")))
+
+	// foo has no url.
+	assert.Error(t, act.Open(gptest.CliCtx(ctx, t, "foo")))
+
+	assert.NoError(t, act.Open(gptest.CliCtx(ctx, t, "web/example.org")))
+	assert.NotContains(t, buf.String(), "hunter2")
+	buf.Reset()
+
+	assert.NoError(t, act.Open(gptest.CliCtxWithFlags(ctx, t, map[string]string{"copy": "true", "delay": "0"}, "web/example.org")))
+	assert.Contains(t, buf.String(), "Copying the password")
+	assert.NotContains(t, buf.String(), "hunter2")
+}
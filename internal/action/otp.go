@@ -10,12 +10,14 @@ import (
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/pkg/clipboard"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/otp"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/mattn/go-tty"
+	gotp "github.com/pquerna/otp"
 	"github.com/pquerna/otp/hotp"
 	"github.com/pquerna/otp/totp"
 	"github.com/urfave/cli/v2"
@@ -23,11 +25,12 @@ import (
 
 // OTP implements OTP token handling for TOTP and HOTP.
 func (s *Action) OTP(c *cli.Context) error {
-	ctx := ctxutil.WithGlobalFlags(c)
+	ctx := root.WithKeyIndex(ctxutil.WithGlobalFlags(c), true)
 	name := c.Args().First()
 	if name == "" {
-		return exit.Error(exit.Usage, nil, "Usage: %s otp <NAME>", s.Name)
+		return exit.Error(exit.Usage, nil, "Usage: %s otp <NAME> [KEY]", s.Name)
 	}
+	key := c.Args().Get(1)
 
 	qrf := c.String("qr")
 	clip := c.Bool("clip")
@@ -44,7 +47,7 @@ func (s *Action) OTP(c *cli.Context) error {
 		if err != nil || !choice {
 			return err
 		}
-		err = s.insertYAML(ctxutil.WithInteractive(ctx, false), name, "otpauth", []byte(qr), nil)
+		err = s.insertYAML(ctxutil.WithInteractive(ctx, false), name, "otpauth", []byte(qr), false, nil)
 		if err != nil {
 			return err
 		}
@@ -52,7 +55,7 @@ func (s *Action) OTP(c *cli.Context) error {
 		out.Print(ctx, "Value written, carrying on to display OTP value from it.")
 	}
 
-	return s.otp(ctx, name, qrf, clip, pw, true)
+	return s.otp(ctx, name, key, qrf, clip, pw, true)
 }
 
 func tickingBar(ctx context.Context, expiresAt time.Time, bar *termio.ProgressBar) {
@@ -104,10 +107,12 @@ func waitForKeyPress(ctx context.Context, cancel context.CancelFunc) (func(), fu
 }
 
 // nolint: cyclop
-func (s *Action) otp(ctx context.Context, name, qrf string, clip, pw, recurse bool) error {
+// otp shows the token of the entry. If key is set, the token is computed from
+// the otpauth URL or the TOTP secret in this key.
+func (s *Action) otp(ctx context.Context, name, key, qrf string, clip, pw, recurse bool) error {
 	sec, err := s.Store.Get(ctx, name)
 	if err != nil {
-		return s.otpHandleError(ctx, name, qrf, clip, pw, recurse, err)
+		return s.otpHandleError(ctx, name, key, qrf, clip, pw, recurse, err)
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -135,7 +140,12 @@ func (s *Action) otp(ctx context.Context, name, qrf string, clip, pw, recurse bo
 		default:
 		}
 
-		two, err := otp.Calculate(name, sec)
+		var two *gotp.Key
+		if key != "" {
+			two, err = otp.CalculateKey(sec, key)
+		} else {
+			two, err = otp.Calculate(name, sec)
+		}
 		if err != nil {
 			return exit.Error(exit.Unknown, err, "No OTP entry found for %s: %s", name, err)
 		}
@@ -198,7 +208,7 @@ func (s *Action) otp(ctx context.Context, name, qrf string, clip, pw, recurse bo
 		}
 
 		// if not then we want to print a progress bar with the expiry time.
-		out.Warningf(ctx, "([q] to stop. -o flag to avoid.) This OTP password still lasts for:", nil)
+		out.Warningf(ctx, "([q] to stop. -o flag to avoid.) This OTP password still lasts for:")
 
 		if bar.Hidden {
 			cancel()
@@ -227,14 +237,14 @@ func (s *Action) otp(ctx context.Context, name, qrf string, clip, pw, recurse bo
 	}
 }
 
-func (s *Action) otpHandleError(ctx context.Context, name, qrf string, clip, pw, recurse bool, err error) error {
+func (s *Action) otpHandleError(ctx context.Context, name, key, qrf string, clip, pw, recurse bool, err error) error {
 	if !errors.Is(err, store.ErrNotFound) || !recurse || !ctxutil.IsTerminal(ctx) {
 		return exit.Error(exit.Unknown, err, "failed to retrieve secret %q: %s", name, err)
 	}
 
 	out.Printf(ctx, "Entry %q not found. Starting search...", name)
 	cb := func(ctx context.Context, c *cli.Context, name string, recurse bool) error {
-		return s.otp(ctx, name, qrf, clip, pw, false)
+		return s.otp(ctx, name, key, qrf, clip, pw, false)
 	}
 	if err := s.find(ctx, nil, name, cb, false); err != nil {
 		return exit.Error(exit.NotFound, err, "%s", err)
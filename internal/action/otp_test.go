@@ -59,7 +59,7 @@ func TestOTP(t *testing.T) {
 
 	t.Run("copy to clipboard", func(t *testing.T) {
 		defer buf.Reset()
-		assert.NoError(t, act.otp(ctx, "bar", "", true, false, false))
+		assert.NoError(t, act.otp(ctx, "bar", "", "", true, false, false))
 	})
 
 	t.Run("write QR file", func(t *testing.T) {
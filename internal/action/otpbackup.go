@@ -0,0 +1,205 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/fips"
+	"github.com/gopasspw/gopass/internal/otpbackup"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// OTPExport writes the OTP seeds of all entries to the backup format of an
+// authenticator app.
+func (s *Action) OTPExport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	format := c.String("format")
+	if fn == "" || format == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s otp export --format <%s> [--folder <folder>] <file>", s.Name, strings.Join(otpbackup.Formats(), "|"))
+	}
+
+	names, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	folder := strings.TrimSuffix(c.String("folder"), "/")
+
+	var entries []otpbackup.Entry
+	for _, name := range names {
+		if folder != "" && !strings.HasPrefix(name, folder+"/") {
+			continue
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			out.Warningf(ctx, "Skipping %s: %s", name, err)
+
+			continue
+		}
+
+		if !otp.Has(sec) {
+			continue
+		}
+
+		k, err := otp.Calculate(name, sec)
+		if err != nil {
+			out.Warningf(ctx, "Skipping %s: %s", name, err)
+
+			continue
+		}
+
+		e := otpbackup.FromKey(k)
+		// seeds without an otpauth URL get placeholder labels.
+		if k.Issuer() == "gopass" && k.AccountName() == "new" {
+			e.Account = name
+		}
+		debug.Log("exporting OTP seed of %s as %s:%s", name, e.Issuer, e.Account)
+		entries = append(entries, e)
+	}
+
+	if len(entries) < 1 {
+		return exit.Error(exit.NotFound, nil, "No entries with OTP seeds found")
+	}
+
+	var pw string
+	if otpbackup.Encrypted(format) {
+		pw, err = termio.AskForPassword(ctx, "password for the backup", true)
+		if err != nil {
+			return exit.Error(exit.Aborted, err, "failed to read password: %s", err)
+		}
+	}
+
+	// Aegis derives the key with scrypt.
+	if format == otpbackup.FormatAegis && pw != "" && fips.Enabled(ctx) {
+		return exit.Error(exit.Unsupported, fips.ErrNotApproved, "encrypted Aegis backups use scrypt: %s", fips.ErrNotApproved)
+	}
+
+	buf, err := otpbackup.Export(format, entries, []byte(pw))
+	if err != nil {
+		return exit.Error(exit.Usage, err, "failed to export OTP seeds: %s", err)
+	}
+
+	if err := os.WriteFile(fn, buf, 0o600); err != nil {
+		return exit.Error(exit.IO, err, "failed to write %s: %s", fn, err)
+	}
+
+	if pw == "" {
+		out.Warningf(ctx, "The backup is not encrypted. Delete %s once it has been imported", fn)
+	}
+
+	out.OKf(ctx, "Exported %d OTP seeds to %s", len(entries), fn)
+
+	return nil
+}
+
+// OTPImport adds the OTP seeds from the backup of an authenticator app to
+// the store.
+func (s *Action) OTPImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	format := c.String("format")
+	if fn == "" || format == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s otp import --format <%s> [--folder <folder>] <file>", s.Name, strings.Join(otpbackup.Formats(), "|"))
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to read %s: %s", fn, err)
+	}
+
+	var pw string
+	if otpbackup.Encrypted(format) {
+		pw, err = termio.AskForPassword(ctx, "password of the backup", false)
+		if err != nil {
+			return exit.Error(exit.Aborted, err, "failed to read password: %s", err)
+		}
+	}
+
+	entries, err := otpbackup.Import(format, buf, []byte(pw))
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to import %s: %s", fn, err)
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Imported OTP seed from %s backup", format))
+
+	var added, skipped int
+	for _, e := range entries {
+		name := otpImportName(c.String("folder"), e)
+
+		sec := secrets.New()
+		if s.Store.Exists(ctx, name) {
+			sec, err = s.Store.Get(ctx, name)
+			if err != nil {
+				return exit.Error(exit.Decrypt, err, "failed to decrypt %s: %s", name, err)
+			}
+		}
+
+		if otp.Has(sec) {
+			if k, err := otp.Calculate(name, sec); err == nil && strings.EqualFold(otpbackup.FromKey(k).Secret, e.Secret) {
+				debug.Log("%s already has this seed", name)
+				skipped++
+
+				continue
+			}
+
+			if !c.Bool("force") {
+				out.Warningf(ctx, "%s already has a different OTP seed. Use --force to replace it", name)
+				skipped++
+
+				continue
+			}
+		}
+
+		if err := sec.Set("otpauth", e.URL()); err != nil {
+			return exit.Error(exit.Unknown, err, "failed to set OTP seed of %s: %s", name, err)
+		}
+
+		if err := s.Store.Set(ctx, name, sec); err != nil {
+			return exit.Error(exit.Encrypt, err, "failed to write %s: %s", name, err)
+		}
+
+		out.Printf(ctx, "Imported %s", name)
+		added++
+	}
+
+	out.OKf(ctx, "Imported %d OTP seeds, %d were already present", added, skipped)
+
+	return nil
+}
+
+// otpImportName returns the name of the entry for an imported OTP seed,
+// i.e. <folder>/<issuer>/<account>.
+func otpImportName(folder string, e otpbackup.Entry) string {
+	clean := func(s string) string {
+		s = strings.TrimSpace(strings.ReplaceAll(s, "/", "_"))
+		if s == "." || s == ".." {
+			return "_"
+		}
+
+		return s
+	}
+
+	parts := []string{strings.Trim(folder, "/")}
+	if e.Issuer != "" {
+		parts = append(parts, clean(e.Issuer))
+	}
+
+	account := clean(e.Account)
+	if account == "" {
+		account = "unnamed"
+	}
+
+	return path.Join(append(parts, account)...)
+}
@@ -0,0 +1,76 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPExportImport(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foobar")
+	require.NoError(t, sec.Set("otpauth", "otpauth://totp/Example:alice@example.org?secret=JBSWY3DPEHPK3PXP&issuer=Example"))
+	require.NoError(t, act.Store.Set(ctx, "web/example", sec))
+
+	fn := filepath.Join(t.TempDir(), "aegis.json")
+
+	t.Run("export without format", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.OTPExport(gptest.CliCtx(ctx, t, fn)))
+	})
+
+	t.Run("export", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.OTPExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "aegis"}, fn)))
+		assert.Contains(t, buf.String(), "Exported 1 OTP seeds")
+		assert.Contains(t, buf.String(), "not encrypted")
+	})
+
+	t.Run("import", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.OTPImport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "aegis", "folder": "phone"}, fn)))
+
+		sec, err := act.Store.Get(ctx, "phone/Example/alice@example.org")
+		require.NoError(t, err)
+		assert.True(t, otp.Has(sec))
+
+		k, err := otp.Calculate("phone/Example/alice@example.org", sec)
+		require.NoError(t, err)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", k.Secret())
+	})
+
+	t.Run("import again", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.OTPImport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "aegis", "folder": "phone"}, fn)))
+		assert.Contains(t, buf.String(), "Imported 0 OTP seeds, 1 were already present")
+	})
+}
@@ -0,0 +1,63 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Pin marks an entry as immutable or lists the pinned entries.
+func (s *Action) Pin(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+
+	if name == "" {
+		if c.Bool("unpin") {
+			return exit.Error(exit.Usage, nil, "Usage: %s pin [--unpin] <entry>", s.Name)
+		}
+
+		for _, p := range s.Store.Pinned(ctx) {
+			fmt.Fprintln(stdout, p)
+		}
+
+		return nil
+	}
+
+	if c.Bool("unpin") {
+		if err := s.Store.Unpin(ctx, name); err != nil {
+			return exit.Error(exit.Unknown, err, "failed to unpin %s: %s", name, err)
+		}
+
+		out.OKf(ctx, "%s is no longer pinned", name)
+
+		return nil
+	}
+
+	if err := s.Store.Pin(ctx, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return exit.Error(exit.NotFound, err, "Secret %s not found", name)
+		}
+
+		return exit.Error(exit.Unknown, err, "failed to pin %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "%s is pinned. It can't be changed until you run '%s pin --unpin %s'", name, s.Name, name)
+
+	return nil
+}
+
+// checkPinned returns an error if the entry is pinned. Commands use it to
+// fail before asking the user for any input.
+func (s *Action) checkPinned(ctx context.Context, name string) error {
+	if !s.Store.IsPinned(ctx, name) {
+		return nil
+	}
+
+	return exit.Error(exit.Aborted, store.ErrPinned, "%s is pinned. Run '%s pin --unpin %s' first", name, s.Name, name)
+}
@@ -0,0 +1,68 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPin(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	assert.Error(t, act.Pin(gptest.CliCtx(ctx, t, "missing")))
+	assert.Error(t, act.Pin(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unpin": "true"})))
+	require.NoError(t, act.Pin(gptest.CliCtx(ctx, t, "foo")))
+	buf.Reset()
+
+	t.Run("list", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Pin(gptest.CliCtx(ctx, t)))
+		assert.Equal(t, "foo\n", buf.String())
+	})
+
+	t.Run("pinned entries can't be changed", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "foo", "24")))
+		assert.Error(t, act.Delete(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "foo")))
+		assert.Error(t, act.Edit(gptest.CliCtx(ctx, t, "foo")))
+		assert.True(t, act.Store.Exists(ctx, "foo"))
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Pin(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unpin": "true"}, "foo")))
+		assert.Error(t, act.Pin(gptest.CliCtxWithFlags(ctx, t, map[string]string{"unpin": "true"}, "foo")))
+		require.NoError(t, act.Delete(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "foo")))
+	})
+}
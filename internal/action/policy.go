@@ -0,0 +1,127 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/policy"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// PolicyList prints the policy packs of all stores.
+func (s *Action) PolicyList(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	var found bool
+	for _, alias := range append([]string{""}, s.Store.MountPoints()...) {
+		packs := s.Store.PolicyPacks(ctx, alias)
+		if len(packs) < 1 {
+			continue
+		}
+		found = true
+
+		label := alias
+		if label == "" {
+			label = "<root>"
+		}
+		fmt.Fprintf(stdout, "%s\n", label)
+
+		for _, p := range packs {
+			printPolicyPack(stdout, p)
+		}
+	}
+
+	if !found {
+		out.Printf(ctx, "No policy packs installed")
+	}
+
+	return nil
+}
+
+func printPolicyPack(w io.Writer, p *policy.Pack) {
+	fmt.Fprintf(w, "  %s v%d", p.Name, p.Version)
+	if p.Description != "" {
+		fmt.Fprintf(w, " - %s", p.Description)
+	}
+	fmt.Fprintln(w)
+
+	for _, r := range p.Rules {
+		var reqs []string
+		if r.MinLength > 0 {
+			reqs = append(reqs, fmt.Sprintf("min %d", r.MinLength))
+		}
+		if r.MaxLength > 0 {
+			reqs = append(reqs, fmt.Sprintf("max %d", r.MaxLength))
+		}
+		if len(r.Require) > 0 {
+			reqs = append(reqs, "requires "+strings.Join(r.Require, ", "))
+		}
+		if r.Charset != "" {
+			reqs = append(reqs, "charset "+r.Charset)
+		}
+		if len(r.Deny) > 0 {
+			reqs = append(reqs, fmt.Sprintf("%d denied patterns", len(r.Deny)))
+		}
+		if r.MaxConsecutive > 0 {
+			reqs = append(reqs, fmt.Sprintf("max %d consecutive", r.MaxConsecutive))
+		}
+
+		scope := "all secrets"
+		if r.Match != "" {
+			scope = r.Match
+		}
+
+		fmt.Fprintf(w, "    %s (%s): %s\n", r.Name, scope, strings.Join(reqs, "; "))
+	}
+}
+
+// PolicyImport adds or updates a policy pack. Use - to read from stdin.
+func (s *Action) PolicyImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s policy import [--store <store>] <file>", s.Name)
+	}
+
+	var buf []byte
+	var err error
+	if fn == "-" {
+		buf, err = io.ReadAll(stdin)
+	} else {
+		buf, err = os.ReadFile(fn)
+	}
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to read %s: %s", fn, err)
+	}
+
+	p, err := s.Store.ImportPolicyPack(ctx, c.String("store"), buf, c.Bool("force"))
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to import policy pack: %s", err)
+	}
+
+	out.OKf(ctx, "Imported policy pack %s v%d with %d rules", p.Name, p.Version, len(p.Rules))
+
+	return nil
+}
+
+// PolicyRemove removes a policy pack.
+func (s *Action) PolicyRemove(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s policy remove [--store <store>] <name>", s.Name)
+	}
+
+	if err := s.Store.RemovePolicyPack(ctx, c.String("store"), name); err != nil {
+		return exit.Error(exit.NotFound, err, "failed to remove policy pack: %s", err)
+	}
+
+	out.OKf(ctx, "Removed policy pack %s", name)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/audit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	pack := func(version int) string {
+		fn := filepath.Join(t.TempDir(), "acme.json")
+		require.NoError(t, os.WriteFile(fn, []byte(`{"name": "acme", "version": `+strconv.Itoa(version)+`, "rules": [
+  {"name": "long", "min_length": 40, "require": ["lower", "upper", "digit"]}
+]}`), 0o600))
+
+		return fn
+	}
+
+	t.Run("list without packs", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.PolicyList(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "No policy packs installed")
+	})
+
+	t.Run("import", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.PolicyImport(gptest.CliCtx(ctx, t)))
+		require.NoError(t, act.PolicyImport(gptest.CliCtx(ctx, t, pack(2))))
+
+		// no downgrades.
+		assert.Error(t, act.PolicyImport(gptest.CliCtx(ctx, t, pack(1))))
+
+		buf.Reset()
+		require.NoError(t, act.PolicyList(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "acme v2")
+		assert.Contains(t, buf.String(), "long (all secrets): min 40; requires lower, upper, digit")
+	})
+
+	t.Run("generate complies", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Generate(gptest.CliCtx(ctx, t, "policy/web", "16")))
+
+		sec, err := act.Store.Get(ctx, "policy/web")
+		require.NoError(t, err)
+		assert.Len(t, sec.Password(), 40)
+		assert.NoError(t, act.Store.CheckPolicy(ctx, "policy/web", sec.Password()))
+	})
+
+	t.Run("generate refuses non-compliant passwords", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"generator": "memorable"}, "policy/memorable", "12")))
+		assert.False(t, act.Store.Exists(ctx, "policy/memorable"))
+	})
+
+	t.Run("audit reports violations", func(t *testing.T) {
+		defer buf.Reset()
+		sec := secrets.New()
+		sec.SetPassword("Short1")
+		require.NoError(t, act.Store.Set(ctx, "policy/short", sec))
+
+		r, err := audit.New(ctx, act.Store).Batch(ctx, []string{"policy/short", "policy/web"})
+		require.NoError(t, err)
+		assert.Contains(t, r.Secrets["policy/short"].Findings["policy"].Message, "shorter than 40 characters")
+		assert.Equal(t, "ok", r.Secrets["policy/web"].Findings["policy"].Message)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.PolicyRemove(gptest.CliCtx(ctx, t)))
+		assert.Error(t, act.PolicyRemove(gptest.CliCtx(ctx, t, "foo")))
+		require.NoError(t, act.PolicyRemove(gptest.CliCtx(ctx, t, "acme")))
+		assert.Empty(t, act.Store.PolicyPacks(ctx, ""))
+	})
+}
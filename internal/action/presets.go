@@ -0,0 +1,250 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/editor"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/urfave/cli/v2"
+)
+
+// presetOptions are the generate options a preset can set. Presets are
+// configured as preset.<name>.<option>, e.g. preset.banking.length.
+var presetOptions = []string{"length", "symbols", "generator", "charset", "strict"}
+
+// preset is a named bundle of generate options. Options given on the
+// command line take precedence.
+type preset struct {
+	name string
+	opts map[string]string
+}
+
+// presetNames returns the names of all configured presets.
+func presetNames(ctx context.Context) []string {
+	names := make(map[string]bool)
+	for _, k := range config.FromContext(ctx).Keys("") {
+		if !strings.HasPrefix(k, "preset.") {
+			continue
+		}
+
+		k = strings.TrimPrefix(k, "preset.")
+		if i := strings.LastIndex(k, "."); i > 0 && set.Contains(presetOptions, k[i+1:]) {
+			names[k[:i]] = true
+		}
+	}
+
+	return set.SortedKeys(names)
+}
+
+// loadPreset reads and validates the named preset from the config.
+func loadPreset(ctx context.Context, name string) (preset, error) {
+	cfg := config.FromContext(ctx)
+
+	p := preset{name: name, opts: make(map[string]string, len(presetOptions))}
+	for _, o := range presetOptions {
+		if v := strings.TrimSpace(cfg.Get("preset." + name + "." + o)); v != "" {
+			p.opts[o] = v
+		}
+	}
+
+	if len(p.opts) < 1 {
+		return p, fmt.Errorf("preset %q not found. Available presets: %s", name, strings.Join(presetNames(ctx), ", "))
+	}
+
+	return p, p.validate()
+}
+
+func (p preset) validate() error {
+	for o, v := range p.opts {
+		switch o {
+		case "length":
+			if n, err := strconv.Atoi(v); err != nil || n < 1 {
+				return fmt.Errorf("preset %s: length must be a positive number, not %q", p.name, v)
+			}
+		case "symbols", "strict":
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("preset %s: %s must be true or false, not %q", p.name, o, v)
+			}
+		case "generator":
+			if !set.Contains([]string{"cryptic", "memorable", "xkcd", "external", pwgen.TokenUUID, pwgen.TokenHex, pwgen.TokenBase64, pwgen.TokenBase58}, v) {
+				return fmt.Errorf("preset %s: unknown generator %q", p.name, v)
+			}
+		case "charset":
+		default:
+			return fmt.Errorf("preset %s: unknown option %q. Supported options: %s", p.name, o, strings.Join(presetOptions, ", "))
+		}
+	}
+
+	return nil
+}
+
+// bool returns the value of a boolean option and whether it is set.
+func (p preset) bool(opt string) (bool, bool) {
+	v, found := p.opts[opt]
+	if !found {
+		return false, false
+	}
+
+	bv, err := strconv.ParseBool(v)
+
+	return bv, err == nil
+}
+
+// String returns the options of the preset in a stable order.
+func (p preset) String() string {
+	var buf bytes.Buffer
+	for _, o := range presetOptions {
+		if v, found := p.opts[o]; found {
+			fmt.Fprintf(&buf, "%s = %s\n", o, v)
+		}
+	}
+
+	return buf.String()
+}
+
+// parsePreset parses the options of a preset as written by String.
+func parsePreset(name string, buf []byte) (preset, error) {
+	p := preset{name: name, opts: make(map[string]string, len(presetOptions))}
+	for _, line := range strings.Split(string(buf), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		o, v, found := strings.Cut(line, "=")
+		if !found {
+			return p, fmt.Errorf("invalid line %q. Use <option> = <value>", line)
+		}
+
+		if v = strings.TrimSpace(v); v != "" {
+			p.opts[strings.TrimSpace(o)] = v
+		}
+	}
+
+	return p, p.validate()
+}
+
+// PresetsList prints all generation presets.
+func (s *Action) PresetsList(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	for _, name := range presetNames(ctx) {
+		p, err := loadPreset(ctx, name)
+		if err != nil {
+			out.Warningf(ctx, "%s", err)
+
+			continue
+		}
+
+		fmt.Fprintf(stdout, "%s: %s\n", name, strings.Join(strings.Split(strings.TrimSpace(p.String()), "\n"), ", "))
+	}
+
+	return nil
+}
+
+// PresetsEdit creates or changes a generation preset in the editor.
+func (s *Action) PresetsEdit(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" || strings.HasSuffix(name, ".") {
+		return exit.Error(exit.Usage, nil, "Usage: %s presets edit <name>", s.Name)
+	}
+
+	cur := preset{name: name, opts: map[string]string{}}
+	if set.Contains(presetNames(ctx), name) {
+		p, err := loadPreset(ctx, name)
+		if err != nil {
+			out.Warningf(ctx, "%s", err)
+		}
+		cur = p
+	}
+
+	content := fmt.Sprintf("# Options of the preset %s, one per line. Supported options: %s\n", name, strings.Join(presetOptions, ", "))
+	if len(cur.opts) > 0 {
+		content += cur.String()
+	} else {
+		content += "length = " + strconv.Itoa(defaultLength) + "\nsymbols = true\n"
+	}
+
+	buf, err := editor.Invoke(ctx, editor.Path(c), []byte(content))
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to invoke editor: %s", err)
+	}
+
+	p, err := parsePreset(name, buf)
+	if err != nil {
+		return exit.Error(exit.Usage, err, "Invalid preset: %s", err)
+	}
+
+	if err := s.savePreset(cur, p); err != nil {
+		return exit.Error(exit.Config, err, "failed to save preset %s: %s", name, err)
+	}
+
+	if len(p.opts) < 1 {
+		out.OKf(ctx, "Removed preset %s", name)
+
+		return nil
+	}
+
+	out.OKf(ctx, "Saved preset %s. Use it with '%s generate --preset %s'", name, s.Name, name)
+
+	return nil
+}
+
+// savePreset writes the options of p to the config and removes the options
+// of cur that are no longer set.
+func (s *Action) savePreset(cur, p preset) error {
+	opts := make([]string, 0, len(p.opts))
+	for o := range p.opts {
+		opts = append(opts, o)
+	}
+	sort.Strings(opts)
+
+	for _, o := range opts {
+		if cur.opts[o] == p.opts[o] {
+			continue
+		}
+
+		if err := s.cfg.Set("", "preset."+p.name+"."+o, p.opts[o]); err != nil {
+			return err
+		}
+	}
+
+	for o := range cur.opts {
+		if _, found := p.opts[o]; found {
+			continue
+		}
+
+		if err := s.cfg.Unset("", "preset."+p.name+"."+o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completePresets prints the preset names if the user is completing the
+// value of --preset.
+func completePresets(ctx context.Context) bool {
+	// the completion flag is the last argument, the flag the one before.
+	if len(os.Args) < 3 || os.Args[len(os.Args)-2] != "--preset" {
+		return false
+	}
+
+	for _, name := range presetNames(ctx) {
+		fmt.Fprintln(stdout, bashEscape(name))
+	}
+
+	return true
+}
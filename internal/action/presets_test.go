@@ -0,0 +1,94 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresets(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	require.NoError(t, act.cfg.SetEnv("preset.banking.length", "12"))
+	require.NoError(t, act.cfg.SetEnv("preset.banking.charset", "0123456789"))
+	require.NoError(t, act.cfg.SetEnv("preset.pin.length", "zero"))
+
+	assert.Equal(t, []string{"banking", "pin"}, presetNames(ctx))
+
+	t.Run("load", func(t *testing.T) {
+		p, err := loadPreset(ctx, "banking")
+		require.NoError(t, err)
+		assert.Equal(t, "length = 12\ncharset = 0123456789\n", p.String())
+
+		_, err = loadPreset(ctx, "pin")
+		assert.Error(t, err)
+		_, err = loadPreset(ctx, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.PresetsList(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "banking: length = 12, charset = 0123456789\n")
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"preset": "banking"}, "bank/login")))
+		sec, err := act.Store.Get(ctx, "bank/login")
+		require.NoError(t, err)
+		assert.Regexp(t, `^[0-9]{12}$`, sec.Password())
+
+		require.NoError(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"preset": "banking", "force": "true"}, "bank/login", "6")))
+		sec, err = act.Store.Get(ctx, "bank/login")
+		require.NoError(t, err)
+		assert.Regexp(t, `^[0-9]{6}$`, sec.Password())
+
+		assert.Error(t, act.Generate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"preset": "missing"}, "bank/other")))
+	})
+}
+
+func TestParsePreset(t *testing.T) {
+	t.Parallel()
+
+	p, err := parsePreset("banking", []byte("# comment\nlength = 20\nsymbols=false\nstrict =\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"length": "20", "symbols": "false"}, p.opts)
+
+	for _, in := range []string{"length 20\n", "length = -1\n", "symbols = maybe\n", "generator = foo\n", "colour = red\n"} {
+		_, err := parsePreset("banking", []byte(in))
+		assert.Error(t, err, in)
+	}
+}
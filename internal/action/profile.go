@@ -0,0 +1,360 @@
+package action
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/blueprint"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/profile"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// ProfileExport writes the mounts, remotes, templates, schemas and policy
+// settings to a signed profile.
+func (s *Action) ProfileExport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s profile export [--name <name>] [--blueprint <file>] <file|->", s.Name)
+	}
+
+	p := &profile.Profile{
+		Name:        c.String("name"),
+		Description: c.String("description"),
+		Created:     time.Now().UTC(),
+		Templates:   map[string]string{},
+		Schemas:     map[string][]string{},
+		Config:      map[string]string{},
+	}
+
+	for _, mp := range append([]string{""}, s.Store.MountPoints()...) {
+		p.Mounts = append(p.Mounts, profile.Mount{
+			Alias:   mp,
+			Remote:  s.profileRemote(ctx, mp),
+			Crypto:  s.Store.Crypto(ctx, mp).Name(),
+			Storage: s.Store.Storage(ctx, mp).Name(),
+		})
+	}
+
+	tt, err := s.Store.TemplateTree(ctx)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list templates: %s", err)
+	}
+	for _, name := range tt.List(tree.INF) {
+		content, err := s.Store.GetTemplate(ctx, name)
+		if err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to read template %s: %s", name, err)
+		}
+		p.Templates[name] = string(content)
+	}
+
+	if bfn := c.String("blueprint"); bfn != "" {
+		bp, err := readBlueprint(bfn)
+		if err != nil {
+			return exit.Error(exit.IO, err, "Failed to read blueprint: %s", err)
+		}
+
+		for _, f := range bp.Folders {
+			if len(f.Schema.Required) > 0 {
+				p.Schemas[f.Path] = f.Schema.Required
+			}
+		}
+	}
+
+	for _, k := range s.cfg.Keys("") {
+		if profile.Shareable(k) {
+			p.Config[k] = s.cfg.Get(k)
+		}
+	}
+
+	priv, err := s.profileSigningKey(ctx, c.String("signing-key"))
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to load signing key: %s", err)
+	}
+
+	buf, err := p.Sign(priv)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to sign profile: %s", err)
+	}
+
+	if fn == "-" {
+		_, err := stdout.Write(buf)
+
+		return err
+	}
+
+	if err := os.WriteFile(fn, buf, 0o644); err != nil {
+		return exit.Error(exit.IO, err, "failed to write profile to %s: %s", fn, err)
+	}
+
+	key := profile.EncodeKey(priv.Public().(ed25519.PublicKey))
+	out.OKf(ctx, "Wrote profile %s to %s", p.Name, fn)
+	out.Printf(ctx, "Members trust it with '%s config profile.trusted-keys %s' (fingerprint %s)", s.Name, key, profile.Fingerprint(key))
+
+	return nil
+}
+
+// profileRemote returns the URL of the git remote of the store, if any.
+func (s *Action) profileRemote(ctx context.Context, mount string) string {
+	g, ok := local.Unwrap(s.Store.Storage(ctx, mount)).(gitConfigGetter)
+	if !ok {
+		return ""
+	}
+
+	remoteURL, err := g.ConfigGet(ctx, "remote.origin.url")
+	if err != nil {
+		debug.Log("no remote for %q: %s", mount, err)
+
+		return ""
+	}
+
+	return remoteURL
+}
+
+// profileKeyFile returns the file holding the key profiles are signed with.
+// The key is kept outside of the stores, otherwise every member who can
+// decrypt it could sign profiles.
+func profileKeyFile(ctx context.Context, fn string) string {
+	if fn == "" {
+		fn = config.String(ctx, "profile.signing-key")
+	}
+	if fn == "" {
+		return filepath.Join(appdir.UserConfig(), "profile-signing-key")
+	}
+
+	return fsutil.CleanPath(fn)
+}
+
+// profileSigningKey loads the signing key from the given file or creates a
+// new one.
+func (s *Action) profileSigningKey(ctx context.Context, fn string) (ed25519.PrivateKey, error) {
+	fn = profileKeyFile(ctx, fn)
+
+	buf, err := os.ReadFile(fn)
+	if err == nil {
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(buf)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%s is not a profile signing key", fn)
+		}
+
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	priv, err := profile.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(fn, []byte(base64.StdEncoding.EncodeToString(priv.Seed())+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to save signing key to %s: %w", fn, err)
+	}
+
+	out.Noticef(ctx, "Created a new profile signing key in %s. Keep it private, everybody who can read it can sign profiles", fn)
+
+	return priv, nil
+}
+
+// ProfileImport verifies a profile and applies it: missing stores are
+// cloned, templates written and the policy settings configured.
+func (s *Action) ProfileImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().First()
+	if fn == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s profile import [--key <key>] <file>", s.Name)
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to read profile %s: %s", fn, err)
+	}
+
+	p, key, err := profile.Parse(buf)
+	if err != nil {
+		return exit.Error(exit.Unsupported, err, "Failed to verify profile: %s", err)
+	}
+
+	if err := s.profileTrust(ctx, c, key); err != nil {
+		return err
+	}
+
+	n := s.profileMounts(ctx, p)
+
+	for _, name := range set.SortedKeys(p.Templates) {
+		if content, err := s.Store.GetTemplate(ctx, name); err == nil && strings.TrimSpace(string(content)) == strings.TrimSpace(p.Templates[name]) {
+			continue
+		}
+
+		if err := s.Store.SetTemplate(ctx, name, []byte(p.Templates[name])); err != nil {
+			out.Errorf(ctx, "Failed to write template %s: %s", name, err)
+
+			continue
+		}
+		n++
+	}
+
+	for _, k := range set.SortedKeys(p.Config) {
+		if s.cfg.IsSet(k) && s.cfg.Get(k) == p.Config[k] {
+			continue
+		}
+
+		if err := s.cfg.Set("", k, p.Config[k]); err != nil {
+			return exit.Error(exit.Config, err, "failed to set %s: %s", k, err)
+		}
+		n++
+	}
+
+	out.OKf(ctx, "Applied profile %s (%d changes)", p.Name, n)
+
+	return s.profileCheckSchemas(ctx, p)
+}
+
+// profileTrust makes sure the profile was signed by a trusted key. Unknown
+// keys can be trusted interactively.
+func (s *Action) profileTrust(ctx context.Context, c *cli.Context, key string) error {
+	if c.IsSet("key") {
+		if c.String("key") != key {
+			return exit.Error(exit.Unsupported, profile.ErrUntrusted, "profile is signed by %s, not by the given key", profile.Fingerprint(key))
+		}
+
+		return nil
+	}
+
+	trusted := config.String(ctx, "profile.trusted-keys")
+	if profile.Trusted(key, trusted) {
+		return nil
+	}
+
+	// an unknown key must always be confirmed explicitly.
+	if !ctxutil.IsInteractive(ctx) || ctxutil.IsAlwaysYes(ctx) ||
+		!termio.AskForConfirmation(ctx, fmt.Sprintf("The profile is signed by the unknown key %s. Trust it?", profile.Fingerprint(key))) {
+		return exit.Error(exit.Aborted, profile.ErrUntrusted, "profile is signed by the untrusted key %s. Use --key to trust it", profile.Fingerprint(key))
+	}
+
+	if trusted != "" {
+		key = trusted + "," + key
+	}
+
+	if err := s.cfg.Set("", "profile.trusted-keys", key); err != nil {
+		return exit.Error(exit.Config, err, "failed to save trusted key: %s", err)
+	}
+
+	return nil
+}
+
+// profileMounts clones the stores of the profile that don't exist yet and
+// returns the number of stores cloned.
+func (s *Action) profileMounts(ctx context.Context, p *profile.Profile) int {
+	mounts := p.Mounts
+	// the root store has to be cloned before anything can be mounted.
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return mounts[i].Alias == "" && mounts[j].Alias != ""
+	})
+
+	var n int
+	for _, m := range mounts {
+		name := storeName(m.Alias)
+
+		_, exists := s.Store.Mounts()[m.Alias]
+		if m.Alias == "" {
+			inited, err := s.Store.IsInitialized(ctxutil.WithGitInit(ctx, false))
+			if err != nil {
+				out.Errorf(ctx, "Failed to check the root store: %s", err)
+
+				continue
+			}
+			exists = inited
+		}
+
+		if exists {
+			if have := s.profileRemote(ctx, m.Alias); m.Remote != "" && have != m.Remote {
+				out.Warningf(ctx, "The remote of %s is %q, the profile uses %q", name, have, m.Remote)
+			}
+
+			continue
+		}
+
+		if m.Remote == "" {
+			out.Warningf(ctx, "The profile has no remote for %s, skipping it", name)
+
+			continue
+		}
+
+		cctx := ctx
+		if m.Crypto != "" {
+			cctx = backend.WithCryptoBackendString(cctx, m.Crypto)
+		}
+		if m.Storage != "" {
+			cctx = backend.WithStorageBackendString(cctx, m.Storage)
+		}
+
+		if err := s.clone(cctx, m.Remote, m.Alias, ""); err != nil {
+			out.Errorf(ctx, "Failed to clone %s: %s", name, err)
+
+			continue
+		}
+		n++
+
+		if m.Alias == "" {
+			s.Store = root.New(s.cfg)
+		}
+	}
+
+	return n
+}
+
+// profileCheckSchemas reports the entries that don't match the schemas of the
+// profile.
+func (s *Action) profileCheckSchemas(ctx context.Context, p *profile.Profile) error {
+	if len(p.Schemas) < 1 {
+		return nil
+	}
+
+	bp := &blueprint.Blueprint{Name: p.Name}
+	for _, dir := range set.SortedKeys(p.Schemas) {
+		bp.Folders = append(bp.Folders, blueprint.Folder{
+			Path:   path.Clean(dir),
+			Schema: blueprint.Schema{Required: p.Schemas[dir]},
+		})
+	}
+
+	diffs, err := bp.Diff(ctx, s.Store, "")
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to check schemas: %s", err)
+	}
+
+	for _, d := range diffs {
+		out.Warningf(ctx, "%s", d)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/profile"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	bfn := filepath.Join(dir, "blueprint.yml")
+	require.NoError(t, os.WriteFile(bfn, []byte("name: team\nfolders:\n  - path: web\n    schema:\n      required: [username]\n"), 0o600))
+
+	require.NoError(t, act.Store.SetTemplate(ctx, "web", []byte("username: \n")))
+	require.NoError(t, act.cfg.Set("", "generate.length", "32"))
+	require.NoError(t, act.cfg.Set("", "core.post-hook", "/bin/true"))
+
+	fn := filepath.Join(dir, "team.profile")
+	require.NoError(t, act.ProfileExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"name": "team", "blueprint": bfn}, fn)))
+	// the signing key never ends up in the shared store.
+	kfn := profileKeyFile(ctx, "")
+	assert.FileExists(t, kfn)
+	assert.NotContains(t, kfn, u.StoreDir(""))
+	assert.False(t, act.Store.Exists(ctx, "gopass/profile-signing-key"))
+
+	pbuf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	p, key, err := profile.Parse(pbuf)
+	require.NoError(t, err)
+	assert.Equal(t, "team", p.Name)
+	assert.Equal(t, "username: \n", p.Templates["web"])
+	assert.Equal(t, []string{"username"}, p.Schemas["web"])
+	assert.Equal(t, "32", p.Config["generate.length"])
+	assert.NotContains(t, p.Config, "core.post-hook")
+	require.Len(t, p.Mounts, 1)
+	assert.Equal(t, "", p.Mounts[0].Alias)
+
+	t.Run("the same key signs the next export", func(t *testing.T) {
+		require.NoError(t, act.ProfileExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"name": "team"}, filepath.Join(dir, "again.profile"))))
+
+		again, err := os.ReadFile(filepath.Join(dir, "again.profile"))
+		require.NoError(t, err)
+		_, key2, err := profile.Parse(again)
+		require.NoError(t, err)
+		assert.Equal(t, key, key2)
+	})
+
+	t.Run("another signing key", func(t *testing.T) {
+		other := filepath.Join(dir, "other-key")
+		require.NoError(t, act.ProfileExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"name": "team", "signing-key": other}, filepath.Join(dir, "other.profile"))))
+		assert.FileExists(t, other)
+
+		buf, err := os.ReadFile(filepath.Join(dir, "other.profile"))
+		require.NoError(t, err)
+		_, key2, err := profile.Parse(buf)
+		require.NoError(t, err)
+		assert.NotEqual(t, key, key2)
+	})
+
+	t.Run("untrusted keys are rejected", func(t *testing.T) {
+		assert.Error(t, act.ProfileImport(gptest.CliCtx(ctx, t, fn)))
+		assert.Error(t, act.ProfileImport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"key": "AAAA"}, fn)))
+	})
+
+	t.Run("import restores the configuration", func(t *testing.T) {
+		sec := secrets.NewAKV()
+		sec.SetPassword("foo")
+		require.NoError(t, act.Store.Set(ctx, "web/example.org", sec))
+		require.NoError(t, act.Store.RemoveTemplate(ctx, "web"))
+		require.NoError(t, act.cfg.Set("", "generate.length", "16"))
+		buf.Reset()
+
+		require.NoError(t, act.cfg.Set("", "profile.trusted-keys", key))
+		require.NoError(t, act.ProfileImport(gptest.CliCtx(ctx, t, fn)))
+		assert.Contains(t, buf.String(), "Applied profile team (2 changes)")
+		assert.Contains(t, buf.String(), "web/example.org: required key username is missing")
+		assert.Equal(t, "32", act.cfg.Get("generate.length"))
+		assert.True(t, act.Store.HasTemplate(ctx, "web"))
+	})
+}
@@ -0,0 +1,74 @@
+package action
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Protect makes revealing an entry require a tap on a FIDO2 token.
+func (s *Action) Protect(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s protect [--remove] <entry>", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) {
+		return exit.Error(exit.NotFound, nil, "Secret %s not found", name)
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Changed FIDO2 protection")
+
+	if c.Bool("remove") {
+		if err := s.Store.Unprotect(ctx, name); err != nil {
+			return exit.Error(exit.Unknown, err, "failed to unprotect %s: %s", name, err)
+		}
+
+		out.OKf(ctx, "%s is no longer protected by a FIDO2 token", name)
+
+		return nil
+	}
+
+	cred, err := s.fido2Credential(ctx)
+	if err != nil {
+		return exit.Error(exit.Unknown, err, "failed to get FIDO2 credential: %s", err)
+	}
+
+	if err := s.Store.Protect(ctx, name, cred); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to protect %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "Revealing %s now requires a tap on your FIDO2 token", name)
+
+	return nil
+}
+
+// fido2Credential returns the configured credential or creates a new one.
+func (s *Action) fido2Credential(ctx context.Context) ([]byte, error) {
+	if v := s.cfg.Get("fido2.credential"); v != "" {
+		cred, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fido2.credential: %w", err)
+		}
+
+		return cred, nil
+	}
+
+	cred, err := fido2.New(ctx).MakeCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Set("", "fido2.credential", base64.StdEncoding.EncodeToString(cred)); err != nil {
+		return nil, fmt.Errorf("failed to save fido2.credential: %w", err)
+	}
+
+	return cred, nil
+}
@@ -34,6 +34,10 @@ func GetCommands() []*cli.Command {
 					Aliases: []string{"y"},
 					Usage:   "Include at least one symbol in the password.",
 				},
+				&cli.StringFlag{
+					Name:  "layout-safe",
+					Usage: "Only use characters that are typed the same on a US keyboard and the given layouts, e.g. de or fr,uk",
+				},
 				&cli.BoolFlag{
 					Name:    "one-per-line",
 					Aliases: []string{"1"},
@@ -53,8 +57,30 @@ func GetCommands() []*cli.Command {
 				&cli.StringFlag{
 					Name:    "lang",
 					Aliases: []string{"xkcdlang", "xl"},
-					Usage:   "Language to generate password from, currently only en (english, default) or de are supported",
-					Value:   "en",
+					Usage:   "Language to generate password from: de, en, es, fr, it, nl or pt. Default: The language of the locale, if supported, or en",
+				},
+				&cli.BoolFlag{
+					Name:    "capitalize",
+					Aliases: []string{"xkcdcapitalize", "xc"},
+					Usage:   "Capitalize the first letter of each word of generated xkcd style passwords",
+				},
+				&cli.BoolFlag{
+					Name:    "ascii",
+					Aliases: []string{"xkcdascii", "xa"},
+					Usage:   "Replace letters with diacritics, e.g. é by e, in generated xkcd style passwords",
+				},
+				&cli.StringFlag{
+					Name:    "generator",
+					Aliases: []string{"g"},
+					Usage:   "Generate tokens instead of passwords, use one of: uuid, hex, base64 or b58",
+				},
+				&cli.StringFlag{
+					Name:  "prefix",
+					Usage: "Prepend this prefix to each password, e.g. sk_live_ for API keys. It does not count towards the length",
+				},
+				&cli.BoolFlag{
+					Name:  "insecure-rng-ok",
+					Usage: "Generate passwords even if the system random number generator looks unsafe",
 				},
 			},
 		},
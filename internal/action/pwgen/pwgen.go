@@ -6,6 +6,7 @@ package pwgen
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/out"
@@ -39,16 +40,32 @@ func Pwgen(c *cli.Context) error {
 		}
 	}
 
+	if err := pwgen.CheckRNG(); err != nil {
+		if !c.Bool("insecure-rng-ok") {
+			return exit.Error(exit.Unsupported, err, "Refusing to generate passwords: %s. Use --insecure-rng-ok to override", err)
+		}
+		out.Warningf(c.Context, "%s. Generating anyway since --insecure-rng-ok is set", err)
+	}
+
 	if c.Bool("xkcd") {
 		return xkcdGen(c, pwNum)
 	}
 
+	if gen := c.String("generator"); gen != "" {
+		return tokenGen(c, gen, pwLen, pwNum)
+	}
+
 	return pwGen(c, pwLen, pwNum)
 }
 
 func xkcdGen(c *cli.Context, num int) error {
 	for i := 0; i < num; i++ {
-		s, err := xkcdgen.RandomLengthDelim(4, c.String("sep"), c.String("lang"))
+		s, err := xkcdgen.RandomLengthOpts(4, xkcdgen.Options{
+			Delim:      c.String("sep"),
+			Lang:       c.String("lang"),
+			Capitalize: c.Bool("capitalize"),
+			ASCII:      c.Bool("ascii"),
+		})
 		if err != nil {
 			return err
 		}
@@ -58,6 +75,18 @@ func xkcdGen(c *cli.Context, num int) error {
 	return nil
 }
 
+func tokenGen(c *cli.Context, format string, pwLen, pwNum int) error {
+	for i := 0; i < pwNum; i++ {
+		s, err := pwgen.GenerateToken(format, pwLen)
+		if err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+		out.Print(c.Context, c.String("prefix")+s)
+	}
+
+	return nil
+}
+
 func pwGen(c *cli.Context, pwLen, pwNum int) error {
 	ctx := c.Context
 
@@ -85,10 +114,21 @@ func pwGen(c *cli.Context, pwLen, pwNum int) error {
 		charset += pwgen.Syms
 	}
 
+	if layouts := c.String("layout-safe"); layouts != "" {
+		cs, err := pwgen.LayoutSafe(charset, strings.Split(layouts, ",")...)
+		if err != nil {
+			return exit.Error(exit.Usage, err, "%s", err)
+		}
+		if cs == "" {
+			return exit.Error(exit.Usage, nil, "no characters left that are safe to type on %s", layouts)
+		}
+		charset = cs
+	}
+
 	for i := 0; i < pwNum; i++ {
 		for j := 0; j < perLine; j++ {
 			ctx := out.WithNewline(ctx, false)
-			out.Print(ctx, pwgen.GeneratePasswordCharset(pwLen, charset))
+			out.Print(ctx, c.String("prefix")+pwgen.GeneratePasswordCharset(pwLen, charset))
 			out.Print(ctx, " ")
 		}
 		out.Print(ctx, "")
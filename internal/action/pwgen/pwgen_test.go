@@ -28,3 +28,19 @@ func TestPwgen(t *testing.T) {
 	assert.NoError(t, Pwgen(gptest.CliCtxWithFlags(ctx, t, map[string]string{"one-per-line": "true"}, "24", "1")))
 	assert.True(t, len(buf.Bytes()) >= 24, string(buf.Bytes()))
 }
+
+func TestPwgenToken(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	assert.NoError(t, Pwgen(gptest.CliCtxWithFlags(ctx, t, map[string]string{"generator": "hex", "prefix": "sk_live_"}, "16", "2")))
+	assert.Regexp(t, `^sk_live_[0-9a-f]{16}\nsk_live_[0-9a-f]{16}\n$`, buf.String())
+	buf.Reset()
+
+	assert.Error(t, Pwgen(gptest.CliCtxWithFlags(ctx, t, map[string]string{"generator": "rot13"}, "16", "1")))
+}
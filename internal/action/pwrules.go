@@ -0,0 +1,76 @@
+package action
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/urfave/cli/v2"
+)
+
+// PwRulesShow prints the password rule, the aliases and the change URL that
+// apply to a domain, i.e. what generate uses for entries named after it.
+func (s *Action) PwRulesShow(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	domain := c.Args().First()
+	if domain == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s pwrules show <domain>", s.Name)
+	}
+
+	// accept URLs and entry names, too.
+	if u, err := url.Parse(domain); err == nil && u.Host != "" {
+		domain = u.Hostname()
+	} else if strings.Contains(domain, "/") {
+		if d, _ := hasPwRuleForSecret(ctx, domain); d != "" {
+			domain = d
+		}
+	}
+
+	fmt.Fprintf(stdout, "Domain:      %s\n", domain)
+
+	rule, match, found := pwrules.LookupRuleDomain(ctx, domain)
+	switch {
+	case !found:
+		fmt.Fprintf(stdout, "Rule:        none, generate uses the default generator\n")
+	case rule.String() == "":
+		fmt.Fprintf(stdout, "Rule:        no requirements\n")
+	default:
+		fmt.Fprintf(stdout, "Rule:        %s\n", rule)
+	}
+
+	if found && match != domain {
+		fmt.Fprintf(stdout, "Rule of:     %s\n", match)
+	}
+
+	if found {
+		fmt.Fprintf(stdout, "Characters:  %s\n", pwgen.NewCrypticForDomain(ctx, 0, domain).Chars)
+	}
+
+	custom := make(map[string]bool)
+	for _, a := range pwrules.LookupCustomAliases(ctx, domain) {
+		custom[a] = true
+	}
+
+	aliases := make([]string, 0, len(pwrules.LookupAliases(ctx, domain)))
+	for _, a := range pwrules.LookupAliases(ctx, domain) {
+		if custom[a] {
+			a += " (custom)"
+		}
+		aliases = append(aliases, a)
+	}
+
+	if len(aliases) > 0 {
+		fmt.Fprintf(stdout, "Aliases:     %s\n", strings.Join(aliases, ", "))
+	}
+
+	if u := pwrules.LookupChangeURL(ctx, domain); u != "" {
+		fmt.Fprintf(stdout, "Change URL:  %s\n", u)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPwRulesShow(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "domain-alias.dkb.de.insteadOf", "my-bank.de"))
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+	}()
+
+	t.Run("domain with rule", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.PwRulesShow(gptest.CliCtx(ctx, t, "dkb.de")))
+		assert.Contains(t, buf.String(), "Domain:      dkb.de\n")
+		assert.Contains(t, buf.String(), "Rule:        minlength: 8; maxlength: 38;")
+		assert.Contains(t, buf.String(), "Characters:  ")
+	})
+
+	t.Run("custom alias", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.PwRulesShow(gptest.CliCtx(ctx, t, "https://my-bank.de/login")))
+		assert.Contains(t, buf.String(), "Domain:      my-bank.de\n")
+		assert.Contains(t, buf.String(), "Rule of:     dkb.de\n")
+		assert.Contains(t, buf.String(), "Aliases:     dkb.de (custom)\n")
+	})
+
+	t.Run("entry name", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.PwRulesShow(gptest.CliCtx(ctx, t, "websites/dkb.de/john")))
+		assert.Contains(t, buf.String(), "Domain:      dkb.de\n")
+	})
+
+	t.Run("unknown domain", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.PwRulesShow(gptest.CliCtx(ctx, t, "example.org")))
+		assert.Contains(t, buf.String(), "Rule:        none")
+	})
+
+	t.Run("no domain", func(t *testing.T) {
+		assert.Error(t, act.PwRulesShow(gptest.CliCtx(ctx, t)))
+	})
+}
@@ -3,6 +3,7 @@ package action
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/cui"
@@ -67,11 +68,88 @@ func (s *Action) RecipientsComplete(c *cli.Context) {
 	}
 }
 
-// RecipientsAck updates `recipients.hash`.
+// RecipientsAck updates `recipients.hash` and acknowledges that we can still
+// decrypt the stores.
 func (s *Action) RecipientsAck(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
 
-	return s.Store.SaveRecipients(ctxutil.WithHidden(ctx, true), true)
+	if err := s.Store.SaveRecipients(ctxutil.WithHidden(ctx, true), true); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, mp := range s.recipientsStores(c) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil {
+			return exit.Error(exit.Mount, err, "failed to get store %s: %s", storeName(mp), err)
+		}
+
+		acked, err := sub.AckAccess(ctx)
+		if err != nil {
+			out.Errorf(ctx, "[%s] Failed to acknowledge access: %s", storeName(mp), err)
+			failed++
+
+			continue
+		}
+
+		out.OKf(ctx, "[%s] Acknowledged access for %s", storeName(mp), strings.Join(acked, ", "))
+	}
+
+	if failed > 0 {
+		return exit.Error(exit.Recipients, nil, "failed to acknowledge access to %d stores", failed)
+	}
+
+	return nil
+}
+
+// RecipientsStatus shows which recipients confirmed that they can decrypt the
+// stores since the recipients were changed last.
+func (s *Action) RecipientsStatus(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	var missing int
+	for _, mp := range s.recipientsStores(c) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil {
+			return exit.Error(exit.Mount, err, "failed to get store %s: %s", storeName(mp), err)
+		}
+
+		acks, err := sub.AccessStatus(ctx)
+		if err != nil {
+			return exit.Error(exit.Recipients, err, "failed to read acknowledgements of %s: %s", storeName(mp), err)
+		}
+
+		out.Printf(ctx, "%s:", storeName(mp))
+		for _, ack := range acks {
+			if ack.Date.IsZero() {
+				missing++
+				out.Printf(ctx, "  ❌ %s has not confirmed access since the last change", ack.Recipient)
+
+				continue
+			}
+
+			var verified string
+			if !ack.Verified {
+				verified = " (not verified)"
+			}
+			out.Printf(ctx, "  ✅ %s confirmed access on %s%s", ack.Recipient, ack.Date.Local().Format("2006-01-02 15:04"), verified)
+		}
+	}
+
+	if missing > 0 {
+		out.Noticef(ctx, "%d recipients have not confirmed access. Ask them to run 'gopass recipients ack'", missing)
+	}
+
+	return nil
+}
+
+// recipientsStores returns the store given with --store or all stores.
+func (s *Action) recipientsStores(c *cli.Context) []string {
+	if c.IsSet("store") {
+		return []string{c.String("store")}
+	}
+
+	return append([]string{""}, s.Store.MountPoints()...)
 }
 
 // RecipientsAdd adds new recipients.
@@ -79,7 +157,7 @@ func (s *Action) RecipientsAdd(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
 	store := c.String("store")
 	force := c.Bool("force")
-	added := 0
+	var added []string
 
 	// select store.
 	if store == "" {
@@ -141,13 +219,13 @@ func (s *Action) RecipientsAdd(c *cli.Context) error {
 		if err := s.Store.AddRecipient(ctx, store, recp); err != nil {
 			return exit.Error(exit.Recipients, err, "failed to add recipient %q: %s", r, err)
 		}
-		added++
+		added = append(added, recp)
 	}
-	if added < 1 {
+	if len(added) < 1 {
 		return exit.Error(exit.Unknown, nil, "no key added")
 	}
 
-	out.Printf(ctx, "\nAdded %d recipients", added)
+	out.Printf(ctx, "\nAdded %d recipients", len(added))
 	out.Printf(ctx, "You need to run 'gopass sync' to push these changes")
 
 	return nil
@@ -158,7 +236,7 @@ func (s *Action) RecipientsRemove(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
 	store := c.String("store")
 	force := c.Bool("force")
-	removed := 0
+	var removed []string
 
 	// select store if none is given.
 	if !c.IsSet("store") {
@@ -198,7 +276,7 @@ func (s *Action) RecipientsRemove(c *cli.Context) error {
 				return exit.Error(exit.Recipients, err, "failed to remove recipient %q: %s", r, err)
 			}
 
-			removed++
+			removed = append(removed, r)
 
 			continue
 		}
@@ -239,14 +317,14 @@ func (s *Action) RecipientsRemove(c *cli.Context) error {
 		}
 
 		fmt.Fprintf(stdout, removalWarning, r)
-		removed++
+		removed = append(removed, recp)
 	}
 
-	if removed < 1 {
+	if len(removed) < 1 {
 		return exit.Error(exit.Unknown, nil, "no key removed")
 	}
 
-	out.Printf(ctx, "\nRemoved %d recipients", removed)
+	out.Printf(ctx, "\nRemoved %d recipients", len(removed))
 	out.Printf(ctx, "You need to run 'gopass sync' to push these changes")
 
 	return nil
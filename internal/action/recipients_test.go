@@ -57,6 +57,22 @@ func TestRecipients(t *testing.T) {
 		assert.Equal(t, want, buf.String())
 	})
 
+	t.Run("ack and status", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.RecipientsStatus(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "❌ 0xDEADBEEF has not confirmed access since the last change")
+
+		buf.Reset()
+		require.NoError(t, act.RecipientsAck(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "Acknowledged access for 0xDEADBEEF")
+
+		buf.Reset()
+		require.NoError(t, act.RecipientsStatus(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "✅ 0xDEADBEEF confirmed access on ")
+		assert.NotContains(t, buf.String(), "not verified")
+	})
+
 	t.Run("add recipients w/o args", func(t *testing.T) {
 		defer buf.Reset()
 		assert.Error(t, act.RecipientsAdd(gptest.CliCtx(ctx, t)))
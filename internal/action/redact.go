@@ -0,0 +1,56 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// RecipientsRedact shows or sets how a folder is listed for users that can't
+// decrypt it.
+func (s *Action) RecipientsRedact(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	dir := strings.TrimSuffix(c.Args().Get(0), leaf.Sep)
+	if dir == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s recipients redact <folder> [%s|none]", s.Name, strings.Join(leaf.RedactModes, "|"))
+	}
+
+	if !s.Store.IsDir(ctx, dir) {
+		return exit.Error(exit.NotFound, nil, "folder %q not found", dir)
+	}
+
+	if c.Args().Len() < 2 {
+		mode := s.Store.FolderRedaction(ctx, dir)
+		if mode == "" {
+			mode = "none"
+		}
+		fmt.Fprintln(stdout, mode)
+
+		return nil
+	}
+
+	mode := strings.ToLower(c.Args().Get(1))
+	if mode == "none" {
+		mode = ""
+	}
+
+	if err := s.Store.SetFolderRedaction(ctx, dir, mode); err != nil {
+		return exit.Error(exit.Recipients, err, "failed to set redaction of %s: %s", dir, err)
+	}
+
+	if mode == "" {
+		out.OKf(ctx, "%s is no longer redacted", dir)
+
+		return nil
+	}
+
+	out.OKf(ctx, "%s is listed as %q for users without access", dir, mode)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipientsRedact(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("123")
+	require.NoError(t, act.Store.Set(ctx, "ops/db", sec))
+
+	assert.Error(t, act.RecipientsRedact(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "missing")))
+	assert.Error(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "ops", "hidden")))
+
+	assert.NoError(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "ops/")))
+	assert.Equal(t, "none\n", buf.String())
+	buf.Reset()
+
+	assert.NoError(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "ops", "Count")))
+	assert.Equal(t, leaf.RedactCount, act.Store.FolderRedaction(ctx, "ops"))
+	buf.Reset()
+
+	assert.NoError(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "ops")))
+	assert.Equal(t, "count\n", buf.String())
+	buf.Reset()
+
+	assert.NoError(t, act.RecipientsRedact(gptest.CliCtx(ctx, t, "ops", "none")))
+	assert.Equal(t, "", act.Store.FolderRedaction(ctx, "ops"))
+}
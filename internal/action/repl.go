@@ -6,20 +6,23 @@ import (
 	"strings"
 
 	"github.com/chzyer/readline"
+	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/out"
-	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/debug"
 	shellquote "github.com/kballard/go-shellquote"
 	"github.com/urfave/cli/v2"
 )
 
-func (s *Action) entriesForCompleter(ctx context.Context) ([]readline.PrefixCompleterInterface, error) {
+func (s *Action) entriesForCompleter(ctx context.Context, rs *shellRestrictions) ([]readline.PrefixCompleterInterface, error) {
 	args := []readline.PrefixCompleterInterface{}
-	list, err := s.Store.List(ctx, tree.INF)
+	list, err := s.Store.CachedList(ctx)
 	if err != nil {
 		return args, err
 	}
 	for _, v := range list {
+		if rs != nil && !rs.allowed(v) {
+			continue
+		}
 		args = append(args, readline.PcItem(v))
 	}
 
@@ -56,8 +59,8 @@ func (s *Action) replCompleteTemplates(ctx context.Context, cmd *cli.Command) []
 	return args
 }
 
-func (s *Action) prefixCompleter(c *cli.Context) *readline.PrefixCompleter {
-	secrets, err := s.entriesForCompleter(c.Context)
+func (s *Action) prefixCompleter(c *cli.Context, rs *shellRestrictions) *readline.PrefixCompleter {
+	secrets, err := s.entriesForCompleter(c.Context, rs)
 	if err != nil {
 		debug.Log("failed to list secrets: %s", err)
 	}
@@ -66,6 +69,9 @@ func (s *Action) prefixCompleter(c *cli.Context) *readline.PrefixCompleter {
 		if cmd.Hidden {
 			continue
 		}
+		if rs != nil && rs.commands[cmd.Name] == "" {
+			continue
+		}
 		subCmds := []readline.PrefixCompleterInterface{}
 		switch cmd.Name {
 		case "config":
@@ -109,8 +115,18 @@ func (s *Action) prefixCompleter(c *cli.Context) *readline.PrefixCompleter {
 }
 
 // REPL implements a read-execute-print-line shell
-// with readline support and autocompletion.
+// with readline support and autocompletion. In restricted mode only a few
+// read-only commands on a few folders are allowed, see shellRestrictions.
 func (s *Action) REPL(c *cli.Context) error {
+	var rs *shellRestrictions
+	if restrictedShell(c.Context, c.Bool("restricted")) {
+		r, err := newShellRestrictions(c.Context)
+		if err != nil {
+			return exit.Error(exit.Config, err, "failed to start the restricted shell: %s", err)
+		}
+		rs = r
+	}
+
 	c.App.ExitErrHandler = func(c *cli.Context, err error) {
 		if err == nil {
 			return
@@ -120,7 +136,11 @@ func (s *Action) REPL(c *cli.Context) error {
 
 	out.Printf(c.Context, logo)
 	out.Printf(c.Context, "🌟 Welcome to gopass!")
-	out.Printf(c.Context, "⚠ This is the built-in shell. Type 'help' for a list of commands.")
+	if rs != nil {
+		out.Printf(c.Context, "⚠ This is the restricted shell. Allowed commands: %s", strings.Join(rs.names(), ", "))
+	} else {
+		out.Printf(c.Context, "⚠ This is the built-in shell. Type 'help' for a list of commands.")
+	}
 
 	rl, err := readline.New("gopass> ")
 	if err != nil {
@@ -139,7 +159,7 @@ READ:
 			return fmt.Errorf("user aborted")
 		default:
 		}
-		rl.Config.AutoComplete = s.prefixCompleter(c)
+		rl.Config.AutoComplete = s.prefixCompleter(c, rs)
 		line, err := rl.Readline()
 		if err != nil {
 			debug.Log("Readline error: %s", err)
@@ -169,6 +189,21 @@ READ:
 		default:
 		}
 
+		if rs != nil {
+			lines, err := rs.commandLines(args)
+			if err != nil {
+				out.Errorf(c.Context, "%s", err)
+
+				continue
+			}
+
+			for _, line := range lines {
+				_ = c.App.RunContext(WithRestrictedShell(c.Context, true), append([]string{"gopass"}, line...))
+			}
+
+			continue
+		}
+
 		if err := c.App.RunContext(c.Context, append([]string{"gopass"}, args...)); err != nil {
 			continue
 		}
@@ -178,7 +213,9 @@ READ:
 }
 
 func (s *Action) replLock(ctx context.Context) {
-	if err := s.Store.Lock(); err != nil {
+	// the REPL lock only wipes the caches. Unlike gopass lock it's not
+	// recorded for auto-locking.
+	if err := s.Store.Lock(ctx); err != nil {
 		out.Errorf(ctx, "Failed to lock stores: %s", err)
 
 		return
@@ -0,0 +1,285 @@
+package action
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/editor"
+	"github.com/gopasspw/gopass/internal/names"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// restructureSep separates the old and the new name in a restructure plan.
+const restructureSep = " -> "
+
+// restructureMove is a single rename of a restructure plan.
+type restructureMove struct {
+	From string
+	To   string
+}
+
+// restructureUndoFile returns the location of the plan that reverts the last
+// restructure.
+func restructureUndoFile() string {
+	return filepath.Join(appdir.UserState(), "restructure-undo.txt")
+}
+
+// Restructure moves entries for websites into another layout. It proposes a
+// plan, lets the user edit it and renames all entries at once. The reverse
+// plan is kept, so --undo can revert the last run.
+func (s *Action) Restructure(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if c.Bool("undo") {
+		return s.restructureUndo(ctx)
+	}
+
+	layout := c.String("to")
+	if layout == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s restructure --to <%s> [folder]", s.Name, strings.Join(names.Layouts, "|"))
+	}
+
+	entries, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	moves, err := s.restructureProposal(ctx, entries, layout, strings.TrimSuffix(c.Args().First(), "/"))
+	if err != nil {
+		return exit.Error(exit.Usage, err, "%s", err)
+	}
+
+	if len(moves) < 1 {
+		out.OKf(ctx, "All entries already use the %s layout", layout)
+
+		return nil
+	}
+
+	plan := restructureFormat(fmt.Sprintf("Restructuring to %s.", layout), moves)
+
+	if c.Bool("dry-run") {
+		fmt.Fprint(stdout, string(plan))
+
+		return nil
+	}
+
+	if ctxutil.IsInteractive(ctx) && ctxutil.IsTerminal(ctx) && !ctxutil.IsAlwaysYes(ctx) {
+		plan, err = editor.Invoke(ctx, editor.Path(c), plan)
+		if err != nil {
+			return exit.Error(exit.Unknown, err, "failed to edit the plan: %s", err)
+		}
+	}
+
+	return s.restructureApply(ctx, plan, entries, false)
+}
+
+// restructureProposal maps all entries below folder, that are for a website,
+// to their names in the given layout. The mount of an entry is kept.
+func (s *Action) restructureProposal(ctx context.Context, entries []string, layout, folder string) ([]restructureMove, error) {
+	var moves []restructureMove
+
+	for _, e := range entries {
+		if folder != "" && !strings.HasPrefix(e, folder+"/") {
+			continue
+		}
+
+		mp := s.Store.MountPoint(e)
+		site, ok := names.ParseSite(strings.TrimPrefix(strings.TrimPrefix(e, mp), "/"))
+		if !ok {
+			debug.Log("not restructuring %s: not a website", e)
+
+			continue
+		}
+
+		n, err := site.Name(layout)
+		if err != nil {
+			return nil, err
+		}
+
+		if n = path.Join(mp, n); n != e {
+			moves = append(moves, restructureMove{From: e, To: n})
+		}
+	}
+
+	return moves, nil
+}
+
+// restructureFormat renders a plan for the editor.
+func restructureFormat(title string, moves []restructureMove) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s Each line renames an entry:\n", title)
+	fmt.Fprintf(&buf, "#   <old name>%s<new name>\n", restructureSep)
+	fmt.Fprintf(&buf, "# Change the new names or remove lines to keep entries where they are.\n")
+	for _, m := range moves {
+		fmt.Fprintf(&buf, "%s%s%s\n", m.From, restructureSep, m.To)
+	}
+
+	return buf.Bytes()
+}
+
+// restructureParse parses and validates a plan. Every entry must exist and
+// the new names must neither exist nor be used twice.
+func restructureParse(plan []byte, entries []string) ([]restructureMove, error) {
+	existing := set.Map(entries)
+	targets := make(map[string]string, len(entries))
+
+	var moves []restructureMove
+
+	sc := bufio.NewScanner(bytes.NewReader(plan))
+	for i := 1; sc.Scan(); i++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		from, to, found := strings.Cut(line, strings.TrimSpace(restructureSep))
+		if !found {
+			return nil, fmt.Errorf("line %d: expected <old name>%s<new name>", i, restructureSep)
+		}
+
+		m := restructureMove{From: strings.TrimSpace(from), To: strings.Trim(strings.TrimSpace(to), "/")}
+		switch {
+		case m.From == m.To:
+			continue
+		case m.To == "":
+			return nil, fmt.Errorf("line %d: the new name of %s is empty", i, m.From)
+		case !existing[m.From]:
+			return nil, fmt.Errorf("line %d: %s does not exist", i, m.From)
+		case existing[m.To]:
+			return nil, fmt.Errorf("line %d: %s already exists", i, m.To)
+		case targets[m.To] != "":
+			return nil, fmt.Errorf("line %d: %s and %s would both be moved to %s", i, targets[m.To], m.From, m.To)
+		}
+
+		targets[m.To] = m.From
+		moves = append(moves, m)
+	}
+
+	return moves, sc.Err()
+}
+
+// restructureApply performs all moves of the plan and saves the plan that
+// reverts them. When undoing, the moves that were not reverted are kept in
+// the undo plan instead, so a failed undo can be resumed.
+func (s *Action) restructureApply(ctx context.Context, plan []byte, entries []string, undo bool) error {
+	moves, err := restructureParse(plan, entries)
+	if err != nil {
+		return exit.Error(exit.Usage, err, "invalid plan: %s", err)
+	}
+
+	if len(moves) < 1 {
+		out.Noticef(ctx, "Nothing to do")
+
+		return nil
+	}
+
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Rename %d entries?", len(moves))) {
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	done := make([]restructureMove, 0, len(moves))
+	var moveErr error
+	for _, m := range moves {
+		if err := s.Store.Move(ctx, m.From, m.To); err != nil {
+			moveErr = fmt.Errorf("failed to move %s to %s: %w", m.From, m.To, err)
+
+			break
+		}
+		debug.Log("moved %s to %s", m.From, m.To)
+		done = append(done, m)
+	}
+
+	if undo {
+		return s.restructureFinishUndo(ctx, moves[len(done):], moveErr)
+	}
+
+	if err := restructureSaveUndo(restructureReverse(done)); err != nil {
+		out.Errorf(ctx, "Failed to save the undo plan: %s", err)
+	}
+
+	if moveErr != nil {
+		return exit.Error(exit.Unknown, moveErr, "%s. Run '%s restructure --undo' to revert the %d entries renamed so far", moveErr, s.Name, len(done))
+	}
+
+	out.OKf(ctx, "Renamed %d entries. Run '%s restructure --undo' to revert", len(done), s.Name)
+
+	return nil
+}
+
+// restructureFinishUndo removes the undo plan once all of it has been
+// applied. Otherwise it only keeps the moves that are left.
+func (s *Action) restructureFinishUndo(ctx context.Context, left []restructureMove, moveErr error) error {
+	if moveErr == nil {
+		// drop the plan, so --undo can't be run twice by accident.
+		if err := os.Remove(restructureUndoFile()); err != nil {
+			debug.Log("failed to remove %s: %s", restructureUndoFile(), err)
+		}
+
+		out.OKf(ctx, "Reverted the last restructure")
+
+		return nil
+	}
+
+	if err := restructureSaveUndo(left); err != nil {
+		out.Errorf(ctx, "Failed to save the undo plan: %s", err)
+	}
+
+	return exit.Error(exit.Unknown, moveErr, "%s. Run '%s restructure --undo' to revert the %d remaining entries", moveErr, s.Name, len(left))
+}
+
+// restructureReverse returns the moves that revert the given moves.
+func restructureReverse(moves []restructureMove) []restructureMove {
+	undo := make([]restructureMove, 0, len(moves))
+	for i := len(moves) - 1; i >= 0; i-- {
+		undo = append(undo, restructureMove{From: moves[i].To, To: moves[i].From})
+	}
+
+	return undo
+}
+
+// restructureSaveUndo saves the given undo plan.
+func restructureSaveUndo(undo []restructureMove) error {
+	fn := restructureUndoFile()
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fn, restructureFormat("Reverting the last restructure.", undo), 0o600)
+}
+
+// restructureUndo reverts the last restructure.
+func (s *Action) restructureUndo(ctx context.Context) error {
+	fn := restructureUndoFile()
+
+	plan, err := os.ReadFile(fn)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return exit.Error(exit.NotFound, err, "nothing to undo")
+		}
+
+		return exit.Error(exit.IO, err, "failed to read %s: %s", fn, err)
+	}
+
+	entries, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	return s.restructureApply(ctx, plan, entries, true)
+}
@@ -0,0 +1,111 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestructure(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	for _, name := range []string{"websites/github.com/alice", "websites/bob@gitlab.com", "websites/example.org"} {
+		sec := secrets.NewAKV()
+		sec.SetPassword("pw")
+		require.NoError(t, act.Store.Set(ctx, name, sec))
+	}
+
+	assert.Error(t, act.Restructure(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.Restructure(gptest.CliCtxWithFlags(ctx, t, map[string]string{"to": "nested"})))
+	buf.Reset()
+
+	t.Run("dry-run", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Restructure(gptest.CliCtxWithFlags(ctx, t, map[string]string{"to": "flat", "dry-run": "true"})))
+		assert.Contains(t, buf.String(), "websites/github.com/alice -> websites/alice@github.com\n")
+		assert.NotContains(t, buf.String(), "gitlab.com ->")
+		assert.True(t, act.Store.Exists(ctx, "websites/github.com/alice"))
+	})
+
+	t.Run("restructure and undo", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Restructure(gptest.CliCtxWithFlags(ctx, t, map[string]string{"to": "folder-per-site"}, "websites")))
+		assert.True(t, act.Store.Exists(ctx, "websites/gitlab.com/bob"))
+		assert.False(t, act.Store.Exists(ctx, "websites/bob@gitlab.com"))
+
+		require.NoError(t, act.Restructure(gptest.CliCtxWithFlags(ctx, t, map[string]string{"undo": "true"})))
+		assert.True(t, act.Store.Exists(ctx, "websites/bob@gitlab.com"))
+		assert.False(t, act.Store.Exists(ctx, "websites/gitlab.com/bob"))
+
+		assert.Error(t, act.Restructure(gptest.CliCtxWithFlags(ctx, t, map[string]string{"undo": "true"})))
+	})
+
+	t.Run("failed undo keeps the remaining moves", func(t *testing.T) {
+		defer buf.Reset()
+
+		left := []restructureMove{{From: "b", To: "a"}, {From: "d", To: "c"}}
+		require.Error(t, act.restructureFinishUndo(ctx, left, fmt.Errorf("failed")))
+
+		plan, err := os.ReadFile(restructureUndoFile())
+		require.NoError(t, err)
+		moves, err := restructureParse(plan, []string{"b", "d"})
+		require.NoError(t, err)
+		assert.Equal(t, left, moves)
+
+		require.NoError(t, act.restructureFinishUndo(ctx, nil, nil))
+		assert.NoFileExists(t, restructureUndoFile())
+	})
+}
+
+func TestRestructureParse(t *testing.T) {
+	t.Parallel()
+
+	entries := []string{"a/github.com", "b/github.com", "c"}
+
+	moves, err := restructureParse([]byte("# comment\n\na/github.com -> github.com/a\nc -> c\n"), entries)
+	require.NoError(t, err)
+	assert.Equal(t, []restructureMove{{From: "a/github.com", To: "github.com/a"}}, moves)
+
+	for _, plan := range []string{
+		"a/github.com github.com\n",
+		"a/github.com -> \n",
+		"missing -> foo\n",
+		"a/github.com -> c\n",
+		"a/github.com -> github.com\nb/github.com -> github.com\n",
+	} {
+		_, err := restructureParse([]byte(plan), entries)
+		assert.Error(t, err, plan)
+	}
+}
@@ -0,0 +1,240 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// ReviewList lists all changes waiting for the approval of a folder owner
+// and all pushes to protected stores waiting for approval.
+func (s *Action) ReviewList(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	ps, err := s.Store.ListPending(ctx)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list pending changes: %s", err)
+	}
+
+	sps := s.listStaged(ctx)
+
+	if len(ps) < 1 && len(sps) < 1 {
+		out.Printf(ctx, "No pending changes")
+
+		return nil
+	}
+
+	for _, p := range ps {
+		out.Printf(ctx, "%s %-6s %s by %s (%s)", p.ID, p.Op, pendingName(p), p.Author, p.Created.Local().Format("2006-01-02 15:04"))
+	}
+
+	for _, sp := range sps {
+		out.Printf(ctx, "%s push   %s by %s (%s, %d/%d approvals)", sp.ID, storeName(sp.Store), sp.StagedBy, sp.Date.Local().Format("2006-01-02 15:04"), len(sp.Approvals), sp.Required)
+	}
+
+	return nil
+}
+
+// ReviewShow displays a pending change.
+func (s *Action) ReviewShow(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s review show <id>", s.Name)
+	}
+
+	p, sec, err := s.Store.GetPending(ctx, id)
+	if errors.Is(err, leaf.ErrNoPending) {
+		if sp := s.findStaged(ctx, id); sp != nil {
+			printStaged(ctx, sp)
+
+			return nil
+		}
+	}
+	if err != nil {
+		return exit.Error(exit.NotFound, err, "failed to read pending change %s: %s", id, err)
+	}
+
+	out.Printf(ctx, "Change:  %s", p.ID)
+	out.Printf(ctx, "Op:      %s", p.Op)
+	out.Printf(ctx, "Secret:  %s", pendingName(p))
+	out.Printf(ctx, "Author:  %s", p.Author)
+	out.Printf(ctx, "Owners:  %s", strings.Join(p.Owners, ", "))
+	if p.Message != "" {
+		out.Printf(ctx, "Message: %s", p.Message)
+	}
+
+	if sec == nil {
+		return nil
+	}
+
+	out.Printf(ctx, "")
+	fmt.Fprint(stdout, string(sec.Bytes()))
+
+	return nil
+}
+
+// ReviewApprove applies a pending change. Only owners of the folder can
+// approve it. Pushes to protected stores are merged once they have enough
+// approvals.
+func (s *Action) ReviewApprove(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s review approve <id>", s.Name)
+	}
+
+	p, err := s.Store.ApprovePending(ctx, id)
+	if errors.Is(err, leaf.ErrNoPending) {
+		if sp := s.findStaged(ctx, id); sp != nil {
+			return s.approveStaged(ctx, sp)
+		}
+	}
+	if err != nil {
+		return reviewError(err, "approve", id)
+	}
+
+	out.OKf(ctx, "Approved %s %s", p.Op, pendingName(p))
+
+	return nil
+}
+
+// ReviewReject discards a pending change. Owners of the folder and the
+// author of the change can reject it.
+func (s *Action) ReviewReject(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s review reject <id>", s.Name)
+	}
+
+	p, err := s.Store.RejectPending(ctx, id)
+	if err != nil {
+		return reviewError(err, "reject", id)
+	}
+
+	out.OKf(ctx, "Rejected %s %s", p.Op, pendingName(p))
+
+	return nil
+}
+
+func reviewError(err error, op, id string) error {
+	switch {
+	case errors.Is(err, leaf.ErrNoPending):
+		return exit.Error(exit.NotFound, err, "no pending change %s", id)
+	case errors.Is(err, leaf.ErrNotOwner):
+		return exit.Error(exit.Aborted, err, "only an owner can %s change %s", op, id)
+	case errors.Is(err, store.ErrSelfApproval):
+		return exit.Error(exit.Aborted, err, "push %s must be approved by another recipient", id)
+	case errors.Is(err, store.ErrStagedUnsigned):
+		return exit.Error(exit.Aborted, err, "push %s was not signed by a recipient of the store. Do not approve it", id)
+	case errors.Is(err, store.ErrStagedOutdated):
+		return exit.Error(exit.Aborted, err, "push %s is outdated. It has to be synced again: %s", id, err)
+	default:
+		return exit.Error(exit.Unknown, err, "failed to %s change %s: %s", op, id, err)
+	}
+}
+
+// stagedPush is a push to a protected store waiting for approval.
+type stagedPush struct {
+	backend.Staged
+
+	Store    string
+	Required int
+	sub      *leaf.Store
+}
+
+// listStaged returns the pushes waiting for approval in all protected
+// stores.
+func (s *Action) listStaged(ctx context.Context) []*stagedPush {
+	var res []*stagedPush
+
+	for _, mp := range append([]string{""}, s.Store.MountPoints()...) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil || !sub.IsProtected(ctx) {
+			continue
+		}
+
+		sts, err := sub.ListStaged(ctx)
+		if err != nil {
+			out.Errorf(ctx, "[%s] Failed to list staged pushes: %s", storeName(mp), err)
+
+			continue
+		}
+
+		for _, st := range sts {
+			res = append(res, &stagedPush{
+				Staged:   st,
+				Store:    mp,
+				Required: sub.RequiredApprovals(ctx),
+				sub:      sub,
+			})
+		}
+	}
+
+	return res
+}
+
+// findStaged returns the staged push with the given ID or nil.
+func (s *Action) findStaged(ctx context.Context, id string) *stagedPush {
+	for _, sp := range s.listStaged(ctx) {
+		if sp.ID == id || (len(id) >= 7 && strings.HasPrefix(sp.Commit, id)) {
+			return sp
+		}
+	}
+
+	return nil
+}
+
+func (s *Action) approveStaged(ctx context.Context, sp *stagedPush) error {
+	st, merged, err := sp.sub.ApproveStaged(ctx, sp.ID)
+	if err != nil {
+		return reviewError(err, "approve", sp.ID)
+	}
+
+	if !merged {
+		out.OKf(ctx, "Approved push %s to %s (%d/%d approvals)", st.ID, storeName(sp.Store), len(st.Approvals), sp.Required)
+
+		return nil
+	}
+
+	s.Store.InvalidateNameIndex()
+	out.OKf(ctx, "Approved and merged push %s to %s", st.ID, storeName(sp.Store))
+
+	return nil
+}
+
+func printStaged(ctx context.Context, sp *stagedPush) {
+	out.Printf(ctx, "Push:      %s", sp.Commit)
+	out.Printf(ctx, "Store:     %s", storeName(sp.Store))
+	out.Printf(ctx, "Branch:    %s", sp.Branch)
+	out.Printf(ctx, "Staged by: %s", sp.StagedBy)
+	out.Printf(ctx, "Commits:   %d (latest: %s)", sp.Commits, sp.Subject)
+	out.Printf(ctx, "Approvals: %d/%d %s", len(sp.Approvals), sp.Required, strings.Join(sp.Approvals, ", "))
+}
+
+func storeName(mp string) string {
+	if mp == "" {
+		return "<root>"
+	}
+
+	return mp
+}
+
+func pendingName(p *leaf.Pending) string {
+	if p.Store == "" {
+		return p.Name
+	}
+
+	return path.Join(p.Store, p.Name)
+}
@@ -0,0 +1,90 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReview(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	// a folder owned by someone else.
+	require.NoError(t, os.MkdirAll(filepath.Join(u.StoreDir(""), "team"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(u.StoreDir(""), "team", leaf.OwnersFile), []byte("0xBADC0FFEE\n"), 0o600))
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, act.Store.Set(ctx, "team/db", sec))
+	assert.False(t, act.Store.Exists(ctx, "team/db"))
+
+	ps, err := act.Store.ListPending(ctx)
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	id := ps[0].ID
+	buf.Reset()
+
+	t.Run("list", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.ReviewList(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), id)
+		assert.Contains(t, buf.String(), "team/db")
+	})
+
+	t.Run("show", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.ReviewShow(gptest.CliCtx(ctx, t)))
+		require.NoError(t, act.ReviewShow(gptest.CliCtx(ctx, t, id)))
+		assert.Contains(t, buf.String(), "0xBADC0FFEE")
+		assert.True(t, strings.HasSuffix(buf.String(), "secret\n"), buf.String())
+	})
+
+	t.Run("approve by non-owner", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.ReviewApprove(gptest.CliCtx(ctx, t)))
+		assert.Error(t, act.ReviewApprove(gptest.CliCtx(ctx, t, id)))
+		assert.Error(t, act.ReviewApprove(gptest.CliCtx(ctx, t, "00000000")))
+		assert.False(t, act.Store.Exists(ctx, "team/db"))
+	})
+
+	t.Run("reject by author", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.ReviewReject(gptest.CliCtx(ctx, t, id)))
+
+		ps, err := act.Store.ListPending(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+	})
+}
@@ -0,0 +1,177 @@
+package action
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// pendingPasswordKey holds the staged password of a two-phase rotation.
+	pendingPasswordKey = "pending-password"
+	// pendingSinceKey records when the pending password was staged.
+	pendingSinceKey = "pending-since"
+)
+
+// Rotate implements a two-phase password rotation. The new password is first
+// staged next to the current one and only promoted to the password once it
+// has been applied upstream. This prevents lockouts if the upstream change
+// fails.
+func (s *Action) Rotate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	ctx = WithClip(ctx, c.Bool("clip"))
+
+	modes := 0
+	for _, f := range []string{"staged", "commit", "abort"} {
+		if c.Bool(f) {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return exit.Error(exit.Usage, nil, "Specify exactly one of --staged, --commit or --abort")
+	}
+
+	tagged, ok, err := s.taggedEntries(ctx, c)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list tagged entries: %s", err)
+	}
+	if ok {
+		return s.rotateTagged(ctx, c, tagged)
+	}
+
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s rotate --staged|--commit|--abort <entry> [length]", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) {
+		return exit.Error(exit.NotFound, nil, "Entry %q not found", name)
+	}
+
+	return s.rotateEntry(ctx, c, name, c.Args().Get(1))
+}
+
+// rotateTagged applies the selected rotation phase to all tagged entries. The
+// only argument is the optional password length. Failures are reported per
+// entry and do not stop the rotation of the remaining entries.
+func (s *Action) rotateTagged(ctx context.Context, c *cli.Context, names []string) error {
+	if len(names) < 1 {
+		return exit.Error(exit.NotFound, nil, "No entries tagged %s", strings.Join(c.StringSlice("tag"), ", "))
+	}
+
+	if c.Bool("clip") {
+		return exit.Error(exit.Usage, nil, "--clip can not be used with --tag")
+	}
+
+	var failed int
+	for _, name := range names {
+		if err := s.rotateEntry(ctx, c, name, c.Args().First()); err != nil {
+			out.Errorf(ctx, "Failed to rotate %q: %s", name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return exit.Error(exit.Unknown, nil, "failed to rotate %d of %d entries", failed, len(names))
+	}
+
+	return nil
+}
+
+func (s *Action) rotateEntry(ctx context.Context, c *cli.Context, name, length string) error {
+	switch {
+	case c.Bool("staged"):
+		return s.rotateStage(ctx, c, name, length)
+	case c.Bool("commit"):
+		return s.rotateCommit(ctx, c, name)
+	default:
+		return s.rotateAbort(ctx, name)
+	}
+}
+
+func (s *Action) rotateStage(ctx context.Context, c *cli.Context, name, length string) error {
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read %q: %s", name, err)
+	}
+
+	if _, found := sec.Get(pendingPasswordKey); found {
+		return exit.Error(exit.Aborted, nil, "%q already has a pending password. Use --commit or --abort first", name)
+	}
+
+	password, err := s.generatePassword(ctx, c, length, name)
+	if err != nil {
+		return err
+	}
+
+	_ = sec.Set(pendingPasswordKey, password)
+	_ = sec.Set(pendingSinceKey, time.Now().UTC().Format(time.RFC3339))
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Staged new password"), name, sec); err != nil {
+		return exit.Error(exit.Encrypt, err, "failed to save %q: %s", name, err)
+	}
+
+	if err := s.generateCopyOrPrint(ctx, c, name, pendingPasswordKey, password); err != nil {
+		return err
+	}
+
+	out.Noticef(ctx, "Apply the new password upstream, then run '%s rotate --commit %s'", s.Name, name)
+
+	return nil
+}
+
+func (s *Action) rotateCommit(ctx context.Context, c *cli.Context, name string) error {
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read %q: %s", name, err)
+	}
+
+	password, found := sec.Get(pendingPasswordKey)
+	if !found {
+		return exit.Error(exit.NotFound, nil, "%q has no pending password. Use '%s rotate --staged %s' first", name, s.Name, name)
+	}
+
+	keepOldPasswords(sec, c.Int("keep-old"))
+	sec.SetPassword(password)
+	_ = sec.Del(pendingPasswordKey)
+	_ = sec.Del(pendingSinceKey)
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Committed staged password"), name, sec); err != nil {
+		return exit.Error(exit.Encrypt, err, "failed to save %q: %s", name, err)
+	}
+
+	s.publishEntries(ctx, event.EntryRotated, name)
+	out.OKf(ctx, "Promoted the pending password of %q", name)
+
+	return nil
+}
+
+func (s *Action) rotateAbort(ctx context.Context, name string) error {
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read %q: %s", name, err)
+	}
+
+	if _, found := sec.Get(pendingPasswordKey); !found {
+		out.Noticef(ctx, "%q has no pending password", name)
+
+		return nil
+	}
+
+	_ = sec.Del(pendingPasswordKey)
+	_ = sec.Del(pendingSinceKey)
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Discarded staged password"), name, sec); err != nil {
+		return exit.Error(exit.Encrypt, err, "failed to save %q: %s", name, err)
+	}
+
+	out.OKf(ctx, "Discarded the pending password of %q", name)
+
+	return nil
+}
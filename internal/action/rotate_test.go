@@ -0,0 +1,168 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestRotate(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	require.NoError(t, act.cfg.Set("", "core.autoclip", "false"))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("current")
+	require.NoError(t, sec.Set("user", "admin"))
+	require.NoError(t, act.Store.Set(ctx, "db", sec))
+
+	t.Run("no mode", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Rotate(gptest.CliCtx(ctx, t, "db")))
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"staged": "true"}, "nope")))
+	})
+
+	t.Run("commit without pending", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"commit": "true"}, "db")))
+	})
+
+	var pending string
+	t.Run("staged", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"staged": "true"}, "db", "24")))
+
+		sec, err := act.Store.Get(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, "current", sec.Password())
+
+		var found bool
+		pending, found = sec.Get(pendingPasswordKey)
+		require.True(t, found)
+		assert.Len(t, pending, 24)
+
+		// staging again must fail
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"staged": "true"}, "db")))
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"commit": "true", "keep-old": "1"}, "db")))
+
+		sec, err := act.Store.Get(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, pending, sec.Password())
+
+		_, found := sec.Get(pendingPasswordKey)
+		assert.False(t, found)
+		_, found = sec.Get(pendingSinceKey)
+		assert.False(t, found)
+
+		var old []string
+		for _, k := range sec.Keys() {
+			if strings.HasPrefix(k, oldPasswordPrefix) {
+				v, _ := sec.Get(k)
+				old = append(old, v)
+			}
+		}
+		assert.Equal(t, []string{"current"}, old)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"staged": "true"}, "db")))
+		require.NoError(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"abort": "true"}, "db")))
+
+		sec, err := act.Store.Get(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, pending, sec.Password())
+		_, found := sec.Get(pendingPasswordKey)
+		assert.False(t, found)
+	})
+	t.Run("tag", func(t *testing.T) {
+		defer buf.Reset()
+
+		for _, name := range []string{"web/a", "web/b"} {
+			sec := secrets.NewAKV()
+			sec.SetPassword("old")
+			require.NoError(t, sec.Set("tags", "quarterly"))
+			require.NoError(t, act.Store.Set(ctx, name, sec))
+		}
+
+		require.NoError(t, act.Rotate(rotateTagCtx(ctx, t, "quarterly", "--staged", "16")))
+		for _, name := range []string{"web/a", "web/b"} {
+			sec, err := act.Store.Get(ctx, name)
+			require.NoError(t, err)
+			pw, found := sec.Get(pendingPasswordKey)
+			require.True(t, found, name)
+			assert.Len(t, pw, 16)
+		}
+
+		// untagged entries are left alone.
+		sec, err := act.Store.Get(ctx, "db")
+		require.NoError(t, err)
+		_, found := sec.Get(pendingPasswordKey)
+		assert.False(t, found)
+
+		assert.Error(t, act.Rotate(rotateTagCtx(ctx, t, "nope", "--commit")))
+		assert.Error(t, act.Rotate(rotateTagCtx(ctx, t, "quarterly", "--commit", "--clip")))
+		require.NoError(t, act.Rotate(rotateTagCtx(ctx, t, "quarterly", "--commit")))
+		for _, name := range []string{"web/a", "web/b"} {
+			sec, err := act.Store.Get(ctx, name)
+			require.NoError(t, err)
+			assert.Len(t, sec.Password(), 16)
+		}
+	})
+}
+
+func rotateTagCtx(ctx context.Context, t *testing.T, tag string, args ...string) *cli.Context {
+	t.Helper()
+
+	fs := flag.NewFlagSet("default", flag.ContinueOnError)
+	for _, f := range []cli.Flag{
+		&cli.BoolFlag{Name: "staged"},
+		&cli.BoolFlag{Name: "commit"},
+		&cli.BoolFlag{Name: "clip"},
+		&cli.StringSliceFlag{Name: "tag"},
+	} {
+		require.NoError(t, f.Apply(fs))
+	}
+	require.NoError(t, fs.Parse(append([]string{"--tag", tag}, args...)))
+
+	c := cli.NewContext(cli.NewApp(), fs, nil)
+	c.Context = ctx
+
+	return c
+}
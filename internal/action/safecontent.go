@@ -0,0 +1,64 @@
+package action
+
+import (
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// safeContentPolicy decides which keys of a secret are obstructed on output.
+// Keys listed in safecontent.mask are always obstructed, keys listed in
+// safecontent.show are never obstructed. Both can be set per mount.
+type safeContentPolicy struct {
+	mask map[string]bool
+	show map[string]bool
+}
+
+// safeContentPolicy returns the policy for the store the secret belongs to.
+func (s *Action) safeContentPolicy(name string) safeContentPolicy {
+	mp := s.Store.MountPoint(name)
+
+	return safeContentPolicy{
+		mask: splitKeys(s.cfg.GetM(mp, "safecontent.mask")),
+		show: splitKeys(s.cfg.GetM(mp, "safecontent.show")),
+	}
+}
+
+func splitKeys(in string) map[string]bool {
+	keys := make(map[string]bool, strings.Count(in, ",")+1)
+	for _, k := range strings.Split(in, ",") {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			keys[k] = true
+		}
+	}
+
+	return keys
+}
+
+// isMasked returns true if the key must always be obstructed.
+func (p safeContentPolicy) isMasked(key string) bool {
+	k := strings.ToLower(key)
+
+	return p.mask[k] && !p.show[k]
+}
+
+// isUnsafe returns true if the key must be obstructed in safecontent mode.
+func (p safeContentPolicy) isUnsafe(key string, sec gopass.Secret) bool {
+	k := strings.ToLower(key)
+	if p.show[k] {
+		return false
+	}
+
+	return p.mask[k] || isUnsafeKey(key, sec)
+}
+
+// masks returns true if any key of the secret must always be obstructed.
+func (p safeContentPolicy) masks(sec gopass.Secret) bool {
+	for _, k := range sec.Keys() {
+		if p.isMasked(k) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/selftest"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Selftest exercises the configured backends end to end in a throwaway store.
+func (s *Action) Selftest(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	// every commit must be done before the store is pushed.
+	ctx = queue.WithQueue(ctx, nil)
+
+	ctx, storageBe, recipient, err := s.selftestSetup(ctx, c.String("crypto"), c.String("storage"), c.String("recipient"))
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "gopass-selftest-")
+	if err != nil {
+		return exit.Error(exit.IO, err, "failed to create temp dir: %s", err)
+	}
+
+	if c.Bool("keep") {
+		out.Noticef(ctx, "Keeping the test store in %s", dir)
+	} else {
+		defer func() {
+			_ = os.RemoveAll(dir)
+		}()
+	}
+
+	out.Printf(ctx, "Running self test with crypto %s and storage %s for %s ...", backend.CryptoBackendName(backend.GetCryptoBackend(ctx)), storageBe, recipient)
+
+	res := selftest.Run(ctx, dir, recipient)
+	for _, r := range res {
+		switch {
+		case r.Err != nil:
+			out.Errorf(ctx, "%-10s FAILED after %s: %s", r.Step, r.Duration.Round(time.Millisecond), r.Err)
+		case r.Skipped:
+			out.Warningf(ctx, "%-10s skipped", r.Step)
+		default:
+			out.OKf(ctx, "%-10s %s", r.Step, r.Duration.Round(time.Millisecond))
+		}
+
+		if r.Diag != "" && (r.Err != nil || r.Skipped || ctxutil.IsVerbose(ctx)) {
+			for _, line := range strings.Split(r.Diag, "\n") {
+				out.Printf(ctx, "             %s", line)
+			}
+		}
+	}
+
+	if selftest.Failed(res) {
+		return exit.Error(exit.Unknown, nil, "self test failed. Please include the output above when reporting a bug")
+	}
+
+	out.OKf(ctx, "Self test passed")
+
+	return nil
+}
+
+// selftestSetup selects the backends and the recipient for the self test.
+// They default to the ones of the root store and the first private key.
+func (s *Action) selftestSetup(ctx context.Context, cryptoBe, storageBe, recipient string) (context.Context, string, string, error) {
+	if ok, _ := s.Store.IsInitialized(ctx); ok {
+		if cryptoBe == "" && s.Store.Crypto(ctx, "") != nil {
+			cryptoBe = s.Store.Crypto(ctx, "").Name()
+		}
+		if storageBe == "" && s.Store.Storage(ctx, "") != nil {
+			storageBe = s.Store.Storage(ctx, "").Name()
+		}
+	}
+
+	if cryptoBe != "" {
+		ctx = backend.WithCryptoBackendString(ctx, cryptoBe)
+	}
+	if storageBe == "" {
+		storageBe = "gitfs"
+	}
+	ctx = backend.WithStorageBackendString(ctx, storageBe)
+
+	if recipient != "" {
+		return ctx, storageBe, recipient, nil
+	}
+
+	crypto, err := backend.NewCrypto(ctx, backend.GetCryptoBackend(ctx))
+	if err != nil {
+		return ctx, "", "", exit.Error(exit.Unknown, err, "failed to initialize crypto backend: %s", err)
+	}
+
+	ids, err := crypto.ListIdentities(ctx)
+	if err != nil || len(ids) < 1 {
+		return ctx, "", "", exit.Error(exit.NoName, err, "no private key found. Use --recipient to select one")
+	}
+
+	return ctx, storageBe, ids[0], nil
+}
@@ -0,0 +1,252 @@
+package action
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// passExtensions maps pass extensions to their gopass counterparts.
+var passExtensions = map[string]string{
+	"audit":  "gopass audit",
+	"clip":   "gopass show --clip",
+	"import": "gopass convert",
+	"ln":     "gopass link",
+	"otp":    "gopass otp",
+	"tail":   "gopass show",
+	"update": "gopass rotate",
+}
+
+// passStore describes an existing store of the pass password manager.
+type passStore struct {
+	Path       string
+	Entries    int
+	Recipients []string
+	// Folders are the folders with their own .gpg-id.
+	Folders    []string
+	Git        bool
+	Extensions []string
+}
+
+// passStoreDir returns the location of the pass store.
+func passStoreDir() string {
+	if d := os.Getenv("PASSWORD_STORE_DIR"); d != "" {
+		return fsutil.CleanPath(d)
+	}
+
+	return filepath.Join(appdir.UserHome(), ".password-store")
+}
+
+// passExtensionDirs returns the directories pass loads extensions from.
+func passExtensionDirs(store string) []string {
+	dirs := []string{filepath.Join(store, ".extensions")}
+	if d := os.Getenv("PASSWORD_STORE_EXTENSIONS_DIR"); d != "" {
+		dirs[0] = d
+	}
+
+	return append(dirs, "/usr/lib/password-store/extensions", "/usr/local/lib/password-store/extensions")
+}
+
+// detectPassStore inspects the pass store in dir. It returns false if there
+// is none.
+func detectPassStore(dir string) (passStore, bool) {
+	ps := passStore{Path: dir}
+
+	buf, err := os.ReadFile(filepath.Join(dir, ".gpg-id"))
+	if err != nil {
+		debug.Log("no pass store at %s: %s", dir, err)
+
+		return ps, false
+	}
+	ps.Recipients = recipients.Unmarshal(buf).IDs()
+
+	ps.Git = fsutil.IsDir(filepath.Join(dir, ".git"))
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == ".extensions") {
+			return filepath.SkipDir
+		}
+
+		switch {
+		case strings.HasSuffix(d.Name(), ".gpg"):
+			ps.Entries++
+		case d.Name() == ".gpg-id" && filepath.Dir(path) != dir:
+			rel, err := filepath.Rel(dir, filepath.Dir(path))
+			if err == nil {
+				ps.Folders = append(ps.Folders, filepath.ToSlash(rel))
+			}
+		}
+
+		return nil
+	})
+	sort.Strings(ps.Folders)
+
+	for _, ed := range passExtensionDirs(dir) {
+		matches, _ := filepath.Glob(filepath.Join(ed, "*.bash"))
+		for _, m := range matches {
+			ps.Extensions = append(ps.Extensions, strings.TrimSuffix(filepath.Base(m), ".bash"))
+		}
+	}
+	sort.Strings(ps.Extensions)
+
+	return ps, true
+}
+
+// initDetectPass offers to import an existing pass store on the first run.
+// Without a config gopass uses the pass store as its root store, so pass and
+// gopass can be used side by side. Users may prefer to keep their pass store
+// separate, so they can mount it into a new gopass store or copy it instead.
+func (s *Action) initDetectPass(ctx context.Context) error {
+	if !s.cfg.FirstRun() || !ctxutil.IsInteractive(ctx) || !ctxutil.IsTerminal(ctx) {
+		return nil
+	}
+
+	ps, found := detectPassStore(passStoreDir())
+	if !found || fsutil.CleanPath(s.cfg.Path()) != ps.Path {
+		return nil
+	}
+
+	out.Printf(ctx, "🔎 Found an existing pass store at %s", ps.Path)
+	out.Printf(ctx, "   %d entries encrypted for %s", ps.Entries, strings.Join(ps.Recipients, ", "))
+	if !ps.Git {
+		out.Printf(ctx, "   It is not a git repository. Run 'gopass git init' to track changes")
+	}
+	if len(ps.Folders) > 0 {
+		out.Printf(ctx, "   Folders with their own recipients (.gpg-id): %s", strings.Join(ps.Folders, ", "))
+	}
+	for _, e := range ps.Extensions {
+		if alt, found := passExtensions[e]; found {
+			out.Printf(ctx, "   The pass extension %q is built in: use '%s'", e, alt)
+
+			continue
+		}
+		out.Printf(ctx, "   The pass extension %q has no gopass equivalent", e)
+	}
+
+	choices := []string{
+		"Use it in place (pass and gopass can be used side by side)",
+		"Mount it below pass/ in a new gopass store",
+		"Copy it into a new gopass store (the pass store is kept as is)",
+	}
+
+	act, sel := cui.GetSelection(ctx, "How do you want to use it?", choices)
+	debug.Log("Action: %s - Selection: %d", act, sel)
+
+	switch act {
+	case "default", "impossible":
+	default:
+		return exit.Error(exit.Aborted, nil, "user aborted")
+	}
+
+	if sel == 0 {
+		out.OKf(ctx, "Using the pass store at %s", ps.Path)
+
+		return nil
+	}
+
+	dst := filepath.Join(appdir.UserData(), "stores", "root")
+	if empty, err := fsutil.IsEmptyDir(dst); err == nil && !empty {
+		return exit.Error(exit.Aborted, nil, "%s is not empty", dst)
+	}
+
+	switch sel {
+	case 1:
+		return s.initPassMount(ctx, ps, dst)
+	default:
+		return s.initPassCopy(ctx, ps, dst)
+	}
+}
+
+// initPassMount creates a new root store at dst for the recipients of the
+// pass store and mounts the pass store below pass/.
+func (s *Action) initPassMount(ctx context.Context, ps passStore, dst string) error {
+	if err := s.initPassNewRoot(ctx, dst); err != nil {
+		return err
+	}
+
+	if err := s.Store.Init(ctx, "", dst, ps.Recipients...); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to initialize new store at %s: %s", dst, err)
+	}
+
+	if err := s.Store.AddMount(ctx, "pass", ps.Path); err != nil {
+		return exit.Error(exit.Mount, err, "failed to mount %s: %s", ps.Path, err)
+	}
+
+	out.OKf(ctx, "Created a new store at %s and mounted the pass store below pass/", dst)
+
+	return nil
+}
+
+// initPassCopy copies the pass store, including its recipients and git
+// history, to dst and uses the copy as the root store.
+func (s *Action) initPassCopy(ctx context.Context, ps passStore, dst string) error {
+	if err := copyDir(ps.Path, dst); err != nil {
+		return exit.Error(exit.IO, err, "failed to copy %s to %s: %s", ps.Path, dst, err)
+	}
+
+	if err := s.initPassNewRoot(ctx, dst); err != nil {
+		return err
+	}
+
+	out.OKf(ctx, "Copied the pass store to %s. The original at %s is no longer used by gopass", dst, ps.Path)
+
+	return nil
+}
+
+// initPassNewRoot switches the root store to dst.
+func (s *Action) initPassNewRoot(ctx context.Context, dst string) error {
+	if err := s.cfg.SetPath(dst); err != nil {
+		return exit.Error(exit.Config, err, "failed to set the store path: %s", err)
+	}
+
+	s.Store = root.New(s.cfg)
+	if _, err := s.Store.IsInitialized(ctx); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to open %s: %s", dst, err)
+	}
+
+	return nil
+}
+
+// copyDir copies the files in src to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		to := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(to, 0o700)
+		}
+
+		if !d.Type().IsRegular() {
+			debug.Log("not copying %s: not a regular file", path)
+
+			return nil
+		}
+
+		return fsutil.CopyFile(path, to)
+	})
+}
@@ -0,0 +1,56 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPassStore(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PASSWORD_STORE_DIR", dir)
+	t.Setenv("PASSWORD_STORE_EXTENSIONS_DIR", filepath.Join(dir, ".extensions"))
+
+	assert.Equal(t, dir, passStoreDir())
+
+	_, found := detectPassStore(dir)
+	assert.False(t, found)
+
+	for fn, content := range map[string]string{
+		".gpg-id":               "0xDEADBEEF\n",
+		"email/gmail.gpg":       "x",
+		"web/example.org.gpg":   "x",
+		"work/.gpg-id":          "0xDEADBEEF\n0xFEEDBEEF\n",
+		"work/vpn.gpg":          "x",
+		".extensions/otp.bash":  "",
+		".extensions/tomb.bash": "",
+		".git/config":           "",
+		".git/objects/ab.gpg":   "x",
+	} {
+		fn = filepath.Join(dir, fn)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fn), 0o700))
+		require.NoError(t, os.WriteFile(fn, []byte(content), 0o600))
+	}
+
+	ps, found := detectPassStore(dir)
+	require.True(t, found)
+	assert.Equal(t, 3, ps.Entries)
+	assert.Equal(t, []string{"0xDEADBEEF"}, ps.Recipients)
+	assert.Equal(t, []string{"work"}, ps.Folders)
+	assert.True(t, ps.Git)
+	assert.Contains(t, ps.Extensions, "otp")
+	assert.Contains(t, ps.Extensions, "tomb")
+
+	t.Run("copy", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "root")
+		require.NoError(t, copyDir(dir, dst))
+
+		cp, found := detectPassStore(dst)
+		require.True(t, found)
+		assert.Equal(t, ps.Entries, cp.Entries)
+		assert.Equal(t, ps.Folders, cp.Folders)
+	})
+}
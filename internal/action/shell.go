@@ -0,0 +1,186 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+)
+
+// restrictedCommands are the read-only commands that can be allowed in the
+// restricted shell, with their aliases. All of them take an entry or folder
+// as argument, so access can be limited to path prefixes.
+var restrictedCommands = map[string][]string{
+	"history": {"hist"},
+	"list":    {"ls"},
+	"otp":     nil,
+	"show":    nil,
+}
+
+// restrictedFlags are the flags that can be used with the restricted commands.
+// Flags that write files, scan the screen or print other entries are left out.
+// Flags ending with "=" take a value.
+var restrictedFlags = map[string][]string{
+	"history": {"password", "p"},
+	"list":    {"limit=", "l=", "flat", "f", "folders", "d", "strip-prefix", "s"},
+	"otp":     {"clip", "c", "password", "o"},
+	"show": {
+		"clip", "c", "alsoclip", "C", "unsafe", "u", "force", "f", "password", "o",
+		"strict", "revision=", "r=", "noparsing", "n", "chars=", "spell",
+	},
+}
+
+// restrictedFlag returns whether the flag can be used with the command and
+// whether it takes a value.
+func restrictedFlag(cmd, name string) (bool, bool) {
+	for _, f := range restrictedFlags[cmd] {
+		if f == name {
+			return true, false
+		}
+		if f == name+"=" {
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+// defaultRestrictedCommands are allowed unless shell.restricted-commands is
+// set.
+var defaultRestrictedCommands = []string{"list", "otp", "show"}
+
+// shellRestrictions limit the built-in shell to a few read-only commands and
+// to the entries below a few folders, e.g. for operators on a shared jump
+// host. The login shell of these users should be gopass shell --restricted.
+type shellRestrictions struct {
+	commands map[string]string // alias or name -> name.
+	prefixes []string
+}
+
+// restrictedShell returns true if the shell must run in restricted mode.
+func restrictedShell(ctx context.Context, flag bool) bool {
+	return flag || config.Bool(ctx, "shell.restricted")
+}
+
+// newShellRestrictions reads the allowed commands and folders from the
+// config. It fails if no folders are configured, a restricted shell must
+// never fall back to the whole store.
+func newShellRestrictions(ctx context.Context) (*shellRestrictions, error) {
+	r := &shellRestrictions{
+		commands: map[string]string{},
+	}
+
+	cmds := defaultRestrictedCommands
+	if cv := config.String(ctx, "shell.restricted-commands"); cv != "" {
+		cmds = strings.Split(cv, ",")
+	}
+	for _, cmd := range cmds {
+		cmd = strings.ToLower(strings.TrimSpace(cmd))
+		aliases, found := restrictedCommands[cmd]
+		if !found {
+			out.Warningf(ctx, "Ignoring %q in shell.restricted-commands. Only these read-only commands are supported: %s", cmd, strings.Join(set.SortedKeys(restrictedCommands), ", "))
+
+			continue
+		}
+
+		r.commands[cmd] = cmd
+		for _, a := range aliases {
+			r.commands[a] = cmd
+		}
+	}
+
+	for _, p := range strings.Split(config.String(ctx, "shell.restricted-paths"), ",") {
+		p = strings.Trim(path.Clean("/"+strings.TrimSpace(p)), "/")
+		if p == "" {
+			continue
+		}
+		r.prefixes = append(r.prefixes, p)
+	}
+	sort.Strings(r.prefixes)
+
+	if len(r.prefixes) < 1 {
+		return nil, fmt.Errorf("shell.restricted-paths is not set")
+	}
+
+	return r, nil
+}
+
+// allowed returns true if the entry or folder is below one of the prefixes.
+func (r *shellRestrictions) allowed(name string) bool {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	for _, p := range r.prefixes {
+		if name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commandLines checks a line entered in the restricted shell and returns the
+// command lines to run. Only the flags in restrictedFlags are passed through.
+// The first argument must be an allowed entry or folder, the following ones
+// are keys inside of it. A list without a folder lists every allowed folder.
+func (r *shellRestrictions) commandLines(args []string) ([][]string, error) {
+	if len(args) < 1 {
+		return nil, nil
+	}
+
+	cmd, found := r.commands[strings.ToLower(args[0])]
+	if !found {
+		return nil, fmt.Errorf("%q is not allowed in the restricted shell. Allowed commands: %s", args[0], strings.Join(r.names(), ", "))
+	}
+
+	var hasPath bool
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		if strings.HasPrefix(a, "-") {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			found, takesValue := restrictedFlag(cmd, name)
+			if !found || (hasValue && !takesValue) {
+				return nil, fmt.Errorf("%s %s is not allowed in the restricted shell", cmd, a)
+			}
+			if takesValue && !hasValue {
+				// skip the value.
+				i++
+			}
+
+			continue
+		}
+
+		if hasPath {
+			continue
+		}
+
+		if !r.allowed(a) {
+			return nil, fmt.Errorf("access to %q is not allowed in the restricted shell", a)
+		}
+		hasPath = true
+	}
+
+	if hasPath {
+		return [][]string{append([]string{cmd}, args[1:]...)}, nil
+	}
+
+	if cmd != "list" {
+		return nil, fmt.Errorf("%s needs an entry", cmd)
+	}
+
+	lines := make([][]string, 0, len(r.prefixes))
+	for _, p := range r.prefixes {
+		line := append([]string{cmd}, args[1:]...)
+		lines = append(lines, append(line, p))
+	}
+
+	return lines, nil
+}
+
+// names returns the names of the allowed commands.
+func (r *shellRestrictions) names() []string {
+	return set.SortedKeys(r.commands)
+}
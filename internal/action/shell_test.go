@@ -0,0 +1,86 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellRestrictions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	_, err := newShellRestrictions(ctx)
+	require.Error(t, err, "no paths configured")
+
+	require.NoError(t, cfg.SetEnv("shell.restricted-paths", "ops/db, ops/web/"))
+	rs, err := newShellRestrictions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"list", "ls", "otp", "show"}, rs.names())
+
+	for _, tc := range []struct {
+		line []string
+		want [][]string
+	}{
+		{[]string{"show", "ops/db/root"}, [][]string{{"show", "ops/db/root"}}},
+		{[]string{"show", "-c", "ops/web"}, [][]string{{"show", "-c", "ops/web"}}},
+		{[]string{"ls"}, [][]string{{"list", "ops/db"}, {"list", "ops/web"}}},
+		{[]string{"ls", "--flat"}, [][]string{{"list", "--flat", "ops/db"}, {"list", "--flat", "ops/web"}}},
+		{[]string{"ls", "-l", "2"}, [][]string{{"list", "-l", "2", "ops/db"}, {"list", "-l", "2", "ops/web"}}},
+		{[]string{"show", "--revision", "-2", "ops/db/root"}, [][]string{{"show", "--revision", "-2", "ops/db/root"}}},
+		{[]string{"show", "-r=-2", "-u", "ops/db/root"}, [][]string{{"show", "-r=-2", "-u", "ops/db/root"}}},
+		{[]string{"show", "ops/db/root", "user"}, [][]string{{"show", "ops/db/root", "user"}}},
+	} {
+		got, err := rs.commandLines(tc.line)
+		require.NoError(t, err, tc.line)
+		assert.Equal(t, tc.want, got, tc.line)
+	}
+
+	for _, line := range [][]string{
+		{"delete", "ops/db/root"},
+		{"show", "ops/dbx"},
+		{"show", "ops/db/../../private"},
+		{"show", "--qr", "ops/db/root"},
+		{"show", "--render=/tmp/x", "ops/db/root"},
+		{"show", "--clip=false", "ops/db/root"},
+		{"show", "--", "ops/db/root"},
+		{"otp", "--snip", "ops/db/root"},
+		{"otp", "-q", "/tmp/qr.png", "ops/db/root"},
+		{"otp", "export", "ops/db"},
+		{"ls", "--tag", "x"},
+		{"show"},
+		{"--yes", "show", "ops/db/root"},
+	} {
+		_, err := rs.commandLines(line)
+		assert.Error(t, err, line)
+	}
+
+	t.Run("configured commands", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, cfg.SetEnv("shell.restricted-commands", "show,history,delete"))
+		rs, err := newShellRestrictions(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hist", "history", "show"}, rs.names())
+		assert.Contains(t, buf.String(), `Ignoring "delete"`)
+	})
+
+	assert.False(t, restrictedShell(ctx, false))
+	assert.True(t, restrictedShell(ctx, true))
+	require.NoError(t, cfg.SetEnv("shell.restricted", "true"))
+	assert.True(t, restrictedShell(ctx, false))
+}
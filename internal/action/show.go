@@ -1,18 +1,26 @@
 package action
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/demo"
 	"github.com/gopasspw/gopass/internal/hook"
 	"github.com/gopasspw/gopass/internal/notify"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/spell"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/internal/tpl"
+	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/clipboard"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -24,7 +32,7 @@ import (
 )
 
 func showParseArgs(c *cli.Context) context.Context {
-	ctx := ctxutil.WithGlobalFlags(c)
+	ctx := root.WithKeyIndex(ctxutil.WithGlobalFlags(c), true)
 	if c.IsSet("clip") {
 		ctx = WithOnlyClip(ctx, c.Bool("clip"))
 	}
@@ -41,6 +49,11 @@ func showParseArgs(c *cli.Context) context.Context {
 		ctx = WithPasswordOnly(ctx, c.Bool("password"))
 	}
 
+	if c.Bool("strict") {
+		ctx = WithStrict(ctx, true)
+		ctx = WithPasswordOnly(ctx, true)
+	}
+
 	if c.IsSet("revision") {
 		ctx = WithRevision(ctx, c.String("revision"))
 	}
@@ -53,6 +66,18 @@ func showParseArgs(c *cli.Context) context.Context {
 		ctx = ctxutil.WithShowParsing(ctx, !c.Bool("noparsing"))
 	}
 
+	if c.IsSet("render") {
+		ctx = WithRender(ctx, c.String("render"))
+	}
+
+	if c.IsSet("as") {
+		ctx = WithRenderAs(ctx, c.String("as"))
+	}
+
+	if c.IsSet("spell") {
+		ctx = WithSpell(ctx, c.Bool("spell"))
+	}
+
 	if c.IsSet("chars") {
 		iv := []int{}
 		for _, v := range strings.Split(c.String("chars"), ",") {
@@ -79,12 +104,17 @@ func (s *Action) Show(c *cli.Context) error {
 
 	ctx := showParseArgs(c)
 
+	if c.Bool("json") || c.Bool("all") {
+		return s.showBatch(ctx, c)
+	}
+
 	if key := c.Args().Get(1); key != "" {
 		debug.Log("Adding key to ctx: %s", key)
 		ctx = WithKey(ctx, key)
 	}
 
-	if err := s.show(ctx, c, name, true); err != nil {
+	// scripts must never end up with the result of a fuzzy search.
+	if err := s.show(ctx, c, name, !IsStrict(ctx)); err != nil {
 		return exit.Error(exit.Decrypt, err, "%s", err)
 	}
 
@@ -98,6 +128,14 @@ func (s *Action) show(ctx context.Context, c *cli.Context, name string, recurse
 	}
 
 	if s.Store.IsDir(ctx, name) && !s.Store.Exists(ctx, name) {
+		if IsStrict(ctx) {
+			return exit.Error(exit.NotFound, store.ErrNotFound, "%s is a folder, not a secret", name)
+		}
+
+		if isRender(ctx) {
+			return s.showRenderFolder(ctx, name)
+		}
+
 		return s.List(c)
 	}
 
@@ -175,9 +213,59 @@ func (s *Action) showHandleOutputChars(ctx context.Context, pw string, chars []i
 	return nil
 }
 
+// showHandleOutputSpell prints the password spelled out, one character per
+// line.
+func (s *Action) showHandleOutputSpell(ctx context.Context, pw string) error {
+	if pw == "" {
+		if config.Bool(ctx, "core.showsafecontent") && !ctxutil.IsForce(ctx) {
+			out.Warning(ctx, "core.showsafecontent=true. Use -f to spell out the password")
+		}
+
+		return exit.Error(exit.NotFound, store.ErrNoPassword, store.ErrNoPassword.Error())
+	}
+
+	printSpelled(ctx, pw)
+
+	return nil
+}
+
+// printSpelled prints the password spelled out using the NATO phonetic
+// alphabet, in groups of four characters.
+func printSpelled(ctx context.Context, pw string) {
+	for _, line := range spell.Lines(pw, 4) {
+		out.Print(ctx, out.Secret(line))
+	}
+}
+
 // showHandleOutput displays a secret.
 func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.Secret) error {
-	pw, body, err := s.showGetContent(ctx, sec)
+	if ctxutil.IsDemo(ctx) {
+		name, sec = demo.Name(name), demo.Secret(sec)
+	}
+
+	if IsStrict(ctx) {
+		pw, err := strictPassword(ctx, sec)
+		if err != nil {
+			return exit.Error(exit.NotFound, err, "%s: %s", name, err)
+		}
+
+		fmt.Fprint(stdout, pw)
+
+		return nil
+	}
+
+	if isRender(ctx) {
+		buf, err := s.render(ctx, name, sec, false)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(stdout, string(buf))
+
+		return nil
+	}
+
+	pw, body, err := s.showGetContent(ctx, sec, s.safeContentPolicy(name))
 	if err != nil {
 		return err
 	}
@@ -186,6 +274,10 @@ func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.S
 		return s.showHandleOutputChars(ctx, pw, chars)
 	}
 
+	if IsSpell(ctx) {
+		return s.showHandleOutputSpell(ctx, pw)
+	}
+
 	if pw == "" && body == "" {
 		if config.Bool(ctx, "core.showsafecontent") && !ctxutil.IsForce(ctx) {
 			out.Warning(ctx, "core.showsafecontent=true. Use -f to display password, if any")
@@ -225,7 +317,76 @@ func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.S
 	return nil
 }
 
-func (s *Action) showGetContent(ctx context.Context, sec gopass.Secret) (string, string, error) {
+// isRender returns true if the secret is rendered through a template
+// (--render) or for a built-in target (--as).
+func isRender(ctx context.Context) bool {
+	return HasRender(ctx) || GetRenderAs(ctx) != ""
+}
+
+// render renders the secret through the template given with --render or
+// --as. Like --password it includes the password regardless of safecontent.
+func (s *Action) render(ctx context.Context, name string, sec gopass.Secret, folder bool) ([]byte, error) {
+	if target := GetRenderAs(ctx); target != "" {
+		buf, err := tpl.RenderTarget(ctx, target, name, sec, s.Store, folder)
+		if err != nil {
+			return nil, exit.Error(exit.Usage, err, "failed to render %s as %s: %s", name, target, err)
+		}
+
+		return buf, nil
+	}
+
+	fn := GetRender(ctx)
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, exit.Error(exit.IO, err, "failed to read template %s: %s", fn, err)
+	}
+
+	res, err := tpl.Render(ctx, string(buf), name, sec, s.Store, folder)
+	if err != nil {
+		return nil, exit.Error(exit.Unknown, err, "failed to render %s with %s: %s", name, fn, err)
+	}
+
+	return res, nil
+}
+
+// showRenderFolder renders all secrets in a folder, e.g. to create a
+// .netrc with one stanza per entry.
+func (s *Action) showRenderFolder(ctx context.Context, name string) error {
+	t, err := s.Store.Tree(ctx)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	subtree, err := t.FindFolder(name)
+	if err != nil {
+		return exit.Error(exit.NotFound, err, "Entry %q not found", name)
+	}
+
+	var res bytes.Buffer
+	for _, e := range subtree.List(tree.INF) {
+		sec, err := s.Store.Get(ctx, e)
+		if err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to decrypt %s: %s", e, err)
+		}
+
+		if ctxutil.IsDemo(ctx) {
+			e, sec = demo.Name(e), demo.Secret(sec)
+		}
+
+		buf, err := s.render(ctx, e, sec, true)
+		if err != nil {
+			return err
+		}
+		res.Write(buf)
+	}
+
+	fmt.Fprint(stdout, res.String())
+
+	return nil
+}
+
+func (s *Action) showGetContent(ctx context.Context, sec gopass.Secret, policy safeContentPolicy) (string, string, error) {
 	// YAML key.
 	if HasKey(ctx) {
 		key := GetKey(ctx)
@@ -256,25 +417,96 @@ func (s *Action) showGetContent(ctx context.Context, sec gopass.Secret) (string,
 
 	// everything but the first line.
 	if config.Bool(ctx, "core.showsafecontent") && !ctxutil.IsForce(ctx) {
-		body := showSafeContent(sec)
-		if IsAlsoClip(ctx) {
+		body := showSafeContent(sec, func(k string) bool { return policy.isUnsafe(k, sec) })
+		if isSafeContentClip(ctx) {
 			return pw, body, nil
 		}
 
 		return "", body, nil
 	}
 
+	// everything, but the keys that must always be obstructed.
+	if policy.masks(sec) && !ctxutil.IsForce(ctx) {
+		return pw, pw + "\n" + showSafeContent(sec, policy.isMasked), nil
+	}
+
 	// everything (default).
 	return pw, fullBody, nil
 }
 
-func showSafeContent(sec gopass.Secret) string {
+// strictPassword returns the password (or the value of the requested key)
+// for scripts. Anything a script could silently get wrong is an error: an
+// empty first line next to a password key, a password key that differs from
+// the first line, several values, surrounding whitespace or control
+// characters.
+func strictPassword(ctx context.Context, sec gopass.Secret) (string, error) {
+	var pw string
+
+	if HasKey(ctx) {
+		values, found := sec.Values(GetKey(ctx))
+		if !found {
+			return "", store.ErrNoKey
+		}
+
+		if len(values) != 1 {
+			return "", fmt.Errorf("%w: key %q has %d values", store.ErrAmbiguousPassword, GetKey(ctx), len(values))
+		}
+
+		pw = values[0]
+	} else {
+		pw = sec.Password()
+
+		for _, k := range sec.Keys() {
+			if !strings.EqualFold(k, "password") {
+				continue
+			}
+
+			values, _ := sec.Values(k)
+			for _, v := range values {
+				if v != pw {
+					return "", fmt.Errorf("%w: the %q key differs from the first line", store.ErrAmbiguousPassword, k)
+				}
+			}
+		}
+
+		if pw == "" {
+			return "", store.ErrNoPassword
+		}
+	}
+
+	if strings.TrimSpace(pw) != pw {
+		return "", fmt.Errorf("%w: leading or trailing whitespace", store.ErrAmbiguousPassword)
+	}
+
+	if strings.IndexFunc(pw, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w: control characters", store.ErrAmbiguousPassword)
+	}
+
+	return pw, nil
+}
+
+// isSafeContentClip returns true if the password should be copied to the
+// clipboard while only the safe content is displayed, i.e. with --alsoclip or
+// core.showautoclip. Never for flags that print (parts of) the password.
+func isSafeContentClip(ctx context.Context) bool {
+	if IsAlsoClip(ctx) {
+		return true
+	}
+
+	if len(GetPrintChars(ctx)) > 0 || IsSpell(ctx) {
+		return false
+	}
+
+	return config.Bool(ctx, "core.showautoclip")
+}
+
+func showSafeContent(sec gopass.Secret, unsafe func(string) bool) string {
 	var sb strings.Builder
 	for i, k := range sec.Keys() {
 		sb.WriteString(k)
 		sb.WriteString(": ")
 		// check if this key should be obstructed.
-		if isUnsafeKey(k, sec) {
+		if unsafe(k) {
 			debug.Log("obstructing unsafe key %s", k)
 			sb.WriteString(randAsterisk())
 		} else {
@@ -348,7 +580,9 @@ func (s *Action) hasAliasDomain(ctx context.Context, name string) string {
 
 // showHandleError handles errors retrieving secrets.
 func (s *Action) showHandleError(ctx context.Context, c *cli.Context, name string, recurse bool, err error) error {
-	if !errors.Is(err, store.ErrNotFound) || !recurse || !ctxutil.IsTerminal(ctx) {
+	// the restricted shell only allows the name it was given, all fallbacks
+	// could end up at entries outside of the allowed folders.
+	if !errors.Is(err, store.ErrNotFound) || !recurse || !ctxutil.IsTerminal(ctx) || IsRestrictedShell(ctx) {
 		if IsClip(ctx) {
 			_ = notify.Notify(ctx, "gopass - error", fmt.Sprintf("failed to retrieve secret %q: %s", name, err))
 		}
@@ -360,6 +594,10 @@ func (s *Action) showHandleError(ctx context.Context, c *cli.Context, name strin
 		return s.show(ctx, nil, newName, false)
 	}
 
+	if newName := s.showMountFallback(ctx, name); newName != "" {
+		return s.show(ctx, c, newName, false)
+	}
+
 	if IsClip(ctx) {
 		_ = notify.Notify(ctx, "gopass - warning", fmt.Sprintf("Entry %q not found. Starting search...", name))
 	}
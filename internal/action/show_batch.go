@@ -0,0 +1,187 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/demo"
+	"github.com/gopasspw/gopass/internal/hook"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/urfave/cli/v2"
+)
+
+// showBatchEntry is the JSON representation of a secret in batch mode.
+type showBatchEntry struct {
+	Password string         `json:"password,omitempty"`
+	Values   map[string]any `json:"values,omitempty"`
+	Body     string         `json:"body,omitempty"`
+}
+
+// showBatch prints several entries as one JSON object that maps the names,
+// in the given order, to their content or, with --password, to their
+// passwords. With --all the arguments are folders and all entries below them
+// are included. Scripts fetching many secrets this way start the crypto
+// backend only once. The content is obstructed like in show and the
+// show.post-hook runs for every entry.
+func (s *Action) showBatch(ctx context.Context, c *cli.Context) error {
+	if !c.Bool("json") {
+		return exit.Error(exit.Usage, nil, "--all requires --json")
+	}
+
+	names := c.Args().Slice()
+	if len(names) < 1 {
+		return exit.Error(exit.Usage, nil, "Usage: %s show --json [name...]", s.Name)
+	}
+
+	if c.Bool("all") {
+		expanded, err := s.showExpandFolders(ctx, names)
+		if err != nil {
+			return err
+		}
+		names = expanded
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return exit.Error(exit.NotFound, err, "Entry %q not found", name)
+			}
+
+			return exit.Error(exit.Decrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+
+		label := name
+		if ctxutil.IsDemo(ctx) {
+			label, sec = demo.Name(name), demo.Secret(sec)
+		}
+
+		key, err := json.Marshal(label)
+		if err != nil {
+			return err
+		}
+
+		val, err := json.MarshalIndent(showBatchValue(ctx, sec, s.safeContentPolicy(name)), "  ", "  ")
+		if err != nil {
+			return err
+		}
+
+		if len(seen) > 0 {
+			buf.WriteString(",")
+		}
+		seen[name] = true
+
+		fmt.Fprintf(&buf, "\n  %s: %s", key, val)
+	}
+
+	buf.WriteString("\n}\n")
+
+	fmt.Fprint(stdout, buf.String())
+
+	for _, name := range names {
+		if !seen[name] {
+			continue
+		}
+		seen[name] = false
+
+		if err := hook.InvokeRoot(ctx, "show.post-hook", name, s.Store); err != nil {
+			return exit.Error(exit.Hook, err, "Hook failed for %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// showBatchValue returns the password (--password) or the content of the
+// secret. Like showGetContent it leaves out the password and obstructs the
+// unsafe keys with core.showsafecontent and always obstructs the keys masked
+// by the policy, unless forced.
+func showBatchValue(ctx context.Context, sec gopass.Secret, policy safeContentPolicy) any {
+	if IsPasswordOnly(ctx) {
+		return sec.Password()
+	}
+
+	e := showBatchEntry{
+		Password: sec.Password(),
+		Body:     sec.Body(),
+	}
+
+	hidden := func(string) bool { return false }
+	switch {
+	case ctxutil.IsForce(ctx):
+	case config.Bool(ctx, "core.showsafecontent"):
+		e.Password = ""
+		hidden = func(k string) bool { return policy.isUnsafe(k, sec) }
+	default:
+		hidden = policy.isMasked
+	}
+
+	for _, k := range sec.Keys() {
+		values, found := sec.Values(k)
+		if !found {
+			continue
+		}
+		if e.Values == nil {
+			e.Values = make(map[string]any, len(sec.Keys()))
+		}
+		if hidden(k) {
+			e.Values[k] = randAsterisk()
+
+			continue
+		}
+		// keys with several values, e.g. from insert --append, are lists.
+		if len(values) > 1 {
+			e.Values[k] = values
+
+			continue
+		}
+		e.Values[k] = strings.Join(values, "\n")
+	}
+
+	return e
+}
+
+// showExpandFolders replaces all folders with the entries below them.
+func (s *Action) showExpandFolders(ctx context.Context, names []string) ([]string, error) {
+	t, err := s.Store.Tree(ctx)
+	if err != nil {
+		return nil, exit.Error(exit.List, err, "failed to list store: %s", err)
+	}
+
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if s.Store.Exists(ctx, name) {
+			res = append(res, name)
+		}
+
+		if !s.Store.IsDir(ctx, name) {
+			continue
+		}
+
+		subtree, err := t.FindFolder(name)
+		if err != nil {
+			return nil, exit.Error(exit.NotFound, err, "Folder %q not found", name)
+		}
+
+		res = append(res, subtree.List(tree.INF)...)
+	}
+
+	return res, nil
+}
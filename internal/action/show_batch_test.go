@@ -0,0 +1,113 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowBatch(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	for _, name := range []string{"db/prod", "db/dev", "web"} {
+		sec := secrets.NewAKV()
+		sec.SetPassword(name + "-pw")
+		require.NoError(t, sec.Set("user", "admin"))
+		require.NoError(t, act.Store.Set(ctx, name, sec))
+	}
+
+	t.Run("ordered names", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true"}, "web", "db/prod", "web")))
+		assert.Equal(t, `{
+  "web": {
+    "password": "web-pw",
+    "values": {
+      "user": "admin"
+    }
+  },
+  "db/prod": {
+    "password": "db/prod-pw",
+    "values": {
+      "user": "admin"
+    }
+  }
+}
+`, buf.String())
+	})
+
+	t.Run("passwords of a folder", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true", "all": "true", "password": "true"}, "db")))
+
+		m := map[string]string{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+		assert.Equal(t, map[string]string{"db/dev": "db/dev-pw", "db/prod": "db/prod-pw"}, m)
+	})
+
+	t.Run("safecontent", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.Set("", "core.showsafecontent", "true"))
+		require.NoError(t, act.cfg.Set("", "safecontent.mask", "user"))
+		defer func() {
+			require.NoError(t, act.cfg.Set("", "core.showsafecontent", "false"))
+			require.NoError(t, act.cfg.Set("", "safecontent.mask", ""))
+		}()
+
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true"}, "web")))
+		assert.Equal(t, `{
+  "web": {
+    "values": {
+      "user": "*****"
+    }
+  }
+}
+`, buf.String())
+		buf.Reset()
+
+		// --unsafe shows everything.
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true", "unsafe": "true"}, "web")))
+		assert.Contains(t, buf.String(), `"password": "web-pw"`)
+		assert.Contains(t, buf.String(), `"user": "admin"`)
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"json": "true"}, "web", "nope")))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("--all requires --json", func(t *testing.T) {
+		assert.Error(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"all": "true"}, "db")))
+	})
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// rootMount is the name of the root store in show.fallback-mounts.
+const rootMount = "<root>"
+
+// showFallbackMounts returns the mounts to search for entries that were not
+// found, in the order configured with show.fallback-mounts. "*" stands for
+// all mounts: the root store first, then the mounts in alphabetical order.
+func (s *Action) showFallbackMounts(ctx context.Context) []string {
+	sv := strings.TrimSpace(config.String(ctx, "show.fallback-mounts"))
+	if sv == "" {
+		return nil
+	}
+
+	if sv == "*" {
+		mps := s.Store.MountPoints()
+		sort.Strings(mps)
+
+		return append([]string{""}, mps...)
+	}
+
+	var mps []string
+	for _, mp := range strings.Split(sv, ",") {
+		mp = strings.Trim(strings.TrimSpace(mp), "/")
+		if mp == rootMount {
+			mp = ""
+		}
+		mps = append(mps, mp)
+	}
+
+	return mps
+}
+
+// showMountFallback looks for an entry with the same name in the other
+// mounts listed in show.fallback-mounts. If there are matches the user can
+// pick one. It returns an empty string if there is none or the user declined.
+// Scripts always get the entry they asked for, so there is no fallback if
+// gopass is not interactive or --yes answers all prompts.
+func (s *Action) showMountFallback(ctx context.Context, name string) string {
+	if !ctxutil.IsInteractive(ctx) || ctxutil.IsAlwaysYes(ctx) {
+		return ""
+	}
+
+	mps := s.showFallbackMounts(ctx)
+	if len(mps) < 1 {
+		return ""
+	}
+
+	own := s.Store.MountPoint(name)
+	rel := strings.TrimPrefix(strings.TrimPrefix(name, own), "/")
+
+	var matches []string
+	for _, mp := range mps {
+		if mp == own {
+			continue
+		}
+
+		if mp != "" && s.Store.MountPoint(mp) != mp {
+			debug.Log("show.fallback-mounts: %s is not mounted", mp)
+
+			continue
+		}
+
+		if cand := path.Join(mp, rel); s.Store.Exists(ctx, cand) {
+			matches = append(matches, cand)
+		}
+	}
+	debug.Log("found %q in other mounts: %v", rel, matches)
+
+	switch len(matches) {
+	case 0:
+		return ""
+	case 1:
+		ok, err := termio.AskForBool(ctx, fmt.Sprintf("Entry %q not found. Use %s instead?", name, matches[0]), true)
+		if err != nil || !ok {
+			return ""
+		}
+
+		return matches[0]
+	}
+
+	out.Noticef(ctx, "Entry %q not found, but it exists in other mounts", name)
+
+	act, sel := cui.GetSelection(ctx, "Which one do you want to use?", matches)
+	switch act {
+	case "default", "impossible":
+		return matches[sel]
+	default:
+		return ""
+	}
+}
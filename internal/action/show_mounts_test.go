@@ -0,0 +1,104 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowMountFallback(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, true)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	// accept every fallback that is offered.
+	termio.Stdin = strings.NewReader(strings.Repeat("y\n", 16))
+	defer func() {
+		termio.Stdin = os.Stdin
+	}()
+
+	require.NoError(t, u.InitStore("work"))
+	require.NoError(t, u.InitStore("home"))
+	require.NoError(t, act.Store.AddMount(ctx, "work", u.StoreDir("work")))
+	require.NoError(t, act.Store.AddMount(ctx, "home", u.StoreDir("home")))
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("vpn-secret")
+	require.NoError(t, act.Store.Set(ctx, "work/vpn", sec))
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, "", act.showMountFallback(ctx, "vpn"))
+	})
+
+	t.Run("all mounts", func(t *testing.T) {
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "*"))
+		assert.Equal(t, []string{"", "home", "work"}, act.showFallbackMounts(ctx))
+		assert.Equal(t, "work/vpn", act.showMountFallback(ctx, "vpn"))
+		assert.Equal(t, "work/vpn", act.showMountFallback(ctx, "home/vpn"))
+		assert.Equal(t, "", act.showMountFallback(ctx, "missing"))
+		// foo is in the root store.
+		assert.Equal(t, "foo", act.showMountFallback(ctx, "work/foo"))
+	})
+
+	t.Run("configured order", func(t *testing.T) {
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "home, <root>, unknown"))
+		assert.Equal(t, []string{"home", "", "unknown"}, act.showFallbackMounts(ctx))
+		assert.Equal(t, "", act.showMountFallback(ctx, "vpn"))
+		assert.Equal(t, "foo", act.showMountFallback(ctx, "home/foo"))
+	})
+
+	t.Run("show", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "work"))
+		require.NoError(t, act.Show(gptest.CliCtx(ctx, t, "vpn")))
+		assert.Contains(t, buf.String(), "vpn-secret")
+	})
+
+	t.Run("restricted shell", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "work"))
+		assert.Error(t, act.Show(gptest.CliCtx(WithRestrictedShell(ctx, true), t, "vpn")))
+		assert.NotContains(t, buf.String(), "vpn-secret")
+	})
+
+	t.Run("not interactive", func(t *testing.T) {
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "work"))
+		assert.Equal(t, "", act.showMountFallback(ctxutil.WithInteractive(ctx, false), "vpn"))
+	})
+
+	t.Run("always yes", func(t *testing.T) {
+		require.NoError(t, act.cfg.SetEnv("show.fallback-mounts", "work"))
+		assert.Equal(t, "", act.showMountFallback(ctxutil.WithAlwaysYes(ctx, true), "vpn"))
+	})
+}
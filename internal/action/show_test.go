@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/atotto/clipboard"
@@ -60,6 +61,24 @@ func TestShowMulti(t *testing.T) {
 		assert.Contains(t, buf.String(), "secret")
 	})
 
+	t.Run("show --render bar/baz", func(t *testing.T) {
+		defer buf.Reset()
+		fn := filepath.Join(t.TempDir(), "netrc.tmpl")
+		require.NoError(t, os.WriteFile(fn, []byte("machine {{ .Name }} login {{ .Values.bar }} password {{ .Password }}\n"), 0o600))
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"render": fn}, "bar/baz")
+		assert.NoError(t, act.Show(c))
+		assert.Equal(t, "machine baz login zab password 123\n", buf.String())
+	})
+
+	t.Run("show --as pgpass bar", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"as": "pgpass"}, "bar")
+		assert.NoError(t, act.Show(c))
+		assert.Equal(t, "*:*:*:*:123\n", buf.String())
+	})
+
 	t.Run("show dir", func(t *testing.T) {
 		c := gptest.CliCtx(ctx, t, "bar")
 		assert.NoError(t, act.Show(c))
@@ -140,6 +159,47 @@ func TestShowMulti(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("show entry with safecontent policy", func(t *testing.T) {
+		require.NoError(t, act.cfg.Set("", "safecontent.mask", "pin"))
+		require.NoError(t, act.cfg.Set("", "safecontent.show", "foo"))
+		defer func() {
+			require.NoError(t, act.cfg.Set("", "safecontent.mask", ""))
+			require.NoError(t, act.cfg.Set("", "safecontent.show", ""))
+		}()
+
+		sec := secrets.NewAKV()
+		sec.SetPassword("123")
+		assert.NoError(t, sec.Set("foo", "baz"))
+		assert.NoError(t, sec.Set("pin", "4711"))
+		assert.NoError(t, sec.Set("unsafe-keys", "foo"))
+		assert.NoError(t, act.Store.Set(ctx, "policy/card", sec))
+		buf.Reset()
+
+		c := gptest.CliCtx(ctx, t, "policy/card")
+		assert.NoError(t, act.Show(c))
+		assert.Contains(t, buf.String(), "foo: baz")
+		assert.Contains(t, buf.String(), "pin: *****")
+		assert.NotContains(t, buf.String(), "4711")
+		buf.Reset()
+
+		// masked keys are obstructed even if safecontent is disabled.
+		require.NoError(t, act.cfg.Set("", "core.showsafecontent", "false"))
+		defer func() {
+			require.NoError(t, act.cfg.Set("", "core.showsafecontent", "true"))
+		}()
+
+		assert.NoError(t, act.Show(c))
+		assert.Contains(t, buf.String(), "123")
+		assert.Contains(t, buf.String(), "pin: *****")
+		assert.NotContains(t, buf.String(), "4711")
+		buf.Reset()
+
+		c = gptest.CliCtxWithFlags(ctx, t, map[string]string{"unsafe": "true"}, "policy/card")
+		assert.NoError(t, act.Show(c))
+		assert.Contains(t, buf.String(), "4711")
+		buf.Reset()
+	})
+
 	require.NoError(t, act.cfg.Set("", "core.showsafecontent", "false"))
 
 	t.Run("show key ", func(t *testing.T) {
@@ -167,6 +227,16 @@ func TestShowMulti(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("show --spell", func(t *testing.T) {
+		assert.NoError(t, act.insertStdin(ctx, "spell", []byte("aB3!x\nuser: name"), false))
+		buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"spell": "true"}, "spell")
+		assert.NoError(t, act.Show(c))
+		assert.Equal(t, "1  a  alfa\n2  B  capital BRAVO\n3  3  three\n4  !  exclamation mark\n\n5  x  x-ray\n", buf.String())
+		buf.Reset()
+	})
+
 	t.Run("show value with format strings", func(t *testing.T) {
 		pw := "some-chars-are-odd-%s-%p-%q"
 
@@ -181,6 +251,70 @@ func TestShowMulti(t *testing.T) {
 	})
 }
 
+func TestShowStrict(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	color.NoColor = true
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	for name, content := range map[string]string{
+		"ok":         "s3cret\nuser: foo\npassword: s3cret\n",
+		"multi":      "s3cret\nuser: foo\nuser: bar\n",
+		"emptyfirst": "\nuser: foo\npassword: s3cret\n",
+		"differs":    "s3cret\nPassword: other\n",
+		"tab":        "s3\tcret\n",
+		"nopw":       "\nuser: foo\n",
+	} {
+		require.NoError(t, act.Store.Set(ctx, "strict/"+name, secrets.ParseAKV([]byte(content))))
+	}
+	buf.Reset()
+
+	flags := map[string]string{"strict": "true"}
+
+	t.Run("password", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, flags, "strict/ok")))
+		assert.Equal(t, "s3cret", buf.String())
+	})
+
+	t.Run("key", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, flags, "strict/ok", "user")))
+		assert.Equal(t, "foo", buf.String())
+	})
+
+	for _, name := range []string{"emptyfirst", "differs", "tab", "nopw", "missing", "strict"} {
+		name := name
+		t.Run("fails for "+name, func(t *testing.T) {
+			defer buf.Reset()
+			assert.Error(t, act.Show(gptest.CliCtxWithFlags(ctx, t, flags, "strict/"+name)))
+			assert.Empty(t, buf.String())
+		})
+	}
+
+	t.Run("fails for several values", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Show(gptest.CliCtxWithFlags(ctx, t, flags, "strict/multi", "user")))
+		assert.Empty(t, buf.String())
+	})
+}
+
 func TestShowAutoClip(t *testing.T) {
 	// make sure we consistently get the unsupported error message
 	ov := clipboard.Unsupported
@@ -315,6 +449,45 @@ func TestShowAutoClip(t *testing.T) {
 		stdoutBuf.Reset()
 		stderrBuf.Reset()
 	})
+
+	require.NoError(t, act.cfg.Set("", "core.showsafecontent", "true"))
+
+	// gopass show -C foo
+	// -> Copy to clipboard AND print the safe content
+	t.Run("gopass show -C foo with safecontent", func(t *testing.T) {
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"alsoclip": "true"}, "foo")
+		assert.NoError(t, act.Show(c))
+		assert.Contains(t, stderrBuf.String(), "WARNING")
+		assert.NotContains(t, stdoutBuf.String(), "secret")
+		assert.Contains(t, stdoutBuf.String(), "second")
+		stdoutBuf.Reset()
+		stderrBuf.Reset()
+	})
+
+	// gopass show foo
+	// -> Copy to clipboard AND print the safe content
+	require.NoError(t, act.cfg.Set("", "core.showautoclip", "true"))
+
+	t.Run("gopass show foo with safecontent and showautoclip", func(t *testing.T) {
+		c := gptest.CliCtx(ctx, t, "foo")
+		assert.NoError(t, act.Show(c))
+		assert.Contains(t, stderrBuf.String(), "WARNING")
+		assert.NotContains(t, stdoutBuf.String(), "secret")
+		assert.Contains(t, stdoutBuf.String(), "second")
+		stdoutBuf.Reset()
+		stderrBuf.Reset()
+	})
+
+	// gopass show --chars 1 foo
+	// -> Nothing, the password is never copied for flags that reveal it
+	t.Run("gopass show --chars 1 foo with safecontent and showautoclip", func(t *testing.T) {
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"chars": "1"}, "foo")
+		assert.NoError(t, act.Show(c))
+		assert.NotContains(t, stderrBuf.String(), "WARNING")
+		assert.NotContains(t, stdoutBuf.String(), "s")
+		stdoutBuf.Reset()
+		stderrBuf.Reset()
+	})
 }
 
 func TestShowHandleRevision(t *testing.T) {
@@ -0,0 +1,142 @@
+package action
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/hashsum"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// defaultSumKeyPrefix is the folder of the per-consumer HMAC keys unless
+// sum.key-prefix is set.
+const defaultSumKeyPrefix = "sum-keys"
+
+// sumHMAC prints a fingerprint of every entry that changes whenever the
+// entry changes but reveals nothing about its content. Each consumer gets
+// its own HMAC key, so fingerprints can't be correlated across consumers.
+func (s *Action) sumHMAC(ctx context.Context, c *cli.Context, consumer string) error {
+	if !c.Args().Present() {
+		return exit.Error(exit.Usage, nil, "Usage: %s sum --consumer <name> [--field <key>] <entry or folder>...", s.Name)
+	}
+
+	key, err := s.sumKey(ctx, consumer)
+	if err != nil {
+		return err
+	}
+
+	names, err := s.sumNames(ctx, c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return exit.Error(exit.Decrypt, err, "failed to read %s: %s", name, err)
+		}
+
+		buf, err := sumData(sec, c.String("field"))
+		if err != nil {
+			return exit.Error(exit.NotFound, err, "%s: %s", name, err)
+		}
+
+		fmt.Fprintf(stdout, "%s  %s\n", hashsum.HMACSHA256Hex(key, buf), name)
+	}
+
+	return nil
+}
+
+// sumKey returns the HMAC key of the consumer. It's created on first use.
+func (s *Action) sumKey(ctx context.Context, consumer string) ([]byte, error) {
+	if consumer != path.Base(consumer) || strings.HasPrefix(consumer, ".") {
+		return nil, exit.Error(exit.Usage, nil, "invalid consumer name %q", consumer)
+	}
+
+	prefix := config.String(ctx, "sum.key-prefix")
+	if prefix == "" {
+		prefix = defaultSumKeyPrefix
+	}
+	name := path.Join(prefix, consumer)
+
+	if s.Store.Exists(ctx, name) {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return nil, exit.Error(exit.Decrypt, err, "failed to read the HMAC key %s: %s", name, err)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(sec.Password())
+		if err != nil {
+			return nil, exit.Error(exit.Decrypt, err, "invalid HMAC key in %s: %s", name, err)
+		}
+
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, exit.Error(exit.Unknown, err, "failed to create a HMAC key: %s", err)
+	}
+
+	sec := secrets.NewAKV()
+	sec.SetPassword(base64.StdEncoding.EncodeToString(key))
+	_ = sec.Set("consumer", consumer)
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Created HMAC key for "+consumer), name, sec); err != nil {
+		return nil, exit.Error(exit.Encrypt, err, "failed to save the HMAC key in %s: %s", name, err)
+	}
+
+	return key, nil
+}
+
+// sumNames expands folders to the entries below them.
+func (s *Action) sumNames(ctx context.Context, args []string) ([]string, error) {
+	var names []string
+	for _, name := range args {
+		if !s.Store.IsDir(ctx, name) {
+			names = append(names, name)
+
+			continue
+		}
+
+		t, err := s.Store.Tree(ctx)
+		if err != nil {
+			return nil, exit.Error(exit.List, err, "failed to get store tree: %s", err)
+		}
+
+		subtree, err := t.FindFolder(name)
+		if err != nil {
+			return nil, exit.Error(exit.NotFound, err, "folder %q not found: %s", name, err)
+		}
+		names = append(names, subtree.List(tree.INF)...)
+	}
+
+	return names, nil
+}
+
+// sumData returns the part of the secret to fingerprint: the whole secret,
+// its password or the value of a single key.
+func sumData(sec gopass.Secret, field string) ([]byte, error) {
+	switch field {
+	case "":
+		return sec.Bytes(), nil
+	case "password":
+		return []byte(sec.Password()), nil
+	}
+
+	values, found := sec.Values(field)
+	if !found {
+		return nil, fmt.Errorf("no key %q", field)
+	}
+
+	return []byte(strings.Join(values, "\n")), nil
+}
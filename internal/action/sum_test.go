@@ -0,0 +1,86 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumHMAC(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+	color.NoColor = true
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("hunter2")
+	_ = sec.Set("username", "alice")
+	require.NoError(t, act.Store.Set(ctx, "db/prod", sec))
+
+	sum := func(t *testing.T, flags map[string]string, args ...string) string {
+		t.Helper()
+		defer buf.Reset()
+
+		require.NoError(t, act.Sum(gptest.CliCtxWithFlags(ctx, t, flags, args...)))
+
+		return buf.String()
+	}
+
+	first := sum(t, map[string]string{"consumer": "deploy"}, "db/prod")
+	assert.Regexp(t, `^[0-9a-f]{64}  db/prod\n$`, first)
+	assert.True(t, act.Store.Exists(ctx, "sum-keys/deploy"))
+	assert.NotContains(t, first, "hunter2")
+
+	t.Run("stable", func(t *testing.T) {
+		assert.Equal(t, first, sum(t, map[string]string{"consumer": "deploy"}, "db/prod"))
+	})
+
+	t.Run("per consumer", func(t *testing.T) {
+		assert.NotEqual(t, first, sum(t, map[string]string{"consumer": "monitoring"}, "db/prod"))
+	})
+
+	t.Run("fields and folders", func(t *testing.T) {
+		pw := sum(t, map[string]string{"consumer": "deploy", "field": "password"}, "db/prod")
+		assert.NotEqual(t, first, pw)
+
+		sec.SetPassword("changed")
+		require.NoError(t, act.Store.Set(ctx, "db/prod", sec))
+		assert.NotEqual(t, pw, sum(t, map[string]string{"consumer": "deploy", "field": "password"}, "db/prod"))
+
+		user := sum(t, map[string]string{"consumer": "deploy", "field": "username"}, "db")
+		assert.Equal(t, 1, strings.Count(user, "\n"))
+		assert.Contains(t, user, "  db/prod\n")
+
+		assert.Error(t, act.Sum(gptest.CliCtxWithFlags(ctx, t, map[string]string{"consumer": "deploy", "field": "missing"}, "db/prod")))
+	})
+
+	assert.Error(t, act.Sum(gptest.CliCtxWithFlags(ctx, t, map[string]string{"consumer": "../foo"}, "db/prod")))
+	assert.Error(t, act.Sum(gptest.CliCtxWithFlags(ctx, t, map[string]string{"consumer": "deploy"})))
+}
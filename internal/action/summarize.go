@@ -0,0 +1,209 @@
+package action
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/urfave/cli/v2"
+)
+
+// inventoryEntry describes an entry without revealing any secret value.
+type inventoryEntry struct {
+	Name        string
+	Username    string
+	URLs        []string
+	Owners      []string
+	Tags        []string
+	LastRotated time.Time
+}
+
+// usernameKeys are the keys holding the login name, in order of preference.
+var usernameKeys = []string{"username", "login", "user"}
+
+// Summarize prints a redacted inventory of a folder, e.g. for handovers.
+func (s *Action) Summarize(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	folder := c.Args().First()
+
+	t, err := s.Store.Tree(ctx)
+	if err != nil {
+		return exit.Error(exit.List, err, "failed to get store tree: %s", err)
+	}
+
+	if folder != "" {
+		subtree, err := t.FindFolder(folder)
+		if err != nil {
+			return exit.Error(exit.NotFound, err, "folder %q not found: %s", folder, err)
+		}
+		t = subtree
+	}
+
+	entries := make([]inventoryEntry, 0, t.Len())
+	var skipped []string
+	for _, name := range t.List(tree.INF) {
+		e, err := s.inventoryEntry(ctx, name)
+		if err != nil {
+			debug.Log("failed to read %q: %s", name, err)
+			skipped = append(skipped, name)
+
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	if len(skipped) > 0 {
+		out.Warningf(ctx, "Skipped %d entries that could not be read: %s", len(skipped), strings.Join(skipped, ", "))
+	}
+
+	var render func(io.Writer) error
+	var suffix string
+	switch format := c.String("format"); format {
+	case "csv":
+		render = func(w io.Writer) error {
+			return renderInventoryCSV(w, entries)
+		}
+		suffix = "csv"
+	case "markdown", "md", "":
+		render = func(w io.Writer) error {
+			return renderInventoryMarkdown(w, folder, entries)
+		}
+		suffix = "md"
+	default:
+		return exit.Error(exit.Usage, nil, "unknown format %q, use markdown or csv", format)
+	}
+
+	if fn := c.String("output-file"); fn != "" {
+		return saveReport(ctx, render, fn, suffix)
+	}
+
+	return render(stdout)
+}
+
+func (s *Action) inventoryEntry(ctx context.Context, name string) (inventoryEntry, error) {
+	e := inventoryEntry{
+		Name:   name,
+		Owners: s.Store.Owners(ctx, name),
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return e, err
+	}
+
+	for _, k := range usernameKeys {
+		if v, found := sec.Get(k); found {
+			e.Username = v
+			break
+		}
+	}
+
+	e.URLs, _ = sec.Values("url")
+	e.Tags = root.Tags(sec)
+	e.LastRotated = s.lastRotated(ctx, name, sec)
+
+	return e, nil
+}
+
+// lastRotated returns when the password was last rotated without decrypting
+// any older revisions. That's the time of the newest password kept by
+// `generate --keep-old` or, if there is none, when the entry was created.
+func (s *Action) lastRotated(ctx context.Context, name string, sec gopass.Secret) time.Time {
+	var newest string
+	for _, k := range sec.Keys() {
+		if strings.HasPrefix(k, oldPasswordPrefix) && k > newest {
+			newest = k
+		}
+	}
+
+	if ts := strings.TrimPrefix(newest, oldPasswordPrefix); len(ts) >= 14 {
+		if rotated, err := time.ParseInLocation("20060102150405", ts[:14], time.Local); err == nil {
+			return rotated
+		}
+	}
+
+	revs, err := s.Store.ListRevisions(ctx, name)
+	if err != nil || len(revs) < 1 {
+		debug.Log("no revisions for %s: %v", name, err)
+
+		return time.Time{}
+	}
+
+	return revs[len(revs)-1].Date
+}
+
+func inventoryDate(ts time.Time) string {
+	if ts.IsZero() {
+		return "unknown"
+	}
+
+	return ts.Format("2006-01-02")
+}
+
+func renderInventoryMarkdown(w io.Writer, folder string, entries []inventoryEntry) error {
+	if folder == "" {
+		folder = "the password store"
+	}
+
+	cell := func(s ...string) string {
+		return strings.ReplaceAll(strings.Join(s, ", "), "|", `\|`)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Inventory of %s\n\n", folder)
+	fmt.Fprintf(&sb, "Generated on %s. Contains %d entries and no secret values.\n\n", time.Now().Format("2006-01-02"), len(entries))
+	sb.WriteString("| Entry | Username | URLs | Owners | Tags | Last rotated |\n")
+	sb.WriteString("|-------|----------|------|--------|------|--------------|\n")
+
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n",
+			cell(e.Name), cell(e.Username), cell(e.URLs...), cell(e.Owners...), cell(e.Tags...), inventoryDate(e.LastRotated))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func renderInventoryCSV(w io.Writer, entries []inventoryEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "username", "urls", "owners", "tags", "last_rotated"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := cw.Write([]string{
+			csvCell(e.Name),
+			csvCell(e.Username),
+			csvCell(strings.Join(e.URLs, " ")),
+			csvCell(strings.Join(e.Owners, " ")),
+			csvCell(strings.Join(e.Tags, " ")),
+			inventoryDate(e.LastRotated),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// csvCell prevents spreadsheets from evaluating cells as formulas.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
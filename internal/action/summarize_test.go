@@ -0,0 +1,123 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	sec := secrets.ParseAKV([]byte("s3cr3t-value\nusername: jane\nurl: https://example.org\nurl: https://example.com\ntags: prod\n"))
+	require.NoError(t, act.Store.Set(ctx, "team/web", sec))
+
+	t.Run("markdown", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Summarize(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "markdown"}, "team")))
+		assert.Contains(t, buf.String(), "# Inventory of team\n")
+		assert.Contains(t, buf.String(), "| team/web | jane | https://example.org, https://example.com |  | prod | ")
+		assert.NotContains(t, buf.String(), "s3cr3t")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		defer buf.Reset()
+
+		fn := filepath.Join(t.TempDir(), "inventory.csv")
+		require.NoError(t, act.Summarize(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "csv", "output-file": fn}, "team")))
+
+		got, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "name,username,urls,owners,tags,last_rotated\n")
+		assert.Contains(t, string(got), "team/web,jane,https://example.org https://example.com,,prod,")
+		assert.NotContains(t, string(got), "s3cr3t")
+	})
+
+	t.Run("unreadable entries are skipped", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Store.Set(ctx, "team/broken", secrets.ParseAKV([]byte("broken\n"))))
+		fns, err := filepath.Glob(filepath.Join(u.StoreDir(""), "team", "broken.*"))
+		require.NoError(t, err)
+		require.Len(t, fns, 1)
+		require.NoError(t, os.WriteFile(fns[0], []byte(fido2.Header+"\nnot wrapped\n"), 0o600))
+		defer func() {
+			require.NoError(t, act.Store.Delete(ctx, "team/broken"))
+		}()
+
+		require.NoError(t, act.Summarize(gptest.CliCtx(ctx, t, "team")))
+		assert.Contains(t, buf.String(), "| team/web | jane |")
+		assert.NotContains(t, buf.String(), "| team/broken |")
+		assert.Contains(t, buf.String(), "Skipped 1 entries that could not be read: team/broken")
+	})
+
+	t.Run("unknown folder", func(t *testing.T) {
+		defer buf.Reset()
+
+		assert.Error(t, act.Summarize(gptest.CliCtx(ctx, t, "nope")))
+	})
+}
+
+func TestInventoryLastRotated(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	ctx = act.cfg.WithConfig(ctx)
+
+	sec := secrets.ParseAKV([]byte("new\nold-password-20240102030405: older\nold-password-20240506070809-02: old\n"))
+	require.NoError(t, act.Store.Set(ctx, "web", sec))
+
+	e, err := act.inventoryEntry(ctx, "web")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local), e.LastRotated)
+}
+
+func TestRenderInventoryCSV(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, renderInventoryCSV(buf, []inventoryEntry{{
+		Name:     "web",
+		Username: "=HYPERLINK(\"https://evil.example\")",
+		URLs:     []string{"+1", "https://example.org"},
+		Tags:     []string{"@prod"},
+		Owners:   []string{"-alice"},
+	}}))
+	assert.Equal(t, "name,username,urls,owners,tags,last_rotated\n"+
+		"web,\"'=HYPERLINK(\"\"https://evil.example\"\")\",'+1 https://example.org,'-alice,'@prod,unknown\n", buf.String())
+}
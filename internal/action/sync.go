@@ -2,21 +2,28 @@ package action
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/action/exit"
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
 	"github.com/gopasspw/gopass/internal/config"
-	"github.com/gopasspw/gopass/internal/diff"
-	"github.com/gopasspw/gopass/internal/notify"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/network"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/store/leaf"
-	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/urfave/cli/v2"
@@ -45,7 +52,30 @@ func init() {
 
 // Sync all stores with their remotes.
 func (s *Action) Sync(c *cli.Context) error {
-	return s.sync(ctxutil.WithGlobalFlags(c), c.String("store"))
+	ctx := ctxutil.WithGlobalFlags(c)
+	ctx = WithEstimateOnly(ctx, c.Bool("estimate"))
+
+	if sv := c.String("max-bandwidth"); sv != "" {
+		bw, err := network.ParseBandwidth(sv)
+		if err != nil {
+			return exit.Error(exit.Usage, err, "invalid --max-bandwidth: %s", err)
+		}
+		ctx = network.WithMaxBandwidth(ctx, bw)
+	}
+
+	switch f := c.String("format"); f {
+	case "", "text":
+		return s.sync(ctx, c.String("store"))
+	case "json":
+		sum, err := s.syncStores(ctxutil.WithHidden(ctx, true), c.String("store"))
+		if err != nil {
+			return err
+		}
+
+		return json.NewEncoder(stdout).Encode(sum)
+	default:
+		return exit.Error(exit.Usage, nil, "unknown format %q. Use text or json", f)
+	}
 }
 
 func (s *Action) autoSync(ctx context.Context) error {
@@ -88,24 +118,62 @@ func (s *Action) autoSync(ctx context.Context) error {
 }
 
 func (s *Action) sync(ctx context.Context, store string) error {
+	_, err := s.syncStores(ctx, store)
+
+	return err
+}
+
+// syncSummary describes the changes a sync brought to all synced mounts.
+type syncSummary struct {
+	Mounts []*syncMountSummary `json:"mounts"`
+}
+
+// syncMountSummary describes the changes a sync brought to a single mount.
+type syncMountSummary struct {
+	Mount             string   `json:"mount"`
+	Status            string   `json:"status"`
+	Added             []string `json:"added"`
+	Changed           []string `json:"changed"`
+	Removed           []string `json:"removed"`
+	RecipientsChanged bool     `json:"recipients_changed"`
+	Error             string   `json:"error,omitempty"`
+	// Estimate is only set if the bandwidth is limited or an estimate
+	// was requested.
+	Estimate *backend.TransferEstimate `json:"estimate,omitempty"`
+}
+
+// HasChanges returns true if any entry or the recipients of the mount changed.
+func (m *syncMountSummary) HasChanges() bool {
+	return len(m.Added)+len(m.Changed)+len(m.Removed) > 0 || m.RecipientsChanged
+}
+
+// String returns a one line summary of the changes.
+func (m *syncMountSummary) String() string {
+	rcs := "unchanged"
+	if m.RecipientsChanged {
+		rcs = "changed"
+	}
+
+	return fmt.Sprintf("%d added, %d changed, %d removed, recipients %s", len(m.Added), len(m.Changed), len(m.Removed), rcs)
+}
+
+func (s *Action) syncStores(ctx context.Context, store string) (*syncSummary, error) {
+	sum := &syncSummary{}
+
 	// we just did a full sync, no need to run it again
 	if time.Since(autosyncLastRun) < 10*time.Second {
 		debug.Log("skipping sync. last sync %ds ago", time.Since(autosyncLastRun))
 
-		return nil
+		return sum, nil
 	}
 
 	out.Printf(ctx, "🚥 Syncing with all remotes ...")
 
-	numEntries := 0
-	if l, err := s.Store.Tree(ctx); err == nil {
-		numEntries = len(l.List(tree.INF))
-	}
-	numMPs := 0
-
 	mps := s.Store.MountPoints()
 	mps = append([]string{""}, mps...)
 
+	sp := out.NewSpinner(ctx, "Syncing")
+
 	// sync all stores (root and all mounted sub stores).
 	for _, mp := range mps {
 		if store != "" {
@@ -117,9 +185,13 @@ func (s *Action) sync(ctx context.Context, store string) error {
 			}
 		}
 
-		numMPs++
-		_ = s.syncMount(ctx, mp)
+		sp.Step("%s", mp)
+		ms, _ := s.syncMount(ctx, mp)
+		sum.Mounts = append(sum.Mounts, ms)
 	}
+	sp.Done()
+
+	syncPrintSummary(ctx, sum)
 	out.OKf(ctx, "All done")
 
 	// If we just sync'ed all stores we can reset the auto-sync interval
@@ -127,34 +199,45 @@ func (s *Action) sync(ctx context.Context, store string) error {
 		_ = s.rem.Reset("autosync")
 	}
 
-	// Calculate number of changed entries.
-	// This is a rough estimate as additions and deletions.
-	// might cancel each other out.
-	if l, err := s.Store.Tree(ctx); err == nil {
-		numEntries = len(l.List(tree.INF)) - numEntries
-	}
-	diff := ""
-	if numEntries > 0 {
-		diff = fmt.Sprintf(" Added %d entries", numEntries)
-	} else if numEntries < 0 {
-		diff = fmt.Sprintf(" Removed %d entries", -1*numEntries)
+	var added, changed, removed int
+	var entries []string
+	for _, ms := range sum.Mounts {
+		added += len(ms.Added)
+		changed += len(ms.Changed)
+		removed += len(ms.Removed)
+		entries = append(entries, ms.Added...)
+		entries = append(entries, ms.Changed...)
+		entries = append(entries, ms.Removed...)
 	}
 
-	if numEntries != 0 {
-		_ = notify.Notify(ctx, "gopass - sync", fmt.Sprintf("Finished. Synced %d remotes.%s", numMPs, diff))
+	if added+removed > 0 {
+		s.Store.InvalidateNameIndex()
 	}
 
-	return nil
+	event.Publish(ctx, event.Event{
+		Type:    event.SyncCompleted,
+		Store:   store,
+		Entries: entries,
+		Message: fmt.Sprintf("Finished. Synced %d remotes. %d added, %d changed, %d removed entries", len(sum.Mounts), added, changed, removed),
+	})
+
+	return sum, nil
 }
 
-// syncMount syncs a single mount.
-func (s *Action) syncMount(ctx context.Context, mp string) error {
+// syncMount syncs a single mount and reports the changes the sync brought.
+func (s *Action) syncMount(ctx context.Context, mp string) (*syncMountSummary, error) {
+	ms := &syncMountSummary{
+		Mount:  mp,
+		Status: "ok",
+	}
+
 	// using GetM here to get the value for this mount, it might be different
 	// than the global value
 	if as := s.cfg.GetM(mp, "core.autosync"); as == "false" {
 		debug.Log("not syncing %s, autosync is disabled for this mount", mp)
+		ms.Status = "disabled"
 
-		return nil
+		return ms, nil
 	}
 
 	ctxno := out.WithNewline(ctx, false)
@@ -164,65 +247,166 @@ func (s *Action) syncMount(ctx context.Context, mp string) error {
 	}
 	out.Printf(ctxno, color.GreenString("[%s] ", name))
 
+	if s.isAirGapped(mp) {
+		out.Printf(ctx, "\n   air-gapped. Use 'gopass bundle export' and 'gopass bundle import' to sync")
+		ms.Status = "air-gapped"
+
+		return ms, nil
+	}
+
+	fail := func(err error) (*syncMountSummary, error) {
+		ms.Status = "error"
+		ms.Error = err.Error()
+
+		return ms, err
+	}
+
 	sub, err := s.Store.GetSubStore(mp)
 	if err != nil {
 		out.Errorf(ctx, "Failed to get sub store %q: %s", name, err)
 
-		return fmt.Errorf("failed to get sub stores (%w)", err)
+		return fail(fmt.Errorf("failed to get sub stores (%w)", err))
 	}
 
 	if sub == nil {
 		out.Errorf(ctx, "Failed to get sub stores '%s: nil'", name)
 
-		return fmt.Errorf("failed to get sub stores (nil)")
+		return fail(fmt.Errorf("failed to get sub stores (nil)"))
 	}
 
-	l, err := sub.List(ctx, "")
+	release, err := sub.Acquire(ctx)
 	if err != nil {
-		out.Errorf(ctx, "Failed to list store: %s", err)
+		out.Errorf(ctx, "Failed to sync %q: %s", name, err)
+
+		return fail(err)
+	}
+	defer release()
+
+	if IsEstimateOnly(ctx) || network.MaxBandwidth(ctx) > 0 {
+		ms.Estimate = syncEstimate(ctxno, sub)
 	}
 
+	if IsEstimateOnly(ctx) {
+		out.Printf(ctx, "")
+		ms.Status = "estimated"
+
+		return ms, nil
+	}
+
+	before := syncSnapshot(ctx, sub)
+
 	out.Printf(ctxno, "\n   "+color.GreenString("%s pull and push ... ", sub.Storage().Name()))
 	err = sub.Storage().Push(ctx, "", "")
 
 	switch {
+	case err == nil && sub.IsProtected(ctx) && sub.Staged() != "":
+		debug.Log("Push staged for approval")
+		out.Printf(ctxno, color.GreenString("OK (staged, approve with '%s review approve' as another recipient)", s.Name))
+		ms.Status = "staged"
 	case err == nil:
 		debug.Log("Push succeeded")
 		out.Printf(ctxno, color.GreenString("OK"))
 	case errors.Is(err, store.ErrGitNoRemote):
 		out.Printf(ctx, "Skipped (no remote)")
 		debug.Log("Failed to push %q to its remote: %s", name, err)
+		ms.Status = "no-remote"
 
-		return err
+		return ms, err
 	case errors.Is(err, backend.ErrNotSupported):
 		out.Printf(ctxno, "Skipped (not supported)")
+		ms.Status = "not-supported"
+	case errors.Is(err, network.ErrOffline):
+		out.Printf(ctxno, "Skipped (offline)")
+		ms.Status = "offline"
 	case errors.Is(err, store.ErrGitNotInit):
 		out.Printf(ctxno, "Skipped (no Git repo)")
+		ms.Status = "no-git"
 	default: // any other error
 		out.Errorf(ctx, "Failed to push %q to its remote: %s", name, err)
 
-		return err
+		return fail(err)
 	}
 
-	ln, err := sub.List(ctx, "")
-	if err != nil {
-		out.Errorf(ctx, "Failed to list store: %s", err)
+	if err == nil {
+		syncApplyTombstones(ctxno, sub)
+	}
+
+	after := syncSnapshot(ctx, sub)
+	ms.Added, ms.Changed, ms.Removed, ms.RecipientsChanged = before.diff(after)
+	if ms.HasChanges() {
+		out.Printf(ctxno, color.GreenString(" (%s)", ms))
+	} else {
+		out.Printf(ctxno, color.GreenString(" (no changes)"))
 	}
-	syncPrintDiff(ctxno, l, ln)
 
 	exportKeys := s.cfg.GetBool("core.exportkeys")
 	debug.Log("Syncing Mount %s. Exportkeys: %t", mp, exportKeys)
 	if err := syncImportKeys(ctxno, sub, name); err != nil {
-		return err
+		return fail(err)
 	}
 	if exportKeys {
 		if err := syncExportKeys(ctxno, sub, name); err != nil {
-			return err
+			return fail(err)
 		}
 	}
 	out.Printf(ctx, "\n   "+color.GreenString("done"))
 
-	return nil
+	return ms, nil
+}
+
+// syncApplyTombstones removes entries that were deleted on this or another
+// device, but resurrected by the merge, and pushes the result.
+func syncApplyTombstones(ctx context.Context, sub *leaf.Store) {
+	deleted, err := sub.ApplyTombstones(ctx)
+	if err != nil {
+		out.Errorf(ctx, "Failed to apply tombstones: %s", err)
+
+		return
+	}
+
+	if len(deleted) < 1 {
+		return
+	}
+
+	out.Printf(ctx, "\n   "+color.YellowString("removed %d resurrected entries: %s", len(deleted), strings.Join(deleted, ", ")))
+
+	if err := sub.Storage().Push(ctx, "", ""); err != nil {
+		out.Errorf(ctx, "Failed to push: %s", err)
+	}
+}
+
+// transferEstimator is implemented by storage backends that can estimate
+// the size of a sync.
+type transferEstimator interface {
+	EstimateTransfer(ctx context.Context, remote, branch string) (*backend.TransferEstimate, error)
+}
+
+// syncEstimate prints the estimated transfer size of the next sync.
+func syncEstimate(ctx context.Context, sub *leaf.Store) *backend.TransferEstimate {
+	te, ok := local.Unwrap(sub.Storage()).(transferEstimator)
+	if !ok {
+		return nil
+	}
+
+	est, err := te.EstimateTransfer(ctx, "", "")
+	if err != nil {
+		debug.Log("failed to estimate transfer size: %s", err)
+
+		return nil
+	}
+
+	incoming := "nothing"
+	if est.Incoming {
+		incoming = "new commits (size unknown)"
+	}
+
+	msg := fmt.Sprintf("estimated transfer: %s up (%d objects), %s down", humanize.IBytes(uint64(est.Upload)), est.Objects, incoming)
+	if bw := network.MaxBandwidth(ctx); bw > 0 && est.Upload > 0 {
+		msg += fmt.Sprintf(", upload takes at least %s at %s/s", time.Duration(float64(est.Upload)/float64(bw)*float64(time.Second)).Round(time.Second), humanize.IBytes(uint64(bw)))
+	}
+	out.Printf(ctx, "\n   "+color.GreenString("%s", msg))
+
+	return est
 }
 
 func syncImportKeys(ctx context.Context, sub *leaf.Store, name string) error {
@@ -265,16 +449,99 @@ func syncExportKeys(ctx context.Context, sub *leaf.Store, name string) error {
 	return nil
 }
 
-func syncPrintDiff(ctxno context.Context, l, r []string) {
-	added, removed := diff.Stat(l, r)
-	debug.Log("diff - added: %d - removed: %d", added, removed)
-	if added > 0 {
-		out.Printf(ctxno, color.GreenString(" (Added %d entries)", added))
+// syncState is a snapshot of the entries and recipients of a store.
+type syncState struct {
+	entries    map[string]string
+	recipients string
+}
+
+// syncSnapshot records the checksums of all (encrypted) entries and the
+// recipients of the given store. We compare checksums of the encrypted files
+// so we don't need to decrypt anything.
+func syncSnapshot(ctx context.Context, sub *leaf.Store) syncState {
+	st := syncState{
+		entries: map[string]string{},
 	}
-	if removed > 0 {
-		out.Printf(ctxno, color.GreenString(" (Removed %d entries)", removed))
+
+	l, err := sub.List(ctx, "")
+	if err != nil {
+		out.Errorf(ctx, "Failed to list store: %s", err)
 	}
-	if added < 1 && removed < 1 {
-		out.Printf(ctxno, color.GreenString(" (no changes)"))
+
+	for _, e := range l {
+		buf, err := sub.Storage().Get(ctx, sub.Passfile(strings.TrimPrefix(e, sub.Alias()+"/")))
+		if err != nil {
+			debug.Log("failed to read %s: %s", e, err)
+		}
+		st.entries[e] = fmt.Sprintf("%x", sha256.Sum256(buf))
+	}
+
+	rt := sub.RecipientsTree(ctx)
+	dirs := make([]string, 0, len(rt))
+	for d := range rt {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	var sb strings.Builder
+	for _, d := range dirs {
+		fmt.Fprintf(&sb, "%s:%s\n", d, strings.Join(set.Sorted(rt[d]), ","))
+	}
+	st.recipients = sb.String()
+
+	return st
+}
+
+// diff returns the sorted names of the added, changed and removed entries
+// and whether the recipients changed from s to o.
+func (s syncState) diff(o syncState) ([]string, []string, []string, bool) {
+	added := []string{}
+	changed := []string{}
+	removed := []string{}
+
+	for e, sum := range o.entries {
+		old, found := s.entries[e]
+		switch {
+		case !found:
+			added = append(added, e)
+		case old != sum:
+			changed = append(changed, e)
+		}
+	}
+	for e := range s.entries {
+		if _, found := o.entries[e]; !found {
+			removed = append(removed, e)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	return added, changed, removed, s.recipients != o.recipients
+}
+
+// syncPrintSummary prints the names of all entries changed by a sync.
+func syncPrintSummary(ctx context.Context, sum *syncSummary) {
+	for _, ms := range sum.Mounts {
+		if !ms.HasChanges() {
+			continue
+		}
+
+		name := ms.Mount
+		if name == "" {
+			name = "<root>"
+		}
+
+		out.Printf(ctx, "%s: %s", color.GreenString(name), ms)
+		for _, e := range ms.Added {
+			out.Printf(ctx, "   + %s", e)
+		}
+		for _, e := range ms.Changed {
+			out.Printf(ctx, "   ~ %s", e)
+		}
+		for _, e := range ms.Removed {
+			out.Printf(ctx, "   - %s", e)
+		}
 	}
 }
@@ -3,8 +3,10 @@ package action
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -19,9 +21,11 @@ func TestSync(t *testing.T) {
 	buf := &bytes.Buffer{}
 	out.Stdout = buf
 	out.Stderr = buf
+	stdout = buf
 	defer func() {
 		out.Stdout = os.Stdout
 		out.Stderr = os.Stderr
+		stdout = os.Stdout
 	}()
 
 	ctx := context.Background()
@@ -40,4 +44,44 @@ func TestSync(t *testing.T) {
 		defer buf.Reset()
 		assert.NoError(t, act.Sync(gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": "root"})))
 	})
+
+	t.Run("sync --format=json", func(t *testing.T) {
+		defer buf.Reset()
+		autosyncLastRun = time.Time{}
+		require.NoError(t, act.Sync(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "json"})))
+
+		sum := &syncSummary{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), sum))
+		require.Len(t, sum.Mounts, 1)
+		assert.Equal(t, "", sum.Mounts[0].Mount)
+		assert.NotContains(t, buf.String(), "Syncing")
+	})
+
+	t.Run("sync --format=yaml", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Sync(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "yaml"})))
+	})
+}
+
+func TestSyncStateDiff(t *testing.T) {
+	t.Parallel()
+
+	before := syncState{
+		entries:    map[string]string{"a": "1", "b": "2", "c": "3"},
+		recipients: "foo",
+	}
+	after := syncState{
+		entries:    map[string]string{"a": "1", "b": "4", "d": "5"},
+		recipients: "foo",
+	}
+
+	added, changed, removed, rcs := before.diff(after)
+	assert.Equal(t, []string{"d"}, added)
+	assert.Equal(t, []string{"b"}, changed)
+	assert.Equal(t, []string{"c"}, removed)
+	assert.False(t, rcs)
+
+	after.recipients = "bar"
+	_, _, _, rcs = before.diff(after)
+	assert.True(t, rcs)
 }
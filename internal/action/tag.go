@@ -0,0 +1,95 @@
+package action
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// TagList prints the tags of an entry.
+func (s *Action) TagList(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return exit.Error(exit.Usage, nil, "Usage: %s tag list <entry>", s.Name)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read %q: %s", name, err)
+	}
+
+	for _, t := range root.Tags(sec) {
+		out.Print(ctx, t)
+	}
+
+	return nil
+}
+
+// TagAdd adds one or more tags to an entry.
+func (s *Action) TagAdd(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	tags := c.Args().Tail()
+	if name == "" || len(tags) < 1 {
+		return exit.Error(exit.Usage, nil, "Usage: %s tag add <entry> <tag>...", s.Name)
+	}
+
+	return s.updateTags(ctxutil.WithCommitMessage(ctx, "Added tags"), name, func(have []string) []string {
+		return append(have, tags...)
+	})
+}
+
+// TagRemove removes one or more tags from an entry.
+func (s *Action) TagRemove(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	tags := c.Args().Tail()
+	if name == "" || len(tags) < 1 {
+		return exit.Error(exit.Usage, nil, "Usage: %s tag remove <entry> <tag>...", s.Name)
+	}
+
+	return s.updateTags(ctxutil.WithCommitMessage(ctx, "Removed tags"), name, func(have []string) []string {
+		return set.Filter(have, tags...)
+	})
+}
+
+func (s *Action) updateTags(ctx context.Context, name string, update func([]string) []string) error {
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return exit.Error(exit.Decrypt, err, "failed to read %q: %s", name, err)
+	}
+
+	if err := root.SetTags(sec, update(root.Tags(sec))); err != nil {
+		return exit.Error(exit.Unknown, err, "failed to set tags of %q: %s", name, err)
+	}
+
+	if err := s.Store.Set(ctx, name, sec); err != nil {
+		if !errors.Is(err, store.ErrMeaninglessWrite) {
+			return exit.Error(exit.Encrypt, err, "failed to save %q: %s", name, err)
+		}
+		out.Warningf(ctx, "No need to write: the secret is already there and with the right value")
+	}
+
+	return nil
+}
+
+// taggedEntries returns the entries matching all tags given with --tag. If no
+// tag was given it returns nil and false.
+func (s *Action) taggedEntries(ctx context.Context, c *cli.Context) ([]string, bool, error) {
+	tags := c.StringSlice("tag")
+	if len(tags) < 1 {
+		return nil, false, nil
+	}
+
+	entries, err := s.Store.ListTagged(ctx, tags...)
+
+	return entries, true, err
+}
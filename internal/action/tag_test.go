@@ -0,0 +1,62 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTag(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u.StoreDir(""))
+	require.NoError(t, err)
+	require.NotNil(t, act)
+	ctx = act.cfg.WithConfig(ctx)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	color.NoColor = true
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+	}()
+
+	t.Run("add tags", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.TagAdd(gptest.CliCtx(ctx, t, "foo")))
+		assert.NoError(t, act.TagAdd(gptest.CliCtx(ctx, t, "foo", "prod", "quarterly")))
+	})
+
+	t.Run("list tags", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.TagList(gptest.CliCtx(ctx, t, "foo")))
+		assert.Equal(t, "prod\nquarterly\n", buf.String())
+	})
+
+	t.Run("remove tags", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.TagRemove(gptest.CliCtx(ctx, t, "foo", "quarterly")))
+		assert.NoError(t, act.TagList(gptest.CliCtx(ctx, t, "foo")))
+		assert.Equal(t, "prod\n", buf.String())
+	})
+
+	t.Run("list by tag", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"flat": "true", "tag": "prod"})))
+		assert.Equal(t, "foo\n", buf.String())
+	})
+}
@@ -130,30 +130,66 @@ func (s *Action) TemplatesComplete(c *cli.Context) {
 	}
 }
 
+// renderTemplate renders the template matching the given secret, if any.
+// Errors are printed but otherwise ignored.
 func (s *Action) renderTemplate(ctx context.Context, name string, content []byte) ([]byte, bool) {
-	tName, tmpl, found := s.Store.LookupTemplate(ctx, name)
+	nc, found, err := s.executeTemplate(ctx, name, "", content)
+	if err != nil {
+		fmt.Fprintf(stdout, "%s\n", err)
+
+		return content, false
+	}
+
+	return nc, found
+}
+
+// executeTemplate renders the given template or, if tplName is empty, the
+// template matching the given secret. It returns false if no template was
+// found.
+func (s *Action) executeTemplate(ctx context.Context, name, tplName string, content []byte) ([]byte, bool, error) {
+	tName, tmpl, found, err := s.lookupTemplate(ctx, name, tplName)
+	if err != nil {
+		return content, false, err
+	}
 	if !found {
 		debug.Log("No template found for %s", name)
 
-		return content, false
+		return content, false, nil
 	}
 
 	tmplStr := strings.TrimSpace(string(tmpl))
 	if tmplStr == "" {
 		debug.Log("Skipping empty template %q, for %s", tName, name)
 
-		return content, false
+		return content, false, nil
 	}
 
 	// load template if it exists.
 	nc, err := tpl.Execute(ctx, string(tmpl), name, content, s.Store)
 	if err != nil {
-		fmt.Fprintf(stdout, "failed to execute template %q: %s\n", tName, err)
-
-		return content, false
+		return content, false, fmt.Errorf("failed to execute template %q: %w", tName, err)
 	}
 
 	out.Printf(ctx, "Note: Using template %s", tName)
 
-	return nc, true
+	return nc, true, nil
+}
+
+func (s *Action) lookupTemplate(ctx context.Context, name, tplName string) (string, []byte, bool, error) {
+	if tplName == "" {
+		tName, tmpl, found := s.Store.LookupTemplate(ctx, name)
+
+		return tName, tmpl, found, nil
+	}
+
+	if !s.Store.HasTemplate(ctx, tplName) {
+		return "", nil, false, fmt.Errorf("template %q not found", tplName)
+	}
+
+	tmpl, err := s.Store.GetTemplate(ctx, tplName)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to read template %q: %w", tplName, err)
+	}
+
+	return tplName, tmpl, true, nil
 }
@@ -1,6 +1,9 @@
 package action
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -18,6 +21,10 @@ func (s *Action) Unclip(c *cli.Context) error {
 	name := os.Getenv("GOPASS_UNCLIP_NAME")
 	checksum := os.Getenv("GOPASS_UNCLIP_CHECKSUM")
 
+	if c.Bool("serve") {
+		return s.unclipServe(ctx, name, timeout)
+	}
+
 	time.Sleep(time.Second * time.Duration(timeout))
 	if err := clipboard.Clear(ctx, name, checksum, force); err != nil {
 		return exit.Error(exit.IO, err, "Failed to clear clipboard: %s", err)
@@ -25,3 +32,21 @@ func (s *Action) Unclip(c *cli.Context) error {
 
 	return nil
 }
+
+// unclipServe owns the Wayland clipboard until it's replaced or the timeout
+// expires. The content is read from stdin and "ready" is written to stdout
+// once the clipboard was set.
+func (s *Action) unclipServe(ctx context.Context, name string, timeout int) error {
+	content, err := io.ReadAll(stdin)
+	if err != nil {
+		return exit.Error(exit.IO, err, "Failed to read content: %s", err)
+	}
+
+	if err := clipboard.Serve(ctx, name, content, timeout, func() {
+		fmt.Fprintln(stdout, "ready")
+	}); err != nil {
+		return exit.Error(exit.IO, err, "Failed to serve clipboard: %s", err)
+	}
+
+	return nil
+}
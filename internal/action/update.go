@@ -2,6 +2,7 @@ package action
 
 import (
 	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/updater"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -25,6 +26,11 @@ func (s *Action) Update(c *cli.Context) error {
 		return exit.Error(exit.Unknown, err, "Failed to update gopass: %s", err)
 	}
 
+	// the updater already reported whether a new release is available.
+	if config.Bool(ctx, "updater.notifyonly") {
+		return nil
+	}
+
 	out.OKf(ctx, "gopass is up to date")
 
 	return nil
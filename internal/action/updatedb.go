@@ -0,0 +1,55 @@
+package action
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gopasspw/gopass/internal/action/exit"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/updatedb"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// UpdateDB fetches the latest password rules, change URLs and domain aliases.
+func (s *Action) UpdateDB(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	out.Printf(ctx, "⚒ Fetching password rules from %s ...", updatedb.URL(ctx))
+	res, err := updatedb.Update(ctx, c.Bool("force"))
+	if err != nil {
+		if errors.Is(err, updatedb.ErrTooFrequent) {
+			return exit.Error(exit.Aborted, err, "%s. Use --force to check again", err)
+		}
+
+		return exit.Error(exit.IO, err, "Failed to update the password rules: %s", err)
+	}
+
+	if !res.Updated {
+		out.OKf(ctx, "Password rules are up to date (%s)", res.Generated.Format("2006-01-02"))
+
+		return nil
+	}
+
+	out.OKf(ctx, "Updated password rules to %s: %d rules, %d aliases, %d change URLs", res.Generated.Format("2006-01-02"), res.Rules, res.Aliases, res.ChangeURLs)
+
+	return nil
+}
+
+// autoUpdateDB refreshes the password rules in the background if
+// updatedb.auto is enabled and they weren't checked for a while.
+func autoUpdateDB(ctx context.Context) {
+	if !updatedb.Due(ctx) {
+		return
+	}
+
+	queue.GetQueue(ctx).Add(func(ctx context.Context) (context.Context, error) {
+		if _, err := updatedb.Update(ctx, false); err != nil {
+			debug.Log("failed to update password rules: %s", err)
+		}
+
+		return ctx, nil
+	})
+}
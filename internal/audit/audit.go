@@ -13,6 +13,7 @@ import (
 	"github.com/gopasspw/gopass-hibp/pkg/hibp/api"
 	"github.com/gopasspw/gopass-hibp/pkg/hibp/dump"
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/canary"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/hashsum"
 	"github.com/gopasspw/gopass/internal/out"
@@ -32,6 +33,12 @@ type secretGetter interface {
 	Concurrency() int
 }
 
+// policyChecker is implemented by stores with password policy packs.
+type policyChecker interface {
+	HasPolicyPacks(ctx context.Context) bool
+	CheckPolicy(ctx context.Context, name, pw string) error
+}
+
 type validator struct {
 	Name        string
 	Description string
@@ -99,6 +106,16 @@ func New(ctx context.Context, s secretGetter) *Auditor {
 		},
 	}
 
+	if pc, ok := s.(policyChecker); ok && pc.HasPolicyPacks(ctx) {
+		a.v = append(a.v, validator{
+			Name:        "policy",
+			Description: "Checks passwords against the policy packs of the store",
+			Validate: func(name string, sec gopass.Secret) error {
+				return pc.CheckPolicy(ctx, name, sec.Password())
+			},
+		})
+	}
+
 	if config.Bool(ctx, "audit.hibp-use-api") {
 		a.v = append(a.v, validator{
 			Name:        "hibp",
@@ -129,6 +146,9 @@ func New(ctx context.Context, s secretGetter) *Auditor {
 func (a *Auditor) Batch(ctx context.Context, secrets []string) (*Report, error) {
 	out.Printf(ctx, "Checking %d secrets. This may take some time ...\n", len(secrets))
 
+	// the audit doesn't reveal any secrets, so don't trigger canaries.
+	ctx = canary.WithSuppressed(ctx, true)
+
 	a.r = newReport()
 	pending := make(chan string, 1024)
 
@@ -201,8 +221,10 @@ func (a *Auditor) auditSecret(ctx context.Context, secret string) {
 	if err != nil {
 		a.r.AddFinding(secret, "error-revisions", err.Error(), "error")
 	}
+	var age time.Duration
 	if len(revs) > 0 {
-		a.r.SetAge(secret, time.Since(revs[0].Date))
+		age = time.Since(revs[0].Date)
+		a.r.SetAge(secret, age)
 	}
 
 	sec, err := a.s.Get(ctx, secret)
@@ -217,6 +239,9 @@ func (a *Auditor) auditSecret(ctx context.Context, secret string) {
 		return
 	}
 
+	// entries with only an OTP seed are checked, too.
+	a.checkOTP(ctx, secret, sec, age)
+
 	// do not check empty secrets.
 	if sec.Password() == "" {
 		return
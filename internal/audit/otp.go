@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/otp"
+)
+
+// otpMaxAge returns how long an entry with an OTP seed may go without a
+// password change.
+func otpMaxAge(ctx context.Context) time.Duration {
+	if d := config.Int(ctx, "audit.otp-max-age"); d > 0 {
+		return time.Duration(d) * 24 * time.Hour
+	}
+
+	return DefaultExpiration
+}
+
+// checkOTP runs the consistency checks of entries with an OTP seed. They
+// catch copy and paste mistakes: a seed stored with the wrong entry, the same
+// seed in two entries or a password that was never rotated because the
+// second factor felt safe enough. age is zero if unknown.
+func (a *Auditor) checkOTP(ctx context.Context, name string, sec gopass.Secret, age time.Duration) {
+	if !otp.Has(sec) {
+		return
+	}
+
+	key, err := otp.Calculate(name, sec)
+	if err != nil {
+		a.r.AddFinding(name, "otp", fmt.Sprintf("invalid OTP seed: %s", err), "warning")
+
+		return
+	}
+
+	a.r.AddOTPSeed(name, key.Secret())
+
+	if maxAge := otpMaxAge(ctx); sec.Password() != "" && age > maxAge {
+		a.r.AddFinding(name, "otp-rotation", fmt.Sprintf("password not changed for %d days even though it has an OTP seed", int(age.Hours()/24)), "warning")
+	}
+
+	if issuer := key.Issuer(); !otpIssuerMatches(name, issuer) {
+		a.r.AddFinding(name, "otp-issuer", fmt.Sprintf("OTP issuer %q does not match the entry name", issuer), "warning")
+	}
+}
+
+// otpIgnoredComponents are parts of entry names that are too common to
+// match an issuer.
+var otpIgnoredComponents = map[string]bool{"com": true, "net": true, "org": true, "www": true}
+
+// otpIssuerMatches returns true if the issuer of an OTP seed matches one of
+// the components of the entry name, e.g. GitHub and websites/github.com/alice.
+// Seeds without an issuer always match.
+func otpIssuerMatches(name, issuer string) bool {
+	issuer = otpNormalize(issuer)
+	if issuer == "" || issuer == "gopass" {
+		return true
+	}
+
+	for _, c := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '/' || r == '.' || r == '@' || r == '-' || r == '_'
+	}) {
+		c = otpNormalize(c)
+		if len(c) < 3 || otpIgnoredComponents[c] {
+			continue
+		}
+
+		if strings.Contains(issuer, c) || strings.Contains(c, issuer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// otpNormalize keeps only the lower case letters and digits of s.
+func otpNormalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, s)
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPIssuerMatches(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		issuer string
+		want   bool
+	}{
+		{name: "websites/github.com/alice", issuer: "GitHub", want: true},
+		{name: "websites/accounts.google.com", issuer: "Google", want: true},
+		{name: "work/aws", issuer: "AWS", want: true},
+		{name: "websites/gitlab.com/alice", issuer: "", want: true},
+		{name: "websites/gitlab.com/alice", issuer: "gopass", want: true},
+		{name: "websites/gitlab.com/alice", issuer: "GitHub", want: false},
+		{name: "websites/example.com", issuer: "Comcast", want: false},
+	} {
+		assert.Equal(t, tc.want, otpIssuerMatches(tc.name, tc.issuer), tc.name+" "+tc.issuer)
+	}
+}
+
+func TestCheckOTP(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	a := &Auditor{r: newReport()}
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("otpauth", "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"))
+
+	a.checkOTP(ctx, "websites/github.com/alice", sec, time.Hour)
+	a.checkOTP(ctx, "websites/gitlab.com/alice", sec, 2*DefaultExpiration)
+
+	plain := secrets.NewAKV()
+	plain.SetPassword("secret")
+	a.checkOTP(ctx, "websites/example.org", plain, 2*DefaultExpiration)
+
+	seedOnly := secrets.NewAKV()
+	require.NoError(t, seedOnly.Set("totp", "jbsw y3dp ehpk 3pxp"))
+	a.checkOTP(ctx, "backup/github", seedOnly, 2*DefaultExpiration)
+
+	r := a.r.Finalize()
+
+	gh := r.Secrets["websites/github.com/alice"].Findings
+	assert.NotContains(t, gh, "otp-rotation")
+	assert.NotContains(t, gh, "otp-issuer")
+	assert.Contains(t, gh["otp-duplicates"].Message, "websites/gitlab.com/alice")
+	assert.Contains(t, gh["otp-duplicates"].Message, "backup/github")
+
+	gl := r.Secrets["websites/gitlab.com/alice"].Findings
+	assert.Contains(t, gl, "otp-rotation")
+	assert.Contains(t, gl, "otp-issuer")
+
+	assert.NotContains(t, r.Secrets["backup/github"].Findings, "otp-rotation")
+	assert.NotContains(t, r.Secrets, "websites/example.org")
+
+	t.Run("audit.otp-max-age", func(t *testing.T) {
+		require.NoError(t, cfg.SetEnv("audit.otp-max-age", "30"))
+		assert.Equal(t, 30*24*time.Hour, otpMaxAge(ctx))
+	})
+}
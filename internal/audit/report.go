@@ -2,6 +2,7 @@ package audit
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -46,6 +47,9 @@ type ReportBuilder struct {
 	// SHA1(password) -> secret names
 	sha1sums map[string]set.Set[string]
 
+	// SHA256(OTP seed) -> secret names
+	otpSeeds map[string]set.Set[string]
+
 	t0 time.Time
 }
 
@@ -68,6 +72,22 @@ func (r *ReportBuilder) AddPassword(name, pw string) {
 	r.sha1sums[s1] = s
 }
 
+// AddOTPSeed records the OTP seed of an entry for the duplicate check.
+func (r *ReportBuilder) AddOTPSeed(name, seed string) {
+	seed = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(seed, " ", "")), "=")
+	if name == "" || seed == "" {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	s256 := hashsum.SHA256Hex(seed)
+	d := r.otpSeeds[s256]
+	d.Add(name)
+	r.otpSeeds[s256] = d
+}
+
 func (r *ReportBuilder) AddFinding(secret, finding, message, severity string) {
 	if secret == "" || finding == "" || message == "" || severity == "" {
 		return
@@ -107,6 +127,7 @@ func newReport() *ReportBuilder {
 		secrets:    make(map[string]SecretReport, 512),
 		duplicates: make(map[string]set.Set[string], 512),
 		sha1sums:   make(map[string]set.Set[string], 512),
+		otpSeeds:   make(map[string]set.Set[string], 64),
 		t0:         time.Now().UTC(),
 	}
 }
@@ -132,6 +153,25 @@ func (r *ReportBuilder) Finalize() *Report {
 		r.secrets[k] = s
 	}
 
+	for _, secs := range r.otpSeeds {
+		if secs.Len() < 2 {
+			continue
+		}
+
+		for _, k := range secs.Elements() {
+			s := r.secrets[k]
+			s.Name = k
+			if s.Findings == nil {
+				s.Findings = make(map[string]Finding, 1)
+			}
+			s.Findings["otp-duplicates"] = Finding{
+				Severity: "warning",
+				Message:  fmt.Sprintf("OTP seed is shared with: %+v", secs.Difference(set.New(k))),
+			}
+			r.secrets[k] = s
+		}
+	}
+
 	ret := &Report{
 		Secrets:  make(map[string]SecretReport, len(r.secrets)),
 		Duration: time.Since(r.t0),
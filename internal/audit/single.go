@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gopasspw/gopass-hibp/pkg/hibp/dump"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/hashsum"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/fsutil"
 	"github.com/muesli/crunchy"
 )
 
@@ -15,3 +19,19 @@ func Single(ctx context.Context, password string) {
 		out.Printf(ctx, fmt.Sprintf("Warning: %s", err))
 	}
 }
+
+// Breached checks if the password is contained in the local HIBP dump. It
+// never uses the HIBP API, so it is safe to use for new passwords.
+func Breached(ctx context.Context, password string) (bool, error) {
+	fn := config.String(ctx, "audit.hibp-dump-file")
+	if fn == "" || !fsutil.IsFile(fn) {
+		return false, fmt.Errorf("audit.hibp-dump-file is not pointing to a valid HIBP dump")
+	}
+
+	scanner, err := dump.New(fn)
+	if err != nil {
+		return false, fmt.Errorf("invalid HIBP dump %s: %w", fn, err)
+	}
+
+	return len(scanner.LookupBatch(ctx, []string{hashsum.SHA1Hex(password)})) > 0, nil
+}
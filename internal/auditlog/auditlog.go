@@ -0,0 +1,88 @@
+// Package auditlog implements a local, append-only log of security relevant
+// events, e.g. access to canary entries. Each event is written as a single
+// JSON object per line.
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Event is a single entry in the audit log.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Entry   string    `json:"entry,omitempty"`
+	Host    string    `json:"host"`
+	User    string    `json:"user"`
+	PID     int       `json:"pid"`
+	Command []string  `json:"command"`
+}
+
+var mu sync.Mutex
+
+// Path returns the location of the audit log.
+func Path() string {
+	return filepath.Join(appdir.UserData(), "audit.log")
+}
+
+// NewEvent returns a new event with the details of the current process
+// filled in.
+func NewEvent(event, entry string) Event {
+	e := Event{
+		Time:    time.Now().UTC(),
+		Event:   event,
+		Entry:   entry,
+		PID:     os.Getpid(),
+		Command: os.Args,
+	}
+
+	if hn, err := os.Hostname(); err == nil {
+		e.Host = hn
+	}
+
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+
+	return e
+}
+
+// Write appends the event to the audit log.
+func Write(ctx context.Context, e Event) error {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	fn := Path()
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(fn), err)
+	}
+
+	fh, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", fn, err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	if _, err := fh.Write(append(buf, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log %s: %w", fn, err)
+	}
+
+	debug.Log("wrote %s event for %q to %s", e.Event, e.Entry, fn)
+
+	return nil
+}
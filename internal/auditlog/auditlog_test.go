@@ -0,0 +1,37 @@
+package auditlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+
+	require.NoError(t, Write(ctx, NewEvent("canary", "foo/bar")))
+	require.NoError(t, Write(ctx, NewEvent("canary", "foo/baz")))
+
+	fh, err := os.Open(Path())
+	require.NoError(t, err)
+	defer fh.Close() //nolint:errcheck
+
+	var entries []string
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(s.Bytes(), &e))
+		assert.Equal(t, "canary", e.Event)
+		assert.Equal(t, os.Getpid(), e.PID)
+		entries = append(entries, e.Entry)
+	}
+
+	assert.Equal(t, []string{"foo/bar", "foo/baz"}, entries)
+}
@@ -0,0 +1,112 @@
+// Package autolock keeps track of when gopass was used last, so the
+// passphrases and keys cached by the crypto backends, e.g. in gpg-agent or the
+// OS keyring, can be wiped after core.autolock minutes of inactivity or when
+// the desktop session locks.
+package autolock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/internal/config"
+)
+
+const (
+	keyUsed   = "used"
+	keyLocked = "locked"
+)
+
+// ErrUnsupported is returned if screen lock events can't be watched on this
+// platform.
+var ErrUnsupported = errors.New("watching the screen lock is not supported on this platform")
+
+// State is the persisted lock state.
+type State struct {
+	// LastUsed is the time gopass was used last.
+	LastUsed time.Time
+	// Locked is the time of the last lock.
+	Locked time.Time
+}
+
+// IsLocked returns true if gopass wasn't used since the last lock.
+func (s State) IsLocked() bool {
+	return !s.Locked.IsZero() && !s.Locked.Before(s.LastUsed)
+}
+
+// Timeout returns the idle time after which the caches are wiped. It returns
+// 0 if auto-locking is disabled.
+func Timeout(ctx context.Context) time.Duration {
+	if m := config.Int(ctx, "core.autolock"); m > 0 {
+		return time.Duration(m) * time.Minute
+	}
+
+	return 0
+}
+
+// Due returns true if the caches should be wiped because gopass was idle for
+// longer than the timeout.
+func Due(ctx context.Context, s State) bool {
+	to := Timeout(ctx)
+	if to == 0 || s.IsLocked() || s.LastUsed.IsZero() {
+		return false
+	}
+
+	return time.Since(s.LastUsed) > to
+}
+
+func open() (*cache.OnDisk, error) {
+	od, err := cache.NewOnDisk("autolock", 24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init autolock cache: %w", err)
+	}
+
+	return od, nil
+}
+
+// Load returns the persisted lock state.
+func Load() (State, error) {
+	od, err := open()
+	if err != nil {
+		return State{}, err
+	}
+
+	return State{
+		LastUsed: stamp(od, keyUsed),
+		Locked:   stamp(od, keyLocked),
+	}, nil
+}
+
+// stamp returns the recorded time. The modification time of the file is only
+// a fallback, its resolution is too coarse to order a lock and an unlock in
+// quick succession.
+func stamp(od *cache.OnDisk, key string) time.Time {
+	if v, err := od.Get(key); err == nil && len(v) > 0 {
+		if t, err := time.Parse(time.RFC3339Nano, v[0]); err == nil {
+			return t
+		}
+	}
+
+	return od.ModTime(key)
+}
+
+// Touch records that gopass was used just now.
+func Touch() error {
+	return mark(keyUsed)
+}
+
+// MarkLocked records that the caches were wiped just now.
+func MarkLocked() error {
+	return mark(keyLocked)
+}
+
+func mark(key string) error {
+	od, err := open()
+	if err != nil {
+		return err
+	}
+
+	return od.Set(key, []string{time.Now().Format(time.RFC3339Nano)})
+}
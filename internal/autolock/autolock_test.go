@@ -0,0 +1,64 @@
+package autolock
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDue(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	idle := State{LastUsed: time.Now().Add(-time.Hour)}
+	assert.False(t, Due(ctx, idle), "disabled by default")
+
+	require.NoError(t, cfg.SetEnv("core.autolock", "15"))
+	assert.Equal(t, 15*time.Minute, Timeout(ctx))
+	assert.True(t, Due(ctx, idle))
+	assert.False(t, Due(ctx, State{LastUsed: time.Now()}))
+	assert.False(t, Due(ctx, State{}), "never used")
+	assert.False(t, Due(ctx, State{LastUsed: idle.LastUsed, Locked: time.Now()}), "already locked")
+}
+
+func TestState(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	s, err := Load()
+	require.NoError(t, err)
+	assert.True(t, s.LastUsed.IsZero())
+	assert.False(t, s.IsLocked())
+
+	require.NoError(t, Touch())
+	require.NoError(t, MarkLocked())
+	s, err = Load()
+	require.NoError(t, err)
+	assert.False(t, s.LastUsed.IsZero())
+	assert.True(t, s.IsLocked())
+}
+
+func TestLockEvents(t *testing.T) {
+	t.Parallel()
+
+	monitor := `Monitoring signals from all objects owned by org.freedesktop.login1
+The name org.freedesktop.login1 is owned by :1.3
+/org/freedesktop/login1/session/_32: org.freedesktop.DBus.Properties.PropertiesChanged ('org.freedesktop.login1.Session', {'IdleHint': <true>}, @as [])
+/org/freedesktop/login1/session/_32: org.freedesktop.login1.Session.Lock ()
+/org/freedesktop/ScreenSaver: org.freedesktop.ScreenSaver.ActiveChanged (false,)
+/org/freedesktop/ScreenSaver: org.freedesktop.ScreenSaver.ActiveChanged (true,)
+`
+
+	ch := make(chan struct{}, 2)
+	scanLockEvents(bytes.NewBufferString(monitor), ch)
+	assert.Len(t, ch, 2)
+
+	assert.True(t, ioregLocked([]byte(`    "IOConsoleUsers" = ({"kCGSSessionOnConsoleKey"=Yes,"CGSSessionScreenIsLocked"=Yes})`)))
+	assert.False(t, ioregLocked([]byte(`    "IOConsoleUsers" = ({"kCGSSessionOnConsoleKey"=Yes})`)))
+}
@@ -0,0 +1,81 @@
+package autolock
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Watch calls lock whenever the screen locks and whenever gopass was idle for
+// longer than the timeout. It blocks until the context is canceled.
+func Watch(ctx context.Context, lock func(reason string)) error {
+	events, err := screenLocks(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrUnsupported) || Timeout(ctx) == 0 {
+			return err
+		}
+		debug.Log("not watching the screen lock: %s", err)
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-events:
+			lock("the screen was locked")
+		case <-ticker.C:
+			s, err := Load()
+			if err != nil {
+				debug.Log("failed to load lock state: %s", err)
+
+				continue
+			}
+
+			if Due(ctx, s) {
+				lock("gopass was idle for " + Timeout(ctx).String())
+			}
+		}
+	}
+}
+
+// scanLockEvents sends an event for every line of gdbus monitor output that
+// signals a screen lock.
+func scanLockEvents(r io.Reader, ch chan<- struct{}) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if !isLockEvent(s.Text()) {
+			continue
+		}
+
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// isLockEvent returns true for the signals of logind and the screen savers
+// that are sent when the session locks, e.g.
+// /org/freedesktop/login1/session/_32: org.freedesktop.login1.Session.Lock ().
+func isLockEvent(line string) bool {
+	if strings.Contains(line, ".login1.Session.Lock (") {
+		return true
+	}
+
+	return strings.Contains(line, "ScreenSaver.ActiveChanged (true")
+}
+
+// ioregLocked returns true if the output of ioreg -n Root -d1 says the screen
+// is locked.
+func ioregLocked(buf []byte) bool {
+	return bytes.Contains(buf, []byte(`"CGSSessionScreenIsLocked"=Yes`))
+}
@@ -0,0 +1,50 @@
+//go:build darwin
+// +build darwin
+
+package autolock
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// pollInterval is the time between two checks of the screen lock.
+const pollInterval = 5 * time.Second
+
+// screenLocks polls the state of the session with ioreg. macOS only
+// announces the screen lock as a distributed notification, which can't be
+// received without Cocoa.
+func screenLocks(ctx context.Context) (<-chan struct{}, error) {
+	ch := make(chan struct{}, 1)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		var locked bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			buf, err := exec.CommandContext(ctx, "ioreg", "-n", "Root", "-d1").Output()
+			if err != nil {
+				continue
+			}
+
+			now := ioregLocked(buf)
+			if now && !locked {
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+			locked = now
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,54 @@
+//go:build linux
+// +build linux
+
+package autolock
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// monitors are the D-Bus services that announce a screen lock.
+var monitors = [][]string{
+	{"--system", "--dest", "org.freedesktop.login1"},
+	{"--session", "--dest", "org.freedesktop.ScreenSaver"},
+	{"--session", "--dest", "org.gnome.ScreenSaver"},
+}
+
+// screenLocks watches logind and the screen savers with gdbus.
+func screenLocks(ctx context.Context) (<-chan struct{}, error) {
+	if _, err := exec.LookPath("gdbus"); err != nil {
+		return nil, fmt.Errorf("gdbus not found: %w", ErrUnsupported)
+	}
+
+	ch := make(chan struct{}, 1)
+	var started int
+	for _, args := range monitors {
+		cmd := exec.CommandContext(ctx, "gdbus", append([]string{"monitor"}, args...)...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cmd.Start(); err != nil {
+			debug.Log("failed to start %s: %s", cmd, err)
+
+			continue
+		}
+		started++
+
+		go func() {
+			scanLockEvents(stdout, ch)
+			_ = cmd.Wait()
+		}()
+	}
+
+	if started == 0 {
+		return nil, fmt.Errorf("failed to monitor D-Bus: %w", ErrUnsupported)
+	}
+
+	return ch, nil
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package autolock
+
+import "context"
+
+func screenLocks(ctx context.Context) (<-chan struct{}, error) {
+	return nil, ErrUnsupported
+}
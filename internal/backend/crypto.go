@@ -43,6 +43,28 @@ type Keyring interface {
 	GenerateIdentity(ctx context.Context, name, email, passphrase string) error
 }
 
+// Locker is implemented by crypto backends that cache passphrases or unlocked
+// keys outside of the gopass process, e.g. in an agent or the OS keyring.
+type Locker interface {
+	// Lock wipes the cached passphrases and keys.
+	Lock(ctx context.Context) error
+}
+
+// ErrBadSignature is returned if a signature wasn't made by any of the given
+// recipients.
+var ErrBadSignature = fmt.Errorf("no valid signature by any of the recipients")
+
+// Signer is implemented by crypto backends that can sign with the private key
+// of a recipient, e.g. to prove who approved a change.
+type Signer interface {
+	// Sign returns a detached signature of data made with the private key of
+	// the recipient id.
+	Sign(ctx context.Context, id string, data []byte) ([]byte, error)
+	// Verify returns the one of the recipients that made the signature or
+	// ErrBadSignature.
+	Verify(ctx context.Context, data, sig []byte, recipients []string) (string, error)
+}
+
 // Crypto is a crypto backend.
 type Crypto interface {
 	Keyring
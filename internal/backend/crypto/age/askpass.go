@@ -2,6 +2,7 @@ package age
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -160,7 +161,14 @@ func (a *askPass) Remove(key string) {
 	a.cache.Remove(key)
 }
 
-// Lock flushes the password cache.
-func (a *Age) Lock() {
+// Lock flushes the password cache. The passphrase of the identities is
+// removed from the OS keyring even if it was cached by another process.
+func (a *Age) Lock(_ context.Context) error {
 	a.askPass.cache.Purge()
+
+	if err := keyring.Delete("gopass", a.identity); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		debug.Log("failed to remove %s from keyring: %s", a.identity, err)
+	}
+
+	return nil
 }
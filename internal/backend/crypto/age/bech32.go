@@ -0,0 +1,140 @@
+package age
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bech32 encoding as specified in BIP 173. The age package does not export
+// its encoder, but we need it to construct X25519 identities from raw keys.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	gen := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	r := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		r = append(r, byte(c>>5))
+	}
+	r = append(r, 0)
+	for _, c := range hrp {
+		r = append(r, byte(c&31))
+	}
+
+	return r
+}
+
+// bech32ConvertBits regroups 8-bit bytes into 5-bit groups, padding the last one.
+func bech32ConvertBits(data []byte) []byte {
+	var (
+		acc  uint32
+		bits uint
+		r    = make([]byte, 0, len(data)*8/5+1)
+	)
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			r = append(r, byte(acc>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		r = append(r, byte(acc<<(5-bits))&31)
+	}
+
+	return r
+}
+
+// bech32Encode encodes data with the given (lower case) human readable part.
+func bech32Encode(hrp string, data []byte) string {
+	values := bech32ConvertBits(data)
+
+	poly := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(poly>>uint(5*(5-i)))&31])
+	}
+
+	return sb.String()
+}
+
+// bech32Decode decodes a bech32 string and returns the lower case human
+// readable part and the data. Unlike BIP 173 it does not limit the length,
+// since post-quantum keys are much longer than 90 characters.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("mixed case")
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("separator '1' at invalid position")
+	}
+
+	hrp := s[:pos]
+	values := make([]byte, 0, len(s)-pos-1)
+	for _, c := range s[pos+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", c)
+		}
+		values = append(values, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum")
+	}
+
+	data, err := bech32RegroupBits(values[:len(values)-6])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
+
+// bech32RegroupBits regroups 5-bit groups into 8-bit bytes. It's the inverse
+// of bech32ConvertBits.
+func bech32RegroupBits(data []byte) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		r    = make([]byte, 0, len(data)*5/8)
+	)
+	for _, b := range data {
+		acc = acc<<5 | uint32(b)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			r = append(r, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || byte(acc<<(8-bits)) != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return r, nil
+}
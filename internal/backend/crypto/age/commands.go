@@ -1,7 +1,9 @@
 package age
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"filippo.io/age"
 	"github.com/gopasspw/gopass/internal/action/exit"
@@ -52,7 +54,13 @@ func (l loader) Commands() []*cli.Command {
 							Name:  "add",
 							Usage: "Add an identity",
 							Description: "" +
-								"Add an identity",
+								"Add an identity. Use --pq to add a hybrid post-quantum (ML-KEM-768 + X25519) identity.",
+							Flags: []cli.Flag{
+								&cli.BoolFlag{
+									Name:  "pq",
+									Usage: "Generate a hybrid post-quantum identity",
+								},
+							},
 							Action: func(c *cli.Context) error {
 								ctx := ctxutil.WithGlobalFlags(c)
 								a, err := New(ctx)
@@ -60,6 +68,14 @@ func (l loader) Commands() []*cli.Command {
 									return exit.Error(exit.Unknown, err, "failed to create age backend")
 								}
 
+								if c.Bool("pq") {
+									if err := a.GeneratePQIdentity(ctx); err != nil {
+										return exit.Error(exit.Unknown, err, "failed to generate post-quantum age identity: %s", err)
+									}
+
+									return nil
+								}
+
 								if err := a.GenerateIdentity(ctx, "", "", ""); err != nil {
 									return exit.Error(exit.Unknown, err, "failed to generate age identity")
 								}
@@ -67,6 +83,40 @@ func (l loader) Commands() []*cli.Command {
 								return nil
 							},
 						},
+						{
+							Name:  "ssh-agent",
+							Usage: "List recipients derived from ssh-agent keys",
+							Description: "" +
+								"List the recipients of the identities derived from the Ed25519 and RSA keys held by the running ssh-agent. " +
+								"Add these recipients to a store and enable age.ssh-agent to decrypt without any key files on disk.",
+							Action: func(c *cli.Context) error {
+								ctx := ctxutil.WithGlobalFlags(c)
+								a, err := New(ctx)
+								if err != nil {
+									return exit.Error(exit.Unknown, err, "failed to create age backend")
+								}
+
+								recps, err := a.SSHAgentRecipients(ctx)
+								if errors.Is(err, ErrNoSSHAgent) {
+									out.Notice(ctx, "No ssh-agent running")
+
+									return nil
+								}
+								if err != nil {
+									return exit.Error(exit.Unknown, err, "failed to get ssh-agent identities: %s", err)
+								}
+
+								if len(recps) < 1 {
+									out.Notice(ctx, "No suitable keys found in ssh-agent")
+								}
+
+								for _, r := range recps {
+									out.Printf(ctx, r)
+								}
+
+								return nil
+							},
+						},
 						{
 							Name:  "remove",
 							Usage: "Remove an identity",
@@ -90,6 +140,9 @@ func (l loader) Commands() []*cli.Command {
 									if x, ok := id.(*age.X25519Identity); ok && x.Recipient().String() == victim {
 										continue
 									}
+									if x, ok := id.(*PQIdentity); ok && x.Recipient().String() == victim {
+										continue
+									}
 									newIds = append(newIds, fmt.Sprintf("%s", id))
 								}
 
@@ -100,5 +153,116 @@ func (l loader) Commands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "tpm",
+			Usage: "Bind the age keyring to the TPM",
+			Description: "" +
+				"Seal a random key encryption key to the TPM of this machine, bound to a PCR policy, " +
+				"and re-encrypt the age keyring to it. The keyring can then be unlocked without a prompt, " +
+				"but only on this machine and only in an untampered boot state. Requires tpm2-tools.",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "enroll",
+					Usage: "Bind the keyring to the TPM",
+					Description: "" +
+						"Seal a new key encryption key to the TPM and re-encrypt the keyring to it. " +
+						"The keyring passphrase no longer unlocks the keyring afterwards. " +
+						"Run 'gopass tpm unenroll' before firmware or boot configuration changes.",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "pcrs",
+							Usage: "PCR selection to bind the keyring to. Defaults to age.tpm-pcrs or " + DefaultTPMPCRs,
+						},
+					},
+					Action: func(c *cli.Context) error {
+						ctx := ctxutil.WithGlobalFlags(c)
+						a, err := New(ctx)
+						if err != nil {
+							return exit.Error(exit.Unknown, err, "failed to create age backend")
+						}
+
+						pcrs := c.String("pcrs")
+						if pcrs == "" {
+							pcrs = TPMPCRs(ctx)
+						}
+
+						if err := a.TPMEnroll(ctx, pcrs); err != nil {
+							return exit.Error(exit.Unknown, err, "failed to bind keyring to TPM: %s", err)
+						}
+
+						out.OKf(ctx, "Bound keyring to TPM (PCRs %s)", pcrs)
+						out.Noticef(ctx, "Run 'gopass tpm unenroll' before firmware or boot configuration changes")
+
+						return nil
+					},
+				},
+				{
+					Name:  "unenroll",
+					Usage: "Remove the TPM binding of the keyring",
+					Description: "" +
+						"Re-encrypt the keyring with a new passphrase and remove the sealed key encryption key.",
+					Action: func(c *cli.Context) error {
+						ctx := ctxutil.WithGlobalFlags(c)
+						a, err := New(ctx)
+						if err != nil {
+							return exit.Error(exit.Unknown, err, "failed to create age backend")
+						}
+
+						if err := a.TPMUnenroll(ctx); err != nil {
+							return exit.Error(exit.Unknown, err, "failed to remove TPM binding: %s", err)
+						}
+
+						out.OKf(ctx, "Keyring is protected by its passphrase again")
+
+						return nil
+					},
+				},
+				{
+					Name:  "unseal",
+					Usage: "Check that the keyring can be unsealed",
+					Description: "" +
+						"Unseal the key encryption key from the TPM and verify that it unlocks the keyring. " +
+						"The key is never printed.",
+					Action: func(c *cli.Context) error {
+						ctx := ctxutil.WithGlobalFlags(c)
+						a, err := New(ctx)
+						if err != nil {
+							return exit.Error(exit.Unknown, err, "failed to create age backend")
+						}
+
+						if _, err := a.decryptFileWithTPM(ctx, a.identity); err != nil {
+							return exit.Error(exit.Decrypt, err, "failed to unlock %s with the TPM: %s", a.identity, err)
+						}
+
+						out.OKf(ctx, "Unlocked keyring with the TPM")
+
+						return nil
+					},
+				},
+				{
+					Name:  "status",
+					Usage: "Show the TPM status",
+					Description: "" +
+						"Show if a TPM and the required tools are available and if the keyring is bound to it.",
+					Action: func(c *cli.Context) error {
+						ctx := ctxutil.WithGlobalFlags(c)
+						a, err := New(ctx)
+						if err != nil {
+							return exit.Error(exit.Unknown, err, "failed to create age backend")
+						}
+
+						st := a.TPMStatus(ctx)
+						out.Printf(ctx, "TPM device: %t", st.Device)
+						if len(st.Missing) > 0 {
+							out.Printf(ctx, "Missing tools: %s", strings.Join(st.Missing, ", "))
+						}
+						out.Printf(ctx, "Enrolled: %t", st.Enrolled)
+						out.Printf(ctx, "PCRs: %s", st.PCRs)
+
+						return nil
+					},
+				},
+			},
+		},
 	}
 }
@@ -55,22 +55,39 @@ func (a *Age) decryptFile(ctx context.Context, filename string) ([]byte, error)
 	}
 	debug.Log("read %d bytes from %s", len(ciphertext), filename)
 
+	if a.tpmSealed(filename) {
+		return a.decryptFileWithTPM(ctx, filename)
+	}
+
 	pw, err := ctxutil.GetPasswordCallback(ctx)(filename, false)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := age.NewScryptIdentity(string(pw))
+	plaintext, err := a.decryptWithPassphrase(ciphertext, pw)
+	if err != nil {
+		ctxutil.GetPasswordPurgeCallback(ctx)(filename)
+	}
+
+	return plaintext, err
+}
+
+func (a *Age) decryptFileWithPassphrase(filename string, pw []byte) ([]byte, error) {
+	ciphertext, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	plaintext, err := a.decrypt(ciphertext, id)
+	return a.decryptWithPassphrase(ciphertext, pw)
+}
+
+func (a *Age) decryptWithPassphrase(ciphertext, pw []byte) ([]byte, error) {
+	id, err := age.NewScryptIdentity(string(pw))
 	if err != nil {
-		ctxutil.GetPasswordPurgeCallback(ctx)(filename)
+		return nil, err
 	}
 
-	return plaintext, err
+	return a.decrypt(ciphertext, id)
 }
 
 func (a *Age) getAllIds(ctx context.Context) ([]age.Identity, error) {
@@ -72,6 +72,20 @@ func (a *Age) encrypt(plaintext []byte, recp ...age.Recipient) ([]byte, error) {
 }
 
 func (a *Age) encryptFile(ctx context.Context, filename string, plaintext []byte, confirm bool) error {
+	if a.tpmSealed(filename) {
+		kek, err := a.TPMUnseal(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to unseal key encryption key for %s from TPM: %w", filename, err)
+		}
+
+		buf, err := a.encrypt(plaintext, kek.Recipient())
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(filename, buf, 0o600)
+	}
+
 	pw, err := ctxutil.GetPasswordCallback(ctx)(filename, confirm)
 	if err != nil {
 		return err
@@ -1,7 +1,6 @@
 package age
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -42,7 +41,7 @@ func (a *Age) Identities(ctx context.Context) ([]age.Identity, error) {
 		return nil, nil
 	}
 
-	ids, err := age.ParseIdentities(bytes.NewReader(buf))
+	ids, err := parseIdentities(buf)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +70,10 @@ func (a *Age) IdentityRecipients(ctx context.Context) ([]age.Recipient, error) {
 
 	var r []age.Recipient
 	for _, id := range ids {
-		if x, ok := id.(*age.X25519Identity); ok {
+		switch x := id.(type) {
+		case *age.X25519Identity:
+			r = append(r, x.Recipient())
+		case *PQIdentity:
 			r = append(r, x.Recipient())
 		}
 	}
@@ -156,7 +158,7 @@ func (a *Age) cachedIDRecpipients() []age.Recipient {
 
 	rs := make([]age.Recipient, 0, len(recps))
 	for _, recp := range recps {
-		r, err := age.ParseX25519Recipient(recp)
+		r, err := parseNativeRecipient(recp)
 		if err != nil {
 			debug.Log("failed to parse recipient %s: %s", recp, err)
 
@@ -168,9 +170,24 @@ func (a *Age) cachedIDRecpipients() []age.Recipient {
 	return rs
 }
 
+// GeneratePQIdentity creates a new hybrid post-quantum identity.
+func (a *Age) GeneratePQIdentity(ctx context.Context) error {
+	_, err := a.addIdentityFn(ctx, func() (age.Identity, error) {
+		return GeneratePQIdentity()
+	})
+
+	return err
+}
+
 func (a *Age) addIdentity(ctx context.Context) ([]age.Identity, error) {
+	return a.addIdentityFn(ctx, func() (age.Identity, error) {
+		return age.GenerateX25519Identity()
+	})
+}
+
+func (a *Age) addIdentityFn(ctx context.Context, genFn func() (age.Identity, error)) ([]age.Identity, error) {
 	ids, _ := a.Identities(ctx)
-	id, err := age.GenerateX25519Identity()
+	id, err := genFn()
 	if err != nil {
 		return nil, err
 	}
@@ -251,6 +268,19 @@ func (a *Age) getAllIdentities(ctx context.Context) (map[string]age.Identity, er
 	}
 	debug.Log("got %d merged identities", len(native))
 
+	if useSSHAgent(ctx) {
+		debug.Log("checking ssh-agent identities")
+		ag, err := a.getSSHAgentIdentities(ctx)
+		if err != nil {
+			debug.Log("unable to load ssh-agent identities: %s", err)
+		}
+
+		// merge
+		for k, v := range ag {
+			native[k] = v
+		}
+	}
+
 	ps, err := a.getPassageIdentities(ctx)
 	if err != nil {
 		debug.Log("unable to load passage identities: %s", err)
@@ -299,7 +329,12 @@ func (a *Age) getNativeIdentities(ctx context.Context) (map[string]age.Identity,
 func idMap(ids []age.Identity) map[string]age.Identity {
 	m := make(map[string]age.Identity)
 	for _, id := range ids {
-		if x, ok := id.(*age.X25519Identity); ok {
+		switch x := id.(type) {
+		case *age.X25519Identity:
+			m[x.Recipient().String()] = id
+
+			continue
+		case *PQIdentity:
 			m[x.Recipient().String()] = id
 
 			continue
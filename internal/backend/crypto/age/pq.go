@@ -0,0 +1,244 @@
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Post-quantum recipients combine ML-KEM-768 with X25519 (X-Wing) through
+// HPKE, so a secret stays confidential as long as either of them is unbroken.
+// They follow the hybrid recipient format of age v1.3, i.e. recipients look
+// like age1pq1... and identities like AGE-SECRET-KEY-PQ-1...
+
+const (
+	pqRecipientHRP = "age1pq"
+	pqIdentityHRP  = "age-secret-key-pq-"
+	pqStanzaType   = "mlkem768x25519"
+	pqLabel        = "age-encryption.org/" + pqStanzaType
+	fileKeySize    = 16
+)
+
+// ErrPQUnsupported is returned if gopass was built without support for
+// post-quantum recipients. The module still targets Go 1.20, so the
+// crypto/hpke based implementation in pq_hpke.go is guarded by the go1.26
+// build tag and pq_nohpke.go provides the fallback for older toolchains.
+var ErrPQUnsupported = errors.New("post-quantum recipients require gopass to be built with Go 1.26 or newer")
+
+var b64 = base64.RawStdEncoding.Strict()
+
+// PQRecipient is a hybrid ML-KEM-768 + X25519 recipient.
+type PQRecipient struct {
+	pk []byte
+}
+
+// PQIdentity is a hybrid ML-KEM-768 + X25519 identity.
+type PQIdentity struct {
+	seed []byte
+	pk   []byte
+}
+
+// IsPQRecipient returns true if the recipient is a post-quantum recipient.
+func IsPQRecipient(r string) bool {
+	return strings.HasPrefix(r, pqRecipientHRP+"1")
+}
+
+// GeneratePQIdentity creates a new random post-quantum identity.
+func GeneratePQIdentity() (*PQIdentity, error) {
+	seed, pk, err := pqGenerate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PQIdentity{seed: seed, pk: pk}, nil
+}
+
+// ParsePQIdentity parses an AGE-SECRET-KEY-PQ-1... identity.
+func ParsePQIdentity(s string) (*PQIdentity, error) {
+	hrp, seed, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed post-quantum identity: %w", err)
+	}
+	if hrp != pqIdentityHRP {
+		return nil, fmt.Errorf("malformed post-quantum identity: unknown type %q", hrp)
+	}
+
+	pk, err := pqPublicKey(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post-quantum identity: %w", err)
+	}
+
+	return &PQIdentity{seed: seed, pk: pk}, nil
+}
+
+// ParsePQRecipient parses an age1pq1... recipient.
+func ParsePQRecipient(s string) (*PQRecipient, error) {
+	hrp, pk, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed post-quantum recipient: %w", err)
+	}
+	if hrp != pqRecipientHRP {
+		return nil, fmt.Errorf("malformed post-quantum recipient: unknown type %q", hrp)
+	}
+
+	if err := pqCheckPublicKey(pk); err != nil {
+		return nil, fmt.Errorf("invalid post-quantum recipient: %w", err)
+	}
+
+	return &PQRecipient{pk: pk}, nil
+}
+
+// Wrap implements age.Recipient.
+func (r *PQRecipient) Wrap(fileKey []byte) ([]*age.Stanza, error) {
+	enc, ct, err := pqSeal(r.pk, fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*age.Stanza{{
+		Type: pqStanzaType,
+		Args: []string{b64.EncodeToString(enc)},
+		Body: ct,
+	}}, nil
+}
+
+// String returns the bech32 encoded recipient.
+func (r *PQRecipient) String() string {
+	return bech32Encode(pqRecipientHRP, r.pk)
+}
+
+// Unwrap implements age.Identity.
+func (i *PQIdentity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	for _, s := range stanzas {
+		if s.Type != pqStanzaType || len(s.Args) != 1 {
+			continue
+		}
+
+		enc, err := b64.DecodeString(s.Args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s stanza: %w", pqStanzaType, err)
+		}
+
+		fileKey, err := pqOpen(i.seed, enc, s.Body)
+		if err != nil {
+			// encrypted for a different identity.
+			continue
+		}
+
+		if len(fileKey) != fileKeySize {
+			return nil, fmt.Errorf("invalid %s stanza: wrong file key size", pqStanzaType)
+		}
+
+		return fileKey, nil
+	}
+
+	return nil, age.ErrIncorrectIdentity
+}
+
+// Recipient returns the recipient matching this identity.
+func (i *PQIdentity) Recipient() *PQRecipient {
+	return &PQRecipient{pk: i.pk}
+}
+
+// String returns the bech32 encoded identity.
+func (i *PQIdentity) String() string {
+	return strings.ToUpper(bech32Encode(pqIdentityHRP, i.seed))
+}
+
+// parseIdentities is like age.ParseIdentities but also accepts post-quantum
+// identities.
+func parseIdentities(buf []byte) ([]age.Identity, error) {
+	var (
+		ids     []age.Identity
+		classic bytes.Buffer
+	)
+
+	s := bufio.NewScanner(bytes.NewReader(buf))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(line, strings.ToUpper(pqIdentityHRP)+"1") {
+			classic.WriteString(line)
+			classic.WriteByte('\n')
+
+			continue
+		}
+
+		id, err := ParsePQIdentity(line)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if strings.TrimSpace(classic.String()) == "" {
+		if len(ids) < 1 {
+			return nil, fmt.Errorf("no secret keys found")
+		}
+
+		return ids, nil
+	}
+
+	cids, err := age.ParseIdentities(&classic)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cids, ids...), nil
+}
+
+// parseNativeRecipient parses X25519 and post-quantum recipients.
+func parseNativeRecipient(s string) (age.Recipient, error) {
+	if IsPQRecipient(s) {
+		return ParsePQRecipient(s)
+	}
+
+	return age.ParseX25519Recipient(s)
+}
+
+// StanzaTypes returns the types of the recipient stanzas in the header of
+// an age file, e.g. X25519 or mlkem768x25519.
+func StanzaTypes(ciphertext []byte) ([]string, error) {
+	s := bufio.NewScanner(bytes.NewReader(ciphertext))
+	if !s.Scan() || s.Text() != "age-encryption.org/v1" {
+		return nil, fmt.Errorf("not an age file")
+	}
+
+	var types []string
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "---") {
+			return types, nil
+		}
+		if !strings.HasPrefix(line, "-> ") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 1 {
+			types = append(types, fields[1])
+		}
+	}
+
+	return nil, fmt.Errorf("truncated age header")
+}
+
+// PostQuantum returns true if the secret can only be decrypted with
+// post-quantum identities. Any classic stanza would allow an attacker with a
+// quantum computer to recover the file key.
+func (a *Age) PostQuantum(ciphertext []byte) (bool, error) {
+	types, err := StanzaTypes(ciphertext)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range types {
+		if t != pqStanzaType {
+			return false, nil
+		}
+	}
+
+	return len(types) > 0, nil
+}
@@ -0,0 +1,71 @@
+//go:build go1.26
+// +build go1.26
+
+package age
+
+import (
+	"crypto/hpke"
+	"fmt"
+)
+
+func pqGenerate() ([]byte, []byte, error) {
+	sk, err := hpke.MLKEM768X25519().GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seed, err := sk.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return seed, sk.PublicKey().Bytes(), nil
+}
+
+func pqPublicKey(seed []byte) ([]byte, error) {
+	sk, err := hpke.MLKEM768X25519().NewPrivateKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return sk.PublicKey().Bytes(), nil
+}
+
+func pqCheckPublicKey(pk []byte) error {
+	_, err := hpke.MLKEM768X25519().NewPublicKey(pk)
+
+	return err
+}
+
+func pqSeal(pk, fileKey []byte) ([]byte, []byte, error) {
+	pub, err := hpke.MLKEM768X25519().NewPublicKey(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enc, s, err := hpke.NewSender(pub, hpke.HKDFSHA256(), hpke.ChaCha20Poly1305(), []byte(pqLabel))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encapsulate: %w", err)
+	}
+
+	ct, err := s.Seal(nil, fileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return enc, ct, nil
+}
+
+func pqOpen(seed, enc, ct []byte) ([]byte, error) {
+	sk, err := hpke.MLKEM768X25519().NewPrivateKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := hpke.NewRecipient(enc, sk, hpke.HKDFSHA256(), hpke.ChaCha20Poly1305(), []byte(pqLabel))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Open(nil, ct)
+}
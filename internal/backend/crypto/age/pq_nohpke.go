@@ -0,0 +1,24 @@
+//go:build !go1.26
+// +build !go1.26
+
+package age
+
+func pqGenerate() ([]byte, []byte, error) {
+	return nil, nil, ErrPQUnsupported
+}
+
+func pqPublicKey([]byte) ([]byte, error) {
+	return nil, ErrPQUnsupported
+}
+
+func pqCheckPublicKey([]byte) error {
+	return ErrPQUnsupported
+}
+
+func pqSeal([]byte, []byte) ([]byte, []byte, error) {
+	return nil, nil, ErrPQUnsupported
+}
+
+func pqOpen([]byte, []byte, []byte) ([]byte, error) {
+	return nil, ErrPQUnsupported
+}
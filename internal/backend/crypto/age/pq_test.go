@@ -0,0 +1,120 @@
+//go:build go1.26
+// +build go1.26
+
+package age
+
+import (
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPQIdentity(t *testing.T) {
+	t.Parallel()
+
+	id, err := GeneratePQIdentity()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(id.String(), "AGE-SECRET-KEY-PQ-1"))
+	assert.True(t, IsPQRecipient(id.Recipient().String()))
+
+	id2, err := ParsePQIdentity(id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id.Recipient().String(), id2.Recipient().String())
+
+	r, err := ParsePQRecipient(id.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, id.Recipient().String(), r.String())
+
+	_, err = ParsePQRecipient(id.Recipient().String() + "q")
+	assert.Error(t, err)
+}
+
+func TestPQEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	a := &Age{}
+
+	pq, err := GeneratePQIdentity()
+	require.NoError(t, err)
+	pq2, err := GeneratePQIdentity()
+	require.NoError(t, err)
+	classic, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	// only post-quantum recipients.
+	ct, err := a.encrypt([]byte("secret"), pq.Recipient(), pq2.Recipient())
+	require.NoError(t, err)
+
+	ok, err := a.PostQuantum(ct)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	for _, id := range []age.Identity{pq, pq2} {
+		buf, err := a.decrypt(ct, id)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(buf))
+	}
+
+	_, err = a.decrypt(ct, classic)
+	assert.Error(t, err)
+
+	// mixed recipients.
+	ct, err = a.encrypt([]byte("secret"), pq.Recipient(), classic.Recipient())
+	require.NoError(t, err)
+
+	ok, err = a.PostQuantum(ct)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	for _, id := range []age.Identity{pq, classic} {
+		buf, err := a.decrypt(ct, id)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(buf))
+	}
+
+	_, err = a.decrypt(ct, pq2)
+	assert.Error(t, err)
+}
+
+func TestParseIdentities(t *testing.T) {
+	t.Parallel()
+
+	pq, err := GeneratePQIdentity()
+	require.NoError(t, err)
+	classic, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	ids, err := parseIdentities([]byte("# comment\n" + classic.String() + "\n" + pq.String() + "\n"))
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.Equal(t, classic.Recipient().String(), ids[0].(*age.X25519Identity).Recipient().String())
+	assert.Equal(t, pq.Recipient().String(), ids[1].(*PQIdentity).Recipient().String())
+
+	ids, err = parseIdentities([]byte(pq.String()))
+	require.NoError(t, err)
+	assert.Len(t, ids, 1)
+
+	_, err = parseIdentities([]byte("# only a comment\n"))
+	assert.Error(t, err)
+}
+
+func TestBech32Roundtrip(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range [][]byte{{0}, []byte("foobar"), make([]byte, 1216)} {
+		hrp, out, err := bech32Decode(bech32Encode("age1pq", in))
+		require.NoError(t, err)
+		assert.Equal(t, "age1pq", hrp)
+		assert.Equal(t, in, out)
+	}
+
+	classic, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	hrp, _, err := bech32Decode(classic.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, "age", hrp)
+}
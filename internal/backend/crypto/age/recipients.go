@@ -2,6 +2,7 @@ package age
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"filippo.io/age"
@@ -47,6 +48,21 @@ func (a *Age) FindRecipients(ctx context.Context, search ...string) ([]string, e
 func (a *Age) parseRecipients(ctx context.Context, recipients []string) ([]age.Recipient, error) {
 	out := make([]age.Recipient, 0, len(recipients))
 	for _, r := range recipients {
+		if IsPQRecipient(r) {
+			id, err := ParsePQRecipient(r)
+			if errors.Is(err, ErrPQUnsupported) {
+				// don't silently drop recipients that others rely on.
+				return out, err
+			}
+			if err != nil {
+				debug.Log("Failed to parse recipient %q as post-quantum: %s", r, err)
+
+				continue
+			}
+			out = append(out, id)
+
+			continue
+		}
 		if strings.HasPrefix(r, "age1") {
 			id, err := age.ParseX25519Recipient(r)
 			if err != nil {
@@ -0,0 +1,171 @@
+package age
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+
+	"filippo.io/age"
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// age has no signatures, so X25519 identities sign with XEdDSA, see
+// https://signal.org/docs/specifications/xeddsa/. The signatures can be
+// verified with the age recipient (the X25519 public key) alone and are
+// regular Ed25519 signatures for the converted key.
+
+const (
+	x25519RecipientHRP = "age"
+	x25519IdentityHRP  = "age-secret-key-"
+	xeddsaSigSize      = 64
+)
+
+// Sign creates a XEdDSA signature with the X25519 identity of the recipient
+// id. Other identities, e.g. SSH or post-quantum keys, can not sign.
+func (a *Age) Sign(ctx context.Context, id string, data []byte) ([]byte, error) {
+	ids, err := a.getAllIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ident, found := ids[id]
+	if !found {
+		return nil, fmt.Errorf("no identity for %s", id)
+	}
+
+	x, ok := ident.(*age.X25519Identity)
+	if !ok {
+		return nil, fmt.Errorf("identity %s is a %T: %w", id, ident, backend.ErrNotSupported)
+	}
+
+	hrp, secret, err := bech32Decode(x.String())
+	if err != nil || hrp != x25519IdentityHRP || len(secret) != 32 {
+		return nil, fmt.Errorf("malformed identity for %s", id)
+	}
+
+	return xeddsaSign(secret, data)
+}
+
+// Verify returns the recipient whose X25519 key made the signature.
+func (a *Age) Verify(ctx context.Context, data, sig []byte, recipients []string) (string, error) {
+	for _, r := range recipients {
+		hrp, u, err := bech32Decode(r)
+		if err != nil || hrp != x25519RecipientHRP || len(u) != 32 {
+			debug.Log("can not verify signatures of %s", r)
+
+			continue
+		}
+
+		if xeddsaVerify(u, data, sig) {
+			return r, nil
+		}
+	}
+
+	return "", backend.ErrBadSignature
+}
+
+func xeddsaSign(secret, msg []byte) ([]byte, error) {
+	k, err := edwards25519.NewScalar().SetBytesWithClamping(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// the public key has a sign bit of zero, so the private key is
+	// negated if necessary.
+	pub := new(edwards25519.Point).ScalarBaseMult(k).Bytes()
+	if pub[31]&0x80 != 0 {
+		k.Negate(k)
+		pub[31] &= 0x7f
+	}
+
+	z := make([]byte, 64)
+	if _, err := rand.Read(z); err != nil {
+		return nil, err
+	}
+
+	// hash_1, i.e. the 32 byte prefix 2^256 - 2.
+	h1 := sha512.New()
+	prefix := bytes.Repeat([]byte{0xff}, 32)
+	prefix[0] = 0xfe
+	_, _ = h1.Write(prefix)
+	_, _ = h1.Write(k.Bytes())
+	_, _ = h1.Write(msg)
+	_, _ = h1.Write(z)
+
+	r, err := edwards25519.NewScalar().SetUniformBytes(h1.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	rp := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h, err := xeddsaChallenge(rp, pub, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := edwards25519.NewScalar().MultiplyAdd(h, k, r)
+
+	return append(rp, s.Bytes()...), nil
+}
+
+func xeddsaVerify(u, msg, sig []byte) bool {
+	if len(u) != 32 || len(sig) != xeddsaSigSize {
+		return false
+	}
+
+	pub, err := montgomeryToEdwards(u)
+	if err != nil {
+		return false
+	}
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+
+	h, err := xeddsaChallenge(sig[:32], pub.Bytes(), msg)
+	if err != nil {
+		return false
+	}
+
+	// R = sB - hA
+	r := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(edwards25519.NewScalar().Negate(h), pub, s)
+
+	return bytes.Equal(r.Bytes(), sig[:32])
+}
+
+// xeddsaChallenge returns SHA-512(R || A || M) mod q.
+func xeddsaChallenge(r, pub, msg []byte) (*edwards25519.Scalar, error) {
+	h := sha512.New()
+	_, _ = h.Write(r)
+	_, _ = h.Write(pub)
+	_, _ = h.Write(msg)
+
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+// montgomeryToEdwards converts the X25519 public key u to the Edwards point
+// with y = (u - 1) / (u + 1) and a sign bit of zero.
+func montgomeryToEdwards(u []byte) (*edwards25519.Point, error) {
+	fu, err := new(field.Element).SetBytes(u)
+	if err != nil {
+		return nil, err
+	}
+
+	// reject non-canonical encodings.
+	if !bytes.Equal(fu.Bytes(), u) {
+		return nil, fmt.Errorf("non-canonical public key")
+	}
+
+	one := new(field.Element).One()
+	y := new(field.Element).Subtract(fu, one)
+	y.Multiply(y, new(field.Element).Invert(new(field.Element).Add(fu, one)))
+
+	return new(edwards25519.Point).SetBytes(y.Bytes())
+}
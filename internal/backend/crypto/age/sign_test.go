@@ -0,0 +1,74 @@
+package age
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXEdDSA(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("approve 0123456789abcdef")
+
+	for i := 0; i < 16; i++ {
+		id, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+
+		_, secret, err := bech32Decode(id.String())
+		require.NoError(t, err)
+		_, u, err := bech32Decode(id.Recipient().String())
+		require.NoError(t, err)
+
+		sig, err := xeddsaSign(secret, msg)
+		require.NoError(t, err)
+		assert.True(t, xeddsaVerify(u, msg, sig))
+
+		// it's a regular Ed25519 signature for the converted key.
+		pub, err := montgomeryToEdwards(u)
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(pub.Bytes(), msg, sig))
+
+		assert.False(t, xeddsaVerify(u, []byte("approve 0123456789abcdee"), sig))
+
+		other, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		_, ou, err := bech32Decode(other.Recipient().String())
+		require.NoError(t, err)
+		assert.False(t, xeddsaVerify(ou, msg, sig))
+
+		sig[40] ^= 1
+		assert.False(t, xeddsaVerify(u, msg, sig))
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	alice, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	a := &Age{}
+	ra, rb := alice.Recipient().String(), bob.Recipient().String()
+
+	_, secret, err := bech32Decode(alice.String())
+	require.NoError(t, err)
+	sig, err := xeddsaSign(secret, []byte("foo"))
+	require.NoError(t, err)
+
+	signer, err := a.Verify(ctx, []byte("foo"), sig, []string{rb, "age1pq1invalid", ra})
+	require.NoError(t, err)
+	assert.Equal(t, ra, signer)
+
+	_, err = a.Verify(ctx, []byte("foo"), sig, []string{rb})
+	assert.ErrorIs(t, err, backend.ErrBadSignature)
+}
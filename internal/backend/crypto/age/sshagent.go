@@ -0,0 +1,138 @@
+package age
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// The SSH agent protocol does not allow any key exchange, only signatures.
+// So we can not use the agessh identities (which need the private key) with
+// keys that only live in the agent. Instead we ask the agent to sign a fixed,
+// per-key challenge and derive a native X25519 identity from the signature.
+// This only works for key types with deterministic signatures (Ed25519 and
+// RSA PKCS#1 v1.5). ECDSA and FIDO (sk-*) signatures contain a random nonce or
+// a counter and would yield a new identity every time.
+const sshAgentChallenge = "gopass age ssh-agent identity v1\n"
+
+var (
+	// sshAgentMu guards sshAgentCache.
+	sshAgentMu    sync.Mutex
+	sshAgentCache map[string]age.Identity
+	// ErrNoSSHAgent signals that no SSH agent is running.
+	ErrNoSSHAgent = errors.New("no ssh-agent")
+)
+
+// deterministicSSHKeyTypes are the key types that produce the same signature
+// for the same input every time.
+var deterministicSSHKeyTypes = map[string]bool{
+	ssh.KeyAlgoED25519: true,
+	ssh.KeyAlgoRSA:     true,
+}
+
+// useSSHAgent returns true if identities derived from keys in the ssh-agent
+// should be used.
+func useSSHAgent(ctx context.Context) bool {
+	return config.Bool(ctx, "age.ssh-agent")
+}
+
+// getSSHAgentIdentities returns all identities that can be derived from the
+// keys held by the running SSH agent.
+func (a *Age) getSSHAgentIdentities(ctx context.Context) (map[string]age.Identity, error) {
+	sshAgentMu.Lock()
+	defer sshAgentMu.Unlock()
+
+	if sshAgentCache != nil {
+		return sshAgentCache, nil
+	}
+
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK not set: %w", ErrNoSSHAgent)
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent at %s: %w", sock, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ids, err := sshAgentIdentities(agent.NewClient(conn))
+	if err != nil {
+		return nil, err
+	}
+	sshAgentCache = ids
+
+	return ids, nil
+}
+
+// sshAgentIdentities derives one X25519 identity for each suitable key in the
+// given agent. The map is keyed by the recipient of the derived identity.
+func sshAgentIdentities(ag agent.Agent) (map[string]age.Identity, error) {
+	keys, err := ag.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+
+	ids := make(map[string]age.Identity, len(keys))
+	for _, key := range keys {
+		if !deterministicSSHKeyTypes[key.Type()] {
+			debug.Log("skipping ssh-agent key %s (%s): no deterministic signatures", key.Comment, key.Type())
+
+			continue
+		}
+
+		id, err := deriveSSHAgentIdentity(ag, key)
+		if err != nil {
+			debug.Log("failed to derive identity from ssh-agent key %s: %s", key.Comment, err)
+
+			continue
+		}
+
+		ids[id.Recipient().String()] = id
+		debug.Log("derived identity %s from ssh-agent key %s", id.Recipient(), key.Comment)
+	}
+
+	return ids, nil
+}
+
+func deriveSSHAgentIdentity(ag agent.Agent, key ssh.PublicKey) (*age.X25519Identity, error) {
+	sig, err := ag.Sign(key, []byte(sshAgentChallenge+ssh.FingerprintSHA256(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign challenge: %w", err)
+	}
+
+	seed := sha256.Sum256(append([]byte(sshAgentChallenge), sig.Blob...))
+
+	return age.ParseX25519Identity(strings.ToUpper(bech32Encode("age-secret-key-", seed[:])))
+}
+
+// SSHAgentRecipients returns the recipients of all identities derived from
+// keys in the SSH agent. These need to be added to a store to make use of
+// the agent.
+func (a *Age) SSHAgentRecipients(ctx context.Context) ([]string, error) {
+	ids, err := a.getSSHAgentIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recps := make([]string, 0, len(ids))
+	for k := range ids {
+		recps = append(recps, k)
+	}
+	sort.Strings(recps)
+
+	return recps, nil
+}
@@ -0,0 +1,58 @@
+package age
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSSHAgentIdentities(t *testing.T) {
+	t.Parallel()
+
+	ag := agent.NewKeyring()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ag.Add(agent.AddedKey{PrivateKey: edKey, Comment: "ed25519"}))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, ag.Add(agent.AddedKey{PrivateKey: ecKey, Comment: "ecdsa"}))
+
+	ids, err := sshAgentIdentities(ag)
+	require.NoError(t, err)
+	// ECDSA signatures are not deterministic and must be skipped.
+	require.Len(t, ids, 1)
+
+	// deriving again must yield the same identity.
+	ids2, err := sshAgentIdentities(ag)
+	require.NoError(t, err)
+	assert.Equal(t, ids, ids2)
+
+	a := &Age{}
+	for recp, id := range ids {
+		r, err := age.ParseX25519Recipient(recp)
+		require.NoError(t, err)
+
+		ciphertext, err := a.encrypt([]byte("foobar"), r)
+		require.NoError(t, err)
+
+		plaintext, err := a.decrypt(ciphertext, id)
+		require.NoError(t, err)
+		assert.Equal(t, "foobar", string(plaintext))
+	}
+}
+
+func TestBech32Encode(t *testing.T) {
+	t.Parallel()
+
+	// test vector from BIP 173.
+	assert.Equal(t, "a12uel5l", bech32Encode("a", nil))
+}
@@ -0,0 +1,328 @@
+package age
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// The TPM support seals a random key encryption key (KEK) to the TPM of the
+// local machine, bound to a PCR policy, and re-encrypts the age keyring to it.
+// The KEK is an age X25519 identity. Once enrolled the keyring can only be
+// decrypted with the KEK unsealed on this machine and only as long as the
+// selected PCRs (e.g. firmware and secure boot state) are unchanged. The
+// keyring passphrase no longer unlocks it. We use the tpm2-tools binaries to
+// talk to the TPM.
+
+const (
+	// DefaultTPMPCRs is the default PCR selection used when sealing.
+	DefaultTPMPCRs = "sha256:0,7"
+	tpmDevice      = "/dev/tpmrm0"
+	tpmPubFile     = "seal.pub"
+	tpmPrivFile    = "seal.priv"
+)
+
+var (
+	// ErrNotEnrolled is returned if no key has been sealed to the TPM.
+	ErrNotEnrolled = errors.New("not enrolled")
+
+	rePCRs = regexp.MustCompile(`^(sha1|sha256|sha384|sha512):([0-9]|1[0-9]|2[0-3])(,([0-9]|1[0-9]|2[0-3]))*$`)
+
+	tpmTools = []string{"tpm2_createprimary", "tpm2_pcrread", "tpm2_createpolicy", "tpm2_create", "tpm2_load", "tpm2_unseal"}
+)
+
+// TPMPCRs returns the configured PCR selection.
+func TPMPCRs(ctx context.Context) string {
+	if pcrs := config.String(ctx, "age.tpm-pcrs"); pcrs != "" {
+		return pcrs
+	}
+
+	return DefaultTPMPCRs
+}
+
+// TPMState describes the state of the TPM integration.
+type TPMState struct {
+	Device bool
+	// Missing lists the required tpm2-tools binaries not found in PATH.
+	Missing  []string
+	Enrolled bool
+	PCRs     string
+}
+
+// tpmDir returns the directory holding the sealed object.
+func (a *Age) tpmDir() string {
+	return filepath.Join(filepath.Dir(a.identity), "tpm")
+}
+
+func (a *Age) tpmEnrolled() bool {
+	return fsutil.IsFile(filepath.Join(a.tpmDir(), tpmPubFile)) && fsutil.IsFile(filepath.Join(a.tpmDir(), tpmPrivFile))
+}
+
+// TPMStatus returns the state of the TPM integration.
+func (a *Age) TPMStatus(ctx context.Context) TPMState {
+	st := TPMState{
+		Device:   tpmDeviceExists(),
+		Enrolled: a.tpmEnrolled(),
+		PCRs:     a.tpmPCRs(ctx),
+	}
+
+	for _, t := range tpmTools {
+		if _, err := exec.LookPath(t); err != nil {
+			st.Missing = append(st.Missing, t)
+		}
+	}
+
+	return st
+}
+
+// tpmPCRs returns the PCR selection the KEK is sealed to or, if not enrolled,
+// the configured selection.
+func (a *Age) tpmPCRs(ctx context.Context) string {
+	if buf, err := os.ReadFile(filepath.Join(a.tpmDir(), "pcrs")); err == nil {
+		return strings.TrimSpace(string(buf))
+	}
+
+	return TPMPCRs(ctx)
+}
+
+// TPMEnroll generates a new KEK, seals it to the TPM using the given PCR
+// selection and re-encrypts the keyring to it. The keyring is unlocked with
+// the passphrase or, if already enrolled, with the currently sealed KEK.
+func (a *Age) TPMEnroll(ctx context.Context, pcrs string) error {
+	if !rePCRs.MatchString(pcrs) {
+		return fmt.Errorf("invalid PCR selection %q, e.g. %q", pcrs, DefaultTPMPCRs)
+	}
+
+	if !fsutil.IsFile(a.identity) {
+		return fmt.Errorf("no age keyring found at %s", a.identity)
+	}
+
+	for _, t := range tpmTools {
+		if _, err := exec.LookPath(t); err != nil {
+			return fmt.Errorf("%s not found, please install tpm2-tools: %w", t, err)
+		}
+	}
+
+	plaintext, err := a.tpmKeyring(ctx)
+	if err != nil {
+		return err
+	}
+
+	kek, err := age.GenerateX25519Identity()
+	if err != nil {
+		return fmt.Errorf("failed to generate key encryption key: %w", err)
+	}
+
+	ciphertext, err := a.encrypt(plaintext, kek.Recipient())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(a.tpmDir(), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", a.tpmDir(), err)
+	}
+
+	tmp, err := os.MkdirTemp("", "gopass-tpm-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	primary := filepath.Join(tmp, "primary.ctx")
+	policy := filepath.Join(tmp, "policy.digest")
+	pcrFile := filepath.Join(tmp, "pcr.bin")
+	pub := filepath.Join(tmp, tpmPubFile)
+	priv := filepath.Join(tmp, tpmPrivFile)
+
+	steps := [][]string{
+		{"tpm2_createprimary", "-Q", "-C", "o", "-c", primary},
+		{"tpm2_pcrread", "-Q", "-o", pcrFile, pcrs},
+		{"tpm2_createpolicy", "-Q", "--policy-pcr", "-l", pcrs, "-f", pcrFile, "-L", policy},
+	}
+	for _, args := range steps {
+		if _, err := runTPMTool(ctx, nil, args...); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runTPMTool(ctx, []byte(kek.String()), "tpm2_create", "-Q", "-C", primary, "-L", policy, "-i", "-",
+		"-u", pub, "-r", priv); err != nil {
+		return err
+	}
+
+	// only replace the sealed KEK and the keyring once sealing succeeded.
+	for src, dst := range map[string]string{
+		pub:  filepath.Join(a.tpmDir(), tpmPubFile),
+		priv: filepath.Join(a.tpmDir(), tpmPrivFile),
+	} {
+		buf, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, buf, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", dst, err)
+		}
+	}
+
+	// remember the PCRs the KEK was sealed to.
+	if err := os.WriteFile(filepath.Join(a.tpmDir(), "pcrs"), []byte(pcrs), 0o600); err != nil {
+		return fmt.Errorf("failed to write PCR selection: %w", err)
+	}
+
+	if err := os.WriteFile(a.identity, ciphertext, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", a.identity, err)
+	}
+
+	debug.Log("sealed key encryption key for %s to TPM (PCRs %s)", a.identity, pcrs)
+
+	return nil
+}
+
+// TPMUnenroll re-encrypts the keyring with a new passphrase and removes the
+// sealed KEK. Run it before firmware or boot configuration updates.
+func (a *Age) TPMUnenroll(ctx context.Context) error {
+	if !a.tpmEnrolled() {
+		return ErrNotEnrolled
+	}
+
+	plaintext, err := a.tpmKeyring(ctx)
+	if err != nil {
+		return err
+	}
+
+	pw, err := a.askPass.Passphrase(a.identity, fmt.Sprintf("to protect the age keyring at %s", a.identity), true)
+	if err != nil {
+		return err
+	}
+
+	id, err := age.NewScryptRecipient(pw)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := a.encrypt(plaintext, id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(a.identity, ciphertext, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", a.identity, err)
+	}
+
+	debug.Log("removed TPM binding from %s", a.identity)
+
+	return os.RemoveAll(a.tpmDir())
+}
+
+// TPMUnseal unseals the KEK from the TPM.
+func (a *Age) TPMUnseal(ctx context.Context) (*age.X25519Identity, error) {
+	if !a.tpmEnrolled() {
+		return nil, ErrNotEnrolled
+	}
+
+	pcrs := a.tpmPCRs(ctx)
+
+	tmp, err := os.MkdirTemp("", "gopass-tpm-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	primary := filepath.Join(tmp, "primary.ctx")
+	sealed := filepath.Join(tmp, "seal.ctx")
+
+	if _, err := runTPMTool(ctx, nil, "tpm2_createprimary", "-Q", "-C", "o", "-c", primary); err != nil {
+		return nil, err
+	}
+	if _, err := runTPMTool(ctx, nil, "tpm2_load", "-Q", "-C", primary,
+		"-u", filepath.Join(a.tpmDir(), tpmPubFile), "-r", filepath.Join(a.tpmDir(), tpmPrivFile), "-c", sealed); err != nil {
+		return nil, err
+	}
+
+	buf, err := runTPMTool(ctx, nil, "tpm2_unseal", "-c", sealed, "-p", "pcr:"+pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := age.ParseX25519Identity(strings.TrimSpace(string(buf)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+
+	return kek, nil
+}
+
+// tpmKeyring returns the decrypted keyring. If enrolled it is decrypted with
+// the unsealed KEK, otherwise with the keyring passphrase.
+func (a *Age) tpmKeyring(ctx context.Context) ([]byte, error) {
+	if a.tpmEnrolled() {
+		return a.decryptFileWithTPM(ctx, a.identity)
+	}
+
+	pw, err := a.askPass.Passphrase(a.identity, fmt.Sprintf("to seal the age keyring %s to the TPM", a.identity), false)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := a.decryptFileWithPassphrase(a.identity, []byte(pw))
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase for %s: %w", a.identity, err)
+	}
+
+	return plaintext, nil
+}
+
+// decryptFileWithTPM decrypts the keyring with the unsealed KEK.
+func (a *Age) decryptFileWithTPM(ctx context.Context, filename string) ([]byte, error) {
+	ciphertext, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := a.TPMUnseal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal key encryption key for %s from TPM: %w", filename, err)
+	}
+
+	return a.decrypt(ciphertext, kek)
+}
+
+// tpmSealed returns true if filename is the keyring and it is encrypted to the
+// KEK sealed to the TPM.
+func (a *Age) tpmSealed(filename string) bool {
+	return filename == a.identity && a.tpmEnrolled()
+}
+
+func tpmDeviceExists() bool {
+	_, err := os.Stat(tpmDevice)
+
+	return err == nil
+}
+
+func runTPMTool(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	debug.Log("running %s", args)
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,46 @@
+package age
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTPM(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	a := &Age{identity: filepath.Join(td, "age", "identities")}
+
+	ctx := config.NewNoWrites().WithConfig(context.Background())
+	assert.Equal(t, DefaultTPMPCRs, TPMPCRs(ctx))
+
+	st := a.TPMStatus(ctx)
+	assert.False(t, st.Enrolled)
+
+	_, err := a.TPMUnseal(ctx)
+	require.ErrorIs(t, err, ErrNotEnrolled)
+
+	require.Error(t, a.TPMEnroll(ctx, "sha256:0,42"))
+	// no keyring, yet.
+	require.Error(t, a.TPMEnroll(ctx, DefaultTPMPCRs))
+
+	require.ErrorIs(t, a.TPMUnenroll(ctx), ErrNotEnrolled)
+	assert.False(t, a.tpmSealed(a.identity))
+
+	// once enrolled the keyring must only be decrypted with the sealed KEK.
+	require.NoError(t, os.MkdirAll(a.tpmDir(), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(a.tpmDir(), tpmPubFile), []byte("pub"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(a.tpmDir(), tpmPrivFile), []byte("priv"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(a.tpmDir(), "pcrs"), []byte("sha256:0,2,7\n"), 0o600))
+	st = a.TPMStatus(ctx)
+	assert.True(t, st.Enrolled)
+	assert.Equal(t, "sha256:0,2,7", st.PCRs)
+	assert.True(t, a.tpmSealed(a.identity))
+	assert.False(t, a.tpmSealed(filepath.Join(td, "other")))
+}
@@ -1,19 +1,33 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
 
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/internal/fips"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 // Decrypt will try to decrypt the given file.
 func (g *GPG) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
 	defer cancel()
 
+	// Windows can not pass extra file descriptors to child processes.
+	if fips.Enabled(ctx) && runtime.GOOS != "windows" {
+		return g.decryptFIPS(ctx, ciphertext)
+	}
+
 	args := append(g.args, "--decrypt")
 	cmd := exec.CommandContext(ctx, g.binary, args...)
 	cmd.Stdin = bytes.NewReader(ciphertext)
@@ -23,3 +37,91 @@ func (g *GPG) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
 
 	return cmd.Output()
 }
+
+// decryptFIPS decrypts the ciphertext and checks the algorithms gpg reports
+// on the status fd. The plaintext is discarded if any of them is not approved.
+func (g *GPG) decryptFIPS(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	sr, sw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status pipe: %w", err)
+	}
+	defer sr.Close() //nolint:errcheck
+
+	args := append(g.args, "--status-fd", "3", "--decrypt")
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Stdin = bytes.NewReader(ciphertext)
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{sw}
+
+	status := make(chan error, 1)
+	go func() {
+		status <- checkStatus(sr)
+	}()
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+	plaintext, err := cmd.Output()
+	_ = sw.Close()
+	serr := <-status
+
+	if err != nil {
+		return nil, err
+	}
+
+	if serr != nil {
+		return nil, serr
+	}
+
+	return plaintext, nil
+}
+
+// errNoDecryptionInfo is returned if gpg did not report the algorithms used.
+var errNoDecryptionInfo = errors.New("gpg did not report the cipher of the secret")
+
+// checkStatus reads the gpg status lines and returns an error if the secret
+// was encrypted with a cipher or AEAD mode that is not approved or if gpg did
+// not report them at all. See doc/DETAILS in the GnuPG sources for the format.
+func checkStatus(r io.Reader) error {
+	res := errNoDecryptionInfo
+	seen := false
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(s.Text(), "[GNUPG:] "))
+
+		// DECRYPTION_INFO <mdc_method> <sym_algo> [<aead_algo>]
+		if len(fields) < 3 || fields[0] != "DECRYPTION_INFO" || seen {
+			continue
+		}
+		seen = true
+
+		res = checkDecryptionInfo(fields[2:])
+	}
+
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("failed to read gpg status: %w", err)
+	}
+
+	return res
+}
+
+func checkDecryptionInfo(fields []string) error {
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid cipher %q: %w", fields[0], err)
+	}
+	if err := fips.CheckCipher(id); err != nil {
+		return err
+	}
+
+	if len(fields) < 2 {
+		return nil
+	}
+
+	aead, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return fmt.Errorf("invalid AEAD mode %q: %w", fields[1], err)
+	}
+
+	return fips.CheckAEAD(aead)
+}
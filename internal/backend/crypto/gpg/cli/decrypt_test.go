@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/fips"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatus(t *testing.T) {
+	t.Parallel()
+
+	aes := `[GNUPG:] ENC_TO 8A61E5C2D8B44B0F 1 0
+[GNUPG:] DECRYPTION_KEY B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4 B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4 u
+[GNUPG:] BEGIN_DECRYPTION
+[GNUPG:] DECRYPTION_INFO 2 9 0
+[GNUPG:] PLAINTEXT 62 1640995200
+[GNUPG:] DECRYPTION_OKAY
+[GNUPG:] END_DECRYPTION
+`
+	assert.NoError(t, checkStatus(strings.NewReader(aes)))
+
+	cast5 := strings.Replace(aes, "DECRYPTION_INFO 2 9 0", "DECRYPTION_INFO 2 3", 1)
+	assert.ErrorIs(t, checkStatus(strings.NewReader(cast5)), fips.ErrNotApproved)
+
+	ocb := strings.Replace(aes, "DECRYPTION_INFO 2 9 0", "DECRYPTION_INFO 0 9 2", 1)
+	assert.ErrorIs(t, checkStatus(strings.NewReader(ocb)), fips.ErrNotApproved)
+
+	missing := strings.Replace(aes, "[GNUPG:] DECRYPTION_INFO 2 9 0\n", "", 1)
+	assert.ErrorIs(t, checkStatus(strings.NewReader(missing)), errNoDecryptionInfo)
+}
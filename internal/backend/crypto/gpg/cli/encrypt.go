@@ -3,19 +3,31 @@ package cli
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/internal/fips"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
+// fipsArgs force AES256 regardless of the recipients' key preferences and
+// make gpg prefer approved digests.
+var fipsArgs = []string{
+	"--cipher-algo", "AES256",
+	"--personal-cipher-preferences", "AES256 AES192 AES",
+	"--personal-digest-preferences", "SHA512 SHA384 SHA256",
+	"--personal-compress-preferences", "Uncompressed",
+}
+
 // Encrypt will encrypt the given content for the recipients. If alwaysTrust is true
 // the trust-model will be set to always as to avoid (annoying) "unusable public key"
 // errors when encrypting.
 func (g *GPG) Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
 	defer cancel()
 
 	args := append(g.args, "--encrypt")
@@ -25,6 +37,11 @@ func (g *GPG) Encrypt(ctx context.Context, plaintext []byte, recipients []string
 		args = append(args, "--trust-model=always")
 	}
 
+	fipsMode := fips.Enabled(ctx)
+	if fipsMode {
+		args = append(args, fipsArgs...)
+	}
+
 	for _, r := range recipients {
 		kl, err := g.listKeys(ctx, "public", r)
 		if err != nil {
@@ -34,6 +51,13 @@ func (g *GPG) Encrypt(ctx context.Context, plaintext []byte, recipients []string
 
 			continue
 		}
+		if fipsMode {
+			for _, k := range kl {
+				if err := fips.CheckKey(k); err != nil {
+					return nil, fmt.Errorf("can not encrypt for %s: %w", r, err)
+				}
+			}
+		}
 		args = append(args, "--recipient", r)
 	}
 
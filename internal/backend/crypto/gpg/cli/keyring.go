@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"text/template"
@@ -11,13 +12,14 @@ import (
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/colons"
+	"github.com/gopasspw/gopass/internal/deadline"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 // listKey lists all keys of the given type and matching the search strings.
 func (g *GPG) listKeys(ctx context.Context, typ string, search ...string) (gpg.KeyList, error) {
-	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
 	defer cancel()
 
 	args := []string{"--with-colons", "--with-fingerprint", "--fixed-list-mode", "--list-" + typ + "-keys"}
@@ -112,6 +114,27 @@ func (g *GPG) ReadNamesFromKey(ctx context.Context, buf []byte) ([]string, error
 	return names, nil
 }
 
+// ReadFingerprintsFromKey unmarshals and returns the fingerprints of the primary
+// key and all subkeys of the given public key.
+func (g *GPG) ReadFingerprintsFromKey(ctx context.Context, buf []byte) ([]string, error) {
+	el, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key ring: %w", err)
+	}
+
+	if len(el) != 1 {
+		return nil, fmt.Errorf("public Key must contain exactly one Entity")
+	}
+
+	fps := make([]string, 0, len(el[0].Subkeys)+1)
+	fps = append(fps, fmt.Sprintf("%X", el[0].PrimaryKey.Fingerprint))
+	for _, sk := range el[0].Subkeys {
+		fps = append(fps, fmt.Sprintf("%X", sk.PublicKey.Fingerprint))
+	}
+
+	return fps, nil
+}
+
 // ImportPublicKey will import a key from the given location into the keyring.
 func (g *GPG) ImportPublicKey(ctx context.Context, buf []byte) error {
 	if len(buf) < 1 {
@@ -161,3 +184,55 @@ func (g *GPG) ExportPublicKey(ctx context.Context, id string) ([]byte, error) {
 
 	return out, nil
 }
+
+// ExportPrivateKey exports the secret key with the given id in armored form.
+// gpg-agent asks for the passphrase of the key.
+func (g *GPG) ExportPrivateKey(ctx context.Context, id string) ([]byte, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is empty")
+	}
+
+	args := append(g.args, "--armor", "--export-secret-keys", id)
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Stderr = os.Stderr
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command '%s %+v': %w", cmd.Path, cmd.Args, err)
+	}
+
+	if len(out) < 1 {
+		return nil, fmt.Errorf("key not found")
+	}
+
+	return out, nil
+}
+
+// DeletePrivateKey removes the secret key with the given fingerprint from the
+// keyring. The public key is kept, so it can still be used as a recipient.
+func (g *GPG) DeletePrivateKey(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+
+	outBuf := &bytes.Buffer{}
+
+	args := append(g.args, "--batch", "--yes", "--delete-secret-keys", id)
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Stdout = outBuf
+	cmd.Stderr = outBuf
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run command '%s %+v': %w - %q", cmd.Path, cmd.Args, err, outBuf.String())
+	}
+
+	// clear key cache
+	g.privKeys = nil
+	if g.listCache != nil {
+		g.listCache.Purge()
+	}
+
+	return nil
+}
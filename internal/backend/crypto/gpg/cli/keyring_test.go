@@ -80,6 +80,22 @@ func TestReadNamesFromKey(t *testing.T) {
 	assert.Equal(t, []string{"Gopass Archive Signing Key <gopass@justwatch.com>"}, names)
 }
 
+func TestReadFingerprintsFromKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	g, err := New(ctx, Config{})
+	require.NoError(t, err)
+
+	fps, err := g.ReadFingerprintsFromKey(ctx, []byte(pubkey))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"7379880F3D29A3B03F44B73D0C92225A97F6B666", "B907B9C09EA4CDB96CA8E656B32472BA4A6EAC01"}, fps)
+
+	_, err = g.ReadFingerprintsFromKey(ctx, []byte("foobar"))
+	assert.Error(t, err)
+}
+
 func TestExportPublicKey(t *testing.T) {
 	t.Parallel()
 
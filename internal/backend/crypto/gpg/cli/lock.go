@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// Lock makes gpg-agent forget all cached passphrases. The keys stay on disk,
+// the next decryption asks for the passphrase again.
+func (g *GPG) Lock(ctx context.Context) error {
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, g.gpgconf(), "--reload", "gpg-agent")
+	cmd.Stderr = os.Stderr
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to reload gpg-agent: %w", err)
+	}
+
+	return nil
+}
+
+// gpgconf returns the gpgconf binary that belongs to the gpg binary in use.
+func (g *GPG) gpgconf() string {
+	bin := filepath.Join(filepath.Dir(g.binary), "gpgconf"+filepath.Ext(g.binary))
+	if fsutil.IsFile(bin) {
+		return bin
+	}
+
+	return "gpgconf"
+}
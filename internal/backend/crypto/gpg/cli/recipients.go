@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/deadline"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -53,7 +54,7 @@ func (g *GPG) FindRecipients(ctx context.Context, search ...string) ([]string, e
 
 // RecipientIDs returns a list of recipient IDs for a given encrypted blob.
 func (g *GPG) RecipientIDs(ctx context.Context, buf []byte) ([]string, error) {
-	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
 	defer cancel()
 
 	recp := make([]string, 0, 5)
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Sign creates a detached signature of data with the secret key of id.
+func (g *GPG) Sign(ctx context.Context, id string, data []byte) ([]byte, error) {
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
+	defer cancel()
+
+	args := append(g.args, "--local-user", id, "--detach-sign")
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = os.Stderr
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+	return cmd.Output()
+}
+
+// Verify checks the detached signature and returns the recipient whose
+// (primary) key made it.
+func (g *GPG) Verify(ctx context.Context, data, sig []byte, recipients []string) (string, error) {
+	ctx, cancel := deadline.With(ctx, deadline.Crypto)
+	defer cancel()
+
+	// gpg only reads detached signatures from a file.
+	fh, err := os.CreateTemp("", "gopass-sig-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Remove(fh.Name())
+	}()
+
+	if _, err := fh.Write(sig); err != nil {
+		_ = fh.Close()
+
+		return "", err
+	}
+	if err := fh.Close(); err != nil {
+		return "", err
+	}
+
+	args := append(g.args, "--status-fd", "1", "--verify", fh.Name(), "-")
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Stdin = bytes.NewReader(data)
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+	status, err := cmd.Output()
+	if err != nil {
+		debug.Log("failed to verify signature: %s", err)
+
+		return "", backend.ErrBadSignature
+	}
+
+	fpr := validSigner(bytes.NewReader(status))
+	if fpr == "" {
+		return "", backend.ErrBadSignature
+	}
+
+	for _, r := range recipients {
+		kl, err := g.listKeys(ctx, "public", r)
+		if err != nil {
+			continue
+		}
+
+		for _, k := range kl {
+			if strings.EqualFold(k.Fingerprint, fpr) {
+				return r, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("signed by %s: %w", fpr, backend.ErrBadSignature)
+}
+
+// validSigner returns the fingerprint of the primary key that made a good
+// signature or an empty string. Signatures by expired or revoked keys are not
+// GOODSIG. See doc/DETAILS in the GnuPG sources for the format.
+func validSigner(r io.Reader) string {
+	var good bool
+	var fpr string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(s.Text(), "[GNUPG:] "))
+		if len(fields) < 1 {
+			continue
+		}
+
+		switch fields[0] {
+		case "GOODSIG":
+			good = true
+		// VALIDSIG <fpr> <date> <ts> <expire> <ver> <reserved> <pk-algo>
+		// <hash-algo> <class> <primary-key-fpr>
+		case "VALIDSIG":
+			if len(fields) > 10 {
+				fpr = fields[10]
+			}
+		case "BADSIG", "ERRSIG", "EXPKEYSIG", "REVKEYSIG", "EXPSIG":
+			return ""
+		}
+	}
+
+	if !good {
+		return ""
+	}
+
+	return fpr
+}
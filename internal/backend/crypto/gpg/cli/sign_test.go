@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidSigner(t *testing.T) {
+	t.Parallel()
+
+	good := `[GNUPG:] NEWSIG
+[GNUPG:] KEY_CONSIDERED B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4 0
+[GNUPG:] SIG_ID 2jmj7l5rSw0yVb/vlWAYkK/YBwk 2022-01-01 1640995200
+[GNUPG:] GOODSIG 0E2C8A61E5C2D8B4 John Doe <john.doe@example.com>
+[GNUPG:] VALIDSIG 8A61E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4B0F1 2022-01-01 1640995200 0 4 0 1 10 00 B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4
+[GNUPG:] TRUST_ULTIMATE 0 pgp
+`
+	assert.Equal(t, "B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4", validSigner(strings.NewReader(good)))
+
+	expired := strings.Replace(good, "GOODSIG", "EXPKEYSIG", 1)
+	assert.Equal(t, "", validSigner(strings.NewReader(expired)))
+
+	bad := "[GNUPG:] BADSIG 0E2C8A61E5C2D8B4 John Doe <john.doe@example.com>\n"
+	assert.Equal(t, "", validSigner(strings.NewReader(bad)))
+}
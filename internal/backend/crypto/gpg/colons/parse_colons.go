@@ -95,11 +95,13 @@ func Parse(reader io.Reader) gpg.KeyList {
 				Identities:     make(map[string]gpg.Identity, 1),
 				SubKeys:        make(map[string]struct{}, 1),
 				Caps:           parseKeyCaps(fields[11]),
+				Algorithms:     []gpg.Algorithm{parseAlgorithm(fields)},
 			}
 		case "sub":
 			fallthrough
 		case "ssb":
 			cur.SubKeys[fields[4]] = struct{}{}
+			cur.Algorithms = append(cur.Algorithms, parseAlgorithm(fields))
 		case "fpr":
 			if cur.Fingerprint == "" {
 				cur.Fingerprint = fields[9]
@@ -117,6 +119,20 @@ func Parse(reader io.Reader) gpg.KeyList {
 	return kl
 }
 
+func parseAlgorithm(fields []string) gpg.Algorithm {
+	a := gpg.Algorithm{
+		ID:     parseInt(fields[3]),
+		Length: parseInt(fields[2]),
+		// the lower case capabilities are those of this (sub)key only
+		Encrypt: strings.Contains(fields[11], "e") && !strings.ContainsAny(fields[1], "deinr"),
+	}
+	if len(fields) > 16 {
+		a.Curve = fields[16]
+	}
+
+	return a
+}
+
 func parseKeyCaps(field string) gpg.Capabilities {
 	keycaps := gpg.Capabilities{}
 
@@ -4,7 +4,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseColonIdentity(t *testing.T) {
@@ -52,3 +54,21 @@ func TestParseColonIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	in := `pub:u:255:22:4B0F2B2F0E2C8A61:1640995200:::u:::scESC:::::ed25519:::0:
+fpr:::::::::B0F1F6A1E5C2D8B44B0F2B2F0E2C8A61E5C2D8B4:
+uid:u::::1640995200::2D6E2E7E1D4B0F2B2F0E2C8A61A1E5C2D8B4B0F1::John Doe <john.doe@example.com>::::::::::0:
+sub:r:3072:1:8A61E5C2D8B44B0F:1640995200::::::e:::::::::
+sub:u:256:18:D8B44B0F2B2F0E2C:1640995200::::::e:::::cv25519::
+`
+	kl := Parse(strings.NewReader(in))
+	require.Len(t, kl, 1)
+	assert.Equal(t, []gpg.Algorithm{
+		{ID: 22, Length: 255, Curve: "ed25519"},
+		{ID: 1, Length: 3072},
+		{ID: 18, Length: 256, Curve: "cv25519", Encrypt: true},
+	}, kl[0].Algorithms)
+}
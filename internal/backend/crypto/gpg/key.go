@@ -18,6 +18,20 @@ type Key struct {
 	Identities     map[string]Identity
 	SubKeys        map[string]struct{}
 	Caps           Capabilities
+	// Algorithms of the primary key followed by those of the subkeys.
+	Algorithms []Algorithm
+}
+
+// Algorithm is the public key algorithm of a key or subkey.
+type Algorithm struct {
+	// ID is the OpenPGP algorithm id, e.g. 1 for RSA.
+	ID     int
+	Length int
+	// Curve is only set for ECC keys.
+	Curve string
+	// Encrypt is true if this (sub)key itself is valid and can be used
+	// for encryption.
+	Encrypt bool
 }
 
 // Capabilities of a Key.
@@ -3,12 +3,15 @@ package plain
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
+	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -165,3 +168,31 @@ func (m *Mocker) ReadNamesFromKey(ctx context.Context, buf []byte) ([]string, er
 func (m *Mocker) Concurrency() int {
 	return runtime.NumCPU()
 }
+
+// Sign returns an insecure signature that only records the id.
+func (m *Mocker) Sign(_ context.Context, id string, data []byte) ([]byte, error) {
+	return []byte(id + ":" + plainDigest(id, data)), nil
+}
+
+// Verify checks that the signature matches the data and was made by one of
+// the recipients.
+func (m *Mocker) Verify(_ context.Context, data, sig []byte, recipients []string) (string, error) {
+	id, digest, found := strings.Cut(string(sig), ":")
+	if !found || digest != plainDigest(id, data) {
+		return "", backend.ErrBadSignature
+	}
+
+	for _, r := range recipients {
+		if r == id {
+			return r, nil
+		}
+	}
+
+	return "", backend.ErrBadSignature
+}
+
+func plainDigest(id string, data []byte) string {
+	sum := sha256.Sum256(append([]byte(id+"\n"), data...))
+
+	return hex.EncodeToString(sum[:])
+}
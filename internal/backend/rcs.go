@@ -20,6 +20,7 @@ type rcs interface {
 	RemoveRemote(ctx context.Context, remote string) error
 
 	Revisions(ctx context.Context, name string) ([]Revision, error)
+	Head(ctx context.Context) (string, error)
 	GetRevision(ctx context.Context, name, revision string) ([]byte, error)
 
 	Status(ctx context.Context) ([]byte, error)
@@ -36,6 +37,46 @@ type Revision struct {
 	Body        string
 }
 
+// Staged is a push to a protected store that waits for approval.
+type Staged struct {
+	// ID is the abbreviated hash of the staged commit.
+	ID     string
+	Commit string
+	// Branch is the staging branch on the remote.
+	Branch  string
+	Subject string
+	Date    time.Time
+	// Commits is the number of commits not on the main branch, yet.
+	Commits int
+	// StagedBy is the recipient that signed the push. It's empty if the
+	// signature is missing or invalid.
+	StagedBy string
+	// Approvals are the recipients, other than StagedBy, that signed an
+	// approval.
+	Approvals []string
+}
+
+// Notary signs the notes of pushes to protected stores and verifies that they
+// were made by a recipient of the store.
+type Notary interface {
+	// Sign signs msg with the key of a recipient the user has the private
+	// key of. It returns that recipient and the signature.
+	Sign(ctx context.Context, msg []byte) (string, []byte, error)
+	// Verify returns the recipient that made the signature.
+	Verify(ctx context.Context, msg, sig []byte) (string, error)
+}
+
+// TransferEstimate is the estimated size of a sync with a remote.
+type TransferEstimate struct {
+	// Upload is the size of the objects to push in bytes.
+	Upload int64 `json:"upload"`
+	// Objects is the number of objects to push.
+	Objects int `json:"objects"`
+	// Incoming is true if the remote has commits that need to be fetched.
+	// Their size is not known before fetching them.
+	Incoming bool `json:"incoming"`
+}
+
 // Revisions implements the sort interface.
 type Revisions []Revision
 
@@ -21,6 +21,8 @@ const (
 	GitFS
 	// FossilFS is a filesystem-backed storage with Fossil.
 	FossilFS
+	// GoGitFS is a filesystem-backed storage with a pure Go Git implementation.
+	GoGitFS
 )
 
 func (s StorageBackend) String() string {
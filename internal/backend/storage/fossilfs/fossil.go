@@ -420,6 +420,24 @@ func (f *Fossil) Status(ctx context.Context) ([]byte, error) {
 	return stdout, nil
 }
 
+// Head returns the hash of the current check-in.
+func (f *Fossil) Head(ctx context.Context) (string, error) {
+	stdout, _, err := f.captureCmd(ctx, "fossilHead", "info")
+	if err != nil {
+		return "", err
+	}
+
+	// checkout:     <hash> <date>
+	for _, line := range strings.Split(string(stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == "checkout:" {
+			return fields[1], nil
+		}
+	}
+
+	return "", fmt.Errorf("no checkout in fossil info")
+}
+
 // Compact will run fossil rebuild.
 func (f *Fossil) Compact(ctx context.Context) error {
 	return f.Cmd(ctx, "fossilRebuild", "rebuild", "--compress", "--analyze", "--vacuum")
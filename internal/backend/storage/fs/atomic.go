@@ -0,0 +1,107 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Durability levels for storage.durability. Every write goes through a temp
+// file that is renamed over the entry, so a crash never leaves a partially
+// written entry behind. The levels only control how much is flushed to disk
+// before the write returns.
+const (
+	// DurabilityNone leaves flushing to the OS. A crash can lose the last
+	// writes, but never corrupt entries on journaling file systems.
+	DurabilityNone = "none"
+	// DurabilityFile flushes the content of the entry before renaming it.
+	DurabilityFile = "file"
+	// DurabilityFull also flushes the directory, so the rename itself
+	// survives a power loss.
+	DurabilityFull = "full"
+)
+
+func durability(ctx context.Context) string {
+	switch d := config.String(ctx, "storage.durability"); d {
+	case DurabilityNone, DurabilityFile, DurabilityFull:
+		return d
+	case "":
+		return DurabilityFull
+	default:
+		debug.Log("unknown storage.durability %q, using %q", d, DurabilityFull)
+
+		return DurabilityFull
+	}
+}
+
+// writeFile atomically replaces filename with value.
+func writeFile(ctx context.Context, filename string, value []byte) error {
+	dir := filepath.Dir(filename)
+	level := durability(ctx)
+
+	// new entries are only readable by the owner. Existing ones keep their
+	// mode.
+	mode := os.FileMode(0o600)
+	if fi, err := os.Stat(filename); err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	fh, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+
+	tmp := fh.Name()
+	cleanup := func() {
+		_ = fh.Close()
+		_ = os.Remove(tmp)
+	}
+
+	if _, err := fh.Write(value); err != nil {
+		cleanup()
+
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	if level != DurabilityNone {
+		if err := fh.Sync(); err != nil {
+			cleanup()
+
+			return fmt.Errorf("failed to sync %s: %w", tmp, err)
+		}
+	}
+
+	if err := fh.Close(); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to close %s: %w", tmp, err)
+	}
+
+	if err := os.Chmod(tmp, mode); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to set mode of %s: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, filename); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to rename %s to %s: %w", tmp, filename, err)
+	}
+
+	if level != DurabilityFull {
+		return nil
+	}
+
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", dir, err)
+	}
+
+	debug.Log("wrote %s with durability %s", filename, level)
+
+	return nil
+}
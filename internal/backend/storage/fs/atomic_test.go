@@ -0,0 +1,67 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", td)
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	for _, level := range []string{DurabilityNone, DurabilityFile, DurabilityFull, "invalid"} {
+		require.NoError(t, cfg.SetEnv("storage.durability", level))
+
+		fn := filepath.Join(td, level+".gpg")
+		require.NoError(t, writeFile(ctx, fn, []byte("foo")), level)
+		require.NoError(t, writeFile(ctx, fn, []byte("bar")), level)
+
+		buf, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		assert.Equal(t, "bar", string(buf), level)
+	}
+
+	// no temp files are left behind.
+	tmps, err := filepath.Glob(filepath.Join(td, ".*.tmp-*"))
+	require.NoError(t, err)
+	assert.Empty(t, tmps)
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	// existing files keep their mode.
+	fn := filepath.Join(td, "mode.gpg")
+	require.NoError(t, os.WriteFile(fn, []byte("foo"), 0o640))
+	require.NoError(t, os.Chmod(fn, 0o640))
+	require.NoError(t, writeFile(ctx, fn, []byte("bar")))
+
+	fi, err := os.Stat(fn)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), fi.Mode().Perm())
+}
+
+func TestDurability(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	assert.Equal(t, DurabilityFull, durability(context.Background()))
+
+	require.NoError(t, cfg.SetEnv("storage.durability", DurabilityNone))
+	assert.Equal(t, DurabilityNone, durability(ctx))
+
+	require.NoError(t, cfg.SetEnv("storage.durability", "sometimes"))
+	assert.Equal(t, DurabilityFull, durability(ctx))
+}
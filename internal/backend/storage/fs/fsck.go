@@ -38,6 +38,7 @@ func (s *Store) Fsck(ctx context.Context) error {
 		if err := s.fsckCheckDir(ctx, dir); err != nil {
 			return err
 		}
+		s.fsckRemoveTempFiles(ctx, dir)
 	}
 
 	if err := s.fsckCheckEmptyDirs(); err != nil {
@@ -75,6 +76,22 @@ func (s *Store) fsckCheckFile(ctx context.Context, filename string) error {
 	return nil
 }
 
+// fsckRemoveTempFiles removes temp files of writes that were interrupted
+// before the rename. The entry itself is still intact.
+func (s *Store) fsckRemoveTempFiles(ctx context.Context, dirname string) {
+	files, err := filepath.Glob(filepath.Join(dirname, ".*.tmp-*"))
+	if err != nil {
+		return
+	}
+
+	for _, fn := range files {
+		out.Printf(ctx, "Removing left over temp file %s", fn)
+		if err := os.Remove(fn); err != nil {
+			out.Errorf(ctx, "  Failed to remove %s: %s", fn, err)
+		}
+	}
+}
+
 func (s *Store) fsckCheckDir(ctx context.Context, dirname string) error {
 	fi, err := os.Stat(dirname)
 	if err != nil {
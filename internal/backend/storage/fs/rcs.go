@@ -72,6 +72,11 @@ func (s *Store) GetRevision(ctx context.Context, name string, revision string) (
 	return []byte(""), backend.ErrNotSupported
 }
 
+// Head is not supported.
+func (s *Store) Head(context.Context) (string, error) {
+	return "", backend.ErrNotSupported
+}
+
 // Status is not implemented.
 func (s *Store) Status(context.Context) ([]byte, error) {
 	return []byte(""), backend.ErrNotSupported
@@ -71,7 +71,7 @@ func (s *Store) Set(ctx context.Context, name string, value []byte) error {
 		return store.ErrMeaninglessWrite
 	}
 
-	return os.WriteFile(filepath.Join(s.path, name), value, 0o644)
+	return writeFile(ctx, filename, value)
 }
 
 // Move moves the named entity to the new location.
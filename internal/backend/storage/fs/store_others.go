@@ -17,3 +17,19 @@ func notEmptyErr(err error) bool {
 
 	return false
 }
+
+// syncDir flushes the directory entries, e.g. after a rename.
+func syncDir(dir string) error {
+	fh, err := os.Open(dir)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := fh.Sync(); err != nil {
+		_ = fh.Close()
+
+		return err //nolint:wrapcheck
+	}
+
+	return fh.Close() //nolint:wrapcheck
+}
@@ -8,3 +8,9 @@ import (
 func notEmptyErr(err error) bool {
 	return err.(*os.PathError).Err == syscall.ERROR_DIR_NOT_EMPTY
 }
+
+// syncDir is a no-op. Directories can't be flushed on Windows, NTFS journals
+// renames instead.
+func syncDir(dir string) error {
+	return nil
+}
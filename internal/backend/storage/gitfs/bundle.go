@@ -0,0 +1,166 @@
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// bundleBaseRef points to the latest commit both sides of an air-gapped
+// setup are known to have, i.e. the head of the last imported or
+// acknowledged bundle. Incremental bundles only contain the commits after it.
+const bundleBaseRef = "refs/gopass/bundle-base"
+
+// CreateBundle writes a git bundle of the current branch to fn. Unless full
+// is set the bundle only contains the commits since the last imported or
+// acknowledged bundle. Exporting doesn't advance the base, since the bundle
+// might never be imported on the other side.
+func (g *Git) CreateBundle(ctx context.Context, fn string, full bool) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	fn, err := filepath.Abs(fn)
+	if err != nil {
+		return err
+	}
+
+	branch := g.defaultBranch(ctx)
+	args := []string{"bundle", "create", "--quiet", fn, branch}
+	if !full && g.hasRef(ctx, bundleBaseRef) {
+		args = append(args, "^"+bundleBaseRef)
+	}
+
+	_, stderr, err := g.captureCmd(ctx, "gitBundleCreate", args...)
+	if err != nil {
+		if strings.Contains(string(stderr), "empty bundle") {
+			return store.ErrEmptyBundle
+		}
+
+		return fmt.Errorf("failed to create bundle: %w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	return nil
+}
+
+// AckBundle records that the bundle fn, created by this repository, was
+// imported on the other side. The next incremental bundle only contains the
+// commits after its head.
+func (g *Git) AckBundle(ctx context.Context, fn string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	fn, err := filepath.Abs(fn)
+	if err != nil {
+		return err
+	}
+
+	heads, err := g.bundleHeads(ctx, fn)
+	if err != nil {
+		return err
+	}
+
+	commit, found := heads["refs/heads/"+g.defaultBranch(ctx)]
+	if !found {
+		return fmt.Errorf("bundle has no %s branch", g.defaultBranch(ctx))
+	}
+
+	// only bundles of this repository can be acknowledged.
+	if _, _, err := g.captureCmd(ctx, "gitBundleAckCommit", "merge-base", "--is-ancestor", commit, "HEAD"); err != nil {
+		return fmt.Errorf("the head of the bundle is not part of the current branch")
+	}
+
+	return g.Cmd(ctx, "gitBundleBase", "update-ref", bundleBaseRef, commit)
+}
+
+// VerifyBundle checks that fn is a valid bundle that can be applied to this
+// repository, i.e. it is not corrupted and all commits it builds on exist.
+// It returns the head of the bundle to import.
+func (g *Git) VerifyBundle(ctx context.Context, fn string) (string, error) {
+	fn, err := filepath.Abs(fn)
+	if err != nil {
+		return "", err
+	}
+
+	if _, stderr, err := g.captureCmd(ctx, "gitBundleVerify", "bundle", "verify", "--quiet", fn); err != nil {
+		return "", fmt.Errorf("invalid bundle: %s", strings.TrimSpace(string(stderr)))
+	}
+
+	heads, err := g.bundleHeads(ctx, fn)
+	if err != nil {
+		return "", err
+	}
+
+	branch := "refs/heads/" + g.defaultBranch(ctx)
+	if _, found := heads[branch]; found {
+		return branch, nil
+	}
+
+	debug.Log("bundle %s has no %s, heads: %q", fn, branch, heads)
+
+	if len(heads) != 1 {
+		return "", fmt.Errorf("bundle has no %s branch", branch)
+	}
+
+	for ref := range heads {
+		return ref, nil
+	}
+
+	return "", nil
+}
+
+// bundleHeads returns the commits of the refs in the bundle fn.
+func (g *Git) bundleHeads(ctx context.Context, fn string) (map[string]string, error) {
+	stdout, stderr, err := g.captureCmd(ctx, "gitBundleHeads", "bundle", "list-heads", fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bundle heads: %w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	heads := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		commit, ref, found := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !found {
+			continue
+		}
+		heads[ref] = commit
+	}
+
+	return heads, nil
+}
+
+// ImportBundle verifies fn and merges its head into the current branch.
+func (g *Git) ImportBundle(ctx context.Context, fn string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	fn, err := filepath.Abs(fn)
+	if err != nil {
+		return err
+	}
+
+	head, err := g.VerifyBundle(ctx, fn)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Cmd(ctx, "gitBundlePull", "pull", "--no-edit", "--no-rebase", fn, head); err != nil {
+		return fmt.Errorf("failed to merge bundle: %w", err)
+	}
+
+	return g.Cmd(ctx, "gitBundleBase", "update-ref", bundleBaseRef, "FETCH_HEAD")
+}
+
+func (g *Git) hasRef(ctx context.Context, ref string) bool {
+	_, _, err := g.captureCmd(ctx, "gitHasRef", "rev-parse", "--verify", "--quiet", ref)
+
+	return err == nil
+}
@@ -0,0 +1,101 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) { //nolint:paralleltest
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Dead Beef")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dead.beef@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "Dead Beef")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dead.beef@example.org")
+
+	ctx := context.Background()
+
+	dir := filepath.Join(td, "online")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	g, err := Init(ctx, dir, "Dead Beef", "dead.beef@example.org")
+	require.NoError(t, err)
+
+	commit := func(g *Git, name, content string) {
+		t.Helper()
+
+		require.NoError(t, g.Set(ctx, name, []byte(content)))
+		require.NoError(t, g.Add(ctx, name))
+		require.NoError(t, g.Commit(ctx, "add "+name))
+	}
+
+	commit(g, "foo.gpg", "foo")
+
+	// the initial bundle is cloned on the other side.
+	full := filepath.Join(td, "full.bundle")
+	require.NoError(t, g.CreateBundle(ctx, full, false))
+	_, err = g.VerifyBundle(ctx, full)
+	require.NoError(t, err)
+
+	dir2 := filepath.Join(td, "offline")
+	require.NoError(t, exec.Command("git", "clone", full, dir2).Run())
+	g2, err := New(dir2)
+	require.NoError(t, err)
+
+	// the export alone doesn't advance the base, only the acknowledgement.
+	require.NoError(t, g.CreateBundle(ctx, filepath.Join(td, "again.bundle"), false))
+	require.NoError(t, g.AckBundle(ctx, full))
+	assert.ErrorIs(t, g.CreateBundle(ctx, filepath.Join(td, "empty.bundle"), false), store.ErrEmptyBundle)
+
+	t.Run("incremental bundle", func(t *testing.T) {
+		commit(g, "bar.gpg", "bar")
+
+		fn := filepath.Join(td, "incr.bundle")
+		require.NoError(t, g.CreateBundle(ctx, fn, false))
+
+		require.NoError(t, g2.ImportBundle(ctx, fn))
+		buf, err := g2.Get(ctx, "bar.gpg")
+		require.NoError(t, err)
+		assert.Equal(t, "bar", string(buf))
+	})
+
+	t.Run("bundles back", func(t *testing.T) {
+		commit(g2, "baz.gpg", "baz")
+
+		fn := filepath.Join(td, "back.bundle")
+		require.NoError(t, g2.CreateBundle(ctx, fn, false))
+
+		require.NoError(t, g.ImportBundle(ctx, fn))
+		assert.True(t, g.Exists(ctx, "baz.gpg"))
+	})
+
+	t.Run("missing prerequisites", func(t *testing.T) {
+		dir3 := filepath.Join(td, "unrelated")
+		require.NoError(t, os.Mkdir(dir3, 0o700))
+		g3, err := Init(ctx, dir3, "Dead Beef", "dead.beef@example.org")
+		require.NoError(t, err)
+		commit(g3, "other.gpg", "other")
+
+		commit(g, "qux.gpg", "qux")
+		fn := filepath.Join(td, "qux.bundle")
+		require.NoError(t, g.CreateBundle(ctx, fn, false))
+
+		_, err = g3.VerifyBundle(ctx, fn)
+		assert.Error(t, err)
+		assert.Error(t, g3.ImportBundle(ctx, fn))
+		assert.Error(t, g3.AckBundle(ctx, fn))
+	})
+
+	t.Run("corrupted bundle", func(t *testing.T) {
+		fn := filepath.Join(td, "corrupt.bundle")
+		require.NoError(t, os.WriteFile(fn, []byte("# v2 git bundle\nnope\n"), 0o600))
+
+		_, err := g.VerifyBundle(ctx, fn)
+		assert.Error(t, err)
+	})
+}
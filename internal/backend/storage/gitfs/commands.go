@@ -24,19 +24,38 @@ func (l loader) Commands(i func(*cli.Context) error, s func(string) (string, err
 			Usage: "Run a git command inside a password store: gopass git [--store=<store>] <git-command>",
 			Description: "" +
 				"If the password store is a git repository, execute a git command " +
-				"specified by git-command-args.",
+				"specified by git-command-args. Secret names are translated to the " +
+				"encrypted files and commands that are known to corrupt the store " +
+				"are rejected.",
 			Hidden: true,
 			Before: i,
 			Action: func(c *cli.Context) error {
 				ctx := ctxutil.WithGlobalFlags(c)
 				store := c.String("store")
+				args := c.Args().Slice()
+
+				if !c.IsSet("store") {
+					store, args = splitMount(args, func(alias string) bool {
+						_, err := s(alias)
+
+						return err == nil
+					})
+				}
 
 				path, err := s(store)
 				if err != nil {
 					return exit.Error(exit.Unknown, err, "failed to get sub store %s: %s", store, err)
 				}
 
-				args := c.Args().Slice()
+				if _, err := exec.LookPath("git"); err != nil {
+					return exit.Error(exit.Unknown, err, "The git binary is required to run git commands. The built-in git implementation only supports syncing: %s", err)
+				}
+
+				if err := checkPassthrough(path, args); err != nil && !c.Bool("unsafe") {
+					return exit.Error(exit.Aborted, err, "Refusing to run git: %s. Use --unsafe to run it anyway", err)
+				}
+
+				args = translatePaths(path, args)
 				out.Noticef(ctx, "Running 'git %s' in %s...", strings.Join(args, " "), path)
 				cmd := exec.CommandContext(ctx, "git", args...)
 				cmd.Dir = path
@@ -48,8 +67,13 @@ func (l loader) Commands(i func(*cli.Context) error, s func(string) (string, err
 			},
 			Flags: []cli.Flag{
 				&cli.StringFlag{
-					Name:  "store",
-					Usage: "Store to operate on",
+					Name:    "store",
+					Aliases: []string{"s"},
+					Usage:   "Store to operate on. Defaults to the mount of the first secret name given",
+				},
+				&cli.BoolFlag{
+					Name:  "unsafe",
+					Usage: "Run git commands that are known to corrupt the store",
 				},
 			},
 		},
@@ -0,0 +1,336 @@
+package gitfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// GCTokenFile is written to the root of the store whenever the history is
+// rewritten by gopass gc. Other clones compare it to the remote version before
+// pulling, so they don't merge the scrubbed history back in.
+const GCTokenFile = ".gopass-gc"
+
+// leaseFile records the remote branches and the tags before the history was
+// rewritten. ForcePush uses them as leases.
+const leaseFile = "gopass-gc-leases"
+
+// ErrHistoryRewritten is returned if the history of the remote was rewritten
+// by gopass gc since the last pull.
+var ErrHistoryRewritten = errors.New("the history of the remote was rewritten by 'gopass gc'. Run 'gopass gc --adopt' to adopt it")
+
+// ErrRewriteNotPushed is returned if the local history was rewritten by gopass
+// gc but not pushed, yet. Pulling would merge the scrubbed history back in.
+var ErrRewriteNotPushed = errors.New("the history was rewritten by 'gopass gc' but not pushed. Run 'gopass gc --push' first")
+
+// DeletedBefore returns all files that were deleted before the given time and
+// were not re-created since.
+func (g *Git) DeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	stdout, stderr, err := g.captureCmd(ctx, "gitLogDeleted", "log", "--diff-filter=D", "--name-only", "--format=%x1e%at")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	// the log is ordered from newest to oldest, so the first deletion of a
+	// file we see is the latest one.
+	deleted := map[string]time.Time{}
+	for _, rec := range strings.Split(string(stdout), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(rec), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+
+		ts, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+		if err != nil {
+			debug.Log("invalid timestamp %q: %s", lines[0], err)
+
+			continue
+		}
+
+		for _, f := range lines[1:] {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			if _, found := deleted[f]; !found {
+				deleted[f] = time.Unix(ts, 0)
+			}
+		}
+	}
+
+	files := make([]string, 0, len(deleted))
+	for f, ts := range deleted {
+		if ts.After(before) || g.fs.Exists(ctx, f) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// Scrub rewrites the whole history to permanently remove the given files,
+// compacts the repository and writes a new GC token.
+func (g *Git) Scrub(ctx context.Context, files []string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	if len(files) < 1 {
+		return nil
+	}
+
+	// rewriting the history of a large store takes much longer than a
+	// regular git command.
+	ctx, cancel := deadline.With(ctx, deadline.Maintenance)
+	defer cancel()
+
+	tmp, err := os.CreateTemp("", "gopass-gc-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(strings.Join(files, "\n") + "\n"); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := g.recordLeases(ctx); err != nil {
+		return err
+	}
+
+	// rewrite all refs, including the remote tracking branches. Otherwise
+	// they would keep the scrubbed objects alive until the next push.
+	ctx = withEnv(ctx, "FILTER_BRANCH_SQUELCH_WARNING=1")
+	filter := "git rm -r --cached --ignore-unmatch --quiet --pathspec-from-file=" + strconv.Quote(tmp.Name())
+	if err := g.Cmd(ctx, "gitFilterBranch", "filter-branch", "--force", "--prune-empty", "--index-filter", filter, "--", "--all"); err != nil {
+		return fmt.Errorf("failed to rewrite history: %w", err)
+	}
+
+	if err := g.removeOriginalRefs(ctx); err != nil {
+		return err
+	}
+
+	if err := g.prune(ctx, "--aggressive"); err != nil {
+		return err
+	}
+
+	token := fmt.Sprintf("%d %d\n", time.Now().UnixNano(), len(files))
+	if err := os.WriteFile(filepath.Join(g.fs.Path(), GCTokenFile), []byte(token), fileMode); err != nil {
+		return fmt.Errorf("failed to write GC token: %w", err)
+	}
+
+	if err := g.Add(ctx, GCTokenFile); err != nil {
+		return err
+	}
+
+	return g.Commit(ctx, fmt.Sprintf("Scrubbed %d deleted files from history", len(files)))
+}
+
+// recordLeases records the remote tracking branches and the tags before the
+// first rewrite. Later rewrites keep the recorded values, since the remote
+// still has the original history until ForcePush succeeded.
+func (g *Git) recordLeases(ctx context.Context) error {
+	fn := filepath.Join(g.fs.Path(), ".git", leaseFile)
+	if _, err := os.Stat(fn); err == nil {
+		return nil
+	}
+
+	stdout, _, err := g.captureCmd(ctx, "gitForEachRef", "for-each-ref", "--format=%(refname) %(objectname)", "refs/remotes/", "refs/tags/")
+	if err != nil {
+		return fmt.Errorf("failed to list refs: %w", err)
+	}
+
+	return os.WriteFile(fn, stdout, fileMode)
+}
+
+// leases returns the --force-with-lease arguments and refspecs to push all
+// local branches and tags. Refs the remote didn't have before the rewrite
+// must not exist on the remote.
+func (g *Git) leases(ctx context.Context, remote string) ([]string, []string, error) {
+	buf, err := os.ReadFile(filepath.Join(g.fs.Path(), ".git", leaseFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	old := map[string]string{}
+	for _, line := range strings.Split(string(buf), "\n") {
+		ref, sha, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		if b, found := strings.CutPrefix(ref, "refs/remotes/"+remote+"/"); found {
+			ref = "refs/heads/" + b
+		}
+		old[ref] = sha
+	}
+
+	stdout, _, err := g.captureCmd(ctx, "gitForEachRef", "for-each-ref", "--format=%(refname)", "refs/heads/", "refs/tags/")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+
+	var args, refspecs []string
+	for _, ref := range strings.Fields(string(stdout)) {
+		args = append(args, "--force-with-lease="+ref+":"+old[ref])
+		refspecs = append(refspecs, ref+":"+ref)
+	}
+
+	return args, refspecs, nil
+}
+
+// prune expires the reflog and removes all unreachable objects.
+func (g *Git) prune(ctx context.Context, args ...string) error {
+	ctx, cancel := deadline.With(ctx, deadline.Maintenance)
+	defer cancel()
+
+	if err := g.Cmd(ctx, "gitReflogExpire", "reflog", "expire", "--expire=now", "--all"); err != nil {
+		return fmt.Errorf("failed to expire reflog: %w", err)
+	}
+
+	if err := g.Cmd(ctx, "gitGC", append([]string{"gc", "--prune=now"}, args...)...); err != nil {
+		return fmt.Errorf("failed to compact repository: %w", err)
+	}
+
+	return nil
+}
+
+// removeOriginalRefs removes the backup refs of git filter-branch. Otherwise
+// they would keep the scrubbed objects alive.
+func (g *Git) removeOriginalRefs(ctx context.Context) error {
+	stdout, _, err := g.captureCmd(ctx, "gitForEachRef", "for-each-ref", "--format=%(refname)", "refs/original/")
+	if err != nil {
+		return fmt.Errorf("failed to list backup refs: %w", err)
+	}
+
+	for _, ref := range strings.Fields(string(stdout)) {
+		if err := g.Cmd(ctx, "gitUpdateRef", "update-ref", "-d", ref); err != nil {
+			return fmt.Errorf("failed to remove backup ref %s: %w", ref, err)
+		}
+	}
+
+	return nil
+}
+
+// ForcePush pushes the rewritten history of all branches and tags to the
+// remote. Each ref is pushed with a lease on its value before the rewrite, so
+// changes pushed by others in the meantime are not overwritten. Afterwards
+// the objects only reachable from the old history are removed.
+func (g *Git) ForcePush(ctx context.Context, remote, branch string) error {
+	remote, _, proxyArgs, stop, err := g.remoteArgs(ctx, remote, branch)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	leases, refspecs, err := g.leases(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	args := append(append(append(proxyArgs, "push"), leases...), remote)
+	if err := g.networkCmd(ctx, "gitForcePush", append(args, refspecs...)...); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(g.fs.Path(), ".git", leaseFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return g.prune(ctx)
+}
+
+// Adopt replaces the local history with the rewritten history of the remote.
+// Local commits that were not pushed are lost.
+func (g *Git) Adopt(ctx context.Context, remote, branch string) error {
+	remote, branch, proxyArgs, stop, err := g.remoteArgs(ctx, remote, branch)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := g.networkCmd(ctx, "gitFetch", append(proxyArgs, "fetch", remote, branch)...); err != nil {
+		return err
+	}
+
+	if err := g.Cmd(ctx, "gitReset", "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("failed to reset to %s/%s: %w", remote, branch, err)
+	}
+
+	return g.prune(ctx)
+}
+
+// fetch fetches the remote branch into FETCH_HEAD and makes sure its history
+// was not rewritten by gopass gc since the last pull.
+func (g *Git) fetch(ctx context.Context, proxyArgs []string, remote, branch string) error {
+	if _, err := os.Stat(filepath.Join(g.fs.Path(), ".git", leaseFile)); err == nil {
+		return ErrRewriteNotPushed
+	}
+
+	if err := g.networkCmd(ctx, "gitFetch", append(proxyArgs, "fetch", remote, branch)...); err != nil {
+		return err
+	}
+
+	return g.checkRewritten(ctx)
+}
+
+// checkRewritten compares the GC token of FETCH_HEAD to the local one.
+func (g *Git) checkRewritten(ctx context.Context) error {
+	theirs, _, err := g.captureCmd(ctx, "gitShowToken", "show", "FETCH_HEAD:"+GCTokenFile)
+	if err != nil {
+		// the history of the remote was never rewritten.
+		return nil
+	}
+
+	ours, _, _ := g.captureCmd(ctx, "gitShowToken", "show", "HEAD:"+GCTokenFile)
+	if bytes.Equal(bytes.TrimSpace(ours), bytes.TrimSpace(theirs)) {
+		return nil
+	}
+
+	return ErrHistoryRewritten
+}
+
+// remoteArgs resolves the default remote and branch and returns the
+// arguments to reach the remote. The returned function must be called once
+// the network operations are done.
+func (g *Git) remoteArgs(ctx context.Context, remote, branch string) (string, string, []string, func(), error) {
+	if network.Offline(ctx) {
+		return "", "", nil, nil, network.ErrOffline
+	}
+
+	if branch == "" {
+		branch = g.defaultBranch(ctx)
+	}
+
+	if remote == "" {
+		remote = g.defaultRemote(ctx, branch)
+	}
+
+	remoteURL, err := g.remoteURL(ctx, remote)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+
+	proxyArgs, stop, err := g.connectArgs(ctx, remote, remoteURL)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+
+	return remote, branch, proxyArgs, stop, nil
+}
@@ -0,0 +1,102 @@
+package gitfs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrub(t *testing.T) { //nolint:paralleltest
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Dead Beef")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dead.beef@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "Dead Beef")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dead.beef@example.org")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	remote := filepath.Join(td, "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+
+	dir := filepath.Join(td, "store")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	g, err := Init(ctx, dir, "Dead Beef", "dead.beef@example.org")
+	require.NoError(t, err)
+	require.NoError(t, g.AddRemote(ctx, "origin", remote))
+
+	commit := func(msg string) {
+		require.NoError(t, g.Add(ctx, dir))
+		require.NoError(t, g.Commit(ctx, msg))
+	}
+
+	require.NoError(t, g.Set(ctx, "old.gpg", []byte("secret")))
+	require.NoError(t, g.Set(ctx, "keep.gpg", []byte("keep")))
+	commit("add")
+	require.NoError(t, exec.Command("git", "-C", dir, "tag", "v1").Run())
+	require.NoError(t, exec.Command("git", "-C", dir, "push", "origin", "v1").Run())
+	require.NoError(t, g.Delete(ctx, "old.gpg"))
+	commit("delete")
+	require.NoError(t, g.Push(ctx, "origin", g.defaultBranch(ctx)))
+
+	// a second clone of the store.
+	dir2 := filepath.Join(td, "store2")
+	require.NoError(t, exec.Command("git", "clone", remote, dir2).Run())
+	g2, err := New(dir2)
+	require.NoError(t, err)
+
+	files, err := g.DeletedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	files, err = g.DeletedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old.gpg"}, files)
+
+	blob, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD~1:old.gpg").Output()
+	require.NoError(t, err)
+
+	require.NoError(t, g.Scrub(ctx, files))
+	// the blob must be gone even before pushing.
+	assert.Error(t, exec.Command("git", "-C", dir, "cat-file", "-e", string(bytes.TrimSpace(blob))).Run())
+	assert.ErrorIs(t, g.Pull(ctx, "", ""), ErrRewriteNotPushed)
+	_, err = g.GetRevision(ctx, "old.gpg", "HEAD~2")
+	assert.Error(t, err)
+	revs, err := g.Revisions(ctx, "old.gpg")
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+	assert.FileExists(t, filepath.Join(dir, GCTokenFile))
+
+	require.NoError(t, g.ForcePush(ctx, "", ""))
+	assert.NoFileExists(t, filepath.Join(dir, ".git", leaseFile))
+
+	// tags are rewritten and pushed, too.
+	local, err := exec.Command("git", "-C", dir, "rev-parse", "v1").Output()
+	require.NoError(t, err)
+	theirs, err := exec.Command("git", "-C", remote, "rev-parse", "v1").Output()
+	require.NoError(t, err)
+	assert.Equal(t, string(local), string(theirs))
+
+	// the other clone must not pull the old history back in.
+	assert.ErrorIs(t, g2.Pull(ctx, "", ""), ErrHistoryRewritten)
+	require.NoError(t, g2.Adopt(ctx, "", ""))
+	require.NoError(t, g2.Pull(ctx, "", ""))
+	revs, err = g2.Revisions(ctx, "old.gpg")
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+}
@@ -4,7 +4,9 @@ package gitfs
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -14,6 +16,8 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/internal/network"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -26,6 +30,7 @@ type contextKey int
 
 const (
 	ctxKeyPathOverride contextKey = iota
+	ctxKeyEnv
 )
 
 func withPathOverride(ctx context.Context, path string) context.Context {
@@ -40,10 +45,27 @@ func getPathOverride(ctx context.Context, def string) string {
 	return def
 }
 
+// withEnv adds environment variables to the git commands run with this context.
+func withEnv(ctx context.Context, env ...string) context.Context {
+	return context.WithValue(ctx, ctxKeyEnv, append(getEnv(ctx), env...))
+}
+
+func getEnv(ctx context.Context) []string {
+	if env, ok := ctx.Value(ctxKeyEnv).([]string); ok {
+		return env
+	}
+
+	return nil
+}
+
 // Git is a cli based git backend.
 type Git struct {
 	fs  *fs.Store
 	cfg *gitconfig.Configs
+	// protected stores push to a staging branch. See SetProtected.
+	notary backend.Notary
+	// staged is the commit staged by the last push, if any.
+	staged string
 }
 
 // New creates a new git cli based git backend.
@@ -69,6 +91,9 @@ func Clone(ctx context.Context, repo, path, userName, userEmail string) (*Git, e
 		cfg: gitconfig.New(),
 	}
 
+	ctx, cancel := deadline.With(ctx, deadline.Network)
+	defer cancel()
+
 	if err := g.Cmd(withPathOverride(ctx, filepath.Dir(path)), "Clone", "clone", repo, path); err != nil {
 		return nil, err
 	}
@@ -137,6 +162,8 @@ func (g *Git) captureCmd(ctx context.Context, name string, args ...string) ([]by
 
 	cmd := exec.CommandContext(ctx, "git", args[0:]...)
 	cmd.Dir = getPathOverride(ctx, g.fs.Path())
+	cmd.Env = append(os.Environ(), network.GitEnv(ctx)...)
+	cmd.Env = append(cmd.Env, getEnv(ctx)...)
 	cmd.Stdout = bufOut
 	cmd.Stderr = bufErr
 
@@ -148,16 +175,96 @@ func (g *Git) captureCmd(ctx context.Context, name string, args ...string) ([]by
 
 // Cmd runs an git command.
 func (g *Git) Cmd(ctx context.Context, name string, args ...string) error {
+	ctx, cancel := deadline.With(ctx, deadline.Git)
+	defer cancel()
+
+	started := time.Now()
 	stdout, stderr, err := g.captureCmd(ctx, name, args...)
 	if err != nil {
 		debug.Log("CMD: %s %+v\nError: %s\nOutput:\n  Stdout: %q\n  Stderr: %q", name, args, err, string(stdout), string(stderr))
 
+		if deadline.Interrupted(ctx, err) {
+			return g.interrupted(ctx, "git "+args[0], started)
+		}
+
 		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(stderr)))
 	}
 
 	return nil
 }
 
+// interrupted cleans up after a git command was killed because it was
+// cancelled or timed out. The killed command may have left its lock on the
+// index behind, which would make all further commands fail. Git doesn't
+// record the owner of the lock, so it's only removed if it was created after
+// the killed command was started. An older lock belongs to somebody else,
+// e.g. a git command run outside of gopass.
+func (g *Git) interrupted(ctx context.Context, what string, started time.Time) error {
+	lock := filepath.Join(g.fs.Path(), ".git", "index.lock")
+	if fi, err := os.Stat(lock); err == nil && !fi.ModTime().Before(started.Truncate(time.Second)) {
+		if err := os.Remove(lock); err != nil {
+			debug.Log("failed to remove %s: %s", lock, err)
+		}
+	}
+
+	return fmt.Errorf("%s was interrupted: %w", what, ctx.Err())
+}
+
+// sshCommand returns the SSH command git uses for this repository.
+func (g *Git) sshCommand(ctx context.Context) string {
+	// always called since it creates the socket dir, if necessary.
+	sc := gitSSHCommand()
+	if c, err := g.ConfigGet(ctx, "core.sshCommand"); err == nil && c != "" {
+		return c
+	}
+
+	return sc
+}
+
+// networkCmd runs a git command that accesses the network. Transient
+// network failures are retried with a backoff.
+func (g *Git) networkCmd(ctx context.Context, name string, args ...string) error {
+	_, err := g.networkOutput(ctx, name, args...)
+
+	return err
+}
+
+// networkOutput is like networkCmd but returns the output of the command.
+func (g *Git) networkOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := deadline.With(ctx, deadline.Network)
+	defer cancel()
+
+	var output []byte
+	err := network.Retry(ctx, name, func() error {
+		started := time.Now()
+		stdout, stderr, err := g.captureCmd(ctx, name, args...)
+		if err == nil {
+			output = stdout
+
+			return nil
+		}
+
+		debug.Log("CMD: %s %+v\nError: %s\nOutput:\n  Stdout: %q\n  Stderr: %q", name, args, err, string(stdout), string(stderr))
+
+		if deadline.Interrupted(ctx, err) {
+			return network.Permanent(g.interrupted(ctx, "git "+strings.ToLower(strings.TrimPrefix(name, "git")), started))
+		}
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(stderr)))
+
+		if se := strings.ToLower(string(stderr)); strings.Contains(se, "could not resolve") || strings.Contains(se, "network is unreachable") {
+			return network.Permanent(fmt.Errorf("%w: %w", network.ErrOffline, err))
+		}
+
+		if network.IsTransient(string(stderr)) {
+			return err
+		}
+
+		return network.Permanent(err)
+	})
+
+	return output, err
+}
+
 // Name returns git.
 func (g *Git) Name() string {
 	return name
@@ -284,8 +391,11 @@ func (g *Git) defaultBranch(ctx context.Context) string {
 // PushPull pushes the repo to it's origin.
 // optional arguments: remote and branch.
 func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
-	if ctxutil.IsNoNetwork(ctx) {
-		debug.Log("Skipping network ops. NoNetwork=true")
+	if op == "push" {
+		g.staged = ""
+	}
+	if network.Offline(ctx) {
+		debug.Log("Skipping network ops. Offline=true")
 
 		return nil
 	}
@@ -303,14 +413,29 @@ func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
 		remote = g.defaultRemote(ctx, branch)
 	}
 
-	urlKey := "remote." + remote + ".url"
-	if v, err := g.ConfigGet(ctx, urlKey); err != nil || v == "" {
-		debug.Log("No value for %q found in config. Keys: %+v", urlKey, g.cfg.Keys())
+	defer debug.Start("git %s %s %s", op, remote, branch).End()
 
-		return store.ErrGitNoRemote
+	remoteURL, err := g.remoteURL(ctx, remote)
+	if err != nil {
+		return err
 	}
 
-	if err := g.Cmd(ctx, "gitPush", "pull", remote, branch); err != nil {
+	proxyArgs, stop, err := g.connectArgs(ctx, remote, remoteURL)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	// a pull is a fetch and a merge. They are run separately, so a history
+	// scrubbed by gopass gc is never merged back in.
+	err = g.fetch(ctx, proxyArgs, remote, branch)
+	if errors.Is(err, ErrHistoryRewritten) || errors.Is(err, ErrRewriteNotPushed) {
+		return err
+	}
+	if err == nil {
+		err = g.Cmd(ctx, "gitMerge", "merge", "--no-edit", "FETCH_HEAD")
+	}
+	if err != nil {
 		if op == "pull" {
 			return err
 		}
@@ -325,13 +450,17 @@ func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
 		out.Warningf(ctx, "Found untracked files: %+v", uf)
 	}
 
-	return g.Cmd(ctx, "gitPush", "push", remote, branch)
+	if g.notary != nil {
+		return g.pushStaging(ctx, proxyArgs, remote, branch)
+	}
+
+	return g.networkCmd(ctx, "gitPush", append(proxyArgs, "push", remote, branch)...)
 }
 
 // Push pushes to the git remote.
 func (g *Git) Push(ctx context.Context, remote, branch string) error {
-	if ctxutil.IsNoNetwork(ctx) {
-		debug.Log("Skipping network ops. NoNetwork=true")
+	if network.Offline(ctx) {
+		debug.Log("Skipping network ops. Offline=true")
 
 		return nil
 	}
@@ -341,8 +470,8 @@ func (g *Git) Push(ctx context.Context, remote, branch string) error {
 
 // Pull pulls from the git remote.
 func (g *Git) Pull(ctx context.Context, remote, branch string) error {
-	if ctxutil.IsNoNetwork(ctx) {
-		debug.Log("Skipping network ops. NoNetwork=true")
+	if network.Offline(ctx) {
+		debug.Log("Skipping network ops. Offline=true")
 
 		return nil
 	}
@@ -453,7 +582,20 @@ func (g *Git) Status(ctx context.Context) ([]byte, error) {
 	return stdout, nil
 }
 
+// Head returns the hash of the current commit.
+func (g *Git) Head(ctx context.Context) (string, error) {
+	stdout, _, err := g.captureCmd(ctx, "gitHead", "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
+
 // Compact will run git gc.
 func (g *Git) Compact(ctx context.Context) error {
+	ctx, cancel := deadline.With(ctx, deadline.Maintenance)
+	defer cancel()
+
 	return g.Cmd(ctx, "gitGC", "gc", "--aggressive")
 }
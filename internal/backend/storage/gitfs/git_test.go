@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -46,6 +47,13 @@ func TestGit(t *testing.T) {
 		assert.NoError(t, git.Commit(ctx, "added some-file"))
 		assert.False(t, git.HasStagedChanges(ctx))
 
+		revs, err := git.Revisions(ctx, "some-file")
+		require.NoError(t, err)
+		require.Len(t, revs, 1)
+		head, err := git.Head(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, revs[0].Hash, head)
+
 		assert.Error(t, git.Push(ctx, "origin", "master"))
 		assert.Error(t, git.Pull(ctx, "origin", "master"))
 	})
@@ -80,3 +88,38 @@ func TestGit(t *testing.T) {
 		assert.Equal(t, "foobar", string(content))
 	})
 }
+
+func TestInterrupted(t *testing.T) {
+	td := t.TempDir()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	git, err := Init(ctx, td, "Dead Beef", "dead.beef@example.org")
+	require.NoError(t, err)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	// a lock that is older than the killed command belongs to somebody else.
+	lock := filepath.Join(td, ".git", "index.lock")
+	require.NoError(t, os.WriteFile(lock, nil, 0o644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lock, old, old))
+
+	err = git.Cmd(cctx, "gitAdd", "add", "--all")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.FileExists(t, lock)
+
+	// a killed git command leaves its lock behind.
+	started := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(lock, time.Now(), time.Now()))
+	err = git.interrupted(cctx, "git add", started)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, lock)
+
+	require.NoError(t, os.WriteFile(filepath.Join(td, "some-file"), []byte("foobar"), 0o644))
+	assert.NoError(t, git.Add(ctx, "some-file"))
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/gogitfs"
 	"github.com/gopasspw/gopass/pkg/fsutil"
 	"github.com/gopasspw/gopass/pkg/termio"
 )
@@ -20,22 +21,36 @@ func init() {
 
 type loader struct{}
 
+// New opens an existing git repo. It falls back to the built-in git
+// implementation if the git binary is not available or git.native is set.
 func (l loader) New(ctx context.Context, path string) (backend.Storage, error) {
+	if gogitfs.Wanted(ctx) {
+		return gogitfs.New(path)
+	}
+
 	return New(path)
 }
 
 // Open implements backend.RCSLoader.
 func (l loader) Open(ctx context.Context, path string) (backend.Storage, error) {
-	return New(path)
+	return l.New(ctx, path)
 }
 
 // Clone implements backend.RCSLoader.
 func (l loader) Clone(ctx context.Context, repo, path string) (backend.Storage, error) {
+	if gogitfs.Wanted(ctx) {
+		return gogitfs.Clone(ctx, repo, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
+	}
+
 	return Clone(ctx, repo, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
 }
 
 // Init implements backend.RCSLoader.
 func (l loader) Init(ctx context.Context, path string) (backend.Storage, error) {
+	if gogitfs.Wanted(ctx) {
+		return gogitfs.Init(ctx, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
+	}
+
 	return Init(ctx, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
 }
 
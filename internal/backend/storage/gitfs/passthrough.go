@@ -0,0 +1,185 @@
+package gitfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// secretExts are the file extensions of encrypted secrets. Secret names given
+// to gopass git are translated to these files.
+var secretExts = []string{"gpg", "age", "txt"}
+
+// checkPassthrough rejects git commands that are known to leave the store
+// in an inconsistent state, e.g. by restoring some secrets to an older
+// revision that might have been encrypted for a different set of recipients.
+// dir is the root of the store.
+func checkPassthrough(dir string, args []string) error {
+	if len(args) < 1 {
+		return nil
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "clean":
+		for _, a := range rest {
+			if a == "--" {
+				break
+			}
+			if a == "-n" || a == "--dry-run" {
+				return nil
+			}
+			if !strings.HasPrefix(a, "-") || strings.HasPrefix(a, "--") {
+				continue
+			}
+			// -x and -X remove ignored files, e.g. the local recipient
+			// caches and the audit exclude files.
+			if strings.ContainsAny(a, "xX") {
+				return fmt.Errorf("'git clean %s' removes ignored files from the store", a)
+			}
+		}
+	case "checkout":
+		// a checkout of only some paths mixes revisions inside the store.
+		if checkoutPaths(dir, rest) {
+			return fmt.Errorf("'git checkout' of a partial tree mixes revisions inside the store. Use 'gopass history' and 'gopass show --revision' instead")
+		}
+	case "restore":
+		for _, a := range rest {
+			if a == "-s" || a == "--source" || strings.HasPrefix(a, "--source=") || strings.HasPrefix(a, "-s") && len(a) > 2 {
+				return fmt.Errorf("'git restore --source' mixes revisions inside the store. Use 'gopass history' and 'gopass show --revision' instead")
+			}
+		}
+	case "sparse-checkout":
+		return fmt.Errorf("'git sparse-checkout' hides secrets from gopass")
+	}
+
+	return nil
+}
+
+// checkoutPaths returns true if the git checkout arguments contain a
+// pathspec. Only switching to a branch or commit is allowed, i.e. at most one
+// positional argument (two with -b, -B or --orphan, whose value is the new
+// branch) that isn't a file or directory in the store.
+func checkoutPaths(dir string, args []string) bool {
+	var pos []string
+	maxPos := 1
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--" || a == "-p" || a == "--patch" || strings.HasPrefix(a, "--pathspec-from-file"):
+			return true
+		case a == "-b" || a == "-B" || a == "--orphan":
+			maxPos = 2
+		case strings.HasPrefix(a, "-"):
+		default:
+			pos = append(pos, a)
+		}
+	}
+
+	if len(pos) > maxPos {
+		return true
+	}
+
+	// the value of -b, -B and --orphan is the name of the new branch.
+	if maxPos == 2 && len(pos) > 0 {
+		pos = pos[1:]
+	}
+
+	// any existing path, including directories, is treated as a pathspec.
+	for _, p := range pos {
+		if translatePath(dir, p) != p {
+			return true
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p))); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// translatePaths replaces secret names in the git arguments with the path of
+// the encrypted file relative to the store root. Arguments that refer to
+// existing files or do not look like secrets are left alone.
+func translatePaths(dir string, args []string) []string {
+	if len(args) < 2 {
+		return args
+	}
+
+	res := make([]string, 0, len(args))
+	res = append(res, args[0])
+
+	for _, a := range args[1:] {
+		res = append(res, translatePath(dir, a))
+	}
+
+	return res
+}
+
+func translatePath(dir, arg string) string {
+	if arg == "" || strings.HasPrefix(arg, "-") || filepath.IsAbs(arg) {
+		return arg
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, arg)); err == nil {
+		return arg
+	}
+
+	for _, ext := range secretExts {
+		fn := arg + "." + ext
+		fi, err := os.Stat(filepath.Join(dir, filepath.FromSlash(fn)))
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		debug.Log("translated %q to %q", arg, fn)
+
+		return fn
+	}
+
+	return arg
+}
+
+// splitMount detects the mount point of the first secret name in args. It
+// returns the mount point and args with the mount point stripped from all
+// names inside this mount. If no argument refers to a mount the root store is
+// used.
+func splitMount(args []string, isMount func(string) bool) (string, []string) {
+	if len(args) < 2 {
+		return "", args
+	}
+
+	var mp string
+	for _, a := range args[1:] {
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		// prefer the longest mount point, i.e. nested mounts.
+		p := strings.Split(a, "/")
+		for i := len(p) - 1; i > 0; i-- {
+			if cand := strings.Join(p[:i], "/"); isMount(cand) {
+				mp = cand
+
+				break
+			}
+		}
+		if mp != "" {
+			break
+		}
+	}
+
+	if mp == "" {
+		return "", args
+	}
+
+	res := make([]string, 0, len(args))
+	res = append(res, args[0])
+	for _, a := range args[1:] {
+		res = append(res, strings.TrimPrefix(a, mp+"/"))
+	}
+
+	return mp, res
+}
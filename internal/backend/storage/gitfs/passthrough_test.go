@@ -0,0 +1,85 @@
+package gitfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPassthrough(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.gpg"), []byte("foo"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "web"), 0o700))
+
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{args: nil, ok: true},
+		{args: []string{"log", "--oneline"}, ok: true},
+		{args: []string{"clean", "-fd"}, ok: true},
+		{args: []string{"clean", "-fdx"}, ok: false},
+		{args: []string{"clean", "-X"}, ok: false},
+		{args: []string{"clean", "-n", "-x"}, ok: true},
+		{args: []string{"checkout", "main"}, ok: true},
+		{args: []string{"checkout", "HEAD~2", "--", "foo.gpg"}, ok: false},
+		{args: []string{"checkout", "HEAD~3", "foo"}, ok: false},
+		{args: []string{"checkout", "foo"}, ok: false},
+		{args: []string{"checkout", "foo.gpg"}, ok: false},
+		{args: []string{"checkout", "-b", "topic", "main"}, ok: true},
+		{args: []string{"checkout", "-b", "topic", "main", "foo"}, ok: false},
+		{args: []string{"checkout", "web"}, ok: false},
+		{args: []string{"checkout", "."}, ok: false},
+		{args: []string{"checkout", "-b", "web"}, ok: true},
+		{args: []string{"checkout", "-b", "topic", "web"}, ok: false},
+		{args: []string{"restore", "--source=HEAD~1", "foo.gpg"}, ok: false},
+		{args: []string{"restore", "--staged", "foo.gpg"}, ok: true},
+		{args: []string{"sparse-checkout", "init"}, ok: false},
+	} {
+		err := checkPassthrough(dir, tc.args)
+		if tc.ok {
+			assert.NoError(t, err, "%v", tc.args)
+		} else {
+			assert.Error(t, err, "%v", tc.args)
+		}
+	}
+}
+
+func TestTranslatePaths(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "web"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "web", "foo.gpg"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "bar.age"), []byte("x"), 0o600))
+
+	assert.Equal(t,
+		[]string{"log", "--oneline", "--", "web/foo.gpg", "bar.age", "web", "HEAD~1"},
+		translatePaths(td, []string{"log", "--oneline", "--", "web/foo", "bar", "web", "HEAD~1"}),
+	)
+}
+
+func TestSplitMount(t *testing.T) {
+	t.Parallel()
+
+	isMount := func(alias string) bool {
+		return alias == "work" || alias == "work/team"
+	}
+
+	mp, args := splitMount([]string{"log", "-p", "work/web/foo"}, isMount)
+	assert.Equal(t, "work", mp)
+	assert.Equal(t, []string{"log", "-p", "web/foo"}, args)
+
+	mp, args = splitMount([]string{"log", "work/team/foo"}, isMount)
+	assert.Equal(t, "work/team", mp)
+	assert.Equal(t, []string{"log", "foo"}, args)
+
+	mp, args = splitMount([]string{"log", "web/foo"}, isMount)
+	assert.Equal(t, "", mp)
+	assert.Equal(t, []string{"log", "web/foo"}, args)
+}
@@ -3,7 +3,13 @@
 
 package gitfs
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
 
 // gitSSHCommand returns a SSH command instructing git to use SSH
 // with persistent connections through a custom socket.
@@ -13,8 +19,18 @@ import "os"
 // Note: Setting GIT_SSH_COMMAND, possibly to an empty string, will take
 // precedence over this setting.
 //
-// %C is a hash of %l%h%p%r and should avoid "path too long for unix domain socket"
-// errors. If you still encounter this error set TMPDIR to a short path, e.g. /tmp.
+// The sockets live in the private runtime dir of the user, so other users
+// can't use or hijack them. The dir is created if necessary, it doesn't
+// survive a reboot. %C is a hash of %l%h%p%r and should avoid "path too long
+// for unix domain socket" errors. If you still encounter this error set
+// GOPASS_RUNTIME_DIR to a short path.
 func gitSSHCommand() string {
-	return "ssh -oControlMaster=auto -oControlPersist=600 -oControlPath=" + os.TempDir() + "/.ssh-%C"
+	dir := appdir.UserRuntime()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		debug.Log("failed to create runtime dir %s: %s", dir, err)
+
+		return ""
+	}
+
+	return "ssh -oControlMaster=auto -oControlPersist=600 -oControlPath=" + filepath.Join(dir, "ssh-%C")
 }
@@ -0,0 +1,24 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package gitfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitSSHCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run")
+	t.Setenv("GOPASS_RUNTIME_DIR", dir)
+
+	assert.Contains(t, gitSSHCommand(), "-oControlPath="+filepath.Join(dir, "ssh-%C"))
+
+	fi, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), fi.Mode().Perm())
+}
@@ -0,0 +1,377 @@
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// stagingPrefix is the prefix of the branches pushes to protected stores
+	// go to. Every recipient and device has its own staging branch.
+	stagingPrefix = "gopass-staging/"
+	// approvalsRef holds who staged and approved a commit as git notes, so
+	// they are shared through the remote without changing the commit.
+	approvalsRef = "refs/notes/gopass-approvals"
+
+	// notes are "<prefix><recipient> <base64 signature>".
+	notePrefixStaged   = "staged-by "
+	notePrefixApproved = "approved-by "
+)
+
+// SetProtected makes pushes go to a staging branch instead of the main
+// branch if n is not nil. The main branch is only fast-forwarded once enough
+// other recipients approved the staged commit with ApproveStaged. n signs the
+// staged commits.
+func (g *Git) SetProtected(n backend.Notary) {
+	g.notary = n
+}
+
+// Staged returns the commit staged by the last push or an empty string if
+// nothing was staged.
+func (g *Git) Staged() string {
+	return g.staged
+}
+
+// stagingBranch returns the staging branch of the recipient on this device.
+// Branches of different recipients never overwrite each other.
+func stagingBranch(recipient string) string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "unknown"
+	}
+
+	return stagingPrefix + refSafe(recipient) + "/" + refSafe(host)
+}
+
+func refSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
+
+// pushStaging pushes HEAD to the staging branch of this device. It must be
+// called after pulling the main branch, so the staged commit can be
+// fast-forwarded.
+func (g *Git) pushStaging(ctx context.Context, proxyArgs []string, remote, branch string) error {
+	tracking := "refs/remotes/" + remote + "/" + branch
+	if g.hasRef(ctx, tracking) && g.isAncestor(ctx, "HEAD", tracking) {
+		debug.Log("nothing to stage, %s has all commits", tracking)
+
+		return nil
+	}
+
+	if err := g.fetchStaging(ctx, proxyArgs, remote, branch); err != nil {
+		return err
+	}
+
+	head, err := g.Head(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	id, err := g.signNote(ctx, g.notary, notePrefixStaged, head)
+	if err != nil {
+		return err
+	}
+
+	sb := stagingBranch(id)
+	debug.Log("protected store, staging %s on %s/%s", head, remote, sb)
+
+	if err := g.networkCmd(ctx, "gitPushStaging", append(proxyArgs, "push", "--force", remote, head+":refs/heads/"+sb, approvalsRef)...); err != nil {
+		return err
+	}
+
+	g.staged = head
+
+	return nil
+}
+
+// fetchStaging fetches the main and staging branches and the approvals.
+func (g *Git) fetchStaging(ctx context.Context, proxyArgs []string, remote, branch string) error {
+	rb := "refs/remotes/" + remote + "/"
+	refspecs := []string{
+		"+refs/heads/" + branch + ":" + rb + branch,
+		"+refs/heads/" + stagingPrefix + "*:" + rb + stagingPrefix + "*",
+		// a pattern, so fetching doesn't fail before the first approval.
+		"+refs/notes/gopass-approval*:refs/notes/gopass-approval*",
+	}
+
+	return g.networkCmd(ctx, "gitFetchStaging", append(proxyArgs, append([]string{"fetch", "--prune", remote}, refspecs...)...)...)
+}
+
+// ListStaged returns the pushes waiting for approval, oldest first. n
+// verifies who staged and approved them.
+func (g *Git) ListStaged(ctx context.Context, remote string, n backend.Notary) ([]backend.Staged, error) {
+	if !g.IsInitialized() {
+		return nil, store.ErrGitNotInit
+	}
+
+	remote, branch, proxyArgs, stop, err := g.remoteArgs(ctx, remote, "")
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	if err := g.fetchStaging(ctx, proxyArgs, remote, branch); err != nil {
+		return nil, err
+	}
+
+	return g.listStaged(ctx, n, remote, branch)
+}
+
+func (g *Git) listStaged(ctx context.Context, n backend.Notary, remote, branch string) ([]backend.Staged, error) {
+	rb := "refs/remotes/" + remote + "/"
+	stdout, stderr, err := g.captureCmd(ctx, "gitListStaged", "for-each-ref", "--sort=committerdate", "--format=%(refname)%09%(objectname)%09%(committerdate:unix)%09%(contents:subject)", rb+stagingPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staging branches: %w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	var res []backend.Staged
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		p := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(p) < 4 {
+			continue
+		}
+
+		st := backend.Staged{
+			ID:      p[1][:12],
+			Commit:  p[1],
+			Branch:  strings.TrimPrefix(p[0], rb),
+			Subject: p[3],
+		}
+
+		if ts, err := strconv.ParseInt(p[2], 10, 64); err == nil {
+			st.Date = time.Unix(ts, 0)
+		}
+
+		st.Commits = g.countCommits(ctx, rb+branch+".."+st.Commit)
+		if st.Commits < 1 {
+			debug.Log("%s was already merged", st.Branch)
+
+			continue
+		}
+
+		st.StagedBy, st.Approvals = g.readNotes(ctx, n, st.Commit)
+		res = append(res, st)
+	}
+
+	return res, nil
+}
+
+// ApproveStaged records the approval of the staged push with the given ID
+// or commit hash, signed by n. Once it has the required number of approvals
+// the main branch is fast-forwarded to it. It returns the push and whether it
+// was merged.
+func (g *Git) ApproveStaged(ctx context.Context, remote, id string, required int, n backend.Notary) (*backend.Staged, bool, error) {
+	if !g.IsInitialized() {
+		return nil, false, store.ErrGitNotInit
+	}
+
+	remote, branch, proxyArgs, stop, err := g.remoteArgs(ctx, remote, "")
+	if err != nil {
+		return nil, false, err
+	}
+	defer stop()
+
+	if err := g.fetchStaging(ctx, proxyArgs, remote, branch); err != nil {
+		return nil, false, err
+	}
+
+	sts, err := g.listStaged(ctx, n, remote, branch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var st *backend.Staged
+	for i := range sts {
+		if sts[i].ID == id || (len(id) >= 7 && strings.HasPrefix(sts[i].Commit, id)) {
+			st = &sts[i]
+
+			break
+		}
+	}
+
+	if st == nil {
+		return nil, false, fmt.Errorf("%s: %w", id, store.ErrNoStaged)
+	}
+
+	if st.StagedBy == "" {
+		return st, false, store.ErrStagedUnsigned
+	}
+
+	me, sig, err := n.Sign(ctx, noteMessage(notePrefixApproved, st.Commit))
+	if err != nil {
+		return st, false, fmt.Errorf("failed to sign the approval: %w", err)
+	}
+
+	if me == st.StagedBy {
+		return st, false, store.ErrSelfApproval
+	}
+
+	if !contains(st.Approvals, me) {
+		if err := g.addNote(ctx, st.Commit, noteLine(notePrefixApproved, me, sig)); err != nil {
+			return st, false, err
+		}
+
+		if err := g.networkCmd(ctx, "gitPushApproval", append(proxyArgs, "push", remote, approvalsRef)...); err != nil {
+			return st, false, fmt.Errorf("failed to push approval: %w", err)
+		}
+
+		st.Approvals = append(st.Approvals, me)
+	}
+
+	if len(st.Approvals) < required {
+		return st, false, nil
+	}
+
+	if !g.isAncestor(ctx, "refs/remotes/"+remote+"/"+branch, st.Commit) {
+		return st, false, store.ErrStagedOutdated
+	}
+
+	// fast-forward the main branch and remove the staging branch in one go.
+	if err := g.networkCmd(ctx, "gitPushApproved", append(proxyArgs, "push", "--atomic", remote, st.Commit+":refs/heads/"+branch, ":refs/heads/"+st.Branch)...); err != nil {
+		return st, false, fmt.Errorf("failed to fast-forward %s: %w", branch, err)
+	}
+
+	if g.isAncestor(ctx, "HEAD", st.Commit) {
+		if err := g.Cmd(ctx, "gitMergeApproved", "merge", "--ff-only", st.Commit); err != nil {
+			debug.Log("failed to fast-forward the local branch: %s", err)
+		}
+	}
+
+	return st, true, nil
+}
+
+func (g *Git) addNote(ctx context.Context, rev, line string) error {
+	if err := g.Cmd(ctx, "gitAddNote", "notes", "--ref="+approvalsRef, "append", "-m", line, rev); err != nil {
+		return fmt.Errorf("failed to record %q: %w", line, err)
+	}
+
+	return nil
+}
+
+// signNote adds a note signed by n to rev and returns the signing recipient.
+func (g *Git) signNote(ctx context.Context, n backend.Notary, prefix, rev string) (string, error) {
+	id, sig, err := n.Sign(ctx, noteMessage(prefix, rev))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s: %w", rev, err)
+	}
+
+	return id, g.addNote(ctx, rev, noteLine(prefix, id, sig))
+}
+
+// noteMessage is the signed message. It binds the signature to the commit and
+// the kind of note.
+func noteMessage(prefix, rev string) []byte {
+	return []byte("gopass " + prefix + rev + "\n")
+}
+
+func noteLine(prefix, id string, sig []byte) string {
+	return prefix + id + " " + base64.StdEncoding.EncodeToString(sig)
+}
+
+// readNotes returns the recipient that staged a commit and those that
+// approved it. Notes without a valid signature by a recipient are ignored,
+// so are approvals by the recipient that staged the commit.
+func (g *Git) readNotes(ctx context.Context, n backend.Notary, rev string) (string, []string) {
+	stdout, _, err := g.captureCmd(ctx, "gitShowNote", "notes", "--ref="+approvalsRef, "show", rev)
+	if err != nil {
+		return "", nil
+	}
+
+	var stagedBy string
+	var approvals []string
+
+	for _, line := range strings.Split(string(stdout), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, notePrefixStaged):
+			if signer := verifyNote(ctx, n, notePrefixStaged, rev, line); signer != "" && stagedBy == "" {
+				stagedBy = signer
+			}
+		case strings.HasPrefix(line, notePrefixApproved):
+			if signer := verifyNote(ctx, n, notePrefixApproved, rev, line); signer != "" && !contains(approvals, signer) {
+				approvals = append(approvals, signer)
+			}
+		}
+	}
+
+	res := make([]string, 0, len(approvals))
+	for _, a := range approvals {
+		if a != stagedBy {
+			res = append(res, a)
+		}
+	}
+
+	return stagedBy, res
+}
+
+// verifyNote returns the recipient that signed the note or an empty string.
+func verifyNote(ctx context.Context, n backend.Notary, prefix, rev, line string) string {
+	rest := strings.TrimPrefix(line, prefix)
+
+	i := strings.LastIndexByte(rest, ' ')
+	if i < 1 {
+		return ""
+	}
+
+	claimed := rest[:i]
+	sig, err := base64.StdEncoding.DecodeString(rest[i+1:])
+	if err != nil {
+		return ""
+	}
+
+	signer, err := n.Verify(ctx, noteMessage(prefix, rev), sig)
+	if err != nil || signer != claimed {
+		debug.Log("ignoring note %q on %s without a valid signature: %v", prefix+claimed, rev, err)
+
+		return ""
+	}
+
+	return signer
+}
+
+func (g *Git) isAncestor(ctx context.Context, ancestor, rev string) bool {
+	_, _, err := g.captureCmd(ctx, "gitIsAncestor", "merge-base", "--is-ancestor", ancestor, rev)
+
+	return err == nil
+}
+
+func (g *Git) countCommits(ctx context.Context, revRange string) int {
+	stdout, _, err := g.captureCmd(ctx, "gitCountCommits", "rev-list", "--count", revRange)
+	if err != nil {
+		return 0
+	}
+
+	n, _ := strconv.Atoi(strings.TrimSpace(string(stdout)))
+
+	return n
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,173 @@
+package gitfs
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNotary signs as id and accepts signatures by the recipients.
+type testNotary struct {
+	id         string
+	recipients []string
+}
+
+func (n testNotary) Sign(_ context.Context, msg []byte) (string, []byte, error) {
+	return n.id, append([]byte(n.id+":"), msg...), nil
+}
+
+func (n testNotary) Verify(_ context.Context, msg, sig []byte) (string, error) {
+	for _, r := range n.recipients {
+		if bytes.Equal(sig, append([]byte(r+":"), msg...)) {
+			return r, nil
+		}
+	}
+
+	return "", backend.ErrBadSignature
+}
+
+func TestStaging(t *testing.T) { //nolint:paralleltest
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Dead Beef")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dead.beef@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "Dead Beef")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dead.beef@example.org")
+
+	ctx := context.Background()
+
+	remote := filepath.Join(td, "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+
+	clone := func(name, email string) *Git {
+		t.Helper()
+
+		g, err := Clone(ctx, remote, filepath.Join(td, name), "Dead Beef", email)
+		require.NoError(t, err)
+
+		return g
+	}
+
+	commit := func(g *Git, name, content string) {
+		t.Helper()
+
+		require.NoError(t, g.Set(ctx, name, []byte(content)))
+		require.NoError(t, g.Add(ctx, name))
+		require.NoError(t, g.Commit(ctx, "add "+name))
+	}
+
+	alice := clone("alice", "alice@example.org")
+	commit(alice, "foo.gpg", "foo")
+	require.NoError(t, alice.Push(ctx, "origin", ""))
+
+	recipients := []string{"alice", "bob"}
+	an := testNotary{id: "alice", recipients: recipients}
+	bn := testNotary{id: "bob", recipients: recipients}
+
+	bob := clone("bob", "bob@example.org")
+	alice.SetProtected(an)
+
+	// nothing to stage.
+	require.NoError(t, alice.Push(ctx, "origin", ""))
+	assert.Equal(t, "", alice.Staged())
+
+	commit(alice, "bar.gpg", "bar")
+	require.NoError(t, alice.Push(ctx, "origin", ""))
+	head, err := alice.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, head, alice.Staged())
+
+	// the change is staged, not pushed.
+	require.NoError(t, bob.Pull(ctx, "origin", ""))
+	assert.False(t, bob.Exists(ctx, "bar.gpg"))
+
+	sts, err := bob.ListStaged(ctx, "origin", bn)
+	require.NoError(t, err)
+	require.Len(t, sts, 1)
+	st := sts[0]
+	assert.Equal(t, 1, st.Commits)
+	assert.Equal(t, "add bar.gpg", st.Subject)
+	assert.Equal(t, "alice", st.StagedBy)
+	assert.Contains(t, st.Branch, "gopass-staging/alice/")
+	assert.Empty(t, st.Approvals)
+
+	t.Run("self approval", func(t *testing.T) {
+		_, _, err := alice.ApproveStaged(ctx, "origin", st.ID, 1, an)
+		assert.ErrorIs(t, err, store.ErrSelfApproval)
+
+		// another device with the same key doesn't count either.
+		_, _, err = bob.ApproveStaged(ctx, "origin", st.ID, 1, testNotary{id: "alice", recipients: recipients})
+		assert.ErrorIs(t, err, store.ErrSelfApproval)
+	})
+
+	t.Run("unknown push", func(t *testing.T) {
+		_, _, err := bob.ApproveStaged(ctx, "origin", "000000000000", 1, bn)
+		assert.ErrorIs(t, err, store.ErrNoStaged)
+	})
+
+	t.Run("forged approval", func(t *testing.T) {
+		require.NoError(t, bob.addNote(ctx, st.Commit, noteLine(notePrefixApproved, "mallory", []byte("mallory:forged"))))
+		require.NoError(t, bob.addNote(ctx, st.Commit, noteLine(notePrefixApproved, "bob", []byte("forged"))))
+
+		_, approvals := bob.readNotes(ctx, bn, st.Commit)
+		assert.Empty(t, approvals)
+
+		// only the recipients of the store count.
+		stager, _ := bob.readNotes(ctx, testNotary{recipients: []string{"bob"}}, st.Commit)
+		assert.Equal(t, "", stager)
+	})
+
+	t.Run("not enough approvals", func(t *testing.T) {
+		got, merged, err := bob.ApproveStaged(ctx, "origin", st.ID, 2, bn)
+		require.NoError(t, err)
+		assert.False(t, merged)
+		assert.Equal(t, []string{"bob"}, got.Approvals)
+
+		// approving twice doesn't count.
+		got, merged, err = bob.ApproveStaged(ctx, "origin", st.ID, 2, bn)
+		require.NoError(t, err)
+		assert.False(t, merged)
+		assert.Len(t, got.Approvals, 1)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		got, merged, err := bob.ApproveStaged(ctx, "origin", st.ID, 1, bn)
+		require.NoError(t, err)
+		assert.True(t, merged)
+		assert.Len(t, got.Approvals, 1)
+		assert.True(t, bob.Exists(ctx, "bar.gpg"))
+
+		sts, err := bob.ListStaged(ctx, "origin", bn)
+		require.NoError(t, err)
+		assert.Empty(t, sts)
+
+		// nothing left to stage after pulling the approved change.
+		require.NoError(t, alice.Push(ctx, "origin", ""))
+		assert.Equal(t, "", alice.Staged())
+		sts, err = alice.ListStaged(ctx, "origin", an)
+		require.NoError(t, err)
+		assert.Empty(t, sts)
+	})
+
+	t.Run("outdated", func(t *testing.T) {
+		commit(alice, "baz.gpg", "baz")
+		require.NoError(t, alice.Push(ctx, "origin", ""))
+
+		// the main branch moves on before the push is approved.
+		commit(bob, "qux.gpg", "qux")
+		require.NoError(t, bob.Push(ctx, "origin", ""))
+
+		sts, err := bob.ListStaged(ctx, "origin", bn)
+		require.NoError(t, err)
+		require.Len(t, sts, 1)
+
+		_, _, err = bob.ApproveStaged(ctx, "origin", sts[0].ID, 1, bn)
+		assert.ErrorIs(t, err, store.ErrStagedOutdated)
+	})
+}
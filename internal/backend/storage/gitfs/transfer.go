@@ -0,0 +1,147 @@
+package gitfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// lowBandwidthArgs tune git for slow links: the wire protocol v2 only
+// advertises the refs we ask for and the maximum compression trades CPU for
+// smaller packs.
+var lowBandwidthArgs = []string{
+	"-c", "protocol.version=2",
+	"-c", "core.compression=9",
+	"-c", "pack.compression=9",
+}
+
+func (g *Git) remoteURL(ctx context.Context, remote string) (string, error) {
+	urlKey := "remote." + remote + ".url"
+	remoteURL, err := g.ConfigGet(ctx, urlKey)
+	if err != nil || remoteURL == "" {
+		debug.Log("No value for %q found in config. Keys: %+v", urlKey, g.cfg.Keys())
+
+		return "", store.ErrGitNoRemote
+	}
+
+	return remoteURL, nil
+}
+
+// connectArgs returns the git options for talking to the remote. The returned
+// function must be called once the network operations are done.
+func (g *Git) connectArgs(ctx context.Context, remote, remoteURL string) ([]string, func(), error) {
+	stop := func() {}
+
+	// git can't limit the bandwidth on its own, so the connection is routed
+	// through a local throttling proxy. Local remotes are never throttled.
+	bw := network.MaxBandwidth(ctx)
+	if bw > 0 && network.RemoteHost(remoteURL) != "" {
+		t, err := network.StartThrottle(ctx, remoteURL, bw)
+		if err != nil {
+			return nil, stop, fmt.Errorf("can not limit bandwidth to remote %s: %w", remote, err)
+		}
+
+		ctx = network.WithThrottle(ctx, t)
+		stop = func() {
+			debug.Log("transferred %d bytes with remote %s", t.Transferred(), remote)
+			_ = t.Close()
+		}
+	}
+
+	// route onion remotes (and everything else if a proxy is configured)
+	// through the proxy.
+	args, err := network.GitRemoteArgs(ctx, remoteURL, g.sshCommand(ctx))
+	if err != nil {
+		stop()
+
+		return nil, func() {}, fmt.Errorf("can not reach remote %s: %w", remote, err)
+	}
+
+	if bw > 0 {
+		args = append(args, lowBandwidthArgs...)
+	}
+
+	return args, stop, nil
+}
+
+// EstimateTransfer estimates the size of the next sync with the remote. It
+// only asks the remote for its head, no objects are transferred.
+func (g *Git) EstimateTransfer(ctx context.Context, remote, branch string) (*backend.TransferEstimate, error) {
+	if !g.IsInitialized() {
+		return nil, store.ErrGitNotInit
+	}
+
+	remote, branch, proxyArgs, stop, err := g.remoteArgs(ctx, remote, branch)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	stdout, err := g.networkOutput(ctx, "gitLsRemote", append(proxyArgs, "ls-remote", "--heads", remote, "refs/heads/"+branch)...)
+	if err != nil {
+		return nil, err
+	}
+
+	est := &backend.TransferEstimate{}
+	args := []string{branch}
+
+	head, _, _ := strings.Cut(string(stdout), "\t")
+	head = strings.TrimSpace(head)
+	tracking := "refs/remotes/" + remote + "/" + branch
+
+	switch {
+	case head == "":
+		// the branch doesn't exist on the remote, yet. Everything is pushed.
+	case g.hasRef(ctx, head+"^{commit}"):
+		args = append(args, "^"+head)
+	default:
+		est.Incoming = true
+		// we don't know the new remote head, so we can only exclude what
+		// we knew about the remote after the last sync.
+		if g.hasRef(ctx, tracking) {
+			args = append(args, "^"+tracking)
+		}
+	}
+
+	est.Objects, est.Upload, err = g.objectsSize(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Log("estimate for %s/%s (remote head %q): %+v", remote, branch, head, est)
+
+	return est, nil
+}
+
+// objectsSize returns the number and the size on disk of the objects
+// reachable from the given revisions.
+func (g *Git) objectsSize(ctx context.Context, revs ...string) (int, int64, error) {
+	stdout, stderr, err := g.captureCmd(ctx, "gitRevListObjects", append([]string{"rev-list", "--objects"}, revs...)...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list objects: %w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	n := bytes.Count(stdout, []byte("\n"))
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	stdout, stderr, err = g.captureCmd(ctx, "gitRevListSize", append([]string{"rev-list", "--objects", "--disk-usage"}, revs...)...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to estimate size: %w: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(string(stdout)), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to estimate size: %w", err)
+	}
+
+	return n, size, nil
+}
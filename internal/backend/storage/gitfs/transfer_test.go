@@ -0,0 +1,71 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTransfer(t *testing.T) { //nolint:paralleltest
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Dead Beef")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dead.beef@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "Dead Beef")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dead.beef@example.org")
+
+	ctx := context.Background()
+
+	remote := filepath.Join(td, "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+
+	dir := filepath.Join(td, "local")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	g, err := Init(ctx, dir, "Dead Beef", "dead.beef@example.org")
+	require.NoError(t, err)
+	require.NoError(t, g.AddRemote(ctx, "origin", remote))
+
+	commit := func(g *Git, name, content string) {
+		t.Helper()
+
+		require.NoError(t, g.Set(ctx, name, []byte(content)))
+		require.NoError(t, g.Add(ctx, name))
+		require.NoError(t, g.Commit(ctx, "add "+name))
+	}
+
+	commit(g, "foo.gpg", "foo")
+
+	// the remote is empty, everything is uploaded.
+	est, err := g.EstimateTransfer(ctx, "", "")
+	require.NoError(t, err)
+	assert.False(t, est.Incoming)
+	assert.Greater(t, est.Objects, 0)
+	assert.Greater(t, est.Upload, int64(0))
+
+	branch := g.defaultBranch(ctx)
+	require.NoError(t, g.Cmd(ctx, "gitPush", "push", "origin", branch))
+
+	est, err = g.EstimateTransfer(ctx, "", "")
+	require.NoError(t, err)
+	assert.False(t, est.Incoming)
+	assert.Equal(t, 0, est.Objects)
+
+	// a change pushed by someone else has to be fetched.
+	dir2 := filepath.Join(td, "other")
+	require.NoError(t, exec.Command("git", "clone", remote, dir2).Run())
+	g2, err := New(dir2)
+	require.NoError(t, err)
+	commit(g2, "bar.gpg", "bar")
+	require.NoError(t, g2.Cmd(ctx, "gitPush", "push", "origin", branch))
+
+	commit(g, "baz.gpg", "baz")
+
+	est, err = g.EstimateTransfer(ctx, "", "")
+	require.NoError(t, err)
+	assert.True(t, est.Incoming)
+	assert.Equal(t, 3, est.Objects) // commit, tree and blob.
+}
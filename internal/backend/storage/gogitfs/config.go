@@ -0,0 +1,156 @@
+package gogitfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+)
+
+const (
+	fileMode = 0o600
+)
+
+// InitConfig initialized and preparse the git config.
+func (g *Git) InitConfig(ctx context.Context, userName, userEmail string) error {
+	// set commit identity.
+	if userName != "" {
+		if err := g.ConfigSet(ctx, "user.name", userName); err != nil {
+			return fmt.Errorf("failed to set git config user.name: %w", err)
+		}
+	} else {
+		out.Printf(ctx, "Git Username not set")
+	}
+	if userEmail != "" && strings.Contains(userEmail, "@") {
+		if err := g.ConfigSet(ctx, "user.email", userEmail); err != nil {
+			return fmt.Errorf("failed to set git config user.email: %w", err)
+		}
+	} else {
+		out.Printf(ctx, "Git Email not set")
+	}
+
+	// setup for proper diffs, in case the git binary is used later.
+	if err := g.ConfigSet(ctx, "diff.gpg.binary", "true"); err != nil {
+		out.Errorf(ctx, "Error while initializing git: %s", err)
+	}
+	if err := g.ConfigSet(ctx, "diff.gpg.textconv", "gpg --no-tty --decrypt"); err != nil {
+		out.Errorf(ctx, "Error while initializing git: %s", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(g.fs.Path(), ".gitattributes"), []byte("*.gpg diff=gpg\n"), fileMode); err != nil {
+		return fmt.Errorf("failed to initialize git: %w", err)
+	}
+	if err := g.Add(ctx, g.fs.Path()+"/.gitattributes"); err != nil {
+		out.Warningf(ctx, "Failed to add .gitattributes to git")
+	}
+	if err := g.Commit(ctx, "Configure git repository for gpg file diff."); err != nil {
+		out.Warningf(ctx, "Failed to commit .gitattributes to git")
+	}
+
+	return nil
+}
+
+// ConfigSet sets a local config value.
+func (g *Git) ConfigSet(ctx context.Context, key, value string) error {
+	section, subsection, option, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return err
+	}
+
+	if subsection != "" {
+		cfg.Raw.Section(section).Subsection(subsection).SetOption(option, value)
+	} else {
+		cfg.Raw.Section(section).SetOption(option, value)
+	}
+
+	// the typed config is marshaled on top of the raw config when it is
+	// saved, so we need to parse the raw config again to keep both in sync.
+	buf := &bytes.Buffer{}
+	if err := config.NewEncoder(buf).Encode(cfg.Raw); err != nil {
+		return err
+	}
+
+	ncfg := gitconfig.NewConfig()
+	if err := ncfg.Unmarshal(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return g.repo.SetConfig(ncfg)
+}
+
+// ConfigGet returns a given config value.
+func (g *Git) ConfigGet(ctx context.Context, key string) (string, error) {
+	if !g.IsInitialized() {
+		return "", store.ErrGitNotInit
+	}
+
+	section, subsection, option, err := splitKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	if subsection != "" {
+		return cfg.Raw.Section(section).Subsection(subsection).Option(option), nil
+	}
+
+	return cfg.Raw.Section(section).Option(option), nil
+}
+
+// ConfigList returns all git config settings.
+func (g *Git) ConfigList(ctx context.Context) (map[string]string, error) {
+	if !g.IsInitialized() {
+		return nil, store.ErrGitNotInit
+	}
+
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	kv := make(map[string]string, 23)
+	for _, s := range cfg.Raw.Sections {
+		for _, o := range s.Options {
+			kv[s.Name+"."+o.Key] = o.Value
+		}
+		for _, ss := range s.Subsections {
+			for _, o := range ss.Options {
+				kv[s.Name+"."+ss.Name+"."+o.Key] = o.Value
+			}
+		}
+	}
+
+	return kv, nil
+}
+
+// splitKey splits a git config key, e.g. remote.origin.url, into its
+// section, subsection and option.
+func splitKey(key string) (string, string, string, error) {
+	i := strings.Index(key, ".")
+	j := strings.LastIndex(key, ".")
+	if i < 1 || j == len(key)-1 {
+		return "", "", "", fmt.Errorf("invalid git config key %q", key)
+	}
+
+	if i == j {
+		return key[:i], "", key[j+1:], nil
+	}
+
+	return key[:i], key[i+1 : j], key[j+1:], nil
+}
@@ -0,0 +1,651 @@
+// Package gogitfs implements a pure Go git based RCS backend. It doesn't
+// need a git binary and is used on systems without one. It only supports
+// the operations gopass needs, i.e. exotic features like merges of diverged
+// branches still require the git binary.
+package gogitfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/deadline"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	gpdebug "github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// ErrDiverged is returned if the local and the remote branch have diverged.
+// Merging them requires the git binary.
+var ErrDiverged = errors.New("local and remote branch have diverged, please merge them with the git binary")
+
+func init() {
+	// go-git uses the git binary for file:// remotes by default.
+	client.InstallProtocol("file", server.NewClient(server.DefaultLoader))
+}
+
+// Git is a pure Go git backend.
+type Git struct {
+	fs   *fs.Store
+	repo *git.Repository
+}
+
+// New opens an existing git repo.
+func New(path string) (*Git, error) {
+	path = fsutil.ExpandHomedir(path)
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repo at %s: %w", path, err)
+	}
+
+	return &Git{
+		fs:   fs.New(path),
+		repo: repo,
+	}, nil
+}
+
+// Clone clones an existing git repo and returns a new go-git backend
+// configured for this clone repo.
+func Clone(ctx context.Context, repo, path, userName, userEmail string) (*Git, error) {
+	opts := &git.CloneOptions{
+		URL: repo,
+	}
+	if err := setAuth(ctx, repo, &opts.Auth, &opts.ProxyOptions); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := deadline.With(ctx, deadline.Network)
+	defer cancel()
+
+	r, err := git.PlainCloneContext(ctx, path, false, opts)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, fmt.Errorf("failed to clone %s: %w", repo, err)
+	}
+
+	if r == nil {
+		// cloning an empty repo leaves us without a repo, set it up manually.
+		if r, err = git.PlainInit(path, false); err != nil {
+			return nil, fmt.Errorf("failed to initialize git: %w", err)
+		}
+		if _, err := r.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{repo}}); err != nil {
+			return nil, fmt.Errorf("failed to add remote: %w", err)
+		}
+	}
+
+	g := &Git{
+		fs:   fs.New(path),
+		repo: r,
+	}
+
+	// initialize the local git config.
+	if err := g.InitConfig(ctx, userName, userEmail); err != nil {
+		return g, fmt.Errorf("failed to configure git: %w", err)
+	}
+	out.Printf(ctx, "git configured at %s", g.fs.Path())
+
+	return g, nil
+}
+
+// Init initializes this store's git repo.
+func Init(ctx context.Context, path, userName, userEmail string) (*Git, error) {
+	path = fsutil.ExpandHomedir(path)
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		repo, err = git.PlainInit(path, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize git: %w", err)
+		}
+		out.Printf(ctx, "git initialized at %s", path)
+	}
+
+	g := &Git{
+		fs:   fs.New(path),
+		repo: repo,
+	}
+
+	if !ctxutil.IsGitInit(ctx) {
+		return g, nil
+	}
+
+	// initialize the local git config.
+	if err := g.InitConfig(ctx, userName, userEmail); err != nil {
+		return g, fmt.Errorf("failed to configure git: %w", err)
+	}
+	out.Printf(ctx, "git configured at %s", g.fs.Path())
+
+	// add current content of the store.
+	if err := g.Add(ctx, g.fs.Path()); err != nil {
+		return g, fmt.Errorf("failed to add %q to git: %w", g.fs.Path(), err)
+	}
+
+	// commit if there is something to commit.
+	if !g.HasStagedChanges(ctx) {
+		gpdebug.Log("No staged changes")
+
+		return g, nil
+	}
+
+	if err := g.Commit(ctx, "Add current content of password store"); err != nil {
+		return g, fmt.Errorf("failed to commit changes to git: %w", err)
+	}
+
+	return g, nil
+}
+
+// Name returns gogitfs.
+func (g *Git) Name() string {
+	return name
+}
+
+// Version returns the version of the go-git library.
+func (g *Git) Version(ctx context.Context) semver.Version {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return semver.Version{}
+	}
+
+	for _, dep := range bi.Deps {
+		if dep.Path != "github.com/go-git/go-git/v5" {
+			continue
+		}
+
+		if sv, err := semver.ParseTolerant(dep.Version); err == nil {
+			return sv
+		}
+	}
+
+	return semver.Version{}
+}
+
+// IsInitialized returns true if this stores has an (probably) initialized .git folder.
+func (g *Git) IsInitialized() bool {
+	return g.repo != nil && fsutil.IsFile(filepath.Join(g.fs.Path(), ".git", "config"))
+}
+
+// Add adds the listed files to the git index.
+func (g *Git) Add(ctx context.Context, files ...string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		f = strings.TrimPrefix(f, g.fs.Path())
+		f = strings.TrimPrefix(filepath.ToSlash(f), "/")
+		if f == "" {
+			f = "."
+		}
+		paths = append(paths, f)
+
+		// adding a removed file stages the deletion. Removed directories are
+		// handled below.
+		if _, err := wt.Add(f); err != nil {
+			if _, serr := os.Stat(filepath.Join(g.fs.Path(), f)); os.IsNotExist(serr) {
+				_, _ = wt.Remove(f)
+
+				continue
+			}
+
+			return fmt.Errorf("failed to add %s: %w", f, err)
+		}
+	}
+
+	// deletions below a directory are not picked up by Add.
+	st, err := wt.Status()
+	if err != nil {
+		return err
+	}
+	for f, fs := range st {
+		if fs.Worktree == git.Deleted && inPaths(f, paths) {
+			if _, err := wt.Remove(f); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", f, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// inPaths returns true if f is one of paths or below one of them.
+func inPaths(f string, paths []string) bool {
+	for _, p := range paths {
+		if p == "." || f == p || strings.HasPrefix(f, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasStagedChanges returns true if there are any staged changes which can be committed.
+func (g *Git) HasStagedChanges(ctx context.Context) bool {
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return false
+	}
+
+	st, err := wt.Status()
+	if err != nil {
+		return false
+	}
+
+	for _, fs := range st {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListUntrackedFiles lists untracked files.
+func (g *Git) ListUntrackedFiles(ctx context.Context) []string {
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return []string{fmt.Sprintf("ERROR: %s", err)}
+	}
+
+	st, err := wt.Status()
+	if err != nil {
+		return []string{fmt.Sprintf("ERROR: %s", err)}
+	}
+
+	uf := []string{}
+	for f, fs := range st {
+		if fs.Worktree == git.Untracked {
+			uf = append(uf, f)
+		}
+	}
+	sort.Strings(uf)
+
+	return uf
+}
+
+// Commit creates a new git commit with the given commit message.
+func (g *Git) Commit(ctx context.Context, msg string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	if !g.HasStagedChanges(ctx) {
+		return store.ErrGitNothingToCommit
+	}
+
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	sig := g.signature(ctx)
+	_, err = wt.Commit(msg, &git.CommitOptions{
+		Author:    sig,
+		Committer: sig,
+	})
+
+	return err
+}
+
+// signature returns the commit identity from the git config.
+func (g *Git) signature(ctx context.Context) *object.Signature {
+	sig := &object.Signature{
+		When: ctxutil.GetCommitTimestamp(ctx).UTC(),
+	}
+
+	if cfg, err := g.repo.ConfigScoped(gitconfig.GlobalScope); err == nil {
+		sig.Name = cfg.User.Name
+		sig.Email = cfg.User.Email
+	}
+
+	if sig.Name == "" {
+		sig.Name = termio.DetectName(ctx, nil)
+	}
+
+	if sig.Email == "" {
+		sig.Email = termio.DetectEmail(ctx, nil)
+	}
+
+	return sig
+}
+
+// Head returns the hash of the current commit.
+func (g *Git) Head(context.Context) (string, error) {
+	ref, err := g.repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return ref.Hash().String(), nil
+}
+
+func (g *Git) defaultBranch(ctx context.Context) string {
+	ref, err := g.repo.Head()
+	if err != nil {
+		// an empty repo has no resolvable HEAD, yet.
+		if ref, err := g.repo.Storer.Reference(plumbing.HEAD); err == nil && ref.Type() == plumbing.SymbolicReference {
+			return ref.Target().Short()
+		}
+
+		// see https://github.com/github/renaming.
+		return "main"
+	}
+
+	return ref.Name().Short()
+}
+
+func (g *Git) defaultRemote(ctx context.Context, branch string) string {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return "origin"
+	}
+
+	if b, found := cfg.Branches[branch]; found && b.Remote != "" {
+		if _, found := cfg.Remotes[b.Remote]; found {
+			return b.Remote
+		}
+	}
+
+	return "origin"
+}
+
+// PushPull pushes the repo to it's origin.
+// optional arguments: remote and branch.
+func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
+	if network.Offline(ctx) {
+		gpdebug.Log("Skipping network ops. Offline=true")
+
+		return nil
+	}
+	if !g.IsInitialized() {
+		gpdebug.Log("Git in %s is not initialized. Can not push/pull", g.Path())
+
+		return store.ErrGitNotInit
+	}
+
+	ctx, cancel := deadline.With(ctx, deadline.Network)
+	defer cancel()
+
+	if branch == "" {
+		branch = g.defaultBranch(ctx)
+	}
+
+	if remote == "" {
+		remote = g.defaultRemote(ctx, branch)
+	}
+
+	r, err := g.repo.Remote(remote)
+	if err != nil || len(r.Config().URLs) < 1 {
+		gpdebug.Log("Remote %q not found: %s", remote, err)
+
+		return store.ErrGitNoRemote
+	}
+	remoteURL := r.Config().URLs[0]
+
+	ref := plumbing.NewBranchReferenceName(branch)
+
+	if err := g.pull(ctx, remote, remoteURL, ref); err != nil {
+		if op == "pull" {
+			return err
+		}
+		out.Warningf(ctx, "Failed to pull before git push: %s", err)
+	}
+
+	if op == "pull" {
+		return nil
+	}
+
+	if uf := g.ListUntrackedFiles(ctx); len(uf) > 0 {
+		out.Warningf(ctx, "Found untracked files: %+v", uf)
+	}
+
+	opts := &git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(ref + ":" + ref)},
+	}
+	if err := setAuth(ctx, remoteURL, &opts.Auth, &opts.ProxyOptions); err != nil {
+		return fmt.Errorf("can not reach remote %s: %w", remote, err)
+	}
+
+	return network.Retry(ctx, "gitPush", func() error {
+		return classify(g.repo.PushContext(ctx, opts))
+	})
+}
+
+func (g *Git) pull(ctx context.Context, remote, remoteURL string, ref plumbing.ReferenceName) error {
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	opts := &git.PullOptions{
+		RemoteName:    remote,
+		ReferenceName: ref,
+		SingleBranch:  true,
+	}
+	if err := setAuth(ctx, remoteURL, &opts.Auth, &opts.ProxyOptions); err != nil {
+		return fmt.Errorf("can not reach remote %s: %w", remote, err)
+	}
+
+	err = network.Retry(ctx, "gitPull", func() error {
+		err := wt.PullContext(ctx, opts)
+		switch {
+		case errors.Is(err, transport.ErrEmptyRemoteRepository), errors.Is(err, plumbing.ErrReferenceNotFound):
+			// nothing to pull, yet.
+			return nil
+		case errors.Is(err, git.ErrNonFastForwardUpdate):
+			return network.Permanent(ErrDiverged)
+		}
+
+		return classify(err)
+	})
+
+	return err
+}
+
+// classify maps go-git errors to the errors expected by network.Retry.
+func classify(err error) error {
+	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+
+	if network.IsUnreachable(err) {
+		return network.Permanent(fmt.Errorf("%w: %w", network.ErrOffline, err))
+	}
+
+	if network.IsTransient(err.Error()) {
+		return err
+	}
+
+	return network.Permanent(err)
+}
+
+// setAuth sets the credentials and proxy for the given remote. HTTP(S)
+// credentials are taken from the remote URL, SSH uses the SSH agent or,
+// if there is none, an unencrypted default key.
+func setAuth(ctx context.Context, remote string, auth *transport.AuthMethod, proxy *transport.ProxyOptions) error {
+	pu, err := network.RemoteProxy(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	if pu != nil {
+		if network.IsOnion(remote) {
+			if err := network.CheckProxy(ctx, pu); err != nil {
+				return err
+			}
+		}
+		proxy.URL = pu.String()
+	}
+
+	if !network.IsSSH(remote) || os.Getenv("SSH_AUTH_SOCK") != "" {
+		return nil
+	}
+
+	ep, err := transport.NewEndpoint(remote)
+	if err != nil {
+		return err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	for _, k := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		fn := filepath.Join(home, ".ssh", k)
+		if !fsutil.IsFile(fn) {
+			continue
+		}
+
+		keys, err := ssh.NewPublicKeysFromFile(ep.User, fn, "")
+		if err != nil {
+			gpdebug.Log("can not use SSH key %s: %s", fn, err)
+
+			continue
+		}
+
+		*auth = keys
+
+		return nil
+	}
+
+	return nil
+}
+
+// Push pushes to the git remote.
+func (g *Git) Push(ctx context.Context, remote, branch string) error {
+	if network.Offline(ctx) {
+		gpdebug.Log("Skipping network ops. Offline=true")
+
+		return nil
+	}
+
+	return g.PushPull(ctx, "push", remote, branch)
+}
+
+// Pull pulls from the git remote.
+func (g *Git) Pull(ctx context.Context, remote, branch string) error {
+	if network.Offline(ctx) {
+		gpdebug.Log("Skipping network ops. Offline=true")
+
+		return nil
+	}
+
+	return g.PushPull(ctx, "pull", remote, branch)
+}
+
+// AddRemote adds a new remote.
+func (g *Git) AddRemote(ctx context.Context, remote, url string) error {
+	_, err := g.repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: remote,
+		URLs: []string{url},
+	})
+
+	return err
+}
+
+// RemoveRemote removes a remote.
+func (g *Git) RemoveRemote(ctx context.Context, remote string) error {
+	return g.repo.DeleteRemote(remote)
+}
+
+// Revisions will list all available revisions of the named entity.
+func (g *Git) Revisions(ctx context.Context, name string) ([]backend.Revision, error) {
+	iter, err := g.repo.Log(&git.LogOptions{
+		FileName: &name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	revs := []backend.Revision{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		subj, body, _ := strings.Cut(c.Message, "\n")
+		revs = append(revs, backend.Revision{
+			Hash:        c.Hash.String(),
+			AuthorName:  c.Author.Name,
+			AuthorEmail: c.Author.Email,
+			Date:        c.Author.When,
+			Subject:     strings.TrimSpace(subj),
+			Body:        strings.TrimSpace(body),
+		})
+
+		return nil
+	})
+
+	gpdebug.Log("Revisions for %s: %+v", name, revs)
+
+	return revs, err
+}
+
+// GetRevision will return the content of any revision of the named entity.
+func (g *Git) GetRevision(ctx context.Context, name, revision string) ([]byte, error) {
+	name = strings.TrimSpace(name)
+	revision = strings.TrimSpace(revision)
+
+	h, err := g.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+	}
+
+	c, err := g.repo.CommitObject(*h)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := c.File(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", name, revision, err)
+	}
+
+	content, err := f.Contents()
+
+	return []byte(content), err
+}
+
+// Status return the git status output.
+func (g *Git) Status(ctx context.Context) ([]byte, error) {
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(st.String()), nil
+}
+
+// Compact is not supported by go-git. Use the git binary to run git gc.
+func (g *Git) Compact(ctx context.Context) error {
+	return backend.ErrNotSupported
+}
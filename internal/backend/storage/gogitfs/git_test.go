@@ -0,0 +1,160 @@
+package gogitfs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGit(t *testing.T) { //nolint:paralleltest
+	td := t.TempDir()
+	t.Setenv("HOME", td)
+
+	remote := filepath.Join(td, "remote.git")
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	gitdir := filepath.Join(td, "git")
+	require.NoError(t, os.Mkdir(gitdir, 0o755))
+	gitdir2 := filepath.Join(td, "git2")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithGitInit(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	t.Run("init new repo", func(t *testing.T) {
+		g, err := Init(ctx, gitdir, "Dead Beef", "dead.beef@example.org")
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		assert.Equal(t, "gogitfs", g.Name())
+		assert.True(t, g.IsInitialized())
+
+		name, err := g.ConfigGet(ctx, "user.name")
+		require.NoError(t, err)
+		assert.Equal(t, "Dead Beef", name)
+
+		require.NoError(t, os.WriteFile(filepath.Join(gitdir, "some-file"), []byte("foobar"), 0o644))
+		assert.Equal(t, []string{"some-file"}, g.ListUntrackedFiles(ctx))
+		require.NoError(t, g.Add(ctx, "some-file"))
+		assert.True(t, g.HasStagedChanges(ctx))
+		require.NoError(t, g.Commit(ctx, "added some-file"))
+		assert.False(t, g.HasStagedChanges(ctx))
+		assert.ErrorIs(t, g.Commit(ctx, "nothing"), store.ErrGitNothingToCommit)
+
+		assert.ErrorIs(t, g.Push(ctx, "origin", ""), store.ErrGitNoRemote)
+		require.NoError(t, g.AddRemote(ctx, "origin", remote))
+		require.NoError(t, g.Push(ctx, "origin", ""))
+	})
+
+	t.Run("clone and sync", func(t *testing.T) {
+		g2, err := Clone(ctx, remote, gitdir2, "Bad Beef", "bad.beef@example.org")
+		require.NoError(t, err)
+
+		buf, err := g2.Get(ctx, "some-file")
+		require.NoError(t, err)
+		assert.Equal(t, "foobar", string(buf))
+
+		require.NoError(t, g2.Set(ctx, "some-file", []byte("barfoo")))
+		require.NoError(t, g2.Add(ctx, "some-file"))
+		require.NoError(t, g2.Commit(ctx, "updated some-file"))
+		require.NoError(t, g2.Push(ctx, "", ""))
+
+		g, err := New(gitdir)
+		require.NoError(t, err)
+		require.NoError(t, g.Pull(ctx, "", ""))
+
+		buf, err = g.Get(ctx, "some-file")
+		require.NoError(t, err)
+		assert.Equal(t, "barfoo", string(buf))
+
+		revs, err := g.Revisions(ctx, "some-file")
+		require.NoError(t, err)
+		require.Len(t, revs, 2)
+		assert.Equal(t, "updated some-file", revs[0].Subject)
+		assert.Equal(t, "bad.beef@example.org", revs[0].AuthorEmail)
+
+		old, err := g.GetRevision(ctx, "some-file", revs[1].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, "foobar", string(old))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		g, err := New(gitdir)
+		require.NoError(t, err)
+
+		require.NoError(t, g.Delete(ctx, "some-file"))
+		require.NoError(t, g.Add(ctx, "some-file"))
+		require.NoError(t, g.Commit(ctx, "removed some-file"))
+		assert.False(t, g.HasStagedChanges(ctx))
+
+		st, err := g.Status(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, string(st))
+
+		assert.ErrorIs(t, g.Compact(ctx), backend.ErrNotSupported)
+	})
+
+	t.Run("only stage deletions of the given paths", func(t *testing.T) {
+		g, err := New(gitdir)
+		require.NoError(t, err)
+
+		for _, f := range []string{"a/x", "b/y"} {
+			require.NoError(t, g.Set(ctx, f, []byte(f)))
+		}
+		require.NoError(t, g.Add(ctx, "a", "b"))
+		require.NoError(t, g.Commit(ctx, "added a and b"))
+
+		require.NoError(t, os.RemoveAll(filepath.Join(gitdir, "a")))
+		require.NoError(t, os.RemoveAll(filepath.Join(gitdir, "b")))
+		require.NoError(t, g.Add(ctx, "a"))
+
+		wt, err := g.repo.Worktree()
+		require.NoError(t, err)
+		st, err := wt.Status()
+		require.NoError(t, err)
+		assert.Equal(t, git.Deleted, st.File("a/x").Staging)
+		assert.Equal(t, git.Unmodified, st.File("b/y").Staging)
+		assert.Equal(t, git.Deleted, st.File("b/y").Worktree)
+	})
+}
+
+func TestSplitKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		key        string
+		section    string
+		subsection string
+		option     string
+	}{
+		{"user.name", "user", "", "name"},
+		{"remote.origin.url", "remote", "origin", "url"},
+		{"branch.feature/x.y.remote", "branch", "feature/x.y", "remote"},
+	} {
+		s, ss, o, err := splitKey(tc.key)
+		require.NoError(t, err)
+		assert.Equal(t, tc.section, s)
+		assert.Equal(t, tc.subsection, ss)
+		assert.Equal(t, tc.option, o)
+	}
+
+	_, _, _, err := splitKey("invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package gogitfs
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+const (
+	name = "gogitfs"
+)
+
+func init() {
+	backend.StorageRegistry.Register(backend.GoGitFS, name, &loader{})
+}
+
+// Wanted returns true if the built-in git implementation should be used
+// instead of the git binary, i.e. if git.native is set or if there is
+// no git binary.
+func Wanted(ctx context.Context) bool {
+	if config.Bool(ctx, "git.native") {
+		return true
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		debug.Log("git binary not found, using built-in git: %s", err)
+
+		return true
+	}
+
+	return false
+}
+
+type loader struct{}
+
+func (l loader) New(ctx context.Context, path string) (backend.Storage, error) {
+	return New(path)
+}
+
+// Open implements backend.RCSLoader.
+func (l loader) Open(ctx context.Context, path string) (backend.Storage, error) {
+	return New(path)
+}
+
+// Clone implements backend.RCSLoader.
+func (l loader) Clone(ctx context.Context, repo, path string) (backend.Storage, error) {
+	return Clone(ctx, repo, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
+}
+
+// Init implements backend.RCSLoader.
+func (l loader) Init(ctx context.Context, path string) (backend.Storage, error) {
+	return Init(ctx, path, termio.DetectName(ctx, nil), termio.DetectEmail(ctx, nil))
+}
+
+func (l loader) Handles(ctx context.Context, path string) error {
+	path = fsutil.ExpandHomedir(path)
+	if !fsutil.IsDir(filepath.Join(path, ".git")) {
+		return fmt.Errorf("no .git at %s", path)
+	}
+
+	if !Wanted(ctx) {
+		return fmt.Errorf("using the git binary")
+	}
+
+	return nil
+}
+
+// Priority sorts gogitfs before gitfs so it is detected first, if wanted.
+func (l loader) Priority() int {
+	return 0
+}
+
+func (l loader) String() string {
+	return name
+}
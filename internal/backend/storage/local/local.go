@@ -0,0 +1,463 @@
+// Package local implements local-only entries for machine specific
+// credentials. They are kept in a per-device directory outside of the
+// repository of the store, so they can never be committed or pushed, no
+// matter which RCS is used. Otherwise they are regular entries: they are
+// encrypted for the recipients of the store and listed and found by their
+// usual names. The local storage wraps any storage backend and is
+// transparent to the rest of gopass.
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// MarksFile is the file in the local directory listing the local-only
+// entries and folders, one per line. Folders end with a slash.
+const MarksFile = ".gopass-local"
+
+// ErrLinkNotSupported is returned when trying to link local-only entries.
+var ErrLinkNotSupported = errors.New("links from or to local-only entries are not supported")
+
+// Dir returns the directory holding the local-only entries of the store at
+// the given path mounted at alias. It's keyed by both, so the entries don't
+// follow an alias to another store, e.g. after remounting it or switching
+// config files.
+func Dir(alias, store string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(store) + "\n" + alias))
+
+	return filepath.Join(appdir.UserData(), "local", fmt.Sprintf("%x", sum[:8]))
+}
+
+// Enabled returns true if the directory has local-only entries.
+func Enabled(dir string) bool {
+	return fsutil.IsFile(filepath.Join(dir, MarksFile))
+}
+
+// Unwrap returns the storage backend wrapped by st if it's a local storage
+// and st otherwise. It's used to access features of the RCS that are not part
+// of the storage interface.
+func Unwrap(st backend.Storage) backend.Storage {
+	if ls, ok := st.(*Storage); ok {
+		return ls.Storage
+	}
+
+	return st
+}
+
+// Storage keeps the local-only entries in a separate directory and
+// everything else in the wrapped storage backend.
+type Storage struct {
+	backend.Storage
+
+	dir   string
+	local *fs.Store
+
+	// mu guards the marks.
+	mu    sync.Mutex
+	marks []string
+}
+
+// New wraps the given storage backend. The local-only entries are kept in
+// dir.
+func New(st backend.Storage, dir string) (*Storage, error) {
+	s := &Storage{
+		Storage: st,
+		dir:     dir,
+	}
+
+	buf, err := os.ReadFile(filepath.Join(dir, MarksFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read local-only entries: %w", err)
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	for sc.Scan() {
+		if m := strings.TrimSpace(sc.Text()); m != "" {
+			s.marks = append(s.marks, m)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	s.local = fs.New(dir)
+
+	return s, nil
+}
+
+// Unwrap returns the wrapped storage backend.
+func (s *Storage) Unwrap() backend.Storage {
+	return s.Storage
+}
+
+// String implements fmt.Stringer.
+func (s *Storage) String() string {
+	return fmt.Sprintf("local(%s)", s.Storage.String())
+}
+
+// Dir returns the directory of the local-only entries.
+func (s *Storage) Dir() string {
+	return s.dir
+}
+
+// Marks returns the local-only files and folders.
+func (s *Storage) Marks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.marks...)
+}
+
+// IsLocal returns true if the named file is local-only. Hidden files and
+// everything inside hidden folders, e.g. recipients, are never local-only.
+func (s *Storage) IsLocal(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isLocal(name)
+}
+
+func (s *Storage) isLocal(name string) bool {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "/")
+	for _, p := range strings.Split(name, "/") {
+		if strings.HasPrefix(p, ".") {
+			return false
+		}
+	}
+
+	for _, m := range s.marks {
+		if name == m || (strings.HasSuffix(m, "/") && strings.HasPrefix(name, m)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Storage) save() error {
+	fn := filepath.Join(s.dir, MarksFile)
+	if len(s.marks) < 1 {
+		if err := os.Remove(fn); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", fn, err)
+		}
+
+		return nil
+	}
+
+	sort.Strings(s.marks)
+	if err := os.WriteFile(fn, []byte(strings.Join(s.marks, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fn, err)
+	}
+
+	return nil
+}
+
+// Mark makes the file or folder, if it ends with a slash, local-only. The
+// files already in the repository are moved to the local directory. It
+// returns them, so the caller can commit their removal.
+func (s *Storage) Mark(ctx context.Context, mark string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.marks {
+		if m == mark {
+			return nil, nil
+		}
+	}
+
+	files, err := s.Storage.List(ctx, mark)
+	if err != nil {
+		return nil, err
+	}
+
+	s.marks = append(s.marks, mark)
+
+	moved := make([]string, 0, len(files))
+	for _, f := range files {
+		if !s.isLocal(f) {
+			continue
+		}
+
+		content, err := s.Storage.Get(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.local.Set(ctx, f, content); err != nil {
+			return nil, err
+		}
+		moved = append(moved, f)
+	}
+
+	// only remove the files from the repository once they are safe.
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+
+	for _, f := range moved {
+		if err := s.Storage.Delete(ctx, f); err != nil {
+			return nil, fmt.Errorf("failed to remove %s from the repository: %w", f, err)
+		}
+	}
+	debug.Log("marked %s as local-only, moved %d files", mark, len(moved))
+
+	return moved, nil
+}
+
+// Unmark removes the local-only mark from the file or folder. The files that
+// are no longer local-only are moved to the repository. It returns them, so
+// the caller can commit them.
+func (s *Storage) Unmark(ctx context.Context, mark string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	marks := make([]string, 0, len(s.marks))
+	for _, m := range s.marks {
+		if m != mark {
+			marks = append(marks, m)
+		}
+	}
+
+	if len(marks) == len(s.marks) {
+		return nil, nil
+	}
+	s.marks = marks
+
+	files, err := s.local.List(ctx, mark)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := make([]string, 0, len(files))
+	for _, f := range files {
+		if f == MarksFile || s.isLocal(f) {
+			continue
+		}
+
+		content, err := s.local.Get(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.Storage.Set(ctx, f, content); err != nil {
+			return nil, err
+		}
+
+		if err := s.local.Delete(ctx, f); err != nil {
+			return nil, err
+		}
+		moved = append(moved, f)
+	}
+	debug.Log("unmarked %s, moved %d files to the repository", mark, len(moved))
+
+	return moved, s.save()
+}
+
+// Get retrieves the named content.
+func (s *Storage) Get(ctx context.Context, name string) ([]byte, error) {
+	if s.IsLocal(name) {
+		return s.local.Get(ctx, name)
+	}
+
+	return s.Storage.Get(ctx, name)
+}
+
+// Set writes the given content.
+func (s *Storage) Set(ctx context.Context, name string, value []byte) error {
+	if s.IsLocal(name) {
+		return s.local.Set(ctx, name, value)
+	}
+
+	return s.Storage.Set(ctx, name, value)
+}
+
+// Delete removes the named entity.
+func (s *Storage) Delete(ctx context.Context, name string) error {
+	if s.IsLocal(name) {
+		return s.local.Delete(ctx, name)
+	}
+
+	return s.Storage.Delete(ctx, name)
+}
+
+// Exists checks if the named entity exists.
+func (s *Storage) Exists(ctx context.Context, name string) bool {
+	if s.IsLocal(name) {
+		return s.local.Exists(ctx, name)
+	}
+
+	return s.Storage.Exists(ctx, name)
+}
+
+// IsDir returns true if the named entity is a directory.
+func (s *Storage) IsDir(ctx context.Context, name string) bool {
+	return s.Storage.IsDir(ctx, name) || s.local.IsDir(ctx, name)
+}
+
+// List returns a list of all entities, including the local-only ones.
+func (s *Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	files, err := s.Storage.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.local.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local-only entries: %w", err)
+	}
+
+	for _, f := range local {
+		if f != MarksFile && s.IsLocal(f) {
+			files = append(files, f)
+		}
+	}
+
+	return set.Sorted(files), nil
+}
+
+// Prune removes a named directory.
+func (s *Storage) Prune(ctx context.Context, prefix string) error {
+	if s.local.IsDir(ctx, prefix) {
+		if err := s.local.Prune(ctx, prefix); err != nil {
+			return err
+		}
+	}
+
+	if !s.Storage.IsDir(ctx, prefix) {
+		return nil
+	}
+
+	return s.Storage.Prune(ctx, prefix)
+}
+
+// Move moves from src to dst.
+func (s *Storage) Move(ctx context.Context, src, dst string, del bool) error {
+	if !s.IsLocal(src) && !s.IsLocal(dst) {
+		return s.Storage.Move(ctx, src, dst, del)
+	}
+
+	content, err := s.Get(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Set(ctx, dst, content); err != nil {
+		return err
+	}
+
+	if !del {
+		return nil
+	}
+
+	return s.Delete(ctx, src)
+}
+
+// Link is not supported for local-only entries.
+func (s *Storage) Link(ctx context.Context, from, to string) error {
+	if !s.IsLocal(from) && !s.IsLocal(to) {
+		return s.Storage.Link(ctx, from, to)
+	}
+
+	return ErrLinkNotSupported
+}
+
+// Add adds the given files to the RCS. Local-only files are never added.
+func (s *Storage) Add(ctx context.Context, args ...string) error {
+	files := make([]string, 0, len(args))
+	for _, a := range args {
+		if rel := strings.TrimPrefix(a, s.Path()+"/"); !filepath.IsAbs(rel) && s.IsLocal(rel) {
+			debug.Log("not adding local-only file %s", rel)
+
+			continue
+		}
+		files = append(files, a)
+	}
+
+	if len(files) < 1 && len(args) > 0 {
+		return nil
+	}
+
+	return s.Storage.Add(ctx, files...)
+}
+
+// Leak is a local-only file that reached the repository.
+type Leak struct {
+	Name string
+	// Tracked is true if the file is still in the repository. Otherwise it's
+	// only in the history.
+	Tracked bool
+}
+
+// Leaks returns the local-only files that are, or have been, in the
+// repository.
+func (s *Storage) Leaks(ctx context.Context) ([]Leak, error) {
+	files, err := s.local.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local-only entries: %w", err)
+	}
+
+	var leaks []Leak
+	for _, f := range files {
+		if f == MarksFile {
+			continue
+		}
+
+		if s.Storage.Exists(ctx, f) {
+			leaks = append(leaks, Leak{Name: f, Tracked: true})
+
+			continue
+		}
+
+		revs, err := s.Storage.Revisions(ctx, f)
+		if err != nil {
+			debug.Log("failed to get revisions of %s: %s", f, err)
+
+			continue
+		}
+
+		// storage backends without history report the current file as
+		// the only revision, so only count revisions with content.
+		for _, rev := range revs {
+			if _, err := s.Storage.GetRevision(ctx, f, rev.Hash); err == nil {
+				leaks = append(leaks, Leak{Name: f})
+
+				break
+			}
+		}
+	}
+
+	return leaks, nil
+}
+
+// Fsck checks the storage integrity, including the local directory.
+func (s *Storage) Fsck(ctx context.Context) error {
+	files, err := s.local.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list local-only entries: %w", err)
+	}
+
+	for _, f := range files {
+		if f != MarksFile && !s.IsLocal(f) {
+			debug.Log("%s in %s is not marked as local-only", f, s.dir)
+		}
+	}
+
+	return s.Storage.Fsck(ctx)
+}
@@ -0,0 +1,118 @@
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addRecorder records the files added to the RCS.
+type addRecorder struct {
+	*fs.Store
+	added []string
+}
+
+func (a *addRecorder) Add(ctx context.Context, args ...string) error {
+	a.added = append(a.added, args...)
+
+	return nil
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	td := t.TempDir()
+	repo := &addRecorder{Store: fs.New(filepath.Join(td, "repo"))}
+	for _, f := range []string{".gpg-id", "foo.gpg", "device/.gpg-id", "device/vpn.gpg", "web/a.gpg"} {
+		require.NoError(t, repo.Set(ctx, f, []byte("content of "+f)))
+	}
+
+	dir := filepath.Join(td, "local")
+	assert.False(t, Enabled(dir))
+
+	s, err := New(repo, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "local("+repo.String()+")", s.String())
+
+	moved, err := s.Mark(ctx, "device/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device/vpn.gpg"}, moved)
+	assert.True(t, Enabled(dir))
+	assert.Equal(t, []string{"device/"}, s.Marks())
+
+	// the file left the repository, but not the store.
+	assert.False(t, repo.Exists(ctx, "device/vpn.gpg"))
+	assert.True(t, repo.Exists(ctx, "device/.gpg-id"))
+	assert.True(t, s.Exists(ctx, "device/vpn.gpg"))
+	buf, err := s.Get(ctx, "device/vpn.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "content of device/vpn.gpg", string(buf))
+
+	// new entries in the folder are local-only, too.
+	require.NoError(t, s.Set(ctx, "device/ssh.gpg", []byte("ssh")))
+	assert.False(t, repo.Exists(ctx, "device/ssh.gpg"))
+
+	lst, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gpg-id", "device/.gpg-id", "device/ssh.gpg", "device/vpn.gpg", "foo.gpg", "web/a.gpg"}, lst)
+
+	// local-only files are never added to the RCS.
+	require.NoError(t, s.Add(ctx, "device/ssh.gpg", "device/vpn.gpg"))
+	assert.Empty(t, repo.added)
+	require.NoError(t, s.Add(ctx, "device/ssh.gpg", "foo.gpg"))
+	assert.Equal(t, []string{"foo.gpg"}, repo.added)
+
+	assert.ErrorIs(t, s.Link(ctx, "device/vpn.gpg", "vpn.gpg"), ErrLinkNotSupported)
+
+	// moving an entry out of the folder puts it in the repository.
+	require.NoError(t, s.Move(ctx, "device/ssh.gpg", "ssh.gpg", true))
+	assert.True(t, repo.Exists(ctx, "ssh.gpg"))
+	assert.False(t, s.Exists(ctx, "device/ssh.gpg"))
+
+	leaks, err := s.Leaks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, leaks)
+
+	// a copy in the repository is reported.
+	require.NoError(t, repo.Set(ctx, "device/vpn.gpg", []byte("leaked")))
+	leaks, err = s.Leaks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Leak{{Name: "device/vpn.gpg", Tracked: true}}, leaks)
+	require.NoError(t, repo.Delete(ctx, "device/vpn.gpg"))
+
+	// the marks are persisted.
+	s, err = New(repo, dir)
+	require.NoError(t, err)
+	assert.True(t, s.IsLocal("device/vpn.gpg"))
+	assert.False(t, s.IsLocal("device/.gpg-id"))
+	assert.False(t, s.IsLocal("foo.gpg"))
+
+	moved, err = s.Unmark(ctx, "device/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device/vpn.gpg"}, moved)
+	assert.True(t, repo.Exists(ctx, "device/vpn.gpg"))
+	assert.False(t, Enabled(dir))
+	assert.Equal(t, repo, Unwrap(s))
+}
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+
+	seen := map[string]string{}
+	for _, store := range []string{"/home/alice/.password-store", "/home/alice/work"} {
+		for _, alias := range []string{"", "root", "a/b", "a-b", "a%2Fb", `a\b`} {
+			d := Dir(alias, store)
+			assert.NotContains(t, seen, d, alias)
+			seen[d] = alias
+
+			assert.Equal(t, filepath.Join(filepath.Dir(Dir("", store)), filepath.Base(d)), d, alias)
+		}
+	}
+
+	assert.Equal(t, Dir("work", "/home/alice/work"), Dir("work", "/home/alice/work/"))
+}
@@ -0,0 +1,744 @@
+// Package pack implements the pack layout for large stores. Instead of one
+// file per entry it stores all entries of a folder in a single bundle file
+// with an index. This drastically reduces the number of files and git objects
+// for stores with many entries. The entries are encrypted individually, just
+// like in the default layout, so the bundles don't need any additional
+// encryption. The pack layout wraps any storage backend and is transparent
+// to the rest of gopass.
+package pack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// MarkerFile is the file in the root of a store that enables the pack layout.
+	MarkerFile = ".gopass-pack"
+	// BundleFile is the name of the bundle file in each folder.
+	BundleFile = ".gopass-bundle"
+
+	header = "gopass-pack 1"
+
+	// tmpSuffix is appended to the name of a bundle while it is written.
+	tmpSuffix = ".tmp"
+)
+
+// ErrLinkNotSupported is returned when trying to link entries in a packed store.
+var ErrLinkNotSupported = errors.New("links are not supported by the pack layout")
+
+// Enabled returns true if the given storage uses the pack layout.
+func Enabled(ctx context.Context, st backend.Storage) bool {
+	return st.Exists(ctx, MarkerFile)
+}
+
+// Packable returns true if the given file is stored in a bundle. Hidden files
+// and everything inside hidden folders, e.g. recipients, templates and
+// exported public keys, are kept as regular files.
+func Packable(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, p := range strings.Split(name, "/") {
+		if strings.HasPrefix(p, ".") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bundlePath returns the path of the bundle holding the given file and the
+// name of the file inside the bundle.
+func bundlePath(name string) (string, string) {
+	dir, base := path.Split(name)
+
+	return path.Join(dir, BundleFile), base
+}
+
+// Storage is a storage backend using the pack layout on top of another
+// storage backend.
+type Storage struct {
+	backend.Storage
+
+	// mu guards read-modify-write cycles of the bundles and the batch
+	// state.
+	mu sync.Mutex
+	// batches is the number of open batches. While it's > 0 changed
+	// bundles are kept in dirty and the files to add in added.
+	batches int
+	dirty   map[string]bundle
+	added   []string
+	// cache holds the parsed bundles, see load.
+	cache map[string]cached
+}
+
+// cached is a parsed bundle along with the size and modification time of the
+// bundle file it was parsed from.
+type cached struct {
+	b    bundle
+	size int64
+	mod  time.Time
+}
+
+// New wraps the given storage backend.
+func New(st backend.Storage) *Storage {
+	return &Storage{
+		Storage: st,
+	}
+}
+
+// Unwrap returns the wrapped storage backend.
+func (s *Storage) Unwrap() backend.Storage {
+	return s.Storage
+}
+
+// String implements fmt.Stringer.
+func (s *Storage) String() string {
+	return fmt.Sprintf("pack(%s)", s.Storage.String())
+}
+
+// load reads the bundle. Parsed bundles are cached as long as the size and
+// modification time of the bundle file don't change, e.g. by a git pull. The
+// returned bundle is a copy and can be modified. It must be called with mu
+// held.
+func (s *Storage) load(ctx context.Context, bp string) (bundle, error) {
+	if b, found := s.dirty[bp]; found {
+		return b, nil
+	}
+
+	fi, statErr := os.Stat(filepath.Join(s.Path(), filepath.FromSlash(bp)))
+	if c, found := s.cache[bp]; found && statErr == nil && c.size == fi.Size() && c.mod.Equal(fi.ModTime()) {
+		return c.b.clone(), nil
+	}
+
+	if !s.Storage.Exists(ctx, bp) {
+		return bundle{}, nil
+	}
+
+	buf, err := s.Storage.Get(ctx, bp)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.remember(bp, b)
+
+	return b.clone(), nil
+}
+
+// remember caches the parsed bundle if the storage is backed by a file. It
+// must be called with mu held.
+func (s *Storage) remember(bp string, b bundle) {
+	fi, err := os.Stat(filepath.Join(s.Path(), filepath.FromSlash(bp)))
+	if err != nil {
+		delete(s.cache, bp)
+
+		return
+	}
+
+	if s.cache == nil {
+		s.cache = map[string]cached{}
+	}
+	s.cache[bp] = cached{b: b, size: fi.Size(), mod: fi.ModTime()}
+}
+
+// save writes the bundle or defers it until the batch ends. It must be called
+// with mu held.
+func (s *Storage) save(ctx context.Context, bp string, b bundle) error {
+	if s.batches > 0 {
+		s.dirty[bp] = b
+
+		return nil
+	}
+
+	delete(s.cache, bp)
+
+	if len(b) < 1 {
+		if !s.Storage.Exists(ctx, bp) {
+			return nil
+		}
+
+		return s.Storage.Delete(ctx, bp)
+	}
+
+	if err := writeBundle(ctx, s.Storage, bp, b); err != nil {
+		return err
+	}
+
+	s.remember(bp, b)
+
+	return nil
+}
+
+// writeBundle writes the bundle to a temporary file first and renames it
+// afterwards, so readers never see a partially written bundle.
+func writeBundle(ctx context.Context, st backend.Storage, bp string, b bundle) error {
+	tmp := bp + tmpSuffix
+	if err := st.Set(ctx, tmp, b.bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+
+	return st.Move(ctx, tmp, bp, true)
+}
+
+// Get retrieves the named content.
+func (s *Storage) Get(ctx context.Context, name string) ([]byte, error) {
+	if !Packable(name) {
+		return s.Storage.Get(ctx, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bp, e := bundlePath(name)
+	b, err := s.load(ctx, bp)
+	if err != nil {
+		return nil, err
+	}
+
+	content, found := b[e]
+	if !found {
+		return nil, fmt.Errorf("%s not found in %s: %w", e, bp, backend.ErrNotFound)
+	}
+
+	return content, nil
+}
+
+// Set writes the given content.
+func (s *Storage) Set(ctx context.Context, name string, value []byte) error {
+	if !Packable(name) {
+		return s.Storage.Set(ctx, name, value)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bp, e := bundlePath(name)
+	b, err := s.load(ctx, bp)
+	if err != nil {
+		return err
+	}
+
+	b[e] = value
+
+	return s.save(ctx, bp, b)
+}
+
+// Delete removes the named entity.
+func (s *Storage) Delete(ctx context.Context, name string) error {
+	if !Packable(name) {
+		return s.Storage.Delete(ctx, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bp, e := bundlePath(name)
+	b, err := s.load(ctx, bp)
+	if err != nil {
+		return err
+	}
+
+	if _, found := b[e]; !found {
+		return fmt.Errorf("%s not found in %s: %w", e, bp, backend.ErrNotFound)
+	}
+
+	delete(b, e)
+
+	return s.save(ctx, bp, b)
+}
+
+// Exists checks if the named entity exists.
+func (s *Storage) Exists(ctx context.Context, name string) bool {
+	if !Packable(name) {
+		return s.Storage.Exists(ctx, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bp, e := bundlePath(name)
+	b, err := s.load(ctx, bp)
+	if err != nil {
+		debug.Log("failed to load bundle %s: %s", bp, err)
+
+		return false
+	}
+
+	_, found := b[e]
+
+	return found
+}
+
+// List returns a list of all entities, including the entries of all bundles.
+func (s *Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+
+	// the bundle of a folder doesn't match prefixes of its entries.
+	dir := path.Dir(prefix)
+	if dir == "." {
+		dir = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.Storage.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// bundles created in the current batch are not written, yet.
+	for bp := range s.dirty {
+		if !strings.HasPrefix(bp, dir) {
+			continue
+		}
+		if !s.Storage.Exists(ctx, bp) {
+			files = append(files, bp)
+		}
+	}
+
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if path.Base(f) == BundleFile+tmpSuffix {
+			continue
+		}
+
+		if path.Base(f) != BundleFile {
+			if f != MarkerFile && strings.HasPrefix(f, prefix) {
+				out = append(out, f)
+			}
+
+			continue
+		}
+
+		b, err := s.load(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bundle %s: %w", f, err)
+		}
+
+		for e := range b {
+			if name := path.Join(path.Dir(f), e); strings.HasPrefix(name, prefix) {
+				out = append(out, name)
+			}
+		}
+	}
+
+	return set.Sorted(out), nil
+}
+
+// Prune removes a named directory.
+func (s *Storage) Prune(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Storage.Prune(ctx, prefix)
+}
+
+// Move moves from src to dst.
+func (s *Storage) Move(ctx context.Context, src, dst string, del bool) error {
+	if !Packable(src) && !Packable(dst) {
+		return s.Storage.Move(ctx, src, dst, del)
+	}
+
+	content, err := s.Get(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Set(ctx, dst, content); err != nil {
+		return err
+	}
+
+	if !del {
+		return nil
+	}
+
+	return s.Delete(ctx, src)
+}
+
+// Link is not supported by the pack layout.
+func (s *Storage) Link(ctx context.Context, from, to string) error {
+	if !Packable(from) && !Packable(to) {
+		return s.Storage.Link(ctx, from, to)
+	}
+
+	return ErrLinkNotSupported
+}
+
+// Add adds the bundles holding the given files to the RCS.
+func (s *Storage) Add(ctx context.Context, args ...string) error {
+	files := make([]string, 0, len(args))
+	for _, a := range args {
+		if rel := strings.TrimPrefix(a, s.Path()+"/"); !filepath.IsAbs(rel) && Packable(rel) && !s.Storage.IsDir(ctx, rel) {
+			a, _ = bundlePath(rel)
+		}
+		files = append(files, a)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batches > 0 {
+		s.added = append(s.added, files...)
+
+		return nil
+	}
+
+	return s.Storage.Add(ctx, set.Sorted(files)...)
+}
+
+// Batch defers writing and adding the bundles until the returned function is
+// called. That avoids rewriting a bundle for every entry of bulk operations
+// like re-encrypting a store. Batches can be nested, the bundles are written
+// when the outermost one ends.
+func (s *Storage) Batch() func(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batches == 0 {
+		s.dirty = map[string]bundle{}
+	}
+	s.batches++
+
+	return func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.batches--
+		if s.batches > 0 {
+			return nil
+		}
+
+		return s.flush(ctx)
+	}
+}
+
+// flush writes the changed bundles and adds them to the RCS. It must be called
+// with mu held.
+func (s *Storage) flush(ctx context.Context) error {
+	dirty, added := s.dirty, s.added
+	s.dirty, s.added = nil, nil
+
+	for _, bp := range set.SortedKeys(dirty) {
+		if err := s.save(ctx, bp, dirty[bp]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", bp, err)
+		}
+	}
+	debug.Log("wrote %d bundles", len(dirty))
+
+	if len(added) < 1 {
+		return nil
+	}
+
+	return s.Storage.Add(ctx, set.Sorted(added)...)
+}
+
+// Revisions lists the revisions of the bundle that changed the named entity.
+func (s *Storage) Revisions(ctx context.Context, name string) ([]backend.Revision, error) {
+	if !Packable(name) {
+		return s.Storage.Revisions(ctx, name)
+	}
+
+	bp, _ := bundlePath(name)
+	revs, err := s.Storage.Revisions(ctx, bp)
+	if err != nil {
+		return nil, err
+	}
+
+	// only keep the revisions that changed the entry. The revisions are
+	// sorted from newest to oldest.
+	out := make([]backend.Revision, 0, len(revs))
+	for i, rev := range revs {
+		cur, err := s.GetRevision(ctx, name, rev.Hash)
+		if err != nil {
+			continue
+		}
+
+		if i+1 < len(revs) {
+			if prev, err := s.GetRevision(ctx, name, revs[i+1].Hash); err == nil && bytes.Equal(cur, prev) {
+				continue
+			}
+		}
+
+		out = append(out, rev)
+	}
+
+	return out, nil
+}
+
+// GetRevision returns the content of the named entity at the given revision.
+func (s *Storage) GetRevision(ctx context.Context, name, revision string) ([]byte, error) {
+	if !Packable(name) {
+		return s.Storage.GetRevision(ctx, name, revision)
+	}
+
+	bp, e := bundlePath(name)
+	buf, err := s.Storage.GetRevision(ctx, bp, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	content, found := b[e]
+	if !found {
+		return nil, fmt.Errorf("%s not found in %s@%s: %w", e, bp, revision, backend.ErrNotFound)
+	}
+
+	return content, nil
+}
+
+// Fsck checks the storage integrity, including all bundles.
+func (s *Storage) Fsck(ctx context.Context) error {
+	files, err := s.Storage.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for _, f := range files {
+		if path.Base(f) != BundleFile {
+			continue
+		}
+
+		if _, err := s.load(ctx, f); err != nil {
+			s.mu.Unlock()
+
+			return fmt.Errorf("bundle %s is corrupt: %w", f, err)
+		}
+	}
+	s.mu.Unlock()
+
+	return s.Storage.Fsck(ctx)
+}
+
+// Pack converts all regular files of the given storage to bundles.
+// It returns the names of all changed files. The regular files are only
+// removed once all bundles and the marker are written, so an interrupted
+// conversion doesn't lose any entries.
+func Pack(ctx context.Context, st backend.Storage) ([]string, error) {
+	files, err := st.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := map[string]bundle{}
+	changed := make([]string, 0, len(files))
+	for _, f := range files {
+		if !Packable(f) {
+			continue
+		}
+
+		content, err := st.Get(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f, err)
+		}
+
+		bp, e := bundlePath(f)
+		if bundles[bp] == nil {
+			bundles[bp] = bundle{}
+		}
+		bundles[bp][e] = content
+		changed = append(changed, f)
+	}
+
+	entries := len(changed)
+	for _, bp := range set.SortedKeys(bundles) {
+		if err := writeBundle(ctx, st, bp, bundles[bp]); err != nil {
+			return nil, err
+		}
+		changed = append(changed, bp)
+	}
+
+	if err := st.Set(ctx, MarkerFile, []byte(header+"\n")); err != nil {
+		return nil, err
+	}
+
+	for _, f := range changed[:entries] {
+		if err := st.Delete(ctx, f); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", f, err)
+		}
+	}
+
+	return append(changed, MarkerFile), nil
+}
+
+// Unpack converts all bundles of the given storage back to regular files.
+// It returns the names of all changed files. The bundles are only removed
+// once all regular files are written and the marker is removed.
+func Unpack(ctx context.Context, st backend.Storage) ([]string, error) {
+	if ps, ok := st.(*Storage); ok {
+		st = ps.Unwrap()
+	}
+
+	files, err := st.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]string, 0, len(files))
+	var bundles []string
+	for _, f := range files {
+		if path.Base(f) != BundleFile {
+			continue
+		}
+
+		buf, err := st.Get(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := parse(buf)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s is corrupt: %w", f, err)
+		}
+
+		for _, e := range set.SortedKeys(b) {
+			name := path.Join(path.Dir(f), e)
+			if err := st.Set(ctx, name, b[e]); err != nil && !errors.Is(err, store.ErrMeaninglessWrite) {
+				return nil, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+			changed = append(changed, name)
+		}
+		bundles = append(bundles, f)
+	}
+
+	if st.Exists(ctx, MarkerFile) {
+		if err := st.Delete(ctx, MarkerFile); err != nil {
+			return nil, err
+		}
+		changed = append(changed, MarkerFile)
+	}
+
+	for _, f := range bundles {
+		if err := st.Delete(ctx, f); err != nil {
+			return nil, err
+		}
+		changed = append(changed, f)
+	}
+
+	return changed, nil
+}
+
+// bundle maps the file names inside a folder to their content.
+type bundle map[string][]byte
+
+// bytes serializes the bundle. It starts with a header, followed by the index
+// (size and name of each file, one per line), an empty line and the
+// concatenated content of all files. The files are sorted by name so the
+// output is stable.
+func (b bundle) bytes() []byte {
+	names := make([]string, 0, len(b))
+	size := len(header) + 2
+	for n, c := range b {
+		names = append(names, n)
+		size += len(n) + len(c) + 22
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	buf.WriteString(header + "\n")
+	for _, n := range names {
+		fmt.Fprintf(buf, "%d %s\n", len(b[n]), n)
+	}
+	buf.WriteString("\n")
+	for _, n := range names {
+		buf.Write(b[n])
+	}
+
+	return buf.Bytes()
+}
+
+// clone returns a shallow copy of the bundle.
+func (b bundle) clone() bundle {
+	c := make(bundle, len(b))
+	for n, content := range b {
+		c[n] = content
+	}
+
+	return c
+}
+
+// parse parses a serialized bundle.
+func parse(buf []byte) (bundle, error) {
+	r := bufio.NewReader(bytes.NewReader(buf))
+
+	line, err := r.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != header {
+		return nil, fmt.Errorf("invalid header")
+	}
+
+	type idx struct {
+		name string
+		size int
+	}
+
+	var index []idx
+	offset := len(line)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("truncated index: %w", err)
+		}
+		offset += len(line)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+
+		sz, name, found := strings.Cut(line, " ")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid index line %q", line)
+		}
+
+		size, err := strconv.Atoi(sz)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid size in index line %q", line)
+		}
+
+		index = append(index, idx{name: name, size: size})
+	}
+
+	b := make(bundle, len(index))
+	for _, i := range index {
+		if offset+i.size > len(buf) {
+			return nil, fmt.Errorf("truncated content of %s", i.name)
+		}
+
+		b[i.name] = buf[offset : offset+i.size]
+		offset += i.size
+	}
+
+	if offset != len(buf) {
+		return nil, fmt.Errorf("%d trailing bytes", len(buf)-offset)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,178 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	b := bundle{
+		"foo.gpg":     []byte("foo"),
+		"bar baz.gpg": []byte("bar\nbaz\n"),
+		"empty.gpg":   {},
+	}
+
+	got, err := parse(b.bytes())
+	require.NoError(t, err)
+	assert.Equal(t, b, got)
+
+	for _, in := range []string{
+		"",
+		"invalid\n\n",
+		header + "\n3 foo.gpg\n",
+		header + "\n3 foo.gpg\n\nfo",
+		header + "\n3 foo.gpg\n\nfooo",
+		header + "\nx foo.gpg\n\nfoo",
+	} {
+		_, err := parse([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
+
+func TestPackable(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Packable("foo.gpg"))
+	assert.True(t, Packable("foo/bar.age"))
+	assert.False(t, Packable(".gpg-id"))
+	assert.False(t, Packable("foo/.gpg-id"))
+	assert.False(t, Packable(".public-keys/0xDEADBEEF"))
+	assert.False(t, Packable(""))
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	td := t.TempDir()
+
+	loose := fs.New(td)
+	for _, f := range []string{".gpg-id", "foo.gpg", "web/a.gpg", "web/b.gpg", "web/sub/c.gpg"} {
+		require.NoError(t, loose.Set(ctx, f, []byte("content of "+f)))
+	}
+
+	changed, err := Pack(ctx, loose)
+	require.NoError(t, err)
+	assert.Contains(t, changed, "web/.gopass-bundle")
+	assert.Contains(t, changed, "web/a.gpg")
+	assert.True(t, Enabled(ctx, loose))
+	assert.NoFileExists(t, filepath.Join(td, "web", "a.gpg"))
+	assert.FileExists(t, filepath.Join(td, ".gpg-id"))
+
+	s := New(loose)
+
+	l, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gpg-id", "foo.gpg", "web/a.gpg", "web/b.gpg", "web/sub/c.gpg"}, l)
+
+	l, err = s.List(ctx, "web/a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web/a.gpg"}, l)
+
+	buf, err := s.Get(ctx, "web/b.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "content of web/b.gpg", string(buf))
+
+	require.NoError(t, s.Set(ctx, "web/d.gpg", []byte("new")))
+	assert.True(t, s.Exists(ctx, "web/d.gpg"))
+	assert.NoFileExists(t, filepath.Join(td, "web", "d.gpg"))
+
+	require.NoError(t, s.Move(ctx, "web/d.gpg", "other/d.gpg", true))
+	assert.False(t, s.Exists(ctx, "web/d.gpg"))
+	assert.True(t, s.Exists(ctx, "other/d.gpg"))
+
+	require.NoError(t, s.Delete(ctx, "other/d.gpg"))
+	assert.NoFileExists(t, filepath.Join(td, "other", BundleFile))
+	assert.Error(t, s.Delete(ctx, "other/d.gpg"))
+
+	assert.ErrorIs(t, s.Link(ctx, "foo.gpg", "bar.gpg"), ErrLinkNotSupported)
+	require.NoError(t, s.Fsck(ctx))
+
+	require.NoError(t, os.WriteFile(filepath.Join(td, "web", BundleFile), []byte("garbage"), 0o600))
+	assert.Error(t, s.Fsck(ctx))
+	// never overwrite a corrupt bundle.
+	assert.Error(t, s.Set(ctx, "web/a.gpg", []byte("fixed")))
+	require.NoError(t, os.Remove(filepath.Join(td, "web", BundleFile)))
+	require.NoError(t, s.Set(ctx, "web/a.gpg", []byte("fixed")))
+
+	_, err = Unpack(ctx, s)
+	require.NoError(t, err)
+	assert.False(t, Enabled(ctx, loose))
+
+	buf, err = os.ReadFile(filepath.Join(td, "web", "a.gpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "fixed", string(buf))
+	assert.FileExists(t, filepath.Join(td, "web", "sub", "c.gpg"))
+	assert.NoFileExists(t, filepath.Join(td, "web", "sub", BundleFile))
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	td := t.TempDir()
+
+	s := New(fs.New(td))
+	require.NoError(t, s.Set(ctx, "web/a.gpg", []byte("a")))
+
+	end := s.Batch()
+	require.NoError(t, s.Set(ctx, "web/b.gpg", []byte("b")))
+	require.NoError(t, s.Set(ctx, "new/c.gpg", []byte("c")))
+	require.NoError(t, s.Delete(ctx, "web/a.gpg"))
+
+	// the changes are visible, but not written.
+	assert.True(t, s.Exists(ctx, "web/b.gpg"))
+	assert.False(t, s.Exists(ctx, "web/a.gpg"))
+	l, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new/c.gpg", "web/b.gpg"}, l)
+	assert.NoFileExists(t, filepath.Join(td, "new", BundleFile))
+
+	buf, err := os.ReadFile(filepath.Join(td, "web", BundleFile))
+	require.NoError(t, err)
+	b, err := parse(buf)
+	require.NoError(t, err)
+	assert.Equal(t, bundle{"a.gpg": []byte("a")}, b)
+
+	require.NoError(t, end(ctx))
+	assert.FileExists(t, filepath.Join(td, "new", BundleFile))
+
+	buf, err = os.ReadFile(filepath.Join(td, "web", BundleFile))
+	require.NoError(t, err)
+	b, err = parse(buf)
+	require.NoError(t, err)
+	assert.Equal(t, bundle{"b.gpg": []byte("b")}, b)
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	td := t.TempDir()
+
+	s := New(fs.New(td))
+	require.NoError(t, s.Set(ctx, "web/a.gpg", []byte("a")))
+	assert.NoFileExists(t, filepath.Join(td, "web", BundleFile+tmpSuffix))
+	assert.Contains(t, s.cache, "web/"+BundleFile)
+
+	buf, err := s.Get(ctx, "web/a.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(buf))
+
+	// changes behind our back, e.g. by a git pull, invalidate the cache.
+	b := bundle{"a.gpg": []byte("changed"), "b.gpg": []byte("b")}
+	require.NoError(t, os.WriteFile(filepath.Join(td, "web", BundleFile), b.bytes(), 0o600))
+
+	buf, err = s.Get(ctx, "web/a.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", string(buf))
+	assert.True(t, s.Exists(ctx, "web/b.gpg"))
+}
@@ -0,0 +1,230 @@
+// Package backup protects exported backups, e.g. bundles, with a passphrase.
+//
+// The key is derived with argon2id. Its parameters are calibrated on the
+// exporting machine, so that brute forcing the passphrase offline is as
+// expensive as the machine allows, and stored in the header. The header is
+// authenticated together with the AES-256-GCM encrypted content.
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/crypto/argon2"
+)
+
+// magic starts every passphrase protected backup.
+var magic = []byte("gopass-backup v1\n")
+
+// KDFArgon2id is the only supported KDF.
+const KDFArgon2id = "argon2id"
+
+const (
+	keyLen  = 32
+	saltLen = 16
+
+	// the defaults used by Calibrate, see RFC 9106 section 4.
+	defaultMemory = 64 * 1024
+	minTime       = 3
+	maxThreads    = 4
+
+	// limits for reading headers, so a crafted backup can't exhaust the
+	// machine.
+	maxMemory = 4 * 1024 * 1024
+	maxTime   = 1024
+)
+
+// ErrNotABackup is returned by Open if the data is not a passphrase protected
+// backup.
+var ErrNotABackup = errors.New("not a passphrase protected backup")
+
+// ErrDecrypt is returned by Open if the passphrase is wrong or the backup was
+// modified.
+var ErrDecrypt = errors.New("wrong passphrase or corrupted backup")
+
+// Params are the argon2id parameters.
+type Params struct {
+	// Time is the number of passes over the memory.
+	Time uint32 `json:"t"`
+	// Memory is the size of the memory in KiB.
+	Memory uint32 `json:"m"`
+	// Threads is the degree of parallelism.
+	Threads uint8 `json:"p"`
+}
+
+type header struct {
+	KDF  string `json:"kdf"`
+	Salt []byte `json:"salt"`
+	Params
+}
+
+func (p Params) key(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, keyLen)
+}
+
+func (p Params) validate() error {
+	if p.Time < 1 || p.Time > maxTime || p.Memory < 8*uint32(p.Threads) || p.Memory > maxMemory || p.Threads < 1 {
+		return fmt.Errorf("invalid argon2id parameters t=%d m=%d p=%d", p.Time, p.Memory, p.Threads)
+	}
+
+	return nil
+}
+
+// Calibrate returns parameters that take about target to derive a key on this
+// machine. The memory is fixed to 64 MiB and the number of passes is
+// increased, but never below the recommended minimum.
+func Calibrate(target time.Duration) Params {
+	threads := runtime.NumCPU()
+	if threads > maxThreads {
+		threads = maxThreads
+	}
+
+	p := Params{
+		Time:    1,
+		Memory:  defaultMemory,
+		Threads: uint8(threads),
+	}
+
+	salt := make([]byte, saltLen)
+
+	start := time.Now()
+	_ = p.key("calibration", salt)
+	once := time.Since(start)
+
+	if once > 0 {
+		p.Time = uint32(target / once)
+	}
+
+	if p.Time < minTime {
+		p.Time = minTime
+	}
+
+	if p.Time > maxTime {
+		p.Time = maxTime
+	}
+
+	debug.Log("calibrated argon2id to t=%d m=%d p=%d (one pass took %s)", p.Time, p.Memory, p.Threads, once)
+
+	return p
+}
+
+// IsBackup returns true if buf is a passphrase protected backup.
+func IsBackup(buf []byte) bool {
+	return bytes.HasPrefix(buf, magic)
+}
+
+// Seal encrypts plaintext with a key derived from passphrase.
+func Seal(passphrase string, plaintext []byte, p Params) ([]byte, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	h := header{
+		KDF:    KDFArgon2id,
+		Salt:   make([]byte, saltLen),
+		Params: p,
+	}
+
+	if _, err := rand.Read(h.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	hdr, err := json.Marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode header: %w", err)
+	}
+
+	aead, err := newAEAD(p.key(passphrase, h.Salt))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// the magic and the header are authenticated, too.
+	ad := make([]byte, 0, len(magic)+len(hdr)+1)
+	ad = append(ad, magic...)
+	ad = append(ad, hdr...)
+	ad = append(ad, '\n')
+
+	out := make([]byte, 0, len(ad)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, ad...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, ad), nil
+}
+
+// Open decrypts a backup created by Seal.
+func Open(passphrase string, buf []byte) ([]byte, error) {
+	if !IsBackup(buf) {
+		return nil, ErrNotABackup
+	}
+
+	end := bytes.IndexByte(buf[len(magic):], '\n')
+	if end < 0 {
+		return nil, fmt.Errorf("missing header: %w", ErrNotABackup)
+	}
+
+	ad := buf[:len(magic)+end+1]
+
+	var h header
+	if err := json.Unmarshal(buf[len(magic):len(magic)+end], &h); err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+
+	if h.KDF != KDFArgon2id {
+		return nil, fmt.Errorf("unsupported KDF %q", h.KDF)
+	}
+
+	if err := h.Params.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(h.Salt) < saltLen {
+		return nil, fmt.Errorf("salt too short")
+	}
+
+	debug.Log("opening backup with argon2id t=%d m=%d p=%d", h.Time, h.Memory, h.Threads)
+
+	aead, err := newAEAD(h.Params.key(passphrase, h.Salt))
+	if err != nil {
+		return nil, err
+	}
+
+	rest := buf[len(ad):]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GCM: %w", err)
+	}
+
+	return aead, nil
+}
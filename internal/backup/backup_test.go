@@ -0,0 +1,65 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fast parameters for tests.
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+
+	plaintext := []byte("the bundle")
+
+	buf, err := Seal("passphrase", plaintext, testParams)
+	require.NoError(t, err)
+	assert.True(t, IsBackup(buf))
+	assert.False(t, bytes.Contains(buf, plaintext))
+
+	got, err := Open("passphrase", buf)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+
+	_, err = Open("wrong", buf)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = Open("passphrase", plaintext)
+	assert.ErrorIs(t, err, ErrNotABackup)
+
+	// the header is authenticated.
+	tampered := bytes.Replace(buf, []byte(`"t":1`), []byte(`"t":2`), 1)
+	require.NotEqual(t, buf, tampered)
+	_, err = Open("passphrase", tampered)
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestOpenLimits(t *testing.T) {
+	t.Parallel()
+
+	for _, hdr := range []string{
+		`{"kdf":"argon2id","salt":"AAAAAAAAAAAAAAAAAAAAAA==","t":1,"m":4294967295,"p":1}`,
+		`{"kdf":"argon2id","salt":"AAAAAAAAAAAAAAAAAAAAAA==","t":100000,"m":64,"p":1}`,
+		`{"kdf":"argon2id","salt":"AA==","t":1,"m":64,"p":1}`,
+		`{"kdf":"scrypt","salt":"AAAAAAAAAAAAAAAAAAAAAA==","t":1,"m":64,"p":1}`,
+	} {
+		buf := append(append([]byte{}, magic...), hdr+"\n0123456789ab"...)
+		_, err := Open("passphrase", buf)
+		assert.Error(t, err, hdr)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	t.Parallel()
+
+	p := Calibrate(time.Millisecond)
+	assert.Equal(t, uint32(minTime), p.Time)
+	assert.Equal(t, uint32(defaultMemory), p.Memory)
+	assert.GreaterOrEqual(t, p.Threads, uint8(1))
+	assert.NoError(t, p.validate())
+}
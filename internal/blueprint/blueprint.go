@@ -0,0 +1,323 @@
+// Package blueprint implements store blueprints. A blueprint is a YAML file
+// describing the desired layout of a shared store: its recipients, folders
+// with their notes, owners and required keys, templates and mounts. New teams
+// bootstrap a correctly structured store from it with
+// `gopass init --from-blueprint` and `gopass blueprint diff` compares a live
+// store against it.
+package blueprint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+// Blueprint is the desired layout of a store.
+type Blueprint struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Recipients  []string `yaml:"recipients,omitempty"`
+	Folders     []Folder `yaml:"folders,omitempty"`
+	// Templates maps template names to their content.
+	Templates map[string]string `yaml:"templates,omitempty"`
+	Mounts    []Mount           `yaml:"mounts,omitempty"`
+}
+
+// Folder describes a folder of the store.
+type Folder struct {
+	Path string `yaml:"path"`
+	// Info is the content of the folder note.
+	Info   string   `yaml:"info,omitempty"`
+	Owners []string `yaml:"owners,omitempty"`
+	Schema Schema   `yaml:"schema,omitempty"`
+}
+
+// Schema lists the keys every entry of a folder must have.
+type Schema struct {
+	Required []string `yaml:"required,omitempty"`
+}
+
+// Mount describes a sub store mounted into the store.
+type Mount struct {
+	Alias string `yaml:"alias"`
+	// Path is the location of the sub store. The default location for the
+	// alias is used if empty.
+	Path       string   `yaml:"path,omitempty"`
+	Recipients []string `yaml:"recipients,omitempty"`
+}
+
+// Parse parses and validates a blueprint.
+func Parse(buf []byte) (*Blueprint, error) {
+	bp := &Blueprint{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(bp); err != nil {
+		return nil, fmt.Errorf("failed to parse blueprint: %w", err)
+	}
+
+	if bp.Name == "" {
+		return nil, fmt.Errorf("blueprint has no name")
+	}
+
+	seen := make(map[string]bool, len(bp.Folders))
+	for i, f := range bp.Folders {
+		p := clean(f.Path)
+		if p == "" {
+			return nil, fmt.Errorf("blueprint %s: folder %d has no path", bp.Name, i+1)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("blueprint %s: folder %s is listed twice", bp.Name, p)
+		}
+		seen[p] = true
+		bp.Folders[i].Path = p
+	}
+
+	for _, m := range bp.Mounts {
+		if m.Alias == "" || strings.Contains(m.Alias, "..") {
+			return nil, fmt.Errorf("blueprint %s: invalid mount alias %q", bp.Name, m.Alias)
+		}
+	}
+
+	return bp, nil
+}
+
+func clean(p string) string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "." {
+		return ""
+	}
+
+	return p
+}
+
+// Store is the part of the password store a blueprint is compared with.
+type Store interface {
+	ListRecipients(ctx context.Context, store string) []string
+	Mounts() map[string]string
+	HasInfo(ctx context.Context, dir string) bool
+	GetInfo(ctx context.Context, dir string) (gopass.Secret, error)
+	FolderOwners(ctx context.Context, dir string) []string
+	HasTemplate(ctx context.Context, name string) bool
+	GetTemplate(ctx context.Context, name string) ([]byte, error)
+	List(ctx context.Context, maxDepth int) ([]string, error)
+	Get(ctx context.Context, name string) (gopass.Secret, error)
+}
+
+// Writer is the part of the password store a blueprint is applied to.
+type Writer interface {
+	Store
+	AddRecipient(ctx context.Context, store, rec string) error
+	SetInfo(ctx context.Context, dir string, sec gopass.Byter) error
+	SetFolderOwners(ctx context.Context, dir string, owners []string) error
+	SetTemplate(ctx context.Context, name string, content []byte) error
+}
+
+// Difference is a deviation of the store from the blueprint.
+type Difference struct {
+	// Path is the folder, template or entry the difference applies to.
+	Path    string
+	Message string
+}
+
+func (d Difference) String() string {
+	if d.Path == "" {
+		return d.Message
+	}
+
+	return d.Path + ": " + d.Message
+}
+
+// Diff compares the store, or the sub store mounted at prefix, with the
+// blueprint. Checking the schemas decrypts all entries of the folders that
+// have one.
+func (bp *Blueprint) Diff(ctx context.Context, s Store, prefix string) ([]Difference, error) {
+	var diffs []Difference
+
+	have := set.Map(s.ListRecipients(ctx, prefix))
+	for _, r := range bp.Recipients {
+		if !have[r] {
+			diffs = append(diffs, Difference{Message: fmt.Sprintf("recipient %s is missing", r)})
+		}
+	}
+
+	mounts := s.Mounts()
+	for _, m := range bp.Mounts {
+		alias := path.Join(prefix, m.Alias)
+		if _, found := mounts[alias]; !found {
+			diffs = append(diffs, Difference{Path: alias, Message: "mount is missing"})
+
+			continue
+		}
+
+		have := set.Map(s.ListRecipients(ctx, alias))
+		for _, r := range m.Recipients {
+			if !have[r] {
+				diffs = append(diffs, Difference{Path: alias, Message: fmt.Sprintf("recipient %s is missing", r)})
+			}
+		}
+	}
+
+	for _, name := range set.SortedKeys(bp.Templates) {
+		tn := path.Join(prefix, name)
+		if !s.HasTemplate(ctx, tn) {
+			diffs = append(diffs, Difference{Path: tn, Message: "template is missing"})
+
+			continue
+		}
+
+		content, err := s.GetTemplate(ctx, tn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", tn, err)
+		}
+		if strings.TrimSpace(string(content)) != strings.TrimSpace(bp.Templates[name]) {
+			diffs = append(diffs, Difference{Path: tn, Message: "template differs"})
+		}
+	}
+
+	fd, err := bp.diffFolders(ctx, s, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(diffs, fd...), nil
+}
+
+func (bp *Blueprint) diffFolders(ctx context.Context, s Store, prefix string) ([]Difference, error) {
+	var diffs []Difference
+
+	var entries []string
+	for _, f := range bp.Folders {
+		dir := path.Join(prefix, f.Path)
+
+		if f.Info != "" {
+			switch {
+			case !s.HasInfo(ctx, dir):
+				diffs = append(diffs, Difference{Path: dir, Message: "folder note is missing"})
+			default:
+				sec, err := s.GetInfo(ctx, dir)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read folder note of %s: %w", dir, err)
+				}
+				if strings.TrimSpace(string(sec.Bytes())) != strings.TrimSpace(f.Info) {
+					diffs = append(diffs, Difference{Path: dir, Message: "folder note differs"})
+				}
+			}
+		}
+
+		if len(f.Owners) > 0 {
+			have := s.FolderOwners(ctx, dir)
+			if !equalSets(have, f.Owners) {
+				diffs = append(diffs, Difference{Path: dir, Message: fmt.Sprintf("owners are %s instead of %s", list(have), list(f.Owners))})
+			}
+		}
+
+		if len(f.Schema.Required) < 1 {
+			continue
+		}
+
+		if entries == nil {
+			l, err := s.List(ctx, tree.INF)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list entries: %w", err)
+			}
+			entries = l
+		}
+
+		for _, name := range entries {
+			if !strings.HasPrefix(name, dir+"/") {
+				continue
+			}
+
+			sec, err := s.Get(ctx, name)
+			if err != nil {
+				diffs = append(diffs, Difference{Path: name, Message: fmt.Sprintf("can not be checked: %s", err)})
+
+				continue
+			}
+
+			for _, k := range f.Schema.Required {
+				if _, found := sec.Get(k); !found {
+					diffs = append(diffs, Difference{Path: name, Message: fmt.Sprintf("required key %s is missing", k)})
+				}
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// Apply creates the folder notes, owners and templates of the blueprint and
+// adds its recipients. Mounts are not created, they need to be initialized
+// first. It returns the number of changes.
+func (bp *Blueprint) Apply(ctx context.Context, s Writer, prefix string) (int, error) {
+	var n int
+
+	have := set.Map(s.ListRecipients(ctx, prefix))
+	for _, r := range bp.Recipients {
+		if have[r] {
+			continue
+		}
+
+		debug.Log("adding recipient %s to %q", r, prefix)
+		if err := s.AddRecipient(ctx, prefix, r); err != nil {
+			return n, fmt.Errorf("failed to add recipient %s: %w", r, err)
+		}
+		n++
+	}
+
+	for _, name := range set.SortedKeys(bp.Templates) {
+		tn := path.Join(prefix, name)
+		if content, err := s.GetTemplate(ctx, tn); err == nil && strings.TrimSpace(string(content)) == strings.TrimSpace(bp.Templates[name]) {
+			continue
+		}
+
+		if err := s.SetTemplate(ctx, tn, []byte(bp.Templates[name])); err != nil {
+			return n, fmt.Errorf("failed to write template %s: %w", tn, err)
+		}
+		n++
+	}
+
+	for _, f := range bp.Folders {
+		dir := path.Join(prefix, f.Path)
+
+		if f.Info != "" {
+			if sec, err := s.GetInfo(ctx, dir); err != nil || strings.TrimSpace(string(sec.Bytes())) != strings.TrimSpace(f.Info) {
+				if err := s.SetInfo(ctx, dir, secrets.ParseAKV([]byte(f.Info))); err != nil {
+					return n, fmt.Errorf("failed to write folder note of %s: %w", dir, err)
+				}
+				n++
+			}
+		}
+
+		if len(f.Owners) > 0 && !equalSets(s.FolderOwners(ctx, dir), f.Owners) {
+			if err := s.SetFolderOwners(ctx, dir, f.Owners); err != nil {
+				return n, fmt.Errorf("failed to write owners of %s: %w", dir, err)
+			}
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func equalSets(a, b []string) bool {
+	return list(a) == list(b)
+}
+
+func list(l []string) string {
+	if len(l) < 1 {
+		return "none"
+	}
+
+	return strings.Join(set.Sorted(l), ", ")
+}
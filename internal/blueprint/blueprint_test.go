@@ -0,0 +1,52 @@
+package blueprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	bp, err := Parse([]byte(`name: team
+recipients:
+  - 0xDEADBEEF
+folders:
+  - path: /web/
+    info: Credentials of the web servers
+    owners: [alice@example.org]
+    schema:
+      required: [username, url]
+templates:
+  web: "{{ .Content }}\nusername: \n"
+mounts:
+  - alias: ops
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "team", bp.Name)
+	assert.Equal(t, []string{"0xDEADBEEF"}, bp.Recipients)
+	assert.Equal(t, "web", bp.Folders[0].Path)
+	assert.Equal(t, []string{"username", "url"}, bp.Folders[0].Schema.Required)
+	assert.Equal(t, "ops", bp.Mounts[0].Alias)
+
+	for _, tc := range []struct {
+		name string
+		in   string
+	}{
+		{"no name", "folders:\n  - path: web\n"},
+		{"unknown field", "name: team\nfolder: web\n"},
+		{"empty path", "name: team\nfolders:\n  - path: /\n"},
+		{"duplicate folder", "name: team\nfolders:\n  - path: web\n  - path: web/\n"},
+		{"invalid alias", "name: team\nmounts:\n  - alias: ../foo\n"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tc.in))
+			assert.Error(t, err)
+		})
+	}
+}
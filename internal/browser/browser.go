@@ -0,0 +1,78 @@
+// Package browser opens URLs in the web browser of the user.
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+	shellquote "github.com/kballard/go-shellquote"
+)
+
+// Command returns the command used to open URLs. It can be set with
+// browser.command, otherwise the platform default is used.
+func Command(ctx context.Context) ([]string, error) {
+	if cmd := config.String(ctx, "browser.command"); cmd != "" {
+		args, err := shellquote.Split(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse browser.command %q: %w", cmd, err)
+		}
+
+		if len(args) > 0 {
+			return args, nil
+		}
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open"}, nil
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler"}, nil
+	default:
+		return []string{"xdg-open"}, nil
+	}
+}
+
+// Open opens the given URL in the browser. Only http and https URLs are
+// opened, everything else could be used to run arbitrary programs. URLs
+// without a scheme, e.g. example.org, are opened with https.
+func Open(ctx context.Context, u string) error {
+	u = strings.TrimSpace(u)
+	if u != "" && !strings.Contains(u, "://") {
+		u = "https://" + u
+	}
+
+	pu, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", u, err)
+	}
+
+	if pu.Scheme != "http" && pu.Scheme != "https" || pu.Host == "" {
+		return fmt.Errorf("refusing to open %q: only http and https URLs are supported", u)
+	}
+
+	args, err := Command(ctx)
+	if err != nil {
+		return err
+	}
+
+	debug.Log("opening %s with %v", pu.String(), args)
+
+	// not bound to ctx, the browser must outlive gopass.
+	cmd := exec.Command(args[0], append(args[1:], pu.String())...) //nolint:gosec
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	// the browser may keep running, don't wait for it.
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	return nil
+}
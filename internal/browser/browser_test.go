@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.SetEnv("browser.command", "true --"))
+	ctx := cfg.WithConfig(context.Background())
+
+	args, err := Command(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"true", "--"}, args)
+
+	for _, u := range []string{
+		"https://example.org/login",
+		"http://example.org",
+		"example.org",
+	} {
+		assert.NoError(t, Open(ctx, u), u)
+	}
+
+	for _, u := range []string{
+		"",
+		"file:///etc/passwd",
+		"javascript:alert(1)",
+		"ftp://example.org",
+	} {
+		assert.Error(t, Open(ctx, u), u)
+	}
+}
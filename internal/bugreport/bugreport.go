@@ -0,0 +1,150 @@
+// Package bugreport collects sanitized diagnostics into a single archive that
+// can be attached to issues.
+package bugreport
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// redacted replaces secret values, like in the debug log.
+const redacted = "*****"
+
+// reSecretKey matches config keys and environment variables that usually
+// hold secrets.
+var reSecretKey = regexp.MustCompile(`(?i)(token|password|passphrase|passwd|secret|api[_-]?key|credential)`)
+
+// reWebhookKey matches config keys that hold webhook URLs. The path of a
+// webhook URL usually is the secret, e.g. for Slack or Discord.
+var reWebhookKey = regexp.MustCompile(`(?i)webhook`)
+
+// Report is a set of text files. The content of every file is sanitized when
+// it's added.
+type Report struct {
+	files []file
+	now   time.Time
+}
+
+type file struct {
+	name    string
+	content string
+}
+
+// New creates an empty report.
+func New() *Report {
+	return &Report{
+		now: time.Now(),
+	}
+}
+
+// Add adds a file to the report.
+func (r *Report) Add(name, content string) {
+	r.files = append(r.files, file{name: name, content: Sanitize(content)})
+}
+
+// Names returns the names of all files in the report.
+func (r *Report) Names() []string {
+	names := make([]string, 0, len(r.files))
+	for _, f := range r.files {
+		names = append(names, f.name)
+	}
+
+	return names
+}
+
+// WriteZip writes all files to a zip archive.
+func (r *Report) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, f := range r.files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: r.now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", f.name, err)
+		}
+
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// Sanitize replaces the home directory with ~, so the report doesn't contain
+// the user name, and removes secrets known to the debug logger.
+func Sanitize(text string) string {
+	if home := appdir.UserHome(); len(home) > 1 {
+		text = strings.ReplaceAll(text, home, "~")
+	}
+
+	return debug.Scrub(text)
+}
+
+// Redact returns the value or a placeholder if the key usually holds a
+// secret, e.g. an API token. Credentials and query parameters are removed
+// from URLs, and so is the path of webhook URLs.
+func Redact(key, value string) string {
+	if value != "" && reSecretKey.MatchString(key) {
+		return redacted
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return value
+	}
+
+	// url.User would escape the placeholder.
+	hasUser := u.User != nil
+	u.User = nil
+	if u.RawQuery != "" {
+		u.RawQuery = redacted
+	}
+	if reWebhookKey.MatchString(key) && strings.Trim(u.Path, "/") != "" {
+		u.Path = "/" + redacted
+		u.RawPath = "/" + redacted
+	}
+
+	if hasUser {
+		return strings.Replace(u.String(), "://", "://"+redacted+"@", 1)
+	}
+
+	return u.String()
+}
+
+// Tail returns the last n lines of the file.
+func Tail(fn string, n int) (string, error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close() //nolint:errcheck
+
+	lines := make([]string, 0, n)
+	s := bufio.NewScanner(fh)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, s.Text())
+	}
+
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
@@ -3,24 +3,15 @@ package ghssh
 import (
 	"bufio"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/gopasspw/gopass/internal/network"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
-var httpClient = &http.Client{
-	Transport: &http.Transport{
-		TLSClientConfig: &tls.Config{
-			// enforce TLS 1.3
-			MinVersion: tls.VersionTLS13,
-		},
-	},
-}
-
 // ListKeys returns the public keys for a github user. It will
 // cache results up the a configurable amount of time (default: 6h).
 func (c *Cache) ListKeys(ctx context.Context, user string) ([]string, error) {
@@ -60,7 +51,7 @@ func (c *Cache) fetchKeys(ctx context.Context, user string) ([]string, error) {
 		return nil, err
 	}
 
-	resp, err := httpClient.Do(req)
+	resp, err := network.Do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
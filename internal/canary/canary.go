@@ -0,0 +1,64 @@
+// Package canary implements honeypot entries. Any read of an entry tagged as
+// canary triggers a local alert, an entry in the audit log and, if configured,
+// a webhook. This helps to detect compromised machines or curious teammates in
+// shared stores.
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/hook"
+	"github.com/gopasspw/gopass/internal/notify"
+	"github.com/gopasspw/gopass/internal/out"
+)
+
+// Tag marks an entry as canary.
+const Tag = "canary"
+
+// Event is the name of the audit log event.
+const Event = "canary"
+
+type contextKey int
+
+const ctxKeySuppressed contextKey = iota
+
+// WithSuppressed returns a context that does not trigger canaries. This is
+// used by operations that decrypt entries without revealing their content,
+// e.g. listing tagged entries or auditing passwords.
+func WithSuppressed(ctx context.Context, suppressed bool) context.Context {
+	return context.WithValue(ctx, ctxKeySuppressed, suppressed)
+}
+
+// IsSuppressed returns true if canaries must not be triggered.
+func IsSuppressed(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeySuppressed).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
+
+// Trigger raises the alarm for the given canary entry. It never fails the
+// read, errors are only reported.
+func Trigger(ctx context.Context, name string) {
+	if IsSuppressed(ctx) {
+		return
+	}
+
+	msg := fmt.Sprintf("Canary entry %q was accessed", name)
+	out.Warning(ctx, msg)
+	_ = notify.Notify(ctx, "gopass - canary", msg)
+
+	ev := auditlog.NewEvent(Event, name)
+	if err := auditlog.Write(ctx, ev); err != nil {
+		out.Errorf(ctx, "Failed to write audit log: %s", err)
+	}
+
+	if url := config.String(ctx, "canary.webhook"); url != "" {
+		hook.SendWebhook(ctx, "canary", url, config.String(ctx, "webhook.secret"), ev)
+	}
+}
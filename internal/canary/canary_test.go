@@ -0,0 +1,46 @@
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigger(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	var got auditlog.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "canary.webhook", srv.URL))
+	ctx := cfg.WithConfig(context.Background())
+
+	t.Run("suppressed", func(t *testing.T) {
+		Trigger(WithSuppressed(ctx, true), "foo/bar")
+		assert.NoFileExists(t, auditlog.Path())
+		assert.Equal(t, "", got.Entry)
+	})
+
+	t.Run("triggered", func(t *testing.T) {
+		Trigger(ctx, "foo/bar")
+		assert.Equal(t, "foo/bar", got.Entry)
+		assert.Equal(t, Event, got.Event)
+
+		buf, err := os.ReadFile(auditlog.Path())
+		require.NoError(t, err)
+		assert.Contains(t, string(buf), `"entry":"foo/bar"`)
+	})
+}
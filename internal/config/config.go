@@ -25,17 +25,20 @@ func newGitconfig() *gitconfig.Configs {
 }
 
 var defaults = map[string]string{
-	"core.autopush":      "true",
-	"core.autosync":      "true",
-	"core.cliptimeout":   "45",
-	"core.exportkeys":    "true",
-	"core.notifications": "true",
+	"core.autopush":        "true",
+	"core.autosync":        "true",
+	"core.cliptimeout":     "45",
+	"core.completionindex": "true",
+	"core.exportkeys":      "true",
+	"core.notifications":   "true",
 }
 
 // Config is a gopass config handler.
 type Config struct {
 	root *gitconfig.Configs
 	cfgs map[string]*gitconfig.Configs
+	// firstRun is true if there was no config before.
+	firstRun bool
 }
 
 // New initializes a new gopass config. It will handle legacy configs as well.
@@ -62,11 +65,13 @@ func newWithOptions(noWrites bool) *Config {
 		}
 	}
 
+	c.firstRun = !HasGlobalConfig()
+
 	// load the global config to get the root path
 	c.root = newGitconfig().LoadAll("")
 	c.root.NoWrites = noWrites
 
-	rootPath := c.root.Get("mounts.path")
+	rootPath := portableAbs(c.root.Get("mounts.path"))
 	if rootPath == "" {
 		if err := c.SetPath(PwStoreDir("")); err != nil {
 			debug.Log("failed to set path: %s", err)
@@ -85,6 +90,13 @@ func newWithOptions(noWrites bool) *Config {
 	// set global defaults
 	c.root.Preset = gitconfig.NewFromMap(defaults)
 
+	// the OS keychain is outside of the portable dir.
+	if Portable() != "" {
+		if err := c.SetEnv("age.usekeychain", "false"); err != nil {
+			debug.Log("failed to disable the keychain: %s", err)
+		}
+	}
+
 	for _, m := range c.Mounts() {
 		c.cfgs[m] = newGitconfig().LoadAll(c.MountPath(m))
 		c.cfgs[m].NoWrites = noWrites
@@ -98,6 +110,11 @@ func HasGlobalConfig() bool {
 	return newGitconfig().HasGlobalConfig()
 }
 
+// FirstRun returns true if there was no config when gopass was started.
+func (c *Config) FirstRun() bool {
+	return c.firstRun
+}
+
 // IsSet returns true if the key is set in the root config.
 func (c *Config) IsSet(key string) bool {
 	return c.root.IsSet(key)
@@ -165,6 +182,12 @@ func (c *Config) Set(mount, key, value string) error {
 		return c.root.SetLocal(key, value)
 	}
 
+	// mounts added after the config was loaded.
+	if _, found := c.cfgs[mount]; !found && c.MountPath(mount) != "" {
+		c.cfgs[mount] = newGitconfig().LoadAll(c.MountPath(mount))
+		c.cfgs[mount].NoWrites = c.root.NoWrites
+	}
+
 	if cfg := c.cfgs[mount]; cfg != nil {
 		return cfg.SetLocal(key, value)
 	}
@@ -179,22 +202,22 @@ func (c *Config) SetEnv(key, value string) error {
 
 // Path returns the root store path.
 func (c *Config) Path() string {
-	return c.Get("mounts.path")
+	return portableAbs(c.Get("mounts.path"))
 }
 
 // MountPath returns the mount store path.
 func (c *Config) MountPath(mountPoint string) string {
-	return c.Get(mpk(mountPoint))
+	return portableAbs(c.Get(mpk(mountPoint)))
 }
 
 // SetPath is a short cut to set the root store path.
 func (c *Config) SetPath(path string) error {
-	return c.Set("", "mounts.path", path)
+	return c.Set("", "mounts.path", portableRel(path))
 }
 
 // SetMountPath is a short cut to set a mount to a path.
 func (c *Config) SetMountPath(mount, path string) error {
-	return c.Set("", mpk(mount), path)
+	return c.Set("", mpk(mount), portableRel(path))
 }
 
 // mpk for mountPathKey.
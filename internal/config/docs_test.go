@@ -25,6 +25,7 @@ var ignoredEnvs = set.Map([]string{
 	"GOPASS_CONFIG_NOSYSTEM", // name assembled, tests can't catch it
 	"GOPASS_DEBUG_FILES",     // indirect usage
 	"GOPASS_DEBUG_FUNCS",     // indirect usage
+	"GOPASS_DEBUG_MODULES",   // indirect usage
 	"GOPASS_GPG_OPTS",        // indirect usage
 	"GOPASS_UMASK",           // indirect usage
 	"PASSWORD_STORE_UMASK",   // indirect usage
@@ -70,7 +71,7 @@ func TestConfigOptsInDocs(t *testing.T) {
 func usedOpts(t *testing.T) map[string]bool {
 	t.Helper()
 
-	optRE := regexp.MustCompile(`(?:\.Get(?:|Int|Bool)\(\"([a-z]+\.[a-z-]+)\"\)|\.GetM\([^,]+, \"([a-z]+\.[a-z-]+)\"\)|config\.(?:Bool|Int|String)\((?:ctx|c\.Context), \"([a-z]+\.[a-z-]+)\"\)|hook\.Invoke(?:Root)?\(ctx, \"([a-z]+\.[a-z-]+)\")`)
+	optRE := regexp.MustCompile(`(?:\.Get(?:|Int|Bool)\(\"([a-z]+\.[a-z-]+)\"\)|\.GetM\([^,]+, \"([a-z]+\.[a-z-]+)\"\)|config\.(?:Bool|Int|String)\((?:ctx|c\.Context), \"([a-z]+\.[a-z-]+)\"\)|hook\.Invoke(?:Root)?\(ctx, \"([a-z]+\.[a-z-]+)\")|const [a-zA-Z]+Key = \"([a-z]+\.[a-z-]+)\"`)
 	opts := make(map[string]bool, 42)
 
 	dir := filepath.Join("..", "..")
@@ -195,7 +196,9 @@ func TestEnvVarsInDocs(t *testing.T) {
 func usedEnvs(t *testing.T) map[string]bool {
 	t.Helper()
 
-	optRE := regexp.MustCompile(`os\.(?:Getenv|LookupEnv)\(\"([^"]+)\"\)`)
+	// variables can be named by an exported constant, e.g.
+	// pwgen.UnsafeDeterministicEnv.
+	optRE := regexp.MustCompile(`(?:os\.(?:Getenv|LookupEnv)\(|^const \w+Env = )\"([^"]+)\"`)
 	opts := make(map[string]bool, 42)
 
 	dir := filepath.Join("..", "..")
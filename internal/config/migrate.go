@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// migration upgrades the config to the next schema version.
+type migration struct {
+	// rename maps old keys to their current names.
+	rename map[string]string
+	// remove maps keys that are no longer supported to a hint for the user.
+	remove map[string]string
+}
+
+// migrations must only ever be appended to. The schema version of a config is
+// the number of migrations that have been applied to it.
+var migrations = []migration{
+	// 1: keys written by the migration of the YAML config before the keys
+	// were mapped explicitly.
+	{
+		rename: map[string]string{
+			"core.keychain":    "age.usekeychain",
+			"core.path":        "mounts.path",
+			"core.safecontent": "core.showsafecontent",
+		},
+		remove: map[string]string{
+			"core.parsing": "use gopass show --noparsing instead",
+		},
+	},
+}
+
+// Version returns the schema version of the config, i.e. the number of
+// migrations that have been applied to it.
+func (c *Config) Version() int {
+	v, err := strconv.Atoi(c.Get("core.configversion"))
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
+
+// Migrate upgrades old key names in all configs to the current ones and
+// records the new schema version. It returns a description of every change
+// so callers can tell the user what happened instead of silently ignoring
+// legacy settings.
+func (c *Config) Migrate() ([]string, error) {
+	from := c.Version()
+	if from >= len(migrations) {
+		return nil, nil
+	}
+
+	var changes []string
+
+	for i, m := range migrations[from:] {
+		debug.Log("migrating config to version %d", from+i+1)
+
+		for _, mount := range c.scopes() {
+			ch, err := c.migrateScope(mount, m)
+			if err != nil {
+				return changes, err
+			}
+			changes = append(changes, ch...)
+		}
+	}
+
+	if err := c.Set("", "core.configversion", strconv.Itoa(len(migrations))); err != nil {
+		return changes, fmt.Errorf("failed to record config version: %w", err)
+	}
+
+	return changes, nil
+}
+
+// scopes returns the names of all configs as understood by Set and Unset.
+func (c *Config) scopes() []string {
+	mounts := make([]string, 0, len(c.cfgs))
+	for m := range c.cfgs {
+		mounts = append(mounts, m)
+	}
+	sort.Strings(mounts)
+
+	return append([]string{"", "<root>"}, mounts...)
+}
+
+// getScope returns the value of key in exactly the given config, i.e. without
+// falling back to other configs.
+func (c *Config) getScope(mount, key string) string {
+	switch mount {
+	case "":
+		return c.root.GetGlobal(key)
+	case "<root>":
+		return c.root.GetLocal(key)
+	}
+
+	if cfg := c.cfgs[mount]; cfg != nil {
+		return cfg.GetLocal(key)
+	}
+
+	return ""
+}
+
+func (c *Config) migrateScope(mount string, m migration) ([]string, error) {
+	var changes []string
+
+	name := mount
+	switch name {
+	case "":
+		name = "user config"
+	case "<root>":
+		name = "root store config"
+	default:
+		name = fmt.Sprintf("config of %s", mount)
+	}
+
+	olds := make([]string, 0, len(m.rename))
+	for k := range m.rename {
+		olds = append(olds, k)
+	}
+	sort.Strings(olds)
+
+	for _, old := range olds {
+		v := c.getScope(mount, old)
+		if v == "" {
+			continue
+		}
+
+		nk := m.rename[old]
+		if c.getScope(mount, nk) == "" {
+			if err := c.Set(mount, nk, v); err != nil {
+				return changes, fmt.Errorf("failed to set %s in %s: %w", nk, name, err)
+			}
+			changes = append(changes, fmt.Sprintf("%s: renamed %s to %s", name, old, nk))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s: removed %s, %s is already set", name, old, nk))
+		}
+
+		if err := c.Unset(mount, old); err != nil {
+			return changes, fmt.Errorf("failed to remove %s from %s: %w", old, name, err)
+		}
+	}
+
+	removed := make([]string, 0, len(m.remove))
+	for k := range m.remove {
+		removed = append(removed, k)
+	}
+	sort.Strings(removed)
+
+	for _, old := range removed {
+		if c.getScope(mount, old) == "" {
+			continue
+		}
+
+		if err := c.Unset(mount, old); err != nil {
+			return changes, fmt.Errorf("failed to remove %s from %s: %w", old, name, err)
+		}
+		changes = append(changes, fmt.Sprintf("%s: removed %s, %s", name, old, m.remove[old]))
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	assert.NotNil(t, u)
+
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", td)
+
+	cfg := New()
+	require.NoError(t, cfg.Set("", "core.safecontent", "true"))
+	require.NoError(t, cfg.Set("", "core.keychain", "true"))
+	require.NoError(t, cfg.Set("", "age.usekeychain", "false"))
+	require.NoError(t, cfg.Set("", "core.parsing", "false"))
+	require.NoError(t, cfg.Set("<root>", "core.safecontent", "false"))
+	assert.Equal(t, 0, cfg.Version())
+
+	changes, err := cfg.Migrate()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"user config: removed core.keychain, age.usekeychain is already set",
+		"user config: renamed core.safecontent to core.showsafecontent",
+		"user config: removed core.parsing, use gopass show --noparsing instead",
+		"root store config: renamed core.safecontent to core.showsafecontent",
+	}, changes)
+
+	assert.Equal(t, "false", cfg.root.GetLocal("core.showsafecontent"))
+	assert.Equal(t, "", cfg.root.GetLocal("core.safecontent"))
+
+	// reload to make sure the changes were written.
+	cfg = New()
+	assert.Equal(t, len(migrations), cfg.Version())
+	assert.Equal(t, "true", cfg.root.GetGlobal("core.showsafecontent"))
+	assert.Equal(t, "false", cfg.Get("age.usekeychain"))
+	assert.False(t, cfg.IsSet("core.safecontent"))
+	assert.False(t, cfg.IsSet("core.keychain"))
+	assert.False(t, cfg.IsSet("core.parsing"))
+
+	// migrations only run once.
+	changes, err = cfg.Migrate()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Portable returns the directory gopass runs from in portable mode or an
+// empty string if portable mode is disabled. In portable mode config, cache,
+// stores and age identities all live below this directory, e.g. on a USB
+// stick, and store paths are recorded relative to it.
+func Portable() string {
+	return os.Getenv("GOPASS_PORTABLE")
+}
+
+// EnablePortable switches gopass to portable mode. It must be called before
+// the config is loaded. The settings are passed on through the environment,
+// so that child processes like hooks or the unclip helper stay inside the
+// portable directory, too.
+func EnablePortable(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve portable dir %q: %w", dir, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create portable dir %q: %w", dir, err)
+	}
+
+	env := map[string]string{
+		"GOPASS_PORTABLE": dir,
+		"GOPASS_HOMEDIR":  dir,
+	}
+	// an explicitly chosen GPG home is respected, but by default the keyring
+	// travels with the stores.
+	if os.Getenv("GNUPGHOME") == "" {
+		env["GNUPGHOME"] = filepath.Join(dir, ".gnupg")
+	}
+
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("failed to set %s: %w", k, err)
+		}
+	}
+
+	debug.Log("portable mode enabled in %s", dir)
+
+	return nil
+}
+
+// portableAbs resolves a path that was recorded relative to the portable dir.
+func portableAbs(path string) string {
+	pd := Portable()
+	if pd == "" || path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	return filepath.Join(pd, path)
+}
+
+// portableRel turns a path below the portable dir into one relative to it, so
+// the config stays valid wherever the portable dir is mounted.
+func portableRel(path string) string {
+	pd := Portable()
+	if pd == "" || !filepath.IsAbs(path) {
+		return path
+	}
+
+	rel, err := filepath.Rel(pd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnablePortable(t *testing.T) {
+	td := filepath.Join(t.TempDir(), "usb")
+	t.Setenv("GOPASS_PORTABLE", "")
+	t.Setenv("GOPASS_HOMEDIR", "")
+	t.Setenv("GNUPGHOME", "")
+
+	require.NoError(t, EnablePortable(td))
+	assert.DirExists(t, td)
+	assert.Equal(t, td, Portable())
+	assert.Equal(t, td, os.Getenv("GOPASS_HOMEDIR"))
+	assert.Equal(t, filepath.Join(td, ".gnupg"), os.Getenv("GNUPGHOME"))
+
+	t.Setenv("GNUPGHOME", "/foo/gnupg")
+	require.NoError(t, EnablePortable(td))
+	assert.Equal(t, "/foo/gnupg", os.Getenv("GNUPGHOME"))
+}
+
+func TestPortablePaths(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_PORTABLE", td)
+
+	for abs, rel := range map[string]string{
+		filepath.Join(td, "stores", "root"): "stores/root",
+		"/somewhere/else":                   "/somewhere/else",
+		td + "-other":                       td + "-other",
+		"~/.password-store":                 "~/.password-store",
+	} {
+		assert.Equal(t, rel, portableRel(abs), abs)
+		assert.Equal(t, abs, portableAbs(rel), rel)
+	}
+}
+
+func TestPortableConfig(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_PORTABLE", td)
+	t.Setenv("GOPASS_HOMEDIR", td)
+
+	cfg := New()
+	assert.Equal(t, filepath.Join(td, ".local", "share", "gopass", "stores", "root"), cfg.Path())
+	assert.Equal(t, ".local/share/gopass/stores/root", cfg.Get("mounts.path"))
+	assert.False(t, cfg.GetBool("age.usekeychain"))
+
+	sub := filepath.Join(td, "sub")
+	require.NoError(t, cfg.SetMountPath("sub", sub))
+	assert.Equal(t, "sub", cfg.Get("mounts.sub.path"))
+	assert.Equal(t, sub, cfg.MountPath("sub"))
+}
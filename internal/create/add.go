@@ -0,0 +1,180 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/hook"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+	"github.com/urfave/cli/v2"
+)
+
+// addPrefix is the folder new logins are added to. It's the same as the one
+// of the default website template, so add and create can be used
+// interchangeably.
+const addPrefix = "websites"
+
+// Add guides novices through adding a complete website login: URL,
+// username, a generated or typed password, an optional OTP seed and tags.
+// Unlike the templates used by create the steps are fixed, so every entry
+// ends up with the same, consistent layout.
+func Add(ctx context.Context, c *cli.Context, s *root.Store, cb ActionCallback) error { //nolint:cyclop
+	name := c.Args().First()
+	store := c.String("store")
+
+	if err := hook.Invoke(ctx, "create.pre-hook", name); err != nil {
+		return err
+	}
+
+	out.Print(ctx, "🧪 Adding a new login")
+
+	sec := secrets.New()
+
+	// 1. site
+	site, err := termio.AskForString(ctx, fmtfn(2, "1", "Website URL"), "")
+	if err != nil {
+		return err
+	}
+	hostname := extractHostname(site)
+	if hostname == "" {
+		return fmt.Errorf("can not parse URL %q", site)
+	}
+	_ = sec.Set("url", site)
+	if u := pwrules.LookupChangeURL(ctx, hostname); u != "" {
+		_ = sec.Set("password-change-url", u)
+	}
+
+	// 2. username
+	username, err := termio.AskForString(ctx, fmtfn(2, "2", "Username"), config.String(ctx, "create.default-username"))
+	if err != nil {
+		return err
+	}
+	if username != "" {
+		_ = sec.Set("username", username)
+	}
+
+	// 3. password
+	genPw, err := termio.AskForBool(ctx, fmtfn(2, "3", "Generate Password?"), true)
+	if err != nil {
+		return err
+	}
+
+	var password string
+	if genPw {
+		password, err = generatePassword(ctx, hostname, "")
+	} else {
+		password, err = termio.AskForPassword(ctx, "Password for the Website", true)
+	}
+	if err != nil {
+		return err
+	}
+	sec.SetPassword(password)
+
+	// 4. OTP
+	seed, err := termio.AskForString(ctx, fmtfn(2, "4", "OTP URL (otpauth://) or seed, empty to skip"), "")
+	if err != nil {
+		return err
+	}
+	if seed != "" {
+		k, v, err := otpField(seed)
+		if err != nil {
+			return err
+		}
+		_ = sec.Set(k, v)
+	}
+
+	// 5. tags
+	tags, err := termio.AskForString(ctx, fmtfn(2, "5", "Tags (comma separated)"), "")
+	if err != nil {
+		return err
+	}
+	if err := root.SetTags(sec, splitTags(tags)); err != nil {
+		return err
+	}
+
+	if name == "" {
+		if store == "" {
+			store = cui.AskForStore(ctx, s)
+		}
+		if store != "" {
+			store += "/"
+		}
+
+		name = store + addPrefix + "/" + hostname
+		if username != "" {
+			name += "/" + fsutil.CleanFilename(username)
+		}
+	}
+
+	if s.Exists(ctx, name) {
+		name, err = termio.AskForString(ctx, fmtfn(2, "6", "Secret already exists. Choose another path or enter to overwrite"), name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.Set(ctxutil.WithCommitMessage(ctx, "Added new login"), name, sec); err != nil {
+		return fmt.Errorf("failed to set %q: %w", name, err)
+	}
+	out.OKf(ctx, "Credentials saved to %q", name)
+
+	return cb(ctx, c, name, password, genPw)
+}
+
+// otpField validates a pasted OTP URL or bare TOTP seed and returns the key
+// and value to store it under, so `gopass otp` picks it up.
+func otpField(seed string) (string, string, error) {
+	seed = strings.TrimSpace(seed)
+
+	key, value := "totp", strings.ToUpper(strings.ReplaceAll(seed, " ", ""))
+	if strings.HasPrefix(seed, "otpauth://") {
+		key, value = "otpauth", seed
+	}
+
+	tmp := secrets.NewAKV()
+	_ = tmp.Set(key, value)
+
+	k, err := otp.Calculate("add", tmp)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid OTP seed: %w", err)
+	}
+
+	// the URL only has to parse, but the seed must be valid base32, too.
+	switch {
+	case k.Secret() == "":
+		err = fmt.Errorf("no secret")
+	case k.Type() == "hotp":
+		_, err = hotp.GenerateCode(k.Secret(), 0)
+	default:
+		_, err = totp.GenerateCode(k.Secret(), time.Now())
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("invalid OTP seed: %w", err)
+	}
+
+	return key, value, nil
+}
+
+func splitTags(in string) []string {
+	tags := make([]string, 0, strings.Count(in, ",")+1)
+	for _, tag := range strings.Split(in, ",") {
+		if tag := strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,35 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPField(t *testing.T) {
+	t.Parallel()
+
+	k, v, err := otpField("otpauth://totp/Example:alice@example.org?secret=JBSWY3DPEHPK3PXP&issuer=Example")
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", k)
+	assert.Equal(t, "otpauth://totp/Example:alice@example.org?secret=JBSWY3DPEHPK3PXP&issuer=Example", v)
+
+	k, v, err = otpField(" jbsw y3dp ehpk 3pxp ")
+	require.NoError(t, err)
+	assert.Equal(t, "totp", k)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", v)
+
+	_, _, err = otpField("not-base32!")
+	assert.Error(t, err)
+
+	_, _, err = otpField("otpauth://totp/foo")
+	assert.Error(t, err)
+}
+
+func TestSplitTags(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"work", "mail"}, splitTags(" work, ,mail ,"))
+	assert.Empty(t, splitTags(""))
+}
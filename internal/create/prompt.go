@@ -0,0 +1,143 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// DefaultPromptTemplate is used to ask for additional data if no wizard
+// template matches the name of an entry.
+var DefaultPromptTemplate = Template{
+	Name: "Default",
+	Attributes: []Attribute{
+		{Name: "username", Type: "string", Prompt: "Username"},
+		{Name: "url", Type: "string", Prompt: "URL"},
+		{Name: "comment", Type: "string", Prompt: "Comment"},
+		{Name: root.TagsKey, Type: "tags", Prompt: "Tags (comma separated)"},
+	},
+}
+
+// LookupTemplate returns the wizard template whose prefix matches the given
+// secret name best. It falls back to DefaultPromptTemplate. Unlike New it
+// does not write the default templates to the store.
+func LookupTemplate(ctx context.Context, s backend.Storage, name string) Template {
+	if s == nil {
+		return DefaultPromptTemplate
+	}
+
+	w := &Wizard{}
+	tpls, err := w.parseTemplates(ctx, s)
+	if err != nil {
+		debug.Log("failed to parse templates: %s", err)
+
+		return DefaultPromptTemplate
+	}
+
+	var best Template
+	for _, tpl := range tpls {
+		if tpl.Prefix == "" || len(tpl.Prefix) <= len(best.Prefix) {
+			continue
+		}
+		if matchesPrefix(name, tpl.Prefix) {
+			best = tpl
+		}
+	}
+
+	if best.Name == "" {
+		return DefaultPromptTemplate
+	}
+
+	debug.Log("using template %q for %s", best.Name, name)
+
+	return best
+}
+
+// matchesPrefix returns true if any leading path of name (ignoring
+// the mount point) equals the prefix.
+func matchesPrefix(name, prefix string) bool {
+	prefix = strings.Trim(prefix, "/")
+	for name != "" && name != "." && name != "/" {
+		if strings.HasSuffix(name, "/"+prefix) || name == prefix {
+			return true
+		}
+		name = path.Dir(name)
+	}
+
+	return false
+}
+
+// Prompt asks for all non-password attributes of the template and stores the
+// answers in the secret. Existing values are offered as defaults and empty
+// answers are skipped. Passwords are never asked for, so nothing sensitive is
+// echoed to the terminal.
+func (t Template) Prompt(ctx context.Context, sec gopass.Secret) error {
+	var step int
+	for _, v := range t.Attributes {
+		if v.Type == "password" {
+			continue
+		}
+
+		step++
+		k := v.Name
+		if v.Prompt == "" {
+			v.Prompt = strings.ToTitle(k)
+		}
+
+		def, _ := sec.Get(k)
+		if def == "" && k == "username" {
+			def = config.String(ctx, "create.default-username")
+		}
+		if v.Type == "tags" {
+			def = strings.Join(root.Tags(sec), ", ")
+		}
+
+		sv, err := termio.AskForString(ctx, fmtfn(2, strconv.Itoa(step), v.Prompt), def)
+		if err != nil {
+			return err
+		}
+		if sv == "" {
+			continue
+		}
+
+		if v.Min > 0 && len(sv) < v.Min {
+			return fmt.Errorf("%s is too short (needs %d)", v.Name, v.Min)
+		}
+		if v.Max > 0 && len(sv) > v.Max {
+			return fmt.Errorf("%s is too long (at most %d)", v.Name, v.Max)
+		}
+
+		switch v.Type {
+		case "tags":
+			if err := root.SetTags(sec, splitTags(sv)); err != nil {
+				return err
+			}
+
+			continue
+		case "hostname":
+			hostname := extractHostname(sv)
+			if hostname == "" {
+				return fmt.Errorf("can not parse URL %s", sv)
+			}
+			if u := pwrules.LookupChangeURL(ctx, hostname); u != "" {
+				_ = sec.Set("password-change-url", u)
+			}
+		}
+
+		if err := sec.Set(k, sv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
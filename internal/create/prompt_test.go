@@ -0,0 +1,62 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store/mockstore/inmem"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := inmem.New()
+	w := &Wizard{}
+	require.NoError(t, w.writeTemplates(ctx, s))
+
+	assert.Equal(t, "Website login", LookupTemplate(ctx, s, "websites/example.org/john").Name)
+	assert.Equal(t, "Website login", LookupTemplate(ctx, s, "work/websites/example.org/john").Name)
+	assert.Equal(t, "PIN Code (numerical)", LookupTemplate(ctx, s, "pin/bank/card").Name)
+	assert.Equal(t, DefaultPromptTemplate.Name, LookupTemplate(ctx, s, "misc/foo").Name)
+	assert.Equal(t, DefaultPromptTemplate.Name, LookupTemplate(ctx, s, "pinball").Name)
+	assert.Equal(t, DefaultPromptTemplate.Name, LookupTemplate(ctx, nil, "websites/foo").Name)
+}
+
+func TestPrompt(t *testing.T) { //nolint:paralleltest
+	ctx := ctxutil.WithInteractive(context.Background(), true)
+
+	termio.Stderr = &bytes.Buffer{}
+	defer func() {
+		termio.Stdin = os.Stdin
+		termio.Stderr = os.Stderr
+	}()
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("comment", "old"))
+
+	// username, url, comment (keep the default), tags.
+	termio.Stdin = strings.NewReader("john\nexample.org\n\nwork, mail ,\n")
+	require.NoError(t, DefaultPromptTemplate.Prompt(ctx, sec))
+
+	assert.Equal(t, "secret", sec.Password())
+	for k, v := range map[string]string{
+		"username": "john",
+		"url":      "example.org",
+		"comment":  "old",
+		"tags":     "mail, work",
+	} {
+		got, found := sec.Get(k)
+		assert.True(t, found, k)
+		assert.Equal(t, v, got, k)
+	}
+}
@@ -13,8 +13,14 @@ import (
 // GetSelection show a navigateable multiple-choice list to the user
 // and returns the selected entry along with the action.
 func GetSelection(ctx context.Context, prompt string, choices []string) (string, int) {
+	return GetSelectionDefault(ctx, prompt, choices, 0)
+}
+
+// GetSelectionDefault is like GetSelection but selects the choice at index
+// def if the user just presses enter.
+func GetSelectionDefault(ctx context.Context, prompt string, choices []string, def int) (string, int) {
 	if ctxutil.IsAlwaysYes(ctx) || !ctxutil.IsInteractive(ctx) {
-		return "impossible", 0
+		return "impossible", def
 	}
 
 	for i, c := range choices {
@@ -25,7 +31,7 @@ func GetSelection(ctx context.Context, prompt string, choices []string) (string,
 	var i int
 	for {
 		var err error
-		i, err = termio.AskForInt(ctx, prompt, 0)
+		i, err = termio.AskForInt(ctx, prompt, def)
 		if err == nil && i < len(choices) {
 			break
 		}
@@ -18,3 +18,14 @@ func TestGetSelection(t *testing.T) {
 	assert.Equal(t, "impossible", act)
 	assert.Equal(t, 0, sel)
 }
+
+func TestGetSelectionDefault(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, sel := GetSelectionDefault(ctx, "foo", []string{"foo", "bar"}, 1)
+	assert.Equal(t, "impossible", act)
+	assert.Equal(t, 1, sel)
+}
@@ -0,0 +1,93 @@
+// Package deadline limits how long a single operation may take, so a hung
+// gpg-agent or an unreachable remote doesn't block gopass forever. Operations
+// are grouped into classes with their own defaults. core.operation-timeout
+// changes the timeout of all classes and core.operation-timeout.<class> the
+// one of a single class. Timeouts are given in seconds, 0 disables them.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Classes of operations.
+const (
+	// Crypto are invocations of the crypto backend, e.g. gpg.
+	Crypto = "crypto"
+	// Git are local operations of the storage backend, e.g. git commit.
+	Git = "git"
+	// Network are operations that talk to a remote, e.g. git push.
+	Network = "network"
+	// Maintenance are local operations that rewrite or compact the whole
+	// repository, e.g. git gc. They may take much longer on large stores.
+	Maintenance = "maintenance"
+)
+
+// Defaults are the timeouts of each class unless configured otherwise.
+var Defaults = map[string]time.Duration{
+	Crypto:      time.Minute,
+	Git:         time.Minute,
+	Network:     5 * time.Minute,
+	Maintenance: 30 * time.Minute,
+}
+
+// For returns the timeout of the given class. It returns 0 if the class has no
+// timeout.
+func For(ctx context.Context, class string) time.Duration {
+	if d, found := parse("core.operation-timeout."+class, config.String(ctx, "core.operation-timeout."+class)); found {
+		return d
+	}
+
+	if d, found := parse("core.operation-timeout", config.String(ctx, "core.operation-timeout")); found {
+		return d
+	}
+
+	return Defaults[class]
+}
+
+// parse parses a timeout in seconds. It returns false if the value is unset
+// or invalid.
+func parse(key, sv string) (time.Duration, bool) {
+	sv = strings.TrimSpace(sv)
+	if sv == "" {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(sv)
+	if err != nil || n < 0 {
+		debug.Log("invalid value for %s: %q", key, sv)
+
+		return 0, false
+	}
+
+	return time.Duration(n) * time.Second, true
+}
+
+// With returns a context that is cancelled after the timeout of the given
+// class or when the parent is cancelled, e.g. by Ctrl-C. If the parent
+// already has a deadline it is kept, e.g. a git clone is a network operation
+// even though it's run like any other git command.
+func With(ctx context.Context, class string) (context.Context, context.CancelFunc) {
+	d := For(ctx, class)
+	if _, found := ctx.Deadline(); found || d <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, d)
+}
+
+// Interrupted returns true if err was caused by the operation being
+// cancelled or timing out.
+func Interrupted(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	return err != nil && ctx.Err() != nil
+}
@@ -0,0 +1,65 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	assert.Equal(t, time.Minute, For(ctx, Crypto))
+	assert.Equal(t, 5*time.Minute, For(ctx, Network))
+	assert.Equal(t, 30*time.Minute, For(ctx, Maintenance))
+
+	require.NoError(t, cfg.SetEnv("core.operation-timeout", "10"))
+	assert.Equal(t, 10*time.Second, For(ctx, Crypto))
+	assert.Equal(t, 10*time.Second, For(ctx, Network))
+
+	require.NoError(t, cfg.SetEnv("core.operation-timeout.network", "0"))
+	assert.Equal(t, time.Duration(0), For(ctx, Network))
+	assert.Equal(t, 10*time.Second, For(ctx, Git))
+
+	require.NoError(t, cfg.SetEnv("core.operation-timeout.git", "soon"))
+	assert.Equal(t, 10*time.Second, For(ctx, Git))
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+	require.NoError(t, cfg.SetEnv("core.operation-timeout.network", "0"))
+
+	cctx, cancel := With(ctx, Crypto)
+	defer cancel()
+	d, found := cctx.Deadline()
+	assert.True(t, found)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), d, 5*time.Second)
+
+	// the deadline of the outer operation is kept.
+	nctx, ncancel := With(cctx, Git)
+	defer ncancel()
+	nd, _ := nctx.Deadline()
+	assert.Equal(t, d, nd)
+
+	// no timeout.
+	uctx, ucancel := With(ctx, Network)
+	defer ucancel()
+	_, found = uctx.Deadline()
+	assert.False(t, found)
+
+	ucancel()
+	assert.True(t, Interrupted(uctx, errors.New("signal: killed")))
+	assert.False(t, Interrupted(ctx, errors.New("exit status 1")))
+	assert.True(t, Interrupted(ctx, context.DeadlineExceeded))
+}
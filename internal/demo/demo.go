@@ -0,0 +1,94 @@
+// Package demo replaces entry names and secrets with generated placeholders
+// so the output of gopass can be shown in documentation, bug reports and
+// screen recordings without leaking the structure or content of a store.
+package demo
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+)
+
+// maxTries is the number of random words tried before a counter is added to
+// make a placeholder unique within its folder.
+const maxTries = 16
+
+var (
+	mu sync.Mutex
+	// names maps real names to their placeholders. The placeholders are
+	// random, but stable within a process, so list, show and the selection
+	// dialogs agree on them.
+	names = map[string]string{}
+	// used are the placeholders in use, to keep siblings apart.
+	used = map[string]bool{}
+)
+
+// Name returns the placeholder for the entry or folder name. Every segment
+// is replaced by a random word, so the depth of the name is kept but nothing
+// else.
+func Name(name string) string {
+	name = strings.Trim(name, "/")
+	if name == "" {
+		return ""
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return placeholder(name)
+}
+
+func placeholder(name string) string {
+	if p, found := names[name]; found {
+		return p
+	}
+
+	parent, _ := path.Split(name)
+	if parent != "" {
+		parent = placeholder(strings.TrimSuffix(parent, "/")) + "/"
+	}
+
+	p := parent + pwgen.RandomWord()
+	for i := 1; used[p]; i++ {
+		p = parent + pwgen.RandomWord()
+		if i >= maxTries {
+			p = fmt.Sprintf("%s%s-%d", parent, pwgen.RandomWord(), i)
+		}
+	}
+
+	names[name] = p
+	used[p] = true
+
+	return p
+}
+
+// Secret returns a copy of the secret with a generated password and
+// placeholders for all values and the body. Only the keys are kept.
+func Secret(sec gopass.Secret) gopass.Secret {
+	d := secrets.NewAKV()
+	if sec.Password() != "" {
+		d.SetPassword(pwgen.GeneratePassword(16, false))
+	}
+
+	for _, k := range sec.Keys() {
+		vs, _ := sec.Values(k)
+		for range vs {
+			_ = d.Add(k, pwgen.RandomWord())
+		}
+	}
+
+	if strings.TrimSpace(sec.Body()) != "" {
+		words := make([]string, 0, 4)
+		for i := 0; i < 4; i++ {
+			words = append(words, pwgen.RandomWord())
+		}
+		_, _ = d.Write([]byte(strings.Join(words, " ") + "\n"))
+	}
+
+	return d
+}
@@ -0,0 +1,52 @@
+package demo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", Name(""))
+
+	a := Name("work/db/prod")
+	assert.NotContains(t, a, "work")
+	assert.Len(t, strings.Split(a, "/"), 3)
+	assert.Equal(t, a, Name("work/db/prod"), "placeholders are stable")
+	assert.Equal(t, a, Name("/work/db/prod/"))
+
+	b := Name("work/db/staging")
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, strings.Split(a, "/")[:2], strings.Split(b, "/")[:2], "siblings share the parent")
+	assert.Equal(t, strings.Join(strings.Split(a, "/")[:2], "/"), Name("work/db"))
+}
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("hunter2")
+	_ = sec.Set("username", "alice")
+	_ = sec.Add("url", "https://a.example.org")
+	_ = sec.Add("url", "https://b.example.org")
+	_, _ = sec.Write([]byte("the pin is 1234\n"))
+
+	d := Secret(sec)
+	assert.NotEqual(t, "hunter2", d.Password())
+	assert.Len(t, d.Password(), 16)
+	assert.Equal(t, sec.Keys(), d.Keys())
+
+	urls, _ := d.Values("url")
+	assert.Len(t, urls, 2)
+
+	buf := string(d.Bytes())
+	for _, s := range []string{"hunter2", "alice", "example.org", "1234"} {
+		assert.NotContains(t, buf, s)
+	}
+
+	assert.Equal(t, "", Secret(secrets.NewAKV()).Password())
+}
@@ -11,8 +11,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/sandbox"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/tempfile"
@@ -40,6 +42,8 @@ func Invoke(ctx context.Context, editor string, content []byte) ([]byte, error)
 	}
 
 	defer func() {
+		removeDroppings(ctx, tmpfile.Name())
+
 		if err := tmpfile.Remove(ctx); err != nil {
 			color.Red("Failed to remove tempfile at %s: %s", tmpfile.Name(), err)
 		}
@@ -62,22 +66,32 @@ func Invoke(ctx context.Context, editor string, content []byte) ([]byte, error)
 
 		editor = cmdArgs[0]
 		args = append(args, cmdArgs[1:]...)
-		args = append(args, vimOptions(resolveEditor(editor))...)
+
+		opts, err := editorOptions(ctx, editor)
+		if err != nil {
+			return []byte{}, err
+		}
+		args = append(args, opts...)
 	}
 
 	args = append(args, tmpfile.Name())
 
-	cmd := exec.Command(editor, args...)
+	cmd := sandbox.Command(ctx, editor, args...)
 	cmd.Stdin = Stdin
 	cmd.Stdout = Stdout
 	cmd.Stderr = Stderr
 
+	start := time.Now()
 	if err := cmd.Run(); err != nil {
 		debug.Log("cmd: %s %+v - error: %+v", cmd.Path, cmd.Args, err)
 
 		return []byte{}, fmt.Errorf("failed to run %s with %s file: %w", editor, tmpfile.Name(), err)
 	}
 
+	if err := verifyWrite(ctx, editor, tmpfile.Name(), start, content); err != nil {
+		return []byte{}, err
+	}
+
 	nContent, err := os.ReadFile(tmpfile.Name())
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to read from tmpfile: %w", err)
@@ -91,7 +105,7 @@ func Invoke(ctx context.Context, editor string, content []byte) ([]byte, error)
 }
 
 func vimOptions(editor string) []string {
-	if editor != "vi" && editor != "vim" && editor != "neovim" {
+	if editor != "vi" && editor != "vim" && editor != "neovim" && editor != "nvim" {
 		debug.Log("Editor %s is not known to be vim compatible", editor)
 
 		return []string{}
@@ -108,7 +122,7 @@ func vimOptions(editor string) []string {
 		path = "/private/**/gopass**"
 	}
 	viminfo := `viminfo=""`
-	if editor == "neovim" {
+	if editor == "neovim" || editor == "nvim" {
 		viminfo = `shada=""`
 	}
 
@@ -124,7 +138,7 @@ func vimOptions(editor string) []string {
 
 // isVim tries to identify the vi variant as vim compatible or not.
 func isVim(editor string) bool {
-	if editor == "neovim" {
+	if editor == "neovim" || editor == "nvim" {
 		return true
 	}
 
@@ -0,0 +1,173 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// shredRuns is the number of passes used to remove editor droppings.
+const shredRuns = 8
+
+// quickExit is the time under which an editor that didn't change the
+// content likely forked into the background.
+var quickExit = time.Second
+
+// editorOptions returns the flags that keep the editor from leaking the
+// plaintext to swap, backup or undo files. With edit.harden unknown editors
+// are rejected.
+func editorOptions(ctx context.Context, editor string) ([]string, error) {
+	name := resolveEditor(editor)
+
+	if !config.Bool(ctx, "edit.harden") {
+		return vimOptions(name), nil
+	}
+
+	opts, found := hardenedOptions(editor, name)
+	if !found {
+		return nil, fmt.Errorf("refusing to start %q: edit.harden only allows vim, neovim, emacs and nano", editor)
+	}
+
+	return opts, nil
+}
+
+// hardenedOptions returns the flags to disable all files an editor might
+// write next to the edited file. cmd is the command as given, name the base
+// name of the resolved binary.
+func hardenedOptions(cmd, name string) ([]string, bool) {
+	// e.g. vim.basic or nvim.appimage.
+	name, _, _ = strings.Cut(name, ".")
+
+	vimSet := []string{"-n", "-i", "NONE", "-c", "set nobackup nowritebackup noundofile noswapfile"}
+
+	switch name {
+	case "vi", "vim":
+		// vi might be nvi or busybox vi which don't support these flags.
+		if !isVim(cmd) {
+			return nil, false
+		}
+
+		return vimSet, true
+	case "nvim", "neovim":
+		return vimSet, true
+	case "emacs":
+		return []string{"--eval", "(setq make-backup-files nil auto-save-default nil create-lockfiles nil)"}, true
+	case "nano":
+		// nano only writes backups if configured to.
+		return []string{"--ignorercfiles"}, true
+	default:
+		return nil, false
+	}
+}
+
+// verifyWrite makes sure the editor left a regular file behind. It warns if
+// the editor returned immediately without changing anything, which usually
+// means that it forked into the background.
+func verifyWrite(ctx context.Context, editor, fn string, start time.Time, content []byte) error {
+	fi, err := os.Lstat(fn)
+	if err != nil {
+		return fmt.Errorf("%s removed the file: %w", editor, err)
+	}
+
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%s replaced the file with a %s", editor, fi.Mode().Type())
+	}
+
+	if time.Since(start) > quickExit {
+		return nil
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err == nil && bytes.Equal(buf, content) {
+		out.Warningf(ctx, "%s returned immediately without any changes. Graphical editors must wait until the file is closed, e.g. 'code --wait'", editor)
+	}
+
+	return nil
+}
+
+// removeDroppings securely removes swap, backup and undo files the editor left
+// behind for the temporary file fn.
+func removeDroppings(ctx context.Context, fn string) {
+	for _, d := range findDroppings(fn) {
+		out.Warningf(ctx, "Removing editor file %s. It might contain your secret. Consider setting edit.harden", d)
+
+		if err := fsutil.Shred(d, shredRuns); err != nil {
+			out.Errorf(ctx, "Failed to remove %s: %s", d, err)
+		}
+	}
+}
+
+// findDroppings looks for editor files of fn. Editors either put them next to
+// the file, i.e. into our temporary directory, or into their state directories
+// with the full path of the file encoded in the name.
+func findDroppings(fn string) []string {
+	dir := filepath.Dir(fn)
+	var res []string
+
+	// everything besides the file itself is a dropping.
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, e := range entries {
+			if p := filepath.Join(dir, e.Name()); p != fn {
+				res = append(res, p)
+			}
+		}
+	}
+
+	// vim uses the full path with slashes replaced by % for files in
+	// directories ending with //, e.g. %dev%shm%gopass-edit123%secret.swp.
+	// Other editors use at least the name of the temporary directory.
+	tag := filepath.Base(dir)
+	for _, sd := range stateDirs() {
+		entries, err := os.ReadDir(sd)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			if e.Type().IsRegular() && strings.Contains(e.Name(), tag) {
+				res = append(res, filepath.Join(sd, e.Name()))
+			}
+		}
+	}
+
+	debug.Log("found editor droppings for %s: %q", fn, res)
+
+	return res
+}
+
+// stateDirs returns the directories vim, neovim and emacs use for swap, backup
+// and undo files by default or in common configurations.
+func stateDirs() []string {
+	home := appdir.UserHome()
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		state = filepath.Join(home, ".local", "state")
+	}
+
+	dirs := []string{
+		os.TempDir(),
+		"/var/tmp",
+		filepath.Join(home, "tmp"),
+		filepath.Join(home, ".emacs.d", "auto-save-list"),
+		filepath.Join(home, ".emacs.d", "backups"),
+	}
+	for _, sub := range []string{"swap", "backup", "undo", "tmp"} {
+		dirs = append(dirs,
+			filepath.Join(home, ".vim", sub),
+			filepath.Join(state, "nvim", sub),
+			filepath.Join(state, "vim", sub),
+		)
+	}
+
+	return dirs
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDroppings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", home)
+	t.Setenv("XDG_STATE_HOME", "")
+
+	td := filepath.Join(t.TempDir(), "gopass-edit1234")
+	require.NoError(t, os.MkdirAll(td, 0o700))
+	fn := filepath.Join(td, "secret")
+	require.NoError(t, os.WriteFile(fn, []byte("foo"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(td, ".secret.swp"), []byte("foo"), 0o600))
+
+	swap := filepath.Join(home, ".local", "state", "nvim", "swap")
+	require.NoError(t, os.MkdirAll(swap, 0o700))
+	nvimSwap := filepath.Join(swap, "%tmp%gopass-edit1234%secret.swp")
+	require.NoError(t, os.WriteFile(nvimSwap, []byte("foo"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(swap, "%home%notes.txt.swp"), []byte("foo"), 0o600))
+
+	assert.Equal(t, []string{filepath.Join(td, ".secret.swp"), nvimSwap}, findDroppings(fn))
+
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() {
+		out.Stderr = os.Stderr
+	}()
+
+	removeDroppings(context.Background(), fn)
+	assert.NoFileExists(t, nvimSwap)
+	assert.FileExists(t, fn)
+	assert.Empty(t, findDroppings(fn))
+}
+
+func TestHardenedOptions(t *testing.T) {
+	t.Parallel()
+
+	opts, found := hardenedOptions("nvim", "nvim.appimage")
+	assert.True(t, found)
+	assert.Contains(t, opts, "-n")
+
+	_, found = hardenedOptions("emacs", "emacs")
+	assert.True(t, found)
+
+	_, found = hardenedOptions("gedit", "gedit")
+	assert.False(t, found)
+}
+
+func TestEditorOptions(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	opts, err := editorOptions(ctx, "true")
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	require.NoError(t, cfg.Set("", "edit.harden", "true"))
+	_, err = editorOptions(ctx, "true")
+	assert.Error(t, err)
+}
+
+func TestVerifyWrite(t *testing.T) {
+	ctx := context.Background()
+	fn := filepath.Join(t.TempDir(), "secret")
+
+	assert.Error(t, verifyWrite(ctx, "ed", fn, time.Now(), nil))
+
+	require.NoError(t, os.WriteFile(fn, []byte("foo"), 0o600))
+
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() {
+		out.Stderr = os.Stderr
+	}()
+
+	require.NoError(t, verifyWrite(ctx, "ed", fn, time.Now(), []byte("foo")))
+	assert.Contains(t, buf.String(), "returned immediately")
+}
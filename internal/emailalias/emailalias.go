@@ -0,0 +1,245 @@
+// Package emailalias requests fresh email aliases from an aliasing service,
+// e.g. SimpleLogin or addy.io (formerly AnonAddy), so every account can use
+// its own address. The service is configured with alias-service.provider and
+// alias-service.url, the API token is kept in an entry of the store.
+package emailalias
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Supported providers.
+const (
+	SimpleLogin = "simplelogin"
+	Addy        = "addy"
+)
+
+// DefaultURLs are the API endpoints of the hosted services. Self-hosted
+// instances are configured with alias-service.url.
+var DefaultURLs = map[string]string{
+	SimpleLogin: "https://app.simplelogin.io",
+	Addy:        "https://app.addy.io",
+}
+
+// maxSize limits the size of the responses.
+const maxSize = 1 << 20
+
+// ErrNotConfigured is returned if no provider is configured.
+var ErrNotConfigured = errors.New("no email alias service configured. Run 'gopass alias-service setup' first")
+
+// Providers returns the supported providers.
+func Providers() []string {
+	return []string{SimpleLogin, Addy}
+}
+
+// Provider returns the configured provider. The old name of addy.io is
+// accepted as well.
+func Provider(ctx context.Context) string {
+	p := strings.ToLower(strings.TrimSpace(config.String(ctx, "alias-service.provider")))
+	if p == "anonaddy" {
+		return Addy
+	}
+
+	return p
+}
+
+// URL returns the API endpoint of the configured provider.
+func URL(ctx context.Context) string {
+	if u := config.String(ctx, "alias-service.url"); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+
+	return DefaultURLs[Provider(ctx)]
+}
+
+// Alias is an email alias created by the service.
+type Alias struct {
+	Email string
+	// ID identifies the alias in the API of the service.
+	ID string
+}
+
+// New requests a new alias from the configured provider. The note is shown
+// next to the alias in the web interface of the service. It may be empty.
+func New(ctx context.Context, token, note string) (Alias, error) {
+	if err := check(ctx, token); err != nil {
+		return Alias{}, err
+	}
+
+	var alias Alias
+	var err error
+
+	switch p := Provider(ctx); p {
+	case SimpleLogin:
+		alias, err = newSimpleLogin(ctx, token, note)
+	case Addy:
+		alias, err = newAddy(ctx, token, note)
+	}
+
+	if err != nil {
+		return Alias{}, err
+	}
+
+	if alias.Email == "" {
+		return Alias{}, fmt.Errorf("the email alias service returned no alias")
+	}
+
+	return alias, nil
+}
+
+// Delete removes an alias, e.g. because the entry it was created for could
+// not be saved.
+func Delete(ctx context.Context, token string, alias Alias) error {
+	if err := check(ctx, token); err != nil {
+		return err
+	}
+
+	if alias.ID == "" {
+		return fmt.Errorf("the alias %s has no id", alias.Email)
+	}
+
+	u := URL(ctx) + "/api/aliases/" + url.PathEscape(alias.ID)
+	if Provider(ctx) == Addy {
+		u = URL(ctx) + "/api/v1/aliases/" + url.PathEscape(alias.ID)
+	}
+
+	return do(ctx, http.MethodDelete, u, header(ctx, token), nil, nil)
+}
+
+// check makes sure the service can be used.
+func check(ctx context.Context, token string) error {
+	if network.Offline(ctx) {
+		return network.ErrOffline
+	}
+
+	if token == "" {
+		return fmt.Errorf("no API token for the email alias service")
+	}
+
+	switch p := Provider(ctx); p {
+	case "":
+		return ErrNotConfigured
+	case SimpleLogin, Addy:
+		return nil
+	default:
+		return fmt.Errorf("unknown email alias service %q. Supported services: %s", p, strings.Join(Providers(), ", "))
+	}
+}
+
+// header returns the authentication headers of the configured provider.
+func header(ctx context.Context, token string) http.Header {
+	if Provider(ctx) == SimpleLogin {
+		return http.Header{"Authentication": []string{token}}
+	}
+
+	return http.Header{
+		"Authorization":    []string{"Bearer " + token},
+		"X-Requested-With": []string{"XMLHttpRequest"},
+	}
+}
+
+func newSimpleLogin(ctx context.Context, token, note string) (Alias, error) {
+	var resp struct {
+		ID    int64  `json:"id"`
+		Email string `json:"email"`
+	}
+
+	body := map[string]string{}
+	if note != "" {
+		body["note"] = note
+	}
+
+	if err := do(ctx, http.MethodPost, URL(ctx)+"/api/alias/random/new", header(ctx, token), body, &resp); err != nil {
+		return Alias{}, err
+	}
+
+	return Alias{Email: resp.Email, ID: strconv.FormatInt(resp.ID, 10)}, nil
+}
+
+func newAddy(ctx context.Context, token, note string) (Alias, error) {
+	var resp struct {
+		Data struct {
+			ID    string `json:"id"`
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+
+	body := map[string]string{}
+	if note != "" {
+		body["description"] = note
+	}
+	if d := config.String(ctx, "alias-service.domain"); d != "" {
+		body["domain"] = d
+	}
+
+	if err := do(ctx, http.MethodPost, URL(ctx)+"/api/v1/aliases", header(ctx, token), body, &resp); err != nil {
+		return Alias{}, err
+	}
+
+	return Alias{Email: resp.Data.Email, ID: resp.Data.ID}, nil
+}
+
+// do sends the JSON body to the URL and decodes the JSON response into v.
+// The body and v may be nil.
+func do(ctx context.Context, method, u string, hdr http.Header, body, v any) error {
+	var rd io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	req.Header = hdr
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := network.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	rbuf, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		debug.Log("alias service returned %s: %s", resp.Status, string(rbuf))
+
+		return fmt.Errorf("email alias service returned %s", resp.Status)
+	}
+
+	debug.Log("%s %s", method, req.URL.Redacted())
+
+	if v == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(rbuf, v); err != nil {
+		return fmt.Errorf("failed to decode the response of the email alias service: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package emailalias
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	deleted := ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = r.URL.Path
+
+			return
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/alias/random/new":
+			if r.Header.Get("Authentication") != "sl-token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+			fmt.Fprintf(w, `{"id": 42, "email": "%s.abc@simplelogin.com"}`, body["note"])
+		case "/api/v1/aliases":
+			if r.Header.Get("Authorization") != "Bearer addy-token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+			fmt.Fprintf(w, `{"data": {"id": "5b7c", "email": "%s@%s"}}`, body["description"], body["domain"])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	_, err := New(ctx, "sl-token", "forum")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	require.NoError(t, cfg.SetEnv("alias-service.url", ts.URL+"/"))
+
+	t.Run("simplelogin", func(t *testing.T) {
+		require.NoError(t, cfg.SetEnv("alias-service.provider", "SimpleLogin"))
+
+		alias, err := New(ctx, "sl-token", "forum")
+		require.NoError(t, err)
+		assert.Equal(t, Alias{Email: "forum.abc@simplelogin.com", ID: "42"}, alias)
+
+		require.NoError(t, Delete(ctx, "sl-token", alias))
+		assert.Equal(t, "/api/aliases/42", deleted)
+
+		_, err = New(ctx, "wrong", "forum")
+		assert.Error(t, err)
+		_, err = New(ctx, "", "forum")
+		assert.Error(t, err)
+	})
+
+	t.Run("addy", func(t *testing.T) {
+		require.NoError(t, cfg.SetEnv("alias-service.provider", "anonaddy"))
+		require.NoError(t, cfg.SetEnv("alias-service.domain", "anonaddy.me"))
+
+		alias, err := New(ctx, "addy-token", "forum")
+		require.NoError(t, err)
+		assert.Equal(t, Alias{Email: "forum@anonaddy.me", ID: "5b7c"}, alias)
+
+		require.NoError(t, Delete(ctx, "addy-token", alias))
+		assert.Equal(t, "/api/v1/aliases/5b7c", deleted)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		require.NoError(t, cfg.SetEnv("alias-service.provider", "mailinator"))
+
+		_, err := New(ctx, "token", "forum")
+		assert.Error(t, err)
+	})
+}
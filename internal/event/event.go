@@ -0,0 +1,155 @@
+// Package event implements an in-process event bus. The store and the
+// commands publish what happened, e.g. an entry was written or the
+// recipients changed, and webhooks, notifications and users of the
+// pkg/gopass API subscribe to it instead of being called from every place
+// that triggers them.
+//
+// The bus travels in the context. Publishing without a bus is a no-op.
+package event
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// Events published by the store.
+const (
+	// EntryWritten is published whenever an entry is written.
+	EntryWritten = gopass.EventEntryWritten
+	// EntryDeleted is published when entries are removed. A recursive
+	// delete publishes one event with all removed entries.
+	EntryDeleted = gopass.EventEntryDeleted
+	// RecipientsChanged is published when recipients are added to or
+	// removed from a store or folder.
+	RecipientsChanged = gopass.EventRecipientsChanged
+)
+
+// Events published by the commands.
+const (
+	// EntryCreated is published when a new entry was created by the user.
+	EntryCreated = gopass.EventEntryCreated
+	// EntryRotated is published when the password of an entry changed.
+	EntryRotated = gopass.EventEntryRotated
+	// FsckFailed is published when fsck found errors.
+	FsckFailed = gopass.EventFsckFailed
+	// SyncCompleted is published when gopass sync finished.
+	SyncCompleted = gopass.EventSyncCompleted
+)
+
+// Event is something that happened to a store. It's part of the public API,
+// see gopass.Watcher.
+type Event = gopass.Event
+
+// Handler is called for every event a subscriber is interested in. It runs
+// synchronously in the goroutine of the publisher, so it must not block for
+// long.
+type Handler func(ctx context.Context, ev Event)
+
+type subscription struct {
+	types   map[string]bool
+	handler Handler
+}
+
+// Bus delivers events to the subscribers.
+type Bus struct {
+	mu   sync.RWMutex
+	next int
+	subs map[int]subscription
+}
+
+// New creates an empty bus.
+func New() *Bus {
+	return &Bus{
+		subs: map[int]subscription{},
+	}
+}
+
+// Subscribe registers the handler for the given event types, or all events
+// if none are given. The returned function removes the subscription.
+func (b *Bus) Subscribe(h Handler, types ...string) func() {
+	sub := subscription{
+		handler: h,
+	}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.next
+	b.next++
+	b.subs[id] = sub
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subs, id)
+	}
+}
+
+// Publish delivers the event to all interested subscribers in the order they
+// subscribed.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	ids := make([]int, 0, len(b.subs))
+	for id, sub := range b.subs {
+		if sub.types == nil || sub.types[ev.Type] {
+			ids = append(ids, id)
+		}
+	}
+	subs := make([]Handler, 0, len(ids))
+	sort.Ints(ids)
+	for _, id := range ids {
+		subs = append(subs, b.subs[id].handler)
+	}
+	b.mu.RUnlock()
+
+	debug.Log("publishing %s to %d subscribers", ev.Type, len(subs))
+
+	for _, h := range subs {
+		h(ctx, ev)
+	}
+}
+
+type contextKey int
+
+const ctxKeyBus contextKey = iota
+
+// WithBus returns a context with the bus set.
+func WithBus(ctx context.Context, b *Bus) context.Context {
+	return context.WithValue(ctx, ctxKeyBus, b)
+}
+
+// FromContext returns the bus of the context or nil.
+func FromContext(ctx context.Context) *Bus {
+	b, ok := ctx.Value(ctxKeyBus).(*Bus)
+	if !ok {
+		return nil
+	}
+
+	return b
+}
+
+// Publish delivers the event to the bus of the context, if any.
+func Publish(ctx context.Context, ev Event) {
+	b := FromContext(ctx)
+	if b == nil {
+		return
+	}
+
+	b.Publish(ctx, ev)
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := New()
+
+	var all, written []string
+	stopAll := b.Subscribe(func(_ context.Context, ev Event) {
+		all = append(all, ev.Type)
+		assert.False(t, ev.Time.IsZero())
+	})
+	defer b.Subscribe(func(_ context.Context, ev Event) {
+		written = append(written, ev.Entries...)
+	}, EntryWritten)()
+
+	b.Publish(ctx, Event{Type: EntryWritten, Entries: []string{"foo"}})
+	b.Publish(ctx, Event{Type: EntryDeleted, Entries: []string{"bar"}})
+	assert.Equal(t, []string{EntryWritten, EntryDeleted}, all)
+	assert.Equal(t, []string{"foo"}, written)
+
+	stopAll()
+	b.Publish(ctx, Event{Type: EntryWritten, Entries: []string{"baz"}})
+	assert.Len(t, all, 2)
+	assert.Equal(t, []string{"foo", "baz"}, written)
+}
+
+func TestPublishContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+
+	// no bus, no panic.
+	Publish(ctx, Event{Type: SyncCompleted})
+
+	b := New()
+	var got int
+	b.Subscribe(func(context.Context, Event) { got++ }, SyncCompleted)
+
+	Publish(WithBus(ctx, b), Event{Type: SyncCompleted})
+	assert.Equal(t, 1, got)
+}
@@ -0,0 +1,203 @@
+// Package fido2 protects single entries with a key derived from the
+// hmac-secret extension of a FIDO2 token. Revealing such an entry always needs
+// a tap on the token, no matter if the passphrase of the crypto backend is
+// cached by an agent. We use the fido2-tools binaries of libfido2 to talk to
+// the token.
+package fido2
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// RelyingParty is the relying party ID of the credentials created by gopass.
+const RelyingParty = "gopass"
+
+var (
+	// ErrNoDevice is returned if no FIDO2 token was found.
+	ErrNoDevice = errors.New("no FIDO2 token found")
+
+	tools = []string{"fido2-token", "fido2-cred", "fido2-assert"}
+)
+
+// Authenticator is a FIDO2 token supporting the hmac-secret extension.
+type Authenticator interface {
+	// MakeCredential creates a new credential and returns its ID.
+	MakeCredential(ctx context.Context) ([]byte, error)
+	// HMACSecret returns the hmac-secret of the credential for the salt. It
+	// requires a tap.
+	HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error)
+}
+
+// CLI is an Authenticator using the fido2-tools binaries.
+type CLI struct {
+	// Device is the path of the token, e.g. /dev/hidraw0. The first token
+	// listed by fido2-token -L is used if empty.
+	Device string
+}
+
+// New returns an Authenticator for the token configured in fido2.device.
+func New(ctx context.Context) *CLI {
+	return &CLI{
+		Device: config.String(ctx, "fido2.device"),
+	}
+}
+
+// MakeCredential creates a new non-resident credential with the hmac-secret
+// extension.
+func (c *CLI) MakeCredential(ctx context.Context) ([]byte, error) {
+	dev, err := c.device(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := make([]byte, 32)
+	if _, err := rand.Read(userID); err != nil {
+		return nil, fmt.Errorf("failed to create user ID: %w", err)
+	}
+
+	in, err := input(RelyingParty, "gopass", base64.StdEncoding.EncodeToString(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(out.Stderr, "Please touch your FIDO2 token to create a credential")
+
+	buf, err := runTool(ctx, in, "fido2-cred", "-M", "-h", dev)
+	if err != nil {
+		return nil, err
+	}
+
+	// client data hash, relying party, format, auth data, credential id, ...
+	lines := outputLines(buf)
+	if len(lines) < 5 {
+		return nil, fmt.Errorf("unexpected output of fido2-cred (%d lines)", len(lines))
+	}
+
+	return decode(lines[4])
+}
+
+// HMACSecret asserts the credential and returns the hmac-secret for the salt.
+func (c *CLI) HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error) {
+	dev, err := c.device(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := input(RelyingParty, base64.StdEncoding.EncodeToString(credential), base64.StdEncoding.EncodeToString(salt))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(out.Stderr, "Please touch your FIDO2 token")
+
+	buf, err := runTool(ctx, in, "fido2-assert", "-G", "-h", "-t", "up=true", dev)
+	if err != nil {
+		return nil, err
+	}
+
+	// the hmac-secret is always the last line.
+	lines := outputLines(buf)
+	if len(lines) < 1 {
+		return nil, fmt.Errorf("unexpected output of fido2-assert")
+	}
+
+	return decode(lines[len(lines)-1])
+}
+
+func (c *CLI) device(ctx context.Context) (string, error) {
+	if c.Device != "" {
+		return c.Device, nil
+	}
+
+	for _, t := range tools {
+		if _, err := exec.LookPath(t); err != nil {
+			return "", fmt.Errorf("%s not found, please install the fido2-tools of libfido2: %w", t, err)
+		}
+	}
+
+	buf, err := runTool(ctx, nil, "fido2-token", "-L")
+	if err != nil {
+		return "", err
+	}
+
+	dev, err := parseDevice(buf)
+	if err != nil {
+		return "", err
+	}
+
+	debug.Log("using FIDO2 token %s", dev)
+	c.Device = dev
+
+	return dev, nil
+}
+
+// parseDevice returns the path of the first token listed by fido2-token -L,
+// e.g. "/dev/hidraw0: vendor=0x1050, product=0x0407 (Yubico YubiKey)".
+func parseDevice(buf []byte) (string, error) {
+	for _, line := range outputLines(buf) {
+		if dev, _, found := strings.Cut(line, ": "); found && dev != "" {
+			return dev, nil
+		}
+	}
+
+	return "", ErrNoDevice
+}
+
+// input returns the input of fido2-cred and fido2-assert. The first line is
+// always a random client data hash.
+func input(lines ...string) ([]byte, error) {
+	cdh := make([]byte, 32)
+	if _, err := rand.Read(cdh); err != nil {
+		return nil, fmt.Errorf("failed to create client data hash: %w", err)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(cdh) + "\n" + strings.Join(lines, "\n") + "\n"), nil
+}
+
+func outputLines(buf []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func decode(s string) ([]byte, error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode output of the FIDO2 tools: %w", err)
+	}
+
+	return buf, nil
+}
+
+func runTool(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	debug.Log("running %s", args)
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return buf, nil
+}
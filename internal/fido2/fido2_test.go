@@ -0,0 +1,93 @@
+package fido2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeToken struct {
+	secret []byte
+	taps   int
+}
+
+func (f *fakeToken) MakeCredential(context.Context) ([]byte, error) {
+	return []byte("credential"), nil
+}
+
+func (f *fakeToken) HMACSecret(_ context.Context, _, salt []byte) ([]byte, error) {
+	f.taps++
+	m := hmac.New(sha256.New, f.secret)
+	m.Write(salt)
+
+	return m.Sum(nil), nil
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tok := &fakeToken{secret: []byte("token")}
+
+	key, err := NewKey(ctx, tok, []byte("credential"))
+	require.NoError(t, err)
+
+	wrapped, err := key.Wrap([]byte("secret\nuser: foo\n"))
+	require.NoError(t, err)
+	assert.True(t, IsProtected(wrapped))
+	assert.NotContains(t, string(wrapped), "secret")
+
+	plain, k2, err := Unwrap(ctx, tok, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "secret\nuser: foo\n", string(plain))
+	assert.Equal(t, 2, tok.taps)
+
+	// re-wrapping with the key of the last read doesn't need a tap.
+	rewrapped, err := k2.Wrap([]byte("new"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, tok.taps)
+
+	plain, _, err = Unwrap(ctx, tok, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(plain))
+
+	t.Run("wrong token", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := Unwrap(ctx, &fakeToken{secret: []byte("other")}, wrapped)
+		assert.Error(t, err)
+	})
+
+	t.Run("not protected", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := Unwrap(ctx, tok, []byte("secret\n"))
+		assert.ErrorIs(t, err, ErrNotProtected)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := Unwrap(ctx, tok, []byte(Header+"\nsalt: AAAA\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseDevice(t *testing.T) {
+	t.Parallel()
+
+	dev, err := parseDevice([]byte("/dev/hidraw3: vendor=0x1050, product=0x0407 (Yubico YubiKey OTP+FIDO+CCID)\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/hidraw3", dev)
+
+	dev, err = parseDevice([]byte("ioreg://4294969283: vendor=0x1050, product=0x0407 (Yubico YubiKey)\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "ioreg://4294969283", dev)
+
+	_, err = parseDevice(nil)
+	assert.ErrorIs(t, err, ErrNoDevice)
+}
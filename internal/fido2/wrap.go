@@ -0,0 +1,199 @@
+package fido2
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Header is the first line of a protected entry.
+const Header = "gopass-fido2-v1"
+
+// ErrNotProtected is returned when unwrapping an entry that isn't protected.
+var ErrNotProtected = errors.New("entry is not protected by a FIDO2 token")
+
+// A protected entry is encrypted with a random data key. The data key is
+// encrypted with the key encryption key (KEK), which is the hmac-secret of the
+// credential for a random salt. All of them are stored in the entry:
+//
+//	gopass-fido2-v1
+//	credential: <credential ID>
+//	salt: <salt>
+//	key: <encrypted data key>
+//	data: <encrypted content>
+
+// Key is the key encryption key of a protected entry. It can wrap new versions
+// of the entry without another tap. It must not be persisted.
+type Key struct {
+	credential []byte
+	salt       []byte
+	kek        []byte
+}
+
+// IsProtected returns true if the content is a protected entry.
+func IsProtected(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(Header+"\n"))
+}
+
+// NewKey creates a new key encryption key for the credential. It requires a
+// tap.
+func NewKey(ctx context.Context, a Authenticator, credential []byte) (*Key, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to create salt: %w", err)
+	}
+
+	return deriveKey(ctx, a, credential, salt)
+}
+
+func deriveKey(ctx context.Context, a Authenticator, credential, salt []byte) (*Key, error) {
+	secret, err := a.HMACSecret(ctx, credential, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hmac-secret from FIDO2 token: %w", err)
+	}
+
+	kek := sha256.Sum256(secret)
+
+	return &Key{
+		credential: credential,
+		salt:       salt,
+		kek:        kek[:],
+	}, nil
+}
+
+// Wrap encrypts the content with a new data key.
+func (k *Key) Wrap(content []byte) ([]byte, error) {
+	dk := make([]byte, 32)
+	if _, err := rand.Read(dk); err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	wrappedKey, err := seal(k.kek, dk)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := seal(dk, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(Header + "\n")
+	for _, f := range []struct {
+		name  string
+		value []byte
+	}{
+		{"credential", k.credential},
+		{"salt", k.salt},
+		{"key", wrappedKey},
+		{"data", data},
+	} {
+		fmt.Fprintf(&sb, "%s: %s\n", f.name, base64.StdEncoding.EncodeToString(f.value))
+	}
+
+	return []byte(sb.String()), nil
+}
+
+// Unwrap decrypts a protected entry. It requires a tap. The returned key can
+// wrap new versions of the entry.
+func Unwrap(ctx context.Context, a Authenticator, content []byte) ([]byte, *Key, error) {
+	fields, err := parse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	k, err := deriveKey(ctx, a, fields["credential"], fields["salt"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dk, err := open(k.kek, fields["key"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt data key, wrong FIDO2 token? %w", err)
+	}
+
+	plain, err := open(dk, fields["data"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt entry: %w", err)
+	}
+
+	return plain, k, nil
+}
+
+func parse(content []byte) (map[string][]byte, error) {
+	if !IsProtected(content) {
+		return nil, ErrNotProtected
+	}
+
+	fields := make(map[string][]byte, 4)
+	for _, line := range strings.Split(string(content), "\n")[1:] {
+		if line == "" {
+			continue
+		}
+
+		k, v, found := strings.Cut(line, ": ")
+		if !found {
+			return nil, fmt.Errorf("malformed protected entry: %q", line)
+		}
+
+		buf, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("malformed %s in protected entry: %w", k, err)
+		}
+		fields[k] = buf
+	}
+
+	for _, k := range []string{"credential", "salt", "key", "data"} {
+		if len(fields[k]) < 1 {
+			return nil, fmt.Errorf("malformed protected entry: missing %s", k)
+		}
+	}
+
+	return fields, nil
+}
+
+func seal(key, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to create nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil) //nolint:wrapcheck
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block) //nolint:wrapcheck
+}
@@ -0,0 +1,151 @@
+// Package fips restricts gopass to FIPS approved algorithms. The mode is
+// enabled by building with the fips build tag or by setting core.fips.
+//
+// In this mode only the GnuPG backend is allowed, recipient keys must use
+// RSA with at least 2048 bits or NIST curves, secrets must be encrypted with
+// AES and template functions using other hashes or KDFs are disabled.
+package fips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/config"
+)
+
+// ErrNotApproved is returned if an operation requires an algorithm that is
+// not FIPS approved.
+var ErrNotApproved = errors.New("algorithm is not FIPS approved")
+
+// minRSABits is the minimal length of RSA keys.
+const minRSABits = 2048
+
+// OpenPGP public key algorithms, see RFC 4880 and RFC 6637.
+const (
+	algoRSA        = 1
+	algoRSAEncrypt = 2
+	algoRSASign    = 3
+	algoElgamal    = 16
+	algoDSA        = 17
+	algoECDH       = 18
+	algoECDSA      = 19
+	algoEdDSA      = 22
+)
+
+// OpenPGP symmetric algorithms.
+const (
+	symAES128 = 7
+	symAES192 = 8
+	symAES256 = 9
+)
+
+// OpenPGP AEAD algorithms. Only GCM is approved, EAX and OCB are not.
+const (
+	aeadNone = 0
+	aeadGCM  = 3
+)
+
+// approvedCurves are the NIST curves as named by GnuPG.
+var approvedCurves = map[string]bool{
+	"nistp256": true,
+	"nistp384": true,
+	"nistp521": true,
+}
+
+// approvedBackends are the crypto backends that only use approved algorithms.
+// age uses X25519, ChaCha20-Poly1305 and scrypt.
+var approvedBackends = map[string]bool{
+	"gpgcli": true,
+}
+
+// Enabled returns true if gopass must only use FIPS approved algorithms.
+func Enabled(ctx context.Context) bool {
+	return forced || config.Bool(ctx, "core.fips")
+}
+
+// CheckBackend returns an error if the named crypto backend is not approved.
+func CheckBackend(name string) error {
+	if approvedBackends[name] {
+		return nil
+	}
+
+	return fmt.Errorf("crypto backend %s: %w. Use gpg or disable core.fips", name, ErrNotApproved)
+}
+
+// CheckKey returns an error if the key has no valid encryption (sub)key or
+// if any of them uses an algorithm that is not approved. gpg may pick any of
+// them, so all are checked. Signing only keys are ignored.
+func CheckKey(k gpg.Key) error {
+	found := false
+	for _, a := range k.Algorithms {
+		if !a.Encrypt {
+			continue
+		}
+		found = true
+		if err := checkAlgorithm(a); err != nil {
+			return fmt.Errorf("key %s: %w", k.ID(), err)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("key %s: no valid encryption subkey", k.ID())
+	}
+
+	return nil
+}
+
+func checkAlgorithm(a gpg.Algorithm) error {
+	switch a.ID {
+	case algoRSA, algoRSAEncrypt, algoRSASign:
+		if a.Length < minRSABits {
+			return fmt.Errorf("RSA with %d bits: %w. At least %d bits are required", a.Length, ErrNotApproved, minRSABits)
+		}
+
+		return nil
+	case algoECDH, algoECDSA:
+		if !approvedCurves[a.Curve] {
+			return fmt.Errorf("curve %s: %w. Use one of the NIST curves", a.Curve, ErrNotApproved)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%s: %w", algorithmName(a.ID), ErrNotApproved)
+	}
+}
+
+// CheckCipher returns an error if the OpenPGP symmetric algorithm with the
+// given id is not approved.
+func CheckCipher(id int) error {
+	switch id {
+	case symAES128, symAES192, symAES256:
+		return nil
+	default:
+		return fmt.Errorf("cipher %d: %w. Re-encrypt the secret with AES", id, ErrNotApproved)
+	}
+}
+
+// CheckAEAD returns an error if the OpenPGP AEAD algorithm with the given id
+// is not approved. 0 means no AEAD mode, i.e. CFB with MDC.
+func CheckAEAD(id int) error {
+	switch id {
+	case aeadNone, aeadGCM:
+		return nil
+	default:
+		return fmt.Errorf("AEAD mode %d: %w. Re-encrypt the secret without AEAD or with GCM", id, ErrNotApproved)
+	}
+}
+
+func algorithmName(id int) string {
+	switch id {
+	case algoElgamal:
+		return "Elgamal"
+	case algoDSA:
+		return "DSA"
+	case algoEdDSA:
+		return "EdDSA"
+	default:
+		return fmt.Sprintf("public key algorithm %d", id)
+	}
+}
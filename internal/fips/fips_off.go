@@ -0,0 +1,7 @@
+//go:build !fips
+// +build !fips
+
+package fips
+
+// forced is set for builds with the fips tag. core.fips can not disable it.
+const forced = false
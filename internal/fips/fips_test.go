@@ -0,0 +1,119 @@
+package fips
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnabled(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+	assert.Equal(t, forced, Enabled(ctx))
+
+	require.NoError(t, cfg.Set("", "core.fips", "true"))
+	assert.True(t, Enabled(ctx))
+}
+
+func TestCheckBackend(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckBackend("gpgcli"))
+	assert.ErrorIs(t, CheckBackend("age"), ErrNotApproved)
+	assert.ErrorIs(t, CheckBackend("plain"), ErrNotApproved)
+}
+
+func TestCheckKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		algos []gpg.Algorithm
+		ok    bool
+	}{
+		{
+			name:  "rsa4096",
+			algos: []gpg.Algorithm{{ID: 1, Length: 4096}, {ID: 1, Length: 4096, Encrypt: true}},
+			ok:    true,
+		},
+		{
+			name:  "rsa1024",
+			algos: []gpg.Algorithm{{ID: 1, Length: 1024, Encrypt: true}},
+		},
+		{
+			name:  "nistp384",
+			algos: []gpg.Algorithm{{ID: 19, Length: 384, Curve: "nistp384"}, {ID: 18, Length: 384, Curve: "nistp384", Encrypt: true}},
+			ok:    true,
+		},
+		{
+			name:  "ed25519 with nistp256 subkey",
+			algos: []gpg.Algorithm{{ID: 22, Length: 255, Curve: "ed25519"}, {ID: 18, Length: 256, Curve: "nistp256", Encrypt: true}},
+			ok:    true,
+		},
+		{
+			name:  "ed25519 with cv25519 subkey",
+			algos: []gpg.Algorithm{{ID: 22, Length: 255, Curve: "ed25519"}, {ID: 18, Length: 255, Curve: "cv25519", Encrypt: true}},
+		},
+		{
+			name:  "dsa with elgamal subkey",
+			algos: []gpg.Algorithm{{ID: 17, Length: 2048}, {ID: 16, Length: 2048, Encrypt: true}},
+		},
+		{
+			name:  "rsa with brainpool subkey",
+			algos: []gpg.Algorithm{{ID: 1, Length: 3072}, {ID: 18, Length: 256, Curve: "brainpoolP256r1", Encrypt: true}},
+		},
+		{
+			name:  "rsa with approved and brainpool subkey",
+			algos: []gpg.Algorithm{{ID: 1, Length: 3072}, {ID: 1, Length: 3072, Encrypt: true}, {ID: 18, Length: 256, Curve: "brainpoolP256r1", Encrypt: true}},
+		},
+
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckKey(gpg.Key{Fingerprint: "A5B1E6FE4F1C2D3B", Algorithms: tc.algos})
+			if tc.ok {
+				assert.NoError(t, err)
+
+				return
+			}
+			assert.ErrorIs(t, err, ErrNotApproved)
+		})
+	}
+
+	t.Run("no encryption subkey", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, CheckKey(gpg.Key{Fingerprint: "A5B1E6FE4F1C2D3B", Algorithms: []gpg.Algorithm{{ID: 1, Length: 3072}}}))
+	})
+}
+
+func TestCheckCipher(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []int{7, 8, 9} {
+		assert.NoError(t, CheckCipher(id))
+	}
+
+	// 3DES, CAST5, Blowfish, Twofish, Camellia
+	for _, id := range []int{2, 3, 4, 10, 11, 12, 13} {
+		assert.ErrorIs(t, CheckCipher(id), ErrNotApproved)
+	}
+}
+
+func TestCheckAEAD(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckAEAD(0))
+	assert.NoError(t, CheckAEAD(3))
+
+	// EAX, OCB
+	for _, id := range []int{1, 2} {
+		assert.ErrorIs(t, CheckAEAD(id), ErrNotApproved)
+	}
+}
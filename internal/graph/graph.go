@@ -0,0 +1,225 @@
+// Package graph builds a graph of the relationships between entries, e.g.
+// entries sharing a password or referring to each other. It helps to
+// untangle stores that grew over many years.
+package graph
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/hashsum"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// Kinds of relationships.
+const (
+	// KindDuplicate connects entries with the same content, e.g. copies or
+	// links.
+	KindDuplicate = "duplicate"
+	// KindSharedPassword connects entries with the same password.
+	KindSharedPassword = "shared-password"
+	// KindReference points from an entry to the entry named in one of its
+	// values, e.g. `parent: websites/github.com` or `gopass://websites/github.com`.
+	KindReference = "reference"
+	// KindAlias connects entries for domains that are aliases of each
+	// other, see pwrules.LookupAliases.
+	KindAlias = "alias"
+)
+
+// RefPrefix is the optional prefix of references.
+const RefPrefix = "gopass://"
+
+// Edge is a relationship between two entries. Only references are directed.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph is the graph of relationships.
+type Graph struct {
+	Nodes []string `json:"nodes"`
+	Edges []Edge   `json:"edges"`
+}
+
+type entry struct {
+	content  string
+	password string
+	domain   string
+	values   []string
+}
+
+// Builder collects entries and builds the graph.
+type Builder struct {
+	aliases func(string) []string
+	entries map[string]entry
+}
+
+// New creates a new builder. aliases returns the aliases of a domain.
+func New(aliases func(string) []string) *Builder {
+	return &Builder{
+		aliases: aliases,
+		entries: make(map[string]entry, 512),
+	}
+}
+
+// Add adds an entry.
+func (b *Builder) Add(name string, sec gopass.Secret) {
+	e := entry{
+		content: hashsum.SHA256Hex(string(sec.Bytes())),
+		domain:  domain(name),
+	}
+
+	if pw := sec.Password(); pw != "" {
+		e.password = hashsum.SHA256Hex(pw)
+	}
+
+	for _, k := range sec.Keys() {
+		vs, _ := sec.Values(k)
+		e.values = append(e.values, vs...)
+	}
+
+	b.entries[name] = e
+}
+
+// domain returns the innermost path component of name that looks like a
+// domain.
+func domain(name string) string {
+	for name != "" && name != "." && name != "/" {
+		if b := path.Base(name); strings.Contains(b, ".") && !strings.HasPrefix(b, ".") {
+			return strings.ToLower(b)
+		}
+		name = path.Dir(name)
+	}
+
+	return ""
+}
+
+// Graph builds the graph. Unless all is set it only contains entries that
+// have at least one relationship.
+func (b *Builder) Graph(all bool) *Graph {
+	names := make([]string, 0, len(b.entries))
+	for name := range b.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byContent := make(map[string][]string, len(names))
+	byPassword := make(map[string][]string, len(names))
+	byDomain := make(map[string][]string, len(names))
+	for _, name := range names {
+		e := b.entries[name]
+		byContent[e.content] = append(byContent[e.content], name)
+		if e.password != "" {
+			byPassword[e.password] = append(byPassword[e.password], name)
+		}
+		if e.domain != "" {
+			byDomain[e.domain] = append(byDomain[e.domain], name)
+		}
+	}
+
+	seen := make(map[Edge]bool, len(names))
+	g := &Graph{}
+	add := func(from, to, kind string) {
+		if from == to {
+			return
+		}
+		// only references are directed.
+		if kind != KindReference && to < from {
+			from, to = to, from
+		}
+		e := Edge{From: from, To: to, Kind: kind}
+		if seen[e] {
+			return
+		}
+		seen[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+
+	for _, name := range names {
+		e := b.entries[name]
+
+		for _, other := range byContent[e.content] {
+			add(name, other, KindDuplicate)
+		}
+
+		// entries with the same content already share their password.
+		for _, other := range byPassword[e.password] {
+			if b.entries[other].content != e.content {
+				add(name, other, KindSharedPassword)
+			}
+		}
+
+		for _, v := range e.values {
+			ref := strings.TrimPrefix(strings.TrimSpace(v), RefPrefix)
+			if _, found := b.entries[ref]; found {
+				add(name, ref, KindReference)
+			}
+		}
+
+		if e.domain != "" && b.aliases != nil {
+			for _, a := range b.aliases(e.domain) {
+				// entries for the same domain are just siblings.
+				if a == e.domain {
+					continue
+				}
+				for _, other := range byDomain[a] {
+					add(name, other, KindAlias)
+				}
+			}
+		}
+	}
+
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+
+		return a.Kind < b.Kind
+	})
+
+	if all {
+		g.Nodes = names
+
+		return g
+	}
+
+	nodes := make(map[string]bool, len(g.Edges))
+	for _, e := range g.Edges {
+		nodes[e.From] = true
+		nodes[e.To] = true
+	}
+	for _, name := range names {
+		if nodes[name] {
+			g.Nodes = append(g.Nodes, name)
+		}
+	}
+
+	return g
+}
+
+// DOT renders the graph in the DOT language of Graphviz.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+
+	_, _ = sb.WriteString("graph gopass {\n")
+	for _, n := range g.Nodes {
+		_, _ = fmt.Fprintf(&sb, "  %q;\n", n)
+	}
+	for _, e := range g.Edges {
+		attrs := fmt.Sprintf("label=%q", e.Kind)
+		if e.Kind == KindReference {
+			attrs += ", dir=forward"
+		}
+		_, _ = fmt.Fprintf(&sb, "  %q -- %q [%s];\n", e.From, e.To, attrs)
+	}
+	_, _ = sb.WriteString("}\n")
+
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string][]string{
+		"amazon.com": {"amazon.com", "amazon.de"},
+		"amazon.de":  {"amazon.de", "amazon.com"},
+	}
+
+	b := New(func(d string) []string {
+		return aliases[d]
+	})
+
+	add := func(name, pw string, kvs ...string) {
+		sec := secrets.NewAKV()
+		sec.SetPassword(pw)
+		for i := 0; i+1 < len(kvs); i += 2 {
+			require.NoError(t, sec.Set(kvs[i], kvs[i+1]))
+		}
+		b.Add(name, sec)
+	}
+
+	add("web/amazon.com", "one")
+	add("web/amazon.de", "two")
+	add("web/github.com", "three", "user", "gopher")
+	add("web/github-copy", "three", "user", "gopher")
+	add("web/gitlab.com", "three", "user", "other")
+	add("db/prod", "four", "parent", "gopass://db/root")
+	add("db/root", "five")
+	add("db/staging", "six", "parent", "db/missing")
+
+	g := b.Graph(false)
+	assert.Equal(t, []Edge{
+		{From: "db/prod", To: "db/root", Kind: KindReference},
+		{From: "web/amazon.com", To: "web/amazon.de", Kind: KindAlias},
+		{From: "web/github-copy", To: "web/github.com", Kind: KindDuplicate},
+		{From: "web/github-copy", To: "web/gitlab.com", Kind: KindSharedPassword},
+		{From: "web/github.com", To: "web/gitlab.com", Kind: KindSharedPassword},
+	}, g.Edges)
+	assert.NotContains(t, g.Nodes, "db/staging")
+	assert.Len(t, g.Nodes, 7)
+
+	g = b.Graph(true)
+	assert.Contains(t, g.Nodes, "db/staging")
+	assert.Len(t, g.Nodes, 8)
+
+	dot := g.DOT()
+	assert.Contains(t, dot, "graph gopass {\n")
+	assert.Contains(t, dot, `  "db/prod" -- "db/root" [label="reference", dir=forward];`)
+	assert.Contains(t, dot, `  "web/github-copy" -- "web/gitlab.com" [label="shared-password"];`)
+	assert.NotContains(t, dot, "three")
+}
+
+func TestDomain(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"web/GitHub.com":       "github.com",
+		"web/github.com/login": "github.com",
+		"misc/.hidden/foo":     "",
+		"foo":                  "",
+	} {
+		assert.Equal(t, want, domain(in), in)
+	}
+}
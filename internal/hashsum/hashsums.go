@@ -1,6 +1,7 @@
 package hashsum
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -23,3 +24,11 @@ func SHA256Hex(in string) string {
 func SHA512Hex(in string) string {
 	return fmt.Sprintf("%x", sha512.Sum512([]byte(in)))
 }
+
+// HMACSHA256Hex returns the hex encoded HMAC-SHA256 of in.
+func HMACSHA256Hex(key, in []byte) string {
+	m := hmac.New(sha256.New, key)
+	_, _ = m.Write(in)
+
+	return fmt.Sprintf("%x", m.Sum(nil))
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/sandbox"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/appdir"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -71,11 +71,13 @@ func Invoke(ctx context.Context, hook, dir string, hookArgs ...string) error {
 
 	args = append(args, hookArgs...)
 
-	cmd := exec.CommandContext(ctx, hook, args...)
+	cmd := sandbox.CommandContext(ctx, hook, args...)
 	cmd.Stdin = nil
 	cmd.Stdout = nil
 	cmd.Stderr = Stderr
-	cmd.Env = os.Environ()
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
 	cmd.Env = append(cmd.Env, "GOPASS_HOOK=1")
 	cmd.Dir = dir
 
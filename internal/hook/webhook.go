@@ -0,0 +1,180 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Webhook events.
+const (
+	EventCreated           = event.EntryCreated
+	EventRotated           = event.EntryRotated
+	EventDeleted           = event.EntryDeleted
+	EventRecipientsChanged = event.RecipientsChanged
+	EventFsckFailed        = event.FsckFailed
+)
+
+// webhookTimeout limits the delivery of a single webhook, including retries.
+const webhookTimeout = 10 * time.Second
+
+// SignatureHeader contains the hex encoded HMAC-SHA256 of the request body
+// keyed with webhook.secret, prefixed with "sha256=".
+const SignatureHeader = "X-Gopass-Signature"
+
+// WebhookPayload is the JSON body posted to the webhook.
+type WebhookPayload struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Host    string    `json:"host"`
+	Store   string    `json:"store"`
+	Folder  string    `json:"folder,omitempty"`
+	Entries []string  `json:"entries,omitempty"`
+	Added   []string  `json:"added,omitempty"`
+	Removed []string  `json:"removed,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// NewWebhookPayload returns a new payload for the given event and store.
+func NewWebhookPayload(event, store string) WebhookPayload {
+	hn, _ := os.Hostname()
+
+	return WebhookPayload{
+		Event: event,
+		Time:  time.Now().UTC(),
+		Host:  hn,
+		Store: store,
+	}
+}
+
+// WebhookEnabled returns true if a webhook is configured and subscribed to the
+// given event. If webhook.events is empty all events are sent.
+func WebhookEnabled(ctx context.Context, event string) bool {
+	if config.String(ctx, "webhook.url") == "" {
+		return false
+	}
+
+	events := strings.TrimSpace(config.String(ctx, "webhook.events"))
+	if events == "" {
+		return true
+	}
+
+	for _, e := range strings.Split(events, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubscribeWebhook posts the webhook events published on the bus to the
+// configured webhook. Errors are only reported, they never fail the
+// operation that triggered the event.
+func SubscribeWebhook(b *event.Bus) func() {
+	return b.Subscribe(func(ctx context.Context, ev event.Event) {
+		if !WebhookEnabled(ctx, ev.Type) {
+			return
+		}
+
+		p := NewWebhookPayload(ev.Type, ev.Store)
+		p.Time = ev.Time
+		if len(ev.Entries) > 0 {
+			p.Entries = set.Sorted(ev.Entries)
+		}
+		p.Added = ev.Added
+		p.Removed = ev.Removed
+		p.Folder = ev.Folder
+		p.Message = ev.Message
+
+		Webhook(ctx, p)
+	}, EventCreated, EventRotated, EventDeleted, EventRecipientsChanged, EventFsckFailed)
+}
+
+// Webhook posts the payload to the configured webhook, if it is subscribed to
+// the event.
+func Webhook(ctx context.Context, p WebhookPayload) {
+	if !WebhookEnabled(ctx, p.Event) {
+		return
+	}
+
+	SendWebhook(ctx, p.Event, config.String(ctx, "webhook.url"), config.String(ctx, "webhook.secret"), p)
+}
+
+// SendWebhook posts the payload in the background queue, so a slow or
+// unreachable webhook doesn't block the command. The queue is drained before
+// gopass exits. Errors are only reported.
+func SendWebhook(ctx context.Context, name, url, secret string, payload any) {
+	t := queue.GetQueue(ctx).Add(func(_ context.Context) (context.Context, error) {
+		if err := PostWebhook(ctx, url, secret, payload); err != nil {
+			out.Warningf(ctx, "Failed to send %s webhook: %s", name, err)
+		}
+
+		return nil, nil
+	})
+	_, _ = t(ctx)
+}
+
+// PostWebhook posts the payload as JSON to the given URL. If secret is not
+// empty the body is signed with HMAC-SHA256, see SignatureHeader. Retries are
+// given up after webhookTimeout.
+func PostWebhook(ctx context.Context, url, secret string, payload any) error {
+	if network.Offline(ctx) {
+		return network.ErrOffline
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	buf, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, buf))
+	}
+
+	resp, err := network.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	debug.Log("posted webhook to %s", req.URL.Redacted())
+
+	return nil
+}
+
+// Sign returns the value of the SignatureHeader for the given body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook(t *testing.T) {
+	var got []WebhookPayload
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, Sign("s3cr3t", buf), r.Header.Get(SignatureHeader))
+		sig = r.Header.Get(SignatureHeader)
+
+		var p WebhookPayload
+		assert.NoError(t, json.Unmarshal(buf, &p))
+		got = append(got, p)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	bus := event.New()
+	defer SubscribeWebhook(bus)()
+	ctx = event.WithBus(ctx, bus)
+	created := event.Event{Type: event.EntryCreated, Store: "team", Entries: []string{"team/foo"}}
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.False(t, WebhookEnabled(ctx, EventCreated))
+		event.Publish(ctx, created)
+		assert.Empty(t, got)
+	})
+
+	require.NoError(t, cfg.Set("", "webhook.url", srv.URL))
+	require.NoError(t, cfg.Set("", "webhook.secret", "s3cr3t"))
+	require.NoError(t, cfg.Set("", "webhook.events", "entry.created, entry.deleted"))
+
+	t.Run("subscribed", func(t *testing.T) {
+		event.Publish(ctx, created)
+		require.Len(t, got, 1)
+		assert.Equal(t, EventCreated, got[0].Event)
+		assert.Equal(t, "team", got[0].Store)
+		assert.Equal(t, []string{"team/foo"}, got[0].Entries)
+		assert.Contains(t, sig, "sha256=")
+	})
+
+	t.Run("not subscribed", func(t *testing.T) {
+		got = nil
+		Webhook(ctx, NewWebhookPayload(EventFsckFailed, ""))
+		event.Publish(ctx, event.Event{Type: event.EntryWritten, Entries: []string{"foo"}})
+		assert.Empty(t, got)
+	})
+
+	t.Run("queued", func(t *testing.T) {
+		got = nil
+		q := queue.New(ctx)
+		qctx := queue.WithQueue(ctx, q)
+
+		// the caller is not blocked by the delivery.
+		Webhook(qctx, NewWebhookPayload(EventDeleted, "team"))
+		require.NoError(t, q.Close(ctx))
+		require.Len(t, got, 1)
+		assert.Equal(t, EventDeleted, got[0].Event)
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// echo -n '{}' | openssl dgst -sha256 -hmac key
+	assert.Equal(t, "sha256=a777724d943eb48dc69bca8a4a6d57a04db3f9ec7e1de4e581e860265bdf3032", Sign("key", []byte("{}")))
+}
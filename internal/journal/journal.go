@@ -0,0 +1,288 @@
+// Package journal records multi-file operations on a store, e.g. re-encrypting
+// all entries or moving a tree. The journal is written before the first file
+// is touched and removed once the operation is committed. If gopass is
+// interrupted the journal is left behind, so the next invocation can complete
+// or roll back the operation. Each store has at most one journal, keyed by the
+// path of the store, in the user data directory. Finished items are appended
+// to a separate log, so marking an item as done doesn't rewrite the whole
+// journal.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Operations recorded in the journal.
+const (
+	OpReencrypt = "reencrypt"
+	OpMove      = "move"
+)
+
+// ErrPending is returned by Begin if the store still has the journal of an
+// interrupted operation.
+var ErrPending = errors.New("the store has an unfinished operation. Run 'gopass fsck' to recover")
+
+// Item is a single file of an operation.
+type Item struct {
+	Name string `json:"name"`
+	Dst  string `json:"dst,omitempty"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// Journal is the record of an in-flight operation.
+type Journal struct {
+	Op      string    `json:"op"`
+	Store   string    `json:"store"`
+	Started time.Time `json:"started"`
+	PID     int       `json:"pid"`
+	// Delete is set for moves, i.e. copies that remove the source.
+	Delete bool `json:"delete,omitempty"`
+	// Tag is added to the re-encrypted entries of a revocation.
+	Tag   string `json:"tag,omitempty"`
+	Items []Item `json:"items"`
+
+	mu   sync.Mutex
+	path string
+}
+
+// Dir returns the directory holding the journals.
+func Dir() string {
+	return filepath.Join(appdir.UserData(), "journal")
+}
+
+func pathFor(store string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(store)))
+
+	return filepath.Join(Dir(), fmt.Sprintf("%x.json", sum[:8]))
+}
+
+// Begin records the start of an operation on the given store.
+func Begin(store, op string, del bool, items []Item) (*Journal, error) {
+	j := &Journal{
+		Op:      op,
+		Store:   store,
+		Started: time.Now().UTC(),
+		PID:     os.Getpid(),
+		Delete:  del,
+		Items:   items,
+		path:    pathFor(store),
+	}
+
+	if _, err := os.Stat(j.path); err == nil {
+		return nil, ErrPending
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir: %w", err)
+	}
+
+	// a log without a journal is left over from an interrupted Finish.
+	if err := os.Remove(j.doneLog()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale journal log: %w", err)
+	}
+
+	if err := j.save(); err != nil {
+		return nil, err
+	}
+
+	debug.Log("started %s journal for %s (%d items)", op, store, len(items))
+
+	return j, nil
+}
+
+// Load returns the journal of the given store. It returns nil if there is no
+// unfinished operation.
+func Load(store string) (*Journal, error) {
+	fn := pathFor(store)
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read journal %s: %w", fn, err)
+	}
+
+	j := &Journal{}
+	if err := json.Unmarshal(buf, j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", fn, err)
+	}
+	j.path = fn
+
+	if err := j.loadDone(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// loadDone marks the items recorded in the log of finished items as done.
+func (j *Journal) loadDone() error {
+	buf, err := os.ReadFile(j.doneLog())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read journal log %s: %w", j.doneLog(), err)
+	}
+
+	done := map[string]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	sc.Buffer(nil, len(buf)+1)
+	for sc.Scan() {
+		var name string
+		// the last record might be truncated if gopass was killed while
+		// writing it.
+		if err := json.Unmarshal(sc.Bytes(), &name); err != nil {
+			debug.Log("ignoring invalid record %q in %s: %s", sc.Text(), j.doneLog(), err)
+
+			continue
+		}
+		done[name] = true
+	}
+
+	// terminate a truncated record, so later records are not appended to it.
+	if len(buf) > 0 && buf[len(buf)-1] != '\n' {
+		if err := appendFile(j.doneLog(), []byte("\n")); err != nil {
+			return err
+		}
+	}
+
+	for i := range j.Items {
+		if done[j.Items[i].Name] {
+			j.Items[i].Done = true
+		}
+	}
+
+	return nil
+}
+
+// doneLog returns the path of the log of finished items.
+func (j *Journal) doneLog() string {
+	return strings.TrimSuffix(j.path, ".json") + ".done"
+}
+
+// Done marks the item with the given name as finished. The name is appended
+// to the log of finished items.
+func (j *Journal) Done(name string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.Items {
+		if j.Items[i].Name == name {
+			j.Items[i].Done = true
+		}
+	}
+
+	rec, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+
+	return appendFile(j.doneLog(), append(rec, '\n'))
+}
+
+func appendFile(fn string, buf []byte) error {
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal log %s: %w", fn, err)
+	}
+
+	if _, err := fh.Write(buf); err != nil {
+		_ = fh.Close()
+
+		return fmt.Errorf("failed to write journal log %s: %w", fn, err)
+	}
+
+	return fh.Close()
+}
+
+// SetTag records the tag that is added to each entry of the operation.
+func (j *Journal) SetTag(tag string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Tag = tag
+
+	return j.saveLocked()
+}
+
+// Pending returns all items that are not marked as finished.
+func (j *Journal) Pending() []Item {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var res []Item
+	for _, it := range j.Items {
+		if !it.Done {
+			res = append(res, it)
+		}
+	}
+
+	return res
+}
+
+// Finish removes the journal once the operation is complete.
+func (j *Journal) Finish() error {
+	if j == nil {
+		return nil
+	}
+
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove journal %s: %w", j.path, err)
+	}
+
+	if err := os.Remove(j.doneLog()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove journal log %s: %w", j.doneLog(), err)
+	}
+
+	debug.Log("finished %s journal for %s", j.Op, j.Store)
+
+	return nil
+}
+
+func (j *Journal) save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.saveLocked()
+}
+
+// saveLocked writes the journal to a temporary file first, so it's never
+// left truncated.
+func (j *Journal) saveLocked() error {
+	buf, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return fmt.Errorf("failed to write journal %s: %w", tmp, err)
+	}
+
+	return os.Rename(tmp, j.path)
+}
@@ -0,0 +1,62 @@
+package journal
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	_ = gptest.NewUnitTester(t)
+
+	store := t.TempDir()
+
+	j, err := Load(store)
+	require.NoError(t, err)
+	assert.Nil(t, j)
+
+	j, err = Begin(store, OpMove, true, []Item{
+		{Name: "foo", Dst: "bar/foo"},
+		{Name: "baz", Dst: "bar/baz"},
+	})
+	require.NoError(t, err)
+
+	// finishing an item only appends to the log.
+	before, err := os.ReadFile(j.path)
+	require.NoError(t, err)
+	require.NoError(t, j.Done("foo"))
+	after, err := os.ReadFile(j.path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// a truncated record is ignored.
+	fh, err := os.OpenFile(j.doneLog(), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = fh.WriteString(`"ba`)
+	require.NoError(t, err)
+	require.NoError(t, fh.Close())
+
+	_, err = Begin(store, OpReencrypt, false, nil)
+	assert.ErrorIs(t, err, ErrPending)
+
+	l, err := Load(store)
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	assert.Equal(t, OpMove, l.Op)
+	assert.True(t, l.Delete)
+	assert.Equal(t, []Item{{Name: "baz", Dst: "bar/baz"}}, l.Pending())
+
+	require.NoError(t, l.Done("baz"))
+	l, err = Load(store)
+	require.NoError(t, err)
+	assert.Empty(t, l.Pending())
+
+	require.NoError(t, l.Finish())
+	j, err = Load(store)
+	require.NoError(t, err)
+	assert.Nil(t, j)
+	assert.NoFileExists(t, l.doneLog())
+}
@@ -0,0 +1,51 @@
+// Package keychain reads the web credentials stored in the password manager
+// of the operating system, i.e. the macOS Keychain, the Secret Service
+// (libsecret) on Linux or the Windows Credential Manager, so they can be
+// imported into gopass.
+package keychain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupported is returned if there is no supported password manager on
+// this platform.
+var ErrUnsupported = errors.New("reading the OS password manager is not supported on this platform")
+
+// Credential is a single set of credentials for a site.
+type Credential struct {
+	// Server is the host name of the site, e.g. github.com.
+	Server   string
+	User     string
+	Password string
+	// Label is the name shown by the password manager.
+	Label string
+}
+
+// host returns the host name of a server that may be given as an URL.
+func host(server string) string {
+	server = strings.TrimSpace(server)
+	if strings.Contains(server, "://") {
+		if u, err := url.Parse(server); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+
+	return strings.TrimSuffix(server, "/")
+}
+
+// valid filters out credentials without a server or a password.
+func valid(creds []Credential) []Credential {
+	out := make([]Credential, 0, len(creds))
+	for _, c := range creds {
+		c.Server = host(c.Server)
+		if c.Server == "" || c.Password == "" {
+			continue
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+// +build darwin
+
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// Source is the name of the password manager.
+const Source = "the macOS Keychain"
+
+// List returns the internet passwords of the default keychain. macOS asks
+// the user to allow access to each of them.
+func List(ctx context.Context) ([]Credential, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "security", "dump-keychain", "-d")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run security dump-keychain: %w: %s", err, stderr.String())
+	}
+
+	return parseSecurityDump(stdout.Bytes()), nil
+}
@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// Source is the name of the password manager.
+const Source = "the Secret Service (libsecret)"
+
+// List returns the network passwords stored in the Secret Service, e.g. the
+// GNOME Keyring or KWallet. Locked collections are unlocked, which may ask
+// the user for their password.
+func List(ctx context.Context) ([]Credential, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, fmt.Errorf("secret-tool not found. Please install libsecret-tools: %w", err)
+	}
+
+	// secret-tool prints some of the fields to stderr, so both are needed.
+	var buf bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "secret-tool", "search", "--all", "--unlock", "xdg:schema", "org.gnome.keyring.NetworkPassword")
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits with 1 if nothing was found.
+		if ee, ok := err.(*exec.ExitError); ok && ee.ExitCode() == 1 && buf.Len() == 0 {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to run secret-tool: %w: %s", err, buf.String())
+	}
+
+	return parseSecretTool(buf.Bytes()), nil
+}
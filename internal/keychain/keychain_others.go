@@ -0,0 +1,14 @@
+//go:build !darwin && !linux && !windows
+// +build !darwin,!linux,!windows
+
+package keychain
+
+import "context"
+
+// Source is the name of the password manager.
+const Source = "the OS password manager"
+
+// List is not supported on this platform.
+func List(ctx context.Context) ([]Credential, error) {
+	return nil, ErrUnsupported
+}
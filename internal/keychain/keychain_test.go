@@ -0,0 +1,85 @@
+package keychain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const securityDump = `keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "inet"
+attributes:
+    0x00000007 <blob>="github.com (alice)"
+    0x00000008 <blob>=<NULL>
+    "acct"<blob>="alice"
+    "atyp"<blob>="form"
+    "path"<blob>=<NULL>
+    "port"<uint32>=0x00000000
+    "ptcl"<uint32>="htps"
+    "srvr"<blob>="github.com"
+data:
+"hunter2"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="alice"
+    "svce"<blob>="Some App"
+data:
+"app-secret"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "inet"
+attributes:
+    "acct"<blob>="bob@example.org"
+    "srvr"<blob>="www.example.org"
+data:
+0x7061C3A9FF  "pa\303\251\377"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "inet"
+attributes:
+    "acct"<blob>="nopass"
+    "srvr"<blob>="example.net"
+data:
+<NULL>
+`
+
+func TestParseSecurityDump(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Credential{
+		{Server: "github.com", User: "alice", Password: "hunter2", Label: "github.com (alice)"},
+		{Server: "www.example.org", User: "bob@example.org", Password: "pa\xc3\xa9\xff"},
+	}, parseSecurityDump([]byte(securityDump)))
+}
+
+const secretToolOutput = `[/org/freedesktop/secrets/collection/login/12]
+label = alice@github.com
+secret = hunter2
+created = 2024-01-02 03:04:05
+modified = 2024-01-02 03:04:05
+schema = org.gnome.keyring.NetworkPassword
+attribute.protocol = https
+attribute.server = github.com
+attribute.user = alice
+[/org/freedesktop/secrets/collection/login/13]
+label = https://example.org/login
+secret = s3cret
+schema = org.gnome.keyring.NetworkPassword
+attribute.username_value = bob
+[/org/freedesktop/secrets/collection/login/14]
+label = Empty
+secret = 
+attribute.server = example.net
+`
+
+func TestParseSecretTool(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Credential{
+		{Server: "github.com", User: "alice", Password: "hunter2", Label: "alice@github.com"},
+		{Server: "example.org", User: "bob", Password: "s3cret", Label: "https://example.org/login"},
+	}, parseSecretTool([]byte(secretToolOutput)))
+}
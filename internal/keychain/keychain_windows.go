@@ -0,0 +1,107 @@
+//go:build windows
+// +build windows
+
+package keychain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Source is the name of the password manager.
+const Source = "the Windows Credential Manager"
+
+const credTypeGeneric = 1
+
+var (
+	advapi32          = windows.NewLazySystemDLL("advapi32.dll")
+	procCredEnumerate = advapi32.NewProc("CredEnumerateW")
+	procCredFree      = advapi32.NewProc("CredFree")
+)
+
+// credential is the CREDENTIALW struct of wincred.h.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// List returns the generic credentials of the current user whose target is
+// a web site, e.g. the ones stored by git.
+func List(ctx context.Context) ([]Credential, error) {
+	var count uint32
+	var creds **credential
+
+	r, _, err := procCredEnumerate.Call(0, 0, uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&creds)))
+	if r == 0 {
+		if err == windows.ERROR_NOT_FOUND {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to enumerate credentials: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(creds))) //nolint:errcheck
+
+	list := unsafe.Slice(creds, count)
+
+	res := make([]Credential, 0, count)
+	for _, c := range list {
+		if c.Type != credTypeGeneric {
+			continue
+		}
+
+		target := windows.UTF16PtrToString(c.TargetName)
+		res = append(res, Credential{
+			Server:   targetServer(target),
+			User:     windows.UTF16PtrToString(c.UserName),
+			Password: blobString(unsafe.Slice(c.CredentialBlob, c.CredentialBlobSize)),
+			Label:    target,
+		})
+	}
+
+	return valid(res), nil
+}
+
+// targetServer strips the prefixes of common targets, e.g.
+// git:https://github.com or LegacyGeneric:target=example.org.
+func targetServer(target string) string {
+	target = strings.TrimPrefix(target, "LegacyGeneric:target=")
+	target = strings.TrimPrefix(target, "git:")
+	if !strings.Contains(target, ".") {
+		return ""
+	}
+
+	return target
+}
+
+// blobString decodes a credential blob. The Credential Manager stores
+// passwords entered by users as UTF-16, other tools use UTF-8.
+func blobString(b []byte) string {
+	if len(b)%2 != 0 || len(b) < 2 {
+		return string(b)
+	}
+
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		if b[i+1] != 0 {
+			return string(b)
+		}
+		u = append(u, uint16(b[i])|uint16(b[i+1])<<8)
+	}
+
+	return string(utf16.Decode(u))
+}
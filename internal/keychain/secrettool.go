@@ -0,0 +1,68 @@
+package keychain
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// parseSecretTool parses the output of `secret-tool search --all`. Every item
+// starts with its object path in brackets, followed by key = value lines.
+func parseSecretTool(buf []byte) []Credential {
+	var creds []Credential
+
+	var cur Credential
+	var started bool
+	attrs := map[string]string{}
+
+	flush := func() {
+		if started {
+			cur.Server = firstOf(attrs, "server", "host", "origin_url", "signon_realm", "url")
+			if cur.Server == "" {
+				cur.Server = cur.Label
+			}
+			cur.User = firstOf(attrs, "user", "username", "username_value", "account")
+			creds = append(creds, cur)
+		}
+		cur = Credential{}
+		attrs = map[string]string{}
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			flush()
+			started = true
+
+			continue
+		}
+
+		k, v, found := strings.Cut(line, " = ")
+		if !found {
+			continue
+		}
+
+		switch {
+		case k == "label":
+			cur.Label = v
+		case k == "secret":
+			cur.Password = v
+		case strings.HasPrefix(k, "attribute."):
+			attrs[strings.TrimPrefix(k, "attribute.")] = v
+		}
+	}
+	flush()
+
+	return valid(creds)
+}
+
+func firstOf(m map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := m[k]; v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
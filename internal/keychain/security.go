@@ -0,0 +1,97 @@
+package keychain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reSecurityAttr matches an attribute in the output of security dump-keychain,
+// e.g. `    "srvr"<blob>="github.com"`.
+var reSecurityAttr = regexp.MustCompile(`^\s+(?:"(\w{4})"|0x[0-9A-Fa-f]+)\s*<\w+>=(.*)$`)
+
+// parseSecurityDump parses the output of `security dump-keychain -d`. Only
+// internet passwords are returned, application passwords aren't credentials
+// of web sites.
+func parseSecurityDump(buf []byte) []Credential {
+	var creds []Credential
+
+	var cur Credential
+	var class string
+	var inData bool
+
+	flush := func() {
+		if class == "inet" {
+			creds = append(creds, cur)
+		}
+		cur = Credential{}
+		class = ""
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+
+		if inData {
+			inData = false
+			cur.Password = securityValue(line)
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "keychain: "):
+			flush()
+		case strings.HasPrefix(line, "class: "):
+			class = securityValue(strings.TrimPrefix(line, "class: "))
+		case line == "data:":
+			inData = true
+		default:
+			m := reSecurityAttr.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+
+			switch m[1] {
+			case "srvr":
+				cur.Server = securityValue(m[2])
+			case "acct":
+				cur.User = securityValue(m[2])
+			}
+
+			if strings.HasPrefix(line, "    0x00000007 ") {
+				cur.Label = securityValue(m[2])
+			}
+		}
+	}
+	flush()
+
+	return valid(creds)
+}
+
+// securityValue decodes a value printed by security. Strings are quoted with
+// octal escapes, binary data is printed in hex, optionally followed by the
+// quoted string.
+func securityValue(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "<NULL>" {
+		return ""
+	}
+
+	if strings.HasPrefix(v, "0x") {
+		h, _, _ := strings.Cut(strings.TrimPrefix(v, "0x"), " ")
+		if buf, err := hex.DecodeString(h); err == nil {
+			return strings.TrimRight(string(buf), "\x00")
+		}
+	}
+
+	if s, err := strconv.Unquote(v); err == nil {
+		return s
+	}
+
+	return strings.Trim(v, `"`)
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package lock
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive returns true if a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid < 1 {
+		return false
+	}
+
+	err := syscall.Kill(pid, 0)
+
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
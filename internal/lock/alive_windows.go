@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package lock
+
+import "os"
+
+// processAlive returns true if a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid < 1 {
+		return false
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = proc.Release()
+
+	return true
+}
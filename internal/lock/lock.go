@@ -0,0 +1,228 @@
+// Package lock implements advisory, cross process locks for password stores.
+// Each mount gets its own lockfile (keyed by the path of the store) in the
+// user cache directory. Lockfiles record the PID and the host of the owner
+// so stale locks of crashed processes can be detected and broken.
+package lock
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+var (
+	// StaleAfter is the age after which a lock is considered stale even if
+	// the owner can not be checked, e.g. because it is running on a different
+	// host.
+	StaleAfter = 15 * time.Minute
+	// PollInterval is the interval in which a waiting process retries to
+	// acquire a lock.
+	PollInterval = 250 * time.Millisecond
+
+	// ErrNotLocked is returned when unlocking a lock that is not held.
+	ErrNotLocked = errors.New("not locked")
+)
+
+// LockedError is returned if a lock is held by another process.
+type LockedError struct {
+	Path  string
+	PID   int
+	Host  string
+	Since time.Time
+}
+
+func (e *LockedError) Error() string {
+	if e.PID < 1 {
+		return fmt.Sprintf("store is locked by another process (%s). Use --wait to wait for it to finish", e.Path)
+	}
+
+	return fmt.Sprintf("store is locked by PID %d on %s since %s (%s). Use --wait to wait for it to finish", e.PID, e.Host, e.Since.Format(time.RFC3339), e.Path)
+}
+
+// Lock is a reentrant lock on a store. It is safe for concurrent use.
+type Lock struct {
+	path string
+
+	mu    sync.Mutex
+	count int
+}
+
+// Dir returns the directory holding the lockfiles.
+func Dir() string {
+	return filepath.Join(appdir.UserCache(), "locks")
+}
+
+// New returns a new lock for the store at the given path.
+func New(store string) *Lock {
+	sum := sha256.Sum256([]byte(filepath.Clean(store)))
+
+	return &Lock{
+		path: filepath.Join(Dir(), fmt.Sprintf("%x.lock", sum[:8])),
+	}
+}
+
+// Path returns the path of the lockfile.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Held returns true if this process holds the lock.
+func (l *Lock) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.count > 0
+}
+
+// Lock acquires the lock. If the lock is already held by this process the
+// call only increments the lock count. If another process holds the lock
+// Lock returns a *LockedError, unless wait is true. Then it retries until
+// the lock is released or the context is canceled.
+func (l *Lock) Lock(ctx context.Context, wait bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count > 0 {
+		l.count++
+
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create lock dir: %w", err)
+	}
+
+	for {
+		err := l.tryLock()
+		if err == nil {
+			l.count++
+
+			return nil
+		}
+
+		var le *LockedError
+		if !errors.As(err, &le) || !wait {
+			return err
+		}
+
+		debug.Log("waiting for lock: %s", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting: %w", err)
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// Unlock releases the lock once the lock count drops to zero.
+func (l *Lock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count < 1 {
+		return ErrNotLocked
+	}
+
+	l.count--
+	if l.count > 0 {
+		return nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove lockfile %s: %w", l.path, err)
+	}
+
+	return nil
+}
+
+func (l *Lock) tryLock() error {
+	fh, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err == nil {
+		_, err := fmt.Fprintf(fh, "%d\n%s\n%d\n", os.Getpid(), hostname(), time.Now().Unix())
+		if cerr := fh.Close(); err == nil {
+			err = cerr
+		}
+
+		return err
+	}
+
+	if !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create lockfile %s: %w", l.path, err)
+	}
+
+	owner, err := readOwner(l.path)
+	if err != nil {
+		// the lockfile might have been removed or not written, yet.
+		debug.Log("failed to read lockfile %s: %s", l.path, err)
+
+		return &LockedError{Path: l.path}
+	}
+
+	if !owner.stale() {
+		return owner
+	}
+
+	debug.Log("breaking stale lock of PID %d on %s", owner.PID, owner.Host)
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale lockfile %s: %w", l.path, err)
+	}
+
+	return l.tryLock()
+}
+
+func (e *LockedError) stale() bool {
+	if e.Host == hostname() {
+		return !processAlive(e.PID)
+	}
+
+	return time.Since(e.Since) > StaleAfter
+}
+
+func readOwner(path string) (*LockedError, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) != 3 {
+		return nil, fmt.Errorf("invalid lockfile")
+	}
+
+	pid, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid PID: %w", err)
+	}
+
+	ts, err := strconv.ParseInt(lines[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	return &LockedError{
+		Path:  path,
+		PID:   pid,
+		Host:  lines[1],
+		Since: time.Unix(ts, 0),
+	}, nil
+}
+
+func hostname() string {
+	hn, err := os.Hostname()
+	if err != nil {
+		return "localhost"
+	}
+
+	return hn
+}
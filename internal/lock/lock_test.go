@@ -0,0 +1,104 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOwner(t *testing.T, l *Lock, pid int, host string, since time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(l.Path()), 0o700))
+	require.NoError(t, os.WriteFile(l.Path(), []byte(fmt.Sprintf("%d\n%s\n%d\n", pid, host, since.Unix())), 0o600))
+}
+
+func TestLockReentrant(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+	l := New("/tmp/store")
+
+	assert.False(t, l.Held())
+	require.NoError(t, l.Lock(ctx, false))
+	require.NoError(t, l.Lock(ctx, false))
+	assert.FileExists(t, l.Path())
+	assert.True(t, l.Held())
+
+	require.NoError(t, l.Unlock())
+	assert.FileExists(t, l.Path())
+	assert.True(t, l.Held())
+	require.NoError(t, l.Unlock())
+	assert.NoFileExists(t, l.Path())
+	assert.False(t, l.Held())
+
+	assert.ErrorIs(t, l.Unlock(), ErrNotLocked)
+}
+
+func TestLockPerStore(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	assert.Equal(t, New("/tmp/store").Path(), New("/tmp/store/").Path())
+	assert.NotEqual(t, New("/tmp/store").Path(), New("/tmp/other").Path())
+}
+
+func TestLockedByOther(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+	l := New("/tmp/store")
+
+	// our parent process is alive.
+	writeOwner(t, l, os.Getppid(), hostname(), time.Now())
+
+	err := l.Lock(ctx, false)
+	var le *LockedError
+	require.True(t, errors.As(err, &le), "%s", err)
+	assert.Equal(t, os.Getppid(), le.PID)
+	assert.Contains(t, err.Error(), "store is locked by PID")
+
+	// waiting gives up when the context is canceled.
+	oldPoll := PollInterval
+	PollInterval = 10 * time.Millisecond
+	defer func() { PollInterval = oldPoll }()
+
+	wctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Lock(wctx, true))
+
+	// waiting succeeds once the lock is released.
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = os.Remove(l.Path())
+	}()
+	require.NoError(t, l.Lock(ctx, true))
+	require.NoError(t, l.Unlock())
+}
+
+func TestLockStale(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+	l := New("/tmp/store")
+
+	// dead process on this host.
+	writeOwner(t, l, 1<<22+1, hostname(), time.Now())
+	require.NoError(t, l.Lock(ctx, false))
+	require.NoError(t, l.Unlock())
+
+	// recent lock of another host.
+	writeOwner(t, l, 42, "other-host", time.Now())
+	assert.Error(t, l.Lock(ctx, false))
+
+	// old lock of another host.
+	writeOwner(t, l, 42, "other-host", time.Now().Add(-2*StaleAfter))
+	require.NoError(t, l.Lock(ctx, false))
+	require.NoError(t, l.Unlock())
+}
@@ -0,0 +1,139 @@
+// Package mobile provisions what a pass compatible mobile app, e.g. Android
+// Password Store, needs to sync a store: a read-only SSH deploy key for the
+// git remote and the location of the repository. Deploy keys can be added to
+// Gitea (and Forgejo) through its API, other hosts need a manual step.
+package mobile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Remote is a parsed git remote URL.
+type Remote struct {
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// ParseRemote parses the usual forms of git remote URLs, e.g.
+// git@example.org:owner/repo.git, ssh://git@example.org:2222/owner/repo.git
+// or https://example.org/owner/repo.
+func ParseRemote(remote string) (Remote, error) {
+	var host, p string
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return Remote{}, fmt.Errorf("failed to parse remote %q: %w", remote, err)
+		}
+		host, p = u.Hostname(), u.Path
+	} else {
+		hp, path, found := strings.Cut(remote, ":")
+		if !found {
+			return Remote{}, fmt.Errorf("unsupported remote %q", remote)
+		}
+		if _, h, found := strings.Cut(hp, "@"); found {
+			hp = h
+		}
+		host, p = hp, path
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(p, ".git"), "/"), "/")
+	if host == "" || len(parts) < 2 {
+		return Remote{}, fmt.Errorf("remote %q has no owner and repository", remote)
+	}
+
+	return Remote{
+		Host:  host,
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Repo:  parts[len(parts)-1],
+	}, nil
+}
+
+// NewDeployKey creates a new ed25519 SSH key pair with ssh-keygen. It returns
+// the public key in authorized_keys format and the private key in OpenSSH
+// format. The private key has no passphrase, mobile apps keep it in their own
+// key store.
+func NewDeployKey(ctx context.Context, comment string) ([]byte, []byte, error) {
+	dir, err := os.MkdirTemp("", "gopass-mobile-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	fn := filepath.Join(dir, "id_ed25519")
+	cmd := exec.CommandContext(ctx, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", comment, "-f", fn)
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if buf, err := cmd.CombinedOutput(); err != nil {
+		return nil, nil, fmt.Errorf("failed to run ssh-keygen: %s: %w", strings.TrimSpace(string(buf)), err)
+	}
+
+	priv, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pub, err := os.ReadFile(fn + ".pub")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bytes.TrimSpace(pub), priv, nil
+}
+
+// AddGiteaDeployKey adds a read-only deploy key to a repository on a Gitea or
+// Forgejo instance. baseURL is the web address of the instance, e.g.
+// https://gitea.example.org.
+func AddGiteaDeployKey(ctx context.Context, baseURL, token string, r Remote, title string, pub []byte) error {
+	if network.Offline(ctx) {
+		return network.ErrOffline
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"title":     title,
+		"key":       string(pub),
+		"read_only": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s/keys", strings.TrimSuffix(baseURL, "/"), url.PathEscape(r.Owner), url.PathEscape(r.Repo))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := network.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= 300 {
+		buf, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		debug.Log("gitea returned %s: %s", resp.Status, string(buf))
+
+		return fmt.Errorf("gitea returned %s", resp.Status)
+	}
+
+	debug.Log("added deploy key %q to %s/%s", title, r.Owner, r.Repo)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package mobile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemote(t *testing.T) {
+	t.Parallel()
+
+	for remote, want := range map[string]Remote{
+		"git@gitea.example.org:alice/pass.git":            {Host: "gitea.example.org", Owner: "alice", Repo: "pass"},
+		"ssh://git@gitea.example.org:2222/alice/pass.git": {Host: "gitea.example.org", Owner: "alice", Repo: "pass"},
+		"https://gitlab.example.org/team/sub/pass":        {Host: "gitlab.example.org", Owner: "team/sub", Repo: "pass"},
+		"gitea.example.org:alice/pass":                    {Host: "gitea.example.org", Owner: "alice", Repo: "pass"},
+	} {
+		got, err := ParseRemote(remote)
+		require.NoError(t, err, remote)
+		assert.Equal(t, want, got, remote)
+	}
+
+	for _, remote := range []string{"/srv/git/pass.git", "https://example.org/pass", "git@example.org:pass"} {
+		_, err := ParseRemote(remote)
+		assert.Error(t, err, remote)
+	}
+}
+
+func TestAddGiteaDeployKey(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/alice/pass/keys" || r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	r := Remote{Host: "gitea.example.org", Owner: "alice", Repo: "pass"}
+	require.NoError(t, AddGiteaDeployKey(context.Background(), ts.URL+"/", "secret", r, "phone", []byte("ssh-ed25519 AAAA phone")))
+	assert.Equal(t, map[string]any{"title": "phone", "key": "ssh-ed25519 AAAA phone", "read_only": true}, got)
+
+	assert.Error(t, AddGiteaDeployKey(context.Background(), ts.URL, "wrong", r, "phone", []byte("ssh-ed25519 AAAA phone")))
+}
@@ -0,0 +1,79 @@
+package names
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Layouts of entries for websites.
+const (
+	// LayoutFlat stores one entry per account: <folder>/<user>@<domain>.
+	LayoutFlat = "flat"
+	// LayoutSite has one folder per site: <folder>/<domain>/<user>.
+	LayoutSite = "folder-per-site"
+	// LayoutDomainUser has one folder per site at the top of the mount:
+	// <domain>/<user>.
+	LayoutDomainUser = "domain-user"
+)
+
+// Layouts are the supported layouts.
+var Layouts = []string{LayoutFlat, LayoutSite, LayoutDomainUser}
+
+// Site is the name of an entry for a website, split into its parts.
+type Site struct {
+	// Folder are the folders above the domain, if any.
+	Folder string
+	Domain string
+	// User is the account, if any.
+	User string
+}
+
+// ParseSite splits a name in any of the layouts into its parts. It returns
+// false if the name doesn't contain a domain or has more than one component
+// below the domain.
+func ParseSite(name string) (Site, bool) {
+	parts := strings.Split(name, "/")
+
+	for i, p := range parts {
+		rest := parts[i+1:]
+
+		if at := strings.LastIndex(p, "@"); at > 0 && reDomain.MatchString(p[at+1:]) {
+			if len(rest) > 0 {
+				return Site{}, false
+			}
+
+			return Site{Folder: strings.Join(parts[:i], "/"), Domain: p[at+1:], User: p[:at]}, true
+		}
+
+		if !reDomain.MatchString(p) {
+			continue
+		}
+
+		if len(rest) > 1 {
+			return Site{}, false
+		}
+
+		return Site{Folder: strings.Join(parts[:i], "/"), Domain: p, User: strings.Join(rest, "")}, true
+	}
+
+	return Site{}, false
+}
+
+// Name returns the name of the site in the given layout.
+func (s Site) Name(layout string) (string, error) {
+	switch layout {
+	case LayoutFlat:
+		if s.User == "" {
+			return path.Join(s.Folder, s.Domain), nil
+		}
+
+		return path.Join(s.Folder, s.User+"@"+s.Domain), nil
+	case LayoutSite:
+		return path.Join(s.Folder, s.Domain, s.User), nil
+	case LayoutDomainUser:
+		return path.Join(s.Domain, s.User), nil
+	default:
+		return "", fmt.Errorf("unknown layout %q. Use one of %s", layout, strings.Join(Layouts, ", "))
+	}
+}
@@ -0,0 +1,56 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSite(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]Site{
+		"websites/github.com":       {Folder: "websites", Domain: "github.com"},
+		"websites/github.com/alice": {Folder: "websites", Domain: "github.com", User: "alice"},
+		"websites/alice@github.com": {Folder: "websites", Domain: "github.com", User: "alice"},
+		"a.b@github.com":            {Domain: "github.com", User: "a.b"},
+		"github.com/alice":          {Domain: "github.com", User: "alice"},
+	} {
+		got, ok := ParseSite(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{
+		"misc/wifi",
+		"websites/github.com/alice/recovery",
+		"websites/alice@github.com/totp",
+	} {
+		_, ok := ParseSite(in)
+		assert.False(t, ok, in)
+	}
+}
+
+func TestSiteName(t *testing.T) {
+	t.Parallel()
+
+	s := Site{Folder: "websites", Domain: "github.com", User: "alice"}
+	for layout, want := range map[string]string{
+		LayoutFlat:       "websites/alice@github.com",
+		LayoutSite:       "websites/github.com/alice",
+		LayoutDomainUser: "github.com/alice",
+	} {
+		got, err := s.Name(layout)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, layout)
+	}
+
+	s.User = ""
+	got, err := s.Name(LayoutFlat)
+	require.NoError(t, err)
+	assert.Equal(t, "websites/github.com", got)
+
+	_, err = s.Name("nested")
+	assert.Error(t, err)
+}
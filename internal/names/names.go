@@ -0,0 +1,108 @@
+// Package names normalizes the names of new entries. Over time stores tend to
+// accumulate near duplicates like GitHub.com, github.com and www.github.com.
+// Stores can opt into rules that normalize new names and detect collisions
+// with existing entries that only differ in these details.
+package names
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+)
+
+// Collision policies.
+const (
+	// CollisionPrompt asks whether to use the existing entry instead.
+	CollisionPrompt = "prompt"
+	// CollisionExisting always uses the existing entry.
+	CollisionExisting = "existing"
+	// CollisionIgnore creates the new entry anyway.
+	CollisionIgnore = "ignore"
+)
+
+var reDomain = regexp.MustCompile(`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+$`)
+
+// Rules are the normalization rules of a store.
+type Rules struct {
+	// Lowercase lowercases path components that are domains.
+	Lowercase bool
+	// StripWWW removes a leading www. from domains.
+	StripWWW bool
+	// Spaces replaces spaces, unless empty.
+	Spaces string
+	// Collisions is the collision policy.
+	Collisions string
+}
+
+// RulesFor returns the rules of the given mount.
+func RulesFor(ctx context.Context, mount string) Rules {
+	cfg := config.FromContext(ctx)
+
+	r := Rules{
+		Lowercase:  cfg.GetM(mount, "names.lowercase-domains") == "true",
+		StripWWW:   cfg.GetM(mount, "names.strip-www") == "true",
+		Spaces:     cfg.GetM(mount, "names.spaces"),
+		Collisions: cfg.GetM(mount, "names.collisions"),
+	}
+
+	switch r.Collisions {
+	case CollisionExisting, CollisionIgnore:
+	default:
+		r.Collisions = CollisionPrompt
+	}
+
+	return r
+}
+
+// Enabled returns true if any rule is enabled.
+func (r Rules) Enabled() bool {
+	return r.Lowercase || r.StripWWW || r.Spaces != ""
+}
+
+// Normalize applies the rules to the name.
+func (r Rules) Normalize(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		if r.Spaces != "" {
+			p = strings.ReplaceAll(strings.TrimSpace(p), " ", r.Spaces)
+		}
+
+		if reDomain.MatchString(p) {
+			if r.Lowercase {
+				p = strings.ToLower(p)
+			}
+
+			if r.StripWWW && len(p) > 4 && strings.EqualFold(p[:4], "www.") && strings.Contains(p[4:], ".") {
+				p = p[4:]
+			}
+		}
+
+		parts[i] = p
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// Key returns the name with all rules applied, ignoring case. Names with the
+// same key are considered duplicates.
+func Key(name string) string {
+	r := Rules{Lowercase: true, StripWWW: true, Spaces: "-"}
+
+	return strings.ToLower(strings.ReplaceAll(r.Normalize(name), "_", "-"))
+}
+
+// Collisions returns the entries that are duplicates of name.
+func Collisions(name string, entries []string) []string {
+	key := Key(name)
+
+	var dups []string
+	for _, e := range entries {
+		if e != name && Key(e) == key {
+			dups = append(dups, e)
+		}
+	}
+
+	return dups
+}
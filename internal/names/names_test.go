@@ -0,0 +1,57 @@
+package names
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	r := Rules{Lowercase: true, StripWWW: true, Spaces: "-"}
+	for in, want := range map[string]string{
+		"websites/GitHub.com":            "websites/github.com",
+		"websites/www.GitHub.com/me":     "websites/github.com/me",
+		"websites/www.com":               "websites/www.com",
+		"Personal/My Bank/ login":        "Personal/My-Bank/login",
+		"Work/VPN":                       "Work/VPN",
+		"email/John.Doe@Example.com":     "email/John.Doe@Example.com",
+		"servers/DB-01.prod.Example.org": "servers/db-01.prod.example.org",
+	} {
+		assert.Equal(t, want, r.Normalize(in), in)
+	}
+
+	assert.False(t, Rules{}.Enabled())
+	assert.Equal(t, "web/WWW.GitHub.com", Rules{}.Normalize("web/WWW.GitHub.com"))
+}
+
+func TestCollisions(t *testing.T) {
+	t.Parallel()
+
+	entries := []string{"websites/GitHub.com", "websites/gitlab.com", "misc/my_bank"}
+
+	assert.Equal(t, []string{"websites/GitHub.com"}, Collisions("websites/www.github.com", entries))
+	assert.Equal(t, []string{"misc/my_bank"}, Collisions("misc/My Bank", entries))
+	assert.Empty(t, Collisions("websites/GitHub.com", entries))
+	assert.Empty(t, Collisions("websites/bitbucket.org", entries))
+}
+
+func TestRulesFor(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	r := RulesFor(ctx, "")
+	assert.False(t, r.Enabled())
+	assert.Equal(t, CollisionPrompt, r.Collisions)
+
+	require.NoError(t, cfg.SetEnv("names.lowercase-domains", "true"))
+	require.NoError(t, cfg.SetEnv("names.collisions", "existing"))
+
+	r = RulesFor(ctx, "")
+	assert.True(t, r.Enabled())
+	assert.Equal(t, CollisionExisting, r.Collisions)
+}
@@ -0,0 +1,286 @@
+// Package network centralizes the network access of gopass. It provides a
+// HTTP client and retry helpers that honor the network.* config options
+// (proxy, timeouts, retries and offline mode) as well as the environment
+// for git operations.
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// DefaultTimeout is the default timeout for a single network request.
+	DefaultTimeout = 30 * time.Second
+	// DefaultRetries is the default number of retries for failed requests.
+	DefaultRetries = 3
+)
+
+// ErrOffline is returned when network access was requested in offline mode
+// or when the network is unreachable.
+var ErrOffline = errors.New("offline")
+
+// Offline returns true if gopass must not access the network.
+func Offline(ctx context.Context) bool {
+	return ctxutil.IsNoNetwork(ctx) || config.Bool(ctx, "network.offline")
+}
+
+// Timeout returns the timeout for a single network operation.
+func Timeout(ctx context.Context) time.Duration {
+	if s := config.Int(ctx, "network.timeout"); s > 0 {
+		return time.Duration(s) * time.Second
+	}
+
+	return DefaultTimeout
+}
+
+// Retries returns the number of retries for failed network operations.
+func Retries(ctx context.Context) int {
+	sv := config.String(ctx, "network.retries")
+	if sv == "" {
+		return DefaultRetries
+	}
+
+	n, err := strconv.Atoi(sv)
+	if err != nil || n < 0 {
+		debug.Log("invalid value for network.retries: %q", sv)
+
+		return DefaultRetries
+	}
+
+	return n
+}
+
+// Proxy returns the configured proxy URL or nil if the proxy from the
+// environment (HTTPS_PROXY, ALL_PROXY, ...) should be used. Supported
+// schemes are http, https, socks5 and socks5h (DNS resolution by the proxy,
+// e.g. for Tor).
+func Proxy(ctx context.Context) (*url.URL, error) {
+	if t := throttleFromContext(ctx); t != nil {
+		return t.URL(), nil
+	}
+
+	sv := config.String(ctx, "network.proxy")
+	if sv == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(sv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network.proxy %q: %w", sv, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q in network.proxy", u.Scheme)
+	}
+
+	return u, nil
+}
+
+// Transport returns a HTTP transport that honors the network config.
+func Transport(ctx context.Context) *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if u, err := Proxy(ctx); err != nil {
+		debug.Log("ignoring proxy: %s", err)
+	} else if u != nil {
+		proxy = http.ProxyURL(u)
+	}
+
+	timeout := Timeout(ctx)
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   timeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+}
+
+// Client returns a HTTP client that honors the network config.
+func Client(ctx context.Context) *http.Client {
+	return &http.Client{
+		Transport: Transport(ctx),
+	}
+}
+
+// Retry runs op until it succeeds, returns a permanent error (see
+// Permanent) or the configured number of retries is exhausted. Failed
+// attempts are retried with an exponential backoff.
+func Retry(ctx context.Context, name string, op func() error) error {
+	if Offline(ctx) {
+		return fmt.Errorf("%w: network access disabled", ErrOffline)
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = 500 * time.Millisecond
+	bo.MaxElapsedTime = 0
+
+	attempt := 0
+
+	//nolint:wrapcheck
+	return backoff.Retry(func() error {
+		select {
+		case <-ctx.Done():
+			return backoff.Permanent(ctx.Err())
+		default:
+		}
+
+		attempt++
+		err := op()
+		if err != nil {
+			debug.Log("%s failed (attempt %d): %s", name, attempt, err)
+		}
+
+		return err
+	}, backoff.WithMaxRetries(backoff.WithContext(bo, ctx), uint64(Retries(ctx))))
+}
+
+// Permanent wraps an error so Retry won't retry it.
+func Permanent(err error) error {
+	return backoff.Permanent(err)
+}
+
+// Do sends the request with the configured client, retrying network errors
+// and transient server errors (5xx, 429).
+func Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	client := Client(ctx)
+
+	var resp *http.Response
+	err := Retry(ctx, req.Method+" "+req.URL.Redacted(), func() error {
+		ctx, cancel := context.WithTimeout(ctx, Timeout(ctx))
+		rc := req.Clone(ctx)
+		if req.GetBody != nil {
+			// the body of the previous attempt was already consumed.
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+
+				return Permanent(err)
+			}
+			rc.Body = body
+		}
+		r, err := client.Do(rc) //nolint:bodyclose
+		if err != nil {
+			cancel()
+
+			if IsUnreachable(err) {
+				return Permanent(fmt.Errorf("%w: %s", ErrOffline, err))
+			}
+
+			return err
+		}
+
+		if r.StatusCode >= 500 || r.StatusCode == http.StatusTooManyRequests {
+			_ = r.Body.Close()
+			cancel()
+
+			return fmt.Errorf("request failed: %s", r.Status)
+		}
+
+		r.Body = &cancelBody{ReadCloser: r.Body, cancel: cancel}
+		resp = r
+
+		return nil
+	})
+
+	return resp, err
+}
+
+// GitEnv returns the environment variables that make git (and curl) use the
+// configured proxy and timeouts.
+func GitEnv(ctx context.Context) []string {
+	var env []string
+
+	if u, err := Proxy(ctx); err == nil && u != nil {
+		p := u.String()
+		env = append(env, "HTTPS_PROXY="+p, "HTTP_PROXY="+p, "ALL_PROXY="+p, "https_proxy="+p, "http_proxy="+p, "all_proxy="+p)
+	}
+
+	// abort transfers slower than 1 KB/s for longer than the timeout. Bandwidth
+	// limits lower the threshold accordingly.
+	limit := int64(1000)
+	if bw := MaxBandwidth(ctx); bw > 0 && bw/2 < limit {
+		limit = bw/2 + 1
+	}
+	env = append(env,
+		"GIT_HTTP_LOW_SPEED_LIMIT="+strconv.FormatInt(limit, 10),
+		"GIT_HTTP_LOW_SPEED_TIME="+strconv.Itoa(int(Timeout(ctx).Seconds())),
+	)
+
+	return env
+}
+
+// IsUnreachable returns true if the error indicates that we are offline,
+// i.e. there is no route to the network or host. There is no point in
+// retrying those. DNS errors do not count, they are often caused by a
+// misconfigured or temporarily failing resolver.
+func IsUnreachable(err error) bool {
+	if errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "network is unreachable") || strings.Contains(msg, "no route to host")
+}
+
+// IsTransient returns true if the git error output indicates a network
+// failure worth retrying.
+func IsTransient(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, needle := range []string{
+		"connection timed out",
+		"connection refused",
+		"connection reset",
+		"operation timed out",
+		"the remote end hung up unexpectedly",
+		"temporary failure",
+		"failed to connect",
+		"http 5",
+		"returned error: 5",
+		"early eof",
+	} {
+		if strings.Contains(stderr, needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cancelBody cancels the per-request context once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
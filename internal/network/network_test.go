@@ -0,0 +1,129 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCtx(t *testing.T, kv ...string) context.Context {
+	t.Helper()
+
+	cfg := config.NewNoWrites()
+	for i := 0; i+1 < len(kv); i += 2 {
+		require.NoError(t, cfg.Set("", kv[i], kv[i+1]))
+	}
+
+	return cfg.WithConfig(context.Background())
+}
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	u, err := Proxy(newCtx(t))
+	require.NoError(t, err)
+	assert.Nil(t, u)
+
+	u, err = Proxy(newCtx(t, "network.proxy", "socks5h://127.0.0.1:9050"))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9050", u.Host)
+
+	_, err = Proxy(newCtx(t, "network.proxy", "ftp://127.0.0.1"))
+	assert.Error(t, err)
+
+	env := GitEnv(newCtx(t, "network.proxy", "socks5h://127.0.0.1:9050", "network.timeout", "10"))
+	assert.Contains(t, env, "ALL_PROXY=socks5h://127.0.0.1:9050")
+	assert.Contains(t, env, "GIT_HTTP_LOW_SPEED_TIME=10")
+}
+
+func TestSettings(t *testing.T) {
+	t.Parallel()
+
+	ctx := newCtx(t)
+	assert.Equal(t, DefaultRetries, Retries(ctx))
+	assert.Equal(t, DefaultTimeout, Timeout(ctx))
+	assert.False(t, Offline(ctx))
+	assert.True(t, Offline(ctxutil.WithNoNetwork(ctx, true)))
+
+	ctx = newCtx(t, "network.retries", "0", "network.offline", "true")
+	assert.Equal(t, 0, Retries(ctx))
+	assert.True(t, Offline(ctx))
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := newCtx(t, "network.retries", "2")
+
+	n := 0
+	assert.Error(t, Retry(ctx, "test", func() error {
+		n++
+
+		return fmt.Errorf("failed")
+	}))
+	assert.Equal(t, 3, n)
+
+	n = 0
+	perm := errors.New("permanent")
+	assert.ErrorIs(t, Retry(ctx, "test", func() error {
+		n++
+
+		return Permanent(perm)
+	}), perm)
+	assert.Equal(t, 1, n)
+
+	assert.ErrorIs(t, Retry(newCtx(t, "network.offline", "true"), "test", func() error { return nil }), ErrOffline)
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	n := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if n < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	ctx := newCtx(t)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := Do(ctx, req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	buf, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(buf))
+	assert.Equal(t, 2, n)
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, IsUnreachable(&net.DNSError{Err: "no such host", Name: "example.org"}))
+	assert.True(t, IsUnreachable(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}))
+	assert.True(t, IsUnreachable(fmt.Errorf("dial tcp: connect: network is unreachable")))
+	assert.False(t, IsUnreachable(fmt.Errorf("boom")))
+
+	assert.True(t, IsTransient("fatal: unable to access 'https://example.org/': Failed to connect to example.org"))
+	assert.False(t, IsTransient("fatal: Authentication failed"))
+}
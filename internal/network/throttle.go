@@ -0,0 +1,408 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/net/proxy"
+)
+
+type contextKey int
+
+const (
+	ctxKeyMaxBandwidth contextKey = iota
+	ctxKeyThrottle
+)
+
+// WithMaxBandwidth overrides network.max-bandwidth. bw is in bytes per
+// second, 0 disables the limit.
+func WithMaxBandwidth(ctx context.Context, bw int64) context.Context {
+	return context.WithValue(ctx, ctxKeyMaxBandwidth, bw)
+}
+
+// MaxBandwidth returns the maximum bandwidth for git transfers in bytes per
+// second or 0 if it is not limited.
+func MaxBandwidth(ctx context.Context) int64 {
+	if bw, ok := ctx.Value(ctxKeyMaxBandwidth).(int64); ok {
+		return bw
+	}
+
+	sv := config.String(ctx, "network.max-bandwidth")
+	if sv == "" {
+		return 0
+	}
+
+	bw, err := ParseBandwidth(sv)
+	if err != nil {
+		debug.Log("invalid value for network.max-bandwidth: %s", err)
+
+		return 0
+	}
+
+	return bw
+}
+
+// ParseBandwidth parses a bandwidth in bytes per second, e.g. 64k, 1.5MB or
+// 512KiB/s.
+func ParseBandwidth(sv string) (int64, error) {
+	sv = strings.TrimSuffix(strings.TrimSpace(sv), "/s")
+
+	n, err := humanize.ParseBytes(sv)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth %q: %w", sv, err)
+	}
+
+	return int64(n), nil
+}
+
+// WithThrottle routes all git network access through the throttling proxy.
+func WithThrottle(ctx context.Context, t *Throttle) context.Context {
+	return context.WithValue(ctx, ctxKeyThrottle, t)
+}
+
+func throttleFromContext(ctx context.Context) *Throttle {
+	t, _ := ctx.Value(ctxKeyThrottle).(*Throttle)
+
+	return t
+}
+
+// Throttle is a local HTTP CONNECT proxy that limits the bandwidth of all
+// connections passing through it. Git doesn't support bandwidth limits on
+// its own, so the connection to the remote is routed through it. The
+// connections to the remote use the configured proxy, if any. Only
+// connections to the remote are accepted, so it can't be used as an open
+// proxy by other local processes.
+type Throttle struct {
+	ln    net.Listener
+	addr  string
+	dial  func(ctx context.Context, addr string) (net.Conn, error)
+	bw    int64
+	total atomic.Int64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// StartThrottle starts a throttling proxy for the given git remote.
+func StartThrottle(ctx context.Context, remote string, bw int64) (*Throttle, error) {
+	if bw <= 0 {
+		return nil, fmt.Errorf("invalid bandwidth %d", bw)
+	}
+
+	addr, err := RemoteAddr(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	dial, err := upstreamDialer(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start throttling proxy: %w", err)
+	}
+
+	t := &Throttle{
+		ln:   ln,
+		addr: addr,
+		dial: dial,
+		bw:   bw,
+	}
+
+	go t.serve(ctx)
+
+	debug.Log("throttling %s to %d bytes/s via %s", remote, bw, ln.Addr())
+
+	return t, nil
+}
+
+// URL returns the proxy URL of the throttle.
+func (t *Throttle) URL() *url.URL {
+	return &url.URL{Scheme: "http", Host: t.ln.Addr().String()}
+}
+
+// Transferred returns the number of bytes transferred in both directions.
+func (t *Throttle) Transferred() int64 {
+	return t.total.Load()
+}
+
+// Close stops the proxy.
+func (t *Throttle) Close() error {
+	return t.ln.Close()
+}
+
+func (t *Throttle) serve(ctx context.Context) {
+	for {
+		c, err := t.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go t.handle(ctx, c)
+	}
+}
+
+func (t *Throttle) handle(ctx context.Context, c net.Conn) {
+	defer c.Close() //nolint:errcheck
+
+	br := bufio.NewReader(c)
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		debug.Log("throttle: invalid request: %s", err)
+
+		return
+	}
+
+	addr := req.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		port := "80"
+		if req.Method == http.MethodConnect {
+			port = "443"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+
+	if !strings.EqualFold(addr, t.addr) {
+		debug.Log("throttle: rejecting connection to %s, only %s is allowed", addr, t.addr)
+		_, _ = fmt.Fprint(c, "HTTP/1.1 403 Forbidden\r\n\r\n")
+
+		return
+	}
+
+	up, err := t.dial(ctx, addr)
+	if err != nil {
+		debug.Log("throttle: failed to connect to %s: %s", addr, err)
+		_, _ = fmt.Fprint(c, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+
+		return
+	}
+	defer up.Close() //nolint:errcheck
+
+	if req.Method == http.MethodConnect {
+		if _, err := fmt.Fprint(c, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+			return
+		}
+	} else if err := req.Write(&throttledWriter{t: t, w: up}); err != nil {
+		debug.Log("throttle: failed to forward request to %s: %s", addr, err)
+
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst net.Conn, src io.Reader) {
+		if _, err := io.Copy(&throttledWriter{t: t, w: dst}, src); err != nil {
+			debug.Log("throttle: %s", err)
+		}
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		} else {
+			_ = dst.Close()
+		}
+		done <- struct{}{}
+	}
+
+	go pipe(up, br)
+	go pipe(c, up)
+	<-done
+	<-done
+}
+
+// RemoteAddr returns the host and port git connects to for the remote.
+func RemoteAddr(remote string) (string, error) {
+	host := RemoteHost(remote)
+	if host == "" {
+		return "", fmt.Errorf("no host in remote %q", remote)
+	}
+
+	port := "22"
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", fmt.Errorf("invalid remote %q: %w", remote, err)
+		}
+
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		case "git":
+			port = "9418"
+		}
+
+		if p := u.Port(); p != "" {
+			port = p
+		}
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// wait blocks until n more bytes may be sent. All connections share the
+// same budget.
+func (t *Throttle) wait(n int) {
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	at := t.next
+	t.next = t.next.Add(time.Duration(int64(n) * int64(time.Second) / t.bw))
+	t.mu.Unlock()
+
+	time.Sleep(time.Until(at))
+}
+
+// chunk is the largest write allowed at once. Small chunks keep the
+// transfer smooth on slow links.
+func (t *Throttle) chunk() int {
+	c := int(t.bw / 10)
+	if c < 512 {
+		return 512
+	}
+	if c > 32*1024 {
+		return 32 * 1024
+	}
+
+	return c
+}
+
+type throttledWriter struct {
+	t *Throttle
+	w io.Writer
+}
+
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		n := len(p)
+		if c := tw.t.chunk(); n > c {
+			n = c
+		}
+
+		tw.t.wait(n)
+		m, err := tw.w.Write(p[:n])
+		written += m
+		tw.t.total.Add(int64(m))
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// upstreamDialer returns a function that connects to the remote, through
+// the configured proxy if any.
+func upstreamDialer(ctx context.Context, remote string) (func(context.Context, string) (net.Conn, error), error) {
+	u, err := RemoteProxy(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if u != nil && IsOnion(remote) {
+		if err := CheckProxy(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &net.Dialer{Timeout: Timeout(ctx)}
+	if u == nil {
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			return d.DialContext(ctx, "tcp", addr)
+		}, nil
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		pd, err := proxy.FromURL(u, d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %w", u.Redacted(), err)
+		}
+
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			if cd, ok := pd.(proxy.ContextDialer); ok {
+				return cd.DialContext(ctx, "tcp", addr)
+			}
+
+			return pd.Dial("tcp", addr)
+		}, nil
+	case "http":
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialConnect(ctx, d, u, addr)
+		}, nil
+	default:
+		return nil, fmt.Errorf("bandwidth limits are not supported with %s proxies", u.Scheme)
+	}
+}
+
+// dialConnect opens a tunnel to addr through a HTTP proxy.
+func dialConnect(ctx context.Context, d *net.Dialer, u *url.URL, addr string) (net.Conn, error) {
+	c, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if pw, ok := u.User.Password(); ok {
+		req.SetBasicAuth(u.User.Username(), pw)
+		req.Header.Set("Proxy-Authorization", req.Header.Get("Authorization"))
+		req.Header.Del("Authorization")
+	}
+
+	if err := req.Write(c); err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+
+	br := bufio.NewReader(c)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_ = c.Close()
+
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", u.Redacted(), addr, resp.Status)
+	}
+
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: c, r: br}, nil
+	}
+
+	return c, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,124 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBandwidth(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]int64{
+		"1000":     1000,
+		"64k":      64000,
+		"64KiB/s":  65536,
+		"1.5MB":    1500000,
+		" 2 kb/s ": 2000,
+	} {
+		bw, err := ParseBandwidth(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, bw, in)
+	}
+
+	_, err := ParseBandwidth("fast")
+	assert.Error(t, err)
+}
+
+func TestMaxBandwidth(t *testing.T) {
+	t.Parallel()
+
+	ctx := newCtx(t, "network.max-bandwidth", "10k")
+	assert.Equal(t, int64(10000), MaxBandwidth(ctx))
+	assert.Equal(t, int64(0), MaxBandwidth(WithMaxBandwidth(ctx, 0)))
+	assert.Contains(t, GitEnv(WithMaxBandwidth(ctx, 100)), "GIT_HTTP_LOW_SPEED_LIMIT=51")
+}
+
+func TestRemoteAddr(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"https://example.org/repo.git":        "example.org:443",
+		"http://example.org:8080/repo.git":    "example.org:8080",
+		"ssh://git@example.org:2222/repo.git": "example.org:2222",
+		"git@example.org:repo.git":            "example.org:22",
+		"git://example.org/repo.git":          "example.org:9418",
+	} {
+		addr, err := RemoteAddr(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, addr, in)
+	}
+
+	_, err := RemoteAddr("/tmp/repo")
+	assert.Error(t, err)
+}
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	payload := strings.Repeat("x", 10000)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close() //nolint:errcheck
+
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close() //nolint:errcheck
+		_, _ = io.WriteString(c, payload)
+	}()
+
+	th, err := StartThrottle(ctx, "https://"+ln.Addr().String()+"/repo.git", 20000)
+	require.NoError(t, err)
+	defer th.Close() //nolint:errcheck
+
+	u, err := Proxy(WithThrottle(ctx, th))
+	require.NoError(t, err)
+	assert.Equal(t, th.URL(), u)
+
+	c, err := net.Dial("tcp", th.URL().Host)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	_, err = fmt.Fprintf(c, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", ln.Addr(), ln.Addr())
+	require.NoError(t, err)
+
+	br := bufio.NewReader(c)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// other hosts are rejected.
+	c2, err := net.Dial("tcp", th.URL().Host)
+	require.NoError(t, err)
+	defer c2.Close() //nolint:errcheck
+
+	_, err = fmt.Fprint(c2, "CONNECT example.org:443 HTTP/1.1\r\nHost: example.org:443\r\n\r\n")
+	require.NoError(t, err)
+
+	resp, err = http.ReadResponse(bufio.NewReader(c2), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	start := time.Now()
+	buf, err := io.ReadAll(br)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(buf))
+
+	// 10 KB at 20 KB/s, the first chunk is sent right away.
+	assert.Greater(t, time.Since(start), 300*time.Millisecond)
+	assert.Equal(t, int64(len(payload)), th.Transferred())
+}
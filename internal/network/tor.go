@@ -0,0 +1,147 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// DefaultTorProxy is the SOCKS proxy of a local Tor daemon. It is used for
+// onion remotes if no proxy is configured.
+const DefaultTorProxy = "socks5h://127.0.0.1:9050"
+
+// RemoteHost returns the host name of a git remote URL. It supports URLs
+// (https://host/repo, ssh://user@host:22/repo) as well as the scp-like
+// syntax (user@host:repo).
+func RemoteHost(remote string) string {
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+
+		return u.Hostname()
+	}
+
+	host, _, found := strings.Cut(remote, ":")
+	if !found {
+		return ""
+	}
+
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+
+	return host
+}
+
+// IsOnion returns true if the remote is a Tor onion service.
+func IsOnion(remote string) bool {
+	return strings.HasSuffix(strings.ToLower(RemoteHost(remote)), ".onion")
+}
+
+// IsSSH returns true if git will use SSH to talk to the remote.
+func IsSSH(remote string) bool {
+	if strings.HasPrefix(remote, "ssh://") || strings.HasPrefix(remote, "git+ssh://") {
+		return true
+	}
+
+	return !strings.Contains(remote, "://") && RemoteHost(remote) != ""
+}
+
+// RemoteProxy returns the proxy to use for the given git remote or nil if
+// the proxy from the environment should be used. Onion remotes always use a
+// proxy (the local Tor daemon by default) and require the proxy to resolve
+// host names, so the existence of the remote is never leaked to the local
+// resolver.
+func RemoteProxy(ctx context.Context, remote string) (*url.URL, error) {
+	u, err := Proxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsOnion(remote) {
+		return u, nil
+	}
+
+	if u == nil {
+		u, _ = url.Parse(DefaultTorProxy)
+	}
+
+	if u.Scheme == "socks5" {
+		return nil, fmt.Errorf("onion remote %s requires the proxy to resolve host names, use socks5h:// instead of socks5:// in network.proxy", RemoteHost(remote))
+	}
+
+	return u, nil
+}
+
+// CheckProxy makes sure the proxy is reachable, so we fail early instead of
+// falling back to a direct connection.
+func CheckProxy(ctx context.Context, proxy *url.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", proxy.Host)
+	if err != nil {
+		return fmt.Errorf("proxy %s is not reachable. Is Tor running? %w", proxy.Redacted(), err)
+	}
+	_ = conn.Close()
+
+	return nil
+}
+
+// SSHProxyCommand returns the ssh ProxyCommand to tunnel SSH connections
+// through the given proxy. It can be overridden with
+// network.ssh-proxy-command, unless the bandwidth is limited. Throttled
+// connections always use the local throttling proxy.
+func SSHProxyCommand(ctx context.Context, proxy *url.URL) string {
+	if pc := config.String(ctx, "network.ssh-proxy-command"); pc != "" && throttleFromContext(ctx) == nil {
+		return pc
+	}
+
+	mode := "5"
+	if proxy.Scheme == "http" || proxy.Scheme == "https" {
+		mode = "connect"
+	}
+
+	// OpenBSD netcat, available on most systems. ncat or connect-proxy
+	// work as well, see network.ssh-proxy-command.
+	return fmt.Sprintf("nc -X %s -x %s %%h %%p", mode, proxy.Host)
+}
+
+// GitRemoteArgs returns git options (-c key=value) that route the
+// connection to the remote through the proxy, if any.
+func GitRemoteArgs(ctx context.Context, remote, sshCommand string) ([]string, error) {
+	proxy, err := RemoteProxy(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy == nil {
+		return nil, nil
+	}
+
+	if IsOnion(remote) {
+		if err := CheckProxy(ctx, proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	debug.Log("using proxy %s for remote %s", proxy.Redacted(), remote)
+
+	args := []string{"-c", "http.proxy=" + proxy.String()}
+	if IsSSH(remote) {
+		if sshCommand == "" {
+			sshCommand = "ssh"
+		}
+		args = append(args, "-c", fmt.Sprintf("core.sshCommand=%s -o %q", sshCommand, "ProxyCommand="+SSHProxyCommand(ctx, proxy)))
+	}
+
+	return args, nil
+}
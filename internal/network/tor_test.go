@@ -0,0 +1,71 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteHost(t *testing.T) {
+	t.Parallel()
+
+	for remote, host := range map[string]string{
+		"https://github.com/gopasspw/gopass.git": "github.com",
+		"ssh://git@example.onion:2222/store.git": "example.onion",
+		"git@example.onion:store.git":            "example.onion",
+		"example.org:store":                      "example.org",
+		"/srv/git/store.git":                     "",
+		"file:///srv/git/store.git":              "",
+	} {
+		assert.Equal(t, host, RemoteHost(remote), remote)
+	}
+
+	assert.True(t, IsOnion("git@abcdef.onion:store.git"))
+	assert.False(t, IsOnion("git@github.com:foo/store.git"))
+	assert.True(t, IsSSH("git@github.com:foo/store.git"))
+	assert.True(t, IsSSH("ssh://git@github.com/foo/store.git"))
+	assert.False(t, IsSSH("https://github.com/foo/store.git"))
+}
+
+func TestRemoteProxy(t *testing.T) {
+	t.Parallel()
+
+	u, err := RemoteProxy(newCtx(t), "https://github.com/foo/bar")
+	require.NoError(t, err)
+	assert.Nil(t, u)
+
+	u, err = RemoteProxy(newCtx(t), "git@abcdef.onion:store.git")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTorProxy, u.String())
+
+	_, err = RemoteProxy(newCtx(t, "network.proxy", "socks5://127.0.0.1:9050"), "git@abcdef.onion:store.git")
+	assert.Error(t, err)
+}
+
+func TestGitRemoteArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := GitRemoteArgs(newCtx(t), "https://github.com/foo/bar", "ssh")
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	args, err = GitRemoteArgs(newCtx(t, "network.proxy", "socks5h://127.0.0.1:1080"), "git@github.com:foo/bar", "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"-c", "http.proxy=socks5h://127.0.0.1:1080",
+		"-c", `core.sshCommand=ssh -o "ProxyCommand=nc -X 5 -x 127.0.0.1:1080 %h %p"`,
+	}, args)
+
+	// onion remotes fail if the proxy is not running.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	_, err = GitRemoteArgs(newCtx(t, "network.proxy", "socks5h://"+addr), "git@abcdef.onion:store.git", "ssh")
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	_, err = GitRemoteArgs(newCtx(t, "network.proxy", "socks5h://"+addr), "git@abcdef.onion:store.git", "ssh")
+	assert.Error(t, err)
+}
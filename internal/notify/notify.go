@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"os"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+)
+
+// disabled returns true if no desktop notifications should be shown, i.e.
+// GOPASS_NO_NOTIFY is set, core.notifications is off or gopass runs in
+// quiet mode.
+func disabled(ctx context.Context) bool {
+	return os.Getenv("GOPASS_NO_NOTIFY") != "" || !config.Bool(ctx, "core.notifications") || ctxutil.IsQuiet(ctx)
+}
+
+// Subscribe shows a desktop notification whenever a sync brought changes.
+func Subscribe(b *event.Bus) func() {
+	return b.Subscribe(func(ctx context.Context, ev event.Event) {
+		if len(ev.Entries) < 1 {
+			return
+		}
+
+		_ = Notify(ctx, "gopass - sync", ev.Message)
+	}, event.SyncCompleted)
+}
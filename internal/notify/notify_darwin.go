@@ -5,10 +5,7 @@ package notify
 
 import (
 	"context"
-	"os"
 	"os/exec"
-
-	"github.com/gopasspw/gopass/internal/config"
 )
 
 const (
@@ -23,7 +20,7 @@ var (
 
 // Notify displays a desktop notification using osascript.
 func Notify(ctx context.Context, subj, msg string) error {
-	if os.Getenv("GOPASS_NO_NOTIFY") != "" || !config.Bool(ctx, "core.notifications") {
+	if disabled(ctx) {
 		return nil
 	}
 
@@ -5,16 +5,14 @@ package notify
 
 import (
 	"context"
-	"os"
 
 	"github.com/godbus/dbus"
-	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 // Notify displays a desktop notification with dbus.
 func Notify(ctx context.Context, subj, msg string) error {
-	if os.Getenv("GOPASS_NO_NOTIFY") != "" || !config.Bool(ctx, "core.notifications") {
+	if disabled(ctx) {
 		debug.Log("Notifications disabled")
 
 		return nil
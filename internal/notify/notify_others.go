@@ -11,5 +11,9 @@ import (
 
 // Notify is not yet implemented on this platform
 func Notify(ctx context.Context, subj, msg string) error {
+	if disabled(ctx) {
+		return nil
+	}
+
 	return fmt.Errorf("GOOS %s not yet supported", runtime.GOOS)
 }
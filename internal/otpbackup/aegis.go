@@ -0,0 +1,351 @@
+package otpbackup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// errScryptParams is returned if a backup uses scrypt parameters that exceed
+// the supported limits.
+var errScryptParams = errors.New("scrypt parameters out of range")
+
+// Aegis encrypts the database with a random master key (AES-GCM). The
+// master key is stored in password slots, encrypted with a key derived
+// with scrypt.
+const (
+	aegisSlotPassword = 1
+	aegisScryptN      = 32768
+	aegisScryptR      = 8
+	aegisScryptP      = 1
+	aegisKeyLen       = 32
+	aegisNonceLen     = 12
+	aegisTagLen       = 16
+)
+
+// The scrypt parameters are read from the backup. Slots exceeding these
+// limits (up to 1 GiB of memory) are skipped so a crafted backup can not
+// exhaust memory or CPU.
+const (
+	aegisMaxScryptN = 1 << 20
+	aegisMaxScryptR = 8
+	aegisMaxScryptP = 16
+)
+
+type aegisVault struct {
+	Version int             `json:"version"`
+	Header  aegisHeader     `json:"header"`
+	DB      json.RawMessage `json:"db"`
+}
+
+type aegisHeader struct {
+	Slots  []aegisSlot  `json:"slots"`
+	Params *aegisParams `json:"params"`
+}
+
+type aegisParams struct {
+	Nonce string `json:"nonce"`
+	Tag   string `json:"tag"`
+}
+
+type aegisSlot struct {
+	Type      int          `json:"type"`
+	UUID      string       `json:"uuid"`
+	Key       string       `json:"key"`
+	KeyParams *aegisParams `json:"key_params"`
+	N         int          `json:"n,omitempty"`
+	R         int          `json:"r,omitempty"`
+	P         int          `json:"p,omitempty"`
+	Salt      string       `json:"salt,omitempty"`
+	Repaired  bool         `json:"repaired,omitempty"`
+}
+
+type aegisDB struct {
+	Version int          `json:"version"`
+	Entries []aegisEntry `json:"entries"`
+}
+
+type aegisEntry struct {
+	Type     string    `json:"type"`
+	UUID     string    `json:"uuid"`
+	Name     string    `json:"name"`
+	Issuer   string    `json:"issuer"`
+	Note     string    `json:"note"`
+	Favorite bool      `json:"favorite"`
+	Icon     *string   `json:"icon"`
+	Info     aegisInfo `json:"info"`
+}
+
+type aegisInfo struct {
+	Secret  string `json:"secret"`
+	Algo    string `json:"algo"`
+	Digits  int    `json:"digits"`
+	Period  int    `json:"period,omitempty"`
+	Counter uint64 `json:"counter,omitempty"`
+}
+
+func exportAegis(entries []Entry, password []byte) ([]byte, error) {
+	db := aegisDB{
+		Version: 2,
+		Entries: make([]aegisEntry, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		e = e.normalize()
+		ae := aegisEntry{
+			Type:   e.Type,
+			UUID:   newUUID(),
+			Name:   e.Account,
+			Issuer: e.Issuer,
+			Info: aegisInfo{
+				Secret: e.Secret,
+				Algo:   e.Algorithm,
+				Digits: e.Digits,
+			},
+		}
+		if e.Type == "hotp" {
+			ae.Info.Counter = e.Counter
+		} else {
+			ae.Info.Period = e.Period
+		}
+		db.Entries = append(db.Entries, ae)
+	}
+
+	plain, err := json.Marshal(db)
+	if err != nil {
+		return nil, err
+	}
+
+	vault := aegisVault{Version: 1}
+
+	if len(password) < 1 {
+		vault.DB = plain
+
+		return json.MarshalIndent(vault, "", "  ")
+	}
+
+	masterKey := make([]byte, aegisKeyLen)
+	salt := make([]byte, 32)
+	for _, b := range [][]byte{masterKey, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+
+	slotKey, err := scrypt.Key(password, salt, aegisScryptN, aegisScryptR, aegisScryptP, aegisKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	encKey, keyParams, err := aegisSeal(slotKey, masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encDB, params, err := aegisSeal(masterKey, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	vault.Header = aegisHeader{
+		Slots: []aegisSlot{{
+			Type:      aegisSlotPassword,
+			UUID:      newUUID(),
+			Key:       hex.EncodeToString(encKey),
+			KeyParams: keyParams,
+			N:         aegisScryptN,
+			R:         aegisScryptR,
+			P:         aegisScryptP,
+			Salt:      hex.EncodeToString(salt),
+			Repaired:  true,
+		}},
+		Params: params,
+	}
+
+	vault.DB, err = json.Marshal(base64.StdEncoding.EncodeToString(encDB))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(vault, "", "  ")
+}
+
+func importAegis(buf, password []byte) ([]Entry, error) {
+	var vault aegisVault
+	if err := json.Unmarshal(buf, &vault); err != nil {
+		return nil, fmt.Errorf("failed to parse Aegis vault: %w", err)
+	}
+
+	plain := []byte(vault.DB)
+	if vault.Header.Params != nil {
+		if len(password) < 1 {
+			return nil, ErrNeedPassword
+		}
+
+		var err error
+		plain, err = aegisDecrypt(vault, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var db aegisDB
+	if err := json.Unmarshal(plain, &db); err != nil {
+		return nil, fmt.Errorf("failed to parse Aegis database: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(db.Entries))
+	for _, ae := range db.Entries {
+		if t := strings.ToLower(ae.Type); t != "totp" && t != "hotp" {
+			return nil, fmt.Errorf("unsupported OTP type %s of %s", ae.Type, ae.Name)
+		}
+
+		entries = append(entries, Entry{
+			Type:      ae.Type,
+			Issuer:    ae.Issuer,
+			Account:   ae.Name,
+			Secret:    ae.Info.Secret,
+			Algorithm: ae.Info.Algo,
+			Digits:    ae.Info.Digits,
+			Period:    ae.Info.Period,
+			Counter:   ae.Info.Counter,
+		}.normalize())
+	}
+
+	return entries, nil
+}
+
+// aegisDecrypt tries all password slots to decrypt the master key and
+// returns the decrypted database.
+func aegisDecrypt(vault aegisVault, password []byte) ([]byte, error) {
+	var encDB string
+	if err := json.Unmarshal(vault.DB, &encDB); err != nil {
+		return nil, fmt.Errorf("failed to parse Aegis database: %w", err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Aegis database: %w", err)
+	}
+
+	var paramErr error
+	for _, slot := range vault.Header.Slots {
+		if slot.Type != aegisSlotPassword || slot.KeyParams == nil {
+			continue
+		}
+
+		if err := checkScryptParams(slot.N, slot.R, slot.P); err != nil {
+			paramErr = err
+
+			continue
+		}
+
+		salt, err := hex.DecodeString(slot.Salt)
+		if err != nil {
+			continue
+		}
+
+		encKey, err := hex.DecodeString(slot.Key)
+		if err != nil {
+			continue
+		}
+
+		slotKey, err := scrypt.Key(password, salt, slot.N, slot.R, slot.P, aegisKeyLen)
+		if err != nil {
+			continue
+		}
+
+		masterKey, err := aegisOpen(slotKey, encKey, slot.KeyParams)
+		if err != nil {
+			continue
+		}
+
+		return aegisOpen(masterKey, ciphertext, vault.Header.Params)
+	}
+
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	return nil, ErrPassword
+}
+
+// checkScryptParams returns an error if the scrypt parameters of a slot are
+// outside of the supported range.
+func checkScryptParams(n, r, p int) error {
+	if n < 2 || n > aegisMaxScryptN || r < 1 || r > aegisMaxScryptR || p < 1 || p > aegisMaxScryptP {
+		return fmt.Errorf("unsupported scrypt parameters N=%d, r=%d, p=%d: %w", n, r, p, errScryptParams)
+	}
+
+	return nil
+}
+
+func aegisSeal(key, plain []byte) ([]byte, *aegisParams, error) {
+	gcm, err := aegisCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, aegisNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	sealed := gcm.Seal(nil, nonce, plain, nil)
+	ciphertext, tag := sealed[:len(sealed)-aegisTagLen], sealed[len(sealed)-aegisTagLen:]
+
+	return ciphertext, &aegisParams{
+		Nonce: hex.EncodeToString(nonce),
+		Tag:   hex.EncodeToString(tag),
+	}, nil
+}
+
+func aegisOpen(key, ciphertext []byte, params *aegisParams) ([]byte, error) {
+	gcm, err := aegisCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(params.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, ErrPassword
+	}
+
+	tag, err := hex.DecodeString(params.Tag)
+	if err != nil {
+		return nil, ErrPassword
+	}
+
+	plain, err := gcm.Open(nil, nonce, append(append([]byte{}, ciphertext...), tag...), nil)
+	if err != nil {
+		return nil, ErrPassword
+	}
+
+	return plain, nil
+}
+
+func aegisCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,170 @@
+package otpbackup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// andOTP encrypts its backups with AES-GCM. The key is derived with
+// PBKDF2-HMAC-SHA1. The file starts with the iterations (4 bytes, big
+// endian), followed by the salt and the nonce.
+const (
+	andOTPSaltLen       = 12
+	andOTPNonceLen      = 12
+	andOTPKeyLen        = 32
+	andOTPMinIterations = 140000
+	andOTPMaxIterations = 160000
+)
+
+type andOTPEntry struct {
+	Secret        string   `json:"secret"`
+	Issuer        string   `json:"issuer"`
+	Label         string   `json:"label"`
+	Digits        int      `json:"digits"`
+	Type          string   `json:"type"`
+	Algorithm     string   `json:"algorithm"`
+	Thumbnail     string   `json:"thumbnail"`
+	LastUsed      int64    `json:"last_used"`
+	UsedFrequency int      `json:"used_frequency"`
+	Period        int      `json:"period,omitempty"`
+	Counter       uint64   `json:"counter,omitempty"`
+	Tags          []string `json:"tags"`
+}
+
+func exportAndOTP(entries []Entry, password []byte) ([]byte, error) {
+	if len(password) < 1 {
+		return nil, ErrNeedPassword
+	}
+
+	ae := make([]andOTPEntry, 0, len(entries))
+	for _, e := range entries {
+		e = e.normalize()
+		a := andOTPEntry{
+			Secret:    e.Secret,
+			Issuer:    e.Issuer,
+			Label:     e.Account,
+			Digits:    e.Digits,
+			Type:      strings.ToUpper(e.Type),
+			Algorithm: e.Algorithm,
+			Thumbnail: "Default",
+			Tags:      []string{},
+		}
+		if e.Type == "hotp" {
+			a.Counter = e.Counter
+		} else {
+			a.Period = e.Period
+		}
+		ae = append(ae, a)
+	}
+
+	plain, err := json.Marshal(ae)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(andOTPMaxIterations-andOTPMinIterations))
+	if err != nil {
+		return nil, err
+	}
+	iter := uint32(andOTPMinIterations + n.Int64())
+
+	salt := make([]byte, andOTPSaltLen)
+	nonce := make([]byte, andOTPNonceLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	gcm, err := andOTPCipher(password, salt, iter)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.BigEndian, iter)
+	buf.Write(salt)
+	buf.Write(nonce)
+	buf.Write(gcm.Seal(nil, nonce, plain, nil))
+
+	return buf.Bytes(), nil
+}
+
+func importAndOTP(buf, password []byte) ([]Entry, error) {
+	// unencrypted backups are plain JSON.
+	if !bytes.HasPrefix(bytes.TrimSpace(buf), []byte("[")) {
+		if len(password) < 1 {
+			return nil, ErrNeedPassword
+		}
+
+		if len(buf) < 4+andOTPSaltLen+andOTPNonceLen {
+			return nil, ErrPassword
+		}
+
+		iter := binary.BigEndian.Uint32(buf[:4])
+		salt := buf[4 : 4+andOTPSaltLen]
+		nonce := buf[4+andOTPSaltLen : 4+andOTPSaltLen+andOTPNonceLen]
+
+		gcm, err := andOTPCipher(password, salt, iter)
+		if err != nil {
+			return nil, err
+		}
+
+		plain, err := gcm.Open(nil, nonce, buf[4+andOTPSaltLen+andOTPNonceLen:], nil)
+		if err != nil {
+			return nil, ErrPassword
+		}
+		buf = plain
+	}
+
+	var ae []andOTPEntry
+	if err := json.Unmarshal(buf, &ae); err != nil {
+		return nil, fmt.Errorf("failed to parse andOTP backup: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(ae))
+	for _, a := range ae {
+		if t := strings.ToLower(a.Type); t != "totp" && t != "hotp" {
+			return nil, fmt.Errorf("unsupported OTP type %s of %s", a.Type, a.Label)
+		}
+
+		entries = append(entries, Entry{
+			Type:      a.Type,
+			Issuer:    a.Issuer,
+			Account:   a.Label,
+			Secret:    a.Secret,
+			Algorithm: a.Algorithm,
+			Digits:    a.Digits,
+			Period:    a.Period,
+			Counter:   a.Counter,
+		}.normalize())
+	}
+
+	return entries, nil
+}
+
+func andOTPCipher(password, salt []byte, iter uint32) (cipher.AEAD, error) {
+	if iter < 1 || iter > 10*andOTPMaxIterations {
+		return nil, ErrPassword
+	}
+
+	key := pbkdf2.Key(password, salt, int(iter), andOTPKeyLen, sha1.New)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
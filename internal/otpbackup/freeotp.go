@@ -0,0 +1,91 @@
+package otpbackup
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FreeOTP+ exports its tokens as plain JSON. The seeds are arrays of signed
+// bytes.
+type freeOTPBackup struct {
+	TokenOrder []string       `json:"tokenOrder"`
+	Tokens     []freeOTPToken `json:"tokens"`
+}
+
+type freeOTPToken struct {
+	Algo      string `json:"algo"`
+	Counter   uint64 `json:"counter"`
+	Digits    int    `json:"digits"`
+	IssuerExt string `json:"issuerExt"`
+	Label     string `json:"label"`
+	Period    int    `json:"period"`
+	Secret    []int8 `json:"secret"`
+	Type      string `json:"type"`
+}
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func exportFreeOTP(entries []Entry) ([]byte, error) {
+	b := freeOTPBackup{
+		TokenOrder: make([]string, 0, len(entries)),
+		Tokens:     make([]freeOTPToken, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		e = e.normalize()
+
+		raw, err := b32.DecodeString(e.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret of %s: %w", e.Account, err)
+		}
+
+		secret := make([]int8, len(raw))
+		for i, c := range raw {
+			secret[i] = int8(c)
+		}
+
+		b.Tokens = append(b.Tokens, freeOTPToken{
+			Algo:      e.Algorithm,
+			Counter:   e.Counter,
+			Digits:    e.Digits,
+			IssuerExt: e.Issuer,
+			Label:     e.Account,
+			Period:    e.Period,
+			Secret:    secret,
+			Type:      strings.ToUpper(e.Type),
+		})
+		b.TokenOrder = append(b.TokenOrder, e.Issuer+":"+e.Account)
+	}
+
+	return json.MarshalIndent(b, "", "  ")
+}
+
+func importFreeOTP(buf []byte) ([]Entry, error) {
+	var b freeOTPBackup
+	if err := json.Unmarshal(buf, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse FreeOTP+ backup: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(b.Tokens))
+	for _, t := range b.Tokens {
+		raw := make([]byte, len(t.Secret))
+		for i, c := range t.Secret {
+			raw[i] = byte(c)
+		}
+
+		entries = append(entries, Entry{
+			Type:      t.Type,
+			Issuer:    t.IssuerExt,
+			Account:   t.Label,
+			Secret:    b32.EncodeToString(raw),
+			Algorithm: t.Algo,
+			Digits:    t.Digits,
+			Period:    t.Period,
+			Counter:   t.Counter,
+		}.normalize())
+	}
+
+	return entries, nil
+}
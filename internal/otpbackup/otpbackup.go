@@ -0,0 +1,159 @@
+// Package otpbackup reads and writes the backup formats of popular
+// authenticator apps, so OTP seeds can be moved between gopass and the
+// apps on a phone.
+package otpbackup
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pquerna/otp"
+)
+
+// Supported formats.
+const (
+	FormatAndOTP  = "andotp"
+	FormatAegis   = "aegis"
+	FormatFreeOTP = "freeotp"
+)
+
+var (
+	// ErrPassword is returned if a backup can't be decrypted with the given
+	// password.
+	ErrPassword = errors.New("wrong password or corrupted backup")
+	// ErrNeedPassword is returned if a format requires a password.
+	ErrNeedPassword = errors.New("a password is required")
+)
+
+// Entry is an OTP seed.
+type Entry struct {
+	// Type is totp or hotp.
+	Type    string
+	Issuer  string
+	Account string
+	// Secret is the base32 encoded seed.
+	Secret string
+	// Algorithm is SHA1, SHA256 or SHA512.
+	Algorithm string
+	Digits    int
+	Period    int
+	Counter   uint64
+}
+
+// FromKey converts an OTP key to an entry.
+func FromKey(k *otp.Key) Entry {
+	e := Entry{
+		Type:      strings.ToLower(k.Type()),
+		Issuer:    k.Issuer(),
+		Account:   k.AccountName(),
+		Secret:    strings.ToUpper(k.Secret()),
+		Algorithm: strings.ToUpper(k.Algorithm().String()),
+		Digits:    k.Digits().Length(),
+		Period:    int(k.Period()),
+	}
+
+	if u, err := url.Parse(k.URL()); err == nil {
+		e.Counter, _ = strconv.ParseUint(u.Query().Get("counter"), 10, 64)
+	}
+
+	return e.normalize()
+}
+
+// URL returns the otpauth URL of the entry.
+func (e Entry) URL() string {
+	e = e.normalize()
+
+	label := e.Account
+	if e.Issuer != "" {
+		label = e.Issuer + ":" + e.Account
+	}
+
+	v := url.Values{}
+	v.Set("secret", e.Secret)
+	if e.Issuer != "" {
+		v.Set("issuer", e.Issuer)
+	}
+	v.Set("algorithm", e.Algorithm)
+	v.Set("digits", strconv.Itoa(e.Digits))
+	if e.Type == "hotp" {
+		v.Set("counter", strconv.FormatUint(e.Counter, 10))
+	} else {
+		v.Set("period", strconv.Itoa(e.Period))
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     e.Type,
+		Path:     "/" + label,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
+
+// normalize fills in the defaults.
+func (e Entry) normalize() Entry {
+	e.Type = strings.ToLower(e.Type)
+	if e.Type != "hotp" {
+		e.Type = "totp"
+	}
+	e.Algorithm = strings.ToUpper(e.Algorithm)
+	if e.Algorithm == "" {
+		e.Algorithm = "SHA1"
+	}
+	if e.Digits == 0 {
+		e.Digits = 6
+	}
+	if e.Period == 0 {
+		e.Period = 30
+	}
+	e.Secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(e.Secret, " ", "")), "=")
+
+	return e
+}
+
+// Formats returns the names of the supported formats.
+func Formats() []string {
+	names := []string{FormatAndOTP, FormatAegis, FormatFreeOTP}
+	sort.Strings(names)
+
+	return names
+}
+
+// Export writes the entries in the given format. The backup is encrypted with
+// the password if the format supports it.
+func Export(format string, entries []Entry, password []byte) ([]byte, error) {
+	switch format {
+	case FormatAndOTP:
+		return exportAndOTP(entries, password)
+	case FormatAegis:
+		return exportAegis(entries, password)
+	case FormatFreeOTP:
+		return exportFreeOTP(entries)
+	default:
+		return nil, fmt.Errorf("unknown format %q. Use one of %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// Import reads the entries from a backup in the given format.
+func Import(format string, buf, password []byte) ([]Entry, error) {
+	switch format {
+	case FormatAndOTP:
+		return importAndOTP(buf, password)
+	case FormatAegis:
+		return importAegis(buf, password)
+	case FormatFreeOTP:
+		return importFreeOTP(buf)
+	default:
+		return nil, fmt.Errorf("unknown format %q. Use one of %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// Encrypted returns true if the format encrypts the backup with a password.
+func Encrypted(format string) bool {
+	return format == FormatAndOTP || format == FormatAegis
+}
@@ -0,0 +1,108 @@
+package otpbackup
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pquerna/otp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEntries = []Entry{
+	{
+		Type:    "totp",
+		Issuer:  "Example",
+		Account: "alice@example.org",
+		Secret:  "JBSWY3DPEHPK3PXP",
+	},
+	{
+		Type:      "hotp",
+		Issuer:    "",
+		Account:   "bob",
+		Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		Algorithm: "SHA256",
+		Digits:    8,
+		Counter:   42,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := make([]Entry, 0, len(testEntries))
+	for _, e := range testEntries {
+		want = append(want, e.normalize())
+	}
+
+	for _, format := range Formats() {
+		for _, pw := range []string{"", "s3cret"} {
+			buf, err := Export(format, testEntries, []byte(pw))
+			if pw == "" && format == FormatAndOTP {
+				assert.ErrorIs(t, err, ErrNeedPassword)
+
+				continue
+			}
+			require.NoError(t, err, format)
+
+			entries, err := Import(format, buf, []byte(pw))
+			require.NoError(t, err, format)
+			assert.Equal(t, want, entries, format)
+
+			if pw != "" && Encrypted(format) {
+				assert.NotContains(t, string(buf), "JBSWY3DPEHPK3PXP", format)
+
+				_, err := Import(format, buf, []byte("wrong"))
+				assert.ErrorIs(t, err, ErrPassword, format)
+
+				_, err = Import(format, buf, nil)
+				assert.ErrorIs(t, err, ErrNeedPassword, format)
+			}
+		}
+	}
+}
+
+func TestAegisScryptLimits(t *testing.T) {
+	t.Parallel()
+
+	buf, err := Export(FormatAegis, testEntries, []byte("s3cret"))
+	require.NoError(t, err)
+
+	for _, params := range [][3]int{
+		{1 << 30, 8, 1},
+		{32768, 1 << 20, 1},
+		{32768, 8, 1 << 20},
+		{0, 8, 1},
+	} {
+		var vault aegisVault
+		require.NoError(t, json.Unmarshal(buf, &vault))
+		vault.Header.Slots[0].N = params[0]
+		vault.Header.Slots[0].R = params[1]
+		vault.Header.Slots[0].P = params[2]
+		crafted, err := json.Marshal(vault)
+		require.NoError(t, err)
+
+		_, err = Import(FormatAegis, crafted, []byte("s3cret"))
+		assert.ErrorIs(t, err, errScryptParams, params)
+	}
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range testEntries {
+		k, err := otp.NewKeyFromURL(e.URL())
+		require.NoError(t, err)
+		assert.Equal(t, e.normalize(), FromKey(k))
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := Export("authy", testEntries, nil)
+	assert.Error(t, err)
+
+	_, err = Import("authy", nil, nil)
+	assert.Error(t, err)
+}
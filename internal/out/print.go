@@ -2,6 +2,7 @@ package out
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -41,6 +42,7 @@ func Print(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "%s", arg)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+"%s"+newline(ctx), arg)
 }
 
@@ -50,24 +52,27 @@ func Printf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, format, args...)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+format+newline(ctx), args...)
 }
 
 // Notice prints the string with an exclamation mark.
 func Notice(ctx context.Context, arg any) {
-	if ctxutil.IsHidden(ctx) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
 		return
 	}
 	debug.LogN(1, "NOTICE: %s", arg)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+"⚠ %s"+newline(ctx), arg)
 }
 
 // Noticef prints the string with an exclamation mark in front.
 func Noticef(ctx context.Context, format string, args ...any) {
-	if ctxutil.IsHidden(ctx) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
 		return
 	}
 	debug.LogN(1, "NOTICE: "+format, args...)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+"⚠ "+format+newline(ctx), args...)
 }
 
@@ -77,6 +82,12 @@ func Error(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "ERROR: %s", arg)
+	clearSpinner()
+	if ctxutil.IsQuiet(ctx) {
+		printJSON("error", fmt.Sprint(arg))
+
+		return
+	}
 	fmt.Fprint(Stderr, color.RedString(Prefix(ctx)+"❌ %s"+newline(ctx), arg))
 }
 
@@ -86,24 +97,32 @@ func Errorf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "ERROR: "+format, args...)
+	clearSpinner()
+	if ctxutil.IsQuiet(ctx) {
+		printJSON("error", fmt.Sprintf(format, args...))
+
+		return
+	}
 	fmt.Fprint(Stderr, color.RedString(Prefix(ctx)+"❌ "+format+newline(ctx), args...))
 }
 
 // OK prints the string with a green checkmark in front.
 func OK(ctx context.Context, arg any) {
-	if ctxutil.IsHidden(ctx) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
 		return
 	}
 	debug.LogN(1, "OK: %s", arg)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+"✅ %s"+newline(ctx), arg)
 }
 
 // OKf prints the string in with an OK checkmark in front.
 func OKf(ctx context.Context, format string, args ...any) {
-	if ctxutil.IsHidden(ctx) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
 		return
 	}
 	debug.LogN(1, "OK: "+format, args...)
+	clearSpinner()
 	fmt.Fprintf(Stdout, Prefix(ctx)+"✅ "+format+newline(ctx), args...)
 }
 
@@ -113,6 +132,12 @@ func Warning(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "WARNING: %s", arg)
+	clearSpinner()
+	if ctxutil.IsQuiet(ctx) {
+		printJSON("warning", fmt.Sprint(arg))
+
+		return
+	}
 	fmt.Fprint(Stderr, color.YellowString(Prefix(ctx)+"⚠ %s"+newline(ctx), arg))
 }
 
@@ -122,5 +147,60 @@ func Warningf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "WARNING: "+format, args...)
+	clearSpinner()
+	if ctxutil.IsQuiet(ctx) {
+		printJSON("warning", fmt.Sprintf(format, args...))
+
+		return
+	}
 	fmt.Fprint(Stderr, color.YellowString(Prefix(ctx)+"⚠ "+format+newline(ctx), args...))
 }
+
+// Info prints a status message, e.g. that something was copied to the
+// clipboard. It is hidden in quiet mode.
+func Info(ctx context.Context, arg any) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
+		return
+	}
+	debug.LogN(1, "INFO: %s", arg)
+	clearSpinner()
+	fmt.Fprintf(Stdout, Prefix(ctx)+"%s"+newline(ctx), arg)
+}
+
+// Infof formats and prints a status message. It is hidden in quiet mode.
+func Infof(ctx context.Context, format string, args ...any) {
+	if ctxutil.IsHidden(ctx) || ctxutil.IsQuiet(ctx) {
+		return
+	}
+	debug.LogN(1, "INFO: "+format, args...)
+	clearSpinner()
+	fmt.Fprintf(Stdout, Prefix(ctx)+format+newline(ctx), args...)
+}
+
+// Verbosef prints details to stderr, but only if verbose output (-v) was
+// requested.
+func Verbosef(ctx context.Context, format string, args ...any) {
+	if ctxutil.IsHidden(ctx) || !ctxutil.IsVerbose(ctx) {
+		return
+	}
+	debug.LogN(1, "VERBOSE: "+format, args...)
+	clearSpinner()
+	fmt.Fprintf(Stderr, Prefix(ctx)+format+newline(ctx), args...)
+}
+
+// printJSON prints a message as a single line JSON object to stderr. It is
+// used for errors and warnings in quiet mode so they can be parsed by scripts.
+func printJSON(level, msg string) {
+	buf, err := json.Marshal(struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}{
+		Level:   level,
+		Message: msg,
+	})
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(Stderr, string(buf))
+}
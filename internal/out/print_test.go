@@ -30,3 +30,35 @@ func TestPrint(t *testing.T) {
 	assert.Equal(t, "foo = 42", buf.String())
 	buf.Reset()
 }
+
+func TestPrintQuiet(t *testing.T) {
+	ctx := ctxutil.WithVerbosity(context.Background(), ctxutil.VerbosityQuiet)
+	buf := &bytes.Buffer{}
+	ebuf := &bytes.Buffer{}
+	Stdout = buf
+	Stderr = ebuf
+	defer func() {
+		Stdout = os.Stdout
+		Stderr = os.Stderr
+	}()
+
+	// regular output is kept, notices and status messages are not.
+	Printf(ctx, "%s = %d", "foo", 42)
+	OKf(ctx, "done")
+	Noticef(ctx, "note")
+	Infof(ctx, "copied")
+	Verbosef(ctx, "details")
+	assert.Equal(t, "foo = 42\n", buf.String())
+	assert.Equal(t, "", ebuf.String())
+	buf.Reset()
+
+	Errorf(ctx, "failed to %s", "decrypt")
+	assert.Equal(t, `{"level":"error","message":"failed to decrypt"}`+"\n", ebuf.String())
+	ebuf.Reset()
+
+	ctx = ctxutil.WithVerbosity(ctx, ctxutil.VerbosityVerbose)
+	Verbosef(ctx, "details")
+	Infof(ctx, "copied")
+	assert.Equal(t, "details\n", ebuf.String())
+	assert.Equal(t, "copied\n", buf.String())
+}
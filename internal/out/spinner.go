@@ -0,0 +1,167 @@
+package out
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+var (
+	// SpinnerDelay is the time an operation may take before a spinner is shown.
+	// It is exported for tests.
+	SpinnerDelay = 500 * time.Millisecond
+
+	spinnerFrames   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerInterval = 100 * time.Millisecond
+
+	// spinnerMu guards the active spinner and all terminal output of it.
+	spinnerMu sync.Mutex
+	active    *Spinner
+)
+
+// Spinner shows a progress indicator and the elapsed time for long running
+// operations. It is only rendered if the operation takes longer than
+// SpinnerDelay and if we are running in a terminal. The spinner is drawn
+// at the current cursor position, so it can follow partial lines.
+type Spinner struct {
+	ctx     context.Context //nolint:containedctx
+	msg     string
+	step    string
+	start   time.Time
+	visible bool
+	drawn   bool
+	frame   int
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSpinner starts a new spinner for the given operation. Callers must call
+// Done when the operation is finished.
+func NewSpinner(ctx context.Context, msg string) *Spinner {
+	s := &Spinner{
+		ctx:     ctx,
+		msg:     msg,
+		start:   time.Now(),
+		visible: ctxutil.IsTerminal(ctx) && !ctxutil.IsHidden(ctx) && !ctxutil.IsQuiet(ctx),
+		done:    make(chan struct{}),
+	}
+
+	if !s.visible {
+		return s
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s
+}
+
+// Step updates the description of the current step, e.g. to report granular
+// progress of a subsystem.
+func (s *Spinner) Step(format string, args ...any) {
+	if s == nil {
+		return
+	}
+
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+
+	s.step = fmt.Sprintf(format, args...)
+}
+
+// Done stops the spinner and reports the elapsed time if the operation took
+// longer than SpinnerDelay.
+func (s *Spinner) Done() {
+	if s == nil {
+		return
+	}
+
+	elapsed := time.Since(s.start)
+	debug.LogN(1, "%s took %s", s.msg, elapsed)
+
+	if !s.visible {
+		return
+	}
+
+	close(s.done)
+	s.wg.Wait()
+
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+
+	s.clear()
+	if active == s {
+		active = nil
+	}
+
+	if elapsed >= SpinnerDelay {
+		fmt.Fprintf(Stderr, Prefix(s.ctx)+"⏱ %s took %s"+newline(s.ctx), s.msg, elapsed.Round(10*time.Millisecond))
+	}
+}
+
+func (s *Spinner) run() {
+	defer s.wg.Done()
+
+	select {
+	case <-s.done:
+		return
+	case <-time.After(SpinnerDelay):
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for {
+		s.draw()
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Spinner) draw() {
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+
+	active = s
+	s.clear()
+
+	txt := s.msg
+	if s.step != "" {
+		txt += ": " + s.step
+	}
+
+	// save cursor, draw, restore cursor.
+	fmt.Fprintf(Stderr, "\0337%s %s (%s)\0338", spinnerFrames[s.frame%len(spinnerFrames)], txt, time.Since(s.start).Round(time.Second))
+	s.frame++
+	s.drawn = true
+}
+
+// clear removes the spinner from the terminal. Must be called with
+// spinnerMu held.
+func (s *Spinner) clear() {
+	if !s.drawn {
+		return
+	}
+
+	fmt.Fprint(Stderr, "\033[K")
+	s.drawn = false
+}
+
+// clearSpinner removes the active spinner, if any, before other output is
+// written. The spinner will redraw itself on the next tick.
+func clearSpinner() {
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+
+	if active != nil {
+		active.clear()
+	}
+}
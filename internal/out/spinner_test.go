@@ -0,0 +1,48 @@
+package out
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinner(t *testing.T) { //nolint:paralleltest
+	buf := &bytes.Buffer{}
+	Stderr = buf
+	oldDelay := SpinnerDelay
+	defer func() {
+		Stderr = os.Stderr
+		SpinnerDelay = oldDelay
+	}()
+	SpinnerDelay = 10 * time.Millisecond
+
+	ctx := context.Background()
+
+	// not a terminal, nothing is printed.
+	sp := NewSpinner(ctxutil.WithTerminal(ctx, false), "quiet")
+	time.Sleep(30 * time.Millisecond)
+	sp.Done()
+	assert.Equal(t, "", buf.String())
+
+	// fast operations do not show a spinner.
+	sp = NewSpinner(ctxutil.WithTerminal(ctx, true), "fast")
+	sp.Done()
+	assert.Equal(t, "", buf.String())
+
+	sp = NewSpinner(ctxutil.WithTerminal(ctx, true), "slow")
+	sp.Step("step %d", 1)
+	time.Sleep(50 * time.Millisecond)
+	sp.Done()
+	assert.Contains(t, buf.String(), "slow: step 1")
+	assert.Contains(t, buf.String(), "⏱ slow took")
+
+	// a nil spinner is safe to use.
+	var nsp *Spinner
+	nsp.Step("foo")
+	nsp.Done()
+}
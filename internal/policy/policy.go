@@ -0,0 +1,304 @@
+// Package policy implements password policy packs. A policy pack is a JSON
+// bundle of rules published by a security team, e.g. a minimum length,
+// required character classes or forbidden patterns. Packs are stored inside
+// the password store, so every member of a team follows the same, versioned
+// policy. Both audit and generate consume them.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/pwgen"
+)
+
+// Character classes that can be required by a rule.
+const (
+	ClassLower   = "lower"
+	ClassUpper   = "upper"
+	ClassDigit   = "digit"
+	ClassSpecial = "special"
+)
+
+var reName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// ErrViolation is wrapped by all policy violations.
+var ErrViolation = errors.New("policy violation")
+
+// Pack is a named and versioned set of rules.
+type Pack struct {
+	Name        string `json:"name"`
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	Rules       []Rule `json:"rules"`
+}
+
+// Rule is a single password rule. Empty fields are not checked.
+type Rule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Match is a regular expression matching the names of the secrets this
+	// rule applies to. Empty matches all secrets.
+	Match     string `json:"match,omitempty"`
+	MinLength int    `json:"min_length,omitempty"`
+	MaxLength int    `json:"max_length,omitempty"`
+	// Charset lists the allowed characters. Use the class names or
+	// characters in brackets, e.g. "lower,upper,digit,[-_]".
+	Charset string `json:"charset,omitempty"`
+	// Require lists the character classes that must be present.
+	Require []string `json:"require,omitempty"`
+	// Deny lists regular expressions that must not match the password.
+	Deny           []string `json:"deny,omitempty"`
+	MaxConsecutive int      `json:"max_consecutive,omitempty"`
+
+	match *regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// Parse parses and validates a policy pack.
+func Parse(buf []byte) (*Pack, error) {
+	p := &Pack{}
+	if err := json.Unmarshal(buf, p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy pack: %w", err)
+	}
+
+	if !reName.MatchString(p.Name) {
+		return nil, fmt.Errorf("invalid policy pack name %q", p.Name)
+	}
+
+	if p.Version < 1 {
+		return nil, fmt.Errorf("policy pack %s: version must be positive", p.Name)
+	}
+
+	for i := range p.Rules {
+		if err := p.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("policy pack %s: rule %q: %w", p.Name, p.Rules[i].Name, err)
+		}
+	}
+
+	return p, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Match != "" {
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match: %w", err)
+		}
+		r.match = re
+	}
+
+	r.deny = make([]*regexp.Regexp, 0, len(r.Deny))
+	for _, d := range r.Deny {
+		re, err := regexp.Compile(d)
+		if err != nil {
+			return fmt.Errorf("invalid deny pattern: %w", err)
+		}
+		r.deny = append(r.deny, re)
+	}
+
+	for _, c := range r.Require {
+		if classChars(c) == "" {
+			return fmt.Errorf("unknown character class %q", c)
+		}
+	}
+
+	if r.MaxLength > 0 && r.MaxLength < r.MinLength {
+		return fmt.Errorf("max_length is less than min_length")
+	}
+
+	return nil
+}
+
+// Applies returns true if the rule applies to the given secret.
+func (r *Rule) Applies(name string) bool {
+	return r.match == nil || r.match.MatchString(name)
+}
+
+// Check returns an error for every requirement the password doesn't meet.
+func (r *Rule) Check(pw string) error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s: %w", r.Name, fmt.Sprintf(format, args...), ErrViolation))
+	}
+
+	if n := len([]rune(pw)); n < r.MinLength {
+		fail("shorter than %d characters", r.MinLength)
+	} else if r.MaxLength > 0 && n > r.MaxLength {
+		fail("longer than %d characters", r.MaxLength)
+	}
+
+	if cs := charset(r.Charset); cs != "" {
+		for _, c := range pw {
+			if !strings.ContainsRune(cs, c) {
+				fail("contains characters outside of the allowed set")
+
+				break
+			}
+		}
+	}
+
+	for _, c := range r.Require {
+		if !strings.ContainsAny(pw, classChars(c)) {
+			fail("no %s characters", c)
+		}
+	}
+
+	for i, re := range r.deny {
+		if re.MatchString(pw) {
+			fail("matches denied pattern %s", r.Deny[i])
+		}
+	}
+
+	if r.MaxConsecutive > 0 && maxRun(pw) > r.MaxConsecutive {
+		fail("more than %d identical characters in a row", r.MaxConsecutive)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check checks the password of the given secret against all rules of all
+// packs that apply to it.
+func Check(packs []*Pack, name, pw string) error {
+	var errs []error
+	for _, p := range packs {
+		for i := range p.Rules {
+			r := &p.Rules[i]
+			if !r.Applies(name) {
+				continue
+			}
+			if err := r.Check(pw); err != nil {
+				errs = append(errs, fmt.Errorf("%s v%d: %w", p.Name, p.Version, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Generator returns a password generator that satisfies all rules of all
+// packs that apply to the given secret. It returns nil if no rule applies.
+// The length is adjusted to the allowed range.
+func Generator(packs []*Pack, name string, length int, symbols bool) *pwgen.Cryptic {
+	var rules []*Rule
+	for _, p := range packs {
+		for i := range p.Rules {
+			if r := &p.Rules[i]; r.Applies(name) {
+				rules = append(rules, r)
+			}
+		}
+	}
+
+	if len(rules) < 1 {
+		return nil
+	}
+
+	c := pwgen.NewCryptic(length, symbols)
+	c.MaxTries = 1024
+
+	for _, r := range rules {
+		if c.Length < r.MinLength {
+			c.Length = r.MinLength
+		}
+		if r.MaxLength > 0 && c.Length > r.MaxLength {
+			c.Length = r.MaxLength
+		}
+
+		// required classes must be available, even without --symbols.
+		for _, req := range r.Require {
+			c.Chars = union(c.Chars, classChars(req))
+		}
+	}
+
+	// the allowed charsets restrict the characters further.
+	for _, r := range rules {
+		if cs := charset(r.Charset); cs != "" {
+			c.Chars = intersect(c.Chars, cs)
+		}
+	}
+
+	for _, r := range rules {
+		c.Validators = append(c.Validators, r.Check)
+	}
+
+	return c
+}
+
+// charset returns the characters of a comma separated list of class names
+// and bracketed characters.
+func charset(spec string) string {
+	if spec == "" {
+		return ""
+	}
+
+	var chars string
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]") && len(part) > 2 {
+			chars += part[1 : len(part)-1]
+
+			continue
+		}
+		chars += classChars(part)
+	}
+
+	return chars
+}
+
+func classChars(class string) string {
+	switch class {
+	case ClassLower:
+		return pwgen.Lower
+	case ClassUpper:
+		return pwgen.Upper
+	case ClassDigit:
+		return pwgen.Digits
+	case ClassSpecial:
+		return pwgen.Syms
+	default:
+		return ""
+	}
+}
+
+func union(a, b string) string {
+	for _, c := range b {
+		if !strings.ContainsRune(a, c) {
+			a += string(c)
+		}
+	}
+
+	return a
+}
+
+func intersect(a, b string) string {
+	var res strings.Builder
+	for _, c := range a {
+		if strings.ContainsRune(b, c) {
+			res.WriteRune(c)
+		}
+	}
+
+	return res.String()
+}
+
+// maxRun returns the length of the longest run of identical characters.
+func maxRun(pw string) int {
+	var maxN, n int
+	var last rune
+	for i, c := range pw {
+		if i > 0 && c == last {
+			n++
+		} else {
+			n = 1
+		}
+		last = c
+		if n > maxN {
+			maxN = n
+		}
+	}
+
+	return maxN
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPack = `{
+  "name": "acme",
+  "version": 2,
+  "rules": [
+    {
+      "name": "baseline",
+      "min_length": 12,
+      "require": ["lower", "upper", "digit"],
+      "deny": ["(?i)acme"],
+      "max_consecutive": 2
+    },
+    {
+      "name": "production",
+      "match": "^prod/",
+      "min_length": 24,
+      "max_length": 32,
+      "charset": "lower,upper,digit,[-_]",
+      "require": ["special"]
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	p, err := Parse([]byte(testPack))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", p.Name)
+	assert.Equal(t, 2, p.Version)
+	assert.Len(t, p.Rules, 2)
+
+	for _, tc := range []string{
+		`{`,
+		`{"name": "../acme", "version": 1}`,
+		`{"name": "acme"}`,
+		`{"name": "acme", "version": 1, "rules": [{"name": "r", "deny": ["("]}]}`,
+		`{"name": "acme", "version": 1, "rules": [{"name": "r", "require": ["emoji"]}]}`,
+		`{"name": "acme", "version": 1, "rules": [{"name": "r", "min_length": 8, "max_length": 4}]}`,
+	} {
+		_, err := Parse([]byte(tc))
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	p, err := Parse([]byte(testPack))
+	require.NoError(t, err)
+	packs := []*Pack{p}
+
+	assert.NoError(t, Check(packs, "web/example", "Correct9Horse"))
+	assert.NoError(t, Check(packs, "prod/db", "Correct9Horse-battery-staple"))
+
+	for name, pw := range map[string]string{
+		"short":        "Ab1",
+		"no digit":     "CorrectHorseBattery",
+		"denied":       "MyAcmePassword1",
+		"consecutive":  "Correct9Horrrse",
+		"prod charset": "Correct9Horse+battery+staple",
+		"prod special": "Correct9HorseBatteryStaple",
+	} {
+		secret := "web/example"
+		if name[:4] == "prod" {
+			secret = "prod/db"
+		}
+
+		err := Check(packs, secret, pw)
+		assert.ErrorIs(t, err, ErrViolation, name)
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+
+	p, err := Parse([]byte(testPack))
+	require.NoError(t, err)
+	packs := []*Pack{p}
+
+	assert.Nil(t, Generator(nil, "web/example", 16, false))
+
+	gen := Generator(packs, "web/example", 8, false)
+	require.NotNil(t, gen)
+	assert.Equal(t, 12, gen.Length)
+
+	gen = Generator(packs, "prod/db", 64, false)
+	require.NotNil(t, gen)
+	assert.Equal(t, 32, gen.Length)
+
+	for i := 0; i < 10; i++ {
+		pw := gen.Password()
+		require.NotEmpty(t, pw)
+		assert.NoError(t, Check(packs, "prod/db", pw))
+	}
+}
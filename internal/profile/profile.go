@@ -0,0 +1,235 @@
+// Package profile implements team profiles. A profile is a signed YAML file
+// describing the client configuration of a team: the stores with their
+// remotes and backends, templates, folder schemas and policy settings. It
+// never contains secrets. `gopass profile export` creates it and
+// `gopass profile import` applies it, so every member of a team converges on
+// the same configuration in one step.
+package profile
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrSignature is returned if the signature of a profile is invalid.
+	ErrSignature = errors.New("invalid profile signature")
+	// ErrUntrusted is returned if a profile is signed by an unknown key.
+	ErrUntrusted = errors.New("profile signed by an untrusted key")
+)
+
+// Profile is the shareable client configuration of a team.
+type Profile struct {
+	Name        string    `yaml:"name"`
+	Description string    `yaml:"description,omitempty"`
+	Created     time.Time `yaml:"created"`
+	Mounts      []Mount   `yaml:"mounts,omitempty"`
+	// Templates maps template names, including the mount point, to their
+	// content.
+	Templates map[string]string `yaml:"templates,omitempty"`
+	// Schemas maps folders to the keys every entry in them must have.
+	Schemas map[string][]string `yaml:"schemas,omitempty"`
+	// Config holds the policy settings, see Shareable.
+	Config map[string]string `yaml:"config,omitempty"`
+}
+
+// Mount is a store of the profile. The root store has an empty alias.
+type Mount struct {
+	Alias   string `yaml:"alias"`
+	Remote  string `yaml:"remote,omitempty"`
+	Crypto  string `yaml:"crypto,omitempty"`
+	Storage string `yaml:"storage,omitempty"`
+}
+
+// file is the on-disk format. The signature covers the exact bytes of the
+// embedded profile.
+type file struct {
+	Profile   string `yaml:"profile"`
+	Key       string `yaml:"key"`
+	Signature string `yaml:"signature"`
+}
+
+// shareable are the config keys, or prefixes of keys, that are part of a
+// profile. Anything pointing to local paths, hooks or credentials is left
+// out.
+var shareable = []string{
+	"audit.concurrency",
+	"audit.hibp-use-api",
+	"audit.otp-max-age",
+	"autosync.interval",
+	"clipboard.hygiene",
+	"core.airgapped",
+	"core.approvals",
+	"core.autoimport",
+	"core.autopush",
+	"core.autosync",
+	"core.cliptimeout",
+	"core.exportkeys",
+	"core.fips",
+	"core.protected",
+	"core.sandbox",
+	"core.showsafecontent",
+	"core.tombstonettl",
+	"edit.harden",
+	"generate.",
+	"names.",
+	"preset.",
+	"recipients.check",
+	"safecontent.",
+	"storage.",
+}
+
+// Shareable returns true if the config key is a policy setting that may be
+// part of a profile.
+func Shareable(key string) bool {
+	for _, s := range shareable {
+		if key == s || (strings.HasSuffix(s, ".") && strings.HasPrefix(key, s)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GenerateKey creates a new signing key.
+func GenerateKey() (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	return priv, nil
+}
+
+// EncodeKey returns the base64 encoding of a public key, as used in the
+// profile and in the list of trusted keys.
+func EncodeKey(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// Fingerprint returns a short, human readable fingerprint of an encoded
+// public key.
+func Fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return strings.ToUpper(hex.EncodeToString(sum[:8]))
+}
+
+// Sign returns the signed profile file.
+func (p *Profile) Sign(priv ed25519.PrivateKey) ([]byte, error) {
+	body, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid signing key")
+	}
+
+	buf, err := yaml.Marshal(file{
+		Profile:   string(body),
+		Key:       EncodeKey(pub),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	return buf, nil
+}
+
+// Parse verifies the signature of a profile file and returns the profile and
+// the encoded key it was signed with. Whether the key is trusted is up to
+// the caller.
+func Parse(buf []byte) (*Profile, string, error) {
+	f := file{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, "", fmt.Errorf("failed to parse profile: %w", err)
+	}
+
+	pub, err := base64.StdEncoding.DecodeString(f.Key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, "", fmt.Errorf("%w: invalid key", ErrSignature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(f.Signature)
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(pub), []byte(f.Profile), sig) {
+		return nil, f.Key, ErrSignature
+	}
+
+	p := &Profile{}
+	dec = yaml.NewDecoder(strings.NewReader(f.Profile))
+	dec.KnownFields(true)
+	if err := dec.Decode(p); err != nil {
+		return nil, f.Key, fmt.Errorf("failed to parse profile: %w", err)
+	}
+
+	if p.Name == "" {
+		return nil, f.Key, fmt.Errorf("profile has no name")
+	}
+
+	seen := make(map[string]bool, len(p.Mounts))
+	for _, m := range p.Mounts {
+		if strings.Contains(m.Alias, "..") || seen[m.Alias] {
+			return nil, f.Key, fmt.Errorf("profile %s: invalid mount alias %q", p.Name, m.Alias)
+		}
+		seen[m.Alias] = true
+	}
+
+	for k := range p.Config {
+		if !Shareable(k) {
+			return nil, f.Key, fmt.Errorf("profile %s: setting %s can not be shared", p.Name, k)
+		}
+	}
+
+	for name := range p.Templates {
+		if !validTemplateName(name) {
+			return nil, f.Key, fmt.Errorf("profile %s: invalid template name %q", p.Name, name)
+		}
+	}
+
+	return p, f.Key, nil
+}
+
+// validTemplateName returns true if the template stays inside of the store
+// it's written to.
+func validTemplateName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return false
+	}
+
+	for _, e := range strings.Split(name, "/") {
+		if e == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Trusted returns true if the encoded key is in the comma separated list of
+// trusted keys.
+func Trusted(key, trusted string) bool {
+	for _, k := range strings.Split(trusted, ",") {
+		if strings.TrimSpace(k) == key {
+			return true
+		}
+	}
+
+	return false
+}
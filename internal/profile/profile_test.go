@@ -0,0 +1,95 @@
+package profile
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignParse(t *testing.T) {
+	t.Parallel()
+
+	priv, err := GenerateKey()
+	require.NoError(t, err)
+
+	p := &Profile{
+		Name:    "team",
+		Created: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		Mounts: []Mount{
+			{Remote: "git@example.org:team/root.git", Crypto: "age", Storage: "gitfs"},
+			{Alias: "ops", Remote: "git@example.org:team/ops.git"},
+		},
+		Templates: map[string]string{"web": "username: \n"},
+		Schemas:   map[string][]string{"web": {"username", "url"}},
+		Config:    map[string]string{"generate.length": "32"},
+	}
+
+	buf, err := p.Sign(priv)
+	require.NoError(t, err)
+
+	got, key, err := Parse(buf)
+	require.NoError(t, err)
+	assert.Equal(t, p, got)
+	assert.Equal(t, EncodeKey(priv.Public().(ed25519.PublicKey)), key)
+	assert.Len(t, Fingerprint(key), 16)
+
+	t.Run("tampered", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := Parse([]byte(strings.Replace(string(buf), "ops.git", "evil.git", 1)))
+		assert.ErrorIs(t, err, ErrSignature)
+	})
+
+	t.Run("unshareable settings", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Profile{Name: "team", Config: map[string]string{"core.post-hook": "curl evil"}}
+		buf, err := p.Sign(priv)
+		require.NoError(t, err)
+
+		_, _, err = Parse(buf)
+		assert.Error(t, err)
+	})
+
+	t.Run("template names", func(t *testing.T) {
+		t.Parallel()
+
+		for _, name := range []string{"../../.ssh/authorized_keys", "web/../../x", "/etc/passwd", `..\x`, ""} {
+			p := &Profile{Name: "team", Templates: map[string]string{name: "x"}}
+			buf, err := p.Sign(priv)
+			require.NoError(t, err)
+
+			_, _, err = Parse(buf)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestShareable(t *testing.T) {
+	t.Parallel()
+
+	for k, want := range map[string]bool{
+		"generate.length":       true,
+		"core.autosync":         true,
+		"safecontent.mask":      true,
+		"core.post-hook":        false,
+		"mounts.path":           false,
+		"canary.webhook":        false,
+		"alias-service.url":     false,
+		"core.autosyncinterval": false,
+	} {
+		assert.Equal(t, want, Shareable(k), k)
+	}
+}
+
+func TestTrusted(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Trusted("b", "a, b"))
+	assert.False(t, Trusted("c", "a,b"))
+	assert.False(t, Trusted("c", ""))
+}
@@ -0,0 +1,255 @@
+// Package sandbox confines child processes like editors, external password
+// generators and hooks. The child is started through gopass itself. This
+// helper process restricts itself before executing the actual command, so the
+// restrictions are inherited by the command but never by gopass.
+//
+// Confined processes can not access the password stores, the gopass
+// configuration, data and cache directories and the GnuPG home directory.
+// Where supported they can not open network connections either.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+const (
+	// Arg is the first argument of the helper process.
+	Arg = "__sandbox"
+	// envDeny holds the list of denied paths for the helper process.
+	envDeny = "GOPASS_SANDBOX_DENY"
+)
+
+// ErrUnsupported is returned if the platform has no support for confining
+// child processes.
+var ErrUnsupported = errors.New("sandboxing is not supported on " + runtime.GOOS)
+
+// Enabled returns true if child processes should be confined.
+func Enabled(ctx context.Context) bool {
+	return config.Bool(ctx, "core.sandbox")
+}
+
+// Command returns an exec.Cmd that runs the given command inside the sandbox,
+// if enabled. Otherwise it's equivalent to exec.Command.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return wrap(ctx, exec.Command, name, args...)
+}
+
+// CommandContext is like Command but kills the process when the context is
+// done.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return wrap(ctx, func(name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, name, args...)
+	}, name, args...)
+}
+
+func wrap(ctx context.Context, cmdFn func(string, ...string) *exec.Cmd, name string, args ...string) *exec.Cmd {
+	if !Enabled(ctx) {
+		return cmdFn(name, args...)
+	}
+
+	if !supported() {
+		out.Warningf(ctx, "Can not sandbox %s: %s", name, ErrUnsupported)
+
+		return cmdFn(name, args...)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		out.Warningf(ctx, "Can not sandbox %s: %s", name, err)
+
+		return cmdFn(name, args...)
+	}
+
+	cmd := cmdFn(self, append([]string{Arg, name}, args...)...)
+	cmd.Env = append(os.Environ(), envDeny+"="+strings.Join(Denied(ctx), string(os.PathListSeparator)))
+
+	debug.Log("sandboxing %s %v", name, args)
+
+	return cmd
+}
+
+// Denied returns the paths a confined process must not access.
+func Denied(ctx context.Context) []string {
+	cfg := config.FromContext(ctx)
+
+	paths := []string{
+		appdir.UserConfig(),
+		appdir.UserData(),
+		appdir.UserCache(),
+		appdir.UserState(),
+	}
+
+	if sv := os.Getenv("GNUPGHOME"); sv != "" {
+		paths = append(paths, sv)
+	} else {
+		paths = append(paths, filepath.Join(appdir.UserHome(), ".gnupg"))
+	}
+
+	root := cfg.Path()
+	if sv := os.Getenv("PASSWORD_STORE_DIR"); sv != "" {
+		root = sv
+	}
+	paths = append(paths, root)
+
+	for _, mp := range cfg.Mounts() {
+		paths = append(paths, cfg.MountPath(mp))
+	}
+
+	res := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		res = append(res, fsutil.CleanPath(p))
+	}
+
+	return res
+}
+
+// Main is the entry point of the helper process. args are the command and its
+// arguments. It never returns.
+func Main(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "gopass: missing sandbox command")
+		os.Exit(1)
+	}
+
+	if err := run(args, filepath.SplitList(os.Getenv(envDeny))); err != nil {
+		fmt.Fprintf(os.Stderr, "gopass: failed to run %s in the sandbox: %s\n", args[0], err)
+		os.Exit(1)
+	}
+}
+
+func run(args, deny []string) error {
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, envDeny+"=") {
+			env = append(env, e)
+		}
+	}
+
+	// restrict and exec must happen on the same OS thread since some of the
+	// restrictions are per thread.
+	runtime.LockOSThread()
+
+	if err := restrict(resolve(deny)); err != nil {
+		return err
+	}
+
+	return execve(path, args, env)
+}
+
+// resolve makes all paths absolute and resolves symlinks, so they can be
+// compared with the paths found while walking the filesystem.
+func resolve(paths []string) []string {
+	res := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if ap, err := filepath.Abs(p); err == nil {
+			p = ap
+		}
+		if rp, err := filepath.EvalSymlinks(p); err == nil {
+			p = rp
+		}
+		res = append(res, filepath.Clean(p))
+	}
+
+	return res
+}
+
+// allowList returns the paths below root that may be accessed, i.e. all
+// paths that are neither denied nor an ancestor of a denied path. Ancestors
+// are walked recursively.
+func allowList(root string, deny []string) []string {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		debug.Log("failed to read %s: %s", root, err)
+
+		return nil
+	}
+
+	var res []string
+	for _, e := range entries {
+		p := filepath.Join(root, e.Name())
+		rp := p
+
+		if e.Type()&os.ModeSymlink != 0 {
+			var err error
+			rp, err = filepath.EvalSymlinks(p)
+			if err != nil {
+				continue
+			}
+			// the target is walked on its own if it's an ancestor.
+			if isDenied(rp, deny) || isAncestor(rp, deny) {
+				continue
+			}
+		}
+
+		if isDenied(rp, deny) {
+			continue
+		}
+
+		if isAncestor(rp, deny) {
+			if e.IsDir() {
+				res = append(res, allowList(p, deny)...)
+			}
+
+			continue
+		}
+
+		res = append(res, p)
+	}
+
+	return res
+}
+
+// isDenied returns true if p is one of the denied paths or inside one of them.
+func isDenied(p string, deny []string) bool {
+	for _, d := range deny {
+		if within(p, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAncestor returns true if p contains a denied path.
+func isAncestor(p string, deny []string) bool {
+	for _, d := range deny {
+		if p != d && within(d, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// within returns true if p is dir or inside of it.
+func within(p, dir string) bool {
+	if p == dir {
+		return true
+	}
+
+	return strings.HasPrefix(p, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
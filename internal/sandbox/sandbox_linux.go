@@ -0,0 +1,134 @@
+//go:build linux
+// +build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/sys/unix"
+)
+
+// Landlock network rights (ABI 4). Not yet defined in x/sys/unix.
+const (
+	landlockAccessNetBindTCP    = 1 << 0
+	landlockAccessNetConnectTCP = 1 << 1
+)
+
+// file rights can be granted on regular files, everything else only on
+// directories.
+const landlockFileRights = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+	unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_READ_FILE |
+	unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+// landlockRulesetAttr is struct landlock_ruleset_attr incl. the network
+// rights of ABI 4.
+type landlockRulesetAttr struct {
+	handledFS  uint64
+	handledNet uint64
+}
+
+func supported() bool {
+	return landlockABI() > 0
+}
+
+func landlockABI() int {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0
+	}
+
+	return int(abi)
+}
+
+// restrict uses landlock to deny access to the given paths. Since landlock
+// can only grant access, access is granted to everything else below /.
+// With landlock ABI 4 (Linux 6.7) TCP connections are denied as well.
+func restrict(deny []string) error {
+	abi := landlockABI()
+	if abi < 1 {
+		return ErrUnsupported
+	}
+
+	// ABI 1 rights.
+	handled := uint64(unix.LANDLOCK_ACCESS_FS_MAKE_SYM<<1 - 1)
+	if abi >= 2 {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := landlockRulesetAttr{handledFS: handled}
+	size := unsafe.Sizeof(attr.handledFS)
+	if abi >= 4 {
+		attr.handledNet = landlockAccessNetBindTCP | landlockAccessNetConnectTCP
+		size = unsafe.Sizeof(attr)
+	} else {
+		debug.Log("landlock ABI %d does not support network restrictions", abi)
+	}
+
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), size, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %w", errno)
+	}
+	defer unix.Close(int(fd)) //nolint:errcheck
+
+	for _, p := range allowList("/", deny) {
+		if err := landlockAllow(int(fd), p, handled); err != nil {
+			debug.Log("failed to allow %s: %s", p, err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce landlock ruleset: %w", errno)
+	}
+
+	return nil
+}
+
+func landlockAllow(rulesetFd int, path string, handled uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd) //nolint:errcheck
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return err
+	}
+
+	access := handled
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileRights
+	}
+
+	attr := unix.LandlockPathBeneathAttr{
+		Allowed_access: access,
+		Parent_fd:      int32(fd),
+	}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func execve(path string, args, env []string) error {
+	err := syscall.Exec(path, args, env)
+	if errors.Is(err, syscall.ENOENT) {
+		return fmt.Errorf("%s not found: %w", path, err)
+	}
+
+	return err
+}
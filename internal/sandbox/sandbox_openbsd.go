@@ -0,0 +1,25 @@
+//go:build openbsd
+// +build openbsd
+
+package sandbox
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+func supported() bool {
+	return true
+}
+
+// restrict pledges the promises of the executed command. unveil(2) does not
+// survive execve(2), so on OpenBSD the command can still access the denied
+// paths. Only network access is denied.
+func restrict(_ []string) error {
+	return unix.PledgeExecpromises("stdio rpath wpath cpath fattr flock tty proc exec getpw")
+}
+
+func execve(path string, args, env []string) error {
+	return syscall.Exec(path, args, env)
+}
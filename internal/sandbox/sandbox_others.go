@@ -0,0 +1,16 @@
+//go:build !linux && !openbsd
+// +build !linux,!openbsd
+
+package sandbox
+
+func supported() bool {
+	return false
+}
+
+func restrict(_ []string) error {
+	return ErrUnsupported
+}
+
+func execve(_ string, _, _ []string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,74 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowList(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	for _, d := range []string{
+		"usr/bin",
+		"home/user/.password-store/web",
+		"home/user/.config/gopass",
+		"home/user/.config/nvim",
+		"home/user/src",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(td, d), 0o700))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(td, "home/user/.vimrc"), []byte("x"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(td, "home/user/.password-store"), filepath.Join(td, "home/user/pw")))
+
+	deny := resolve([]string{
+		filepath.Join(td, "home/user/.password-store"),
+		filepath.Join(td, "home/user/.config/gopass"),
+	})
+
+	root, err := filepath.EvalSymlinks(td)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(root, "home/user/.config/nvim"),
+		filepath.Join(root, "home/user/.vimrc"),
+		filepath.Join(root, "home/user/src"),
+		filepath.Join(root, "usr"),
+	}, allowList(root, deny))
+}
+
+func TestWithin(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, within("/home/user/.password-store", "/home/user/.password-store"))
+	assert.True(t, within("/home/user/.password-store/web", "/home/user/.password-store"))
+	assert.False(t, within("/home/user/.password-store-old", "/home/user/.password-store"))
+	assert.True(t, within("/home", "/"))
+}
+
+func TestCommand(t *testing.T) {
+	ctx := config.NewNoWrites().WithConfig(context.Background())
+
+	cmd := Command(ctx, "vim", "foo")
+	assert.Equal(t, []string{"vim", "foo"}, cmd.Args)
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.sandbox", "true"))
+	require.NoError(t, cfg.SetPath("/tmp/store"))
+	ctx = cfg.WithConfig(context.Background())
+
+	assert.Contains(t, Denied(ctx), "/tmp/store")
+
+	if !supported() {
+		return
+	}
+
+	cmd = Command(ctx, "vim", "foo")
+	assert.Equal(t, []string{Arg, "vim", "foo"}, cmd.Args[1:])
+}
@@ -0,0 +1,280 @@
+// Package selftest implements `gopass selftest`. It exercises the configured
+// backends end to end in a throwaway store, so platform specific issues can
+// be debugged without touching the real stores.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+)
+
+const (
+	alias     = "selftest"
+	userName  = "gopass selftest"
+	userEmail = "selftest@gopass.pw"
+)
+
+// Result is the outcome of a single step.
+type Result struct {
+	Step     string
+	Duration time.Duration
+	// Skipped is set if the step didn't apply to the backends or an earlier
+	// step failed.
+	Skipped bool
+	Err     error
+	// Diag helps to debug failures, e.g. the backend versions or the state of
+	// the repository.
+	Diag string
+}
+
+// Failed returns true if any step failed.
+func Failed(res []Result) bool {
+	for _, r := range res {
+		if r.Err != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+type runner struct {
+	dir       string
+	recipient string
+
+	store *leaf.Store
+	// want is the expected plaintext of every entry.
+	want map[string][]byte
+}
+
+// Run creates a store in dir, which must be empty and is not removed, for
+// the recipient using the crypto and storage backends selected in the
+// context and runs all steps against it.
+func Run(ctx context.Context, dir, recipient string) []Result {
+	r := &runner{
+		dir:       dir,
+		recipient: recipient,
+		want:      make(map[string][]byte, 2),
+	}
+
+	steps := []struct {
+		name string
+		fn   func(context.Context) (string, error)
+	}{
+		{"init", r.init},
+		{"generate", r.generate},
+		{"insert", r.insert},
+		{"show", r.show},
+		{"move", r.move},
+		{"reencrypt", r.reencrypt},
+		{"sync", r.sync},
+	}
+
+	res := make([]Result, 0, len(steps))
+	failed := false
+
+	for _, step := range steps {
+		if failed {
+			res = append(res, Result{Step: step.name, Skipped: true, Diag: "an earlier step failed"})
+
+			continue
+		}
+
+		start := time.Now()
+		diag, err := step.fn(ctx)
+		rs := Result{
+			Step:     step.name,
+			Duration: time.Since(start),
+			Err:      err,
+			Diag:     diag,
+		}
+
+		if errors.Is(err, errSkipped) {
+			rs.Err = nil
+			rs.Skipped = true
+		}
+
+		if rs.Err != nil {
+			debug.Log("selftest step %s failed: %s", step.name, err)
+			rs.Diag = strings.TrimSpace(rs.Diag + "\n" + r.diagnostics(ctx))
+			failed = true
+		}
+
+		res = append(res, rs)
+	}
+
+	return res
+}
+
+// errSkipped marks steps that don't apply to the selected backends.
+var errSkipped = errors.New("skipped")
+
+func (r *runner) init(ctx context.Context) (string, error) {
+	path := filepath.Join(r.dir, "store")
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return "", err
+	}
+
+	s, err := leaf.Init(ctx, alias, path)
+	if err != nil {
+		return "", err
+	}
+
+	r.store = s
+
+	if err := s.Storage().InitConfig(ctx, userName, userEmail); err != nil && !errors.Is(err, backend.ErrNotSupported) {
+		return "", fmt.Errorf("failed to configure %s: %w", s.Storage().Name(), err)
+	}
+
+	if err := s.Init(ctx, s.Path(), r.recipient); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("crypto %s %s, storage %s %s", s.Crypto().Name(), s.Crypto().Version(ctx), s.Storage().Name(), s.Storage().Version(ctx)), nil
+}
+
+func (r *runner) generate(ctx context.Context) (string, error) {
+	sec := secrets.New()
+	sec.SetPassword(pwgen.GeneratePassword(24, true))
+
+	return "", r.set(ctx, "selftest/generated", sec)
+}
+
+func (r *runner) insert(ctx context.Context) (string, error) {
+	sec := secrets.NewAKV()
+	sec.SetPassword("correct horse battery staple")
+	_ = sec.Set("username", "gopher")
+	_, _ = sec.Write([]byte("multi\nline\nnotes ✓\n"))
+
+	return "", r.set(ctx, "selftest/inserted", sec)
+}
+
+func (r *runner) show(ctx context.Context) (string, error) {
+	return "", r.verify(ctx, r.store)
+}
+
+func (r *runner) move(ctx context.Context) (string, error) {
+	from, to := "selftest/inserted", "selftest/moved"
+
+	if err := r.store.Move(ctx, from, to); err != nil {
+		return "", err
+	}
+
+	if r.store.Exists(ctx, from) {
+		return "", fmt.Errorf("%s still exists after the move", from)
+	}
+
+	r.want[to] = r.want[from]
+	delete(r.want, from)
+
+	return "", r.verify(ctx, r.store)
+}
+
+func (r *runner) reencrypt(ctx context.Context) (string, error) {
+	if err := r.store.Reencrypt(ctx); err != nil {
+		return "", err
+	}
+
+	return "", r.verify(ctx, r.store)
+}
+
+// sync pushes the store to a local bare repository, clones it and decrypts
+// all entries from the clone.
+func (r *runner) sync(ctx context.Context) (string, error) {
+	st := r.store.Storage()
+	if st.Name() != "gitfs" {
+		return st.Name() + " doesn't sync", errSkipped
+	}
+
+	remote := filepath.Join(r.dir, "remote.git")
+	if buf, err := exec.CommandContext(ctx, "git", "init", "--bare", remote).CombinedOutput(); err != nil {
+		return string(buf), fmt.Errorf("failed to create remote: %w", err)
+	}
+
+	if err := st.AddRemote(ctx, "origin", remote); err != nil {
+		return "", err
+	}
+
+	if err := st.Push(ctx, "origin", ""); err != nil {
+		return "", fmt.Errorf("failed to push: %w", err)
+	}
+
+	clone := filepath.Join(r.dir, "clone")
+	if _, err := backend.Clone(ctx, backend.GitFS, remote, clone); err != nil {
+		return "", fmt.Errorf("failed to clone: %w", err)
+	}
+
+	cs, err := leaf.New(ctx, alias, clone)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.verify(ctx, cs); err != nil {
+		return "", fmt.Errorf("clone: %w", err)
+	}
+
+	if err := st.Pull(ctx, "origin", ""); err != nil {
+		return "", fmt.Errorf("failed to pull: %w", err)
+	}
+
+	return "", nil
+}
+
+func (r *runner) set(ctx context.Context, name string, sec gopass.Byter) error {
+	if err := r.store.Set(ctx, name, sec); err != nil {
+		return err
+	}
+
+	r.want[name] = sec.Bytes()
+
+	return nil
+}
+
+// verify decrypts all entries written so far and compares them.
+func (r *runner) verify(ctx context.Context, s *leaf.Store) error {
+	for name, want := range r.want {
+		sec, err := s.Get(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if got := sec.Bytes(); !bytes.Equal(got, want) {
+			return fmt.Errorf("%s changed: got %d bytes, want %d bytes", name, len(got), len(want))
+		}
+	}
+
+	return nil
+}
+
+// diagnostics describes the state of the store after a failure.
+func (r *runner) diagnostics(ctx context.Context) string {
+	if r.store == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	if l, err := r.store.Storage().List(ctx, ""); err == nil {
+		fmt.Fprintf(&sb, "files: %s\n", strings.Join(l, ", "))
+	}
+
+	if buf, err := r.store.Storage().Status(ctx); err == nil && len(buf) > 0 {
+		fmt.Fprintf(&sb, "status:\n%s", buf)
+	}
+
+	return sb.String()
+}
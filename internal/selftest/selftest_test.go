@@ -0,0 +1,51 @@
+package selftest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	_ "github.com/gopasspw/gopass/internal/backend/crypto"
+	_ "github.com/gopasspw/gopass/internal/backend/storage"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	_ = gptest.NewUnitTester(t)
+
+	for _, sb := range []backend.StorageBackend{backend.FS, backend.GitFS} {
+		sb := sb
+		t.Run(backend.StorageBackendName(sb), func(t *testing.T) {
+			ctx := context.Background()
+			ctx = ctxutil.WithAlwaysYes(ctx, true)
+			ctx = ctxutil.WithHidden(ctx, true)
+			ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+			ctx = backend.WithStorageBackend(ctx, sb)
+
+			res := Run(ctx, t.TempDir(), "0xDEADBEEF")
+			require.Len(t, res, 7)
+			for _, r := range res {
+				assert.NoError(t, r.Err, r.Step+": "+r.Diag)
+			}
+			assert.False(t, Failed(res))
+			assert.Contains(t, res[0].Diag, "crypto plain")
+			assert.Contains(t, res[0].Diag, "storage "+backend.StorageBackendName(sb))
+			assert.Equal(t, sb == backend.FS, res[6].Skipped)
+		})
+	}
+
+	t.Run("no recipient", func(t *testing.T) {
+		ctx := context.Background()
+		ctx = ctxutil.WithHidden(ctx, true)
+		ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+		ctx = backend.WithStorageBackend(ctx, backend.FS)
+
+		res := Run(ctx, t.TempDir(), "")
+		assert.True(t, Failed(res))
+		assert.Error(t, res[0].Err)
+		assert.True(t, res[1].Skipped)
+	})
+}
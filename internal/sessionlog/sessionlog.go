@@ -0,0 +1,155 @@
+// Package sessionlog implements the journal of mutating commands shown by
+// `gopass journal`. Every command that changes entries appends one record
+// with the command line, the entries it touched and the resulting commits.
+// Unlike the git history it spans all mounts and is never synced.
+package sessionlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Operations recorded for an entry.
+const (
+	OpSet    = "set"
+	OpDelete = "delete"
+	OpMove   = "move"
+	OpCopy   = "copy"
+	OpPrune  = "prune"
+	OpLink   = "link"
+)
+
+// Change is a single entry touched by a command.
+type Change struct {
+	Op    string `json:"op"`
+	Entry string `json:"entry"`
+	// From is the source of moves and copies.
+	From string `json:"from,omitempty"`
+	// Commit is the current commit of the entry's store after the command
+	// finished.
+	Commit string `json:"commit,omitempty"`
+}
+
+// Record is a single command in the journal.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Command []string  `json:"command"`
+	Changes []Change  `json:"changes"`
+}
+
+var (
+	mu      sync.Mutex
+	pending []Change
+)
+
+// Path returns the location of the journal.
+func Path() string {
+	return filepath.Join(appdir.UserState(), "journal.jsonl")
+}
+
+// Add records a change made by the current command.
+func Add(op, entry, from string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, c := range pending {
+		if c.Op == op && c.Entry == entry && c.From == from {
+			return
+		}
+	}
+
+	pending = append(pending, Change{Op: op, Entry: entry, From: from})
+}
+
+// Flush appends a record with all pending changes to the journal. commit is
+// used to look up the current commit of each entry's store. Commands that
+// didn't change anything are not recorded.
+func Flush(ctx context.Context, command []string, commit func(context.Context, string) string) error {
+	mu.Lock()
+	changes := pending
+	pending = nil
+	mu.Unlock()
+
+	if len(changes) < 1 {
+		return nil
+	}
+
+	for i := range changes {
+		if commit != nil {
+			changes[i].Commit = commit(ctx, changes[i].Entry)
+		}
+	}
+
+	buf, err := json.Marshal(Record{
+		Time:    time.Now().UTC(),
+		Command: command,
+		Changes: changes,
+	})
+	if err != nil {
+		return err
+	}
+
+	fn := Path()
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(fn), err)
+	}
+
+	fh, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal %s: %w", fn, err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	if _, err := fh.Write(append(buf, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal %s: %w", fn, err)
+	}
+
+	debug.Log("recorded %d changes in %s", len(changes), fn)
+
+	return nil
+}
+
+// Read returns all records since the given time, oldest first.
+func Read(since time.Time) ([]Record, error) {
+	fh, err := os.Open(Path())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	var recs []Record
+
+	s := bufio.NewScanner(fh)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for s.Scan() {
+		var r Record
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			debug.Log("skipping invalid journal line: %s", err)
+
+			continue
+		}
+
+		if r.Time.Before(since) {
+			continue
+		}
+
+		recs = append(recs, r)
+	}
+
+	return recs, s.Err()
+}
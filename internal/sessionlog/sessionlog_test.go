@@ -0,0 +1,48 @@
+package sessionlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	t.Setenv("GOPASS_STATE_DIR", t.TempDir())
+
+	ctx := context.Background()
+	commit := func(_ context.Context, name string) string {
+		return "abcdef-" + name
+	}
+
+	// nothing changed, nothing recorded.
+	require.NoError(t, Flush(ctx, []string{"show", "foo"}, commit))
+	recs, err := Read(time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+
+	Add(OpSet, "foo", "")
+	Add(OpSet, "foo", "")
+	Add(OpMove, "bar", "baz")
+	require.NoError(t, Flush(ctx, []string{"edit", "foo"}, commit))
+
+	Add(OpDelete, "bar", "")
+	require.NoError(t, Flush(ctx, []string{"rm", "bar"}, nil))
+
+	recs, err = Read(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+
+	assert.Equal(t, []string{"edit", "foo"}, recs[0].Command)
+	assert.Equal(t, []Change{
+		{Op: OpSet, Entry: "foo", Commit: "abcdef-foo"},
+		{Op: OpMove, Entry: "bar", From: "baz", Commit: "abcdef-bar"},
+	}, recs[0].Changes)
+	assert.Equal(t, []Change{{Op: OpDelete, Entry: "bar"}}, recs[1].Changes)
+
+	recs, err = Read(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+}
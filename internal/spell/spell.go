@@ -0,0 +1,103 @@
+// Package spell spells out secrets using the NATO phonetic alphabet and names
+// for digits and symbols, e.g. to read a password over the phone or to type
+// it into a console.
+package spell
+
+import (
+	"fmt"
+	"strings"
+)
+
+var letters = []string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+	"quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey",
+	"x-ray", "yankee", "zulu",
+}
+
+var digits = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+}
+
+var symbols = map[rune]string{
+	' ':  "space",
+	'!':  "exclamation mark",
+	'"':  "double quote",
+	'#':  "hash",
+	'$':  "dollar",
+	'%':  "percent",
+	'&':  "ampersand",
+	'\'': "single quote",
+	'(':  "left parenthesis",
+	')':  "right parenthesis",
+	'*':  "asterisk",
+	'+':  "plus",
+	',':  "comma",
+	'-':  "dash",
+	'.':  "period",
+	'/':  "slash",
+	':':  "colon",
+	';':  "semicolon",
+	'<':  "less than",
+	'=':  "equals",
+	'>':  "greater than",
+	'?':  "question mark",
+	'@':  "at sign",
+	'[':  "left bracket",
+	'\\': "backslash",
+	']':  "right bracket",
+	'^':  "caret",
+	'_':  "underscore",
+	'`':  "backtick",
+	'{':  "left brace",
+	'|':  "pipe",
+	'}':  "right brace",
+	'~':  "tilde",
+}
+
+// Word returns the spoken form of a single character. Upper case letters are
+// prefixed with "capital".
+func Word(r rune) string {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return letters[r-'a']
+	case r >= 'A' && r <= 'Z':
+		return "capital " + strings.ToUpper(letters[r-'A'])
+	case r >= '0' && r <= '9':
+		return digits[r-'0']
+	}
+
+	if w, found := symbols[r]; found {
+		return w
+	}
+
+	return fmt.Sprintf("unicode %U", r)
+}
+
+// Spell returns the spoken form of every character of s.
+func Spell(s string) []string {
+	words := make([]string, 0, len(s))
+	for _, r := range s {
+		words = append(words, Word(r))
+	}
+
+	return words
+}
+
+// Lines returns one numbered line per character of s, e.g.
+// " 1  K  capital KILO". Groups are separated by an empty line every group
+// characters to make it easier to follow along. Use zero to disable grouping.
+func Lines(s string, group int) []string {
+	runes := []rune(s)
+	lines := make([]string, 0, len(runes))
+	width := len(fmt.Sprintf("%d", len(runes)))
+
+	for i, r := range runes {
+		if group > 0 && i > 0 && i%group == 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("%*d  %c  %s", width, i+1, r, Word(r)))
+	}
+
+	return lines
+}
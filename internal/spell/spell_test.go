@@ -0,0 +1,43 @@
+package spell
+
+import (
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpell(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"kilo", "capital KILO", "seven", "exclamation mark", "unicode U+00E4"}, Spell("kK7!ä"))
+	assert.Empty(t, Spell(""))
+
+	// every character a password can be generated from has a name.
+	for _, r := range pwgen.CharAll {
+		assert.NotContains(t, Word(r), "unicode", string(r))
+	}
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{
+		" 1  a  alfa",
+		" 2  B  capital BRAVO",
+		"",
+		" 3  3  three",
+		" 4  -  dash",
+		"",
+		" 5  a  alfa",
+		" 6  a  alfa",
+		"",
+		" 7  a  alfa",
+		" 8  a  alfa",
+		"",
+		" 9  a  alfa",
+		"10  a  alfa",
+	}, Lines("aB3-aaaaaa", 2))
+
+	assert.Equal(t, []string{"1  x  x-ray", "2  Y  capital YANKEE"}, Lines("xY", 0))
+}
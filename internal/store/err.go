@@ -21,14 +21,36 @@ var (
 	ErrGitNoRemote = fmt.Errorf("git has no remote origin")
 	// ErrGitNothingToCommit is returned if there are no staged changes.
 	ErrGitNothingToCommit = fmt.Errorf("git has nothing to commit")
+	// ErrEmptyBundle is returned if there are no new commits since the last
+	// bundle.
+	ErrEmptyBundle = fmt.Errorf("no new commits since the last bundle")
+	// ErrNoStaged is returned if there is no staged push with the given ID.
+	ErrNoStaged = fmt.Errorf("no such staged push")
+	// ErrSelfApproval is returned if a recipient tries to approve its own push.
+	ErrSelfApproval = fmt.Errorf("a push can not be approved by the recipient that staged it")
+	// ErrStagedUnsigned is returned if a staged push wasn't signed by a
+	// recipient of the store.
+	ErrStagedUnsigned = fmt.Errorf("the push was not staged by a recipient of the store")
+	// ErrStagedOutdated is returned if a staged push can no longer be
+	// fast-forwarded.
+	ErrStagedOutdated = fmt.Errorf("the branch moved on since the push was staged")
+	// ErrConflict is returned if an entry was changed by someone else since it
+	// was read and the changes can't be merged.
+	ErrConflict = fmt.Errorf("the entry was changed concurrently")
 	// ErrEmptySecret is returned if a secret exists but has no content.
 	ErrEmptySecret = fmt.Errorf("empty secret. see https://go.gopass.pw/faq#empty-secret")
+	// ErrPinned is returned if a pinned entry would be changed, moved or
+	// removed.
+	ErrPinned = fmt.Errorf("entry is pinned")
 	// ErrMeaninglessWrite is returned if a secret is overwritten with its current (ciphertext) content.
 	ErrMeaninglessWrite = fmt.Errorf("meaningless write")
 	// ErrNoBody is returned if a secret exists but has no content beyond a password.
 	ErrNoBody = fmt.Errorf("no safe content to display, you can force display with -f")
 	// ErrNoPassword is returned is a secret exists but has no password, only a body.
 	ErrNoPassword = fmt.Errorf("no password to display, check the body of the entry instead")
+	// ErrAmbiguousPassword is returned if a secret has several candidates for
+	// the password or the password could be mangled by scripts.
+	ErrAmbiguousPassword = fmt.Errorf("ambiguous password")
 	// ErrYAMLNoMark is returned if a secret contains no valid YAML document marker.
 	ErrYAMLNoMark = fmt.Errorf("no YAML document marker found")
 	// ErrNoKey is returned if a KV or YAML entry doesn't contain a key.
@@ -0,0 +1,178 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// AccessSourceCiphertext means the recipients were read from the
+	// encrypted entry itself.
+	AccessSourceCiphertext = "ciphertext"
+	// AccessSourceIDFile means the recipients could not be read from the
+	// encrypted entry and were taken from the recipients file instead.
+	AccessSourceIDFile = "id-file"
+)
+
+// AccessPeriod is a time range during which a recipient could decrypt an
+// entry. Until is zero if the recipient can still decrypt the current
+// revision.
+type AccessPeriod struct {
+	Recipient     string
+	From          time.Time
+	Until         time.Time
+	FirstRevision string
+	LastRevision  string
+	Source        string
+}
+
+// accessRevision is a single revision of an entry and the recipients it was
+// encrypted for. An empty list of recipients means the entry was deleted.
+type accessRevision struct {
+	Hash       string
+	Date       time.Time
+	Recipients []string
+	Source     string
+}
+
+// AccessHistory reconstructs from the history of the store who could decrypt
+// the given entry during which time ranges. The recipients are read from the
+// encrypted revisions of the entry if the crypto backend supports it and
+// otherwise from the revision of the recipients file that was current at that
+// time.
+func (s *Store) AccessHistory(ctx context.Context, name string) ([]AccessPeriod, error) {
+	p := s.Passfile(name)
+
+	revs, err := s.storage.Revisions(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of %q: %w", name, err)
+	}
+
+	if len(revs) < 1 {
+		return nil, fmt.Errorf("no revisions of %q found", name)
+	}
+
+	idf := s.idFile(ctx, name)
+	idRevs, err := s.storage.Revisions(ctx, idf)
+	if err != nil {
+		debug.Log("failed to list revisions of %s: %s", idf, err)
+	}
+
+	// oldest first.
+	sort.Sort(sort.Reverse(backend.Revisions(revs)))
+	sort.Sort(sort.Reverse(backend.Revisions(idRevs)))
+
+	ars := make([]accessRevision, 0, len(revs))
+	for _, rev := range revs {
+		ar := accessRevision{
+			Hash:   rev.Hash,
+			Date:   rev.Date,
+			Source: AccessSourceCiphertext,
+		}
+
+		ciphertext, err := s.getCiphertextRevision(ctx, p, rev.Hash)
+		if err != nil {
+			// the entry was deleted in this revision.
+			debug.Log("failed to get %s@%s: %s", p, rev.Hash, err)
+			ars = append(ars, ar)
+
+			continue
+		}
+
+		ar.Recipients, err = s.crypto.RecipientIDs(ctx, ciphertext)
+		if err != nil || len(ar.Recipients) < 1 {
+			debug.Log("failed to read recipients of %s@%s: %s", p, rev.Hash, err)
+			ar.Recipients = s.idFileRecipientsAt(ctx, idf, idRevs, rev.Date)
+			ar.Source = AccessSourceIDFile
+		}
+
+		ars = append(ars, ar)
+	}
+
+	return accessPeriods(ars), nil
+}
+
+// idFileRecipientsAt returns the recipients listed in the latest revision of
+// the recipients file that is not newer than the given time. idRevs must be
+// sorted oldest first.
+func (s *Store) idFileRecipientsAt(ctx context.Context, idf string, idRevs []backend.Revision, ts time.Time) []string {
+	hash := ""
+	for _, rev := range idRevs {
+		if rev.Date.After(ts) {
+			break
+		}
+		hash = rev.Hash
+	}
+
+	if hash == "" {
+		return nil
+	}
+
+	buf, err := s.storage.GetRevision(ctx, idf, hash)
+	if err != nil {
+		debug.Log("failed to get %s@%s: %s", idf, hash, err)
+
+		return nil
+	}
+
+	return recipients.Unmarshal(buf).IDs()
+}
+
+// accessPeriods turns a list of revisions, sorted oldest first, into the
+// periods each recipient had access to the entry.
+func accessPeriods(revs []accessRevision) []AccessPeriod {
+	var periods []AccessPeriod
+
+	open := map[string]*AccessPeriod{}
+	for _, rev := range revs {
+		current := set.Map(rev.Recipients)
+
+		for _, r := range set.SortedKeys(open) {
+			if current[r] {
+				continue
+			}
+
+			ap := open[r]
+			ap.Until = rev.Date
+			periods = append(periods, *ap)
+			delete(open, r)
+		}
+
+		for _, r := range set.Sorted(rev.Recipients) {
+			if ap, found := open[r]; found {
+				ap.LastRevision = rev.Hash
+
+				continue
+			}
+
+			open[r] = &AccessPeriod{
+				Recipient:     r,
+				From:          rev.Date,
+				FirstRevision: rev.Hash,
+				LastRevision:  rev.Hash,
+				Source:        rev.Source,
+			}
+		}
+	}
+
+	for _, ap := range open {
+		periods = append(periods, *ap)
+	}
+
+	sort.SliceStable(periods, func(i, j int) bool {
+		if !periods[i].From.Equal(periods[j].From) {
+			return periods[i].From.Before(periods[j].From)
+		}
+
+		return periods[i].Recipient < periods[j].Recipient
+	})
+
+	return periods
+}
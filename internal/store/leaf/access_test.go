@@ -0,0 +1,59 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessPeriods(t *testing.T) {
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(24 * time.Hour)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+
+	periods := accessPeriods([]accessRevision{
+		{Hash: "a", Date: t0, Recipients: []string{"alice", "bob"}, Source: AccessSourceCiphertext},
+		{Hash: "b", Date: t1, Recipients: []string{"alice"}, Source: AccessSourceCiphertext},
+		{Hash: "c", Date: t2, Recipients: []string{"alice", "carol"}, Source: AccessSourceIDFile},
+		{Hash: "d", Date: t3, Source: AccessSourceCiphertext},
+	})
+
+	assert.Equal(t, []AccessPeriod{
+		{Recipient: "alice", From: t0, Until: t3, FirstRevision: "a", LastRevision: "c", Source: AccessSourceCiphertext},
+		{Recipient: "bob", From: t0, Until: t1, FirstRevision: "a", LastRevision: "a", Source: AccessSourceCiphertext},
+		{Recipient: "carol", From: t2, Until: t3, FirstRevision: "c", LastRevision: "c", Source: AccessSourceIDFile},
+	}, periods)
+
+	// still open
+	periods = accessPeriods([]accessRevision{
+		{Hash: "a", Date: t0, Recipients: []string{"alice"}, Source: AccessSourceCiphertext},
+	})
+	require.Len(t, periods, 1)
+	assert.True(t, periods[0].Until.IsZero())
+}
+
+func TestAccessHistory(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "foo/bar", sec))
+
+	// the fs backend only knows the latest revision.
+	periods, err := s.AccessHistory(ctx, "foo/bar")
+	require.NoError(t, err)
+	require.NotEmpty(t, periods)
+
+	for _, ap := range periods {
+		assert.Equal(t, "latest", ap.FirstRevision)
+		assert.True(t, ap.Until.IsZero())
+	}
+}
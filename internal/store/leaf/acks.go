@@ -0,0 +1,315 @@
+package leaf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Every recipient confirms that it can still decrypt the store by decrypting a
+// challenge and committing an acknowledgement signed with its own key. The
+// challenge is encrypted for all recipients and records the hash of the
+// recipients, so it is replaced and all acknowledgements become stale whenever
+// the recipients change. Both live in a dot dir, so they are not listed as
+// entries.
+const (
+	ackDir        = ".gopass-acks"
+	challengeName = "challenge"
+	ackExt        = ".ack"
+)
+
+var (
+	reAckFilename  = regexp.MustCompile(`[^A-Za-z0-9@._+-]`)
+	errNoChallenge = errors.New("no challenge")
+)
+
+// AccessAck is the acknowledgement of a recipient that it can decrypt the
+// store.
+type AccessAck struct {
+	Recipient string
+	// Date is zero if the recipient didn't acknowledge access since the
+	// recipients were changed last.
+	Date time.Time
+	// Verified is true if the proof of the acknowledgement was checked, i.e.
+	// the challenge could be decrypted. The signature is always checked.
+	Verified bool
+}
+
+type challenge struct {
+	recipients string
+	nonce      []byte
+}
+
+func (s *Store) challengePath() string {
+	return path.Join(ackDir, challengeName+"."+s.crypto.Ext())
+}
+
+func ackPath(recipient string) string {
+	return path.Join(ackDir, reAckFilename.ReplaceAllString(recipient, "_")+ackExt)
+}
+
+// readChallenge decrypts the challenge of the store. It returns errNoChallenge
+// if there is none yet.
+func (s *Store) readChallenge(ctx context.Context) (*challenge, error) {
+	if !s.storage.Exists(ctx, s.challengePath()) {
+		return nil, errNoChallenge
+	}
+
+	ciphertext, err := s.storage.Get(ctx, s.challengePath())
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := s.crypto.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the challenge: %w", err)
+	}
+
+	fields := parseFields(buf)
+	nonce, err := hex.DecodeString(fields["nonce"])
+	if err != nil || len(nonce) < 1 {
+		return nil, fmt.Errorf("malformed challenge")
+	}
+
+	return &challenge{
+		recipients: fields["recipients"],
+		nonce:      nonce,
+	}, nil
+}
+
+// writeChallenge encrypts a new challenge for the current recipients.
+func (s *Store) writeChallenge(ctx context.Context, hash string, recipients []string) error {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to create challenge: %w", err)
+	}
+
+	buf := fmt.Sprintf("recipients: %s\nnonce: %s\n", hash, hex.EncodeToString(nonce))
+
+	ciphertext, err := s.crypto.Encrypt(ctx, []byte(buf), recipients)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt challenge: %w", err)
+	}
+
+	if err := s.storage.Set(ctx, s.challengePath(), ciphertext); err != nil {
+		return fmt.Errorf("failed to write challenge: %w", err)
+	}
+
+	return nil
+}
+
+func (c *challenge) proof(recipient, date string) string {
+	mac := hmac.New(sha256.New, c.nonce)
+	fmt.Fprintf(mac, "%s\n%s\n%s", recipient, c.recipients, date)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ackMessage is what a recipient signs. Only recipients can compute the proof,
+// only the recipient itself can sign it.
+func ackMessage(recipient, hash, date, proof string) []byte {
+	return []byte(fmt.Sprintf("gopass access ack\nrecipient: %s\nrecipients: %s\ndate: %s\nproof: %s\n", recipient, hash, date, proof))
+}
+
+// AckAccess decrypts the challenge of the store and commits an
+// acknowledgement signed by each recipient we have the private key of. A new
+// challenge is created if the recipients changed. It returns the acknowledged
+// recipients.
+func (s *Store) AckAccess(ctx context.Context) ([]string, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rs, err := s.GetRecipients(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipients: %w", err)
+	}
+	hash := rs.Hash()
+
+	ours := s.ownRecipients(ctx, rs.IDs())
+	if len(ours) < 1 {
+		return nil, fmt.Errorf("none of the recipients of this store is one of your identities")
+	}
+
+	c, err := s.readChallenge(ctx)
+	switch {
+	case errors.Is(err, errNoChallenge) || err == nil && c.recipients != hash:
+		debug.Log("creating a new challenge (recipients changed or %v)", err)
+
+		if err := s.writeChallenge(ctx, hash, rs.IDs()); err != nil {
+			return nil, err
+		}
+
+		// only decrypting it proves anything.
+		c, err = s.readChallenge(ctx)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	files := []string{s.challengePath()}
+	date := time.Now().UTC().Format(time.RFC3339)
+	acked := make([]string, 0, len(ours))
+	for _, r := range ours {
+		proof := c.proof(r, date)
+
+		_, sig, err := s.signAs(ctx, []string{r}, ackMessage(r, hash, date, proof))
+		if errors.Is(err, backend.ErrNotSupported) {
+			out.Warningf(ctx, "Can not acknowledge access for %s: %s", r, err)
+
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		buf := fmt.Sprintf("recipient: %s\nrecipients: %s\ndate: %s\nproof: %s\nsignature: %s\n", r, hash, date, proof, base64.StdEncoding.EncodeToString(sig))
+
+		fn := ackPath(r)
+		if err := s.storage.Set(ctx, fn, []byte(buf)); err != nil && !errors.Is(err, store.ErrMeaninglessWrite) {
+			return nil, fmt.Errorf("failed to write acknowledgement: %w", err)
+		}
+		files = append(files, fn)
+		acked = append(acked, r)
+	}
+
+	if len(acked) < 1 {
+		return nil, fmt.Errorf("none of your identities can sign: %w", backend.ErrNotSupported)
+	}
+
+	if err := s.storage.Add(ctx, files...); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return acked, nil
+		}
+
+		return nil, fmt.Errorf("failed to add acknowledgements to git: %w", err)
+	}
+
+	if !ctxutil.IsGitCommit(ctx) {
+		return acked, nil
+	}
+
+	if err := s.storage.Commit(ctx, fmt.Sprintf("Acknowledged access for %s", strings.Join(acked, ", "))); err != nil && !errors.Is(err, store.ErrGitNothingToCommit) {
+		return nil, fmt.Errorf("failed to commit acknowledgement: %w", err)
+	}
+
+	return acked, nil
+}
+
+// AccessStatus returns the acknowledgements of all recipients of the store.
+// Acknowledgements given before the recipients were changed last and those not
+// signed by the recipient don't count.
+func (s *Store) AccessStatus(ctx context.Context) ([]AccessAck, error) {
+	sg, err := s.signer()
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := s.GetRecipients(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipients: %w", err)
+	}
+	hash := rs.Hash()
+
+	// without the challenge the proofs can not be checked.
+	c, err := s.readChallenge(ctx)
+	if err != nil {
+		debug.Log("can not verify acknowledgements: %s", err)
+		c = nil
+	}
+
+	acks := make([]AccessAck, 0, rs.Len())
+	for _, r := range rs.IDs() {
+		ack := AccessAck{Recipient: r}
+
+		buf, err := s.storage.Get(ctx, ackPath(r))
+		if err != nil {
+			acks = append(acks, ack)
+
+			continue
+		}
+
+		fields := parseFields(buf)
+		if fields["recipient"] != r || fields["recipients"] != hash {
+			debug.Log("acknowledgement of %s is stale", r)
+			acks = append(acks, ack)
+
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, fields["date"])
+		if err != nil {
+			debug.Log("acknowledgement of %s has an invalid date: %s", r, err)
+			acks = append(acks, ack)
+
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(fields["signature"])
+		if err != nil || len(sig) < 1 {
+			debug.Log("acknowledgement of %s is not signed", r)
+			acks = append(acks, ack)
+
+			continue
+		}
+
+		if signer, err := sg.Verify(ctx, ackMessage(r, hash, fields["date"], fields["proof"]), sig, []string{r}); err != nil || signer != r {
+			debug.Log("acknowledgement of %s is not signed by it: %v", r, err)
+			acks = append(acks, ack)
+
+			continue
+		}
+
+		if c != nil && c.recipients == hash {
+			if !hmac.Equal([]byte(c.proof(r, fields["date"])), []byte(fields["proof"])) {
+				debug.Log("acknowledgement of %s has an invalid proof", r)
+				acks = append(acks, ack)
+
+				continue
+			}
+			ack.Verified = true
+		}
+
+		ack.Date = date
+		acks = append(acks, ack)
+	}
+
+	return acks, nil
+}
+
+// parseFields parses "key: value" lines.
+func parseFields(buf []byte) map[string]string {
+	fields := make(map[string]string, 4)
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		k, v, found := strings.Cut(scanner.Text(), ": ")
+		if !found {
+			continue
+		}
+		fields[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return fields
+}
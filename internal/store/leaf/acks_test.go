@@ -0,0 +1,129 @@
+package leaf
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckAccess(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	rs, err := s.GetRecipients(ctx, "")
+	require.NoError(t, err)
+	require.True(t, rs.Len() > 0)
+
+	acks, err := s.AccessStatus(ctx)
+	require.NoError(t, err)
+	for _, ack := range acks {
+		assert.True(t, ack.Date.IsZero(), ack.Recipient)
+	}
+
+	ours, err := s.AckAccess(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, ours)
+
+	acks, err = s.AccessStatus(ctx)
+	require.NoError(t, err)
+	for _, ack := range acks {
+		assert.False(t, ack.Date.IsZero(), ack.Recipient)
+		assert.True(t, ack.Verified, ack.Recipient)
+	}
+
+	t.Run("recipient changes invalidate acknowledgements", func(t *testing.T) {
+		rs.Add("0xC0FFEE")
+		require.NoError(t, s.SetRecipients(ctx, rs))
+
+		acks, err := s.AccessStatus(ctx)
+		require.NoError(t, err)
+		require.Len(t, acks, rs.Len())
+		for _, ack := range acks {
+			assert.True(t, ack.Date.IsZero(), ack.Recipient)
+		}
+
+		_, err = s.AckAccess(ctx)
+		require.NoError(t, err)
+
+		acks, err = s.AccessStatus(ctx)
+		require.NoError(t, err)
+		for _, ack := range acks {
+			assert.Equal(t, ack.Recipient == "0xC0FFEE", ack.Date.IsZero(), ack.Recipient)
+		}
+	})
+
+	t.Run("forged acknowledgements are rejected", func(t *testing.T) {
+		fn := ackPath(ours[0])
+		buf, err := s.storage.Get(ctx, fn)
+		require.NoError(t, err)
+
+		fields := parseFields(buf)
+		forged := "recipient: " + fields["recipient"] + "\nrecipients: " + fields["recipients"] + "\ndate: 2030-01-01T00:00:00Z\nproof: " + fields["proof"] + "\n"
+		require.NoError(t, s.storage.Set(ctx, fn, []byte(forged)))
+
+		acks, err := s.AccessStatus(ctx)
+		require.NoError(t, err)
+		for _, ack := range acks {
+			if ack.Recipient == ours[0] {
+				assert.True(t, ack.Date.IsZero())
+			}
+		}
+	})
+
+	t.Run("acknowledgements by other members are rejected", func(t *testing.T) {
+		_, err := s.AckAccess(ctx)
+		require.NoError(t, err)
+
+		fn := ackPath(ours[0])
+		buf, err := s.storage.Get(ctx, fn)
+		require.NoError(t, err)
+		fields := parseFields(buf)
+
+		sg, err := s.signer()
+		require.NoError(t, err)
+		sig, err := sg.Sign(ctx, "0xC0FFEE", ackMessage(ours[0], fields["recipients"], fields["date"], fields["proof"]))
+		require.NoError(t, err)
+
+		for _, forged := range []string{
+			// signed by someone else.
+			strings.Replace(string(buf), fields["signature"], base64.StdEncoding.EncodeToString(sig), 1),
+			// not signed at all.
+			strings.Replace(string(buf), "signature: "+fields["signature"]+"\n", "", 1),
+		} {
+			require.NoError(t, s.storage.Set(ctx, fn, []byte(forged)))
+
+			acks, err := s.AccessStatus(ctx)
+			require.NoError(t, err)
+			for _, ack := range acks {
+				if ack.Recipient == ours[0] {
+					assert.True(t, ack.Date.IsZero())
+				}
+			}
+		}
+	})
+
+	t.Run("unreadable challenges are not replaced", func(t *testing.T) {
+		require.NoError(t, s.storage.Set(ctx, s.challengePath(), []byte("garbage")))
+
+		_, err := s.AckAccess(ctx)
+		assert.Error(t, err)
+
+		buf, err := s.storage.Get(ctx, s.challengePath())
+		require.NoError(t, err)
+		assert.Equal(t, "garbage", string(buf))
+	})
+
+	lst, err := s.List(ctx, "")
+	require.NoError(t, err)
+	for _, e := range lst {
+		assert.NotContains(t, e, ackDir)
+	}
+}
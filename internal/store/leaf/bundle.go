@@ -0,0 +1,265 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/backup"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// bundler is implemented by storage backends that can exchange their
+// history as files, e.g. git bundles.
+type bundler interface {
+	CreateBundle(ctx context.Context, fn string, full bool) error
+	VerifyBundle(ctx context.Context, fn string) (string, error)
+	ImportBundle(ctx context.Context, fn string) error
+	AckBundle(ctx context.Context, fn string) error
+}
+
+func (s *Store) bundler() (bundler, error) {
+	b, ok := local.Unwrap(s.storage).(bundler)
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s does not support bundles: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+
+	return b, nil
+}
+
+// IsEncryptedBundle returns true if fn is a bundle encrypted by this store.
+func (s *Store) IsEncryptedBundle(fn string) bool {
+	return strings.HasSuffix(fn, "."+s.crypto.Ext())
+}
+
+// bundleKDFTarget is how long deriving the key of a passphrase protected
+// bundle takes on the exporting machine.
+var bundleKDFTarget = time.Second
+
+// ExportBundle writes the history of the store to fn. Unless full is set it
+// only contains the changes since the last imported or acknowledged bundle. If
+// encrypt is set the bundle is encrypted for the recipients of the store
+// and the crypto backend's extension is appended to fn. If the context
+// carries a bundle passphrase the bundle is protected with it instead. It
+// returns the name of the written file.
+func (s *Store) ExportBundle(ctx context.Context, fn string, full, encrypt bool) (string, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	b, err := s.bundler()
+	if err != nil {
+		return "", err
+	}
+
+	pw := GetBundlePassphrase(ctx)
+	if !encrypt && pw == "" {
+		return fn, b.CreateBundle(ctx, fn, full)
+	}
+
+	tmp, cleanup, err := bundleTempFile()
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	if err := b.CreateBundle(ctx, tmp, full); err != nil {
+		return "", err
+	}
+
+	buf, err := os.ReadFile(tmp)
+	if err != nil {
+		return "", err
+	}
+
+	if pw != "" {
+		return fn, sealBundle(fn, pw, buf)
+	}
+
+	recipients, err := s.useableKeys(ctx, "")
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := s.crypto.Encrypt(ctx, buf, s.ensureOurKeyID(ctx, recipients))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	if !s.IsEncryptedBundle(fn) {
+		fn += "." + s.crypto.Ext()
+	}
+
+	if err := os.WriteFile(fn, ciphertext, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return fn, nil
+}
+
+// VerifyBundle checks that fn can be imported into this store.
+func (s *Store) VerifyBundle(ctx context.Context, fn string) error {
+	b, err := s.bundler()
+	if err != nil {
+		return err
+	}
+
+	fn, cleanup, err := s.decryptBundle(ctx, fn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	_, err = b.VerifyBundle(ctx, fn)
+
+	return err
+}
+
+// ImportBundle verifies the bundle and merges it into the store.
+func (s *Store) ImportBundle(ctx context.Context, fn string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	b, err := s.bundler()
+	if err != nil {
+		return err
+	}
+
+	fn, cleanup, err := s.decryptBundle(ctx, fn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return b.ImportBundle(ctx, fn)
+}
+
+// AckBundle records that the bundle fn, exported from this store, was
+// imported on the other side.
+func (s *Store) AckBundle(ctx context.Context, fn string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	b, err := s.bundler()
+	if err != nil {
+		return err
+	}
+
+	fn, cleanup, err := s.decryptBundle(ctx, fn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return b.AckBundle(ctx, fn)
+}
+
+// sealBundle writes a bundle protected with a passphrase. The key derivation
+// is calibrated so that brute forcing the passphrase is expensive.
+func sealBundle(fn, pw string, buf []byte) error {
+	ciphertext, err := backup.Seal(pw, buf, backup.Calibrate(bundleKDFTarget))
+	if err != nil {
+		return fmt.Errorf("failed to protect bundle: %w", err)
+	}
+
+	if err := os.WriteFile(fn, ciphertext, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return nil
+}
+
+// decryptBundle decrypts an encrypted or passphrase protected bundle to a
+// temporary file. Plain bundles are returned as is.
+func (s *Store) decryptBundle(ctx context.Context, fn string) (string, func(), error) {
+	protected := isProtectedBundle(fn)
+	if !protected && !s.IsEncryptedBundle(fn) {
+		return fn, func() {}, nil
+	}
+
+	ciphertext, err := os.ReadFile(fn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf []byte
+	if protected {
+		buf, err = openBundle(ctx, ciphertext)
+	} else {
+		buf, err = s.crypto.Decrypt(ctx, ciphertext)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+
+	tmp, cleanup, err := bundleTempFile()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		cleanup()
+
+		return "", nil, err
+	}
+
+	return tmp, cleanup, nil
+}
+
+func openBundle(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	pw := GetBundlePassphrase(ctx)
+	if pw == "" {
+		var err error
+
+		pw, err = termio.AskForPassword(ctx, "the passphrase of the bundle", false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return backup.Open(pw, ciphertext)
+}
+
+// isProtectedBundle returns true if fn is a passphrase protected bundle.
+func isProtectedBundle(fn string) bool {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return false
+	}
+	defer fh.Close() //nolint:errcheck
+
+	buf := make([]byte, 64)
+	n, _ := io.ReadFull(fh, buf)
+
+	return backup.IsBackup(buf[:n])
+}
+
+// bundleTempFile returns the name of a temporary file for a bundle.
+func bundleTempFile() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "gopass-bundle")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return filepath.Join(dir, "store.bundle"), func() {
+		if err := os.RemoveAll(dir); err != nil {
+			debug.Log("failed to remove %s: %s", dir, err)
+		}
+	}, nil
+}
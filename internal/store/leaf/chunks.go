@@ -0,0 +1,283 @@
+package leaf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Ciphertexts larger than storage.chunk-size are split into chunks. The entry
+// file then only holds a manifest listing the chunks, which are stored by their
+// hash in a dot dir. Finding the chunks that are no longer referenced requires
+// reading every manifest, so they are only removed by fsck. Older revisions
+// read their chunks from the same revision.
+const (
+	chunkDir    = ".gopass-chunks"
+	chunkHeader = "gopass-chunks-v1\n"
+)
+
+// chunkSize returns the configured chunk size in bytes. Chunking is disabled
+// if it is zero.
+func (s *Store) chunkSize(ctx context.Context) int {
+	v := config.FromContext(ctx).GetM(s.alias, "storage.chunk-size")
+	if v == "" {
+		return 0
+	}
+
+	kib, err := strconv.Atoi(v)
+	if err != nil || kib < 0 {
+		debug.Log("invalid storage.chunk-size %q: %v", v, err)
+
+		return 0
+	}
+
+	return kib * 1024
+}
+
+func chunkPath(hash string) string {
+	return path.Join(chunkDir, hash[:2], hash)
+}
+
+// isChunked returns true if the file of an entry is a manifest. Ciphertexts of
+// the crypto backends never start with the header and content that would be
+// mistaken for it is escaped by compress, so this is unambiguous even for the
+// plain backend.
+func isChunked(buf []byte) bool {
+	return bytes.HasPrefix(buf, []byte(chunkHeader))
+}
+
+// setCiphertext writes the ciphertext of an entry to p, splitting it into
+// chunks if it's too large. It returns the files that need to be added to git.
+func (s *Store) setCiphertext(ctx context.Context, p string, ciphertext []byte) ([]string, error) {
+	// chunks are kept in the repository, so local-only entries are never
+	// chunked.
+	size := s.chunkSize(ctx)
+	if ls, ok := s.storage.(*local.Storage); ok && ls.IsLocal(p) {
+		size = 0
+	}
+	if size < 1 || len(ciphertext) <= size {
+		if err := s.storage.Set(ctx, p, ciphertext); err != nil {
+			return nil, err
+		}
+
+		return []string{p}, nil
+	}
+
+	files := []string{p}
+	manifest := &strings.Builder{}
+	manifest.WriteString(chunkHeader)
+
+	for off := 0; off < len(ciphertext); off += size {
+		end := off + size
+		if end > len(ciphertext) {
+			end = len(ciphertext)
+		}
+		chunk := ciphertext[off:end]
+
+		sum := sha256.Sum256(chunk)
+		hash := hex.EncodeToString(sum[:])
+		fmt.Fprintln(manifest, hash)
+
+		cp := chunkPath(hash)
+		if s.storage.Exists(ctx, cp) {
+			continue
+		}
+		if err := s.storage.Set(ctx, cp, chunk); err != nil {
+			return nil, fmt.Errorf("failed to write chunk %s: %w", hash, err)
+		}
+		files = append(files, cp)
+	}
+
+	debug.Log("split %s (%d bytes) into chunks of %d bytes, %d new", p, len(ciphertext), size, len(files)-1)
+
+	if err := s.storage.Set(ctx, p, []byte(manifest.String())); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// getCiphertext reads the ciphertext of an entry and joins its chunks.
+func (s *Store) getCiphertext(ctx context.Context, p string) ([]byte, error) {
+	buf, err := s.storage.Get(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.joinChunks(ctx, p, buf, func(cp string) ([]byte, error) {
+		return s.storage.Get(ctx, cp)
+	})
+}
+
+// getCiphertextRevision reads the ciphertext of an entry at the given
+// revision and joins its chunks.
+func (s *Store) getCiphertextRevision(ctx context.Context, p, revision string) ([]byte, error) {
+	buf, err := s.storage.GetRevision(ctx, p, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// the chunks may have been removed since, so they are read from the
+	// same revision.
+	return s.joinChunks(ctx, p, buf, func(cp string) ([]byte, error) {
+		return s.storage.GetRevision(ctx, cp, revision)
+	})
+}
+
+// joinChunks returns the ciphertext described by a manifest, reading the
+// chunks with get. Other content is returned as is.
+func (s *Store) joinChunks(ctx context.Context, p string, buf []byte, get func(string) ([]byte, error)) ([]byte, error) {
+	if !isChunked(buf) {
+		return buf, nil
+	}
+
+	hashes, err := parseManifest(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %s", err, p)
+	}
+
+	var ciphertext []byte
+	for _, hash := range hashes {
+		chunk, err := get(chunkPath(hash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %s of %s: %w", hash, p, err)
+		}
+
+		sum := sha256.Sum256(chunk)
+		if hex.EncodeToString(sum[:]) != hash {
+			return nil, fmt.Errorf("chunk %s of %s is corrupted", hash, p)
+		}
+
+		ciphertext = append(ciphertext, chunk...)
+	}
+
+	return ciphertext, nil
+}
+
+// parseManifest returns the chunk hashes listed in a manifest.
+func parseManifest(buf []byte) ([]string, error) {
+	var hashes []string
+	scanner := bufio.NewScanner(bytes.NewReader(buf[len(chunkHeader):]))
+	for scanner.Scan() {
+		hash := strings.TrimSpace(scanner.Text())
+		if hash == "" {
+			continue
+		}
+		if len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid chunk %q", hash)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, scanner.Err()
+}
+
+// chunksOf returns the chunk hashes of the entry at p. It returns nothing if
+// the entry doesn't exist or isn't chunked.
+func (s *Store) chunksOf(ctx context.Context, p string) []string {
+	buf, err := s.storage.Get(ctx, p)
+	if err != nil || !isChunked(buf) {
+		return nil
+	}
+
+	hashes, err := parseManifest(buf)
+	if err != nil {
+		debug.Log("invalid manifest %s: %s", p, err)
+
+		return nil
+	}
+
+	return hashes
+}
+
+// referencedChunks returns the hashes of all chunks referenced by any
+// manifest in the store.
+func (s *Store) referencedChunks(ctx context.Context) (map[string]bool, error) {
+	files, err := s.storage.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cExt := "." + s.crypto.Ext()
+	refs := make(map[string]bool, len(files))
+	for _, fn := range files {
+		if !strings.HasSuffix(fn, cExt) {
+			continue
+		}
+		for _, hash := range s.chunksOf(ctx, fn) {
+			refs[hash] = true
+		}
+	}
+
+	return refs, nil
+}
+
+// gcChunks removes those of the given chunks that are no longer referenced
+// by any manifest. It returns the removed files, which are already staged.
+func (s *Store) gcChunks(ctx context.Context, hashes []string) ([]string, error) {
+	if len(hashes) < 1 {
+		return nil, nil
+	}
+
+	refs, err := s.referencedChunks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks: %w", err)
+	}
+
+	removed := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		cp := chunkPath(hash)
+		if refs[hash] || !s.storage.Exists(ctx, cp) {
+			continue
+		}
+
+		if err := s.deleteSingle(ctx, cp); err != nil {
+			return removed, fmt.Errorf("failed to remove chunk %s: %w", hash, err)
+		}
+		removed = append(removed, cp)
+	}
+	debug.Log("removed %d unreferenced chunks", len(removed))
+
+	return removed, nil
+}
+
+// fsckChunks removes all chunks that are not referenced by any manifest,
+// e.g. because they were left behind by a sync.
+func (s *Store) fsckChunks(ctx context.Context) error {
+	files, err := s.storage.List(ctx, chunkDir)
+	if err != nil {
+		return err
+	}
+
+	hashes := make([]string, 0, len(files))
+	for _, fn := range files {
+		if !strings.HasPrefix(fn, chunkDir+"/") {
+			continue
+		}
+		hashes = append(hashes, path.Base(fn))
+	}
+
+	removed, err := s.gcChunks(ctx, hashes)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) < 1 {
+		return nil
+	}
+
+	out.Printf(ctx, "Removed %d unreferenced chunks", len(removed))
+
+	return s.commitTombstones(ctx, fmt.Sprintf("fsck removed %d unreferenced chunks", len(removed)))
+}
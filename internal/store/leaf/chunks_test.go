@@ -0,0 +1,184 @@
+package leaf
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressAndChunk(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.SetEnv("storage.compress", "true"))
+	require.NoError(t, cfg.SetEnv("storage.chunk-size", "1"))
+	ctx = cfg.WithConfig(ctx)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	// hex encoded random data compresses to about half of its size.
+	blob := make([]byte, 4096)
+	_, _ = rand.New(rand.NewSource(42)).Read(blob)
+	sec := secrets.ParseAKV([]byte("foo\n" + hex.EncodeToString(blob)))
+	require.NoError(t, s.Set(ctx, "certs/large", sec))
+
+	// the plain crypto backend doesn't encrypt, so the stored ciphertext
+	// is the compressed content.
+	manifest, err := s.storage.Get(ctx, s.Passfile("certs/large"))
+	require.NoError(t, err)
+	assert.True(t, isChunked(manifest))
+
+	ciphertext, err := s.getCiphertext(ctx, s.Passfile("certs/large"))
+	require.NoError(t, err)
+	assert.True(t, isCompressed(ciphertext))
+	assert.Less(t, len(ciphertext), len(sec.Bytes()))
+	assert.Greater(t, len(ciphertext), 1024)
+
+	got, err := s.Get(ctx, "certs/large")
+	require.NoError(t, err)
+	assert.Equal(t, string(sec.Bytes()), string(got.Bytes()))
+
+	l, err := s.List(ctx, "")
+	require.NoError(t, err)
+	for _, e := range l {
+		assert.NotContains(t, e, chunkDir)
+	}
+
+	t.Run("small entries are stored as is", func(t *testing.T) {
+		sec := secrets.New()
+		sec.SetPassword("bar")
+		require.NoError(t, s.Set(ctx, "small", sec))
+
+		buf, err := s.storage.Get(ctx, s.Passfile("small"))
+		require.NoError(t, err)
+		assert.False(t, isChunked(buf))
+		assert.False(t, isCompressed(buf))
+	})
+
+	t.Run("compressed entries are read if compression is disabled", func(t *testing.T) {
+		require.NoError(t, cfg.SetEnv("storage.compress", "false"))
+		require.NoError(t, cfg.SetEnv("storage.chunk-size", "0"))
+
+		got, err := s.Get(ctx, "certs/large")
+		require.NoError(t, err)
+		assert.Equal(t, string(sec.Bytes()), string(got.Bytes()))
+	})
+
+	t.Run("corrupted chunks are detected", func(t *testing.T) {
+		_, err := s.joinChunks(ctx, "foo", []byte(chunkHeader+strings.Repeat("0", 64)+"\n"), func(cp string) ([]byte, error) {
+			return s.storage.Get(ctx, cp)
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestEnvelopeHeaders(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	// secrets that look like compressed, escaped, chunked or protected
+	// entries are read back as they were written.
+	for i, content := range []string{
+		compressHeader + "not zstd\n",
+		rawHeader + "foo\n",
+		chunkHeader + strings.Repeat("0", 64) + "\n",
+		fido2.Header + "\nnot wrapped\n",
+	} {
+		name := fmt.Sprintf("headers/%d", i)
+		require.NoError(t, s.Set(ctx, name, secrets.ParseAKV([]byte(content))))
+
+		buf, err := s.storage.Get(ctx, s.Passfile(name))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(buf), rawHeader), name)
+
+		got, err := s.Get(ctx, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, content, string(got.Bytes()), name)
+	}
+}
+
+func TestChunkGC(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.SetEnv("storage.chunk-size", "1"))
+	ctx = cfg.WithConfig(ctx)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	large := func(seed int64) gopass.Secret {
+		blob := make([]byte, 2048)
+		_, _ = rand.New(rand.NewSource(seed)).Read(blob)
+
+		return secrets.ParseAKV([]byte("foo\n" + hex.EncodeToString(blob)))
+	}
+
+	chunks := func() []string {
+		l, err := s.storage.List(ctx, chunkDir)
+		require.NoError(t, err)
+
+		return l
+	}
+
+	require.NoError(t, s.Set(ctx, "a", large(1)))
+	require.NoError(t, s.Set(ctx, "b", large(2)))
+	aChunks := s.chunksOf(ctx, s.Passfile("a"))
+	require.NotEmpty(t, aChunks)
+	n := len(chunks())
+
+	t.Run("updates and deletes leave the chunks to fsck", func(t *testing.T) {
+		require.NoError(t, s.Set(ctx, "a", large(3)))
+		for _, hash := range aChunks {
+			assert.True(t, s.storage.Exists(ctx, chunkPath(hash)))
+		}
+
+		require.NoError(t, s.fsckChunks(ctx))
+		for _, hash := range aChunks {
+			assert.False(t, s.storage.Exists(ctx, chunkPath(hash)))
+		}
+		assert.Len(t, chunks(), n)
+	})
+
+	t.Run("shared chunks are kept", func(t *testing.T) {
+		require.NoError(t, s.Set(ctx, "c", large(2)))
+		require.NoError(t, s.Delete(ctx, "c"))
+		require.NoError(t, s.fsckChunks(ctx))
+
+		got, err := s.Get(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, string(large(2).Bytes()), string(got.Bytes()))
+	})
+
+	t.Run("fsck removes the chunks of deleted entries", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, "a"))
+		require.NoError(t, s.Delete(ctx, "b"))
+		assert.NotEmpty(t, chunks())
+
+		require.NoError(t, s.fsckChunks(ctx))
+		assert.Empty(t, chunks())
+	})
+
+	t.Run("fsck removes unreferenced chunks", func(t *testing.T) {
+		require.NoError(t, s.storage.Set(ctx, chunkPath(strings.Repeat("a", 64)), []byte("stale")))
+		require.NoError(t, s.fsckChunks(ctx))
+		assert.Empty(t, chunks())
+	})
+}
@@ -0,0 +1,106 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Entries are compressed before they are encrypted since ciphertexts don't
+// compress. Compressed entries start with a header, so they can be read
+// regardless of the configuration. Other content that starts with one of the
+// headers of gopass, e.g. a secret that contains a compressed entry, is stored
+// behind rawHeader so it isn't mistaken for it.
+const (
+	compressHeader = "gopass-zstd-v1\n"
+	rawHeader      = "gopass-raw-v1\n"
+	// compressMinSize is the minimum size of entries worth compressing.
+	compressMinSize = 1024
+	// decompressMaxSize limits the memory used to decompress an entry.
+	decompressMaxSize = 256 << 20
+)
+
+// isCompressed returns true if the content was compressed by compress.
+func isCompressed(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(compressHeader))
+}
+
+// needsRaw returns true if the content could be mistaken for a compressed,
+// chunked or protected entry.
+func needsRaw(content []byte) bool {
+	for _, h := range []string{compressHeader, rawHeader, chunkHeader, fido2.Header + "\n"} {
+		if bytes.HasPrefix(content, []byte(h)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// raw escapes content that starts with one of the headers.
+func raw(content []byte) []byte {
+	if !needsRaw(content) {
+		return content
+	}
+
+	return append([]byte(rawHeader), content...)
+}
+
+// compress compresses the content if storage.compress is enabled for this
+// store and compression actually reduces its size.
+func (s *Store) compress(ctx context.Context, name string, content []byte) []byte {
+	if config.FromContext(ctx).GetM(s.alias, "storage.compress") != "true" || len(content) < compressMinSize {
+		return raw(content)
+	}
+
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		debug.Log("failed to create zstd encoder: %s", err)
+
+		return raw(content)
+	}
+	defer func() {
+		_ = enc.Close()
+	}()
+
+	compressed := enc.EncodeAll(content, []byte(compressHeader))
+	if len(compressed) >= len(content) {
+		debug.Log("not compressing %s, %d bytes compress to %d bytes", name, len(content), len(compressed))
+
+		return raw(content)
+	}
+
+	debug.Log("compressed %s from %d to %d bytes", name, len(content), len(compressed))
+
+	return compressed
+}
+
+// decompress returns the content of compressed or escaped entries. Other
+// entries are returned as is.
+func decompress(content []byte) ([]byte, error) {
+	if bytes.HasPrefix(content, []byte(rawHeader)) {
+		return content[len(rawHeader):], nil
+	}
+
+	if !isCompressed(content) {
+		return content, nil
+	}
+
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(decompressMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	defer dec.Close()
+
+	buf, err := dec.DecodeAll(content[len(compressHeader):], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,244 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"golang.org/x/exp/slices"
+)
+
+// readBase is an entry as it was read. A write based on it detects if the
+// entry was changed by another process in the meantime. Only the hash of the
+// ciphertext is kept, the content is never held in memory longer than needed.
+type readBase struct {
+	hash [sha256.Size]byte
+	// key is set if the entry is protected by a FIDO2 token.
+	key *fido2.Key
+}
+
+// recordBase remembers an entry that was read or written. The hash is only
+// recorded with WithConflictCheck, the key of protected entries always.
+func (s *Store) recordBase(ctx context.Context, name string, ciphertext []byte, key *fido2.Key) {
+	check := IsConflictCheck(ctx)
+	if !check && key == nil {
+		return
+	}
+
+	s.basesMu.Lock()
+	defer s.basesMu.Unlock()
+
+	if s.bases == nil {
+		s.bases = make(map[string]readBase, 1)
+	}
+
+	b := readBase{key: key}
+	if check {
+		b.hash = sha256.Sum256(ciphertext)
+	}
+	s.bases[name] = b
+}
+
+// advanceBase moves the base of an entry that was read with a conflict check
+// to our own write, so a later write doesn't mistake it for a change made by
+// someone else.
+func (s *Store) advanceBase(name string, ciphertext []byte) {
+	s.basesMu.Lock()
+	defer s.basesMu.Unlock()
+
+	b, found := s.bases[name]
+	if !found || b.hash == ([sha256.Size]byte{}) {
+		return
+	}
+
+	b.hash = sha256.Sum256(ciphertext)
+	s.bases[name] = b
+}
+
+func (s *Store) getBase(name string) (readBase, bool) {
+	s.basesMu.Lock()
+	defer s.basesMu.Unlock()
+
+	b, found := s.bases[name]
+
+	return b, found
+}
+
+// checkBase compares the entry on disk with the one that was read before.
+// If someone else changed it in the meantime their changes are merged with
+// ours. If both changed the same fields the user has to confirm overwriting
+// their changes, otherwise store.ErrConflict is returned.
+func (s *Store) checkBase(ctx context.Context, name, p string, sec gopass.Byter) (gopass.Byter, error) {
+	base, found := s.getBase(name)
+	if !found || base.hash == ([sha256.Size]byte{}) {
+		return sec, nil
+	}
+
+	ciphertext, err := s.getCiphertext(ctx, p)
+	if err != nil {
+		debug.Log("%s was removed since it was read: %s", name, err)
+
+		return sec, nil
+	}
+
+	if sha256.Sum256(ciphertext) == base.hash {
+		return sec, nil
+	}
+
+	content, err := s.decryptContent(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%s was changed since it was read and can not be read: %w", name, err)
+	}
+
+	if bytes.Equal(content, sec.Bytes()) {
+		return sec, nil
+	}
+
+	out.Warningf(ctx, "%s was changed by someone else since it was read", name)
+
+	// the content that was read is needed to merge both changes. It's only
+	// available if it was committed.
+	baseContent, found := s.baseContent(ctx, p, base.hash)
+	if !found {
+		ok, err := termio.AskForBool(ctx, "Their changes can not be merged. Overwrite them?", false)
+		if err != nil || !ok {
+			return nil, fmt.Errorf("%s: %w", name, store.ErrConflict)
+		}
+
+		return sec, nil
+	}
+
+	merged, conflicts := mergeSecrets(secrets.ParseAKV(baseContent), secrets.ParseAKV(sec.Bytes()), secrets.ParseAKV(content))
+	if len(conflicts) < 1 {
+		ok, err := termio.AskForBool(ctx, "Merge both changes?", true)
+		if err != nil || !ok {
+			return nil, fmt.Errorf("%s: %w", name, store.ErrConflict)
+		}
+
+		return merged, nil
+	}
+
+	ok, err := termio.AskForBool(ctx, fmt.Sprintf("Both changed %s. Overwrite their changes?", strings.Join(conflicts, ", ")), false)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("%s: %w", name, store.ErrConflict)
+	}
+
+	return sec, nil
+}
+
+// maxBaseRevisions is how many recent revisions are searched for the version
+// of an entry that was read.
+const maxBaseRevisions = 10
+
+// baseContent returns the content of the committed version of the entry with
+// the given hash. Usually it's one of the last few revisions.
+func (s *Store) baseContent(ctx context.Context, p string, hash [sha256.Size]byte) ([]byte, bool) {
+	revs, err := s.storage.Revisions(ctx, p)
+	if err != nil {
+		debug.Log("failed to list revisions of %s: %s", p, err)
+
+		return nil, false
+	}
+
+	for i, rev := range revs {
+		if i >= maxBaseRevisions {
+			break
+		}
+
+		ciphertext, err := s.getCiphertextRevision(ctx, p, rev.Hash)
+		if err != nil || sha256.Sum256(ciphertext) != hash {
+			continue
+		}
+
+		content, err := s.decryptContent(ctx, ciphertext)
+		if err != nil {
+			debug.Log("failed to read %s at %s: %s", p, rev.Hash, err)
+
+			return nil, false
+		}
+
+		return content, true
+	}
+
+	debug.Log("the version of %s that was read is not committed", p)
+
+	return nil, false
+}
+
+// decryptContent decrypts, unwraps and decompresses a ciphertext.
+func (s *Store) decryptContent(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	content, err := s.crypto.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if fido2.IsProtected(content) {
+		content, _, err = fido2.Unwrap(ctx, s.authenticator(ctx), content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decompress(content)
+}
+
+// mergeSecrets merges the changes of ours and theirs, both based on base.
+// It returns the merged secret and the fields both sides changed
+// differently.
+func mergeSecrets(base, ours, theirs gopass.Secret) (gopass.Secret, []string) {
+	var conflicts []string
+
+	// the body can't be merged, so the changes to the password and the keys
+	// are applied to the side that changed it.
+	into, from := theirs, ours
+	if ours.Body() != base.Body() {
+		if theirs.Body() != base.Body() && theirs.Body() != ours.Body() {
+			conflicts = append(conflicts, "the body")
+		}
+
+		into, from = ours, theirs
+	}
+
+	if pw := from.Password(); pw != base.Password() && pw != into.Password() {
+		if into.Password() != base.Password() {
+			conflicts = append(conflicts, "the password")
+		} else {
+			into.SetPassword(pw)
+		}
+	}
+
+	keys := append(base.Keys(), from.Keys()...)
+	slices.Sort(keys)
+
+	for _, k := range slices.Compact(keys) {
+		bv, _ := base.Values(k)
+		fv, _ := from.Values(k)
+		iv, _ := into.Values(k)
+
+		if slices.Equal(fv, bv) || slices.Equal(fv, iv) {
+			continue
+		}
+
+		if !slices.Equal(iv, bv) {
+			conflicts = append(conflicts, k)
+
+			continue
+		}
+
+		into.Del(k)
+		for _, v := range fv {
+			_ = into.Add(k, v)
+		}
+	}
+
+	return into, conflicts
+}
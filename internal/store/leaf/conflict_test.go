@@ -0,0 +1,235 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitStorage records the content of added files on commit, like git.
+type commitStorage struct {
+	*fs.Store
+	added   []string
+	commits []map[string][]byte
+}
+
+func (c *commitStorage) Add(ctx context.Context, files ...string) error {
+	c.added = append(c.added, files...)
+
+	return nil
+}
+
+func (c *commitStorage) Commit(ctx context.Context, msg string) error {
+	commit := make(map[string][]byte, len(c.added))
+	for _, fn := range c.added {
+		buf, err := c.Store.Get(ctx, fn)
+		if err != nil {
+			return err
+		}
+		commit[fn] = buf
+	}
+	c.added = nil
+	c.commits = append(c.commits, commit)
+
+	return nil
+}
+
+func (c *commitStorage) Revisions(ctx context.Context, name string) ([]backend.Revision, error) {
+	var revs []backend.Revision
+	for i := len(c.commits) - 1; i >= 0; i-- {
+		if _, found := c.commits[i][name]; found {
+			revs = append(revs, backend.Revision{Hash: strconv.Itoa(i)})
+		}
+	}
+
+	return revs, nil
+}
+
+func (c *commitStorage) GetRevision(ctx context.Context, name, revision string) ([]byte, error) {
+	i, err := strconv.Atoi(revision)
+	if err != nil || i >= len(c.commits) {
+		return nil, fmt.Errorf("unknown revision %s", revision)
+	}
+
+	return c.commits[i][name], nil
+}
+
+func TestSetConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = WithConflictCheck(ctx, true)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	out.Stderr = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	tempdir := t.TempDir()
+	_, _, err := createStore(tempdir, nil, []string{})
+	require.NoError(t, err)
+	t.Setenv("GOPASS_HOMEDIR", tempdir)
+
+	storage := &commitStorage{Store: fs.New(tempdir)}
+	newStore := func() *Store {
+		return &Store{
+			alias:   "",
+			path:    tempdir,
+			crypto:  plain.New(),
+			storage: storage,
+		}
+	}
+
+	// two processes working on the same store.
+	we, they := newStore(), newStore()
+
+	write := func(s *Store, content string) {
+		t.Helper()
+		require.NoError(t, s.Set(ctx, "foo", secrets.ParseAKV([]byte(content))))
+	}
+	read := func(s *Store) string {
+		t.Helper()
+		sec, err := s.Get(ctx, "foo")
+		require.NoError(t, err)
+
+		return string(sec.Bytes())
+	}
+
+	write(we, "pw\nuser: jane\nurl: example.org\n")
+
+	t.Run("no concurrent change", func(t *testing.T) {
+		read(we)
+		write(we, "pw\nuser: jane\nurl: example.org\nfoo: bar\n")
+		assert.Equal(t, "pw\nuser: jane\nurl: example.org\nfoo: bar\n", read(they))
+	})
+
+	t.Run("merge different keys", func(t *testing.T) {
+		read(we)
+		read(they)
+		write(they, "pw\nuser: joe\nurl: example.org\nfoo: bar\n")
+		write(we, "new-pw\nuser: jane\nurl: example.org\nfoo: bar\n")
+		assert.Equal(t, "new-pw\nuser: joe\nurl: example.org\nfoo: bar\n", read(they))
+	})
+
+	t.Run("merge body and keys", func(t *testing.T) {
+		read(we)
+		read(they)
+		write(they, "new-pw\nuser: joe\nurl: example.com\nfoo: bar\n")
+		write(we, "new-pw\nuser: joe\nurl: example.org\nfoo: bar\nsome notes\n")
+		assert.Equal(t, "new-pw\nuser: joe\nfoo: bar\nsome notes\nurl: example.com\n", read(they))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		read(we)
+		read(they)
+		write(they, "their-pw\nuser: joe\n")
+		err := we.Set(ctx, "foo", secrets.ParseAKV([]byte("our-pw\nuser: joe\n")))
+		assert.ErrorIs(t, err, store.ErrConflict)
+		assert.Equal(t, "their-pw\nuser: joe\n", read(they))
+	})
+
+	t.Run("base not committed", func(t *testing.T) {
+		storage.commits = nil
+		read(we)
+		read(they)
+		write(they, "new-pw\nuser: joe\n")
+		storage.commits = nil
+		err := we.Set(ctx, "foo", secrets.ParseAKV([]byte("their-pw\nuser: jim\n")))
+		assert.ErrorIs(t, err, store.ErrConflict)
+	})
+
+	t.Run("only with conflict check", func(t *testing.T) {
+		we, they := newStore(), newStore()
+		ctx := WithConflictCheck(ctx, false)
+
+		_, err := we.Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Empty(t, we.bases)
+
+		write(they, "pw\n")
+		require.NoError(t, we.Set(ctx, "foo", secrets.ParseAKV([]byte("other-pw\n"))))
+	})
+
+	t.Run("own writes without conflict check", func(t *testing.T) {
+		we := newStore()
+		cctx := WithConflictCheck(ctx, true)
+
+		_, err := we.Get(cctx, "foo")
+		require.NoError(t, err)
+
+		require.NoError(t, we.Set(WithConflictCheck(ctx, false), "foo", secrets.ParseAKV([]byte("own-pw\n"))))
+		require.NoError(t, we.Set(cctx, "foo", secrets.ParseAKV([]byte("new-pw\n"))))
+	})
+}
+
+func TestMergeSecrets(t *testing.T) {
+	t.Parallel()
+
+	parse := func(s string) *secrets.AKV {
+		return secrets.ParseAKV([]byte(s))
+	}
+
+	for _, tc := range []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts []string
+	}{
+		{
+			name:   "same change",
+			base:   "pw\n",
+			ours:   "pw2\n",
+			theirs: "pw2\n",
+			want:   "pw2\n",
+		},
+		{
+			name:   "removed key",
+			base:   "pw\nuser: jane\nfoo: bar\n",
+			ours:   "pw\nuser: jane\n",
+			theirs: "pw2\nuser: jane\nfoo: bar\n",
+			want:   "pw2\nuser: jane\n",
+		},
+		{
+			name:      "both changed a key",
+			base:      "pw\nuser: jane\n",
+			ours:      "pw\nuser: joe\n",
+			theirs:    "pw\nuser: jim\n",
+			want:      "pw\nuser: jim\n",
+			conflicts: []string{"user"},
+		},
+		{
+			name:      "both changed the body",
+			base:      "pw\nnotes\n",
+			ours:      "pw\nour notes\n",
+			theirs:    "pw\ntheir notes\n",
+			want:      "pw\nour notes\n",
+			conflicts: []string{"the body"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, conflicts := mergeSecrets(parse(tc.base), parse(tc.ours), parse(tc.theirs))
+			assert.Equal(t, tc.want, string(got.Bytes()))
+			assert.Equal(t, tc.conflicts, conflicts)
+		})
+	}
+}
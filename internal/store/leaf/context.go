@@ -16,6 +16,10 @@ const (
 	ctxKeyFsckDecrypt
 	ctxKeyNoGitOps
 	ctxKeyPubkeyUpdate
+	ctxKeyNoReview
+	ctxKeyBundlePassphrase
+	ctxKeyConflictCheck
+	ctxKeyNoDeleteEvent
 )
 
 // WithFsckCheck returns a context with the flag for fscks check set.
@@ -145,6 +149,62 @@ func WithPubkeyUpdate(ctx context.Context, d bool) context.Context {
 	return context.WithValue(ctx, ctxKeyPubkeyUpdate, d)
 }
 
+// WithNoReview returns a context that applies changes to owned folders
+// directly instead of proposing them for review. It's used for store wide
+// operations like re-encryption that don't change the content.
+func WithNoReview(ctx context.Context, d bool) context.Context {
+	return context.WithValue(ctx, ctxKeyNoReview, d)
+}
+
+// IsNoReview returns the value for NoReview from the context or the
+// default (false).
+func IsNoReview(ctx context.Context) bool {
+	return is(ctx, ctxKeyNoReview, false)
+}
+
+// WithConflictCheck returns a context that remembers the entries read with it,
+// so writing them back detects changes made by someone else in the meantime.
+// It's used by read-modify-write flows like edit.
+func WithConflictCheck(ctx context.Context, c bool) context.Context {
+	return context.WithValue(ctx, ctxKeyConflictCheck, c)
+}
+
+// IsConflictCheck returns the value for ConflictCheck from the context or the
+// default (false).
+func IsConflictCheck(ctx context.Context) bool {
+	return is(ctx, ctxKeyConflictCheck, false)
+}
+
+// WithNoDeleteEvent returns a context for deletes that don't remove an entry
+// from the point of view of the user, e.g. the source of a move or merge.
+// They don't publish entry.deleted.
+func WithNoDeleteEvent(ctx context.Context, nd bool) context.Context {
+	return context.WithValue(ctx, ctxKeyNoDeleteEvent, nd)
+}
+
+// IsNoDeleteEvent returns the value for NoDeleteEvent from the context or the
+// default (false).
+func IsNoDeleteEvent(ctx context.Context) bool {
+	return is(ctx, ctxKeyNoDeleteEvent, false)
+}
+
+// WithBundlePassphrase returns a context with the passphrase used to protect
+// exported bundles and to open protected bundles on import.
+func WithBundlePassphrase(ctx context.Context, pw string) context.Context {
+	return context.WithValue(ctx, ctxKeyBundlePassphrase, pw)
+}
+
+// GetBundlePassphrase returns the bundle passphrase from the context or an
+// empty string.
+func GetBundlePassphrase(ctx context.Context) string {
+	pw, ok := ctx.Value(ctxKeyBundlePassphrase).(string)
+	if !ok {
+		return ""
+	}
+
+	return pw
+}
+
 // hasBool is a helper function for checking if a bool has been set in
 // the provided context.
 func hasBool(ctx context.Context, key contextKey) bool {
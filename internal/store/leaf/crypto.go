@@ -82,6 +82,14 @@ func (s *Store) ImportMissingPublicKeys(ctx context.Context, newrs ...string) er
 			continue
 		}
 
+		// make sure nobody swapped the exported key for a different one.
+		fp, err := s.verifyPublicKey(ctx, r)
+		if err != nil {
+			out.Errorf(ctx, "Refusing to import public key for %s: %s", r, err)
+
+			continue
+		}
+
 		// we need to ask the user before importing
 		// any key material into his keyring!
 		if imf := ctxutil.GetImportFunc(ctx); imf != nil {
@@ -98,6 +106,7 @@ func (s *Store) ImportMissingPublicKeys(ctx context.Context, newrs ...string) er
 
 			continue
 		}
+		s.pinPublicKey(ctx, r, fp)
 		out.Printf(ctx, "Imported public key for %s into Keyring", r)
 	}
 
@@ -196,24 +205,18 @@ func (s *Store) importPublicKey(ctx context.Context, r string) error {
 	return im.ImportPublicKey(ctx, pk)
 }
 
-type locker interface {
-	Lock()
-}
-
 // Lock clears the credential caches of all supported backends.
-func (s *Store) Lock() error {
-	f, ok := s.crypto.(locker)
+func (s *Store) Lock(ctx context.Context) error {
+	l, ok := s.crypto.(backend.Locker)
 	if !ok {
 		debug.Log("locking not supported by %T in %q", s.crypto, s.alias)
-	}
-
-	if f == nil {
-		debug.Log("backend %q invalid", s.alias)
 
 		return nil
 	}
 
-	f.Lock()
+	if err := l.Lock(ctx); err != nil {
+		return fmt.Errorf("failed to lock %q: %w", s.alias, err)
+	}
 	debug.Log("locked backend %T for %q", s.crypto, s.alias)
 
 	return nil
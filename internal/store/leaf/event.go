@@ -0,0 +1,55 @@
+package leaf
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/event"
+)
+
+// queuedEvent is an event that waits for the store lock to be released.
+type queuedEvent struct {
+	ctx context.Context //nolint:containedctx
+	ev  event.Event
+}
+
+// publish sends the event to the bus in the context. Entries and the folder
+// must be relative to this store, they are published with the mount point.
+// While the store lock is held the event is queued and only sent once the
+// lock is released, so subscribers, e.g. hooks, never run under the lock.
+func (s *Store) publish(ctx context.Context, ev event.Event) {
+	ev.Store = s.alias
+
+	if s.alias != "" && ev.Folder != "" {
+		ev.Folder = s.alias + Sep + ev.Folder
+	}
+
+	if s.alias != "" {
+		full := make([]string, 0, len(ev.Entries))
+		for _, e := range ev.Entries {
+			full = append(full, s.alias+Sep+e)
+		}
+		ev.Entries = full
+	}
+
+	if s.lock != nil && s.lock.Held() {
+		s.eventsMu.Lock()
+		s.events = append(s.events, queuedEvent{ctx: ctx, ev: ev})
+		s.eventsMu.Unlock()
+
+		return
+	}
+
+	event.Publish(ctx, ev)
+}
+
+// flushEvents sends the queued events in the order they were published.
+func (s *Store) flushEvents() {
+	s.eventsMu.Lock()
+	events := s.events
+	s.events = nil
+	s.eventsMu.Unlock()
+
+	for _, q := range events {
+		event.Publish(q.ctx, q.ev)
+	}
+}
@@ -0,0 +1,72 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+
+	_, _, err := createStore(tempdir, nil, nil)
+	require.NoError(t, err)
+
+	s := &Store{
+		alias:   "team",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+
+	bus := event.New()
+	var got []event.Event
+	bus.Subscribe(func(_ context.Context, ev event.Event) {
+		// subscribers must be able to use the store.
+		assert.False(t, s.lock.Held(), ev.Type)
+		got = append(got, ev)
+	})
+	ctx = event.WithBus(ctx, bus)
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "db/a", sec))
+	require.NoError(t, s.Set(ctx, "db/b", sec))
+	require.NoError(t, s.Prune(ctx, "db"))
+
+	require.Len(t, got, 3)
+	assert.Equal(t, event.EntryWritten, got[0].Type)
+	assert.Equal(t, "team", got[0].Store)
+	assert.Equal(t, []string{"team/db/a"}, got[0].Entries)
+	assert.Equal(t, event.EntryDeleted, got[2].Type)
+	assert.ElementsMatch(t, []string{"team/db/a", "team/db/b"}, got[2].Entries)
+
+	// the source of a move isn't deleted from the point of view of the user.
+	got = nil
+	require.NoError(t, s.Set(ctx, "db/c", sec))
+	require.NoError(t, s.Delete(WithNoDeleteEvent(ctx, true), "db/c"))
+	require.Len(t, got, 1)
+	assert.Equal(t, event.EntryWritten, got[0].Type)
+
+	// events are held back until the outermost lock is released.
+	got = nil
+	release, err := s.Acquire(ctx)
+	require.NoError(t, err)
+	require.NoError(t, s.Set(ctx, "db/d", sec))
+	assert.Empty(t, got)
+	release()
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"team/db/d"}, got[0].Entries)
+}
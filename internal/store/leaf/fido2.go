@@ -0,0 +1,215 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// protectedFile lists the entries protected by a FIDO2 token, one per line.
+// The marker inside the entry can only be read after decrypting it, the list
+// tells writes which entries need to be wrapped without decrypting every entry
+// that is written.
+const (
+	protectedFile   = ".gopass-protected"
+	protectedHeader = "# Entries protected by a FIDO2 token. Managed by 'gopass protect'."
+)
+
+// isProtected returns true if the entry is listed as protected.
+func (s *Store) isProtected(ctx context.Context, name string) bool {
+	return set.Contains(s.readList(ctx, protectedFile), strings.TrimPrefix(name, Sep))
+}
+
+func (s *Store) setProtected(ctx context.Context, name string, protected bool) error {
+	name = strings.TrimPrefix(name, Sep)
+	names := set.Filter(s.readList(ctx, protectedFile), name)
+	msg := "Unprotected " + name
+	if protected {
+		names = append(names, name)
+		msg = "Protected " + name
+	}
+
+	return s.writeList(ctx, protectedFile, protectedHeader, names, msg)
+}
+
+// unprotectDeleted removes deleted entries from the list of protected entries.
+// The list is staged with the deletion.
+func (s *Store) unprotectDeleted(ctx context.Context, deleted ...string) error {
+	protected := s.readList(ctx, protectedFile)
+	names := protected
+	for _, name := range deleted {
+		names = set.Filter(names, strings.TrimPrefix(name, Sep))
+	}
+
+	if len(names) == len(protected) {
+		return nil
+	}
+
+	if err := s.writeList(WithNoGitOps(ctx, true), protectedFile, protectedHeader, names, ""); err != nil {
+		return err
+	}
+
+	return s.stageTombstone(ctx, protectedFile)
+}
+
+func (s *Store) authenticator(ctx context.Context) fido2.Authenticator {
+	if s.token != nil {
+		return s.token
+	}
+
+	return fido2.New(ctx)
+}
+
+// wrapProtected wraps the content of an entry that is protected by a FIDO2
+// token. If the entry was read before the key of that read is used, otherwise
+// the current version of a protected entry is unwrapped to get it.
+func (s *Store) wrapProtected(ctx context.Context, name, p string, content []byte) ([]byte, *fido2.Key, error) {
+	key, err := s.protectionKey(ctx, name, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key == nil {
+		return content, nil, nil
+	}
+
+	wrapped, err := key.Wrap(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wrap %s: %w", name, err)
+	}
+
+	return wrapped, key, nil
+}
+
+func (s *Store) protectionKey(ctx context.Context, name, p string) (*fido2.Key, error) {
+	if base, found := s.getBase(name); found {
+		return base.key, nil
+	}
+
+	// only protected entries are decrypted, everything else is written
+	// without asking for the passphrase.
+	if !s.isProtected(ctx, name) {
+		return nil, nil
+	}
+
+	if !s.storage.Exists(ctx, p) {
+		debug.Log("%s is listed as protected but does not exist", name)
+
+		return nil, nil
+	}
+
+	// writing it without the protection would remove it, so anything
+	// that prevents reading the key is an error.
+	ciphertext, err := s.getCiphertext(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protected entry %s: %w", name, err)
+	}
+
+	current, err := s.crypto.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt protected entry %s: %w", name, err)
+	}
+
+	if !fido2.IsProtected(current) {
+		debug.Log("%s is listed as protected but is not wrapped", name)
+
+		return nil, nil
+	}
+
+	debug.Log("%s is protected by a FIDO2 token", name)
+
+	_, key, err := fido2.Unwrap(ctx, s.authenticator(ctx), current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap %s with the FIDO2 token: %w", name, err)
+	}
+
+	return key, nil
+}
+
+// Protect wraps the entry with a key derived from the FIDO2 credential.
+func (s *Store) Protect(ctx context.Context, name string, credential []byte) error {
+	sec, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if base, _ := s.getBase(name); base.key != nil {
+		return fmt.Errorf("%s is already protected", name)
+	}
+
+	key, err := fido2.NewKey(ctx, s.authenticator(ctx), credential)
+	if err != nil {
+		return err
+	}
+
+	s.setBaseKey(name, key)
+
+	if err := s.Set(ctx, name, sec); err != nil {
+		return err
+	}
+
+	return s.setProtected(ctx, name, true)
+}
+
+// Unprotect removes the FIDO2 protection of the entry.
+func (s *Store) Unprotect(ctx context.Context, name string) error {
+	sec, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if base, _ := s.getBase(name); base.key == nil {
+		return fmt.Errorf("%s is not protected", name)
+	}
+
+	s.setBaseKey(name, nil)
+
+	if err := s.Set(ctx, name, sec); err != nil {
+		return err
+	}
+
+	return s.setProtected(ctx, name, false)
+}
+
+// SetFrom writes sec, which was read from the entry from of the store src, to
+// name. If the source is protected by a FIDO2 token the destination is
+// protected by the same key.
+func (s *Store) SetFrom(ctx context.Context, src *Store, from, name string, sec gopass.Byter) error {
+	base, _ := src.getBase(from)
+	if base.key != nil {
+		s.setBaseKey(name, base.key)
+	}
+
+	err := s.Set(ctx, name, sec)
+	if err != nil && !errors.Is(err, store.ErrMeaninglessWrite) {
+		return err
+	}
+
+	if base.key != nil && !s.isProtected(ctx, name) {
+		if err := s.setProtected(ctx, name, true); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (s *Store) setBaseKey(name string, key *fido2.Key) {
+	s.basesMu.Lock()
+	defer s.basesMu.Unlock()
+
+	if s.bases == nil {
+		s.bases = make(map[string]readBase, 1)
+	}
+
+	base := s.bases[name]
+	base.key = key
+	s.bases[name] = base
+}
@@ -0,0 +1,212 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeToken struct {
+	taps int
+}
+
+func (f *fakeToken) MakeCredential(context.Context) ([]byte, error) {
+	return []byte("credential"), nil
+}
+
+func (f *fakeToken) HMACSecret(_ context.Context, _, salt []byte) ([]byte, error) {
+	f.taps++
+	m := hmac.New(sha256.New, []byte("token"))
+	m.Write(salt)
+
+	return m.Sum(nil), nil
+}
+
+// countingCrypto counts the decryptions.
+type countingCrypto struct {
+	*plain.Mocker
+	decrypts int
+}
+
+func (c *countingCrypto) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	c.decrypts++
+
+	return c.Mocker.Decrypt(ctx, ciphertext)
+}
+
+// failingCrypto can't decrypt anything.
+type failingCrypto struct {
+	*plain.Mocker
+}
+
+func (f *failingCrypto) Decrypt(context.Context, []byte) ([]byte, error) {
+	return nil, fmt.Errorf("no secret key")
+}
+
+func TestProtect(t *testing.T) {
+	ctx := context.Background()
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	out.Stderr = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	tempdir := t.TempDir()
+	_, _, err := createStore(tempdir, nil, []string{})
+	require.NoError(t, err)
+	t.Setenv("GOPASS_HOMEDIR", tempdir)
+
+	tok := &fakeToken{}
+	newStore := func() *Store {
+		return &Store{
+			alias:   "",
+			path:    tempdir,
+			crypto:  plain.New(),
+			storage: fs.New(tempdir),
+			token:   tok,
+		}
+	}
+
+	rawEntry := func(dir, name string) []byte {
+		t.Helper()
+
+		buf, err := os.ReadFile(filepath.Join(dir, newStore().Passfile(name)))
+		require.NoError(t, err)
+
+		return buf
+	}
+	raw := func() []byte {
+		t.Helper()
+
+		return rawEntry(tempdir, "foo")
+	}
+
+	s := newStore()
+	require.NoError(t, s.Set(ctx, "foo", secrets.ParseAKV([]byte("secret\nuser: jane\n"))))
+	require.NoError(t, s.Protect(ctx, "foo", []byte("credential")))
+	assert.True(t, fido2.IsProtected(raw()))
+	assert.Error(t, s.Protect(ctx, "foo", []byte("credential")))
+
+	t.Run("reveal requires a tap", func(t *testing.T) {
+		taps := tok.taps
+		sec, err := newStore().Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+		assert.Equal(t, taps+1, tok.taps)
+	})
+
+	t.Run("changes stay protected", func(t *testing.T) {
+		s := newStore()
+		sec, err := s.Get(ctx, "foo")
+		require.NoError(t, err)
+		sec.SetPassword("new")
+		require.NoError(t, s.Set(ctx, "foo", sec))
+		assert.True(t, fido2.IsProtected(raw()))
+
+		// blind overwrites, too.
+		require.NoError(t, newStore().Set(ctx, "foo", secrets.ParseAKV([]byte("newer\n"))))
+		assert.True(t, fido2.IsProtected(raw()))
+
+		sec, err = newStore().Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "newer", sec.Password())
+	})
+
+	t.Run("moves keep the protection", func(t *testing.T) {
+		s := newStore()
+		require.NoError(t, s.Move(ctx, "foo", "moved"))
+		assert.True(t, s.isProtected(ctx, "moved"))
+		assert.False(t, s.isProtected(ctx, "foo"))
+		require.NoError(t, s.Move(ctx, "moved", "foo"))
+		assert.True(t, s.isProtected(ctx, "foo"))
+	})
+
+	t.Run("other entries are written without decrypting", func(t *testing.T) {
+		c := &countingCrypto{Mocker: plain.New()}
+		s := newStore()
+		s.crypto = c
+		require.NoError(t, s.Set(ctx, "bar", secrets.ParseAKV([]byte("one\n"))))
+
+		s = newStore()
+		s.crypto = c
+		require.NoError(t, s.Set(ctx, "bar", secrets.ParseAKV([]byte("two\n"))))
+		assert.Equal(t, 0, c.decrypts)
+	})
+
+	t.Run("revisions are unwrapped", func(t *testing.T) {
+		s := newStore()
+		s.storage = &commitStorage{Store: fs.New(tempdir)}
+		require.NoError(t, s.Set(ctx, "foo", secrets.ParseAKV([]byte("newer\n"))))
+		assert.True(t, fido2.IsProtected(raw()))
+
+		revs, err := s.ListRevisions(ctx, "foo")
+		require.NoError(t, err)
+		require.NotEmpty(t, revs)
+
+		sec, err := s.GetRevision(ctx, "foo", revs[0].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, "newer", sec.Password())
+	})
+
+	t.Run("copies keep the protection", func(t *testing.T) {
+		s := newStore()
+		sec, err := s.Get(ctx, "foo")
+		require.NoError(t, err)
+
+		require.NoError(t, s.SetFrom(ctx, s, "foo", "copy", sec))
+		assert.True(t, s.isProtected(ctx, "copy"))
+		assert.True(t, fido2.IsProtected(rawEntry(tempdir, "copy")))
+
+		otherdir := t.TempDir()
+		_, _, err = createStore(otherdir, nil, []string{})
+		require.NoError(t, err)
+		other := &Store{
+			alias:   "other",
+			path:    otherdir,
+			crypto:  plain.New(),
+			storage: fs.New(otherdir),
+			token:   tok,
+		}
+		require.NoError(t, other.SetFrom(ctx, s, "foo", "foo", sec))
+		assert.True(t, other.isProtected(ctx, "foo"))
+		assert.True(t, fido2.IsProtected(rawEntry(otherdir, "foo")))
+	})
+
+	t.Run("deletes remove the protection", func(t *testing.T) {
+		s := newStore()
+		require.NoError(t, s.Delete(ctx, "copy"))
+		assert.False(t, s.isProtected(ctx, "copy"))
+		assert.True(t, s.isProtected(ctx, "foo"))
+	})
+
+	t.Run("unreadable protected entries are not overwritten", func(t *testing.T) {
+		s := newStore()
+		s.crypto = &failingCrypto{Mocker: plain.New()}
+		assert.Error(t, s.Set(ctx, "foo", secrets.ParseAKV([]byte("plain\n"))))
+		assert.True(t, fido2.IsProtected(raw()))
+	})
+
+	t.Run("unprotect", func(t *testing.T) {
+		s := newStore()
+		require.NoError(t, s.Unprotect(ctx, "foo"))
+		assert.Equal(t, "newer\n", string(raw()))
+		assert.False(t, s.isProtected(ctx, "foo"))
+		assert.Error(t, s.Unprotect(ctx, "foo"))
+	})
+}
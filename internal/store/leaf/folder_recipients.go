@@ -0,0 +1,104 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/journal"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// AddFolderRecipient adds a recipient to a single folder and re-encrypts the
+// secrets below it. The folder gets its own recipients file, initialized with
+// the recipients it has now, so the new recipient can't decrypt anything
+// outside of it. It returns the re-encrypted secrets.
+func (s *Store) AddFolderRecipient(ctx context.Context, folder, id string) ([]string, error) {
+	folder = strings.Trim(folder, Sep)
+	if folder == "" {
+		return nil, fmt.Errorf("folder must not be empty, use AddRecipient for the whole store")
+	}
+
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// the nearest recipients file, i.e. the one of the folder or a parent.
+	buf, err := s.storage.Get(ctx, s.idFile(ctx, folder))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipients of %s: %w", folder, err)
+	}
+
+	rs := recipients.Unmarshal(buf)
+	if !rs.Add(id) {
+		debug.Log("%s is already a recipient of %s", id, folder)
+	}
+
+	idf := filepath.Join(folder, s.crypto.IDFile())
+	if err := s.storage.Set(ctx, idf, rs.Marshal()); err != nil && !errors.Is(err, store.ErrMeaninglessWrite) {
+		return nil, fmt.Errorf("failed to write recipients file %s: %w", idf, err)
+	}
+
+	if err := s.storage.Add(ctx, idf); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+		return nil, fmt.Errorf("failed to add file %q to git: %w", idf, err)
+	}
+
+	msg := fmt.Sprintf("Added recipient %s to %s", id, folder)
+	if err := s.storage.Commit(ctx, msg); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) && !errors.Is(err, store.ErrGitNothingToCommit) {
+			return nil, fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	if config.Bool(ctx, "core.exportkeys") {
+		if _, err := s.UpdateExportedPublicKeys(ctx, []string{id}); err != nil {
+			out.Errorf(ctx, "Failed to export missing public keys: %s", err)
+		}
+	}
+
+	entries, err := s.List(ctx, folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", folder, err)
+	}
+
+	var affected []string
+	items := make([]journal.Item, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimPrefix(e, s.alias+Sep)
+		if s.idFile(ctx, name) != idf {
+			continue
+		}
+
+		affected = append(affected, e)
+		items = append(items, journal.Item{Name: strings.TrimPrefix(e, s.alias)})
+	}
+
+	j, err := journal.Begin(s.path, journal.OpReencrypt, false, items)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Printf(ctx, "Re-encrypting %d secrets. This may take some time ...", len(affected))
+
+	if err := s.reencryptEntries(ctxutil.WithCommitMessage(ctx, msg), j, affected, nil); err != nil {
+		return nil, err
+	}
+
+	if err := j.Finish(); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, event.Event{Type: event.RecipientsChanged, Added: []string{id}, Folder: folder})
+
+	return affected, s.reencryptGitPush(ctx)
+}
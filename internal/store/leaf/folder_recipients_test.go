@@ -0,0 +1,57 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFolderRecipient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+
+	genRecs, _, err := createStore(tempdir, nil, []string{"foo/bar/baz", "foo/qux", "baz/ing/a"})
+	require.NoError(t, err)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+
+	_, err = s.AddFolderRecipient(ctx, "", "A3683834")
+	assert.Error(t, err)
+
+	affected, err := s.AddFolderRecipient(ctx, "foo/", "A3683834")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo/bar/baz", "foo/qux"}, affected)
+
+	rs, err := s.GetRecipients(ctx, "foo/qux")
+	require.NoError(t, err)
+	assert.Equal(t, append(genRecs, "A3683834"), rs.IDs())
+	assert.FileExists(t, filepath.Join(tempdir, "foo", plain.IDFile))
+
+	rs, err = s.GetRecipients(ctx, "baz/ing/a")
+	require.NoError(t, err)
+	assert.Equal(t, genRecs, rs.IDs())
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/age"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/diff"
 	"github.com/gopasspw/gopass/internal/out"
@@ -90,6 +91,12 @@ func (f *fsckMultiError) ErrorOrNil() error {
 
 // Fsck checks all entries matching the given prefix.
 func (s *Store) Fsck(ctx context.Context, path string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	ctx = out.AddPrefix(ctx, "["+s.alias+"] ")
 	debug.Log("Checking %s", path)
 
@@ -125,6 +132,17 @@ func (s *Store) Fsck(ctx context.Context, path string) error {
 		return err
 	}
 
+	s.fsckPostQuantum(ctx, path)
+	s.fsckLocal(ctx)
+
+	if err := s.fsckTombstones(ctx); err != nil {
+		out.Errorf(ctx, "Failed to check tombstones: %s", err)
+	}
+
+	if err := s.fsckChunks(ctx); err != nil {
+		out.Errorf(ctx, "Failed to check chunks: %s", err)
+	}
+
 	if !config.Bool(ctx, "core.autopush") {
 		debug.Log("not pushing to git remote, core.autopush is false")
 
@@ -219,10 +237,91 @@ func (s *Store) fsckLoop(ctx context.Context, path string) error {
 	return nil
 }
 
+// pqChecker is implemented by crypto backends that support post-quantum
+// recipients.
+type pqChecker interface {
+	PostQuantum(ciphertext []byte) (bool, error)
+}
+
+// fsckPostQuantum reports which secrets are protected against an attacker
+// with a quantum computer, i.e. are encrypted only for post-quantum
+// recipients. Stores without any post-quantum recipients are skipped.
+func (s *Store) fsckPostQuantum(ctx context.Context, path string) {
+	pc, ok := s.crypto.(pqChecker)
+	if !ok {
+		return
+	}
+
+	names, err := s.List(ctx, path)
+	if err != nil {
+		debug.Log("failed to list entries for %s: %s", path, err)
+
+		return
+	}
+
+	var protected int
+	var exposed []string
+	for _, name := range names {
+		name = strings.TrimPrefix(name, s.alias+"/")
+
+		ciphertext, err := s.getCiphertext(ctx, s.Passfile(name))
+		if err != nil {
+			debug.Log("failed to read %s: %s", name, err)
+
+			continue
+		}
+
+		pq, err := pc.PostQuantum(ciphertext)
+		if err != nil {
+			debug.Log("failed to check %s: %s", name, err)
+
+			continue
+		}
+
+		if pq {
+			protected++
+
+			continue
+		}
+		exposed = append(exposed, name)
+	}
+
+	if protected == 0 && !s.hasPQRecipients(ctx) {
+		debug.Log("no post-quantum recipients in %s", s.alias)
+
+		return
+	}
+
+	out.Printf(ctx, "%d of %d secrets are post-quantum protected", protected, len(names))
+	for _, name := range exposed {
+		out.Warningf(ctx, "%s is not post-quantum protected. Remove all classic recipients and run 'gopass fsck --decrypt'", name)
+	}
+}
+
+func (s *Store) hasPQRecipients(ctx context.Context) bool {
+	for _, r := range s.Recipients(ctx) {
+		if age.IsPQRecipient(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Store) fsckUpdatePublicKeys(ctx context.Context) error {
 	ctx = WithPubkeyUpdate(ctx, true)
 	rs := s.Recipients(ctx)
 
+	// report any exported keys that do not match their recipient.
+	for _, r := range rs {
+		if _, err := s.getPublicKey(ctx, r); err != nil {
+			continue
+		}
+		if _, err := s.verifyPublicKey(ctx, r); err != nil {
+			out.Errorf(ctx, "Exported public key for %s is invalid: %s", r, err)
+		}
+	}
+
 	// first import possibly new/updated keys to merge any changes
 	// that might come from others.
 	if err := s.ImportMissingPublicKeys(ctx, rs...); err != nil {
@@ -293,7 +392,7 @@ func (s *Store) fsckCheckEntry(ctx context.Context, name string) (string, error)
 		out.Printf(ctx, "Re-encrypting %s to fix storage format. [leaf store]", name)
 	}
 
-	if err := s.Set(ctxutil.WithGitCommit(ctx, false), name, sec); err != nil {
+	if err := s.Set(WithNoReview(ctxutil.WithGitCommit(ctx, false), true), name, sec); err != nil {
 		return "", errs.Append(errsFatal, fmt.Errorf("failed to write secret %s: %w", name, err)).ErrorOrNil()
 	}
 
@@ -318,7 +417,7 @@ func (s *Store) fsckCheckRecipients(ctx context.Context, name string) *fsckMulti
 
 	// now compare the recipients this secret was encoded for and fix it if
 	// it doesn't match.
-	ciphertext, err := s.storage.Get(ctx, s.Passfile(name))
+	ciphertext, err := s.getCiphertext(ctx, s.Passfile(name))
 	if err != nil {
 		return e.Append(errsFatal, fmt.Errorf("failed to get raw secret: %w", err))
 	}
@@ -390,3 +489,28 @@ func compareStringSlices(want, have []string) ([]string, []string) {
 
 	return missing, extra
 }
+
+// fsckTombstones removes resurrected entries and expired tombstones.
+func (s *Store) fsckTombstones(ctx context.Context) error {
+	deleted, err := s.ApplyTombstones(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range deleted {
+		out.Warningf(ctx, "Removed %s. It had been deleted, but was resurrected by a sync", name)
+	}
+
+	n, err := s.expireTombstones(ctx)
+	if err != nil {
+		return err
+	}
+
+	if n < 1 {
+		return nil
+	}
+
+	out.Printf(ctx, "Removed %d expired tombstones", n)
+
+	return s.commitTombstones(ctx, fmt.Sprintf("fsck removed %d expired tombstones", n))
+}
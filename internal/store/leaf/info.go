@@ -0,0 +1,72 @@
+package leaf
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+const (
+	// InfoFile is the name of a folder note. It is encrypted like any
+	// other secret but hidden from regular listings.
+	InfoFile = ".info"
+)
+
+// IsInfo returns true if the given entry name refers to a folder note.
+func IsInfo(name string) bool {
+	return path.Base(name) == InfoFile
+}
+
+// infoName returns the entry name of the folder note for the given folder.
+func infoName(dir string) string {
+	return strings.TrimPrefix(path.Join(dir, InfoFile), "/")
+}
+
+// HasInfo returns true if the given folder contains a folder note.
+func (s *Store) HasInfo(ctx context.Context, dir string) bool {
+	return s.Exists(ctx, infoName(dir))
+}
+
+// GetInfo returns the decrypted folder note of the given folder.
+func (s *Store) GetInfo(ctx context.Context, dir string) (gopass.Secret, error) {
+	return s.Get(ctx, infoName(dir))
+}
+
+// SetInfo will (over)write the folder note of the given folder.
+func (s *Store) SetInfo(ctx context.Context, dir string, sec gopass.Byter) error {
+	return s.Set(ctx, infoName(dir), sec)
+}
+
+// RemoveInfo will delete the folder note of the given folder.
+func (s *Store) RemoveInfo(ctx context.Context, dir string) error {
+	return s.Delete(ctx, infoName(dir))
+}
+
+// LookupInfo will walk up the tree, starting at the given folder, and return
+// the folder (and content) of the nearest folder note.
+func (s *Store) LookupInfo(ctx context.Context, dir string) (string, gopass.Secret, error) {
+	dir = strings.Trim(dir, "/")
+	for {
+		if s.HasInfo(ctx, dir) {
+			debug.Log("Found folder note for %q in %q", dir, infoName(dir))
+			sec, err := s.GetInfo(ctx, dir)
+
+			return dir, sec, err
+		}
+
+		if dir == "" || dir == "." {
+			break
+		}
+
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+	}
+
+	return "", nil, store.ErrNotFound
+}
@@ -0,0 +1,42 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo(t *testing.T) {
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ctx = backend.WithCryptoBackendString(ctx, "plain")
+
+	assert.True(t, IsInfo("foo/.info"))
+	assert.False(t, IsInfo("foo/info"))
+
+	assert.False(t, s.HasInfo(ctx, "foo"))
+	_, _, err = s.LookupInfo(ctx, "foo/bar")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("Team folder")
+	require.NoError(t, sec.Set("owner", "ops"))
+	require.NoError(t, s.SetInfo(ctx, "foo", sec))
+	assert.True(t, s.HasInfo(ctx, "foo"))
+
+	dir, got, err := s.LookupInfo(ctx, "foo/bar/baz")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", dir)
+	owner, _ := got.Get("owner")
+	assert.Equal(t, "ops", owner)
+
+	require.NoError(t, s.RemoveInfo(ctx, "foo"))
+	assert.False(t, s.HasInfo(ctx, "foo"))
+}
@@ -0,0 +1,45 @@
+package leaf
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/journal"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// Journal returns the journal of an interrupted multi-file operation on this
+// store or nil if there is none.
+func (s *Store) Journal() (*journal.Journal, error) {
+	return journal.Load(s.path)
+}
+
+// RecoverReencrypt completes an interrupted re-encryption of this store. The
+// update is applied to each pending entry, like in the interrupted run.
+func (s *Store) RecoverReencrypt(ctx context.Context, j *journal.Journal, update func(gopass.Secret) error) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	pending := j.Pending()
+	out.Warningf(ctx, "Completing an interrupted re-encryption of %d entries (started %s)", len(pending), j.Started.Local().Format("2006-01-02 15:04:05"))
+
+	names := make([]string, 0, len(pending))
+	for _, it := range pending {
+		names = append(names, it.Name)
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Completed interrupted re-encryption")
+	if err := s.reencryptEntries(ctx, j, names, update); err != nil {
+		return err
+	}
+
+	if err := j.Finish(); err != nil {
+		return err
+	}
+
+	return s.reencryptGitPush(ctx)
+}
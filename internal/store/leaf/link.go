@@ -12,6 +12,12 @@ import (
 
 // Link creates a symlink.
 func (s *Store) Link(ctx context.Context, from, to string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if !s.Exists(ctx, from) {
 		return fmt.Errorf("source %q does not exists", from)
 	}
@@ -40,7 +46,7 @@ func (s *Store) Link(ctx context.Context, from, to string) error {
 		return nil, s.gitCommitAndPush(ctx, to)
 	})
 
-	_, err := t(ctx)
+	_, err = t(ctx)
 
 	return err
 }
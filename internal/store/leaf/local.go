@@ -0,0 +1,214 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// LocalOnly returns the local-only entries and folders of this store,
+// including the mount point. Folders end with a slash.
+func (s *Store) LocalOnly() []string {
+	ls, ok := s.storage.(*local.Storage)
+	if !ok {
+		return nil
+	}
+
+	marks := ls.Marks()
+	res := make([]string, 0, len(marks))
+	for _, m := range marks {
+		if !strings.HasSuffix(m, "/") {
+			m = strings.TrimSuffix(m, "."+s.crypto.Ext())
+		}
+		if s.alias != "" {
+			m = s.alias + Sep + m
+		}
+		res = append(res, m)
+	}
+
+	return res
+}
+
+// IsLocalOnly returns true if the entry is never committed or pushed.
+func (s *Store) IsLocalOnly(name string) bool {
+	ls, ok := s.storage.(*local.Storage)
+	if !ok {
+		return false
+	}
+
+	return ls.IsLocal(s.Passfile(name))
+}
+
+// SetLocalOnly marks the entry or folder as local-only or removes the mark.
+// Names ending with a slash and existing folders that are not an entry, too,
+// are folders. Local-only entries already in the repository are removed from
+// it, but they remain in its history.
+func (s *Store) SetLocalOnly(ctx context.Context, name string, localOnly bool) error {
+	if strings.Trim(name, Sep) == "" {
+		return fmt.Errorf("a whole store can not be local-only, use a store without a remote instead")
+	}
+
+	mark := s.Passfile(name)
+	if strings.HasSuffix(name, "/") || (s.storage.IsDir(ctx, name) && !s.Exists(ctx, name)) {
+		mark = strings.TrimPrefix(path.Clean(name), "/") + "/"
+	}
+
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ls, ok := s.storage.(*local.Storage)
+	if !ok {
+		if !localOnly {
+			return nil
+		}
+
+		ls, err = local.New(s.storage, local.Dir(s.alias, s.path))
+		if err != nil {
+			return err
+		}
+		s.storage = ls
+	}
+
+	var moved []string
+	msg := fmt.Sprintf("Made %s local-only", name)
+	if localOnly {
+		moved, err = ls.Mark(ctx, mark)
+	} else {
+		msg = fmt.Sprintf("Added local-only %s to the store", name)
+		moved, err = ls.Unmark(ctx, mark)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update local-only entries: %w", err)
+	}
+
+	if localOnly {
+		if _, err := s.unchunkLocal(ctx, moved); err != nil {
+			return err
+		}
+	}
+
+	if !local.Enabled(ls.Dir()) {
+		s.storage = ls.Unwrap()
+	}
+
+	if len(moved) < 1 {
+		return nil
+	}
+
+	// the local storage doesn't add local-only files, so removing them
+	// from the repository has to go to the RCS directly.
+	if err := ls.Unwrap().Add(ctx, moved...); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add %q to git: %w", moved, err)
+	}
+
+	if err := s.storage.Commit(ctx, msg); err != nil && !errors.Is(err, store.ErrGitNothingToCommit) {
+		return fmt.Errorf("failed to commit changes to git: %w", err)
+	}
+
+	if localOnly {
+		out.Warningf(ctx, "Removed %d entries from the repository. They remain in its history until you run 'gopass gc --days 0'", len(moved))
+	}
+
+	return s.reencryptGitPush(ctx)
+}
+
+// fsckLocal makes sure no local-only entry reached the repository.
+func (s *Store) fsckLocal(ctx context.Context) {
+	ls, ok := s.storage.(*local.Storage)
+	if !ok {
+		return
+	}
+
+	if err := s.fsckLocalChunks(ctx, ls); err != nil {
+		out.Errorf(ctx, "Failed to check the chunks of local-only entries: %s", err)
+	}
+
+	leaks, err := ls.Leaks(ctx)
+	if err != nil {
+		out.Errorf(ctx, "Failed to check local-only entries: %s", err)
+
+		return
+	}
+
+	for _, l := range leaks {
+		name := strings.TrimSuffix(l.Name, "."+s.crypto.Ext())
+		if l.Tracked {
+			out.Errorf(ctx, "Local-only entry %s is in the repository, too. Remove %s from the repository with 'git rm'", name, l.Name)
+
+			continue
+		}
+
+		out.Warningf(ctx, "Local-only entry %s is in the history of the repository. Use 'gopass gc --days 0' to remove it", name)
+	}
+	debug.Log("checked local-only entries, %d leaks", len(leaks))
+}
+
+// fsckLocalChunks removes the chunks of local-only entries from the
+// repository, e.g. of entries that were chunked by an older version.
+func (s *Store) fsckLocalChunks(ctx context.Context, ls *local.Storage) error {
+	files, err := ls.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var lf []string
+	for _, f := range files {
+		if ls.IsLocal(f) {
+			lf = append(lf, f)
+		}
+	}
+
+	n, err := s.unchunkLocal(ctx, lf)
+	if err != nil || n < 1 {
+		return err
+	}
+
+	out.Printf(ctx, "Moved %d chunked local-only entries out of the repository", n)
+
+	return s.commitTombstones(ctx, fmt.Sprintf("fsck removed the chunks of %d local-only entries", n))
+}
+
+// unchunkLocal stores chunked local-only entries in one piece and removes
+// their chunks from the repository. It returns the number of entries that were
+// chunked.
+func (s *Store) unchunkLocal(ctx context.Context, files []string) (int, error) {
+	var n int
+	var chunks []string
+	for _, f := range files {
+		hashes := s.chunksOf(ctx, f)
+		if len(hashes) < 1 {
+			continue
+		}
+		chunks = append(chunks, hashes...)
+
+		ciphertext, err := s.getCiphertext(ctx, f)
+		if err != nil {
+			return n, fmt.Errorf("failed to read %s: %w", f, err)
+		}
+
+		if _, err := s.setCiphertext(ctx, f, ciphertext); err != nil {
+			return n, fmt.Errorf("failed to write %s: %w", f, err)
+		}
+		n++
+	}
+
+	if _, err := s.gcChunks(ctx, chunks); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package leaf
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/lock"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Acquire acquires the cross process lock of this store. It must be held
+// while modifying the store, so that concurrent gopass invocations can not
+// interleave their git operations. The lock is reentrant, so nested calls
+// within the same process are fine. Callers must call the returned function
+// to release the lock.
+func (s *Store) Acquire(ctx context.Context) (func(), error) {
+	s.lockOnce.Do(func() {
+		s.lock = lock.New(s.path)
+	})
+
+	if err := s.lock.Lock(ctx, ctxutil.IsLockWait(ctx)); err != nil {
+		return func() {}, err
+	}
+
+	return func() {
+		if err := s.lock.Unlock(); err != nil {
+			debug.Log("failed to unlock %s: %s", s.alias, err)
+		}
+
+		if !s.lock.Held() {
+			s.flushEvents()
+		}
+	}, nil
+}
@@ -0,0 +1,44 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/lock"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	sec := secrets.New()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "foo", sec))
+
+	// simulate a lock held by another (running) gopass process.
+	hn, err := os.Hostname()
+	require.NoError(t, err)
+	lf := lock.New(s.path).Path()
+	require.NoError(t, os.MkdirAll(filepath.Dir(lf), 0o700))
+	require.NoError(t, os.WriteFile(lf, []byte(fmt.Sprintf("%d\n%s\n%d\n", os.Getppid(), hn, time.Now().Unix())), 0o600))
+
+	err = s.Set(ctx, "bar", sec)
+	var le *lock.LockedError
+	assert.True(t, errors.As(err, &le), "%s", err)
+	assert.False(t, s.Exists(ctx, "bar"))
+
+	require.NoError(t, os.Remove(lf))
+	require.NoError(t, s.Set(ctx, "bar", sec))
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
 	"github.com/gopasspw/gopass/internal/store"
@@ -19,13 +20,23 @@ import (
 // supported. Each entry has to be decoded and encoded for the destination
 // to make sure it's encrypted for the right set of recipients.
 func (s *Store) Copy(ctx context.Context, from, to string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// recursive copy?
 	if s.IsDir(ctx, from) {
 		return fmt.Errorf("recursive operations are not supported")
 	}
 
+	if err := s.checkPinned(ctx, to); err != nil {
+		return err
+	}
+
 	// try direct copy first
-	err := s.directMove(ctx, from, to, false)
+	err = s.directMove(ctx, from, to, false)
 	if err == nil {
 		debug.Log("direct copy %s -> %s successful", from, to)
 
@@ -39,7 +50,7 @@ func (s *Store) Copy(ctx context.Context, from, to string) error {
 		return fmt.Errorf("failed to get %q from store: %w", from, err)
 	}
 
-	if err := s.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Copied from %s to %s", from, to)), to, content); err != nil {
+	if err := s.SetFrom(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Copied from %s to %s", from, to)), s, from, to, content); err != nil {
 		if !errors.Is(err, store.ErrMeaninglessWrite) {
 			return fmt.Errorf("failed to save secret %q to store: %w", to, err)
 		}
@@ -54,13 +65,25 @@ func (s *Store) Copy(ctx context.Context, from, to string) error {
 // for the destination store with the right set of recipients and remove it
 // from the old location afterwards.
 func (s *Store) Move(ctx context.Context, from, to string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// recursive move?
 	if s.IsDir(ctx, from) {
 		return fmt.Errorf("recursive operations are not supported")
 	}
 
+	for _, name := range []string{from, to} {
+		if err := s.checkPinned(ctx, name); err != nil {
+			return err
+		}
+	}
+
 	// try direct move first
-	err := s.directMove(ctx, from, to, true)
+	err = s.directMove(ctx, from, to, true)
 	if err == nil {
 		debug.Log("direct move %s -> %s successful", from, to)
 
@@ -75,7 +98,7 @@ func (s *Store) Move(ctx context.Context, from, to string) error {
 		return fmt.Errorf("failed to decrypt %q: %w", from, err)
 	}
 
-	if err := s.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Move from %s to %s", from, to)), to, content); err != nil {
+	if err := s.SetFrom(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Move from %s to %s", from, to)), s, from, to, content); err != nil {
 		if !errors.Is(err, store.ErrMeaninglessWrite) {
 			return fmt.Errorf("failed to save secret %q to store: %w", to, err)
 		}
@@ -96,10 +119,41 @@ func (s *Store) directMove(ctx context.Context, from, to string, del bool) error
 
 	debug.Log("directMove %s (%q) -> %s (%q)", from, to, pFrom, pTo)
 
+	// fall back to Set and Delete which propose the changes for review.
+	for _, name := range []string{from, to} {
+		if _, ok := s.needsReview(ctx, name); ok {
+			return fmt.Errorf("%s requires review", name)
+		}
+	}
+
 	if err := s.storage.Move(ctx, pFrom, pTo, del); err != nil {
 		return fmt.Errorf("failed to move %q to %q: %w", from, to, err)
 	}
 
+	if del {
+		if err := s.writeTombstones(ctx, from); err != nil {
+			return err
+		}
+	}
+
+	if err := s.removeTombstone(ctx, to); err != nil {
+		return err
+	}
+
+	// the ciphertext is still wrapped, so the protection moves along.
+	files := []string{pFrom, pTo}
+	if s.isProtected(ctx, from) {
+		if err := s.setProtected(WithNoGitOps(ctx, true), to, true); err != nil {
+			return err
+		}
+		if del {
+			if err := s.setProtected(WithNoGitOps(ctx, true), from, false); err != nil {
+				return err
+			}
+		}
+		files = append(files, protectedFile)
+	}
+
 	// It is not possible to perform concurrent git add and git commit commands
 	// so we need to skip this step when using concurrency and perform them
 	// at the end of the batch processing.
@@ -109,7 +163,7 @@ func (s *Store) directMove(ctx context.Context, from, to string, del bool) error
 		return nil
 	}
 
-	if err := s.storage.Add(ctx, pFrom, pTo); err != nil {
+	if err := s.storage.Add(ctx, files...); err != nil {
 		if errors.Is(err, store.ErrGitNotInit) {
 			return nil
 		}
@@ -145,9 +199,39 @@ func (s *Store) Prune(ctx context.Context, tree string) error {
 // delete will either delete one file or an directory tree depending on the
 // recurse flag.
 func (s *Store) delete(ctx context.Context, name string, recurse bool) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if recurse {
+		err = s.checkPinnedBelow(ctx, name)
+	} else {
+		err = s.checkPinned(ctx, name)
+	}
+	if err != nil {
+		return err
+	}
+
 	path := s.Passfile(name)
 
 	if recurse {
+		if err := s.checkPruneOwners(ctx, name); err != nil {
+			return err
+		}
+	} else if owners, ok := s.needsReview(ctx, name); ok {
+		if !s.storage.Exists(ctx, path) {
+			return store.ErrNotFound
+		}
+
+		return s.propose(ctx, OpDelete, name, owners, nil)
+	}
+
+	deleted := []string{name}
+	if recurse {
+		deleted = s.entriesBelow(ctx, name)
+
 		if err := s.deleteRecurse(ctx, name, path); err != nil {
 			return err
 		}
@@ -160,6 +244,18 @@ func (s *Store) delete(ctx context.Context, name string, recurse bool) error {
 		}
 	}
 
+	if err := s.writeTombstones(ctx, deleted...); err != nil {
+		return err
+	}
+
+	if err := s.unprotectDeleted(ctx, deleted...); err != nil {
+		return err
+	}
+
+	if !IsNoDeleteEvent(ctx) {
+		s.publish(ctx, event.Event{Type: event.EntryDeleted, Entries: deleted})
+	}
+
 	if !ctxutil.IsGitCommit(ctx) {
 		return nil
 	}
@@ -192,6 +288,32 @@ func (s *Store) delete(ctx context.Context, name string, recurse bool) error {
 	return nil
 }
 
+// entriesBelow returns the names of the entry and all entries below it.
+func (s *Store) entriesBelow(ctx context.Context, name string) []string {
+	name = strings.Trim(name, Sep)
+	files, err := s.storage.List(ctx, name)
+	if err != nil {
+		debug.Log("failed to list %s: %s", name, err)
+
+		return nil
+	}
+
+	ext := "." + s.crypto.Ext()
+	names := make([]string, 0, len(files))
+	for _, fn := range files {
+		if !strings.HasSuffix(fn, ext) {
+			continue
+		}
+
+		n := strings.TrimSuffix(fn, ext)
+		if n == name || strings.HasPrefix(n, name+Sep) {
+			names = append(names, n)
+		}
+	}
+
+	return names
+}
+
 func (s *Store) deleteRecurse(ctx context.Context, name, path string) error {
 	if !s.storage.IsDir(ctx, name) && !s.storage.Exists(ctx, path) {
 		return store.ErrNotFound
@@ -0,0 +1,461 @@
+package leaf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets/secparse"
+)
+
+const (
+	// OwnersFile lists the recipients owning a folder, one per line. The
+	// file closest to an entry applies.
+	OwnersFile = "OWNERS"
+	// pendingDir holds the changes waiting for review.
+	pendingDir = ".gopass-pending"
+)
+
+// Operations of pending changes.
+const (
+	OpSet    = "set"
+	OpDelete = "delete"
+)
+
+var (
+	// ErrNotOwner is returned if only an owner may perform an operation.
+	ErrNotOwner = errors.New("not an owner")
+	// ErrNoPending is returned if there is no pending change with the given ID.
+	ErrNoPending = errors.New("no such pending change")
+)
+
+// Pending is a change to an owned folder that waits for the approval of
+// one of its owners.
+type Pending struct {
+	ID      string    `json:"id"`
+	Op      string    `json:"op"`
+	Name    string    `json:"name"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Message string    `json:"message,omitempty"`
+	Owners  []string  `json:"owners"`
+	// Ciphertext is the new content of the entry, encrypted for the same
+	// recipients as the entry itself. Empty for deletes.
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+	// Store is the mount point of the store the change belongs to.
+	Store string `json:"-"`
+}
+
+// ownersFile returns the path to the OWNERS file that applies to the
+// given entry or an empty string if there is none.
+func (s *Store) ownersFile(ctx context.Context, name string) string {
+	fn := path.Dir(strings.TrimPrefix(name, Sep))
+
+	for i := 0; i < 100; i++ {
+		if fn == "." || fn == "" || fn == Sep {
+			break
+		}
+
+		ofn := path.Join(fn, OwnersFile)
+		if s.storage.Exists(ctx, ofn) {
+			return ofn
+		}
+
+		fn = path.Dir(fn)
+	}
+
+	if s.storage.Exists(ctx, OwnersFile) {
+		return OwnersFile
+	}
+
+	return ""
+}
+
+// Owners returns the owners of the given entry. Entries without an OWNERS
+// file have no owners.
+func (s *Store) Owners(ctx context.Context, name string) []string {
+	fn := s.ownersFile(ctx, name)
+	if fn == "" {
+		return nil
+	}
+
+	return s.readOwners(ctx, fn)
+}
+
+// FolderOwners returns the owners listed in the OWNERS file of exactly this
+// folder, ignoring the ones inherited from parent folders.
+func (s *Store) FolderOwners(ctx context.Context, dir string) []string {
+	fn := path.Join(strings.Trim(dir, Sep), OwnersFile)
+	if !s.storage.Exists(ctx, fn) {
+		return nil
+	}
+
+	return s.readOwners(ctx, fn)
+}
+
+// SetFolderOwners writes the OWNERS file of the folder.
+func (s *Store) SetFolderOwners(ctx context.Context, dir string, owners []string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fn := path.Join(strings.Trim(dir, Sep), OwnersFile)
+	if err := s.storage.Set(ctx, fn, []byte(strings.Join(owners, "\n")+"\n")); err != nil {
+		if errors.Is(err, store.ErrMeaninglessWrite) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to write %s: %w", fn, err)
+	}
+
+	if err := s.storage.Add(ctx, fn); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add %q to git: %w", fn, err)
+	}
+
+	if !ctxutil.IsGitCommit(ctx) {
+		return nil
+	}
+
+	return s.gitCommitAndPush(ctx, fn)
+}
+
+func (s *Store) readOwners(ctx context.Context, fn string) []string {
+	buf, err := s.storage.Get(ctx, fn)
+	if err != nil {
+		debug.Log("failed to read %s: %s", fn, err)
+
+		return nil
+	}
+
+	var owners []string
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			owners = append(owners, line)
+		}
+	}
+
+	return owners
+}
+
+// isOwner returns true if one of our identities is among the owners.
+func (s *Store) isOwner(ctx context.Context, owners []string) bool {
+	if len(owners) < 1 {
+		return true
+	}
+
+	kl, err := s.crypto.FindIdentities(ctx, owners...)
+	if err != nil {
+		debug.Log("failed to find owners %q: %s", owners, err)
+
+		return false
+	}
+
+	return len(kl) > 0
+}
+
+// needsReview returns the owners of the entry if changing it requires their
+// approval.
+func (s *Store) needsReview(ctx context.Context, name string) ([]string, bool) {
+	if IsNoReview(ctx) {
+		return nil, false
+	}
+
+	owners := s.Owners(ctx, name)
+	if s.isOwner(ctx, owners) {
+		return nil, false
+	}
+
+	return owners, true
+}
+
+// checkPruneOwners makes sure that removing the tree doesn't remove any
+// entries we don't own. Pruning can't be proposed for review.
+func (s *Store) checkPruneOwners(ctx context.Context, tree string) error {
+	if IsNoReview(ctx) {
+		return nil
+	}
+
+	entries, err := s.List(ctx, strings.TrimPrefix(tree, Sep))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		e = strings.TrimPrefix(strings.TrimPrefix(e, s.alias), Sep)
+		if owners, ok := s.needsReview(ctx, e); ok {
+			return fmt.Errorf("removing %s requires the approval of %s: %w", e, strings.Join(owners, ", "), ErrNotOwner)
+		}
+	}
+
+	return nil
+}
+
+// propose records a change for review instead of applying it.
+func (s *Store) propose(ctx context.Context, op, name string, owners []string, ciphertext []byte) error {
+	id, err := newPendingID()
+	if err != nil {
+		return err
+	}
+
+	p := &Pending{
+		ID:         id,
+		Op:         op,
+		Name:       strings.TrimPrefix(name, Sep),
+		Author:     s.OurKeyID(ctx),
+		Created:    time.Now().UTC(),
+		Message:    ctxutil.GetCommitMessage(ctx),
+		Owners:     owners,
+		Ciphertext: ciphertext,
+	}
+
+	buf, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fn := pendingFile(id)
+	if err := s.storage.Set(ctx, fn, buf); err != nil {
+		return fmt.Errorf("failed to write pending change: %w", err)
+	}
+
+	if err := s.commitFiles(ctx, fmt.Sprintf("Proposed change %s to %s", id, p.Name), fn); err != nil {
+		return err
+	}
+
+	out.Noticef(ctx, "%s is owned by %s. Your change was recorded as %s and waits for review (gopass review)", p.Name, strings.Join(owners, ", "), id)
+
+	return nil
+}
+
+// ListPending returns all changes waiting for review, oldest first.
+func (s *Store) ListPending(ctx context.Context) ([]*Pending, error) {
+	files, err := s.storage.List(ctx, pendingDir+Sep)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Pending, 0, len(files))
+	for _, fn := range files {
+		if !strings.HasPrefix(fn, pendingDir+Sep) || !strings.HasSuffix(fn, ".json") {
+			continue
+		}
+
+		p, err := s.loadPending(ctx, fn)
+		if err != nil {
+			debug.Log("skipping %s: %s", fn, err)
+
+			continue
+		}
+		res = append(res, p)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+// HasPending returns true if there is a pending change with the given ID.
+func (s *Store) HasPending(ctx context.Context, id string) bool {
+	_, err := s.loadPending(ctx, pendingFile(id))
+
+	return err == nil
+}
+
+// GetPending returns the pending change with the given ID and, unless it's
+// a delete, the proposed content.
+func (s *Store) GetPending(ctx context.Context, id string) (*Pending, gopass.Secret, error) {
+	p, err := s.loadPending(ctx, pendingFile(id))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.Op != OpSet {
+		return p, nil, nil
+	}
+
+	sec, err := s.decryptPending(ctx, p)
+
+	return p, sec, err
+}
+
+// decryptPending returns the proposed content of a pending change.
+func (s *Store) decryptPending(ctx context.Context, p *Pending) (gopass.Secret, error) {
+	content, err := s.crypto.Decrypt(ctx, p.Ciphertext)
+	if err != nil {
+		return nil, store.ErrDecrypt
+	}
+
+	content, err = decompress(content)
+	if err != nil {
+		return nil, store.ErrDecrypt
+	}
+
+	return secparse.Parse(content)
+}
+
+// ApprovePending applies the pending change with the given ID. Only owners
+// of the entry can approve a change.
+func (s *Store) ApprovePending(ctx context.Context, id string) (*Pending, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	fn := pendingFile(id)
+
+	p, err := s.loadPending(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	// the owners might have changed since the change was proposed.
+	if !s.isOwner(ctx, s.Owners(ctx, p.Name)) {
+		return p, ErrNotOwner
+	}
+
+	pf := s.Passfile(p.Name)
+	changed := []string{pf, fn}
+	switch p.Op {
+	case OpSet:
+		// the proposal was encrypted by the author. Writing it through Set
+		// applies the checks, protection and recipients of this store.
+		sec, err := s.decryptPending(ctx, p)
+		if err != nil {
+			return p, fmt.Errorf("failed to decrypt pending change: %w", err)
+		}
+
+		if err := s.Set(WithNoReview(ctxutil.WithGitCommit(ctx, false), true), p.Name, sec); err != nil {
+			return p, fmt.Errorf("failed to write secret: %w", err)
+		}
+	case OpDelete:
+		if err := s.storage.Delete(ctx, pf); err != nil && s.storage.Exists(ctx, pf) {
+			return p, fmt.Errorf("failed to delete secret: %w", err)
+		}
+	default:
+		return p, fmt.Errorf("unknown operation %q", p.Op)
+	}
+
+	if err := s.storage.Delete(ctx, fn); err != nil {
+		return p, fmt.Errorf("failed to remove pending change: %w", err)
+	}
+
+	return p, s.commitFiles(ctx, fmt.Sprintf("Approved change %s to %s", id, p.Name), changed...)
+}
+
+// RejectPending discards the pending change with the given ID. Owners of the
+// entry and the author of the change can reject it.
+func (s *Store) RejectPending(ctx context.Context, id string) (*Pending, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	fn := pendingFile(id)
+
+	p, err := s.loadPending(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	isAuthor := p.Author != "" && s.isOwner(ctx, []string{p.Author})
+	if !isAuthor && !s.isOwner(ctx, s.Owners(ctx, p.Name)) {
+		return p, ErrNotOwner
+	}
+
+	if err := s.storage.Delete(ctx, fn); err != nil {
+		return p, fmt.Errorf("failed to remove pending change: %w", err)
+	}
+
+	return p, s.commitFiles(ctx, fmt.Sprintf("Rejected change %s to %s", id, p.Name), fn)
+}
+
+func (s *Store) loadPending(ctx context.Context, fn string) (*Pending, error) {
+	// IDs are given by the user, make sure they don't point elsewhere.
+	id := strings.TrimSuffix(path.Base(fn), ".json")
+	if _, err := hex.DecodeString(id); err != nil || path.Dir(fn) != pendingDir {
+		return nil, ErrNoPending
+	}
+
+	if !s.storage.Exists(ctx, fn) {
+		return nil, ErrNoPending
+	}
+
+	buf, err := s.storage.Get(ctx, fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fn, err)
+	}
+
+	p := &Pending{}
+	if err := json.Unmarshal(buf, p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fn, err)
+	}
+	p.Store = s.alias
+
+	return p, nil
+}
+
+func (s *Store) commitFiles(ctx context.Context, msg string, files ...string) error {
+	if err := s.storage.Add(ctx, files...); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add %q to git: %w", files, err)
+	}
+
+	if !ctxutil.IsGitCommit(ctx) {
+		return nil
+	}
+
+	if err := s.storage.Commit(ctx, msg); err != nil {
+		switch {
+		case errors.Is(err, store.ErrGitNotInit):
+			debug.Log("skipping git commit - git not initialized")
+		case errors.Is(err, store.ErrGitNothingToCommit):
+			debug.Log("skipping git commit - nothing to commit")
+		default:
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	return s.reencryptGitPush(ctx)
+}
+
+func pendingFile(id string) string {
+	return path.Join(pendingDir, id+".json")
+}
+
+func newPendingID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate change ID: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,106 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	require.NoError(t, s.storage.Set(ctx, "team/"+OwnersFile, []byte("# the team\n0xBADC0FFEE\n")))
+	assert.Equal(t, []string{"0xBADC0FFEE"}, s.Owners(ctx, "team/db/password"))
+	assert.Empty(t, s.Owners(ctx, "other/password"))
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foo")
+
+	t.Run("unowned folders are written directly", func(t *testing.T) {
+		require.NoError(t, s.Set(ctx, "other/password", sec))
+		assert.True(t, s.Exists(ctx, "other/password"))
+	})
+
+	var id string
+	t.Run("changes by non-owners are pending", func(t *testing.T) {
+		require.NoError(t, s.Set(ctx, "team/db/password", sec))
+		assert.False(t, s.Exists(ctx, "team/db/password"))
+
+		ps, err := s.ListPending(ctx)
+		require.NoError(t, err)
+		require.Len(t, ps, 1)
+		assert.Equal(t, OpSet, ps[0].Op)
+		assert.Equal(t, "team/db/password", ps[0].Name)
+		assert.Equal(t, []string{"0xBADC0FFEE"}, ps[0].Owners)
+		id = ps[0].ID
+
+		p, got, err := s.GetPending(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, p.ID)
+		assert.Equal(t, "foo", got.Password())
+
+		// the pending change is not a secret.
+		lst, err := s.List(ctx, "")
+		require.NoError(t, err)
+		assert.NotContains(t, lst, "team/db/password")
+	})
+
+	t.Run("only owners approve", func(t *testing.T) {
+		_, err := s.ApprovePending(ctx, id)
+		assert.ErrorIs(t, err, ErrNotOwner)
+
+		_, err = s.ApprovePending(ctx, "../../etc/passwd")
+		assert.ErrorIs(t, err, ErrNoPending)
+	})
+
+	t.Run("owners approve", func(t *testing.T) {
+		require.NoError(t, s.storage.Set(ctx, "team/"+OwnersFile, []byte("0xBADC0FFEE\n0xDEADBEEF\n")))
+
+		p, err := s.ApprovePending(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "team/db/password", p.Name)
+		assert.True(t, s.Exists(ctx, "team/db/password"))
+
+		ps, err := s.ListPending(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+	})
+
+	t.Run("deletes by non-owners are pending", func(t *testing.T) {
+		require.NoError(t, s.storage.Set(ctx, "team/"+OwnersFile, []byte("0xBADC0FFEE\n")))
+
+		require.NoError(t, s.Delete(ctx, "team/db/password"))
+		assert.True(t, s.Exists(ctx, "team/db/password"))
+		assert.ErrorIs(t, s.Prune(ctx, "team"), ErrNotOwner)
+
+		ps, err := s.ListPending(ctx)
+		require.NoError(t, err)
+		require.Len(t, ps, 1)
+		assert.Equal(t, OpDelete, ps[0].Op)
+
+		// the author can withdraw the change.
+		_, err = s.RejectPending(ctx, ps[0].ID)
+		require.NoError(t, err)
+
+		ps, err = s.ListPending(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+	})
+
+	t.Run("re-encryption needs no review", func(t *testing.T) {
+		sec.SetPassword("bar")
+		require.NoError(t, s.Set(WithNoReview(ctx, true), "team/db/password", sec))
+
+		ps, err := s.ListPending(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+	})
+}
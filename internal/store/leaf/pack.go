@@ -0,0 +1,85 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/backend/storage/pack"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// IsPacked returns true if this store uses the pack layout.
+func (s *Store) IsPacked() bool {
+	_, ok := local.Unwrap(s.storage).(*pack.Storage)
+
+	return ok
+}
+
+// batch defers the writes to the bundles of a packed store until the
+// returned function is called. It's a no-op for other layouts.
+func (s *Store) batch() (func(context.Context) error, bool) {
+	ps, ok := local.Unwrap(s.storage).(*pack.Storage)
+	if !ok {
+		return func(context.Context) error { return nil }, false
+	}
+
+	return ps.Batch(), true
+}
+
+// SetPacked converts the store to or from the pack layout.
+func (s *Store) SetPacked(ctx context.Context, packed bool) error {
+	if s.IsPacked() == packed {
+		return nil
+	}
+
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// local-only entries are not part of the layout.
+	st := local.Unwrap(s.storage)
+
+	var changed []string
+	msg := "Convert to pack layout"
+	if packed {
+		changed, err = pack.Pack(ctx, st)
+	} else {
+		msg = "Convert to loose layout"
+		changed, err = pack.Unpack(ctx, st)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert layout: %w", err)
+	}
+
+	if packed {
+		st = pack.New(st)
+	} else if ps, ok := st.(*pack.Storage); ok {
+		st = ps.Unwrap()
+	}
+
+	if ls, ok := s.storage.(*local.Storage); ok {
+		ls.Storage = st
+	} else {
+		s.storage = st
+	}
+	debug.Log("converted %d files of %s. packed: %t", len(changed), s.path, packed)
+
+	if err := s.storage.Add(ctx, s.path); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add changes to git: %w", err)
+	}
+
+	if err := s.storage.Commit(ctx, msg); err != nil && !errors.Is(err, store.ErrGitNothingToCommit) {
+		return fmt.Errorf("failed to commit changes to git: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package leaf
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/storage/pack"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPacked(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	sec := secrets.New()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "web/foo", sec))
+
+	before, err := s.List(ctx, "")
+	require.NoError(t, err)
+
+	assert.False(t, s.IsPacked())
+	require.NoError(t, s.SetPacked(ctx, true))
+	assert.True(t, s.IsPacked())
+	assert.FileExists(t, filepath.Join(s.path, "web", pack.BundleFile))
+	assert.NoFileExists(t, filepath.Join(s.path, s.Passfile("web/foo")))
+
+	after, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	got, err := s.Get(ctx, "web/foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got.Password())
+
+	require.NoError(t, s.Set(ctx, "web/bar", sec))
+	assert.True(t, s.Exists(ctx, "web/bar"))
+
+	// the layout is detected when the store is opened again.
+	s2, err := New(ctx, s.alias, s.path)
+	require.NoError(t, err)
+	assert.True(t, s2.IsPacked())
+	assert.True(t, s2.Exists(ctx, "web/bar"))
+
+	require.NoError(t, s.SetPacked(ctx, false))
+	assert.False(t, s.IsPacked())
+	assert.FileExists(t, filepath.Join(s.path, s.Passfile("web/bar")))
+	assert.True(t, s.Exists(ctx, "web/foo"))
+}
@@ -0,0 +1,166 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// pinFile lists the pinned entries of a store, one per line. Pinned entries
+// can't be changed, moved or removed until they are unpinned. The file is
+// part of the store, so the pins apply to every device and team member.
+const pinFile = ".gopass-pinned"
+
+// Pinned returns the pinned entries, sorted.
+func (s *Store) Pinned(ctx context.Context) []string {
+	return s.readList(ctx, pinFile)
+}
+
+// readList returns the sorted entries of a list file like pinFile.
+func (s *Store) readList(ctx context.Context, fn string) []string {
+	if !s.storage.Exists(ctx, fn) {
+		return nil
+	}
+
+	buf, err := s.storage.Get(ctx, fn)
+	if err != nil {
+		debug.Log("failed to read %s: %s", fn, err)
+
+		return nil
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			names = append(names, line)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// IsPinned returns true if the entry is pinned.
+func (s *Store) IsPinned(ctx context.Context, name string) bool {
+	return set.Contains(s.Pinned(ctx), strings.TrimPrefix(name, Sep))
+}
+
+// Pin pins an existing entry.
+func (s *Store) Pin(ctx context.Context, name string) error {
+	name = strings.TrimPrefix(name, Sep)
+	if !s.Exists(ctx, name) {
+		return store.ErrNotFound
+	}
+
+	pins := s.Pinned(ctx)
+	if set.Contains(pins, name) {
+		return nil
+	}
+
+	return s.writePins(ctx, append(pins, name), "Pinned "+name)
+}
+
+// Unpin unpins an entry.
+func (s *Store) Unpin(ctx context.Context, name string) error {
+	name = strings.TrimPrefix(name, Sep)
+
+	pins := s.Pinned(ctx)
+	if !set.Contains(pins, name) {
+		return fmt.Errorf("%s is not pinned", name)
+	}
+
+	return s.writePins(ctx, set.Filter(pins, name), "Unpinned "+name)
+}
+
+func (s *Store) writePins(ctx context.Context, pins []string, msg string) error {
+	return s.writeList(ctx, pinFile, "# Pinned entries can't be changed until they are unpinned with 'gopass pin --unpin'.", pins, msg)
+}
+
+// writeList writes and commits a list file like pinFile.
+func (s *Store) writeList(ctx context.Context, fn, header string, names []string, msg string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	_, _ = buf.WriteString(header + "\n")
+	for _, p := range names {
+		_, _ = buf.WriteString(p + "\n")
+	}
+
+	if err := s.storage.Set(ctx, fn, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fn, err)
+	}
+
+	if IsNoGitOps(ctx) {
+		return nil
+	}
+
+	if err := s.storage.Add(ctx, fn); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add %q to git: %w", fn, err)
+	}
+
+	if err := s.storage.Commit(ctx, msg); err != nil && !errors.Is(err, store.ErrGitNothingToCommit) {
+		return fmt.Errorf("failed to commit changes to git: %w", err)
+	}
+
+	return nil
+}
+
+// checkPinned returns an error if the entry is pinned.
+func (s *Store) checkPinned(ctx context.Context, name string) error {
+	if !s.IsPinned(ctx, name) {
+		return nil
+	}
+
+	return pinnedError(s.alias, name)
+}
+
+// checkPinnedBelow returns an error if any entry below tree is pinned.
+func (s *Store) checkPinnedBelow(ctx context.Context, tree string) error {
+	tree = strings.TrimSuffix(strings.TrimPrefix(tree, Sep), Sep)
+	for _, p := range s.Pinned(ctx) {
+		if p == tree || strings.HasPrefix(p, tree+Sep) {
+			return pinnedError(s.alias, p)
+		}
+	}
+
+	return nil
+}
+
+// checkPinnedWrite returns an error if the entry is pinned and content would
+// change it. Writing the same content, e.g. to re-encrypt it, is allowed.
+func (s *Store) checkPinnedWrite(ctx context.Context, name string, content []byte) error {
+	if !s.IsPinned(ctx, name) {
+		return nil
+	}
+
+	if cur, err := s.Get(ctx, name); err == nil && bytes.Equal(cur.Bytes(), content) {
+		return nil
+	}
+
+	return pinnedError(s.alias, name)
+}
+
+func pinnedError(alias, name string) error {
+	if alias != "" {
+		name = alias + Sep + name
+	}
+
+	return fmt.Errorf("%s: %w. Run 'gopass pin --unpin %s' to change it", name, store.ErrPinned, name)
+}
@@ -0,0 +1,49 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPin(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "bank/pin", sec))
+
+	assert.ErrorIs(t, s.Pin(ctx, "bank/missing"), store.ErrNotFound)
+	require.NoError(t, s.Pin(ctx, "bank/pin"))
+	require.NoError(t, s.Pin(ctx, "bank/pin"))
+	assert.Equal(t, []string{"bank/pin"}, s.Pinned(ctx))
+	assert.True(t, s.IsPinned(ctx, "bank/pin"))
+
+	t.Run("same content", func(t *testing.T) {
+		cur, err := s.Get(ctx, "bank/pin")
+		require.NoError(t, err)
+		assert.NotErrorIs(t, s.Set(ctx, "bank/pin", cur), store.ErrPinned)
+	})
+
+	t.Run("changes are refused", func(t *testing.T) {
+		other := secrets.NewAKV()
+		other.SetPassword("bar")
+		assert.ErrorIs(t, s.Set(ctx, "bank/pin", other), store.ErrPinned)
+		assert.ErrorIs(t, s.Delete(ctx, "bank/pin"), store.ErrPinned)
+		assert.ErrorIs(t, s.Prune(ctx, "bank"), store.ErrPinned)
+		assert.ErrorIs(t, s.Move(ctx, "bank/pin", "bank/other"), store.ErrPinned)
+		assert.True(t, s.Exists(ctx, "bank/pin"))
+	})
+
+	require.NoError(t, s.Unpin(ctx, "bank/pin"))
+	assert.Error(t, s.Unpin(ctx, "bank/pin"))
+	assert.Empty(t, s.Pinned(ctx))
+	assert.NoError(t, s.Delete(ctx, "bank/pin"))
+}
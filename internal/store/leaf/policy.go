@@ -0,0 +1,111 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/policy"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// policyDir holds the policy packs of a store.
+const policyDir = ".gopass-policy"
+
+// ErrPolicyDowngrade is returned when importing an older version of an
+// existing policy pack.
+var ErrPolicyDowngrade = errors.New("policy pack is older than the installed one")
+
+// PolicyPacks returns all policy packs of this store, sorted by name.
+// Packs that fail to parse are skipped.
+func (s *Store) PolicyPacks(ctx context.Context) []*policy.Pack {
+	files, err := s.storage.List(ctx, policyDir+Sep)
+	if err != nil {
+		debug.Log("failed to list policy packs: %s", err)
+
+		return nil
+	}
+
+	packs := make([]*policy.Pack, 0, len(files))
+	for _, fn := range files {
+		if !strings.HasPrefix(fn, policyDir+Sep) || !strings.HasSuffix(fn, ".json") {
+			continue
+		}
+
+		buf, err := s.storage.Get(ctx, fn)
+		if err != nil {
+			debug.Log("failed to read %s: %s", fn, err)
+
+			continue
+		}
+
+		p, err := policy.Parse(buf)
+		if err != nil {
+			debug.Log("skipping %s: %s", fn, err)
+
+			continue
+		}
+		packs = append(packs, p)
+	}
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Name < packs[j].Name
+	})
+
+	return packs
+}
+
+// ImportPolicyPack adds or updates the given policy pack. Unless force is
+// set, packs can only be replaced by newer versions.
+func (s *Store) ImportPolicyPack(ctx context.Context, buf []byte, force bool) (*policy.Pack, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	p, err := policy.Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, old := range s.PolicyPacks(ctx) {
+		if old.Name == p.Name && old.Version > p.Version && !force {
+			return nil, fmt.Errorf("%s v%d < v%d: %w", p.Name, p.Version, old.Version, ErrPolicyDowngrade)
+		}
+	}
+
+	fn := policyFile(p.Name)
+	if err := s.storage.Set(ctx, fn, buf); err != nil {
+		return nil, fmt.Errorf("failed to write policy pack: %w", err)
+	}
+
+	return p, s.commitFiles(ctx, fmt.Sprintf("Imported policy pack %s v%d", p.Name, p.Version), fn)
+}
+
+// RemovePolicyPack removes the policy pack with the given name.
+func (s *Store) RemovePolicyPack(ctx context.Context, name string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fn := policyFile(name)
+	if path.Dir(fn) != policyDir || !s.storage.Exists(ctx, fn) {
+		return fmt.Errorf("no policy pack %q", name)
+	}
+
+	if err := s.storage.Delete(ctx, fn); err != nil {
+		return fmt.Errorf("failed to remove policy pack: %w", err)
+	}
+
+	return s.commitFiles(ctx, fmt.Sprintf("Removed policy pack %s", name), fn)
+}
+
+func policyFile(name string) string {
+	return path.Join(policyDir, name+".json")
+}
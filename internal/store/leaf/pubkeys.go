@@ -0,0 +1,130 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// ErrPubkeyMismatch is returned if an exported public key does not match the
+// recipient it was exported for or its pinned fingerprint.
+var ErrPubkeyMismatch = fmt.Errorf("public key mismatch")
+
+var reHexID = regexp.MustCompile(`^(?i)(0x)?[0-9a-f]{8,40}$`)
+
+type keyFingerprinter interface {
+	ReadFingerprintsFromKey(ctx context.Context, buf []byte) ([]string, error)
+}
+
+// pinKey returns the config key holding the pinned fingerprint of a recipient.
+func pinKey(r string) string {
+	return fmt.Sprintf("pubkeys.%s.fingerprint", r)
+}
+
+// PinnedFingerprint returns the fingerprint pinned for the given recipient, if any.
+func PinnedFingerprint(ctx context.Context, r string) string {
+	return config.FromContext(ctx).Get(pinKey(r))
+}
+
+// verifyPublicKey makes sure that the public key exported to the store for the
+// given recipient actually belongs to that recipient (as listed in the
+// recipients file) and that it matches the fingerprint pinned on first import,
+// if any. It returns the fingerprint of the primary key.
+func (s *Store) verifyPublicKey(ctx context.Context, r string) (string, error) {
+	kf, ok := s.crypto.(keyFingerprinter)
+	if !ok {
+		debug.Log("not verifying public keys for %T", s.crypto)
+
+		return "", nil
+	}
+
+	pk, err := s.getPublicKey(ctx, r)
+	if err != nil {
+		return "", err
+	}
+
+	fps, err := kf.ReadFingerprintsFromKey(ctx, pk)
+	if err != nil {
+		return "", fmt.Errorf("failed to read fingerprints from public key %q: %w", r, err)
+	}
+
+	if len(fps) < 1 {
+		return "", fmt.Errorf("public key %q has no fingerprints: %w", r, ErrPubkeyMismatch)
+	}
+
+	if err := s.matchRecipient(ctx, r, pk, fps); err != nil {
+		return "", err
+	}
+
+	fp := fps[0]
+	if pin := PinnedFingerprint(ctx, r); pin != "" && !strings.EqualFold(pin, fp) {
+		return "", fmt.Errorf("public key for %q has fingerprint %s but %s is pinned: %w", r, fp, pin, ErrPubkeyMismatch)
+	}
+
+	return fp, nil
+}
+
+// matchRecipient checks if the given key material matches the recipient ID.
+// Fingerprints must be equal to one of the key fingerprints and long key IDs
+// must be equal to the key ID of one of them. Short key IDs are ambiguous and
+// never match. Anything else (e.g. an email address) must be the email
+// address or the full name of one of the identities of the key.
+func (s *Store) matchRecipient(ctx context.Context, r string, pk []byte, fps []string) error {
+	if reHexID.MatchString(r) {
+		id := strings.ToUpper(strings.TrimPrefix(strings.ToLower(r), "0x"))
+		for _, fp := range fps {
+			fp = strings.ToUpper(fp)
+			if id == fp || (len(id) == 16 && len(fp) > 16 && id == fp[len(fp)-16:]) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("public key for %q has fingerprints %v: %w", r, fps, ErrPubkeyMismatch)
+	}
+
+	names, err := s.crypto.ReadNamesFromKey(ctx, pk)
+	if err != nil {
+		return fmt.Errorf("failed to read names from public key %q: %w", r, err)
+	}
+
+	for _, name := range names {
+		if strings.EqualFold(name, r) || strings.EqualFold(emailFromIdentity(name), r) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("public key for %q has identities %v: %w", r, names, ErrPubkeyMismatch)
+}
+
+// emailFromIdentity returns the email address of an identity like
+// "John Doe <john.doe@example.org>".
+func emailFromIdentity(name string) string {
+	start := strings.LastIndex(name, "<")
+	end := strings.LastIndex(name, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return name[start+1 : end]
+}
+
+// pinPublicKey records the fingerprint of an imported public key in the
+// per-user config so that later substitutions of the exported key are
+// detected.
+func (s *Store) pinPublicKey(ctx context.Context, r, fp string) {
+	if fp == "" || PinnedFingerprint(ctx, r) != "" {
+		return
+	}
+
+	if err := config.FromContext(ctx).Set("", pinKey(r), fp); err != nil {
+		debug.Log("failed to pin public key %s for %s: %s", fp, r, err)
+
+		return
+	}
+
+	debug.Log("pinned public key %s for %s", fp, r)
+}
@@ -0,0 +1,69 @@
+package leaf
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fpMocker treats the exported key as a comma separated list of fingerprints.
+type fpMocker struct {
+	*plain.Mocker
+}
+
+func (f fpMocker) ReadFingerprintsFromKey(ctx context.Context, buf []byte) ([]string, error) {
+	return strings.Split(string(buf), ","), nil
+}
+
+func (f fpMocker) ReadNamesFromKey(ctx context.Context, buf []byte) ([]string, error) {
+	return []string{"John Doe <john.doe@example.org>"}, nil
+}
+
+func TestVerifyPublicKey(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	tempdir := t.TempDir()
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  fpMocker{plain.New()},
+		storage: fs.New(tempdir),
+	}
+
+	fp := "7379880F3D29A3B03F44B73D0C92225A97F6B666"
+	for _, r := range []string{"0x0C92225A97F6B666", "john.doe@example.org", "foo@example.org", "example.org", "0xDEADBEEF", "0x97F6B666", "0C92225A97F6B666B907"} {
+		require.NoError(t, s.storage.Set(ctx, filepath.Join(keyDir, r), []byte(fp+",B907B9C09EA4CDB96CA8E656B32472BA4A6EAC01")))
+	}
+
+	got, err := s.verifyPublicKey(ctx, "0x0C92225A97F6B666")
+	require.NoError(t, err)
+	assert.Equal(t, fp, got)
+
+	_, err = s.verifyPublicKey(ctx, "john.doe@example.org")
+	require.NoError(t, err)
+
+	_, err = s.verifyPublicKey(ctx, "foo@example.org")
+	assert.ErrorIs(t, err, ErrPubkeyMismatch)
+
+	_, err = s.verifyPublicKey(ctx, "example.org")
+	assert.ErrorIs(t, err, ErrPubkeyMismatch)
+
+	for _, r := range []string{"0xDEADBEEF", "0x97F6B666", "0C92225A97F6B666B907"} {
+		_, err = s.verifyPublicKey(ctx, r)
+		assert.ErrorIs(t, err, ErrPubkeyMismatch, r)
+	}
+
+	s.pinPublicKey(ctx, "john.doe@example.org", "B907B9C09EA4CDB96CA8E656B32472BA4A6EAC01")
+	assert.Equal(t, "B907B9C09EA4CDB96CA8E656B32472BA4A6EAC01", PinnedFingerprint(ctx, "john.doe@example.org"))
+
+	_, err = s.verifyPublicKey(ctx, "john.doe@example.org")
+	assert.ErrorIs(t, err, ErrPubkeyMismatch)
+}
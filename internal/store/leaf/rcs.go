@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/fido2"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -33,7 +34,7 @@ func (s *Store) ListRevisions(ctx context.Context, name string) ([]backend.Revis
 // GetRevision will retrieve a single revision from the backend.
 func (s *Store) GetRevision(ctx context.Context, name, revision string) (gopass.Secret, error) {
 	p := s.Passfile(name)
-	ciphertext, err := s.storage.GetRevision(ctx, p, revision)
+	ciphertext, err := s.getCiphertextRevision(ctx, p, revision)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ciphertext of %q@%q: %w", name, revision, err)
 	}
@@ -45,6 +46,22 @@ func (s *Store) GetRevision(ctx context.Context, name, revision string) (gopass.
 		return nil, store.ErrDecrypt
 	}
 
+	if fido2.IsProtected(content) {
+		content, _, err = fido2.Unwrap(ctx, s.authenticator(ctx), content)
+		if err != nil {
+			out.Errorf(ctx, "Failed to unwrap %s with the FIDO2 token: %s", name, err)
+
+			return nil, store.ErrDecrypt
+		}
+	}
+
+	content, err = decompress(content)
+	if err != nil {
+		debug.Log("Decompression failed: %s", err)
+
+		return nil, store.ErrDecrypt
+	}
+
 	sec, err := secparse.Parse(content)
 	if err != nil {
 		debug.Log("Failed to parse YAML: %s", err)
@@ -1,8 +1,13 @@
 package leaf
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"strings"
 
+	"github.com/gopasspw/gopass/internal/fido2"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -10,33 +15,133 @@ import (
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets/secparse"
+	"github.com/gopasspw/gopass/pkg/termio"
 )
 
 // Get returns the plaintext of a single key.
 func (s *Store) Get(ctx context.Context, name string) (gopass.Secret, error) {
 	p := s.Passfile(name)
 
-	ciphertext, err := s.storage.Get(ctx, p)
+	ciphertext, err := s.getCiphertext(ctx, p)
 	if err != nil {
 		debug.Log("File %s not found: %s", p, err)
 
 		return nil, store.ErrNotFound
 	}
 
+	var recovered bool
+	span := debug.Start("decrypting %s", name)
 	content, err := s.crypto.Decrypt(ctx, ciphertext)
+	span.End()
 	if err != nil {
-		out.Errorf(ctx, "Decryption failed: %s\n%s", err, string(content))
+		rc, ok := s.recoverCorrupted(ctx, name, p, ciphertext, err)
+		if !ok {
+			out.Errorf(ctx, "Decryption failed: %s\n%s", err, string(content))
+
+			return nil, store.ErrDecrypt
+		}
+		content = rc
+		recovered = true
+	}
+
+	var key *fido2.Key
+	if fido2.IsProtected(content) {
+		content, key, err = fido2.Unwrap(ctx, s.authenticator(ctx), content)
+		if err != nil {
+			out.Errorf(ctx, "Failed to unwrap %s with the FIDO2 token: %s", name, err)
+
+			return nil, store.ErrDecrypt
+		}
+	}
+
+	content, err = decompress(content)
+	if err != nil {
+		out.Errorf(ctx, "Failed to read %s: %s", name, err)
 
 		return nil, store.ErrDecrypt
 	}
 
+	if !recovered {
+		s.recordBase(ctx, name, ciphertext, key)
+	}
+
 	if !ctxutil.IsShowParsing(ctx) {
 		debug.Log("secrets parsing is disabled. parsing as AKV")
 
-		return secrets.ParseAKV(content), nil
+		sec := secrets.ParseAKV(content)
+		debug.AddSecret(sec.Password())
+
+		return sec, nil
 	}
 
 	debug.Log("secrets parsing is enabled")
 
-	return secparse.Parse(content)
+	sec, err := secparse.Parse(content)
+	if sec != nil {
+		debug.AddSecret(sec.Password())
+	}
+
+	return sec, err
+}
+
+// recoverCorrupted restores an entry from the last commit if the ciphertext in
+// the working tree is truncated or malformed but the committed one can be
+// decrypted. This happens if a write was interrupted, e.g. by a power loss.
+// Other errors, e.g. a missing key, are left alone. The entry is only
+// restored by fsck --decrypt or if the user confirms it.
+func (s *Store) recoverCorrupted(ctx context.Context, name, p string, ciphertext []byte, derr error) ([]byte, bool) {
+	if !isCorrupted(ciphertext, derr) {
+		return nil, false
+	}
+
+	head, err := s.getCiphertextRevision(ctx, p, "HEAD")
+	if err != nil || len(head) == 0 || bytes.Equal(head, ciphertext) {
+		debug.Log("can not recover %s from HEAD: %v", p, err)
+
+		return nil, false
+	}
+
+	content, err := s.crypto.Decrypt(ctx, head)
+	if err != nil {
+		debug.Log("can not decrypt %s at HEAD: %s", p, err)
+
+		return nil, false
+	}
+
+	out.Warningf(ctx, "The ciphertext of %s is corrupted (%d bytes, %d committed), probably by an interrupted write.", name, len(ciphertext), len(head))
+
+	if !IsFsckDecrypt(ctx) {
+		if ok, err := termio.AskForBool(ctx, "Restore the last committed version?", false); err != nil || !ok {
+			out.Noticef(ctx, "Run 'gopass fsck --decrypt' to restore it.")
+
+			return nil, false
+		}
+	}
+
+	if _, err := s.setCiphertext(ctx, p, head); err != nil {
+		out.Errorf(ctx, "Failed to restore %s: %s", name, err)
+
+		return nil, false
+	}
+
+	out.OKf(ctx, "Restored the last committed version of %s", name)
+
+	return content, true
+}
+
+// isCorrupted returns true if the decryption failed because the ciphertext is
+// truncated or can't be parsed.
+func isCorrupted(ciphertext []byte, err error) bool {
+	if len(ciphertext) == 0 || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range []string{"unexpected eof", "unexpected end of file", "truncated", "invalid packet", "malformed", "parse", "parsing"} {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,101 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncatingCrypto fails to decrypt anything shorter than the full ciphertext.
+type truncatingCrypto struct {
+	*plain.Mocker
+}
+
+func (c truncatingCrypto) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if !bytes.HasSuffix(ciphertext, []byte("\nEOF\n")) {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+
+	return c.Mocker.Decrypt(ctx, ciphertext)
+}
+
+// headStorage returns a fixed ciphertext for HEAD.
+type headStorage struct {
+	*fs.Store
+	head []byte
+}
+
+func (h headStorage) GetRevision(ctx context.Context, name, revision string) ([]byte, error) {
+	if h.head == nil {
+		return nil, fmt.Errorf("no commits")
+	}
+
+	return h.head, nil
+}
+
+func TestGetRecoversCorrupted(t *testing.T) {
+	ctx := context.Background()
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	out.Stderr = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	committed := []byte("secret\nEOF\n")
+	tempdir := t.TempDir()
+	st := headStorage{Store: fs.New(tempdir), head: committed}
+
+	s := &Store{
+		path:    tempdir,
+		crypto:  truncatingCrypto{plain.New()},
+		storage: st,
+	}
+
+	require.NoError(t, st.Set(ctx, "foo.txt", committed[:3]))
+
+	// reading never restores the entry on its own.
+	_, err := s.Get(ctxutil.WithAlwaysYes(ctx, true), "foo")
+	assert.ErrorIs(t, err, store.ErrDecrypt)
+	assert.Contains(t, obuf.String(), "corrupted")
+	assert.Contains(t, obuf.String(), "fsck --decrypt")
+
+	buf, err := st.Get(ctx, "foo.txt")
+	require.NoError(t, err)
+	assert.Equal(t, committed[:3], buf)
+
+	// fsck --decrypt does.
+	sec, err := s.Get(WithFsckDecrypt(ctx, true), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", sec.Password())
+
+	buf, err = st.Get(ctx, "foo.txt")
+	require.NoError(t, err)
+	assert.Equal(t, committed, buf)
+
+	t.Run("other errors", func(t *testing.T) {
+		assert.False(t, isCorrupted([]byte("x"), fmt.Errorf("gpg: decryption failed: No secret key")))
+		assert.True(t, isCorrupted([]byte("x"), fmt.Errorf("failed to read header: unexpected EOF")))
+		assert.True(t, isCorrupted(nil, fmt.Errorf("no data")))
+	})
+
+	t.Run("nothing committed", func(t *testing.T) {
+		s.storage = headStorage{Store: st.Store}
+		require.NoError(t, st.Set(ctx, "bar.txt", []byte("sec")))
+
+		_, err := s.Get(WithFsckDecrypt(ctx, true), "bar")
+		assert.ErrorIs(t, err, store.ErrDecrypt)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/recipients"
 	"github.com/gopasspw/gopass/internal/set"
@@ -164,7 +165,15 @@ func (s *Store) AddRecipient(ctx context.Context, id string) error {
 		commitMsg = "Added " + commitMsg
 	}
 
-	return s.reencrypt(ctxutil.WithCommitMessage(ctx, commitMsg))
+	if err := s.reencrypt(ctxutil.WithCommitMessage(ctx, commitMsg)); err != nil {
+		return err
+	}
+
+	if !idAlreadyInStore {
+		s.publish(ctx, event.Event{Type: event.RecipientsChanged, Added: []string{id}})
+	}
+
+	return nil
 }
 
 // SaveRecipients persists the current recipients on disk. Setting ack to true
@@ -195,47 +204,9 @@ func (s *Store) RemoveRecipient(ctx context.Context, key string) error {
 	}
 
 	var removed int
-RECIPIENTS:
-	for _, k := range rs.IDs() { //nolint:whitespace
-
-		// First lets try a simple match of the stored ids
-		if k == key {
-			debug.Log("removing recipient based on id match %s", k)
-			if rs.Remove(k) {
-				removed++
-			}
-
-			continue RECIPIENTS
-		}
-
-		// If we don't match immediately, we may need to loop through the recipient keys to try and match.
-		// To do this though, we need to ensure that we also do a FindRecipients on the id name from the stored ids.
-		recipientIds, err := s.crypto.FindRecipients(ctx, k)
-		if err != nil {
-			out.Warningf(ctx, "Warning: Failed to get GPG Key Info for %s: %s", k, err)
-		}
-		debug.Log("returned the following ids for recipient %s: %s", k, recipientIds)
-
-		// if the key is available locally we can also match the id against
-		// the fingerprint or failing that we can try against the recipientIds
-		if strings.HasSuffix(key, k) {
-			debug.Log("removing recipient based on id suffix match: %s %s", key, k)
-			if rs.Remove(k) {
-				removed++
-			}
-
-			continue RECIPIENTS
-		}
-
-		for _, recipientID := range recipientIds {
-			if recipientID == key {
-				debug.Log("removing recipient based on recipient id match %s", recipientID)
-				if rs.Remove(k) {
-					removed++
-				}
-
-				continue RECIPIENTS
-			}
+	for _, k := range rs.IDs() {
+		if s.isRecipient(ctx, k, key) && rs.Remove(k) {
+			removed++
 		}
 	}
 
@@ -247,7 +218,49 @@ RECIPIENTS:
 		return fmt.Errorf("failed to save recipients: %w", err)
 	}
 
-	return s.reencrypt(ctxutil.WithCommitMessage(ctx, "Removed Recipient "+key))
+	if err := s.reencrypt(ctxutil.WithCommitMessage(ctx, "Removed Recipient "+key)); err != nil {
+		return err
+	}
+
+	s.publish(ctx, event.Event{Type: event.RecipientsChanged, Removed: []string{key}})
+
+	return nil
+}
+
+// isRecipient returns true if the stored recipient id k refers to the given
+// key, e.g. if key is the fingerprint of k. If the key is available locally
+// we can also match the id against the ids returned by FindRecipients.
+func (s *Store) isRecipient(ctx context.Context, k, key string) bool {
+	// First lets try a simple match of the stored ids
+	if k == key {
+		debug.Log("matched recipient based on id match %s", k)
+
+		return true
+	}
+
+	// If we don't match immediately, we may need to loop through the recipient keys to try and match.
+	// To do this though, we need to ensure that we also do a FindRecipients on the id name from the stored ids.
+	recipientIds, err := s.crypto.FindRecipients(ctx, k)
+	if err != nil {
+		out.Warningf(ctx, "Warning: Failed to get GPG Key Info for %s: %s", k, err)
+	}
+	debug.Log("returned the following ids for recipient %s: %s", k, recipientIds)
+
+	if strings.HasSuffix(key, k) {
+		debug.Log("matched recipient based on id suffix match: %s %s", key, k)
+
+		return true
+	}
+
+	for _, recipientID := range recipientIds {
+		if recipientID == key {
+			debug.Log("matched recipient based on recipient id match %s", recipientID)
+
+			return true
+		}
+	}
+
+	return false
 }
 
 func (s *Store) ensureOurKeyID(ctx context.Context, rs []string) []string {
@@ -427,6 +440,12 @@ type recipientMarshaler interface {
 
 // Save all Recipients in memory to the recipients file on disk.
 func (s *Store) saveRecipients(ctx context.Context, rs recipientMarshaler, msg string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if rs == nil {
 		return fmt.Errorf("need valid recipients")
 	}
@@ -0,0 +1,162 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// RedactFile marks a folder as visible but redacted for users that can't
+	// decrypt it. It contains the redaction mode.
+	RedactFile = ".gopass-redact"
+	// RedactNames shows the names of the entries but marks them as not
+	// accessible.
+	RedactNames = "names"
+	// RedactCount only shows the number of entries in the folder.
+	RedactCount = "count"
+)
+
+// RedactModes are the supported redaction modes.
+var RedactModes = []string{RedactNames, RedactCount}
+
+// ErrRedactMode is returned for unknown redaction modes.
+var ErrRedactMode = errors.New("unknown redaction mode")
+
+// Redactions returns the folders marked for redaction that we can not
+// decrypt, mapped to their redaction mode. Folders we are a recipient of are
+// never redacted.
+func (s *Store) Redactions(ctx context.Context) (map[string]string, error) {
+	if s.storage == nil || s.crypto == nil {
+		return nil, nil
+	}
+
+	lst, err := s.storage.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string)
+	for _, fn := range lst {
+		if path.Base(fn) != RedactFile {
+			continue
+		}
+
+		dir := path.Dir(fn)
+		if dir == "." {
+			// redacting the whole store makes no sense, mount it or not.
+			continue
+		}
+
+		if s.canDecrypt(ctx, dir) {
+			continue
+		}
+
+		if s.alias != "" {
+			dir = s.alias + Sep + dir
+		}
+		res[dir] = s.readRedactMode(ctx, fn)
+	}
+
+	return res, nil
+}
+
+// FolderRedaction returns the redaction mode of exactly this folder or an
+// empty string if it isn't redacted.
+func (s *Store) FolderRedaction(ctx context.Context, dir string) string {
+	fn := path.Join(strings.Trim(dir, Sep), RedactFile)
+	if !s.storage.Exists(ctx, fn) {
+		return ""
+	}
+
+	return s.readRedactMode(ctx, fn)
+}
+
+// SetFolderRedaction sets the redaction mode of the folder. An empty mode
+// removes the redaction.
+func (s *Store) SetFolderRedaction(ctx context.Context, dir, mode string) error {
+	dir = strings.Trim(dir, Sep)
+	if dir == "" {
+		return fmt.Errorf("can not redact the root of a store")
+	}
+
+	if mode != "" && !isRedactMode(mode) {
+		return fmt.Errorf("%w %q", ErrRedactMode, mode)
+	}
+
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fn := path.Join(dir, RedactFile)
+	if mode == "" {
+		if !s.storage.Exists(ctx, fn) {
+			return nil
+		}
+
+		if err := s.storage.Delete(ctx, fn); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", fn, err)
+		}
+	} else if err := s.storage.Set(ctx, fn, []byte(mode+"\n")); err != nil {
+		if errors.Is(err, store.ErrMeaninglessWrite) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to write %s: %w", fn, err)
+	}
+
+	return s.commitFiles(ctx, fmt.Sprintf("Set redaction of %s to %q", dir, mode), fn)
+}
+
+func (s *Store) readRedactMode(ctx context.Context, fn string) string {
+	buf, err := s.storage.Get(ctx, fn)
+	if err != nil {
+		debug.Log("failed to read %s: %s", fn, err)
+
+		return RedactNames
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(string(buf)))
+	if !isRedactMode(mode) {
+		debug.Log("unknown redaction mode %q in %s, using %s", mode, fn, RedactNames)
+
+		return RedactNames
+	}
+
+	return mode
+}
+
+// canDecrypt returns true if one of our identities is a recipient of the
+// folder.
+func (s *Store) canDecrypt(ctx context.Context, dir string) bool {
+	rs, err := s.getRecipients(ctx, s.idFile(ctx, path.Join(dir, "x")))
+	if err != nil {
+		debug.Log("failed to read recipients of %s: %s", dir, err)
+	}
+
+	ids := rs.IDs()
+	if len(ids) < 1 {
+		return false
+	}
+
+	kl, err := s.crypto.FindIdentities(ctx, ids...)
+	if err != nil {
+		debug.Log("failed to find identities %q: %s", ids, err)
+
+		return false
+	}
+
+	return len(kl) > 0
+}
+
+func isRedactMode(mode string) bool {
+	return slices.Contains(RedactModes, mode)
+}
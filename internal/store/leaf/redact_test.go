@@ -0,0 +1,55 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactions(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	// we are a recipient of the store but not of ops.
+	require.NoError(t, s.storage.Set(ctx, "ops/"+s.crypto.IDFile(), []byte("0xBADC0FFEE\n")))
+	require.NoError(t, s.storage.Set(ctx, "ops/db.plain", []byte("secret")))
+	require.NoError(t, s.storage.Set(ctx, "team/wifi.plain", []byte("secret")))
+
+	rs, err := s.Redactions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+
+	require.NoError(t, s.SetFolderRedaction(ctx, "ops/", RedactCount))
+	require.NoError(t, s.SetFolderRedaction(ctx, "team", RedactNames))
+	assert.Equal(t, RedactCount, s.FolderRedaction(ctx, "ops"))
+	assert.Equal(t, RedactNames, s.FolderRedaction(ctx, "team"))
+	assert.Equal(t, "", s.FolderRedaction(ctx, "other"))
+
+	rs, err = s.Redactions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ops": RedactCount}, rs)
+
+	t.Run("unknown modes", func(t *testing.T) {
+		assert.ErrorIs(t, s.SetFolderRedaction(ctx, "ops", "hidden"), ErrRedactMode)
+		assert.Error(t, s.SetFolderRedaction(ctx, "", RedactNames))
+
+		// a broken file falls back to names.
+		require.NoError(t, s.storage.Set(ctx, "ops/"+RedactFile, []byte("hidden\n")))
+		assert.Equal(t, RedactNames, s.FolderRedaction(ctx, "ops"))
+	})
+
+	t.Run("remove redaction", func(t *testing.T) {
+		require.NoError(t, s.SetFolderRedaction(ctx, "ops", ""))
+		require.NoError(t, s.SetFolderRedaction(ctx, "ops", ""))
+
+		rs, err := s.Redactions(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, rs)
+	})
+}
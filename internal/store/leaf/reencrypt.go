@@ -10,29 +10,87 @@ import (
 	"sync"
 
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/journal"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/termio"
 )
 
+// Reencrypt re-encrypts all entries for the current recipients.
+func (s *Store) Reencrypt(ctx context.Context) error {
+	return s.reencrypt(ctxutil.WithCommitMessage(ctx, "Re-encrypted all entries"))
+}
+
 // nolint:ifshort
 // reencrypt will re-encrypt all entries for the current recipients.
 func (s *Store) reencrypt(ctx context.Context) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	entries, err := s.List(ctx, "")
 	if err != nil {
 		return fmt.Errorf("failed to list store: %w", err)
 	}
 
+	items := make([]journal.Item, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, journal.Item{Name: strings.TrimPrefix(e, s.alias)})
+	}
+
+	j, err := journal.Begin(s.path, journal.OpReencrypt, false, items)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reencryptEntries(ctx, j, entries, nil); err != nil {
+		return err
+	}
+
+	if err := j.Finish(); err != nil {
+		return err
+	}
+
+	return s.reencryptGitPush(ctx)
+}
+
+// reencryptEntries re-encrypts the given entries, records the progress in the
+// journal and commits the result. If update is not nil it's applied to each
+// secret before writing it.
+func (s *Store) reencryptEntries(ctx context.Context, j *journal.Journal, entries []string, update func(gopass.Secret) error) error {
 	// Most gnupg setups don't work well with concurrency > 1, but
 	// for other backends - e.g. age - this could very well be > 1.
 	conc := s.crypto.Concurrency()
 
+	// the entries of a packed store are written once all are done. Until
+	// then they must not be marked as done in the journal.
+	flush, batched := s.batch()
+	var doneMu sync.Mutex
+	var done []string
+	markDone := func() error {
+		if err := flush(ctx); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to write bundles: %w", err)
+		}
+
+		for _, e := range done {
+			if err := j.Done(e); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	// save original value of auto push
 	{
 		// shadow ctx in this block only
-		ctx := ctxutil.WithGitCommit(ctx, false)
+		// re-encrypting doesn't change the content, so it needs no review.
+		ctx := WithNoReview(ctxutil.WithGitCommit(ctx, false), true)
 
 		// progress bar
 		bar := termio.NewProgressBar(int64(len(entries)))
@@ -55,6 +113,11 @@ func (s *Store) reencrypt(ctx context.Context) error {
 
 						continue
 					}
+					if update != nil {
+						if err := update(content); err != nil {
+							logger.Printf("Worker %d: Failed to update %s: %s\n", workerId, e, err)
+						}
+					}
 					if err := s.Set(WithNoGitOps(ctx, conc > 1), e, content); err != nil {
 						if !errors.Is(err, store.ErrMeaninglessWrite) {
 							logger.Printf("Worker %d: Failed to write %s: %s\n", workerId, e, err)
@@ -63,6 +126,16 @@ func (s *Store) reencrypt(ctx context.Context) error {
 						}
 						logger.Printf("Worker %d: Writing secret %s is not needed\n", workerId, e)
 					}
+					if batched {
+						doneMu.Lock()
+						done = append(done, e)
+						doneMu.Unlock()
+
+						continue
+					}
+					if err := j.Done(e); err != nil {
+						logger.Printf("Worker %d: Failed to update journal for %s: %s\n", workerId, e, err)
+					}
 				}
 				wg.Done() // report the job as finished
 			}(i)
@@ -77,7 +150,12 @@ func (s *Store) reencrypt(ctx context.Context) error {
 				// we wait for all workers to have finished
 				wg.Wait()
 
-				return fmt.Errorf("context canceled")
+				if err := markDone(); err != nil {
+					return err
+				}
+
+				// the journal is kept, so fsck can complete the operation.
+				return fmt.Errorf("re-encryption was interrupted: %w. Run 'gopass fsck' to complete it", ctx.Err())
 			default:
 			}
 
@@ -95,6 +173,10 @@ func (s *Store) reencrypt(ctx context.Context) error {
 		bar.Done()
 	}
 
+	if err := markDone(); err != nil {
+		return err
+	}
+
 	// if we were working concurrently, we couldn't git add during the process
 	// to avoid a race condition on git .index.lock file, so we do it now.
 	if conc > 1 {
@@ -125,7 +207,7 @@ func (s *Store) reencrypt(ctx context.Context) error {
 		}
 	}
 
-	return s.reencryptGitPush(ctx)
+	return nil
 }
 
 func (s *Store) reencryptGitPush(ctx context.Context) error {
@@ -0,0 +1,170 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
+	"github.com/gopasspw/gopass/internal/journal"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// Revocation describes the effect of removing a recipient from a store.
+type Revocation struct {
+	// IDFiles are the recipient files the recipient was removed from.
+	IDFiles []string
+	// Exposed are the secrets the recipient could decrypt. They have been
+	// re-encrypted but the recipient might have a copy of them.
+	Exposed []string
+}
+
+// RevokeRecipient removes the given recipient from all recipient files of
+// the store, including those of sub folders, and re-encrypts the secrets
+// that were readable by the recipient. If update is not nil it's applied to
+// these secrets before they are written. The tag that update adds is recorded
+// in the journal, so an interrupted revocation can be completed with the same
+// update. It returns nil if the recipient had no access to this store.
+func (s *Store) RevokeRecipient(ctx context.Context, key, tag string, update func(gopass.Secret) error) (*Revocation, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	changed, err := s.revokeFromIDFiles(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changed) < 1 {
+		debug.Log("%s is no recipient of %s", key, s.alias)
+
+		return nil, nil
+	}
+
+	rev := &Revocation{}
+	for idf := range changed {
+		rev.IDFiles = append(rev.IDFiles, idf)
+	}
+	sort.Strings(rev.IDFiles)
+
+	entries, err := s.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store: %w", err)
+	}
+
+	items := make([]journal.Item, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimPrefix(e, s.alias+Sep)
+		if _, found := changed[s.idFile(ctx, name)]; !found {
+			continue
+		}
+
+		rev.Exposed = append(rev.Exposed, e)
+		items = append(items, journal.Item{Name: strings.TrimPrefix(e, s.alias)})
+	}
+
+	j, err := journal.Begin(s.path, journal.OpReencrypt, false, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.SetTag(tag); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveRevokedIDFiles(ctx, changed, key); err != nil {
+		return nil, err
+	}
+
+	out.Printf(ctx, "Re-encrypting %d secrets. This may take some time ...", len(rev.Exposed))
+
+	if err := s.reencryptEntries(ctxutil.WithCommitMessage(ctx, "Revoked recipient "+key), j, rev.Exposed, update); err != nil {
+		return nil, err
+	}
+
+	if err := j.Finish(); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, event.Event{Type: event.RecipientsChanged, Removed: []string{key}})
+
+	return rev, s.reencryptGitPush(ctx)
+}
+
+// revokeFromIDFiles returns the recipient files that contain the key, with the
+// key already removed.
+func (s *Store) revokeFromIDFiles(ctx context.Context, key string) (map[string]*recipients.Recipients, error) {
+	idfs := append(s.idFiles(ctx), s.idFile(ctx, ""))
+	changed := make(map[string]*recipients.Recipients, len(idfs))
+
+	for _, idf := range idfs {
+		if _, found := changed[idf]; found {
+			continue
+		}
+
+		buf, err := s.storage.Get(ctx, idf)
+		if err != nil {
+			debug.Log("failed to read %s: %s", idf, err)
+
+			continue
+		}
+
+		rs := recipients.Unmarshal(buf)
+
+		var removed bool
+		for _, k := range rs.IDs() {
+			if s.isRecipient(ctx, k, key) && rs.Remove(k) {
+				removed = true
+			}
+		}
+
+		if !removed {
+			continue
+		}
+
+		if len(rs.IDs()) < 1 {
+			return nil, fmt.Errorf("can not remove the last recipient from %s", idf)
+		}
+
+		changed[idf] = rs
+	}
+
+	return changed, nil
+}
+
+// saveRevokedIDFiles writes and commits the changed recipient files.
+func (s *Store) saveRevokedIDFiles(ctx context.Context, changed map[string]*recipients.Recipients, key string) error {
+	for idf, rs := range changed {
+		if err := s.storage.Set(ctx, idf, rs.Marshal()); err != nil {
+			return fmt.Errorf("failed to write recipients file %s: %w", idf, err)
+		}
+
+		if err := s.storage.Add(ctx, idf); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to add file %q to git: %w", idf, err)
+		}
+
+		if idf == s.idFile(ctx, "") {
+			if err := config.FromContext(ctx).Set("", s.rhKey(), rs.Hash()); err != nil {
+				out.Errorf(ctx, "Failed to update %s: %s", s.rhKey(), err)
+			}
+		}
+	}
+
+	if err := s.storage.Commit(ctx, "Revoked recipient "+key); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) && !errors.Is(err, store.ErrGitNothingToCommit) {
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	return nil
+}
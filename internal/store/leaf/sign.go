@@ -0,0 +1,93 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// signer returns the crypto backend if it can sign.
+func (s *Store) signer() (backend.Signer, error) {
+	sg, ok := s.crypto.(backend.Signer)
+	if !ok {
+		return nil, fmt.Errorf("crypto backend %s can not sign: %w", s.crypto.Name(), backend.ErrNotSupported)
+	}
+
+	return sg, nil
+}
+
+// ownRecipients returns the recipients of the store the user has the private
+// key of.
+func (s *Store) ownRecipients(ctx context.Context, ids []string) []string {
+	var ours []string
+	for _, r := range ids {
+		if found, err := s.crypto.FindIdentities(ctx, r); err == nil && len(found) > 0 {
+			ours = append(ours, r)
+		}
+	}
+
+	return ours
+}
+
+// signAs signs msg with the key of the first of the given recipients that
+// can sign. It returns that recipient.
+func (s *Store) signAs(ctx context.Context, ids []string, msg []byte) (string, []byte, error) {
+	sg, err := s.signer()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(ids) < 1 {
+		return "", nil, fmt.Errorf("none of the recipients of this store is one of your identities")
+	}
+
+	for _, id := range ids {
+		sig, err := sg.Sign(ctx, id, msg)
+		if errors.Is(err, backend.ErrNotSupported) {
+			debug.Log("%s can not sign: %s", id, err)
+
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to sign as %s: %w", id, err)
+		}
+
+		return id, sig, nil
+	}
+
+	return "", nil, fmt.Errorf("none of your identities can sign: %w", backend.ErrNotSupported)
+}
+
+// notary signs with one of the user's recipients of the store and only
+// accepts signatures made by recipients of the store.
+type notary struct {
+	s *Store
+}
+
+// Sign implements backend.Notary.
+func (n notary) Sign(ctx context.Context, msg []byte) (string, []byte, error) {
+	rs, err := n.s.GetRecipients(ctx, "")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read recipients: %w", err)
+	}
+
+	return n.s.signAs(ctx, n.s.ownRecipients(ctx, rs.IDs()), msg)
+}
+
+// Verify implements backend.Notary.
+func (n notary) Verify(ctx context.Context, msg, sig []byte) (string, error) {
+	sg, err := n.s.signer()
+	if err != nil {
+		return "", err
+	}
+
+	rs, err := n.s.GetRecipients(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to read recipients: %w", err)
+	}
+
+	return sg.Verify(ctx, msg, sig, rs.IDs())
+}
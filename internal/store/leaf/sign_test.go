@@ -0,0 +1,42 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotary(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	s, err := createSubStore(t)
+	require.NoError(t, err)
+
+	rs, err := s.GetRecipients(ctx, "")
+	require.NoError(t, err)
+
+	n := notary{s: s}
+	id, sig, err := n.Sign(ctx, []byte("foo"))
+	require.NoError(t, err)
+	assert.Contains(t, rs.IDs(), id)
+
+	signer, err := n.Verify(ctx, []byte("foo"), sig)
+	require.NoError(t, err)
+	assert.Equal(t, id, signer)
+
+	_, err = n.Verify(ctx, []byte("bar"), sig)
+	assert.ErrorIs(t, err, backend.ErrBadSignature)
+
+	// signatures by anyone but the recipients are rejected.
+	sg, err := s.signer()
+	require.NoError(t, err)
+	sig, err = sg.Sign(ctx, "0xC0FFEE", []byte("foo"))
+	require.NoError(t, err)
+	_, err = n.Verify(ctx, []byte("foo"), sig)
+	assert.ErrorIs(t, err, backend.ErrBadSignature)
+}
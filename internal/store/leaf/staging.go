@@ -0,0 +1,103 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// stager is implemented by storage backends that can hold back pushes to
+// protected stores until they are approved.
+type stager interface {
+	SetProtected(n backend.Notary)
+	Staged() string
+	ListStaged(ctx context.Context, remote string, n backend.Notary) ([]backend.Staged, error)
+	ApproveStaged(ctx context.Context, remote, id string, required int, n backend.Notary) (*backend.Staged, bool, error)
+}
+
+func (s *Store) stager() (stager, error) {
+	st, ok := local.Unwrap(s.storage).(stager)
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s does not support protected stores: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+
+	return st, nil
+}
+
+// IsProtected returns true if pushes to the store need to be approved.
+func (s *Store) IsProtected(ctx context.Context) bool {
+	return config.FromContext(ctx).GetM(s.alias, "core.protected") == "true"
+}
+
+// RequiredApprovals returns the number of approvals a push to a protected
+// store needs.
+func (s *Store) RequiredApprovals(ctx context.Context) int {
+	n, err := strconv.Atoi(config.FromContext(ctx).GetM(s.alias, "core.approvals"))
+	if err != nil || n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+// initProtection makes the storage backend stage all pushes if the store is
+// protected.
+func (s *Store) initProtection(ctx context.Context) {
+	if !s.IsProtected(ctx) {
+		return
+	}
+
+	st, err := s.stager()
+	if err != nil {
+		debug.Log("store %s is protected but can not stage pushes: %s", s.alias, err)
+
+		return
+	}
+
+	debug.Log("store %s is protected, staging all pushes", s.alias)
+	st.SetProtected(notary{s: s})
+}
+
+// Staged returns the commit staged by the last push or an empty string if
+// nothing was staged.
+func (s *Store) Staged() string {
+	st, err := s.stager()
+	if err != nil {
+		return ""
+	}
+
+	return st.Staged()
+}
+
+// ListStaged returns the pushes to this store waiting for approval.
+func (s *Store) ListStaged(ctx context.Context) ([]backend.Staged, error) {
+	st, err := s.stager()
+	if err != nil {
+		return nil, err
+	}
+
+	return st.ListStaged(ctx, "", notary{s: s})
+}
+
+// ApproveStaged approves the staged push with the given ID. Once it has
+// enough approvals it's merged. It returns the push and whether it was
+// merged.
+func (s *Store) ApproveStaged(ctx context.Context, id string) (*backend.Staged, bool, error) {
+	st, err := s.stager()
+	if err != nil {
+		return nil, false, err
+	}
+
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	return st.ApproveStaged(ctx, "", id, s.RequiredApprovals(ctx), notary{s: s})
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/storage/local"
+	"github.com/gopasspw/gopass/internal/backend/storage/pack"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 func (s *Store) initStorageBackend(ctx context.Context) error {
@@ -16,6 +19,20 @@ func (s *Store) initStorageBackend(ctx context.Context) error {
 		return fmt.Errorf("unknown storage backend: %w", err)
 	}
 
+	if pack.Enabled(ctx, store) {
+		debug.Log("using pack layout for %s", s.path)
+		store = pack.New(store)
+	}
+
+	if dir := local.Dir(s.alias, s.path); local.Enabled(dir) {
+		debug.Log("using local-only entries in %s for %s", dir, s.path)
+		ls, err := local.New(store, dir)
+		if err != nil {
+			return err
+		}
+		store = ls
+	}
+
 	s.storage = store
 
 	return nil
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/fips"
+	"github.com/gopasspw/gopass/internal/lock"
 	"github.com/gopasspw/gopass/internal/set"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -17,6 +21,21 @@ type Store struct {
 	path    string
 	crypto  backend.Crypto
 	storage backend.Storage
+
+	lock     *lock.Lock
+	lockOnce sync.Once
+
+	// events are published once the lock is released. See publish.
+	events   []queuedEvent
+	eventsMu sync.Mutex
+
+	// bases are the entries read by this process. See checkBase.
+	bases   map[string]readBase
+	basesMu sync.Mutex
+
+	// token unwraps entries protected by a FIDO2 token. The token
+	// configured in fido2.device is used if nil.
+	token fido2.Authenticator
 }
 
 // Init initializes this sub store.
@@ -41,6 +60,12 @@ func Init(ctx context.Context, alias, path string) (*Store, error) {
 		return nil, fmt.Errorf("failed to initialize crypto for %s at %s: %w", alias, path, err)
 	}
 
+	if fips.Enabled(ctx) {
+		if err := fips.CheckBackend(crypto.Name()); err != nil {
+			return nil, fmt.Errorf("can not initialize %s: %w", path, err)
+		}
+	}
+
 	s.crypto = crypto
 	debug.Log("Crypto for %q => %q initialized as %s", alias, path, crypto.Name())
 
@@ -63,6 +88,8 @@ func New(ctx context.Context, alias, path string) (*Store, error) {
 
 	debug.Log("Storage for %s => %s initialized as %v", alias, path, s.storage)
 
+	s.initProtection(ctx)
+
 	// init crypto backend
 	if err := s.initCryptoBackend(ctx); err != nil {
 		return nil, fmt.Errorf("failed to init crypto backend: %w", err)
@@ -70,6 +97,12 @@ func New(ctx context.Context, alias, path string) (*Store, error) {
 
 	debug.Log("Crypto for %s => %s initialized as %v", alias, path, s.crypto)
 
+	if fips.Enabled(ctx) {
+		if err := fips.CheckBackend(s.crypto.Name()); err != nil {
+			return nil, fmt.Errorf("store %s requires a disallowed algorithm: %w", path, err)
+		}
+	}
+
 	return s, nil
 }
 
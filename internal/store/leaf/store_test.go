@@ -12,6 +12,8 @@ import (
 	_ "github.com/gopasspw/gopass/internal/backend/crypto"
 	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
 	_ "github.com/gopasspw/gopass/internal/backend/storage"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/fips"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -187,3 +189,16 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFIPS(t *testing.T) {
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.fips", "true"))
+	ctx := cfg.WithConfig(context.Background())
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+
+	tempdir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempdir, plain.IDFile), []byte("foobar"), 0o600))
+
+	_, err := New(ctx, "", tempdir)
+	assert.ErrorIs(t, err, fips.ErrNotApproved)
+}
@@ -113,6 +113,12 @@ func (s *Store) GetTemplate(ctx context.Context, name string) ([]byte, error) {
 
 // SetTemplate will (over)write the content to the template file.
 func (s *Store) SetTemplate(ctx context.Context, name string, content []byte) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	p := s.templatefile(name)
 
 	if err := s.storage.Set(ctx, p, content); err != nil {
@@ -140,6 +146,12 @@ func (s *Store) SetTemplate(ctx context.Context, name string, content []byte) er
 
 // RemoveTemplate will delete the named template if it exists.
 func (s *Store) RemoveTemplate(ctx context.Context, name string) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	p := s.templatefile(name)
 
 	if err := s.storage.Delete(ctx, p); err != nil {
@@ -0,0 +1,237 @@
+package leaf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// tombstoneDir holds a record for every deleted entry. Without them a delete
+// on one device is undone when another device that still has an old copy of
+// the entry merges its changes. There is one file per entry, so concurrent
+// deletes on several devices never conflict.
+const tombstoneDir = ".gopass-tombstones"
+
+// defaultTombstoneTTL is the number of days tombstones are kept if
+// core.tombstonettl is not set. All devices should have synced by then.
+const defaultTombstoneTTL = 90
+
+// tombstone records the deletion of an entry.
+type tombstone struct {
+	Deleted time.Time `json:"deleted"`
+	Host    string    `json:"host,omitempty"`
+}
+
+func tombstonePath(name string) string {
+	return path.Join(tombstoneDir, strings.TrimPrefix(name, "/")+".json")
+}
+
+// writeTombstones records the deletion of the given entries.
+func (s *Store) writeTombstones(ctx context.Context, names ...string) error {
+	host, _ := os.Hostname()
+
+	buf, err := json.Marshal(tombstone{
+		Deleted: time.Now().UTC().Truncate(time.Second),
+		Host:    host,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		p := tombstonePath(name)
+		if err := s.storage.Set(ctx, p, buf); err != nil {
+			return fmt.Errorf("failed to write tombstone for %s: %w", name, err)
+		}
+
+		if err := s.stageTombstone(ctx, p); err != nil {
+			return err
+		}
+
+		debug.Log("wrote tombstone for %s", name)
+	}
+
+	return nil
+}
+
+// removeTombstone removes the tombstone of an entry that is written again.
+func (s *Store) removeTombstone(ctx context.Context, name string) error {
+	p := tombstonePath(name)
+	if !s.storage.Exists(ctx, p) {
+		return nil
+	}
+
+	if err := s.storage.Delete(ctx, p); err != nil {
+		return fmt.Errorf("failed to remove tombstone of %s: %w", name, err)
+	}
+
+	debug.Log("removed tombstone of %s", name)
+
+	return s.stageTombstone(ctx, p)
+}
+
+func (s *Store) stageTombstone(ctx context.Context, p string) error {
+	if IsNoGitOps(ctx) {
+		return nil
+	}
+
+	if err := s.storage.Add(ctx, p); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+		return fmt.Errorf("failed to add %q to git: %w", p, err)
+	}
+
+	return nil
+}
+
+// Tombstones returns the deletion time of every entry with a tombstone.
+func (s *Store) Tombstones(ctx context.Context) (map[string]time.Time, error) {
+	files, err := s.storage.List(ctx, tombstoneDir+Sep)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := make(map[string]time.Time, len(files))
+	for _, fn := range files {
+		if !strings.HasPrefix(fn, tombstoneDir+Sep) || !strings.HasSuffix(fn, ".json") {
+			continue
+		}
+
+		buf, err := s.storage.Get(ctx, fn)
+		if err != nil {
+			debug.Log("failed to read %s: %s", fn, err)
+
+			continue
+		}
+
+		var t tombstone
+		if err := json.Unmarshal(buf, &t); err != nil {
+			debug.Log("skipping invalid tombstone %s: %s", fn, err)
+
+			continue
+		}
+
+		ts[strings.TrimSuffix(strings.TrimPrefix(fn, tombstoneDir+Sep), ".json")] = t.Deleted
+	}
+
+	return ts, nil
+}
+
+// ApplyTombstones deletes entries that were resurrected by merging the
+// changes of another device. Entries that were changed after they had been
+// deleted are kept and their tombstones are removed instead. It returns the
+// deleted entries.
+func (s *Store) ApplyTombstones(ctx context.Context) ([]string, error) {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ts, err := s.Tombstones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+
+	changed := false
+	for name, when := range ts {
+		p := s.Passfile(name)
+		if !s.storage.Exists(ctx, p) {
+			continue
+		}
+
+		revs, err := s.storage.Revisions(ctx, p)
+		if err != nil || len(revs) < 1 || revs[0].Hash == "latest" {
+			debug.Log("can not tell when %s was changed. Leaving it alone: %v", name, err)
+
+			continue
+		}
+
+		if revs[0].Date.After(when) {
+			out.Noticef(ctx, "%s was changed after it had been deleted. Keeping it", s.alias+Sep+name)
+
+			if err := s.removeTombstone(ctx, name); err != nil {
+				return deleted, err
+			}
+			changed = true
+
+			continue
+		}
+
+		if err := s.deleteSingle(ctx, p); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, name)
+		changed = true
+	}
+
+	sort.Strings(deleted)
+
+	if !changed {
+		return nil, nil
+	}
+
+	return deleted, s.commitTombstones(ctx, fmt.Sprintf("Applied tombstones. Removed %d resurrected entries", len(deleted)))
+}
+
+// expireTombstones removes tombstones older than core.tombstonettl days.
+// Negative values keep tombstones forever.
+func (s *Store) expireTombstones(ctx context.Context) (int, error) {
+	ttl := config.Int(ctx, "core.tombstonettl")
+	if ttl == 0 {
+		ttl = defaultTombstoneTTL
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	ts, err := s.Tombstones(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-time.Duration(ttl) * 24 * time.Hour)
+
+	var n int
+
+	for name, when := range ts {
+		if when.After(cutoff) {
+			continue
+		}
+
+		if err := s.removeTombstone(ctx, name); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// commitTombstones commits the staged tombstone changes.
+func (s *Store) commitTombstones(ctx context.Context, msg string) error {
+	if err := s.storage.Commit(ctx, msg); err != nil {
+		switch {
+		case errors.Is(err, store.ErrGitNotInit), errors.Is(err, store.ErrGitNothingToCommit):
+			return nil
+		default:
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	return nil
+}
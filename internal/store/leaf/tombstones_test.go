@@ -0,0 +1,119 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revStorage reports a fixed modification time for every entry.
+type revStorage struct {
+	*fs.Store
+	changed time.Time
+}
+
+func (r *revStorage) Revisions(ctx context.Context, name string) ([]backend.Revision, error) {
+	return []backend.Revision{{Hash: "abcdef", Date: r.changed}}, nil
+}
+
+func TestTombstones(t *testing.T) {
+	ctx := context.Background()
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	out.Stderr = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	tempdir := t.TempDir()
+	_, _, err := createStore(tempdir, nil, []string{})
+	require.NoError(t, err)
+	t.Setenv("GOPASS_HOMEDIR", tempdir)
+
+	st := &revStorage{Store: fs.New(tempdir)}
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: st,
+	}
+
+	sec := secrets.NewAKV()
+	sec.SetPassword("foo")
+
+	// deleting leaves a tombstone, writing again removes it.
+	require.NoError(t, s.Set(ctx, "foo", sec))
+	require.NoError(t, s.Delete(ctx, "foo"))
+	ts, err := s.Tombstones(ctx)
+	require.NoError(t, err)
+	require.Contains(t, ts, "foo")
+	deleted := ts["foo"]
+
+	require.NoError(t, s.Set(ctx, "foo", sec))
+	ts, err = s.Tombstones(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, ts, "foo")
+
+	t.Run("resurrected", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, "foo"))
+		require.NoError(t, st.Set(ctx, "foo.txt", sec.Bytes()))
+		st.changed = deleted.Add(-time.Hour)
+
+		names, err := s.ApplyTombstones(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo"}, names)
+		assert.False(t, s.Exists(ctx, "foo"))
+	})
+
+	t.Run("changed after the delete", func(t *testing.T) {
+		require.NoError(t, st.Set(ctx, "foo.txt", sec.Bytes()))
+		st.changed = time.Now().Add(time.Hour)
+
+		names, err := s.ApplyTombstones(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, names)
+		assert.True(t, s.Exists(ctx, "foo"))
+
+		ts, err := s.Tombstones(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, ts, "foo")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		require.NoError(t, s.Set(ctx, "dir/a", sec))
+		require.NoError(t, s.Set(ctx, "dir/sub/b", sec))
+		require.NoError(t, s.Set(ctx, "dirty", sec))
+		require.NoError(t, s.Prune(ctx, "dir"))
+
+		ts, err := s.Tombstones(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, ts, "dir/a")
+		assert.Contains(t, ts, "dir/sub/b")
+		assert.NotContains(t, ts, "dirty")
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		require.NoError(t, st.Set(ctx, tombstonePath("old"), []byte(`{"deleted":"2001-01-01T00:00:00Z"}`)))
+
+		n, err := s.expireTombstones(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		ts, err := s.Tombstones(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, ts, "old")
+		assert.Contains(t, ts, "dir/a")
+	})
+}
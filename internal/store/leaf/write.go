@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
 	"github.com/gopasspw/gopass/internal/store"
@@ -17,6 +18,12 @@ import (
 
 // Set encodes and writes the cipertext of one entry to disk.
 func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if strings.Contains(name, "//") {
 		return fmt.Errorf("invalid secret name: %s", name)
 	}
@@ -25,8 +32,24 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 		return fmt.Errorf("writing to %s is disabled by `core.readonly`.", s.alias)
 	}
 
+	if err := s.checkPinnedWrite(ctx, name, sec.Bytes()); err != nil {
+		return err
+	}
+
 	p := s.Passfile(name)
 
+	sec, err = s.checkBase(ctx, name, p, sec)
+	if err != nil {
+		return err
+	}
+
+	content := sec.Bytes()
+
+	wrapped, key, err := s.wrapProtected(ctx, name, p, s.compress(ctx, name, content))
+	if err != nil {
+		return err
+	}
+
 	recipients, err := s.useableKeys(ctx, name)
 	if err != nil {
 		return fmt.Errorf("failed to list useable keys for %q: %w", p, err)
@@ -35,16 +58,38 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 	// make sure the encryptor can decrypt later
 	recipients = s.ensureOurKeyID(ctx, recipients)
 
-	ciphertext, err := s.crypto.Encrypt(ctx, sec.Bytes(), recipients)
+	out.Verbosef(ctx, "Encrypting %s for %d recipients", name, len(recipients))
+
+	span := debug.Start("encrypting %s for %d recipients", name, len(recipients))
+	ciphertext, err := s.crypto.Encrypt(ctx, wrapped, recipients)
+	span.End()
 	if err != nil {
 		debug.Log("Failed encrypt secret: %s", err)
 
 		return store.ErrEncrypt
 	}
 
-	if err := s.storage.Set(ctx, p, ciphertext); err != nil {
+	// don't touch the store if the command was interrupted while encrypting.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if owners, ok := s.needsReview(ctx, name); ok {
+		return s.propose(ctx, OpSet, name, owners, ciphertext)
+	}
+
+	files, err := s.setCiphertext(ctx, p, ciphertext)
+	if err != nil {
 		return fmt.Errorf("failed to write secret: %w", err)
 	}
+	s.recordBase(ctx, name, ciphertext, key)
+	s.advanceBase(name, ciphertext)
+
+	if err := s.removeTombstone(ctx, name); err != nil {
+		return err
+	}
+
+	s.publish(ctx, event.Event{Type: event.EntryWritten, Entries: []string{name}})
 
 	// It is not possible to perform concurrent git add and git commit commands
 	// so we need to skip this step when using concurrency and perform them
@@ -55,7 +100,7 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 		return nil
 	}
 
-	if err := s.storage.Add(ctx, p); err != nil {
+	if err := s.storage.Add(ctx, files...); err != nil {
 		if errors.Is(err, store.ErrGitNotInit) {
 			return nil
 		}
@@ -79,6 +124,13 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 }
 
 func (s *Store) gitCommitAndPush(ctx context.Context, name string) error {
+	// commits might be run from the background queue after Set returned.
+	release, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if err := s.storage.Commit(ctx, fmt.Sprintf("Save secret to %s: %s", name, ctxutil.GetCommitMessage(ctx))); err != nil {
 		switch {
 		case errors.Is(err, store.ErrGitNotInit):
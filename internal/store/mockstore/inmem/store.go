@@ -207,6 +207,11 @@ func (m *InMem) GetRevision(context.Context, string, string) ([]byte, error) {
 	return []byte("foo\nbar"), nil
 }
 
+// Head is not supported.
+func (m *InMem) Head(context.Context) (string, error) {
+	return "", backend.ErrNotSupported
+}
+
 // Status is not implemented.
 func (m *InMem) Status(context.Context) ([]byte, error) {
 	return []byte(""), nil
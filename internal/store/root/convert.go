@@ -25,10 +25,10 @@ func (r *Store) Convert(ctx context.Context, name string, cryptoBe backend.Crypt
 	if name == "" {
 		debug.Log("success. updating root path to %s", sub.Path())
 
-		return r.cfg.Set("", "mounts.path", sub.Path())
+		return r.cfg.SetPath(sub.Path())
 	}
 
 	debug.Log("success. updating path for %s to %s", name, sub.Path())
 
-	return r.cfg.Set("", "mounts."+name+".path", sub.Path())
+	return r.cfg.SetMountPath(name, sub.Path())
 }
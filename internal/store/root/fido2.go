@@ -0,0 +1,18 @@
+package root
+
+import "context"
+
+// Protect makes revealing the entry require a tap on the FIDO2 token of the
+// credential.
+func (r *Store) Protect(ctx context.Context, name string, credential []byte) error {
+	sub, name := r.getStore(name)
+
+	return sub.Protect(ctx, name, credential)
+}
+
+// Unprotect removes the FIDO2 protection of the entry.
+func (r *Store) Unprotect(ctx context.Context, name string) error {
+	sub, name := r.getStore(name)
+
+	return sub.Unprotect(ctx, name)
+}
@@ -13,6 +13,11 @@ import (
 func (s *Store) Fsck(ctx context.Context, path string) error {
 	var result []error
 
+	if err := s.recover(ctx); err != nil {
+		out.Errorf(ctx, "Failed to recover interrupted operations: %s", err)
+		result = append(result, err)
+	}
+
 	for alias, sub := range s.mounts {
 		if sub == nil {
 			continue
@@ -0,0 +1,118 @@
+package root
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/internal/canary"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// IconKey is the key holding the icon of an entry or of a folder (in its
+// folder note).
+const IconKey = "icon"
+
+// Icon returns the icon of the given secret.
+func Icon(sec gopass.Secret) string {
+	icon, _ := sec.Get(IconKey)
+
+	return strings.TrimSpace(icon)
+}
+
+// iconCacheTTL is how long the icon of an unchanged entry is cached.
+var iconCacheTTL = 90 * 24 * time.Hour
+
+// The icon cache maps the hash of the file of an entry or folder note to its
+// icon, so listing with icons only decrypts entries that changed since they
+// were listed last. Like the key index it only contains metadata and is
+// disabled by core.completionindex.
+func iconCache() (*cache.OnDisk, error) {
+	return cache.NewOnDisk("icons", iconCacheTTL)
+}
+
+// Icons returns the icons of all entries and folders in the given tree,
+// keyed by their path. Folders have a trailing separator. Since icons are
+// stored inside the encrypted entries, entries that are not in the icon cache
+// have to be decrypted.
+func (r *Store) Icons(ctx context.Context, t *tree.Root) map[string]string {
+	// only the icons are looked at, so this must not trigger canaries.
+	ctx = canary.WithSuppressed(ctx, true)
+
+	var ic *cache.OnDisk
+	if r.nameIndexEnabled() {
+		var err error
+		if ic, err = iconCache(); err != nil {
+			debug.Log("failed to open icon cache: %s", err)
+		}
+	}
+
+	icons := make(map[string]string, t.Len())
+
+	for _, e := range t.List(tree.INF) {
+		if icon := r.icon(ctx, ic, e); icon != "" {
+			icons[e] = icon
+		}
+	}
+
+	for _, d := range t.ListFolders(tree.INF) {
+		if !r.HasInfo(ctx, d) {
+			continue
+		}
+
+		if icon := r.icon(ctx, ic, path.Join(d, leaf.InfoFile)); icon != "" {
+			icons[d] = icon
+		}
+	}
+
+	return icons
+}
+
+// icon returns the icon of the entry or folder note name, decrypting it if
+// it's not in the cache.
+func (r *Store) icon(ctx context.Context, ic *cache.OnDisk, name string) string {
+	key := r.iconCacheKey(ctx, name)
+	if ic != nil && key != "" {
+		if v, err := ic.Get(key); err == nil {
+			return strings.Join(v, "")
+		}
+	}
+
+	sec, err := r.Get(ctx, name)
+	if err != nil {
+		debug.Log("failed to decrypt %s: %s", name, err)
+
+		return ""
+	}
+
+	icon := Icon(sec)
+	if ic != nil && key != "" {
+		if err := ic.Set(key, []string{icon}); err != nil {
+			debug.Log("failed to cache icon of %s: %s", name, err)
+		}
+	}
+
+	return icon
+}
+
+// iconCacheKey identifies the current version of an entry by the hash of its
+// file. It's empty if the file can't be read.
+func (r *Store) iconCacheKey(ctx context.Context, name string) string {
+	sub, sn := r.getStore(name)
+
+	buf, err := sub.Storage().Get(ctx, sub.Passfile(strings.TrimPrefix(sn, "/")))
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(buf)
+
+	return keyIndexKey(sub.Path() + "\n" + sn + "\n" + hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,47 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIconsCached(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("icon", "bank"))
+	require.NoError(t, rs.Set(ctx, "bank/checking", sec))
+
+	note := secrets.New()
+	require.NoError(t, note.Set("icon", "🔑"))
+	require.NoError(t, rs.SetInfo(ctx, "bank", note))
+
+	tr, err := rs.Tree(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"bank/checking": "bank", "bank/": "🔑"}, rs.Icons(ctx, tr))
+
+	ic, err := iconCache()
+	require.NoError(t, err)
+	cached, err := ic.Get(rs.iconCacheKey(ctx, "bank/checking"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bank"}, cached)
+
+	// changed entries are decrypted again.
+	require.NoError(t, sec.Set("icon", "card"))
+	require.NoError(t, rs.Set(ctx, "bank/checking", sec))
+	assert.Equal(t, "card", rs.Icons(ctx, tr)["bank/checking"])
+}
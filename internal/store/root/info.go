@@ -0,0 +1,50 @@
+package root
+
+import (
+	"context"
+	"path"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// LookupInfo returns the nearest folder note for the given folder and the
+// folder it was found in.
+func (r *Store) LookupInfo(ctx context.Context, dir string) (string, gopass.Secret, error) {
+	mp := r.MountPoint(dir)
+	store, dir := r.getStore(dir)
+
+	iDir, sec, err := store.LookupInfo(ctx, dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path.Join(mp, iDir), sec, nil
+}
+
+// HasInfo returns true if the given folder has a folder note.
+func (r *Store) HasInfo(ctx context.Context, dir string) bool {
+	store, dir := r.getStore(dir)
+
+	return store.HasInfo(ctx, dir)
+}
+
+// GetInfo returns the folder note of the given folder.
+func (r *Store) GetInfo(ctx context.Context, dir string) (gopass.Secret, error) {
+	store, dir := r.getStore(dir)
+
+	return store.GetInfo(ctx, dir)
+}
+
+// SetInfo will (over)write the folder note of the given folder.
+func (r *Store) SetInfo(ctx context.Context, dir string, sec gopass.Byter) error {
+	store, dir := r.getStore(dir)
+
+	return store.SetInfo(ctx, dir, sec)
+}
+
+// RemoveInfo will delete the folder note of the given folder.
+func (r *Store) RemoveInfo(ctx context.Context, dir string) error {
+	store, dir := r.getStore(dir)
+
+	return store.RemoveInfo(ctx, dir)
+}
@@ -72,7 +72,7 @@ func (r *Store) initialize(ctx context.Context) error {
 	}
 
 	// create the base store
-	path := fsutil.CleanPath(r.cfg.Get("mounts.path"))
+	path := fsutil.CleanPath(r.cfg.Path())
 	if sv := os.Getenv("PASSWORD_STORE_DIR"); sv != "" {
 		path = fsutil.CleanPath(sv)
 	}
@@ -99,10 +99,21 @@ func (r *Store) initialize(ctx context.Context) error {
 		debug.Log("Sub-Store mounted at %s from %s", alias, path)
 	}
 
+	// mount git submodules of all stores.
+	for _, alias := range r.cfg.Mounts() {
+		if sub, found := r.mounts[alias]; found {
+			r.mountSubmodules(ctx, alias, sub)
+		}
+	}
+	r.mountSubmodules(ctx, "", r.store)
+
 	// check for duplicate mounts
 	if err := r.checkMounts(); err != nil {
 		return fmt.Errorf("checking mounts failed: %w", err)
 	}
 
+	// interrupted operations are only recovered by gopass fsck.
+	r.warnInterrupted(ctx)
+
 	return nil
 }
@@ -0,0 +1,160 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/journal"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// recover completes or rolls back multi-file operations that were interrupted,
+// e.g. because gopass was killed during a re-encryption or a move.
+func (r *Store) recover(ctx context.Context) error {
+	var errs []error
+
+	for alias, sub := range r.mounts {
+		if err := r.recoverStore(ctx, sub); err != nil {
+			errs = append(errs, fmt.Errorf("failed to recover %s: %w", alias, err))
+		}
+	}
+
+	if err := r.recoverStore(ctx, r.store); err != nil {
+		errs = append(errs, fmt.Errorf("failed to recover root store: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// warnInterrupted warns about stores with the journal of an interrupted
+// operation. Recovering them can take a while and is left to gopass fsck.
+func (r *Store) warnInterrupted(ctx context.Context) {
+	subs := []*leaf.Store{r.store}
+	for _, sub := range r.mounts {
+		subs = append(subs, sub)
+	}
+
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+
+		if j, err := sub.Journal(); err != nil || j != nil {
+			out.Warningf(ctx, "The store %q has an unfinished operation. Run 'gopass fsck' to recover it", sub.Alias())
+		}
+	}
+}
+
+func (r *Store) recoverStore(ctx context.Context, sub *leaf.Store) error {
+	if sub == nil {
+		return nil
+	}
+
+	j, err := sub.Journal()
+	if err != nil || j == nil {
+		return err
+	}
+
+	// the journal is only written while holding the lock. If somebody else
+	// holds it the operation might still be in progress.
+	release, err := sub.Acquire(ctx)
+	if err != nil {
+		debug.Log("not recovering %s: %s", sub.Alias(), err)
+
+		return nil
+	}
+	defer release()
+
+	switch j.Op {
+	case journal.OpReencrypt:
+		return sub.RecoverReencrypt(ctx, j, tagUpdater(j.Tag))
+	case journal.OpMove:
+		return r.recoverMove(ctx, j)
+	default:
+		return fmt.Errorf("unknown operation %q in journal", j.Op)
+	}
+}
+
+// recoverMove inspects all entries of an interrupted move or copy. Entries that
+// have been written to the destination completely are removed from the source
+// (for moves). Incomplete destinations are removed so that the source is left
+// as it was.
+func (r *Store) recoverMove(ctx context.Context, j *journal.Journal) error {
+	pending := j.Pending()
+	out.Warningf(ctx, "Recovering an interrupted move of %d entries (started %s)", len(pending), j.Started.Local().Format("2006-01-02 15:04:05"))
+
+	defer r.InvalidateNameIndex()
+
+	ctx = ctxutil.WithGitCommit(ctx, false)
+	touched := map[string]*leaf.Store{}
+
+	for _, it := range pending {
+		subFrom, from := r.getStore(it.Name)
+		subTo, to := r.getStore(it.Dst)
+
+		if !subTo.Exists(ctx, to) {
+			debug.Log("%s was not copied to %s, yet. Keeping the source", it.Name, it.Dst)
+
+			continue
+		}
+
+		if !subFrom.Exists(ctx, from) {
+			debug.Log("%s was already moved to %s", it.Name, it.Dst)
+
+			continue
+		}
+
+		dst, err := subTo.Get(ctx, to)
+		if err != nil {
+			out.Warningf(ctx, "Removing incomplete copy %s of %s: %s", it.Dst, it.Name, err)
+			if err := subTo.Delete(leaf.WithNoDeleteEvent(ctx, true), to); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", it.Dst, err)
+			}
+			touched[subTo.Path()] = subTo
+
+			continue
+		}
+
+		src, err := subFrom.Get(ctx, from)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", it.Name, err)
+		}
+
+		if !bytes.Equal(src.Bytes(), dst.Bytes()) {
+			out.Warningf(ctx, "%s and %s differ. Please check them manually", it.Name, it.Dst)
+
+			continue
+		}
+
+		if !j.Delete {
+			continue
+		}
+
+		debug.Log("completing move of %s to %s", it.Name, it.Dst)
+		if err := subFrom.Delete(leaf.WithNoDeleteEvent(ctx, true), from); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", it.Name, err)
+		}
+		touched[subFrom.Path()] = subFrom
+	}
+
+	for p, sub := range touched {
+		if err := sub.Storage().Commit(ctx, "Recovered interrupted move"); err != nil {
+			switch {
+			case errors.Is(err, store.ErrGitNotInit):
+				debug.Log("skipping git commit - git not initialized in %s", p)
+			case errors.Is(err, store.ErrGitNothingToCommit):
+				debug.Log("skipping git commit - nothing to commit in %s", p)
+			default:
+				return fmt.Errorf("failed to commit changes to git (%s): %w", p, err)
+			}
+		}
+	}
+
+	return j.Finish()
+}
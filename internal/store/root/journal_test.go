@@ -0,0 +1,111 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/journal"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverMove(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	u.Entries = []string{
+		"old/a",
+		"old/b",
+		"old/c",
+	}
+	require.NoError(t, u.InitStore(""))
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, rs.Delete(ctx, "foo"))
+
+	// simulate a move of old/ to new/ that was interrupted after copying
+	// old/a. new/b differs from old/b, so both must be kept.
+	a, err := rs.Get(ctx, "old/a")
+	require.NoError(t, err)
+	require.NoError(t, rs.Set(ctx, "new/a", a))
+	require.NoError(t, rs.Set(ctx, "new/b", secrets.NewAKV()))
+
+	_, err = journal.Begin(rs.store.Path(), journal.OpMove, true, []journal.Item{
+		{Name: "old/a", Dst: "new/a"},
+		{Name: "old/b", Dst: "new/b"},
+		{Name: "old/c", Dst: "new/c"},
+	})
+	require.NoError(t, err)
+
+	// another move must wait for the recovery.
+	assert.ErrorIs(t, rs.Move(ctx, "old/c", "misc/c"), journal.ErrPending)
+
+	// starting gopass only warns, the recovery is left to fsck.
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() {
+		out.Stderr = os.Stderr
+	}()
+	rs2, err := createRootStore(ctxutil.WithHidden(ctx, false), u)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Run 'gopass fsck'")
+	assert.True(t, rs2.Exists(ctx, "old/a"))
+
+	require.NoError(t, rs.recover(ctx))
+
+	entries, err := rs.List(ctx, tree.INF)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new/a", "new/b", "old/b", "old/c"}, entries)
+
+	j, err := journal.Load(rs.store.Path())
+	require.NoError(t, err)
+	assert.Nil(t, j)
+}
+
+func TestRecoverReencryptTag(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	u.Entries = []string{
+		"old/a",
+		"old/b",
+	}
+	require.NoError(t, u.InitStore(""))
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	// simulate a revocation that was interrupted after re-encrypting old/a.
+	j, err := journal.Begin(rs.store.Path(), journal.OpReencrypt, false, []journal.Item{
+		{Name: "old/a", Done: true},
+		{Name: "old/b"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, j.SetTag("rotate"))
+
+	require.NoError(t, rs.recover(ctx))
+
+	sec, err := rs.Get(ctx, "old/b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rotate"}, Tags(sec))
+
+	sec, err = rs.Get(ctx, "old/a")
+	require.NoError(t, err)
+	assert.Empty(t, Tags(sec))
+
+	j, err = journal.Load(rs.store.Path())
+	require.NoError(t, err)
+	assert.Nil(t, j)
+}
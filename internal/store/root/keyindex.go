@@ -0,0 +1,107 @@
+package root
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// keyIndexTTL is how long the key names of an entry are cached after it was
+// last read or written.
+var keyIndexTTL = 90 * 24 * time.Hour
+
+type contextKey int
+
+const ctxKeyKeyIndex contextKey = iota
+
+// WithKeyIndex returns a context that adds the keys of the entries read or
+// written to the key index. Commands working on single entries, e.g. show
+// and edit, set it. Bulk operations like grep or audit must not fill the
+// index with every entry of the store.
+func WithKeyIndex(ctx context.Context, index bool) context.Context {
+	return context.WithValue(ctx, ctxKeyKeyIndex, index)
+}
+
+func isKeyIndex(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyKeyIndex).(bool)
+
+	return ok && bv
+}
+
+// The key index caches the names (never the values) of the keys of entries
+// that were recently read or written. It is used for shell completion so
+// completing key names doesn't require decrypting the entry. It's disabled
+// together with the name index by core.completionindex.
+func keyIndex() (*cache.OnDisk, error) {
+	return cache.NewOnDisk("keys", keyIndexTTL)
+}
+
+// keyIndexKey maps entry names to cache keys. The cache can't use the entry
+// name directly, since different names could map to the same filename.
+func keyIndexKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *Store) indexKeys(ctx context.Context, name string, sec gopass.Byter) {
+	if !isKeyIndex(ctx) || !r.nameIndexEnabled() {
+		return
+	}
+
+	s, ok := sec.(gopass.Secret)
+	if !ok {
+		return
+	}
+
+	ki, err := keyIndex()
+	if err != nil {
+		debug.Log("failed to open key index: %s", err)
+
+		return
+	}
+
+	if err := ki.Set(keyIndexKey(name), s.Keys()); err != nil {
+		debug.Log("failed to index keys of %s: %s", name, err)
+	}
+}
+
+func unindexKeys(name string) {
+	ki, err := keyIndex()
+	if err != nil {
+		return
+	}
+
+	_ = ki.Remove(keyIndexKey(name))
+}
+
+// CachedKeys returns the key names of the given entry from the key index. It
+// never decrypts the entry, so entries that were not read or written recently
+// return nothing.
+func (r *Store) CachedKeys(name string) []string {
+	ki, err := keyIndex()
+	if err != nil {
+		return nil
+	}
+
+	keys, err := ki.Get(keyIndexKey(name))
+	if err != nil {
+		debug.Log("no cached keys for %s: %s", name, err)
+
+		return nil
+	}
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k != "" {
+			out = append(out, k)
+		}
+	}
+
+	return out
+}
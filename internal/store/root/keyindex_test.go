@@ -0,0 +1,53 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyIndex(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	assert.Empty(t, rs.CachedKeys("db/prod"))
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("user", "admin"))
+	require.NoError(t, sec.Set("url", "db.example.org"))
+	require.NoError(t, rs.Set(ctx, "db/prod", sec))
+
+	// bulk operations don't fill the index.
+	_, err = rs.Get(ctx, "db/prod")
+	require.NoError(t, err)
+	assert.Empty(t, rs.CachedKeys("db/prod"))
+
+	ctx = WithKeyIndex(ctx, true)
+	_, err = rs.Get(ctx, "db/prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"url", "user"}, rs.CachedKeys("db/prod"))
+
+	require.NoError(t, rs.Delete(ctx, "db/prod"))
+	assert.Empty(t, rs.CachedKeys("db/prod"))
+
+	require.NoError(t, rs.Set(ctx, "db/prod", sec))
+	require.NoError(t, rs.Move(ctx, "db/prod", "db/moved"))
+	assert.Empty(t, rs.CachedKeys("db/prod"))
+
+	_, err = rs.Get(ctx, "db/moved")
+	require.NoError(t, err)
+	require.NoError(t, rs.Prune(ctx, "db"))
+	assert.Empty(t, rs.CachedKeys("db/moved"))
+}
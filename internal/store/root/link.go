@@ -3,6 +3,8 @@ package root
 import (
 	"context"
 	"fmt"
+
+	"github.com/gopasspw/gopass/internal/sessionlog"
 )
 
 // Link creates a symlink.
@@ -14,5 +16,12 @@ func (r *Store) Link(ctx context.Context, from, to string) error {
 		return fmt.Errorf("sylinks across stores are not supported")
 	}
 
-	return subFrom.Link(ctx, fName, tName)
+	if err := subFrom.Link(ctx, fName, tName); err != nil {
+		return err
+	}
+
+	r.indexName(to)
+	sessionlog.Add(sessionlog.OpLink, to, from)
+
+	return nil
 }
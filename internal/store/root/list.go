@@ -8,6 +8,7 @@ import (
 
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -27,6 +28,11 @@ func (r *Store) Tree(ctx context.Context) (*tree.Root, error) {
 	root := tree.New("gopass")
 	addFileFunc := func(in ...string) {
 		for _, f := range in {
+			// folder notes are only accessible through gopass info.
+			if leaf.IsInfo(f) {
+				continue
+			}
+
 			var ct string
 
 			switch {
@@ -62,6 +68,10 @@ func (r *Store) Tree(ctx context.Context) (*tree.Root, error) {
 		return nil, err
 	}
 
+	// entries below a mount point are shadowed by the mount, e.g. for
+	// auto-mounted submodules.
+	sf = r.unmounted("", sf)
+
 	debug.Log("[root] adding files: %q", sf)
 	addFileFunc(sf...)
 	debug.Log("[root] Tree: %s", root.Format(-1))
@@ -85,6 +95,8 @@ func (r *Store) Tree(ctx context.Context) (*tree.Root, error) {
 			return nil, fmt.Errorf("failed to add file: %w", err)
 		}
 
+		sf = r.unmounted(alias, sf)
+
 		debug.Log("[%s] adding files: %q", alias, sf)
 		addFileFunc(sf...)
 		addTplFunc(substore.ListTemplates(ctx, alias)...)
@@ -93,6 +105,20 @@ func (r *Store) Tree(ctx context.Context) (*tree.Root, error) {
 	return root, nil
 }
 
+// unmounted filters out all entries of the given mount that are shadowed
+// by a more specific mount.
+func (r *Store) unmounted(alias string, entries []string) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if r.MountPoint(e) != alias {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	return out
+}
+
 // HasSubDirs returns true if the named entity has subdirectories.
 func (r *Store) HasSubDirs(ctx context.Context, name string) (bool, error) {
 	sub, prefix := r.getStore(name)
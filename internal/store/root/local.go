@@ -0,0 +1,35 @@
+package root
+
+import (
+	"context"
+	"strings"
+)
+
+// LocalOnly returns the local-only entries and folders of all stores.
+// Folders end with a slash.
+func (r *Store) LocalOnly() []string {
+	res := r.store.LocalOnly()
+	for _, sub := range r.mounts {
+		res = append(res, sub.LocalOnly()...)
+	}
+
+	return res
+}
+
+// IsLocalOnly returns true if the entry is never committed or pushed.
+func (r *Store) IsLocalOnly(ctx context.Context, name string) bool {
+	sub, name := r.getStore(name)
+
+	return sub.IsLocalOnly(name)
+}
+
+// SetLocalOnly marks the entry or folder as local-only or removes the mark.
+func (r *Store) SetLocalOnly(ctx context.Context, name string, localOnly bool) error {
+	folder := strings.HasSuffix(name, "/")
+	sub, name := r.getStore(name)
+	if folder {
+		name += "/"
+	}
+
+	return sub.SetLocalOnly(ctx, name, localOnly)
+}
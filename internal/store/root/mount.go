@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/cache"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/store/leaf"
@@ -142,16 +144,46 @@ func (r *Store) MountPoint(name string) string {
 	return ""
 }
 
-// Lock drops all cached credentials, if any. Mostly only useful
-// for the gopass REPL.
-func (r *Store) Lock() error {
-	for _, sub := range r.mounts {
-		if err := sub.Lock(); err != nil {
+// Lock drops all cached credentials, if any. Used by gopass lock and the
+// gopass REPL. Stores can share a crypto backend instance, so each instance is
+// locked only once. The key, name and icon indexes reveal the contents of the
+// stores without decrypting anything, so they are purged as well.
+func (r *Store) Lock(ctx context.Context) error {
+	done := map[backend.Crypto]bool{}
+	for _, sub := range append([]*leaf.Store{r.store}, r.subStores()...) {
+		crypto := sub.Crypto()
+		if crypto == nil || done[crypto] {
+			continue
+		}
+		done[crypto] = true
+
+		if err := sub.Lock(ctx); err != nil {
 			return err
 		}
 	}
 
-	return r.store.Lock()
+	for _, index := range []func() (*cache.OnDisk, error){keyIndex, nameIndex, iconCache} {
+		c, err := index()
+		if err != nil {
+			return err
+		}
+
+		if err := c.Purge(); err != nil {
+			return fmt.Errorf("failed to purge %s: %w", c, err)
+		}
+	}
+
+	return nil
+}
+
+// subStores returns the mounted stores, the most specific first.
+func (r *Store) subStores() []*leaf.Store {
+	subs := make([]*leaf.Store, 0, len(r.mounts))
+	for _, mp := range r.MountPoints() {
+		subs = append(subs, r.mounts[mp])
+	}
+
+	return subs
 }
 
 // getStore returns the Store object at the most-specific mount point for the
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/cache"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +36,35 @@ func TestMount(t *testing.T) {
 	assert.NoError(t, rs.RemoveMount(ctx, "foo"))
 }
 
+func TestLock(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, u.InitStore("sub"))
+	require.NoError(t, rs.AddMount(ctx, "sub", u.StoreDir("sub")))
+
+	indexes := []func() (*cache.OnDisk, error){keyIndex, nameIndex, iconCache}
+	for _, index := range indexes {
+		c, err := index()
+		require.NoError(t, err)
+		require.NoError(t, c.Set("foo", []string{"bar"}))
+	}
+
+	require.NoError(t, rs.Lock(ctx))
+
+	for _, index := range indexes {
+		c, err := index()
+		require.NoError(t, err)
+		_, err = c.Get("foo")
+		assert.Error(t, err, c.String())
+	}
+}
+
 func TestMountPoint(t *testing.T) {
 	u := gptest.NewUnitTester(t)
 
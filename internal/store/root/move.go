@@ -1,22 +1,24 @@
 package root
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"path"
-	"path/filepath"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/journal"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/sessionlog"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
-	"github.com/gopasspw/gopass/pkg/fsutil"
 )
 
+var errCrossStore = errors.New("entries must be re-encrypted when moving between stores")
+
 // Copy will copy one entry to another location. Multi-store copies are
 // supported. Each entry has to be decoded and encoded for the destination
 // to make sure it's encrypted for the right set of recipients.
@@ -39,19 +41,38 @@ func (r *Store) Move(ctx context.Context, from, to string) error {
 // move handles both copy and move operations. Since the only difference is
 // deleting the source entry after the copy, we can reuse the same code.
 func (r *Store) move(ctx context.Context, from, to string, del bool) error {
+	defer r.InvalidateNameIndex()
+
+	srcIsDir := r.IsDir(ctx, from)
+	if !srcIsDir {
+		from = r.resolveOverlay(ctx, from)
+	}
+
 	subFrom, fromPrefix := r.getStore(from)
 	subTo, _ := r.getStore(to)
 
-	srcIsDir := r.IsDir(ctx, from)
 	dstIsDir := r.IsDir(ctx, to)
 
 	if srcIsDir && r.Exists(ctx, to) && !dstIsDir {
 		return fmt.Errorf("destination is a file")
 	}
 
-	if err := r.moveFromTo(ctx, subFrom, from, to, fromPrefix, srcIsDir, dstIsDir, del); err != nil {
+	release, err := subFrom.Acquire(ctx)
+	if err != nil {
 		return err
 	}
+	defer release()
+
+	j, err := r.moveFromTo(ctx, subFrom, from, to, fromPrefix, srcIsDir, dstIsDir, del)
+	if err != nil {
+		return err
+	}
+
+	op := sessionlog.OpCopy
+	if del {
+		op = sessionlog.OpMove
+	}
+	sessionlog.Add(op, to, from)
 
 	if err := subFrom.Storage().Commit(ctx, fmt.Sprintf("Move from %s to %s", from, to)); del && err != nil {
 		switch {
@@ -77,6 +98,10 @@ func (r *Store) move(ctx context.Context, from, to string, del bool) error {
 		}
 	}
 
+	if err := j.Finish(); err != nil {
+		return err
+	}
+
 	if err := subFrom.Storage().Push(ctx, "", ""); err != nil {
 		if errors.Is(err, store.ErrGitNotInit) {
 			msg := "Warning: git is not initialized for this storage. Ignoring auto-push option\n" +
@@ -124,7 +149,7 @@ func (r *Store) move(ctx context.Context, from, to string, del bool) error {
 	return nil
 }
 
-func (r *Store) moveFromTo(ctx context.Context, subFrom *leaf.Store, from, to, fromPrefix string, srcIsDir, dstIsDir, del bool) error {
+func (r *Store) moveFromTo(ctx context.Context, subFrom *leaf.Store, from, to, fromPrefix string, srcIsDir, dstIsDir, del bool) (*journal.Journal, error) {
 	ctx = ctxutil.WithGitCommit(ctx, false)
 
 	entries := []string{from}
@@ -135,21 +160,47 @@ func (r *Store) moveFromTo(ctx context.Context, subFrom *leaf.Store, from, to, f
 
 		entries, err = subFrom.List(ctx, fromPrefix+"/")
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if len(entries) < 1 {
 		debug.Log("Subtree %q has no entries", from)
 
-		return fmt.Errorf("no entries")
+		return nil, fmt.Errorf("no entries")
 	}
 
 	debug.Log("Moving (sub) tree %q to %q (entries: %+v)", from, to, entries)
 
-	var moved uint
+	items := make([]journal.Item, 0, len(entries))
 	for _, src := range entries {
-		dst := computeMoveDestination(src, from, to, srcIsDir, dstIsDir)
+		items = append(items, journal.Item{Name: src, Dst: computeMoveDestination(src, from, to, srcIsDir, dstIsDir)})
+	}
+
+	// refuse to touch pinned entries before anything was moved.
+	for _, it := range items {
+		names := []string{it.Dst}
+		if del {
+			names = append(names, it.Name)
+		}
+
+		for _, name := range names {
+			if r.IsPinned(ctx, name) {
+				return nil, fmt.Errorf("%s: %w. Run 'gopass pin --unpin %s' to change it", name, store.ErrPinned, name)
+			}
+		}
+	}
+
+	// record the move so it can be completed or rolled back if we are
+	// interrupted.
+	j, err := journal.Begin(subFrom.Path(), journal.OpMove, del, items)
+	if err != nil {
+		return nil, err
+	}
+
+	var moved uint
+	for _, it := range items {
+		src, dst := it.Name, it.Dst
 		if src == dst {
 			debug.Log("skipping %q. src eq dst", src)
 
@@ -157,46 +208,38 @@ func (r *Store) moveFromTo(ctx context.Context, subFrom *leaf.Store, from, to, f
 		}
 		debug.Log("Moving entry %q (%q) => %q (%q) (srcIsDir:%t, dstIsDir:%t, delete:%t)\n", src, from, dst, to, srcIsDir, dstIsDir, del)
 
-		err := r.directMove(ctx, src, dst, del)
-		if err == nil {
+		if err := r.directMove(ctx, src, dst, del); err == nil {
 			moved++
 			debug.Log("directly moved from %q to %q", src, dst)
+		} else {
+			debug.Log("direct move failed to move entry %q to %q: %s. Falling back to re-encryption", src, dst, err)
 
-			continue
-		}
-
-		debug.Log("direct move failed to move entry %q to %q: %s. Falling back to get and set", src, dst, err)
-
-		content, err := r.Get(ctx, src)
-		if err != nil {
-			return fmt.Errorf("source %s does not exist in source store %s: %w", from, subFrom.Alias(), err)
-		}
-
-		if err := r.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Move from %s to %s", src, dst)), dst, content); err != nil {
-			if !errors.Is(err, store.ErrMeaninglessWrite) {
-				return fmt.Errorf("failed to save secret %q to store: %w", to, err)
+			if err := r.reencryptMove(ctx, src, dst, del); err != nil {
+				return nil, err
 			}
-			out.Warningf(ctx, "No need to write: the secret is already there and with the right value")
+
+			moved++
 		}
 
 		if del {
-			debug.Log("Deleting moved entry %q from source %q", from, src)
-
-			if err := r.Delete(ctx, src); err != nil {
-				return fmt.Errorf("failed to delete secret %q: %w", src, err)
-			}
+			unindexKeys(src)
 		}
 
-		moved++
+		if err := j.Done(src); err != nil {
+			return nil, err
+		}
 	}
 
 	if moved < 1 {
-		return fmt.Errorf("no entries moved")
+		// nothing was touched, so there is nothing to recover.
+		_ = j.Finish()
+
+		return nil, fmt.Errorf("no entries moved")
 	}
 
 	debug.Log("Moved (sub) tree %q to %q", from, to)
 
-	return nil
+	return j, nil
 }
 
 func (r *Store) directMove(ctx context.Context, from, to string, del bool) error {
@@ -216,29 +259,48 @@ func (r *Store) directMove(ctx context.Context, from, to string, del bool) error
 		return subFrom.Copy(ctx, from, to)
 	}
 
-	debug.Log("cross mount direct move from %s%s to %s%s", subFrom.Alias(), from, subTo.Alias(), to)
+	// copying the ciphertext to another store would keep the recipients of
+	// the source store, so cross mount moves must be re-encrypted.
+	return fmt.Errorf("cross mount move from %s to %s: %w", subFrom.Alias(), subTo.Alias(), errCrossStore)
+}
+
+// reencryptMove decrypts the source entry, encrypts it for the recipients of
+// the destination store and verifies that the new entry can be decrypted and
+// matches the source before the source is removed.
+func (r *Store) reencryptMove(ctx context.Context, from, to string, del bool) error {
+	subFrom, fromName := r.getStore(from)
+	subTo, toName := r.getStore(to)
 
-	// assemble source and destination paths, call fsutil.CopyFile(from, to), remove source
-	// if del is true and then git add and commit both stores.
-	sfn := filepath.Join(subFrom.Path(), subFrom.Passfile(from))
-	dfn := filepath.Join(subTo.Path(), subTo.Passfile(to))
+	debug.Log("re-encrypting %s%s for %s%s", subFrom.Alias(), fromName, subTo.Alias(), toName)
 
-	if err := fsutil.CopyFile(sfn, dfn); err != nil {
-		return fmt.Errorf("failed to copy %q to %q: %w", from, to, err)
+	content, err := subFrom.Get(ctx, fromName)
+	if err != nil {
+		return fmt.Errorf("source %s does not exist in source store %s: %w", from, subFrom.Alias(), err)
 	}
 
-	if del {
-		if err := os.Remove(sfn); err != nil {
-			return fmt.Errorf("failed to delete %q from %s: %w", sfn, subFrom.Alias(), err)
+	if err := subTo.SetFrom(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Move from %s to %s", from, to)), subFrom, fromName, toName, content); err != nil {
+		if !errors.Is(err, store.ErrMeaninglessWrite) {
+			return fmt.Errorf("failed to save secret %q to store: %w", to, err)
 		}
+		out.Warningf(ctx, "No need to write: the secret is already there and with the right value")
+	}
+
+	moved, err := subTo.Get(ctx, toName)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %q after moving it to %s, keeping %q: %w", to, subTo.Alias(), from, err)
+	}
+
+	if !bytes.Equal(moved.Bytes(), content.Bytes()) {
+		return fmt.Errorf("content of %q differs from %q after moving it to %s, keeping %q", to, from, subTo.Alias(), from)
 	}
 
-	if err := subFrom.Storage().Add(ctx, sfn); err != nil {
-		debug.Log("failed to add %q to %s: %w", sfn, subFrom.Alias(), err)
+	if !del {
+		return nil
 	}
 
-	if err := subTo.Storage().Add(ctx, dfn); err != nil {
-		debug.Log("failed to add %q to %s: %w", dfn, subTo.Alias(), err)
+	debug.Log("Deleting moved entry %q from source %q", fromName, subFrom.Alias())
+	if err := subFrom.Delete(leaf.WithNoDeleteEvent(ctx, true), fromName); err != nil {
+		return fmt.Errorf("failed to delete secret %q: %w", from, err)
 	}
 
 	return nil
@@ -290,7 +352,16 @@ func (r *Store) Delete(ctx context.Context, name string) error {
 		return fmt.Errorf("can not delete a mount point. Use `gopass mounts remove %s`", store.Alias())
 	}
 
-	return store.Delete(ctx, sn)
+	unindexKeys(name)
+
+	if err := store.Delete(ctx, r.overlay(ctx, store, sn)); err != nil {
+		return err
+	}
+
+	r.unindexName(name)
+	sessionlog.Add(sessionlog.OpDelete, name, "")
+
+	return nil
 }
 
 // Prune will remove a subtree from the Store.
@@ -301,7 +372,25 @@ func (r *Store) Prune(ctx context.Context, tree string) error {
 		}
 	}
 
+	name := tree
 	store, tree := r.getStore(tree)
 
-	return store.Prune(ctx, tree)
+	defer r.InvalidateNameIndex()
+
+	entries, err := store.List(ctx, tree)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Prune(ctx, tree); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		unindexKeys(e)
+	}
+
+	sessionlog.Add(sessionlog.OpPrune, name, "")
+
+	return nil
 }
@@ -416,3 +416,39 @@ func TestComputeMoveDestination(t *testing.T) {
 		})
 	}
 }
+
+func TestMoveAcrossStores(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	u.Entries = []string{
+		"personal/db",
+	}
+	require.NoError(t, u.InitStore(""))
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, u.InitStore("team"))
+	require.NoError(t, rs.AddMount(ctx, "team", u.StoreDir("team")))
+
+	want, err := rs.Get(ctx, "personal/db")
+	require.NoError(t, err)
+
+	// copies keep the source.
+	require.NoError(t, rs.Copy(ctx, "personal/db", "team/copy"))
+	assert.True(t, rs.Exists(ctx, "personal/db"))
+
+	require.NoError(t, rs.Move(ctx, "personal/db", "team/db"))
+	assert.False(t, rs.Exists(ctx, "personal/db"))
+
+	team, err := rs.GetSubStore("team")
+	require.NoError(t, err)
+
+	for _, name := range []string{"copy", "db"} {
+		got, err := team.Get(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, want.Bytes(), got.Bytes())
+	}
+}
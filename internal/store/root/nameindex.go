@@ -0,0 +1,155 @@
+package root
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// nameIndexTTL bounds how long changes made outside of gopass, e.g. a manual
+// git pull, can go unnoticed by the name index.
+var nameIndexTTL = 24 * time.Hour
+
+// The name index caches the names of all entries for shell completion, so
+// pressing Tab doesn't have to walk every mount. It only contains the entry
+// names, which are stored in plaintext as filenames anyway. It is updated
+// when entries are added or removed through gopass and dropped on changes it
+// can't follow, e.g. moves, prunes and syncs. Set core.completionindex to
+// false to disable it.
+func nameIndex() (*cache.OnDisk, error) {
+	return cache.NewOnDisk("names", nameIndexTTL)
+}
+
+// nameIndexKey identifies the current store layout. Adding or removing a
+// mount changes the key, so the index doesn't need to be dropped on mount
+// changes.
+func (r *Store) nameIndexKey() string {
+	parts := []string{r.Path()}
+	for _, mp := range r.MountPoints() {
+		parts = append(parts, mp+"="+r.mounts[mp].Path())
+	}
+
+	return keyIndexKey(strings.Join(parts, "\n"))
+}
+
+func (r *Store) nameIndexEnabled() bool {
+	return r.cfg.GetBool("core.completionindex")
+}
+
+// CachedList returns the names of all entries from the name index. If the
+// index is missing, expired or disabled the store is listed and the index
+// rebuilt.
+func (r *Store) CachedList(ctx context.Context) ([]string, error) {
+	if !r.nameIndexEnabled() {
+		return r.List(ctx, tree.INF)
+	}
+
+	ni, err := nameIndex()
+	if err != nil {
+		debug.Log("failed to open name index: %s", err)
+
+		return r.List(ctx, tree.INF)
+	}
+
+	key := r.nameIndexKey()
+	if names, err := ni.Get(key); err == nil {
+		return cleanNames(names), nil
+	}
+
+	names, err := r.List(ctx, tree.INF)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ni.Set(key, names); err != nil {
+		debug.Log("failed to write name index: %s", err)
+	}
+
+	return names, nil
+}
+
+// indexName adds a new entry to an existing name index.
+func (r *Store) indexName(name string) {
+	r.updateNameIndex(func(names []string) ([]string, bool) {
+		i := sort.SearchStrings(names, name)
+		if i < len(names) && names[i] == name {
+			return names, false
+		}
+
+		names = append(names, "")
+		copy(names[i+1:], names[i:])
+		names[i] = name
+
+		return names, true
+	})
+}
+
+// unindexName removes an entry from an existing name index.
+func (r *Store) unindexName(name string) {
+	r.updateNameIndex(func(names []string) ([]string, bool) {
+		i := sort.SearchStrings(names, name)
+		if i >= len(names) || names[i] != name {
+			return names, false
+		}
+
+		return append(names[:i], names[i+1:]...), true
+	})
+}
+
+func (r *Store) updateNameIndex(update func([]string) ([]string, bool)) {
+	if !r.nameIndexEnabled() {
+		return
+	}
+
+	ni, err := nameIndex()
+	if err != nil {
+		return
+	}
+
+	key := r.nameIndexKey()
+
+	names, err := ni.Get(key)
+	if err != nil {
+		// nothing to update. the next completion rebuilds the index.
+		return
+	}
+
+	names, changed := update(cleanNames(names))
+	if !changed {
+		return
+	}
+
+	if err := ni.Set(key, names); err != nil {
+		debug.Log("failed to update name index: %s", err)
+		_ = ni.Remove(key)
+	}
+}
+
+// InvalidateNameIndex drops the name index. It must be called after changes
+// to the entries that were not made through Set or Delete.
+func (r *Store) InvalidateNameIndex() {
+	ni, err := nameIndex()
+	if err != nil {
+		return
+	}
+
+	_ = ni.Remove(r.nameIndexKey())
+}
+
+func cleanNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != "" {
+			out = append(out, n)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,62 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameIndex(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	want, err := rs.List(ctx, 0)
+	require.NoError(t, err)
+
+	names, err := rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, names)
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+
+	// changes made behind the back of the root store are not seen ...
+	require.NoError(t, rs.store.Set(ctx, "outside", sec))
+	names, err = rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, names, "outside")
+
+	// ... but Set and Delete update the index.
+	require.NoError(t, rs.Set(ctx, "db/prod", sec))
+	names, err = rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, names, "db/prod")
+
+	require.NoError(t, rs.Delete(ctx, "db/prod"))
+	names, err = rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, names, "db/prod")
+
+	rs.InvalidateNameIndex()
+	names, err = rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, names, "outside")
+
+	// moves drop the index.
+	require.NoError(t, rs.Move(ctx, "outside", "inside"))
+	names, err = rs.CachedList(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, names, "inside")
+	assert.NotContains(t, names, "outside")
+}
@@ -0,0 +1,81 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+var bom = []byte("\xef\xbb\xbf")
+
+// normalize cleans up secrets before they are encrypted. Content pasted from
+// other tools often carries a byte order mark or Windows line endings, which
+// end up in the password and break logins in subtle ways. Both are removed.
+// Trailing whitespace in the password and control characters can be
+// intentional, so they are only reported. Binary content is left alone.
+// The clean up is opt-in with core.normalize, since it also rewrites content
+// that was written on purpose, e.g. with cat or fscopy. The warnings are
+// always shown.
+func normalize(ctx context.Context, name string, buf []byte) ([]byte, bool) {
+	if !utf8.Valid(buf) {
+		debug.Log("not normalizing %s: not valid UTF-8", name)
+
+		return buf, false
+	}
+
+	var changed bool
+
+	if config.Bool(ctx, "core.normalize") {
+		if bytes.HasPrefix(buf, bom) {
+			buf = buf[len(bom):]
+			changed = true
+			out.Noticef(ctx, "Removed byte order mark from %s", name)
+		}
+
+		if bytes.Contains(buf, []byte("\r\n")) {
+			buf = bytes.ReplaceAll(buf, []byte("\r\n"), []byte("\n"))
+			changed = true
+			out.Noticef(ctx, "Converted Windows line endings in %s", name)
+		}
+	}
+
+	pw, _, _ := bytes.Cut(buf, []byte("\n"))
+	if len(bytes.TrimRight(pw, " \t")) != len(pw) {
+		out.Warningf(ctx, "The password of %s ends with whitespace. This is often a copy & paste mistake", name)
+	}
+
+	if hasControlChars(buf) {
+		out.Warningf(ctx, "%s contains control characters", name)
+	}
+
+	return buf, changed
+}
+
+// byteSecret is the normalized content of a secret.
+type byteSecret []byte
+
+// Bytes implements gopass.Byter.
+func (b byteSecret) Bytes() []byte {
+	return b
+}
+
+// hasControlChars returns true if buf contains control characters other than
+// tabs and line breaks.
+func hasControlChars(buf []byte) bool {
+	for _, r := range string(buf) {
+		if r == '\t' || r == '\n' || r == '\r' {
+			continue
+		}
+
+		if unicode.IsControl(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package root
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// HostsDir is the folder (relative to each mount) holding per-host overlays.
+const HostsDir = "hosts"
+
+// hostname returns the short name of the current host. It is a variable so
+// tests can override it.
+var hostname = func() string {
+	hn, err := os.Hostname()
+	if err != nil {
+		debug.Log("failed to get hostname: %s", err)
+
+		return ""
+	}
+
+	if i := strings.Index(hn, "."); i > 0 {
+		hn = hn[:i]
+	}
+
+	return strings.ToLower(hn)
+}
+
+// OverlayCandidates returns the names that override the given entry on the
+// given host, in order of precedence: `entry@host` and `hosts/<host>/entry`.
+func OverlayCandidates(name, host string) []string {
+	if host == "" || strings.Contains(path.Base(name), "@") || strings.HasPrefix(name, HostsDir+"/") {
+		return nil
+	}
+
+	return []string{
+		name + "@" + host,
+		path.Join(HostsDir, host, name),
+	}
+}
+
+// overlay returns the name of the host specific overlay of the given entry
+// within the given sub store or the name itself if there is none.
+func (r *Store) overlay(ctx context.Context, store *leaf.Store, name string) string {
+	if !config.Bool(ctx, "core.hostoverlays") {
+		return name
+	}
+
+	for _, cand := range OverlayCandidates(name, hostname()) {
+		if store.Exists(ctx, cand) {
+			debug.Log("using host overlay %s for %s", cand, name)
+
+			return cand
+		}
+	}
+
+	return name
+}
+
+// resolveOverlay returns the full name of the host specific overlay of the
+// given entry or the name itself if there is none. Operations acting on an
+// existing entry, e.g. delete and move, must use it to act on the entry Get
+// returns.
+func (r *Store) resolveOverlay(ctx context.Context, name string) string {
+	store, sn := r.getStore(name)
+	if ov := r.overlay(ctx, store, sn); ov != sn {
+		return strings.TrimSuffix(name, sn) + ov
+	}
+
+	return name
+}
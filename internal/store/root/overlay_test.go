@@ -0,0 +1,92 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlayCandidates(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"db/pw@laptop", "hosts/laptop/db/pw"}, OverlayCandidates("db/pw", "laptop"))
+	assert.Nil(t, OverlayCandidates("db/pw", ""))
+	assert.Nil(t, OverlayCandidates("db/pw@other", "laptop"))
+	assert.Nil(t, OverlayCandidates("hosts/other/db/pw", "laptop"))
+}
+
+func TestOverlay(t *testing.T) { //nolint:paralleltest
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	// overlays are opt-in.
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.hostoverlays", "true"))
+	ctx = cfg.WithConfig(ctx)
+
+	oldHostname := hostname
+	defer func() { hostname = oldHostname }()
+	hostname = func() string { return "laptop" }
+
+	set := func(name, pw string) {
+		sec := secrets.New()
+		sec.SetPassword(pw)
+		require.NoError(t, rs.store.Set(ctx, name, sec))
+	}
+	get := func(name string) string {
+		sec, err := rs.Get(ctx, name)
+		require.NoError(t, err)
+
+		return sec.Password()
+	}
+
+	set("db/pw", "shared")
+	assert.Equal(t, "shared", get("db/pw"))
+
+	set("hosts/laptop/db/pw", "subtree")
+	assert.Equal(t, "subtree", get("db/pw"))
+
+	set("db/pw@laptop", "suffix")
+	assert.Equal(t, "suffix", get("db/pw"))
+	assert.Equal(t, "shared", func() string {
+		sec, err := rs.Get(config.NewNoWrites().WithConfig(ctx), "db/pw")
+		require.NoError(t, err)
+
+		return sec.Password()
+	}())
+
+	// writes go to the overlay, too.
+	sec := secrets.New()
+	sec.SetPassword("updated")
+	require.NoError(t, rs.Set(ctx, "db/pw", sec))
+	assert.Equal(t, "updated", get("db/pw@laptop"))
+
+	// moves and deletes act on the overlay, too.
+	require.NoError(t, rs.Move(ctx, "db/pw", "db/moved"))
+	assert.Equal(t, "updated", get("db/moved"))
+	assert.Equal(t, "subtree", get("db/pw"))
+	require.NoError(t, rs.Delete(ctx, "db/pw"))
+	assert.False(t, rs.store.Exists(ctx, "hosts/laptop/db/pw"))
+	assert.Equal(t, "shared", get("db/pw"))
+
+	// other hosts see the base entry.
+	hostname = func() string { return "server" }
+	assert.Equal(t, "shared", get("db/pw"))
+
+	// overlays only exist on the host.
+	set("local/only@server", "local")
+	assert.True(t, rs.Exists(ctx, "local/only"))
+	assert.Equal(t, "local", get("local/only"))
+}
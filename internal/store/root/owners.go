@@ -0,0 +1,26 @@
+package root
+
+import "context"
+
+// Owners returns the owners of the given entry, i.e. the people who have to
+// approve changes to it.
+func (r *Store) Owners(ctx context.Context, name string) []string {
+	sub, name := r.getStore(name)
+
+	return sub.Owners(ctx, name)
+}
+
+// FolderOwners returns the owners listed in the OWNERS file of exactly this
+// folder.
+func (r *Store) FolderOwners(ctx context.Context, dir string) []string {
+	sub, dir := r.getStore(dir)
+
+	return sub.FolderOwners(ctx, dir)
+}
+
+// SetFolderOwners writes the OWNERS file of the folder.
+func (r *Store) SetFolderOwners(ctx context.Context, dir string, owners []string) error {
+	sub, dir := r.getStore(dir)
+
+	return sub.SetFolderOwners(ctx, dir, owners)
+}
@@ -0,0 +1,41 @@
+package root
+
+import (
+	"context"
+	"path"
+	"sort"
+)
+
+// Pin pins the entry, so it can't be changed, moved or removed.
+func (r *Store) Pin(ctx context.Context, name string) error {
+	sub, name := r.getStore(name)
+
+	return sub.Pin(ctx, name)
+}
+
+// Unpin unpins the entry.
+func (r *Store) Unpin(ctx context.Context, name string) error {
+	sub, name := r.getStore(name)
+
+	return sub.Unpin(ctx, name)
+}
+
+// IsPinned returns true if the entry is pinned.
+func (r *Store) IsPinned(ctx context.Context, name string) bool {
+	sub, name := r.getStore(name)
+
+	return sub.IsPinned(ctx, name)
+}
+
+// Pinned returns the pinned entries of all mounts, sorted.
+func (r *Store) Pinned(ctx context.Context) []string {
+	pins := r.store.Pinned(ctx)
+	for alias, sub := range r.mounts {
+		for _, p := range sub.Pinned(ctx) {
+			pins = append(pins, path.Join(alias, p))
+		}
+	}
+	sort.Strings(pins)
+
+	return pins
+}
@@ -0,0 +1,62 @@
+package root
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/policy"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+)
+
+// PolicyPacks returns the policy packs of the store with the given mount
+// point.
+func (r *Store) PolicyPacks(ctx context.Context, store string) []*policy.Pack {
+	sub, _ := r.getStore(store)
+
+	return sub.PolicyPacks(ctx)
+}
+
+// HasPolicyPacks returns true if any store has a policy pack.
+func (r *Store) HasPolicyPacks(ctx context.Context) bool {
+	if len(r.store.PolicyPacks(ctx)) > 0 {
+		return true
+	}
+
+	for _, sub := range r.mounts {
+		if len(sub.PolicyPacks(ctx)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckPolicy checks the password against the policy packs of the store
+// holding the secret. Rules match the name relative to that store.
+func (r *Store) CheckPolicy(ctx context.Context, name, pw string) error {
+	sub, sn := r.getStore(name)
+
+	return policy.Check(sub.PolicyPacks(ctx), strings.TrimPrefix(sn, "/"), pw)
+}
+
+// PolicyGenerator returns a password generator for the given secret that
+// satisfies its policy or nil if no policy applies.
+func (r *Store) PolicyGenerator(ctx context.Context, name string, length int, symbols bool) *pwgen.Cryptic {
+	sub, sn := r.getStore(name)
+
+	return policy.Generator(sub.PolicyPacks(ctx), strings.TrimPrefix(sn, "/"), length, symbols)
+}
+
+// ImportPolicyPack adds or updates a policy pack of the given store.
+func (r *Store) ImportPolicyPack(ctx context.Context, store string, buf []byte, force bool) (*policy.Pack, error) {
+	sub, _ := r.getStore(store)
+
+	return sub.ImportPolicyPack(ctx, buf, force)
+}
+
+// RemovePolicyPack removes a policy pack from the given store.
+func (r *Store) RemovePolicyPack(ctx context.Context, store, name string) error {
+	sub, _ := r.getStore(store)
+
+	return sub.RemovePolicyPack(ctx, name)
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/gopass"
 )
 
@@ -43,6 +45,8 @@ func (r *Store) RCSRemoveRemote(ctx context.Context, name, remote string) error
 func (r *Store) RCSPull(ctx context.Context, name, origin, remote string) error {
 	store, _ := r.getStore(name)
 
+	defer r.InvalidateNameIndex()
+
 	return store.Storage().Pull(ctx, origin, remote)
 }
 
@@ -76,3 +80,27 @@ func (r *Store) RCSStatus(ctx context.Context, name string) error {
 
 	return store.GitStatus(ctx, name)
 }
+
+// AccessHistory reconstructs who could decrypt the named entity during which
+// time ranges.
+func (r *Store) AccessHistory(ctx context.Context, name string) ([]leaf.AccessPeriod, error) {
+	store, name := r.getStore(name)
+
+	return store.AccessHistory(ctx, name)
+}
+
+// HeadCommit returns the hash of the current commit of the store containing
+// the entry or an empty string if it's not known, e.g. because the store
+// doesn't use git.
+func (r *Store) HeadCommit(ctx context.Context, name string) string {
+	store, _ := r.getStore(name)
+
+	head, err := store.Storage().Head(ctx)
+	if err != nil {
+		debug.Log("failed to get the current commit of %s: %s", store.Path(), err)
+
+		return ""
+	}
+
+	return head
+}
@@ -27,4 +27,7 @@ func TestRCS(t *testing.T) {
 	revs, err := rs.ListRevisions(ctx, "foo")
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(revs))
+
+	// the fs backend has no commits.
+	assert.Equal(t, "", rs.HeadCommit(ctx, "foo"))
 }
@@ -3,12 +3,26 @@ package root
 import (
 	"context"
 
+	"github.com/gopasspw/gopass/internal/canary"
 	"github.com/gopasspw/gopass/pkg/gopass"
 )
 
-// Get returns the plaintext of a single key.
+// Get returns the plaintext of a single key. If a host specific overlay of the
+// entry exists it is returned instead. Reading a canary entry triggers an
+// alert.
 func (r *Store) Get(ctx context.Context, name string) (gopass.Secret, error) {
-	store, name := r.getStore(name)
+	store, sn := r.getStore(name)
 
-	return store.Get(ctx, name)
+	sec, err := store.Get(ctx, r.overlay(ctx, store, sn))
+	if err != nil {
+		return sec, err
+	}
+
+	r.indexKeys(ctx, name, sec)
+
+	if HasTags(sec, canary.Tag) {
+		canary.Trigger(ctx, name)
+	}
+
+	return sec, nil
 }
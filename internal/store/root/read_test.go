@@ -2,9 +2,13 @@ package root
 
 import (
 	"context"
+	"os"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/canary"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,3 +27,31 @@ func TestGet(t *testing.T) {
 	_, err = rs.Get(ctx, "foo")
 	assert.NoError(t, err)
 }
+
+func TestGetCanary(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	sec := secrets.New()
+	sec.SetPassword("honey")
+	require.NoError(t, SetTags(sec, []string{canary.Tag}))
+	require.NoError(t, rs.Set(ctx, "aws/root", sec))
+
+	// listing tagged entries must not trigger the canary
+	_, err = rs.ListTagged(ctx, canary.Tag)
+	require.NoError(t, err)
+	assert.NoFileExists(t, auditlog.Path())
+
+	_, err = rs.Get(ctx, "aws/root")
+	require.NoError(t, err)
+
+	buf, err := os.ReadFile(auditlog.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), `"entry":"aws/root"`)
+}
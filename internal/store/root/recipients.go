@@ -2,6 +2,7 @@ package root
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -42,6 +44,37 @@ func (r *Store) RemoveRecipient(ctx context.Context, store, rec string) error {
 	return sub.RemoveRecipient(ctx, rec)
 }
 
+// RevokeRecipient removes the recipient from the root store and all mounts,
+// including the recipients of sub folders, and re-encrypts the affected
+// secrets. If tag is not empty it's added to these secrets. It returns the
+// revocations by mount point. Stores that fail are skipped and reported in the
+// error.
+func (r *Store) RevokeRecipient(ctx context.Context, rec, tag string) (map[string]*leaf.Revocation, error) {
+	update := tagUpdater(tag)
+	res := make(map[string]*leaf.Revocation, len(r.mounts)+1)
+
+	var errs []error
+	for _, alias := range append([]string{""}, r.MountPoints()...) {
+		sub := r.store
+		if alias != "" {
+			sub = r.mounts[alias]
+		}
+
+		rev, err := sub.RevokeRecipient(ctx, rec, tag, update)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to revoke %s from store %q: %w", rec, alias, err))
+
+			continue
+		}
+
+		if rev != nil {
+			res[alias] = rev
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
 func (r *Store) addRecipient(ctx context.Context, prefix string, root *tree.Root, recp string, pretty bool) error {
 	sub, _ := r.getStore(prefix)
 	key := recp
@@ -139,3 +172,11 @@ func (r *Store) RecipientsTree(ctx context.Context, pretty bool) (*tree.Root, er
 
 	return root, nil
 }
+
+// AddFolderRecipient adds a recipient to a single folder of the matching
+// store, see leaf.Store.AddFolderRecipient.
+func (r *Store) AddFolderRecipient(ctx context.Context, folder, rec string) ([]string, error) {
+	sub, name := r.getStore(folder)
+
+	return sub.AddFolderRecipient(ctx, name, rec)
+}
@@ -0,0 +1,49 @@
+package root
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/out"
+)
+
+// Redactions returns the folders of all stores that are marked for redaction
+// and that we can not decrypt, mapped to their redaction mode.
+func (r *Store) Redactions(ctx context.Context) map[string]string {
+	res, err := r.store.Redactions(ctx)
+	if err != nil {
+		out.Errorf(ctx, "Failed to list redacted folders: %s", err)
+	}
+
+	if res == nil {
+		res = make(map[string]string)
+	}
+
+	for alias, sub := range r.mounts {
+		rs, err := sub.Redactions(ctx)
+		if err != nil {
+			out.Errorf(ctx, "[%s] Failed to list redacted folders: %s", alias, err)
+
+			continue
+		}
+
+		for dir, mode := range rs {
+			res[dir] = mode
+		}
+	}
+
+	return res
+}
+
+// FolderRedaction returns the redaction mode of exactly this folder.
+func (r *Store) FolderRedaction(ctx context.Context, dir string) string {
+	sub, dir := r.getStore(dir)
+
+	return sub.FolderRedaction(ctx, dir)
+}
+
+// SetFolderRedaction sets the redaction mode of the folder.
+func (r *Store) SetFolderRedaction(ctx context.Context, dir, mode string) error {
+	sub, dir := r.getStore(dir)
+
+	return sub.SetFolderRedaction(ctx, dir, mode)
+}
@@ -0,0 +1,84 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// ListPending returns the changes waiting for review in all stores, oldest
+// first.
+func (r *Store) ListPending(ctx context.Context) ([]*leaf.Pending, error) {
+	res, err := r.store.ListPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for alias, sub := range r.mounts {
+		ps, err := sub.ListPending(ctx)
+		if err != nil {
+			out.Errorf(ctx, "[%s] Failed to list pending changes: %s", alias, err)
+
+			continue
+		}
+		res = append(res, ps...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+// GetPending returns the pending change with the given ID and its proposed
+// content.
+func (r *Store) GetPending(ctx context.Context, id string) (*leaf.Pending, gopass.Secret, error) {
+	sub, err := r.pendingStore(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sub.GetPending(ctx, id)
+}
+
+// ApprovePending applies the pending change with the given ID.
+func (r *Store) ApprovePending(ctx context.Context, id string) (*leaf.Pending, error) {
+	sub, err := r.pendingStore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.InvalidateNameIndex()
+
+	return sub.ApprovePending(ctx, id)
+}
+
+// RejectPending discards the pending change with the given ID.
+func (r *Store) RejectPending(ctx context.Context, id string) (*leaf.Pending, error) {
+	sub, err := r.pendingStore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.RejectPending(ctx, id)
+}
+
+// pendingStore returns the store holding the pending change with the given ID.
+func (r *Store) pendingStore(ctx context.Context, id string) (*leaf.Store, error) {
+	if r.store.HasPending(ctx, id) {
+		return r.store, nil
+	}
+
+	for _, sub := range r.mounts {
+		if sub.HasPending(ctx, id) {
+			return sub, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", id, leaf.ErrNoPending)
+}
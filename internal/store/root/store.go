@@ -42,11 +42,11 @@ func (r *Store) WithStoreConfig(ctx context.Context) context.Context {
 	return r.cfg.WithConfig(ctx)
 }
 
-// Exists checks the existence of a single entry.
+// Exists checks the existence of a single entry (or its host specific overlay).
 func (r *Store) Exists(ctx context.Context, name string) bool {
 	store, name := r.getStore(name)
 
-	return store.Exists(ctx, name)
+	return store.Exists(ctx, r.overlay(ctx, store, name))
 }
 
 // IsDir checks if a given key is actually a folder.
@@ -0,0 +1,82 @@
+package root
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/gitconfig"
+)
+
+// Submodules returns the paths of all git submodules declared in the
+// .gitmodules file in the given directory, relative to that directory.
+func Submodules(dir string) []string {
+	fh, err := os.Open(filepath.Join(dir, ".gitmodules"))
+	if err != nil {
+		return nil
+	}
+	defer fh.Close() //nolint:errcheck
+
+	cfg := &gitconfig.Configs{Preset: gitconfig.ParseConfig(fh)}
+
+	paths := make([]string, 0, 4)
+	for _, name := range cfg.ListSubsections("submodule") {
+		p := path.Clean(filepath.ToSlash(cfg.Get("submodule." + name + ".path")))
+		if p == "" || p == "." || strings.HasPrefix(p, "../") || path.IsAbs(p) {
+			debug.Log("ignoring submodule %q with invalid path %q", name, p)
+
+			continue
+		}
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+// mountSubmodules mounts the git submodules of the given store as their own
+// stores, so they are synced independently. Explicit mounts take
+// precedence. Auto-mounted submodules are not persisted to the config.
+func (r *Store) mountSubmodules(ctx context.Context, alias string, sub *leaf.Store) {
+	if !config.Bool(ctx, "core.submodules") {
+		return
+	}
+
+	for _, p := range Submodules(sub.Path()) {
+		subAlias := p
+		if alias != "" {
+			subAlias = alias + "/" + p
+		}
+
+		if _, found := r.mounts[subAlias]; found {
+			debug.Log("submodule %s already mounted", subAlias)
+
+			continue
+		}
+
+		dir := filepath.Join(sub.Path(), filepath.FromSlash(p))
+		if !fsutil.IsFile(filepath.Join(dir, ".git")) && !fsutil.IsDir(filepath.Join(dir, ".git")) {
+			debug.Log("submodule %s of %s is not checked out. Run 'gopass git submodule update --init' to use it", p, sub.Path())
+
+			continue
+		}
+
+		s, err := leaf.New(ctx, subAlias, dir)
+		if err != nil || !s.IsInitialized(ctx) {
+			debug.Log("submodule %s at %s is not a password store: %v", subAlias, dir, err)
+
+			continue
+		}
+
+		r.mounts[subAlias] = s
+		debug.Log("auto-mounted submodule %s -> %s", subAlias, dir)
+
+		// submodules can have submodules, too.
+		r.mountSubmodules(ctx, subAlias, s)
+	}
+}
@@ -0,0 +1,75 @@
+package root
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmodules(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	assert.Nil(t, Submodules(td))
+
+	require.NoError(t, os.WriteFile(filepath.Join(td, ".gitmodules"), []byte(`[submodule "team"]
+	path = team
+	url = https://example.com/team.git
+[submodule "evil"]
+	path = ../evil
+	url = https://example.com/evil.git
+[submodule "nested"]
+	path = shared/ops
+	url = https://example.com/ops.git
+`), 0o644))
+
+	assert.ElementsMatch(t, []string{"team", "shared/ops"}, Submodules(td))
+}
+
+func TestMountSubmodules(t *testing.T) { //nolint:paralleltest
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	// a checked out submodule containing a store.
+	require.NoError(t, u.InitStore("team-sub"))
+	subDir := filepath.Join(u.StoreDir(""), "team")
+	require.NoError(t, os.Rename(u.StoreDir("team-sub"), subDir))
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, ".git"), []byte("gitdir: ../.git/modules/team\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(u.StoreDir(""), ".gitmodules"), []byte(`[submodule "team"]
+	path = team
+	url = https://example.com/team.git
+`), 0o644))
+
+	// submodules are only mounted if enabled.
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	assert.Equal(t, "", rs.MountPoint("team/foo"))
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.submodules", "true"))
+	ctx = cfg.WithConfig(ctx)
+
+	rs, err = createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	assert.Equal(t, "team", rs.MountPoint("team/foo"))
+
+	entries, err := rs.List(ctx, tree.INF)
+	require.NoError(t, err)
+	assert.Contains(t, entries, "team/foo")
+	assert.Contains(t, entries, "foo")
+
+	// auto-mounted submodules are not persisted.
+	assert.NotContains(t, rs.cfg.Mounts(), "team")
+}
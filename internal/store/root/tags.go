@@ -0,0 +1,118 @@
+package root
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/canary"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// TagsKey is the key holding the comma separated tags of an entry.
+const TagsKey = "tags"
+
+// Tags returns the sorted tags of the given secret.
+func Tags(sec gopass.Secret) []string {
+	tv, found := sec.Get(TagsKey)
+	if !found {
+		return nil
+	}
+
+	tags := make([]string, 0, strings.Count(tv, ",")+1)
+	for _, t := range strings.Split(tv, ",") {
+		if t := strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return set.Sorted(tags)
+}
+
+// SetTags replaces the tags of the given secret. An empty list removes the
+// tags key.
+func SetTags(sec gopass.Secret, tags []string) error {
+	tags = set.Sorted(tags)
+	if len(tags) < 1 {
+		_ = sec.Del(TagsKey)
+
+		return nil
+	}
+
+	return sec.Set(TagsKey, strings.Join(tags, ", "))
+}
+
+// tagUpdater returns a function that adds the tag to a secret or nil if the
+// tag is empty.
+func tagUpdater(tag string) func(gopass.Secret) error {
+	if tag == "" {
+		return nil
+	}
+
+	return func(sec gopass.Secret) error {
+		return SetTags(sec, append(Tags(sec), tag))
+	}
+}
+
+// HasTags returns true if the secret has all of the given tags.
+func HasTags(sec gopass.Secret, tags ...string) bool {
+	have := set.Map(Tags(sec))
+	for _, t := range tags {
+		if !have[t] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListTagged returns all entries that have all of the given tags. Since tags
+// are stored inside the encrypted entries, every entry has to be decrypted.
+func (r *Store) ListTagged(ctx context.Context, tags ...string) ([]string, error) {
+	entries, err := r.List(ctx, tree.INF)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the tags are looked at, so this must not trigger canaries.
+	ctx = canary.WithSuppressed(ctx, true)
+
+	tagged := make([]string, 0, len(entries))
+	for _, e := range entries {
+		sec, err := r.Get(ctx, e)
+		if err != nil {
+			debug.Log("failed to decrypt %s: %s", e, err)
+
+			continue
+		}
+
+		if HasTags(sec, tags...) {
+			tagged = append(tagged, e)
+		}
+	}
+
+	sort.Strings(tagged)
+
+	return tagged, nil
+}
+
+// TaggedTree returns the tree representation of all entries that have all of
+// the given tags.
+func (r *Store) TaggedTree(ctx context.Context, tags ...string) (*tree.Root, error) {
+	tagged, err := r.ListTagged(ctx, tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	root := tree.New("gopass")
+	for _, e := range tagged {
+		if err := root.AddFile(e, "text/plain"); err != nil {
+			debug.Log("failed to add %s to tree: %s", e, err)
+		}
+	}
+
+	return root, nil
+}
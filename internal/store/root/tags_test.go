@@ -0,0 +1,67 @@
+package root
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	sec := secrets.New()
+	assert.Empty(t, Tags(sec))
+
+	require.NoError(t, SetTags(sec, []string{"quarterly", "prod", "prod"}))
+	assert.Equal(t, []string{"prod", "quarterly"}, Tags(sec))
+	assert.True(t, HasTags(sec, "prod"))
+	assert.True(t, HasTags(sec, "prod", "quarterly"))
+	assert.False(t, HasTags(sec, "prod", "dev"))
+
+	sec = secrets.ParseAKV([]byte("pw\ntags: a,b ,, c\n"))
+	assert.Equal(t, []string{"a", "b", "c"}, Tags(sec))
+
+	require.NoError(t, SetTags(sec, nil))
+	_, found := sec.Get(TagsKey)
+	assert.False(t, found)
+}
+
+func TestListTagged(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	for name, tags := range map[string][]string{
+		"db/prod":    {"prod", "quarterly"},
+		"db/staging": {"staging"},
+		"web/prod":   {"prod"},
+	} {
+		sec := secrets.New()
+		sec.SetPassword("secret")
+		require.NoError(t, SetTags(sec, tags))
+		require.NoError(t, rs.Set(ctx, name, sec))
+	}
+
+	tagged, err := rs.ListTagged(ctx, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/prod", "web/prod"}, tagged)
+
+	tagged, err = rs.ListTagged(ctx, "prod", "quarterly")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/prod"}, tagged)
+
+	tt, err := rs.TaggedTree(ctx, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/staging"}, tt.List(tree.INF))
+}
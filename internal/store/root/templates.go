@@ -12,14 +12,24 @@ import (
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
-// LookupTemplate will lookup and return a template.
+// LookupTemplate will lookup and return a template. Templates of the mount
+// the secret belongs to take precedence. If the mount has no matching
+// template the templates of the root store are used, relative to the mount
+// point. This allows to share templates among all mounts.
 func (r *Store) LookupTemplate(ctx context.Context, name string) (string, []byte, bool) {
-	oName := name
+	mp := r.MountPoint(name)
 	store, name := r.getStore(name)
-	tName, content, found := store.LookupTemplate(ctx, name)
-	tName = filepath.Join(r.MountPoint(oName), tName)
+	if tName, content, found := store.LookupTemplate(ctx, name); found {
+		return filepath.Join(mp, tName), content, true
+	}
+
+	if mp == "" {
+		return "", []byte{}, false
+	}
+
+	debug.Log("no template for %q in mount %q, trying root store", name, mp)
 
-	return tName, content, found
+	return r.store.LookupTemplate(ctx, name)
 }
 
 // TemplateTree returns a tree of all templates.
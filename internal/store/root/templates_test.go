@@ -43,3 +43,30 @@ func TestTemplate(t *testing.T) {
 	assert.Equal(t, "foobar", string(b))
 	assert.NoError(t, rs.RemoveTemplate(ctx, "foo"))
 }
+
+func TestLookupTemplateMounts(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, u.InitStore("team"))
+	require.NoError(t, rs.AddMount(ctx, "team", u.StoreDir("team")))
+
+	require.NoError(t, rs.SetTemplate(ctx, "websites", []byte("root")))
+
+	// the root store template is used for mounts without a template.
+	_, b, found := rs.LookupTemplate(ctx, "team/websites/example.org")
+	assert.True(t, found)
+	assert.Equal(t, "root", string(b))
+
+	// mount specific templates take precedence.
+	require.NoError(t, rs.SetTemplate(ctx, "team/websites", []byte("team")))
+	name, b, found := rs.LookupTemplate(ctx, "team/websites/example.org")
+	assert.True(t, found)
+	assert.Equal(t, "team", string(b))
+	assert.Equal(t, "team/websites/.pass-template", name)
+}
@@ -3,12 +3,27 @@ package root
 import (
 	"context"
 
+	"github.com/gopasspw/gopass/internal/sessionlog"
 	"github.com/gopasspw/gopass/pkg/gopass"
 )
 
-// Set encodes and write the ciphertext of one entry to disk.
+// Set encodes and write the ciphertext of one entry to disk. If a host specific
+// overlay of the entry exists it is updated instead.
 func (r *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
-	store, name := r.getStore(name)
+	store, sn := r.getStore(name)
 
-	return store.Set(ctx, name, sec)
+	var content gopass.Byter = sec
+	if buf, changed := normalize(ctx, name, sec.Bytes()); changed {
+		content = byteSecret(buf)
+	}
+
+	if err := store.Set(ctx, r.overlay(ctx, store, sn), content); err != nil {
+		return err
+	}
+
+	r.indexKeys(ctx, name, sec)
+	r.indexName(name)
+	sessionlog.Add(sessionlog.OpSet, name, "")
+
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/tests/gptest"
@@ -30,3 +31,72 @@ func TestSet(t *testing.T) {
 	err = rs.Set(ctx, "zab2", sec)
 	assert.NoError(t, err)
 }
+
+func TestSetNormalize(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	sec := secrets.NewAKV()
+	_, err = sec.Write([]byte("\xef\xbb\xbfpassword\r\nuser: john\r\n"))
+	require.NoError(t, err)
+
+	// secrets are kept as written by default.
+	require.NoError(t, rs.Set(ctx, "raw", sec))
+
+	got, err := rs.Get(ctx, "raw")
+	require.NoError(t, err)
+	assert.Equal(t, "\xef\xbb\xbfpassword", got.Password())
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.normalize", "true"))
+	ctx = cfg.WithConfig(ctx)
+	require.NoError(t, rs.Set(ctx, "normalized", sec))
+
+	got, err = rs.Get(ctx, "normalized")
+	require.NoError(t, err)
+	assert.Equal(t, "password", got.Password())
+	assert.Equal(t, "password\nuser: john\n", string(got.Bytes()))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.Set("", "core.normalize", "true"))
+	ctx = cfg.WithConfig(ctx)
+
+	for _, tc := range []struct {
+		in      string
+		out     string
+		changed bool
+	}{
+		{in: "password\nbody\n", out: "password\nbody\n"},
+		{in: "\xef\xbb\xbfpassword", out: "password", changed: true},
+		{in: "password\r\nbody\r\n", out: "password\nbody\n", changed: true},
+		{in: "password \nbody", out: "password \nbody"},
+		{in: "pass\x07word", out: "pass\x07word"},
+		{in: "\xff\xfe\r\n", out: "\xff\xfe\r\n"},
+	} {
+		buf, changed := normalize(ctx, "foo", []byte(tc.in))
+		assert.Equal(t, tc.out, string(buf), tc.in)
+		assert.Equal(t, tc.changed, changed, tc.in)
+	}
+
+	require.NoError(t, cfg.Set("", "core.normalize", "false"))
+
+	buf, changed := normalize(ctx, "foo", []byte("password\r\n"))
+	assert.Equal(t, "password\r\n", string(buf))
+	assert.False(t, changed)
+
+	assert.True(t, hasControlChars([]byte("a\x1bb")))
+	assert.False(t, hasControlChars([]byte("a\tb\r\nc")))
+}
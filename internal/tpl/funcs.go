@@ -9,6 +9,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gopasspw/gopass/internal/fips"
 	"github.com/gopasspw/gopass/internal/hashsum"
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2i"
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2id"
@@ -369,8 +370,17 @@ func strval(v any) string {
 	}
 }
 
+// nonFIPSFuncs use hashes or KDFs that are not FIPS approved.
+var nonFIPSFuncs = []string{FuncMd5sum, FuncMd5Crypt, FuncArgon2i, FuncArgon2id, FuncBcrypt}
+
+func notApproved(name string) func(...string) (string, error) {
+	return func(...string) (string, error) {
+		return "", fmt.Errorf("template function %s: %w", name, fips.ErrNotApproved)
+	}
+}
+
 func funcMap(ctx context.Context, kv kvstore) template.FuncMap {
-	return template.FuncMap{
+	fm := template.FuncMap{
 		FuncGet:           get(ctx, kv),
 		FuncGetPassword:   getPassword(ctx, kv),
 		FuncGetValue:      getValue(ctx, kv),
@@ -391,6 +401,14 @@ func funcMap(ctx context.Context, kv kvstore) template.FuncMap {
 		FuncDate:          date,
 		FuncTruncate:      truncate,
 	}
+
+	if fips.Enabled(ctx) {
+		for _, name := range nonFIPSFuncs {
+			fm[name] = notApproved(name)
+		}
+	}
+
+	return fm
 }
 
 // PublicFuncMap returns a template.FuncMap with useful template functions.
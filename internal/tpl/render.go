@@ -0,0 +1,125 @@
+package tpl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"text/template"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// entry is the data available to templates rendered by Render.
+type entry struct {
+	Dir      string
+	DirName  string
+	Path     string
+	Name     string
+	Password string
+	Body     string
+	Content  string
+	// Values holds the first value of every key.
+	Values map[string]string
+	// AllValues holds all values of every key.
+	AllValues map[string][]string
+	// Folder is true if the entry is rendered as part of a whole folder.
+	Folder bool
+}
+
+// Value returns the first value of the first of the given keys the entry
+// has or an empty string.
+func (e entry) Value(keys ...string) string {
+	for _, k := range keys {
+		if v := e.Values[k]; v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func (e entry) url() *url.URL {
+	u, err := url.Parse(e.Value("url"))
+	if err != nil {
+		return &url.URL{}
+	}
+
+	return u
+}
+
+// Host returns the host of the entry, from the host key or the url.
+func (e entry) Host() string {
+	if h := e.Value("host", "hostname", "server"); h != "" {
+		return h
+	}
+
+	return e.url().Hostname()
+}
+
+// Port returns the port of the entry, from the port key or the url.
+func (e entry) Port() string {
+	if p := e.Value("port"); p != "" {
+		return p
+	}
+
+	return e.url().Port()
+}
+
+// Login returns the user name of the entry, from the login, user or username
+// key or the url.
+func (e entry) Login() string {
+	if l := e.Value("login", "user", "username"); l != "" {
+		return l
+	}
+
+	return e.url().User.Username()
+}
+
+// Render renders a decrypted secret through a user template, e.g. to create
+// a kubeconfig or a .netrc stanza from an entry. Unlike Execute the template
+// has access to the parsed secret. Referencing a missing key is an error.
+// folder must be set if the entry is rendered as part of a whole folder.
+func Render(ctx context.Context, tpl, name string, sec gopass.Secret, s kvstore, folder bool) ([]byte, error) {
+	dir := filepath.Dir(name)
+
+	e := entry{
+		Dir:       dir,
+		DirName:   filepath.Base(dir),
+		Path:      name,
+		Name:      filepath.Base(name),
+		Password:  sec.Password(),
+		Body:      sec.Body(),
+		Content:   string(sec.Bytes()),
+		Values:    make(map[string]string, len(sec.Keys())),
+		AllValues: make(map[string][]string, len(sec.Keys())),
+		Folder:    folder,
+	}
+
+	for _, k := range sec.Keys() {
+		vs, found := sec.Values(k)
+		if !found || len(vs) < 1 {
+			continue
+		}
+		e.Values[k] = vs[0]
+		e.AllValues[k] = vs
+	}
+
+	funcs := funcMap(ctx, s)
+	for k, v := range targetFuncs {
+		funcs[k] = v
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, e); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
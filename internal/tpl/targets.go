@@ -0,0 +1,96 @@
+package tpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+// targets are the built-in templates for credential files of classic Unix
+// tools.
+var targets = map[string]string{
+	// ~/.netrc, used by curl, ftp, git and many more.
+	"netrc": `machine {{ netrc (or .Host .Name) }}{{ with .Login }}
+  login {{ netrc . }}{{ end }}
+  password {{ netrc .Password }}
+`,
+	// ~/.pgpass: hostname:port:database:username:password.
+	"pgpass": `{{ pgpass (or .Host "*") }}:{{ pgpass (or .Port "*") }}:{{ pgpass (or (.Value "database" "dbname" "db") "*") }}:{{ pgpass (or .Login "*") }}:{{ pgpass .Password }}
+`,
+	// ~/.my.cnf. Folders get one option group per entry, selected with
+	// mysql --defaults-group-suffix=_<name>.
+	"mysql": `[client{{ if .Folder }}_{{ mycnfGroup .Name }}{{ end }}]
+{{ with .Host }}host={{ mycnf . }}
+{{ end }}{{ with .Port }}port={{ . }}
+{{ end }}{{ with .Login }}user={{ mycnf . }}
+{{ end }}password={{ mycnf .Password }}
+`,
+}
+
+// targetFuncs escape values for the built-in targets.
+var targetFuncs = template.FuncMap{
+	"netrc":      netrcQuote,
+	"pgpass":     pgpassEscape,
+	"mycnf":      mycnfQuote,
+	"mycnfGroup": mycnfGroup,
+}
+
+// Targets returns the names of the built-in render targets.
+func Targets() []string {
+	names := make([]string, 0, len(targets))
+	for k := range targets {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RenderTarget renders a decrypted secret for one of the built-in targets.
+func RenderTarget(ctx context.Context, target, name string, sec gopass.Secret, s kvstore, folder bool) ([]byte, error) {
+	tpl, found := targets[target]
+	if !found {
+		return nil, fmt.Errorf("unknown target %q. Use one of %s", target, strings.Join(Targets(), ", "))
+	}
+
+	return Render(ctx, tpl, name, sec, s, folder)
+}
+
+// netrcQuote quotes tokens containing white space or quotes.
+func netrcQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\"\\") {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// pgpassEscape escapes colons and backslashes.
+func pgpassEscape(s string) string {
+	if s == "*" {
+		return s
+	}
+
+	return strings.NewReplacer(`\`, `\\`, `:`, `\:`).Replace(s)
+}
+
+// mycnfQuote quotes an option value.
+func mycnfQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
+
+// mycnfGroup turns an entry name into an option group suffix.
+func mycnfGroup(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
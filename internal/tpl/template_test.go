@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/fips"
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2i"
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2id"
 	"github.com/gopasspw/gopass/pkg/gopass"
@@ -15,6 +17,7 @@ import (
 	"github.com/jsimonetti/pwscheme/ssha256"
 	"github.com/jsimonetti/pwscheme/ssha512"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Example() { //nolint:testableexamples
@@ -351,3 +354,120 @@ func TestVars(t *testing.T) {
 		})
 	}
 }
+
+func TestFIPSFuncs(t *testing.T) {
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+	kv := kvMock{}
+
+	_, err := Execute(ctx, "{{ .Content | md5sum }}", "testdir", []byte("foobar"), kv)
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Set("", "core.fips", "true"))
+
+	for _, tpl := range []string{"{{ .Content | md5sum }}", "{{ .Content | bcrypt }}", "{{ .Content | argon2id }}"} {
+		_, err := Execute(ctx, tpl, "testdir", []byte("foobar"), kv)
+		assert.ErrorIs(t, err, fips.ErrNotApproved, tpl)
+	}
+
+	buf, err := Execute(ctx, "{{ .Content | sha256sum }}", "testdir", []byte("foobar"), kv)
+	require.NoError(t, err)
+	assert.Equal(t, "c3ab8ff13720e8ad9047dd39466b3c8974e592c2fa383d4a3960714caef0c4f2", string(buf))
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := kvMock{}
+
+	sec, err := secparse.Parse([]byte("s3cret\nhost: db.example.org\nport: 5432\nuser: alice\nalias: a\nalias: b\n"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		tpl  string
+		want string
+	}{
+		{
+			name: "pgpass",
+			tpl:  `{{ .Values.host }}:{{ .Values.port }}:*:{{ .Values.user }}:{{ .Password }}`,
+			want: "db.example.org:5432:*:alice:s3cret",
+		},
+		{
+			name: "path",
+			tpl:  `{{ .Dir }} {{ .Name }} {{ .Path }}`,
+			want: "db/prod pg db/prod/pg",
+		},
+		{
+			name: "all values",
+			tpl:  `{{ join "," .AllValues.alias }}`,
+			want: "a,b",
+		},
+		{
+			name: "funcs",
+			tpl:  `{{ .Password | sha1sum }} {{ getval "foo" "barkey" }}`,
+			want: "fef341f85d87439e7d91a2d465b9871ef66b5e98 barvalue",
+		},
+	} {
+		buf, err := Render(ctx, tc.tpl, "db/prod/pg", sec, kv, false)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.want, string(buf), tc.name)
+	}
+
+	_, err = Render(ctx, `{{ .Values.missing }}`, "db/prod/pg", sec, kv, false)
+	assert.Error(t, err)
+}
+
+func TestRenderTarget(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kv := kvMock{}
+
+	sec, err := secparse.Parse([]byte("pa:s s\"\nurl: https://bob@db.example.org:5433/\ndatabase: app\n"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		target string
+		folder bool
+		want   string
+	}{
+		{
+			target: "netrc",
+			want:   "machine db.example.org\n  login bob\n  password \"pa:s s\\\"\"\n",
+		},
+		{
+			target: "pgpass",
+			want:   "db.example.org:5433:app:bob:pa\\:s s\"\n",
+		},
+		{
+			target: "mysql",
+			want:   "[client]\nhost=\"db.example.org\"\nport=5433\nuser=\"bob\"\npassword=\"pa:s s\\\"\"\n",
+		},
+		{
+			target: "mysql",
+			folder: true,
+			want:   "[client_db_prod]\nhost=\"db.example.org\"\nport=5433\nuser=\"bob\"\npassword=\"pa:s s\\\"\"\n",
+		},
+	} {
+		buf, err := RenderTarget(ctx, tc.target, "dbs/db-prod", sec, kv, tc.folder)
+		require.NoError(t, err, tc.target)
+		assert.Equal(t, tc.want, string(buf), tc.target)
+	}
+
+	// missing fields fall back to the name or wildcards.
+	sec, err = secparse.Parse([]byte("secret\n"))
+	require.NoError(t, err)
+
+	buf, err := RenderTarget(ctx, "netrc", "websites/github.com", sec, kv, false)
+	require.NoError(t, err)
+	assert.Equal(t, "machine github.com\n  password secret\n", string(buf))
+
+	buf, err = RenderTarget(ctx, "pgpass", "websites/github.com", sec, kv, false)
+	require.NoError(t, err)
+	assert.Equal(t, "*:*:*:*:secret\n", string(buf))
+
+	_, err = RenderTarget(ctx, "ini", "foo", sec, kv, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package tree
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// icons maps the names that can be used instead of an emoji to their emoji.
+var icons = map[string]string{
+	"bank":   "🏦",
+	"card":   "💳",
+	"cloud":  "☁️",
+	"code":   "💻",
+	"db":     "🗄️",
+	"game":   "🎮",
+	"home":   "🏠",
+	"key":    "🔑",
+	"lock":   "🔒",
+	"mail":   "📧",
+	"phone":  "📱",
+	"server": "🖥️",
+	"shop":   "🛒",
+	"social": "💬",
+	"star":   "⭐",
+	"web":    "🌐",
+	"wifi":   "📶",
+	"work":   "💼",
+}
+
+// iconNames maps the emojis back to their names.
+var iconNames = func() map[string]string {
+	m := make(map[string]string, len(icons))
+	for k, v := range icons {
+		m[v] = k
+		// the variation selector is optional.
+		m[strings.TrimSuffix(v, "\ufe0f")] = k
+	}
+
+	return m
+}()
+
+// IconNames returns the sorted names that can be used instead of an emoji.
+func IconNames() []string {
+	names := make([]string, 0, len(icons))
+	for k := range icons {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// RenderIcon turns the icon of an entry or folder into its display form. The
+// icon is either a single emoji or one of IconNames. With ascii set it is
+// rendered as a short text label instead, e.g. [key], for terminals or fonts
+// that can not display emojis. Icons are user input, so anything else is only
+// shown as a label and control characters are removed.
+func RenderIcon(icon string, ascii bool) string {
+	icon = strings.TrimSpace(stripControl(icon))
+	if icon == "" {
+		return ""
+	}
+
+	name := strings.ToLower(icon)
+	if n, found := iconNames[icon]; found {
+		name = n
+	}
+
+	if e, found := icons[name]; found {
+		if ascii {
+			return "[" + name + "]"
+		}
+
+		return e
+	}
+
+	if isEmoji(icon) {
+		if ascii {
+			return "[*]"
+		}
+
+		return icon
+	}
+
+	if isASCII(icon) {
+		return "[" + icon + "]"
+	}
+
+	return "[*]"
+}
+
+// stripControl removes control and formatting characters, e.g. escape
+// sequences or bidi overrides. The zero width joiner is kept, it's part of
+// many emojis.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || (unicode.Is(unicode.Cf, r) && r != zwj) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+const zwj = '\u200d'
+
+// isEmoji returns true if s is a single grapheme that starts with a symbol,
+// e.g. 🔑 or 👩‍💻. Modifiers, variation selectors and joiners may follow.
+func isEmoji(s string) bool {
+	cluster, rest, _, _ := uniseg.FirstGraphemeClusterInString(s, -1)
+	if rest != "" {
+		return false
+	}
+
+	for i, r := range cluster {
+		if i == 0 {
+			if !unicode.Is(unicode.So, r) {
+				return false
+			}
+
+			continue
+		}
+
+		if r != zwj && !unicode.In(r, unicode.So, unicode.Sk, unicode.Mn, unicode.Me) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,6 +2,7 @@ package tree
 
 import (
 	"bytes"
+	"fmt"
 )
 
 // Node is a tree node.
@@ -11,7 +12,14 @@ type Node struct {
 	Template bool
 	Mount    bool
 	Path     string
-	Subtree  *Tree
+	Icon     string
+	// Redacted marks folders we can't decrypt.
+	Redacted bool
+	// Hidden is the number of entries removed from a redacted folder.
+	Hidden int
+	// Local marks entries and folders that are never synced.
+	Local   bool
+	Subtree *Tree
 }
 
 const (
@@ -71,6 +79,7 @@ func (n Node) Merge(other Node) *Node {
 		Template: n.Template,
 		Mount:    n.Mount,
 		Path:     n.Path,
+		Icon:     n.Icon,
 		Subtree:  n.Subtree,
 	}
 
@@ -82,6 +91,11 @@ func (n Node) Merge(other Node) *Node {
 		r.Leaf = true
 	}
 
+	// An icon set later replaces an existing one.
+	if other.Icon != "" {
+		r.Icon = other.Icon
+	}
+
 	// If either node has a template the merged has a template, too.
 	if other.Template {
 		r.Template = true
@@ -137,6 +151,10 @@ func (n *Node) format(prefix string, last bool, maxDepth, curDepth int) string {
 		prefix += symVert
 	}
 
+	if n.Icon != "" {
+		_, _ = out.WriteString(n.Icon + " ")
+	}
+
 	// any mount will be colored and include the on-disk path
 	switch {
 	case n.Mount:
@@ -154,6 +172,19 @@ func (n *Node) format(prefix string, last bool, maxDepth, curDepth int) string {
 	if n.Leaf && n.Subtree != nil && !n.Mount {
 		_, _ = out.WriteString(" " + colShadow("(shadowed)"))
 	}
+	// mark redacted folders
+	switch {
+	case n.Redacted && n.Hidden == 1:
+		_, _ = out.WriteString(" " + colRedacted("(1 entry, no access)"))
+	case n.Redacted && n.Hidden > 0:
+		_, _ = out.WriteString(" " + colRedacted(fmt.Sprintf("(%d entries, no access)", n.Hidden)))
+	case n.Redacted:
+		_, _ = out.WriteString(" " + colRedacted("(no access)"))
+	}
+	// mark local-only entries and folders
+	if n.Local {
+		_, _ = out.WriteString(" " + colLocal("(local)"))
+	}
 	// finish this output
 	_, _ = out.WriteString("\n")
 
@@ -183,6 +214,9 @@ func (n *Node) Len() int {
 		l++
 	}
 
+	// entries hidden by redaction still exist
+	l += n.Hidden
+
 	// and for any secret it's subtree might contain
 	for _, t := range n.Subtree.Nodes {
 		l += t.Len()
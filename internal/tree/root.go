@@ -22,6 +22,8 @@ var (
 	colDir      = color.New(color.FgBlue, color.Bold).SprintfFunc()
 	colTpl      = color.New(color.FgGreen, color.Bold).SprintfFunc()
 	colShadow   = color.New(color.FgRed, color.Bold).SprintfFunc()
+	colRedacted = color.New(color.FgYellow).SprintfFunc()
+	colLocal    = color.New(color.FgCyan).SprintfFunc()
 	// sep is intentionally NOT platform-agnostic. This is used for the CLI output
 	// and should always be a regular slash.
 	sep = "/"
@@ -164,6 +166,80 @@ func (r *Root) FindFolder(path string) (*Root, error) {
 	return &Root{Name: r.Name, Subtree: t, Prefix: prefix}, nil
 }
 
+// SetIcon sets the icon of the entry or folder at path.
+func (r *Root) SetIcon(path, icon string) error {
+	node, err := r.findNode(path)
+	if err != nil {
+		return err
+	}
+
+	node.Icon = icon
+
+	return nil
+}
+
+// Redact marks the folder at path as not accessible. If collapse is set the
+// entries of the folder are removed and only their number is shown.
+func (r *Root) Redact(path string, collapse bool) error {
+	node, err := r.findNode(path)
+	if err != nil {
+		return err
+	}
+
+	if node.Subtree == nil {
+		return ErrNotFound
+	}
+
+	node.Redacted = true
+	if collapse {
+		node.Hidden = node.Len()
+		if node.Leaf {
+			node.Hidden--
+		}
+		node.Subtree = NewTree()
+	}
+
+	return nil
+}
+
+// SetLocal marks the entry or folder at path as local-only.
+func (r *Root) SetLocal(path string) error {
+	node, err := r.findNode(path)
+	if err != nil {
+		return err
+	}
+
+	node.Local = true
+
+	return nil
+}
+
+// findNode returns the entry or folder at path.
+func (r *Root) findNode(path string) (*Node, error) {
+	path = strings.TrimSuffix(path, "/")
+	t := r.Subtree
+	p := strings.Split(path, "/")
+
+	for i, e := range p {
+		_, node := t.findPositionFor(e)
+		if node == nil {
+			return nil, ErrNotFound
+		}
+
+		if i == len(p)-1 {
+			return node, nil
+		}
+
+		if node.Subtree == nil {
+			return nil, ErrNotFound
+		}
+
+		t = node.Subtree
+	}
+
+	return nil, ErrNotFound
+}
+
 // SetName changes the name of this tree.
 func (r *Root) SetName(n string) {
 	r.Name = n
@@ -84,3 +84,96 @@ func TestMountShadow(t *testing.T) {
 	_, err := r.FindFolder("mnt/m1")
 	assert.Error(t, err)
 }
+
+func TestIcons(t *testing.T) {
+	t.Parallel()
+
+	color.NoColor = true
+
+	r := New("gopass")
+	assert.NoError(t, r.AddFile("bank/checking", ""))
+	assert.NoError(t, r.AddFile("web/example.org", ""))
+	assert.NoError(t, r.SetIcon("bank/", RenderIcon("bank", false)))
+	assert.NoError(t, r.SetIcon("web/example.org", RenderIcon("🔑", true)))
+	assert.ErrorIs(t, r.SetIcon("web/missing", "x"), ErrNotFound)
+	assert.Equal(t, `gopass
+├── 🏦 bank/
+│   └── checking
+└── web/
+    └── [key] example.org
+`, r.Format(INF))
+
+	for in, want := range map[string]string{
+		"":                      "",
+		"lock":                  "🔒",
+		"Lock":                  "🔒",
+		"🦊":                     "🦊",
+		"👩‍💻":                   "👩‍💻",
+		"custom":                "[custom]",
+		"🦊🦊":                    "[*]",
+		"a\x1b[31mred":          "[a[31mred]",
+		"\u202e🦊":               "🦊",
+		"\x1b]8;;http://x\x07🦊": "[*]",
+		"日本":                    "[*]",
+	} {
+		assert.Equal(t, want, RenderIcon(in, false), in)
+	}
+
+	for in, want := range map[string]string{
+		"lock":   "[lock]",
+		"🔒":      "[lock]",
+		"☁":      "[cloud]",
+		"🦊":      "[*]",
+		"custom": "[custom]",
+	} {
+		assert.Equal(t, want, RenderIcon(in, true), in)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	color.NoColor = true
+
+	r := New("gopass")
+	assert.NoError(t, r.AddFile("ops/db/password", ""))
+	assert.NoError(t, r.AddFile("ops/ssh", ""))
+	assert.NoError(t, r.AddFile("team/wifi", ""))
+	assert.NoError(t, r.AddFile("web/example.org", ""))
+	assert.NoError(t, r.Redact("ops/", true))
+	assert.NoError(t, r.Redact("team", false))
+	assert.ErrorIs(t, r.Redact("web/example.org", false), ErrNotFound)
+	assert.ErrorIs(t, r.Redact("missing/", false), ErrNotFound)
+
+	assert.Equal(t, `gopass
+├── ops/ (2 entries, no access)
+├── team/ (no access)
+│   └── wifi
+└── web/
+    └── example.org
+`, r.Format(INF))
+	assert.Equal(t, []string{"team/wifi", "web/example.org"}, r.List(INF))
+	assert.Equal(t, 4, r.Len())
+}
+
+func TestSetLocal(t *testing.T) {
+	t.Parallel()
+
+	color.NoColor = true
+
+	r := New("gopass")
+	assert.NoError(t, r.AddFile("device/vpn", ""))
+	assert.NoError(t, r.AddFile("web/example.org", ""))
+	assert.NoError(t, r.AddFile("web/laptop", ""))
+	assert.NoError(t, r.SetLocal("device/"))
+	assert.NoError(t, r.SetLocal("web/laptop"))
+	assert.ErrorIs(t, r.SetLocal("missing"), ErrNotFound)
+
+	assert.Equal(t, `gopass
+├── device/ (local)
+│   └── vpn
+└── web/
+    ├── example.org
+    └── laptop (local)
+`, r.Format(INF))
+}
@@ -0,0 +1,203 @@
+// Package updatedb updates the local password rules database (see
+// pwrules.Database) from a signed feed. The feed is a JSON document with a
+// detached, armored GPG signature (<url>.sig) made with one of the gopass
+// release signing keys. Updates are rate limited, so running them
+// automatically doesn't hammer the server.
+package updatedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/network"
+	"github.com/gopasspw/gopass/internal/updater"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+)
+
+const (
+	// DefaultURL is the location of the feed unless updatedb.url is set.
+	DefaultURL = "https://www.gopass.pw/pwrules/pwrules.json"
+	// MinInterval is the minimum time between two checks of the feed.
+	MinInterval = time.Hour
+	// DefaultInterval is the time between automatic updates unless
+	// updatedb.interval is set.
+	DefaultInterval = 7 * 24 * time.Hour
+
+	// maxSize limits the size of the feed.
+	maxSize = 32 << 20
+)
+
+// ErrTooFrequent is returned if the feed was checked less than MinInterval
+// ago.
+var ErrTooFrequent = errors.New("the password rules database was checked less than an hour ago")
+
+// verify checks the signature of the feed. Replaced in tests.
+var verify = updater.VerifySignature
+
+// Result describes the outcome of an update.
+type Result struct {
+	// Updated is false if the local database is already up to date.
+	Updated    bool
+	Generated  time.Time
+	Rules      int
+	Aliases    int
+	ChangeURLs int
+}
+
+// URL returns the location of the feed.
+func URL(ctx context.Context) string {
+	if u := config.String(ctx, "updatedb.url"); u != "" {
+		return u
+	}
+
+	return DefaultURL
+}
+
+// checkedFile records the time of the last check.
+func checkedFile() string {
+	return pwrules.DatabaseFile() + ".checked"
+}
+
+// LastCheck returns the time the feed was checked the last time.
+func LastCheck() time.Time {
+	fi, err := os.Stat(checkedFile())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return fi.ModTime()
+}
+
+// Due returns true if automatic updates are enabled with updatedb.auto and
+// the last check is older than updatedb.interval (in hours).
+func Due(ctx context.Context) bool {
+	if !config.Bool(ctx, "updatedb.auto") || network.Offline(ctx) {
+		return false
+	}
+
+	interval := DefaultInterval
+	if h := config.Int(ctx, "updatedb.interval"); h > 0 {
+		interval = time.Duration(h) * time.Hour
+	}
+	if interval < MinInterval {
+		interval = MinInterval
+	}
+
+	return time.Since(LastCheck()) > interval
+}
+
+// Update fetches the feed, verifies its signature and replaces the local
+// database if the feed is newer. Unless force is set it fails with
+// ErrTooFrequent if the feed was checked less than MinInterval ago.
+func Update(ctx context.Context, force bool) (Result, error) {
+	if !force && time.Since(LastCheck()) < MinInterval {
+		return Result{}, ErrTooFrequent
+	}
+
+	u := URL(ctx)
+	debug.Log("updating password rules from %s", u)
+
+	// record the attempt even if it fails to avoid retrying all the time.
+	if err := touch(checkedFile()); err != nil {
+		debug.Log("failed to record check: %s", err)
+	}
+
+	buf, err := fetch(ctx, u)
+	if err != nil {
+		return Result{}, err
+	}
+
+	sig, err := fetch(ctx, u+".sig")
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to fetch signature: %w", err)
+	}
+
+	if err := verify(buf, sig); err != nil {
+		return Result{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	d, err := pwrules.ParseDatabase(buf)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Generated:  d.Generated,
+		Rules:      len(d.Rules),
+		Aliases:    len(d.Aliases),
+		ChangeURLs: len(d.ChangeURLs),
+	}
+
+	if !d.Generated.After(pwrules.DatabaseGenerated()) {
+		debug.Log("local database from %s is up to date", pwrules.DatabaseGenerated())
+
+		return res, nil
+	}
+
+	fn := pwrules.DatabaseFile()
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return res, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	// write and rename, so readers never see a partial database.
+	tmp := fn + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return res, fmt.Errorf("failed to write database: %w", err)
+	}
+	if err := os.Rename(tmp, fn); err != nil {
+		return res, fmt.Errorf("failed to write database: %w", err)
+	}
+
+	pwrules.SetDatabase(d)
+	res.Updated = true
+
+	return res, nil
+}
+
+func fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := network.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", u, resp.Status)
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	if len(buf) > maxSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", u, maxSize)
+	}
+
+	return buf, nil
+}
+
+func touch(fn string) error {
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(fn, now, now); err == nil {
+		return nil
+	}
+
+	return os.WriteFile(fn, nil, 0o600)
+}
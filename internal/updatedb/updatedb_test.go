@@ -0,0 +1,103 @@
+package updatedb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/updater"
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const feed = `{
+  "generated": "2026-01-02T03:04:05Z",
+  "aliases": [["example.org", "example.net"]],
+  "change-urls": {"example.org": "https://example.org/password"},
+  "rules": {"example.org": {"password-rules": "minlength: 12; maxlength: 16; required: digit;"}}
+}`
+
+func TestUpdate(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+	t.Cleanup(func() {
+		pwrules.SetDatabase(nil)
+	})
+
+	var sig string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pwrules.json":
+			fmt.Fprint(w, feed)
+		case "/pwrules.json.sig":
+			fmt.Fprint(w, sig)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	verify = func(data, s []byte) error {
+		if string(s) != "valid" {
+			return fmt.Errorf("bad signature")
+		}
+
+		return nil
+	}
+	t.Cleanup(func() {
+		verify = updater.VerifySignature
+	})
+
+	cfg := config.NewNoWrites()
+	require.NoError(t, cfg.SetEnv("updatedb.url", ts.URL+"/pwrules.json"))
+	ctx := cfg.WithConfig(context.Background())
+
+	_, found := pwrules.LookupRule(ctx, "example.net")
+	assert.False(t, found)
+
+	sig = "forged"
+	_, err := Update(ctx, false)
+	require.Error(t, err)
+
+	_, err = Update(ctx, false)
+	require.ErrorIs(t, err, ErrTooFrequent)
+
+	sig = "valid"
+	res, err := Update(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, res.Updated)
+	assert.Equal(t, 1, res.Rules)
+
+	r, domain, found := pwrules.LookupRuleDomain(ctx, "example.net")
+	require.True(t, found)
+	assert.Equal(t, "example.org", domain)
+	assert.Equal(t, 12, r.Minlen)
+	assert.Equal(t, "https://example.org/password", pwrules.LookupChangeURL(ctx, "example.net"))
+
+	// the database is stored for other processes.
+	buf, err := os.ReadFile(pwrules.DatabaseFile())
+	require.NoError(t, err)
+	assert.Equal(t, feed, string(buf))
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), pwrules.DatabaseGenerated())
+
+	res, err = Update(ctx, true)
+	require.NoError(t, err)
+	assert.False(t, res.Updated)
+
+	t.Run("auto updates", func(t *testing.T) {
+		assert.False(t, Due(ctx))
+
+		require.NoError(t, cfg.SetEnv("updatedb.auto", "true"))
+		assert.False(t, Due(ctx))
+
+		old := time.Now().Add(-8 * 24 * time.Hour)
+		require.NoError(t, touch(checkedFile()))
+		require.NoError(t, os.Chtimes(checkedFile(), old, old))
+		assert.True(t, Due(ctx))
+	})
+}
@@ -9,7 +9,9 @@ func canWrite(path string) error {
 	return unix.Access(path, unix.W_OK) //nolint:wrapcheck
 }
 
-func removeOldBinary(dir, dest string) error {
+func removeOldBinary(dir, dest string) (string, error) {
 	// no need, os.Rename will replace the destination
-	return nil
+	return "", nil
 }
+
+func restoreOldBinary(bak, dest string) {}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 func canWrite(path string) error {
@@ -18,7 +20,7 @@ func canWrite(path string) error {
 // update to the correct location.
 //
 // See https://stackoverflow.com/a/459860
-func removeOldBinary(dir, dest string) error {
+func removeOldBinary(dir, dest string) (string, error) {
 	bakFile := filepath.Join(dir, filepath.Base(dest)+".bak")
 	// check if the bakup file already exists
 	if _, err := os.Stat(bakFile); err == nil {
@@ -28,8 +30,20 @@ func removeOldBinary(dir, dest string) error {
 	// we can't remove the currently running binary, but should be able to
 	// rename it.
 	if err := os.Rename(dest, bakFile); err != nil {
-		return fmt.Errorf("unable to rename %s to %s: %w", dest, bakFile, err)
+		return "", fmt.Errorf("unable to rename %s to %s: %w", dest, bakFile, err)
 	}
 
-	return nil
+	return bakFile, nil
+}
+
+// restoreOldBinary moves the old binary back in place if the new one could not
+// be moved there. Otherwise there would be no gopass binary left at all.
+func restoreOldBinary(bak, dest string) {
+	if bak == "" {
+		return
+	}
+
+	if err := os.Rename(bak, dest); err != nil {
+		debug.Log("failed to restore %s from %s: %s", dest, bak, err)
+	}
 }
@@ -3,31 +3,21 @@ package updater
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"github.com/cenkalti/backoff"
+	"github.com/gopasspw/gopass/internal/network"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"golang.org/x/net/context/ctxhttp"
 )
 
-var (
-	// DownloadTimeout is the overall timeout for the download, including all retries.
-	DownloadTimeout = time.Minute * 5
-	httpClient      = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				// enforce TLS 1.3
-				MinVersion: tls.VersionTLS13,
-			},
-		},
-	}
-)
+// DownloadTimeout is the overall timeout for the download, including all retries.
+var DownloadTimeout = time.Minute * 5
 
 func tryDownload(ctx context.Context, url string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(ctx, DownloadTimeout)
@@ -65,7 +55,7 @@ func download(ctx context.Context, url string) ([]byte, error) {
 
 	t0 := time.Now()
 
-	resp, err := ctxhttp.Do(ctx, httpClient, req)
+	resp, err := ctxhttp.Do(ctx, network.Client(ctx), req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download %s: %w", url, err)
 	}
@@ -30,18 +30,31 @@ func extractFile(buf []byte, filename, dest string) error {
 		return fmt.Errorf("failed to extract update to %s: %w", dest, err)
 	}
 
-	if err := removeOldBinary(dir, dest); err != nil {
+	if err := os.Chmod(tfn, mode); err != nil {
+		_ = os.Remove(tfn)
+
+		return fmt.Errorf("failed to set mode of %s: %w", tfn, err)
+	}
+
+	bak, err := removeOldBinary(dir, dest)
+	if err != nil {
+		_ = os.Remove(tfn)
+
 		return fmt.Errorf("failed to remove old binary %s: %w", dest, err)
 	}
 
+	// the rename is atomic, so dest is either the old or the new binary.
 	if err := os.Rename(tfn, dest); err != nil {
+		_ = os.Remove(tfn)
+		restoreOldBinary(bak, dest)
+
 		return fmt.Errorf("failed to rename tempfile %s to %s: %w", tfn, dest, err)
 	}
 
-	return os.Chmod(dest, mode)
+	return nil
 }
 
-func extractToTempFile(buf []byte, filename, dest string) (string, error) {
+func extractToTempFile(buf []byte, filename, dest string) (tfn string, err error) {
 	// open a temp file for writing
 	dir := filepath.Dir(dest)
 	dfh, err := os.CreateTemp(dir, "gopass")
@@ -52,6 +65,11 @@ func extractToTempFile(buf []byte, filename, dest string) (string, error) {
 	defer func() {
 		_ = dfh.Sync()
 		_ = dfh.Close()
+
+		// never leave partial binaries behind.
+		if err != nil {
+			_ = os.Remove(dfh.Name())
+		}
 	}()
 
 	var rd io.Reader = bytes.NewReader(buf)
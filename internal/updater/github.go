@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/blang/semver/v4"
+	"github.com/gopasspw/gopass/internal/network"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -75,6 +76,10 @@ func FetchLatestRelease(ctx context.Context) (Release, error) {
 	owner := gitHubOrg
 	repo := gitHubRepo
 
+	if network.Offline(ctx) {
+		return Release{}, fmt.Errorf("%w: network access disabled", network.ErrOffline)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, APITimeout)
 	defer cancel()
 
@@ -88,7 +93,7 @@ func FetchLatestRelease(ctx context.Context) (Release, error) {
 	// pin to API version 3 to avoid breaking our structs
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := ctxhttp.Do(ctx, httpClient, req)
+	resp, err := ctxhttp.Do(ctx, network.Client(ctx), req)
 	if err != nil {
 		return Release{}, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -0,0 +1,121 @@
+package updater
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisign signatures are supported in addition to the GPG signatures, e.g.
+// for mirrors or distributions that re-sign the releases. See
+// https://jedisct1.github.io/minisign/ for the format.
+const (
+	minisignAlg       = "Ed"
+	minisignAlgHashed = "ED"
+	minisignKeyLen    = 2 + 8 + ed25519.PublicKeySize
+	minisignSigLen    = 2 + 8 + ed25519.SignatureSize
+	trustedComment    = "trusted comment: "
+	untrustedComment  = "untrusted comment: "
+)
+
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// parseMinisignKey accepts either the base64 encoded key or the content of a
+// minisign .pub file.
+//
+//nolint:goerr113
+func parseMinisignKey(s string) (minisignKey, error) {
+	var line string
+
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, untrustedComment) {
+			continue
+		}
+
+		line = l
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return minisignKey{}, fmt.Errorf("failed to decode minisign key: %w", err)
+	}
+
+	if len(buf) != minisignKeyLen || string(buf[:2]) != minisignAlg {
+		return minisignKey{}, fmt.Errorf("invalid minisign key")
+	}
+
+	return minisignKey{id: buf[2:10], key: ed25519.PublicKey(buf[10:])}, nil
+}
+
+// minisignVerify checks the minisign signature of data against the given
+// public key.
+//
+//nolint:goerr113
+func minisignVerify(data, sig []byte, pubkey string) error {
+	pk, err := parseMinisignKey(pubkey)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(sig)), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], trustedComment) {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	sb, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return fmt.Errorf("failed to decode minisign signature: %w", err)
+	}
+
+	if len(sb) != minisignSigLen {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	if !bytes.Equal(sb[2:10], pk.id) {
+		return fmt.Errorf("minisign signature was made with key %X, not %X", sb[2:10], pk.id)
+	}
+
+	msg := data
+
+	switch string(sb[:2]) {
+	case minisignAlg:
+	case minisignAlgHashed:
+		h := blake2b.Sum512(data)
+		msg = h[:]
+	default:
+		return fmt.Errorf("unsupported minisign algorithm %q", sb[:2])
+	}
+
+	if !ed25519.Verify(pk.key, msg, sb[10:]) {
+		return fmt.Errorf("minisign signature verification failed")
+	}
+
+	// the global signature covers the trusted comment, e.g. the file name.
+	comment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), trustedComment)
+
+	gs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return fmt.Errorf("failed to decode minisign global signature: %w", err)
+	}
+
+	signed := make([]byte, 0, ed25519.SignatureSize+len(comment))
+	signed = append(signed, sb[10:]...)
+	signed = append(signed, comment...)
+
+	if !ed25519.Verify(pk.key, signed, gs) {
+		return fmt.Errorf("minisign verification of the trusted comment failed")
+	}
+
+	debug.Log("minisign signature OK. Trusted comment: %s", comment)
+
+	return nil
+}
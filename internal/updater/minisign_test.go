@@ -0,0 +1,73 @@
+package updater
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignSign creates a signature in the format written by minisign -S.
+func minisignSign(t *testing.T, priv ed25519.PrivateKey, id []byte, alg string, data []byte, comment string) []byte {
+	t.Helper()
+
+	msg := data
+	if alg == minisignAlgHashed {
+		h := blake2b.Sum512(data)
+		msg = h[:]
+	}
+
+	sig := ed25519.Sign(priv, msg)
+	gs := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+
+	sb := append(append([]byte(alg), id...), sig...)
+
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(sb), comment, base64.StdEncoding.EncodeToString(gs)))
+}
+
+func TestMinisignVerify(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	id := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	key := base64.StdEncoding.EncodeToString(append(append([]byte(minisignAlg), id...), pub...))
+	pubFile := "untrusted comment: minisign public key 0807060504030201\n" + key + "\n"
+
+	for _, alg := range []string{minisignAlg, minisignAlgHashed} {
+		sig := minisignSign(t, priv, id, alg, testData, "timestamp:1690000000\tfile:SHA256SUMS")
+
+		assert.NoError(t, minisignVerify(testData, sig, key), alg)
+		assert.NoError(t, minisignVerify(testData, sig, pubFile), alg)
+		assert.Error(t, minisignVerify([]byte("tampered"), sig, key), alg)
+	}
+
+	t.Run("tampered trusted comment", func(t *testing.T) {
+		t.Parallel()
+
+		sig := minisignSign(t, priv, id, minisignAlgHashed, testData, "file:SHA256SUMS")
+		sig = bytes.Replace(sig, []byte("file:SHA256SUMS"), []byte("file:other"), 1)
+		assert.Error(t, minisignVerify(testData, sig, key))
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		t.Parallel()
+
+		sig := minisignSign(t, priv, []byte{8, 7, 6, 5, 4, 3, 2, 1}, minisignAlg, testData, "")
+		assert.Error(t, minisignVerify(testData, sig, key))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, minisignVerify(testData, nil, "not a key"))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"runtime"
 
 	"github.com/blang/semver/v4"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -46,6 +47,12 @@ func Update(ctx context.Context, currentVersion semver.Version) error {
 		}
 	}
 
+	if config.Bool(ctx, "updater.notifyonly") {
+		out.Noticef(ctx, "gopass %s is available. Download it from https://github.com/%s/%s/releases/tag/%s", rel.Version, gitHubOrg, gitHubRepo, rel.TagName)
+
+		return nil
+	}
+
 	debug.Log("downloading SHA256SUMS ...")
 
 	_, sha256sums, err := downloadAsset(ctx, rel.Assets, "SHA256SUMS")
@@ -73,6 +80,19 @@ func Update(ctx context.Context, currentVersion semver.Version) error {
 
 	debug.Log("GPG signature OK!")
 
+	if key := config.String(ctx, "updater.minisignkey"); key != "" {
+		debug.Log("downloading SHA256SUMS.minisig ...")
+
+		_, msig, err := downloadAsset(ctx, rel.Assets, "SHA256SUMS.minisig")
+		if err != nil {
+			return err
+		}
+
+		if err := minisignVerify(sha256sums, msig, key); err != nil {
+			return fmt.Errorf("minisign verification for SHA256SUMS failed: %w", err)
+		}
+	}
+
 	ext := "tar.gz"
 	if runtime.GOOS == "windows" {
 		ext = "zip"
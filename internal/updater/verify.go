@@ -125,6 +125,14 @@ func (k *krLogger) Str() string {
 	return out.String()[:out.Len()-2]
 }
 
+// VerifySignature checks the armored detached GPG signature of data against
+// the gopass release signing keys.
+func VerifySignature(data, sig []byte) error {
+	_, err := gpgVerify(data, sig)
+
+	return err
+}
+
 func gpgVerify(data, sig []byte) (bool, error) {
 	var keyring openpgp.EntityList
 	for _, pubkey := range pubkeys {
@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -16,6 +17,7 @@ import (
 	rdebug "runtime/debug"
 	"runtime/pprof"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -27,13 +29,17 @@ import (
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	_ "github.com/gopasspw/gopass/internal/backend/storage"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/hook"
+	"github.com/gopasspw/gopass/internal/notify"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
+	"github.com/gopasspw/gopass/internal/sandbox"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/protect"
+	gpwgen "github.com/gopasspw/gopass/pkg/pwgen"
 	"github.com/gopasspw/gopass/pkg/termio"
 	colorable "github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -52,6 +58,11 @@ func main() {
 	// Example: https://go.dev/play/p/8214zCX6hVq.
 	defer writeCPUProfile()()
 
+	// confined child processes are started through gopass itself.
+	if len(os.Args) > 1 && os.Args[1] == sandbox.Arg {
+		sandbox.Main(os.Args[2:])
+	}
+
 	if err := protect.Pledge("stdio rpath wpath cpath tty proc exec"); err != nil {
 		panic(err)
 	}
@@ -73,15 +84,34 @@ func main() {
 		case <-sigChan:
 			cancel()
 		case <-ctx.Done():
+			return
 		}
+
+		// give running operations a chance to clean up, but don't hang if
+		// they don't.
+		fmt.Fprintln(os.Stderr, "Interrupted. Cleaning up ... Press Ctrl+C again to quit immediately")
+		<-sigChan
+		os.Exit(exit.Aborted)
 	}(ctx)
 
 	cli.ErrWriter = errorWriter{ //nolint:reassign
 		out: colorable.NewColorableStderr(),
 	}
+	// -v is used for verbose output
+	cli.VersionFlag = &cli.BoolFlag{ //nolint:reassign
+		Name:  "version",
+		Usage: "print the version",
+	}
 	sv := getVersion()
 	cli.VersionPrinter = makeVersionPrinter(os.Stdout, sv)
 
+	// portable mode must be set up before the config is loaded.
+	if dir := portableDir(os.Args); dir != "" {
+		if err := config.EnablePortable(dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// run the app
 	q := queue.New(ctx)
 	ctx = queue.WithQueue(ctx, q)
@@ -102,6 +132,17 @@ func setupApp(ctx context.Context, sv semver.Version) (context.Context, *cli.App
 	// try to read config (if it exists)
 	cfg := config.New()
 
+	// upgrade old config keys
+	from := cfg.Version()
+	if changes, err := cfg.Migrate(); err != nil {
+		out.Errorf(ctx, "Failed to migrate config: %s", err)
+	} else if len(changes) > 0 {
+		out.Noticef(ctx, "Migrated the gopass config from version %d to %d:", from, cfg.Version())
+		for _, change := range changes {
+			out.Noticef(ctx, "  %s", change)
+		}
+	}
+
 	// set config values
 	ctx = initContext(ctx, cfg)
 
@@ -131,7 +172,21 @@ func setupApp(ctx context.Context, sv semver.Version) (context.Context, *cli.App
 		action.Complete(c)
 	}
 
-	app.Flags = ap.ShowFlags()
+	app.Flags = append(ap.ShowFlags(), &cli.StringFlag{
+		Name:  "portable",
+		Usage: "Keep config, cache, stores and keys below this directory, e.g. on a USB stick. Must be given before the command",
+	}, &cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Generate predictable passwords from this seed. INSECURE, for tests and demos only. Requires " + gpwgen.UnsafeDeterministicEnv + "=1",
+	})
+	app.Before = func(c *cli.Context) error {
+		if err := setSeed(c); err != nil {
+			return err
+		}
+
+		return setVerbosity(ctxutil.WithGlobalFlags(c))
+	}
+	app.After = action.FlushJournal
 	app.Action = func(c *cli.Context) error {
 		if err := action.IsInitialized(c); err != nil {
 			return err
@@ -204,7 +259,7 @@ type pathGetter interface {
 func mkHookFn(hookName, cmdName string, s pathGetter, fn func(c *cli.Context) error) func(c *cli.Context) error {
 	if fn == nil {
 		return func(c *cli.Context) error {
-			dir := config.String(c.Context, "mounts.path")
+			dir := config.FromContext(c.Context).Path()
 
 			return hook.Invoke(c.Context, hookName, dir, cmdName)
 		}
@@ -291,10 +346,91 @@ func (e errorWriter) Write(p []byte) (int, error) {
 	return e.out.Write([]byte("\n" + color.RedString("Error: %s", p))) //nolint:wrapcheck
 }
 
+// jsonErrorWriter prints errors as JSON. Used in quiet mode.
+type jsonErrorWriter struct {
+	out io.Writer
+}
+
+func (e jsonErrorWriter) Write(p []byte) (int, error) {
+	buf, err := json.Marshal(map[string]string{
+		"level":   "error",
+		"message": strings.TrimSpace(string(p)),
+	})
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	if _, err := e.out.Write(append(buf, '\n')); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return len(p), nil
+}
+
+// portableDir returns the value of --portable. The config is loaded before the
+// flags are parsed, so it is picked from the arguments before the command.
+func portableDir(args []string) string {
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return ""
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "portable" {
+			continue
+		}
+
+		if hasValue {
+			return value
+		}
+
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
+
+// setVerbosity applies the process wide effects of -q and -vv.
+func setVerbosity(ctx context.Context) error {
+	switch ctxutil.GetVerbosity(ctx) {
+	case ctxutil.VerbosityQuiet:
+		cli.ErrWriter = jsonErrorWriter{out: os.Stderr} //nolint:reassign
+	case ctxutil.VerbosityDebug:
+		debug.Enable()
+	}
+
+	return nil
+}
+
+// setSeed replaces crypto/rand with a seeded source if --seed was given.
+func setSeed(c *cli.Context) error {
+	if !c.IsSet("seed") {
+		return nil
+	}
+
+	if err := gpwgen.Seed(c.Int64("seed")); err != nil {
+		return fmt.Errorf("can not use --seed: %w", err)
+	}
+
+	out.Warningf(c.Context, "Using a fixed seed. Generated passwords are predictable!")
+
+	return nil
+}
+
 func initContext(ctx context.Context, cfg *config.Config) context.Context {
 	// initialize from config, may be overridden by env vars
 	ctx = cfg.WithConfig(ctx)
 
+	// deliver the events of the store and the commands to webhooks and
+	// desktop notifications.
+	bus := event.New()
+	hook.SubscribeWebhook(bus)
+	notify.Subscribe(bus)
+	ctx = event.WithBus(ctx, bus)
+
 	// always trust
 	ctx = gpg.WithAlwaysTrust(ctx, true)
 
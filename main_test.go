@@ -55,12 +55,22 @@ func TestSetupApp(t *testing.T) {
 // commandsWithError is a list of commands that return an error when
 // invoked without arguments.
 var commandsWithError = set.Map([]string{
+	".add",
+	".admin.revoke-user",
 	".age.identities.add",
 	".age.identities.remove",
 	".alias.add",
 	".alias.remove",
 	".alias.delete",
+	".alias-service.new",
+	".alias-service.setup",
 	".audit",
+	".audit.access",
+	".blueprint.diff",
+	".bundle.ack",
+	".bundle.export",
+	".bundle.import",
+	".bundle.verify",
 	".cat",
 	".clone",
 	".copy",
@@ -71,6 +81,7 @@ var commandsWithError = set.Map([]string{
 	".find",
 	".fscopy",
 	".fsmove",
+	".gc",
 	".generate",
 	".git",
 	".git.push",
@@ -80,24 +91,52 @@ var commandsWithError = set.Map([]string{
 	".git.remote.remove",
 	".grep",
 	".history",
+	".info",
+	".info.edit",
+	".info.remove",
 	".init",
 	".insert",
 	".link",
 	".merge",
+	".mobile",
 	".mounts.add",
 	".mounts.remove",
 	".move",
+	".open",
 	".otp",
+	".otp.export",
+	".otp.import",
+	".policy.import",
+	".policy.remove",
+	".presets.edit",
+	".pwrules.show",
 	".process",
+	".profile.export",
+	".profile.import",
+	".protect",
 	".rcs.status",
 	".recipients.add",
+	".recipients.redact",
 	".recipients.remove",
+	".restructure",
+	".review.approve",
+	".review.reject",
+	".review.show",
+	".rotate",
 	".show",
 	".sum",
+	".tag",
+	".tag.add",
+	".tag.list",
+	".tag.remove",
 	".templates.edit",
 	".templates.remove",
 	".templates.show",
+	".tpm.enroll",
+	".tpm.unenroll",
+	".tpm.unseal",
 	".unclip",
+	".updatedb",
 })
 
 func TestGetCommands(t *testing.T) {
@@ -132,7 +171,7 @@ func TestGetCommands(t *testing.T) {
 	c.Context = ctx
 
 	commands := getCommands(act, app)
-	assert.Equal(t, 41, len(commands))
+	assert.Equal(t, 72, len(commands))
 
 	prefix := ""
 	testCommands(t, c, commands, prefix)
@@ -142,7 +181,9 @@ func testCommands(t *testing.T, c *cli.Context, commands []*cli.Command, prefix
 	t.Helper()
 
 	for _, cmd := range commands {
-		if cmd.Name == "update" {
+		// update and import keychain talk to the outside world, shell
+		// waits for input and bugreport writes to the working directory.
+		if cmd.Name == "update" || cmd.Name == "shell" || cmd.Name == "bugreport" || prefix+"."+cmd.Name == ".import.keychain" {
 			continue
 		}
 
@@ -182,3 +223,20 @@ func TestInitContext(t *testing.T) {
 	ctx = initContext(ctx, cfg)
 	assert.Equal(t, true, gpg.IsAlwaysTrust(ctx))
 }
+
+func TestPortableDir(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"gopass"}, want: ""},
+		{args: []string{"gopass", "--portable", "/media/usb"}, want: "/media/usb"},
+		{args: []string{"gopass", "--portable=/media/usb", "show", "foo"}, want: "/media/usb"},
+		{args: []string{"gopass", "-q", "--portable", "/media/usb", "ls"}, want: "/media/usb"},
+		{args: []string{"gopass", "show", "--portable", "/media/usb"}, want: ""},
+		{args: []string{"gopass", "--", "--portable", "/media/usb"}, want: ""},
+		{args: []string{"gopass", "--portable"}, want: ""},
+	} {
+		assert.Equal(t, tc.want, portableDir(tc.args), "%v", tc.args)
+	}
+}
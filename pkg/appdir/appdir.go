@@ -1,10 +1,15 @@
 // Package appdir implements a customized lookup pattern for application paths
 // like config, cache and data dirs. On Linux this uses the XDG specification,
 // on MacOS and Windows the platform defaults.
+//
+// Every directory can be overridden with an environment variable, e.g.
+// GOPASS_CACHE_DIR. GOPASS_HOMEDIR moves all of them below a single
+// directory, which is useful for tests and portable installs.
 package appdir
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -27,3 +32,67 @@ func UserHome() string {
 
 	return uhd
 }
+
+// UserConfig returns the users config dir.
+func UserConfig() string {
+	if d, ok := override(os.Getenv("GOPASS_CONFIG_DIR"), ".config"); ok {
+		return d
+	}
+
+	return userConfig()
+}
+
+// UserCache returns the users cache dir.
+func UserCache() string {
+	if d, ok := override(os.Getenv("GOPASS_CACHE_DIR"), ".cache"); ok {
+		return d
+	}
+
+	return userCache()
+}
+
+// UserData returns the users data dir.
+func UserData() string {
+	if d, ok := override(os.Getenv("GOPASS_DATA_DIR"), ".local", "share"); ok {
+		return d
+	}
+
+	return userData()
+}
+
+// UserState returns the users state dir, e.g. for histories. Unlike data it
+// is not important enough to be backed up.
+func UserState() string {
+	if d, ok := override(os.Getenv("GOPASS_STATE_DIR"), ".local", "state"); ok {
+		return d
+	}
+
+	return userState()
+}
+
+// UserRuntime returns the dir for sockets and other files that only live as
+// long as the user is logged in.
+func UserRuntime() string {
+	if d, ok := override(os.Getenv("GOPASS_RUNTIME_DIR"), ".cache", "run"); ok {
+		return d
+	}
+
+	return userRuntime()
+}
+
+// override returns dir, the value of a dedicated environment variable, or, if
+// GOPASS_HOMEDIR is set, the XDG style directory below it.
+func override(dir string, homeRel ...string) (string, bool) {
+	if dir != "" {
+		return dir, true
+	}
+
+	hd := os.Getenv("GOPASS_HOMEDIR")
+	if hd == "" {
+		return "", false
+	}
+
+	p := append([]string{hd}, homeRel...)
+
+	return filepath.Join(append(p, Name)...), true
+}
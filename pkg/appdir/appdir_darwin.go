@@ -0,0 +1,56 @@
+//go:build darwin
+// +build darwin
+
+package appdir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// macDir returns the dir below ~/Library. The XDG variable is honored if it
+// is set and the XDG style dir is kept if it exists, since earlier versions
+// used those on MacOS, too.
+func macDir(library, env string, homeRel ...string) string {
+	home := os.Getenv("HOME")
+
+	if base := os.Getenv(env); base != "" {
+		return filepath.Join(base, Name)
+	}
+
+	xdg := filepath.Join(append(append([]string{home}, homeRel...), Name)...)
+	if fi, err := os.Stat(xdg); err == nil && fi.IsDir() {
+		return xdg
+	}
+
+	return filepath.Join(home, "Library", library, Name)
+}
+
+func userConfig() string {
+	return macDir("Application Support", "XDG_CONFIG_HOME", ".config")
+}
+
+func userCache() string {
+	return macDir("Caches", "XDG_CACHE_HOME", ".cache")
+}
+
+func userData() string {
+	return macDir("Application Support", "XDG_DATA_HOME", ".local", "share")
+}
+
+func userState() string {
+	if base := os.Getenv("XDG_STATE_HOME"); base != "" {
+		return filepath.Join(base, Name)
+	}
+
+	return filepath.Join(userData(), "state")
+}
+
+func userRuntime() string {
+	if base := os.Getenv("XDG_RUNTIME_DIR"); base != "" {
+		return filepath.Join(base, Name)
+	}
+
+	// $TMPDIR is per user on MacOS.
+	return filepath.Join(os.TempDir(), Name)
+}
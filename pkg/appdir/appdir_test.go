@@ -1,6 +1,7 @@
 package appdir
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,3 +13,27 @@ func TestUserHome(t *testing.T) {
 
 	assert.Equal(t, td, UserHome())
 }
+
+func TestOverrides(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", td)
+
+	assert.Equal(t, filepath.Join(td, ".config", "gopass"), UserConfig())
+	assert.Equal(t, filepath.Join(td, ".cache", "gopass"), UserCache())
+	assert.Equal(t, filepath.Join(td, ".local", "share", "gopass"), UserData())
+	assert.Equal(t, filepath.Join(td, ".local", "state", "gopass"), UserState())
+	assert.Equal(t, filepath.Join(td, ".cache", "run", "gopass"), UserRuntime())
+
+	// the dedicated variables take precedence.
+	for env, fn := range map[string]func() string{
+		"GOPASS_CONFIG_DIR":  UserConfig,
+		"GOPASS_CACHE_DIR":   UserCache,
+		"GOPASS_DATA_DIR":    UserData,
+		"GOPASS_STATE_DIR":   UserState,
+		"GOPASS_RUNTIME_DIR": UserRuntime,
+	} {
+		dir := filepath.Join(td, env)
+		t.Setenv(env, dir)
+		assert.Equal(t, dir, fn(), env)
+	}
+}
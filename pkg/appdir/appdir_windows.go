@@ -3,30 +3,40 @@ package appdir
 import (
 	"os"
 	"path/filepath"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/sys/windows"
 )
 
-// UserConfig returns the users config dir
-func UserConfig() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".config", Name)
+// knownFolder returns the path of a Windows Known Folder and falls back to
+// the environment variable env.
+func knownFolder(id *windows.KNOWNFOLDERID, env string) string {
+	p, err := windows.KnownFolderPath(id, windows.KF_FLAG_DEFAULT)
+	if err != nil || p == "" {
+		debug.Log("failed to look up known folder, using %%%s%%: %s", env, err)
+
+		return os.Getenv(env)
 	}
 
-	return filepath.Join(os.Getenv("APPDATA"), Name)
+	return p
 }
 
-// UserCache returns the users cache dir
-func UserCache() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".cache", Name)
-	}
+func userConfig() string {
+	return filepath.Join(knownFolder(windows.FOLDERID_RoamingAppData, "APPDATA"), Name)
+}
 
-	return filepath.Join(os.Getenv("LOCALAPPDATA"), Name)
+func userCache() string {
+	return filepath.Join(knownFolder(windows.FOLDERID_LocalAppData, "LOCALAPPDATA"), Name)
 }
 
-// UserData returns the users data dir
-func UserData() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".local", "share", Name)
-	}
-	return filepath.Join(os.Getenv("LOCALAPPDATA"), Name)
+func userData() string {
+	return filepath.Join(knownFolder(windows.FOLDERID_LocalAppData, "LOCALAPPDATA"), Name)
+}
+
+func userState() string {
+	return filepath.Join(userData(), "state")
+}
+
+func userRuntime() string {
+	return filepath.Join(userData(), "run")
 }
@@ -1,51 +1,44 @@
-//go:build !windows
-// +build !windows
+//go:build !windows && !darwin
+// +build !windows,!darwin
 
 package appdir
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
-// UserConfig returns the users config dir.
-func UserConfig() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".config", Name)
-	}
-
-	base := os.Getenv("XDG_CONFIG_HOME")
+func xdgDir(env string, homeRel ...string) string {
+	base := os.Getenv(env)
 	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".config")
+		base = filepath.Join(append([]string{os.Getenv("HOME")}, homeRel...)...)
 	}
 
 	return filepath.Join(base, Name)
 }
 
-// UserCache returns the users cache dir.
-func UserCache() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".cache", Name)
-	}
+func userConfig() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
 
-	base := os.Getenv("XDG_CACHE_HOME")
-	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".cache")
-	}
+func userCache() string {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
 
-	return filepath.Join(base, Name)
+func userData() string {
+	return xdgDir("XDG_DATA_HOME", ".local", "share")
 }
 
-// UserData returns the users data dir.
-func UserData() string {
-	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-		return filepath.Join(hd, ".local", "share", Name)
-	}
+func userState() string {
+	return xdgDir("XDG_STATE_HOME", ".local", "state")
+}
 
-	base := os.Getenv("XDG_DATA_HOME")
-	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".local", "share")
+func userRuntime() string {
+	if base := os.Getenv("XDG_RUNTIME_DIR"); base != "" {
+		return filepath.Join(base, Name)
 	}
 
-	return filepath.Join(base, Name)
+	// keep it short, socket paths are limited to ~100 characters.
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", Name, os.Getuid()))
 }
@@ -1,5 +1,5 @@
-//go:build !windows
-// +build !windows
+//go:build !windows && !darwin
+// +build !windows,!darwin
 
 package appdir
 
@@ -69,3 +69,32 @@ func TestUserData(t *testing.T) {
 		assert.Equal(t, "/home/gopass/.local/share/gopass", UserData())
 	})
 }
+
+func TestUserState(t *testing.T) {
+	ov := gptest.UnsetVars("GOPASS_HOMEDIR", "GOPASS_STATE_DIR", "XDG_STATE_HOME", "HOME")
+	defer ov()
+
+	t.Run("xdg_state_home", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", "/foo/baz/mystate")
+		assert.Equal(t, "/foo/baz/mystate/gopass", UserState())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("HOME", "/home/gopass")
+		assert.Equal(t, "/home/gopass/.local/state/gopass", UserState())
+	})
+}
+
+func TestUserRuntime(t *testing.T) {
+	ov := gptest.UnsetVars("GOPASS_HOMEDIR", "GOPASS_RUNTIME_DIR", "XDG_RUNTIME_DIR")
+	defer ov()
+
+	t.Run("xdg_runtime_dir", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+		assert.Equal(t, "/run/user/1000/gopass", UserRuntime())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		assert.Contains(t, UserRuntime(), "gopass-")
+	})
+}
@@ -33,10 +33,29 @@ func CopyTo(ctx context.Context, name string, content []byte, timeout int) error
 
 			return fmt.Errorf("failed to call clipboard copy command: %w", err)
 		}
-	} else if clipboard.Unsupported {
+	} else if clipboard.Unsupported && !useWayland(ctx) {
 		out.Errorf(ctx, "%s", ErrNotSupported)
 		_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("%s", ErrNotSupported))
 
+		return nil
+	} else if handled, err := checkHygiene(ctx, content); err != nil {
+		_ = notify.Notify(ctx, "gopass - clipboard", "refused to copy to clipboard")
+
+		return err
+	} else if handled {
+		out.Infof(ctx, "✔ Copied %s to the clipboard of the terminal.", color.YellowString(name))
+
+		return nil
+	} else if useWayland(ctx) {
+		// the server clears the clipboard itself once the timeout expires.
+		if err := copyToWayland(ctx, name, content, timeout); err != nil {
+			_ = notify.Notify(ctx, "gopass - clipboard", "failed to write to clipboard")
+
+			return fmt.Errorf("failed to write to clipboard: %w", err)
+		}
+
+		copied(ctx, name, timeout)
+
 		return nil
 	} else if err := copyToClipboard(ctx, content); err != nil {
 		_ = notify.Notify(ctx, "gopass - clipboard", "failed to write to clipboard")
@@ -47,8 +66,7 @@ func CopyTo(ctx context.Context, name string, content []byte, timeout int) error
 	if timeout < 1 {
 		debug.Log("Auto-clear of clipboard disabled.")
 
-		out.Printf(ctx, "✔ Copied %s to clipboard.", color.YellowString(name))
-		_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("✔ Copied %s to clipboard.", name))
+		copied(ctx, name, timeout)
 
 		return nil
 	}
@@ -59,12 +77,23 @@ func CopyTo(ctx context.Context, name string, content []byte, timeout int) error
 		return fmt.Errorf("failed to clear clipboard: %w", err)
 	}
 
-	out.Printf(ctx, "✔ Copied %s to clipboard. Will clear in %d seconds.", color.YellowString(name), timeout)
-	_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("✔ Copied %s to clipboard. Will clear in %d seconds.", name, timeout))
+	copied(ctx, name, timeout)
 
 	return nil
 }
 
+func copied(ctx context.Context, name string, timeout int) {
+	if timeout < 1 {
+		out.Infof(ctx, "✔ Copied %s to clipboard.", color.YellowString(name))
+		_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("✔ Copied %s to clipboard.", name))
+
+		return
+	}
+
+	out.Infof(ctx, "✔ Copied %s to clipboard. Will clear in %d seconds.", color.YellowString(name), timeout)
+	_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("✔ Copied %s to clipboard. Will clear in %d seconds.", name, timeout))
+}
+
 func callCommand(ctx context.Context, cmd string, parameter string, stdinValue []byte) error {
 	clipboardProcess := exec.Command(cmd, parameter)
 	stdin, err := clipboardProcess.StdinPipe()
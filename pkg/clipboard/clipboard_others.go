@@ -4,13 +4,16 @@
 package clipboard
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2id"
@@ -49,6 +52,69 @@ func clear(ctx context.Context, name string, content []byte, timeout int) error
 	return nil
 }
 
+// copyToWayland spawns a copy of gopass in a detached background process
+// group that owns and serves the Wayland clipboard, see Serve. The content
+// is passed on stdin so it never shows up in the environment or the command
+// line. It returns once the clipboard was set.
+func copyToWayland(ctx context.Context, name string, content []byte, timeout int) error {
+	// kill any pending unclip processes, including previous servers
+	_ = killPrecedessors()
+
+	cmd := exec.Command(os.Args[0], "unclip", "--serve", "--timeout", strconv.Itoa(timeout))
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+
+	cmd.Env = append(os.Environ(), "GOPASS_UNCLIP_NAME="+name)
+	if !config.Bool(ctx, "core.notifications") {
+		cmd.Env = append(cmd.Env, "GOPASS_NO_NOTIFY=true")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to invoke unclip: %w", err)
+	}
+
+	if _, err := stdin.Write(content); err != nil {
+		return fmt.Errorf("failed to write to STDIN: %w", err)
+	}
+
+	if err := stdin.Close(); err != nil {
+		return fmt.Errorf("failed to close STDIN: %w", err)
+	}
+
+	ready := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(stdout).ReadString('\n')
+		ready <- strings.TrimSpace(line)
+	}()
+
+	select {
+	case line := <-ready:
+		if line != "ready" {
+			_ = cmd.Wait()
+
+			return fmt.Errorf("failed to serve clipboard")
+		}
+	case <-time.After(serveTimeout):
+		killProc(cmd.Process.Pid)
+
+		return fmt.Errorf("timed out waiting for the clipboard")
+	}
+
+	// the server keeps running in the background.
+	return cmd.Process.Release()
+}
+
 func walkFn(pid int, killFn func(int)) {
 	// read the commandline for this process
 	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
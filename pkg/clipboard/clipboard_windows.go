@@ -32,4 +32,8 @@ func clear(ctx context.Context, name string, content []byte, timeout int) error
 	return cmd.Start()
 }
 
+func copyToWayland(context.Context, string, []byte, int) error {
+	return ErrNotSupported
+}
+
 func walkFn(int, func(int)) {}
@@ -0,0 +1,146 @@
+package clipboard
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Clipboard hygiene policies, see clipboard.hygiene.
+const (
+	// HygieneWarn copies to the clipboard but warns about possible leaks.
+	HygieneWarn = "warn"
+	// HygieneRefuse refuses to copy to the clipboard if it might leak.
+	HygieneRefuse = "refuse"
+	// HygieneOSC52 copies to the clipboard of the local terminal using
+	// the OSC 52 escape sequence if the system clipboard might leak.
+	HygieneOSC52 = "osc52"
+	// HygieneOff disables all checks.
+	HygieneOff = "off"
+)
+
+// ErrClipboardLeak is returned if copying to the clipboard was refused.
+var ErrClipboardLeak = fmt.Errorf("refusing to copy to a clipboard that might leak. Use -f to print to the console or set clipboard.hygiene to osc52")
+
+// knownManagers are clipboard managers that keep a history of the clipboard.
+var knownManagers = map[string]string{
+	"cliphist":      "cliphist",
+	"clipit":        "ClipIt",
+	"clipman":       "clipman",
+	"copyq":         "CopyQ",
+	"diodon":        "Diodon",
+	"gpaste-daemon": "GPaste",
+	"greenclip":     "greenclip",
+	"klipper":       "Klipper",
+	"parcellite":    "Parcellite",
+	"xfce4-clipman": "Clipman",
+}
+
+// listProcesses is a variable so tests can override it.
+var listProcesses = processNames
+
+// Risks returns a list of reasons why the content copied to the clipboard
+// might leak, e.g. to a clipboard manager or to a remote X11 display.
+func Risks() []string {
+	var risks []string
+
+	display := os.Getenv("DISPLAY")
+	ssh := os.Getenv("SSH_CONNECTION") != ""
+	if !ssh {
+		ssh = os.Getenv("SSH_CLIENT") != ""
+	}
+	if r := remoteDisplay(display, ssh); r != "" {
+		risks = append(risks, r)
+	}
+
+	seen := make(map[string]bool, len(knownManagers))
+	for _, p := range listProcesses() {
+		name, found := knownManagers[strings.ToLower(p)]
+		if !found || seen[name] {
+			continue
+		}
+		seen[name] = true
+		risks = append(risks, fmt.Sprintf("clipboard manager %s is running", name))
+	}
+
+	debug.Log("clipboard risks: %+v", risks)
+
+	return risks
+}
+
+// remoteDisplay returns a description if the X11 display is on another host
+// or forwarded through SSH.
+func remoteDisplay(display string, ssh bool) string {
+	if display == "" {
+		return ""
+	}
+
+	host, num, found := strings.Cut(display, ":")
+	if !found {
+		return ""
+	}
+
+	if host != "" && host != "unix" && host != "localhost" {
+		return fmt.Sprintf("X11 display %s is on a remote host", display)
+	}
+
+	// sshd uses display numbers starting at 10 for forwarded displays.
+	if n, err := strconv.Atoi(strings.Split(num, ".")[0]); ssh && err == nil && (n >= 10 || host == "localhost") {
+		return fmt.Sprintf("X11 display %s is forwarded through SSH", display)
+	}
+
+	return ""
+}
+
+// checkHygiene applies the configured clipboard hygiene policy. It returns
+// true if the content was already handled (e.g. by OSC 52) and must not be
+// copied to the system clipboard.
+func checkHygiene(ctx context.Context, content []byte) (bool, error) {
+	policy := config.String(ctx, "clipboard.hygiene")
+	if policy == "" {
+		policy = HygieneWarn
+	}
+
+	if policy == HygieneOff {
+		return false, nil
+	}
+
+	risks := Risks()
+	if len(risks) < 1 {
+		return false, nil
+	}
+
+	switch policy {
+	case HygieneRefuse:
+		for _, r := range risks {
+			out.Errorf(ctx, "Clipboard might leak: %s", r)
+		}
+
+		return false, ErrClipboardLeak
+	case HygieneOSC52:
+		debug.Log("using OSC 52 instead of the system clipboard: %+v", risks)
+
+		return true, copyOSC52(content)
+	default:
+		for _, r := range risks {
+			out.Warningf(ctx, "Clipboard might leak: %s", r)
+		}
+		out.Warningf(ctx, "Use -f to print to the console or set clipboard.hygiene to osc52 or refuse")
+
+		return false, nil
+	}
+}
+
+// copyOSC52 asks the (local) terminal emulator to set its clipboard.
+func copyOSC52(content []byte) error {
+	_, err := fmt.Fprintf(out.Stderr, "\033]52;c;%s\a", base64.StdEncoding.EncodeToString(content))
+
+	return err
+}
@@ -0,0 +1,91 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteDisplay(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		display string
+		ssh     bool
+		remote  bool
+	}{
+		{display: ""},
+		{display: ":0"},
+		{display: ":0", ssh: true},
+		{display: ":1.0"},
+		{display: "unix:0"},
+		{display: "localhost:10.0"},
+		{display: "localhost:10.0", ssh: true, remote: true},
+		{display: ":10", ssh: true, remote: true},
+		{display: "10.0.0.1:0", remote: true},
+		{display: "foo.example.org:0", remote: true},
+	} {
+		assert.Equal(t, tc.remote, remoteDisplay(tc.display, tc.ssh) != "", tc.display)
+	}
+}
+
+func TestRisks(t *testing.T) {
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("SSH_CONNECTION", "")
+	t.Setenv("SSH_CLIENT", "")
+
+	oldList := listProcesses
+	defer func() { listProcesses = oldList }()
+
+	listProcesses = func() []string { return []string{"bash", "klipper", "copyq", "copyq"} }
+	assert.Equal(t, []string{"clipboard manager Klipper is running", "clipboard manager CopyQ is running"}, Risks())
+
+	listProcesses = func() []string { return []string{"bash"} }
+	assert.Empty(t, Risks())
+}
+
+func TestCheckHygiene(t *testing.T) {
+	t.Setenv("DISPLAY", "remote.example.org:0")
+
+	oldList := listProcesses
+	defer func() { listProcesses = oldList }()
+	listProcesses = func() []string { return nil }
+
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() { out.Stderr = os.Stderr }()
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	// default: warn.
+	handled, err := checkHygiene(ctx, []byte("secret"))
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Contains(t, buf.String(), "Clipboard might leak")
+	buf.Reset()
+
+	require.NoError(t, cfg.Set("", "clipboard.hygiene", HygieneRefuse))
+	_, err = checkHygiene(ctx, []byte("secret"))
+	require.ErrorIs(t, err, ErrClipboardLeak)
+	buf.Reset()
+
+	require.NoError(t, cfg.Set("", "clipboard.hygiene", HygieneOSC52))
+	handled, err = checkHygiene(ctx, []byte("secret"))
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, "\033]52;c;c2VjcmV0\a", buf.String())
+	buf.Reset()
+
+	require.NoError(t, cfg.Set("", "clipboard.hygiene", HygieneOff))
+	handled, err = checkHygiene(ctx, []byte("secret"))
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !linux && !solaris && !windows && !freebsd
+// +build !darwin,!linux,!solaris,!windows,!freebsd
+
+package clipboard
+
+func processNames() []string {
+	return nil
+}
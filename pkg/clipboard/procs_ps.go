@@ -0,0 +1,26 @@
+//go:build darwin || (freebsd && amd64) || linux || solaris || windows || (freebsd && arm) || (freebsd && arm64)
+// +build darwin freebsd,amd64 linux solaris windows freebsd,arm freebsd,arm64
+
+package clipboard
+
+import (
+	"github.com/gopasspw/gopass/pkg/debug"
+	ps "github.com/mitchellh/go-ps"
+)
+
+// processNames returns the executable names of all running processes.
+func processNames() []string {
+	procs, err := ps.Processes()
+	if err != nil {
+		debug.Log("failed to list processes: %s", err)
+
+		return nil
+	}
+
+	names := make([]string, 0, len(procs))
+	for _, proc := range procs {
+		names = append(names, proc.Executable())
+	}
+
+	return names
+}
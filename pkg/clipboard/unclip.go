@@ -26,6 +26,10 @@ func Clear(ctx context.Context, name string, checksum string, force bool) error
 		return nil
 	}
 
+	if useWayland(ctx) {
+		return clearWayland(ctx, checksum, force)
+	}
+
 	if clipboard.Unsupported {
 		return ErrNotSupported
 	}
@@ -0,0 +1,118 @@
+package clipboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/notify"
+	"github.com/gopasspw/gopass/internal/pwschemes/argon2id"
+	"github.com/gopasspw/gopass/pkg/clipboard/wayland"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// serveTimeout is how long we wait for the clipboard server to take over the
+// clipboard.
+const serveTimeout = 5 * time.Second
+
+// useWayland returns true if the clipboard should be accessed through the
+// native Wayland data control protocol instead of wl-clipboard.
+func useWayland(ctx context.Context) bool {
+	if runtime.GOOS != "linux" || os.Getenv("WAYLAND_DISPLAY") == "" {
+		return false
+	}
+
+	switch config.String(ctx, "clipboard.wayland") {
+	case "native":
+		return true
+	case "wl-clipboard":
+		return false
+	}
+
+	// prefer wl-clipboard if it's installed.
+	_, err := exec.LookPath("wl-copy")
+
+	return err != nil
+}
+
+// Serve copies the content to the Wayland clipboard and serves it until it's
+// replaced, pasted once if clipboard.paste-once is set, or the timeout
+// expires. The latter two clear the clipboard. A compositor only serves the
+// clipboard as long as the client owning it is connected, so this blocks.
+// It's called by the unclip command in the background, see copyToWayland.
+func Serve(ctx context.Context, name string, content []byte, timeout int, ready func()) error {
+	opts := wayland.Options{
+		Primary:   config.Bool(ctx, "clipboard.primary"),
+		PasteOnce: config.Bool(ctx, "clipboard.paste-once"),
+		Timeout:   time.Duration(timeout) * time.Second,
+	}
+
+	cleared, err := wayland.Serve(content, opts, ready)
+	if err != nil {
+		return fmt.Errorf("failed to serve clipboard: %w", err)
+	}
+
+	if !cleared {
+		debug.Log("clipboard was replaced, not clearing it")
+
+		return nil
+	}
+
+	if err := clearClipboardHistory(ctx); err != nil {
+		_ = notify.Notify(ctx, "gopass - clipboard", "Failed to clear clipboard history")
+
+		return fmt.Errorf("failed to clear clipboard history: %w", err)
+	}
+
+	if err := notify.Notify(ctx, "gopass - clipboard", "Clipboard has been cleared"); err != nil {
+		return fmt.Errorf("failed to send unclip notification: %w", err)
+	}
+
+	debug.Log("clipboard cleared (%s)", name)
+
+	return nil
+}
+
+// clearWayland clears the Wayland clipboard if it still contains the data
+// matching the checksum.
+func clearWayland(ctx context.Context, checksum string, force bool) error {
+	cur, err := wayland.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read clipboard: %w", err)
+	}
+
+	match, err := argon2id.Validate(string(cur), checksum)
+	if err != nil {
+		debug.Log("failed to validate checksum %s: %s", checksum, err)
+
+		return nil
+	}
+
+	if !match && !force {
+		return nil
+	}
+
+	if err := wayland.Clear(config.Bool(ctx, "clipboard.primary")); err != nil {
+		_ = notify.Notify(ctx, "gopass - clipboard", "Failed to clear clipboard")
+
+		return fmt.Errorf("failed to clear clipboard: %w", err)
+	}
+
+	if err := clearClipboardHistory(ctx); err != nil {
+		_ = notify.Notify(ctx, "gopass - clipboard", "Failed to clear clipboard history")
+
+		return fmt.Errorf("failed to clear clipboard history: %w", err)
+	}
+
+	if err := notify.Notify(ctx, "gopass - clipboard", "Clipboard has been cleared"); err != nil {
+		return fmt.Errorf("failed to send unclip notification: %w", err)
+	}
+
+	debug.Log("clipboard cleared (%s)", checksum)
+
+	return nil
+}
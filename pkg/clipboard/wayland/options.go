@@ -0,0 +1,28 @@
+// Package wayland implements copying to and clearing the clipboard of Wayland
+// compositors without wl-clipboard. It speaks the data control protocol
+// (ext-data-control-v1 or wlr-data-control-unstable-v1) that is meant for
+// clipboard managers and is supported by most compositors except GNOME.
+package wayland
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned if there is no Wayland compositor or if it
+// doesn't support the data control protocol.
+var ErrNotSupported = errors.New("the Wayland compositor doesn't support the data control protocol")
+
+// Options control how the content is served.
+type Options struct {
+	// Primary sets the primary selection, too.
+	Primary bool
+	// PasteOnce clears the clipboard after the content was pasted once.
+	// Wayland doesn't tell who reads the clipboard, so a clipboard manager
+	// that ignores the password manager hint and reads every new selection
+	// counts as a paste, too.
+	PasteOnce bool
+	// Timeout clears the clipboard once it expires. Zero serves the content
+	// until it's replaced.
+	Timeout time.Duration
+}
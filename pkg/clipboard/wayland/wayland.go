@@ -0,0 +1,526 @@
+//go:build linux
+// +build linux
+
+package wayland
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// displayID is the object ID of the wl_display singleton.
+const displayID = 1
+
+// Opcodes of the requests and events used. The ext and the wlr variant of
+// the data control protocol share them.
+const (
+	displaySync        = 0
+	displayGetRegistry = 1
+	displayEventError  = 0
+
+	registryBind        = 0
+	registryEventGlobal = 0
+
+	callbackEventDone = 0
+
+	managerCreateDataSource = 0
+	managerGetDataDevice    = 1
+
+	deviceSetSelection          = 0
+	deviceSetPrimarySelection   = 2
+	deviceEventDataOffer        = 0
+	deviceEventSelection        = 1
+	deviceEventFinished         = 2
+	deviceEventPrimarySelection = 3
+
+	sourceOffer          = 0
+	sourceDestroy        = 1
+	sourceEventSend      = 0
+	sourceEventCancelled = 1
+
+	offerReceive    = 0
+	offerDestroy    = 1
+	offerEventOffer = 0
+)
+
+// roundtripTimeout is how long we wait for the compositor to respond.
+const roundtripTimeout = 5 * time.Second
+
+// managers are the supported data control managers, the preferred first.
+var managers = []struct {
+	name    string
+	version uint32
+	// primary is the first version supporting the primary selection.
+	primary uint32
+}{
+	{name: "ext_data_control_manager_v1", version: 1, primary: 1},
+	{name: "zwlr_data_control_manager_v1", version: 2, primary: 2},
+}
+
+// mimeTypes are offered for the content, the preferred first.
+var mimeTypes = []string{
+	"text/plain;charset=utf-8",
+	"text/plain",
+	"UTF8_STRING",
+	"STRING",
+	"TEXT",
+}
+
+// passwordHint asks clipboard managers like Klipper to not record the
+// content in their history.
+const passwordHint = "x-kde-passwordManagerHint"
+
+type global struct {
+	name    uint32
+	version uint32
+}
+
+// client is a data control client of the seat of the compositor.
+type client struct {
+	*conn
+	registry uint32
+	manager  uint32
+	device   uint32
+	// primary is true if the manager supports the primary selection.
+	primary bool
+	// offers are the mime types of the data offers announced by the
+	// compositor.
+	offers           map[uint32][]string
+	selection        uint32
+	primarySelection uint32
+}
+
+// dialer connects to the compositor. It's a variable so tests can replace it.
+var dialer = dial
+
+func connect() (*client, error) {
+	c, err := dialer()
+	if err != nil {
+		return nil, err
+	}
+
+	cl := &client{conn: c, offers: map[uint32][]string{}}
+	if err := cl.init(); err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+
+	return cl, nil
+}
+
+// init binds the seat and the data control manager and creates the data
+// control device of the seat.
+func (c *client) init() error {
+	c.registry = c.newID()
+	if err := c.send(displayID, displayGetRegistry, args{}.uint(c.registry)); err != nil {
+		return err
+	}
+
+	globals := map[string]global{}
+	if err := c.roundtrip(func(m message) error {
+		if m.object != c.registry || m.opcode != registryEventGlobal {
+			return nil
+		}
+
+		d := &decoder{buf: m.args}
+		g := global{name: d.uint()}
+		iface := d.string()
+		g.version = d.uint()
+		if _, found := globals[iface]; d.err == nil && !found {
+			globals[iface] = g
+		}
+
+		return d.err
+	}); err != nil {
+		return err
+	}
+
+	g, found := globals["wl_seat"]
+	if !found {
+		return fmt.Errorf("no seat: %w", ErrNotSupported)
+	}
+
+	seat, err := c.bind(g, "wl_seat", 1)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range managers {
+		g, found := globals[m.name]
+		if !found {
+			continue
+		}
+
+		version := m.version
+		if g.version < version {
+			version = g.version
+		}
+
+		debug.Log("using %s version %d", m.name, version)
+		if c.manager, err = c.bind(g, m.name, version); err != nil {
+			return err
+		}
+		c.primary = version >= m.primary
+
+		break
+	}
+
+	if c.manager == 0 {
+		return ErrNotSupported
+	}
+
+	c.device = c.newID()
+
+	return c.send(c.manager, managerGetDataDevice, args{}.uint(c.device).uint(seat))
+}
+
+func (c *client) bind(g global, iface string, version uint32) (uint32, error) {
+	id := c.newID()
+
+	return id, c.send(c.registry, registryBind, args{}.uint(g.name).string(iface).uint(version).uint(id))
+}
+
+// roundtrip waits until the compositor processed all requests sent so far.
+// The events received in the meantime are dispatched.
+func (c *client) roundtrip(handle func(message) error) error {
+	cb := c.newID()
+	if err := c.send(displayID, displaySync, args{}.uint(cb)); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(roundtripTimeout)
+	for {
+		m, err := c.next(deadline)
+		if err != nil {
+			return err
+		}
+
+		if m.object == cb && m.opcode == callbackEventDone {
+			return nil
+		}
+
+		if err := c.dispatch(m, handle); err != nil {
+			return err
+		}
+	}
+}
+
+// dispatch handles the events of the display, the device and the data offers
+// and passes everything else to handle.
+func (c *client) dispatch(m message, handle func(message) error) error {
+	d := &decoder{buf: m.args}
+
+	switch {
+	case m.object == displayID:
+		if m.opcode != displayEventError {
+			return nil
+		}
+
+		obj, code, msg := d.uint(), d.uint(), d.string()
+
+		return fmt.Errorf("compositor error %d on object %d: %s", code, obj, msg)
+	case m.object == c.device && c.device != 0:
+		switch m.opcode {
+		case deviceEventDataOffer:
+			c.offers[d.uint()] = nil
+		case deviceEventSelection:
+			c.selection = d.uint()
+			c.dropOffers()
+		case deviceEventPrimarySelection:
+			c.primarySelection = d.uint()
+			c.dropOffers()
+		case deviceEventFinished:
+			return fmt.Errorf("the data control device is no longer valid")
+		}
+
+		return d.err
+	}
+
+	if mimes, found := c.offers[m.object]; found {
+		if m.opcode == offerEventOffer {
+			c.offers[m.object] = append(mimes, d.string())
+		}
+
+		return d.err
+	}
+
+	if handle == nil {
+		return nil
+	}
+
+	return handle(m)
+}
+
+// dropOffers destroys the data offers that are neither the selection nor the
+// primary selection.
+func (c *client) dropOffers() {
+	for id := range c.offers {
+		if id == c.selection || id == c.primarySelection {
+			continue
+		}
+
+		if err := c.send(id, offerDestroy, nil); err != nil {
+			debug.Log("failed to destroy offer %d: %s", id, err)
+		}
+		delete(c.offers, id)
+	}
+}
+
+// newSource creates a data source offering the content as text.
+func (c *client) newSource() (uint32, error) {
+	src := c.newID()
+	if err := c.send(c.manager, managerCreateDataSource, args{}.uint(src)); err != nil {
+		return 0, err
+	}
+
+	for _, mt := range append(mimeTypes, passwordHint) {
+		if err := c.send(src, sourceOffer, args{}.string(mt)); err != nil {
+			return 0, err
+		}
+	}
+
+	return src, nil
+}
+
+// setSelection sets the selection or the primary selection to the source.
+// Source 0 clears it.
+func (c *client) setSelection(primary bool, src uint32) error {
+	if primary {
+		return c.send(c.device, deviceSetPrimarySelection, args{}.uint(src))
+	}
+
+	return c.send(c.device, deviceSetSelection, args{}.uint(src))
+}
+
+// Serve copies the content to the clipboard and serves it to other clients
+// until it's replaced, the timeout expires or, with PasteOnce, it was pasted
+// once. The clipboard is cleared in the latter cases and Serve returns true.
+// Ready is called as soon as the clipboard was set.
+func Serve(content []byte, opts Options, ready func()) (bool, error) {
+	c, err := connect()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	// the sources we own mapped to whether they serve the primary
+	// selection.
+	sources := map[uint32]bool{}
+
+	kinds := []bool{false}
+	if opts.Primary {
+		if c.primary {
+			kinds = append(kinds, true)
+		} else {
+			debug.Log("the compositor doesn't support the primary selection")
+		}
+	}
+
+	for _, primary := range kinds {
+		// a source can only be used for one selection.
+		src, err := c.newSource()
+		if err != nil {
+			return false, err
+		}
+		sources[src] = primary
+
+		if err := c.setSelection(primary, src); err != nil {
+			return false, err
+		}
+	}
+
+	var pasted bool
+	handle := func(m message) error {
+		if _, found := sources[m.object]; !found {
+			return nil
+		}
+
+		switch m.opcode {
+		case sourceEventSend:
+			d := &decoder{buf: m.args}
+			mime := d.string()
+
+			fd, err := c.takeFD()
+			if err != nil {
+				return err
+			}
+
+			if mime == passwordHint {
+				writeFD(fd, []byte("secret"))
+
+				return nil
+			}
+
+			debug.Log("serving the clipboard as %s", mime)
+			writeFD(fd, content)
+			pasted = true
+		case sourceEventCancelled:
+			debug.Log("the clipboard was replaced")
+			delete(sources, m.object)
+
+			return c.send(m.object, sourceDestroy, nil)
+		}
+
+		return nil
+	}
+
+	if err := c.roundtrip(handle); err != nil {
+		return false, err
+	}
+
+	if ready != nil {
+		ready()
+	}
+
+	var deadline time.Time
+	if opts.Timeout > 0 {
+		deadline = time.Now().Add(opts.Timeout)
+	}
+
+	for len(sources) > 0 {
+		if pasted && opts.PasteOnce {
+			debug.Log("pasted once, clearing the clipboard")
+
+			return true, c.clear(sources)
+		}
+
+		m, err := c.next(deadline)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			debug.Log("timeout expired, clearing the clipboard")
+
+			return true, c.clear(sources)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if err := c.dispatch(m, handle); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func writeFD(fd int, content []byte) {
+	f := os.NewFile(uintptr(fd), "wayland-paste")
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := f.Write(content); err != nil {
+		debug.Log("failed to serve the clipboard: %s", err)
+	}
+}
+
+// clear clears the selections still served by our sources.
+func (c *client) clear(sources map[uint32]bool) error {
+	for src, primary := range sources {
+		if err := c.setSelection(primary, 0); err != nil {
+			return err
+		}
+
+		if err := c.send(src, sourceDestroy, nil); err != nil {
+			return err
+		}
+	}
+
+	return c.roundtrip(nil)
+}
+
+// Read returns the text in the clipboard. It returns nil if the clipboard is
+// empty or doesn't hold text.
+func Read() ([]byte, error) {
+	c, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	// the compositor announces the current selection right away.
+	if err := c.roundtrip(nil); err != nil {
+		return nil, err
+	}
+
+	if c.selection == 0 {
+		return nil, nil
+	}
+
+	mime := pickMimeType(c.offers[c.selection])
+	if mime == "" {
+		debug.Log("the clipboard doesn't hold text: %q", c.offers[c.selection])
+
+		return nil, nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	err = c.send(c.selection, offerReceive, args{}.string(mime), int(w.Fd()))
+	// the source writes into the pipe, we keep just the reading end.
+	_ = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.SetReadDeadline(time.Now().Add(roundtripTimeout)); err != nil {
+		debug.Log("failed to set deadline: %s", err)
+	}
+
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the clipboard: %w", err)
+	}
+
+	return buf, nil
+}
+
+func pickMimeType(offered []string) string {
+	for _, mt := range mimeTypes {
+		for _, o := range offered {
+			if o == mt {
+				return mt
+			}
+		}
+	}
+
+	return ""
+}
+
+// Clear clears the clipboard and optionally the primary selection.
+func Clear(primary bool) error {
+	c, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	if err := c.setSelection(false, 0); err != nil {
+		return err
+	}
+
+	if primary && c.primary {
+		if err := c.setSelection(true, 0); err != nil {
+			return err
+		}
+	}
+
+	return c.roundtrip(nil)
+}
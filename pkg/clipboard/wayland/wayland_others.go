@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package wayland
+
+// Serve is not supported on this platform.
+func Serve(content []byte, opts Options, ready func()) (bool, error) {
+	return false, ErrNotSupported
+}
+
+// Read is not supported on this platform.
+func Read() ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// Clear is not supported on this platform.
+func Clear(primary bool) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,271 @@
+//go:build linux
+// +build linux
+
+package wayland
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCompositor implements just enough of a compositor with the wlr data
+// control protocol to test the client.
+type fakeCompositor struct {
+	t *testing.T
+	c *conn
+
+	registry uint32
+	manager  uint32
+	device   uint32
+
+	mu      sync.Mutex
+	sources map[uint32][]string
+
+	// selection is the text held by the clipboard when the client
+	// connects.
+	selection string
+	// pasted receives the content read from the client.
+	pasted chan string
+	// selections receives the sources the client sets the selection to.
+	selections chan uint32
+}
+
+func newFakeCompositor(t *testing.T, selection string) *fakeCompositor {
+	t.Helper()
+
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	require.NoError(t, err)
+
+	f := &fakeCompositor{
+		t:          t,
+		c:          newConn(unixConn(t, fds[0])),
+		selection:  selection,
+		sources:    map[uint32][]string{},
+		pasted:     make(chan string, 10),
+		selections: make(chan uint32, 10),
+	}
+
+	client := newConn(unixConn(t, fds[1]))
+	dialer = func() (*conn, error) {
+		return client, nil
+	}
+	t.Cleanup(func() {
+		dialer = dial
+		_ = f.c.Close()
+	})
+
+	go f.run()
+
+	return f
+}
+
+func unixConn(t *testing.T, fd int) *net.UnixConn {
+	t.Helper()
+
+	f := os.NewFile(uintptr(fd), "socket")
+	c, err := net.FileConn(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	uc, ok := c.(*net.UnixConn)
+	require.True(t, ok)
+
+	return uc
+}
+
+func (f *fakeCompositor) run() {
+	for {
+		m, err := f.c.next(time.Time{})
+		if err != nil {
+			return
+		}
+
+		if err := f.handle(m); err != nil {
+			f.t.Errorf("fake compositor: %s", err)
+
+			return
+		}
+	}
+}
+
+func (f *fakeCompositor) handle(m message) error {
+	d := &decoder{buf: m.args}
+
+	switch {
+	case m.object == displayID && m.opcode == displayGetRegistry:
+		f.registry = d.uint()
+		if err := f.c.send(f.registry, registryEventGlobal, args{}.uint(1).string("wl_seat").uint(7)); err != nil {
+			return err
+		}
+
+		return f.c.send(f.registry, registryEventGlobal, args{}.uint(2).string("zwlr_data_control_manager_v1").uint(2))
+	case m.object == displayID && m.opcode == displaySync:
+		return f.c.send(d.uint(), callbackEventDone, args{}.uint(0))
+	case m.object == f.registry && m.opcode == registryBind:
+		name, _, _, id := d.uint(), d.string(), d.uint(), d.uint()
+		if name == 2 {
+			f.manager = id
+		}
+	case m.object == f.manager && m.opcode == managerCreateDataSource:
+		f.mu.Lock()
+		f.sources[d.uint()] = nil
+		f.mu.Unlock()
+	case m.object == f.manager && m.opcode == managerGetDataDevice:
+		f.device = d.uint()
+
+		return f.announce()
+	case m.object == f.device && m.opcode == deviceSetSelection:
+		src := d.uint()
+		f.selections <- src
+
+		if src != 0 {
+			return f.paste(src)
+		}
+	default:
+		f.mu.Lock()
+		if _, found := f.sources[m.object]; found && m.opcode == sourceOffer {
+			f.sources[m.object] = append(f.sources[m.object], d.string())
+		}
+		f.mu.Unlock()
+
+		if m.object == 0xff000000 && m.opcode == offerReceive {
+			_ = d.string()
+
+			fd, err := f.c.takeFD()
+			if err != nil {
+				return err
+			}
+
+			writeFD(fd, []byte(f.selection))
+		}
+	}
+
+	return d.err
+}
+
+// announce sends the current selection to a new device.
+func (f *fakeCompositor) announce() error {
+	if f.selection == "" {
+		return f.c.send(f.device, deviceEventSelection, args{}.uint(0))
+	}
+
+	if err := f.c.send(f.device, deviceEventDataOffer, args{}.uint(0xff000000)); err != nil {
+		return err
+	}
+
+	if err := f.c.send(0xff000000, offerEventOffer, args{}.string("text/plain")); err != nil {
+		return err
+	}
+
+	return f.c.send(f.device, deviceEventSelection, args{}.uint(0xff000000))
+}
+
+// paste asks the source for its content like a client pasting it.
+func (f *fakeCompositor) paste(src uint32) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	if err := f.c.send(src, sourceEventSend, args{}.string(mimeTypes[0]), int(w.Fd())); err != nil {
+		return err
+	}
+	_ = w.Close()
+
+	go func() {
+		defer func() {
+			_ = r.Close()
+		}()
+
+		buf, _ := io.ReadAll(r)
+		f.pasted <- string(buf)
+	}()
+
+	return nil
+}
+
+func TestServePasteOnce(t *testing.T) {
+	f := newFakeCompositor(t, "")
+
+	var ready bool
+	cleared, err := Serve([]byte("secret"), Options{PasteOnce: true}, func() { ready = true })
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	assert.True(t, ready)
+
+	assert.Equal(t, "secret", <-f.pasted)
+	// the selection was set and cleared after the first paste.
+	assert.NotEqual(t, uint32(0), <-f.selections)
+	assert.Equal(t, uint32(0), <-f.selections)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, mimes := range f.sources {
+		assert.Contains(t, mimes, "text/plain;charset=utf-8")
+		assert.Contains(t, mimes, passwordHint)
+	}
+}
+
+func TestServeTimeout(t *testing.T) {
+	f := newFakeCompositor(t, "")
+
+	cleared, err := Serve([]byte("secret"), Options{Timeout: 50 * time.Millisecond}, nil)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	assert.Equal(t, "secret", <-f.pasted)
+	assert.NotEqual(t, uint32(0), <-f.selections)
+	assert.Equal(t, uint32(0), <-f.selections)
+}
+
+func TestRead(t *testing.T) {
+	newFakeCompositor(t, "hunter2")
+
+	buf, err := Read()
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(buf))
+}
+
+func TestReadEmpty(t *testing.T) {
+	newFakeCompositor(t, "")
+
+	buf, err := Read()
+	require.NoError(t, err)
+	assert.Nil(t, buf)
+}
+
+func TestClear(t *testing.T) {
+	f := newFakeCompositor(t, "")
+
+	require.NoError(t, Clear(true))
+	assert.Equal(t, uint32(0), <-f.selections)
+}
+
+func TestNotSupported(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	_, err := Read()
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestWire(t *testing.T) {
+	t.Parallel()
+
+	a := args{}.uint(42).string("wl_seat").string("")
+	assert.Len(t, a, 4+4+8+4+4)
+
+	d := &decoder{buf: a}
+	assert.Equal(t, uint32(42), d.uint())
+	assert.Equal(t, "wl_seat", d.string())
+	assert.Equal(t, "", d.string())
+	require.NoError(t, d.err)
+
+	d.uint()
+	assert.Error(t, d.err)
+}
@@ -0,0 +1,266 @@
+//go:build linux
+// +build linux
+
+package wayland
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// headerLen is the length of a message header: the object ID followed by the
+// opcode in the lower and the message size in the upper 16 bits.
+const headerLen = 8
+
+// nativeEndian is the byte order of the host. The wire protocol uses it,
+// since client and compositor always run on the same machine.
+var nativeEndian = func() interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+} {
+	v := uint16(1)
+	if *(*byte)(unsafe.Pointer(&v)) == 1 {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}()
+
+// maxFDs is the number of file descriptors accepted with a single read.
+const maxFDs = 28
+
+// message is a request or event on the wire.
+type message struct {
+	object uint32
+	opcode uint16
+	args   []byte
+	fds    []int
+}
+
+// conn is a connection to the compositor. It isn't safe for concurrent use.
+type conn struct {
+	c      *net.UnixConn
+	nextID uint32
+	buf    []byte
+	fds    []int
+}
+
+// socketPath returns the path of the socket of the compositor.
+func socketPath() (string, error) {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		return "", ErrNotSupported
+	}
+
+	if filepath.IsAbs(display) {
+		return display, nil
+	}
+
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return "", fmt.Errorf("XDG_RUNTIME_DIR is not set: %w", ErrNotSupported)
+	}
+
+	return filepath.Join(dir, display), nil
+}
+
+func dial() (*conn, error) {
+	path, err := socketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+
+	return newConn(c), nil
+}
+
+func newConn(c *net.UnixConn) *conn {
+	// ID 1 is always the wl_display.
+	return &conn{c: c, nextID: displayID + 1}
+}
+
+func (c *conn) Close() error {
+	for _, fd := range c.fds {
+		_ = syscall.Close(fd)
+	}
+	c.fds = nil
+
+	return c.c.Close()
+}
+
+// newID allocates a new client side object ID.
+func (c *conn) newID() uint32 {
+	id := c.nextID
+	c.nextID++
+
+	return id
+}
+
+// send writes a request. The file descriptors are passed along as ancillary
+// data.
+func (c *conn) send(object uint32, opcode uint16, args []byte, fds ...int) error {
+	buf := make([]byte, headerLen, headerLen+len(args))
+	nativeEndian.PutUint32(buf[0:], object)
+	nativeEndian.PutUint32(buf[4:], uint32(headerLen+len(args))<<16|uint32(opcode))
+	buf = append(buf, args...)
+
+	var oob []byte
+	if len(fds) > 0 {
+		oob = syscall.UnixRights(fds...)
+	}
+
+	if _, _, err := c.c.WriteMsgUnix(buf, oob, nil); err != nil {
+		return fmt.Errorf("failed to send request %d to object %d: %w", opcode, object, err)
+	}
+
+	return nil
+}
+
+// next reads the next event. File descriptors received are queued and handed
+// out with the events in the order they arrived, the caller takes them with
+// takeFD.
+func (c *conn) next(deadline time.Time) (message, error) {
+	if err := c.c.SetReadDeadline(deadline); err != nil {
+		return message{}, err
+	}
+
+	for {
+		if len(c.buf) >= headerLen {
+			size := int(nativeEndian.Uint32(c.buf[4:]) >> 16)
+			if size < headerLen {
+				return message{}, fmt.Errorf("invalid message size %d", size)
+			}
+
+			if len(c.buf) >= size {
+				m := message{
+					object: nativeEndian.Uint32(c.buf[0:]),
+					opcode: uint16(nativeEndian.Uint32(c.buf[4:]) & 0xffff),
+					args:   append([]byte{}, c.buf[headerLen:size]...),
+				}
+				c.buf = c.buf[size:]
+
+				return m, nil
+			}
+		}
+
+		buf := make([]byte, 4096)
+		oob := make([]byte, syscall.CmsgSpace(maxFDs*4))
+
+		n, oobn, _, _, err := c.c.ReadMsgUnix(buf, oob)
+		if err != nil {
+			return message{}, err
+		}
+
+		if oobn > 0 {
+			fds, err := parseRights(oob[:oobn])
+			if err != nil {
+				return message{}, err
+			}
+			c.fds = append(c.fds, fds...)
+		}
+
+		if n == 0 {
+			return message{}, fmt.Errorf("connection closed by the compositor")
+		}
+
+		c.buf = append(c.buf, buf[:n]...)
+	}
+}
+
+// takeFD returns the oldest file descriptor received.
+func (c *conn) takeFD() (int, error) {
+	if len(c.fds) < 1 {
+		return -1, errors.New("expected a file descriptor")
+	}
+
+	fd := c.fds[0]
+	c.fds = c.fds[1:]
+
+	return fd, nil
+}
+
+func parseRights(oob []byte) ([]int, error) {
+	scms, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse control message: %w", err)
+	}
+
+	var fds []int
+	for _, scm := range scms {
+		f, err := syscall.ParseUnixRights(&scm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file descriptors: %w", err)
+		}
+		fds = append(fds, f...)
+	}
+
+	return fds, nil
+}
+
+// args encodes the arguments of a request.
+type args []byte
+
+func (a args) uint(v uint32) args {
+	return nativeEndian.AppendUint32(a, v)
+}
+
+func (a args) string(s string) args {
+	// strings are NUL terminated and padded to 32 bits.
+	a = a.uint(uint32(len(s) + 1))
+	a = append(a, s...)
+	a = append(a, 0)
+	for len(a)%4 != 0 {
+		a = append(a, 0)
+	}
+
+	return a
+}
+
+// decoder decodes the arguments of an event.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uint() uint32 {
+	if len(d.buf) < 4 {
+		d.err = errors.New("message too short")
+
+		return 0
+	}
+
+	v := nativeEndian.Uint32(d.buf)
+	d.buf = d.buf[4:]
+
+	return v
+}
+
+func (d *decoder) string() string {
+	l := int(d.uint())
+	if l == 0 {
+		return ""
+	}
+
+	padded := (l + 3) &^ 3
+	if d.err != nil || len(d.buf) < padded {
+		d.err = errors.New("message too short")
+
+		return ""
+	}
+
+	s := string(d.buf[:l-1])
+	d.buf = d.buf[padded:]
+
+	return s
+}
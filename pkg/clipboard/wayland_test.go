@@ -0,0 +1,33 @@
+//go:build linux
+// +build linux
+
+package clipboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseWayland(t *testing.T) {
+	// no wl-copy in $PATH.
+	t.Setenv("PATH", t.TempDir())
+
+	cfg := config.NewNoWrites()
+	ctx := cfg.WithConfig(context.Background())
+
+	t.Setenv("WAYLAND_DISPLAY", "")
+	assert.False(t, useWayland(ctx))
+
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	assert.True(t, useWayland(ctx))
+
+	require.NoError(t, cfg.Set("", "clipboard.wayland", "wl-clipboard"))
+	assert.False(t, useWayland(ctx))
+
+	require.NoError(t, cfg.Set("", "clipboard.wayland", "native"))
+	assert.True(t, useWayland(ctx))
+}
@@ -31,6 +31,18 @@ const (
 	ctxKeyCommitTimestamp
 	ctxKeyShowParsing
 	ctxKeyHidden
+	ctxKeyLockWait
+	ctxKeyVerbosity
+	ctxKeyDemo
+)
+
+// Verbosity levels. Quiet hides notices and status messages and prints
+// errors as JSON, verbose adds details and debug also enables debug logging.
+const (
+	VerbosityQuiet   = -1
+	VerbosityNormal  = 0
+	VerbosityVerbose = 1
+	VerbosityDebug   = 2
 )
 
 // ErrNoCallback is returned when no callback is set in the context.
@@ -39,11 +51,29 @@ var ErrNoCallback = fmt.Errorf("no callback")
 // WithGlobalFlags parses any global flags from the cli context and returns
 // a regular context.
 func WithGlobalFlags(c *cli.Context) context.Context {
+	ctx := c.Context
 	if c.Bool("yes") {
-		return WithAlwaysYes(c.Context, true)
+		ctx = WithAlwaysYes(ctx, true)
+	}
+
+	if c.Bool("wait") {
+		ctx = WithLockWait(ctx, true)
+	}
+
+	if c.Bool("demo") {
+		ctx = WithDemo(ctx, true)
+	}
+
+	if c.Bool("quiet") {
+		ctx = WithVerbosity(ctx, VerbosityQuiet)
+	} else if n := c.Count("verbose"); n > 0 {
+		if n > VerbosityDebug {
+			n = VerbosityDebug
+		}
+		ctx = WithVerbosity(ctx, n)
 	}
 
-	return c.Context
+	return ctx
 }
 
 // ProgressCallback is a callback for updateing progress.
@@ -486,3 +516,61 @@ func IsHidden(ctx context.Context) bool {
 
 	return bv
 }
+
+// WithLockWait returns a context with the flag value for waiting on locked
+// stores set.
+func WithLockWait(ctx context.Context, wait bool) context.Context {
+	return context.WithValue(ctx, ctxKeyLockWait, wait)
+}
+
+// IsLockWait returns true if we should wait for other processes to release
+// the lock of a store instead of failing.
+func IsLockWait(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyLockWait).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
+
+// WithVerbosity returns a context with the verbosity level set.
+func WithVerbosity(ctx context.Context, level int) context.Context {
+	return context.WithValue(ctx, ctxKeyVerbosity, level)
+}
+
+// GetVerbosity returns the verbosity level or the default (normal).
+func GetVerbosity(ctx context.Context) int {
+	iv, ok := ctx.Value(ctxKeyVerbosity).(int)
+	if !ok {
+		return VerbosityNormal
+	}
+
+	return iv
+}
+
+// IsQuiet returns true if only errors should be printed.
+func IsQuiet(ctx context.Context) bool {
+	return GetVerbosity(ctx) <= VerbosityQuiet
+}
+
+// IsVerbose returns true if additional details should be printed.
+func IsVerbose(ctx context.Context) bool {
+	return GetVerbosity(ctx) >= VerbosityVerbose
+}
+
+// WithDemo returns a context with the flag value for the demo mode set.
+func WithDemo(ctx context.Context, demo bool) context.Context {
+	return context.WithValue(ctx, ctxKeyDemo, demo)
+}
+
+// IsDemo returns true if entry names and secrets must be replaced with
+// placeholders in the output.
+func IsDemo(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyDemo).(bool)
+	if !ok {
+		return false
+	}
+
+	return bv
+}
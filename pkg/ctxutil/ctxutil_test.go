@@ -219,3 +219,51 @@ func TestHidden(t *testing.T) {
 	assert.False(t, IsHidden(ctx))
 	assert.True(t, IsHidden(WithHidden(ctx, true)))
 }
+
+func TestDemo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	assert.False(t, IsDemo(ctx))
+	assert.True(t, IsDemo(WithDemo(ctx, true)))
+}
+
+func TestVerbosity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	assert.Equal(t, VerbosityNormal, GetVerbosity(ctx))
+	assert.False(t, IsQuiet(ctx))
+	assert.False(t, IsVerbose(ctx))
+	assert.True(t, IsQuiet(WithVerbosity(ctx, VerbosityQuiet)))
+	assert.True(t, IsVerbose(WithVerbosity(ctx, VerbosityDebug)))
+
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"-q"}, want: VerbosityQuiet},
+		{args: []string{"-v"}, want: VerbosityVerbose},
+		{args: []string{"-vv"}, want: VerbosityDebug},
+		{args: []string{"-v", "-v", "-v"}, want: VerbosityDebug},
+		{args: []string{"-q", "-v"}, want: VerbosityQuiet},
+	} {
+		var got int
+		app := cli.NewApp()
+		app.UseShortOptionHandling = true
+		app.Flags = []cli.Flag{
+			&cli.BoolFlag{Name: "quiet", Aliases: []string{"q"}},
+			&cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}},
+		}
+		app.Action = func(c *cli.Context) error {
+			got = GetVerbosity(WithGlobalFlags(c))
+
+			return nil
+		}
+
+		assert.NoError(t, app.RunContext(ctx, append([]string{"gopass"}, tc.args...)))
+		assert.Equal(t, tc.want, got, tc.args)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -25,7 +26,10 @@ var opts struct {
 	logger  *log.Logger
 	funcs   map[string]bool
 	files   map[string]bool
-	logFile *os.File
+	modules map[string]bool
+	// moduleKeys are the keys of modules in the order they are matched.
+	moduleKeys []string
+	logFile    *os.File
 }
 
 var logFn = doNotLog
@@ -154,12 +158,28 @@ func padFile(s string) string {
 func initDebugTags() {
 	opts.funcs = parseFilter("GOPASS_DEBUG_FUNCS", padFunc)
 	opts.files = parseFilter("GOPASS_DEBUG_FILES", padFile)
+	opts.modules = parseFilter("GOPASS_DEBUG_MODULES", padFunc)
+
+	// longer, i.e. more specific, modules take precedence. Map iteration
+	// order is random, so the keys are sorted for a stable result.
+	opts.moduleKeys = make([]string, 0, len(opts.modules))
+	for k := range opts.modules {
+		opts.moduleKeys = append(opts.moduleKeys, k)
+	}
+	sort.Slice(opts.moduleKeys, func(i, j int) bool {
+		a, b := opts.moduleKeys[i], opts.moduleKeys[j]
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+
+		return a < b
+	})
 }
 
-func getPosition(offset int) (fn, dir, file string, line int) { //nolint:nonamedreturns
+func getPosition(offset int) (fn, pkg, dir, file string, line int) { //nolint:nonamedreturns
 	pc, file, line, ok := runtime.Caller(3 + offset)
 	if !ok {
-		return "", "", "", 0
+		return "", "", "", "", 0
 	}
 
 	dirname := filepath.Base(filepath.Dir(file))
@@ -167,7 +187,51 @@ func getPosition(offset int) (fn, dir, file string, line int) { //nolint:nonamed
 
 	f := runtime.FuncForPC(pc)
 
-	return path.Base(f.Name()), dirname, filename, line
+	return path.Base(f.Name()), packageOf(f.Name()), dirname, filename, line
+}
+
+// modulePrefix is stripped from package paths before matching modules.
+const modulePrefix = "github.com/gopasspw/gopass/"
+
+// packageOf returns the package path of a fully qualified function name,
+// relative to the gopass module.
+func packageOf(fn string) string {
+	dir, base := path.Split(fn)
+	if i := strings.Index(base, "."); i >= 0 {
+		base = base[:i]
+	}
+
+	return strings.TrimPrefix(dir+base, modulePrefix)
+}
+
+// checkModule returns true if the package belongs to one of the modules
+// selected by GOPASS_DEBUG_MODULES. A module matches every package path
+// element it is a prefix of, e.g. git matches internal/backend/storage/gitfs
+// and store matches internal/store/leaf. Without modules everything matches.
+func checkModule(pkg string) bool {
+	if len(opts.modules) < 1 {
+		return true
+	}
+
+	for _, elem := range strings.Split(pkg, "/") {
+		for _, k := range opts.moduleKeys {
+			v := opts.modules[k]
+			if strings.HasPrefix(elem, k) {
+				return v
+			}
+
+			if m, _ := path.Match(k, elem); m {
+				return v
+			}
+		}
+	}
+
+	// check if tag "all" is enabled
+	if v, ok := opts.modules["all"]; ok && v {
+		return true
+	}
+
+	return false
 }
 
 func checkFilter(filter map[string]bool, key string) bool {
@@ -207,7 +271,10 @@ func LogN(offset int, f string, args ...any) {
 func doNotLog(offset int, f string, args ...any) {}
 
 func doLog(offset int, f string, args ...any) {
-	fn, dir, file, line := getPosition(offset)
+	fn, pkg, dir, file, line := getPosition(offset)
+	if !checkModule(pkg) {
+		return
+	}
 
 	if len(f) == 0 || f[len(f)-1] != '\n' {
 		f += "\n"
@@ -237,14 +304,17 @@ func doLog(offset int, f string, args ...any) {
 
 	pos := fmt.Sprintf("%s/%s:%d", dir, file, line)
 
-	formatString := fmt.Sprintf("%s\t%s\t%s", pos, fn, f)
+	msg := fmt.Sprintf(fmt.Sprintf("%s\t%s\t%s", pos, fn, f), argsi...)
+	if !logSecrets {
+		msg = scrub(msg)
+	}
 
 	dbgprint := func() {
-		fmt.Fprintf(Stderr, formatString, argsi...)
+		fmt.Fprint(Stderr, msg)
 	}
 
 	if opts.logger != nil {
-		opts.logger.Printf(formatString, argsi...)
+		opts.logger.Print(msg)
 	}
 
 	filename := fmt.Sprintf("%s/%s:%d", dir, file, line)
@@ -263,3 +333,18 @@ func doLog(offset int, f string, args ...any) {
 func IsEnabled() bool {
 	return enabled
 }
+
+// Enable turns on debug logging at runtime, e.g. for -vv. The environment
+// variables still select the log file and filters. It does nothing if debug
+// logging is already enabled.
+func Enable() {
+	if enabled {
+		return
+	}
+
+	initDebugLogger()
+	initDebugTags()
+
+	logFn = doLog
+	enabled = true
+}
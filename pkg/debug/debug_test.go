@@ -129,3 +129,110 @@ func TestDebugFilter(t *testing.T) {
 	stderrStr := buf.String()
 	assert.Contains(t, stderrStr, "TestDebugFilter")
 }
+
+func TestDebugModules(t *testing.T) {
+	td := t.TempDir()
+	t.Cleanup(func() {
+		initDebug()
+	})
+
+	fn := filepath.Join(td, "gopass.log")
+	t.Setenv("GOPASS_DEBUG_LOG", fn)
+	t.Setenv("GOPASS_DEBUG_MODULES", "store,git")
+
+	// it's been already initialized, need to re-init
+	assert.True(t, initDebug())
+
+	Log("foo")
+
+	fbuf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.NotContains(t, string(fbuf), "foo")
+
+	for _, tc := range []struct {
+		pkg  string
+		want bool
+	}{
+		{"internal/store/leaf", true},
+		{"internal/backend/storage/gitfs", true},
+		{"internal/action", false},
+		{"pkg/debug", false},
+	} {
+		assert.Equal(t, tc.want, checkModule(tc.pkg), tc.pkg)
+	}
+
+	t.Setenv("GOPASS_DEBUG_MODULES", "all,-gitfs")
+	assert.True(t, initDebug())
+	assert.True(t, checkModule("pkg/debug"))
+	assert.False(t, checkModule("internal/backend/storage/gitfs"))
+
+	// the more specific module wins, no matter the order of the map.
+	t.Setenv("GOPASS_DEBUG_MODULES", "git,-gitfs")
+	assert.True(t, initDebug())
+	for i := 0; i < 32; i++ {
+		assert.False(t, checkModule("internal/backend/storage/gitfs"))
+		assert.True(t, checkModule("internal/backend/storage/gitconfig"))
+	}
+
+	assert.Equal(t, "internal/store/leaf", packageOf("github.com/gopasspw/gopass/internal/store/leaf.(*Store).Get"))
+}
+
+func TestDebugScrub(t *testing.T) {
+	td := t.TempDir()
+	t.Cleanup(func() {
+		enabled = initDebug()
+	})
+
+	fn := filepath.Join(td, "gopass.log")
+	t.Setenv("GOPASS_DEBUG_LOG", fn)
+	t.Setenv("GOPASS_DEBUG_LOG_SECRETS", "false")
+
+	// it's been already initialized, need to re-init
+	enabled = initDebug()
+	assert.True(t, enabled)
+
+	AddSecret("hunter2-is-safe", "abc")
+	Log("read %s from foo", "hunter2-is-safe")
+	Log("content: password: s3cr3t\npin=1234")
+	Log("url: otpauth://totp/foo?secret=JBSWY3DPEHPK3PXP&issuer=bar")
+	Log("value abc")
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+
+	logStr := string(buf)
+	assert.NotContains(t, logStr, "hunter2-is-safe")
+	assert.NotContains(t, logStr, "s3cr3t")
+	assert.NotContains(t, logStr, "1234")
+	assert.NotContains(t, logStr, "JBSWY3DPEHPK3PXP")
+	assert.Contains(t, logStr, "issuer=bar")
+	assert.Contains(t, logStr, "password: *****")
+	// too short to be registered.
+	assert.Contains(t, logStr, "value abc")
+}
+
+func TestSpan(t *testing.T) {
+	td := t.TempDir()
+	t.Cleanup(func() {
+		enabled = initDebug()
+	})
+
+	fn := filepath.Join(td, "gopass.log")
+	t.Setenv("GOPASS_DEBUG_LOG", fn)
+
+	// it's been already initialized, need to re-init
+	enabled = initDebug()
+	assert.True(t, enabled)
+
+	Start("work on %s", "foo").End()
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+
+	logStr := string(buf)
+	assert.Contains(t, logStr, "TestSpan\tstart: work on foo")
+	assert.Contains(t, logStr, "TestSpan\tend: work on foo (took ")
+
+	var s *Span
+	assert.NotPanics(t, s.End)
+}
@@ -0,0 +1,60 @@
+package debug
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// scrubbed replaces secret values in log lines.
+const scrubbed = "*****"
+
+// minSecretLen is the minimum length of registered secrets. Shorter values
+// would scrub too many unrelated log lines.
+const minSecretLen = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   = map[string]struct{}{}
+
+	// reSecretValue matches values of keys that usually hold secrets, e.g.
+	// "password: foo" or "pin=1234", and the secret of otpauth URLs.
+	reSecretValue = regexp.MustCompile(`(?i)((?:password|passphrase|passwd|pin|api[_-]?key|token)\s*[:=]\s*|[?&]secret=)([^\s&"']+)`)
+)
+
+// AddSecret registers secret values, e.g. freshly decrypted passwords. They
+// are replaced in all following log lines unless GOPASS_DEBUG_LOG_SECRETS is
+// set. It does nothing if debug logging is disabled.
+func AddSecret(values ...string) {
+	if !enabled || logSecrets {
+		return
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	for _, v := range values {
+		if len(v) < minSecretLen {
+			continue
+		}
+		secrets[v] = struct{}{}
+	}
+}
+
+// scrub removes registered secrets and values of well known secret keys from
+// the log line.
+func scrub(msg string) string {
+	secretsMu.RLock()
+	for v := range secrets {
+		msg = strings.ReplaceAll(msg, v, scrubbed)
+	}
+	secretsMu.RUnlock()
+
+	return reSecretValue.ReplaceAllString(msg, "${1}"+scrubbed)
+}
+
+// Scrub removes registered secrets and values of well known secret keys from
+// the text, e.g. from an excerpt of the debug log.
+func Scrub(text string) string {
+	return scrub(text)
+}
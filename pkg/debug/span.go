@@ -0,0 +1,39 @@
+package debug
+
+import (
+	"time"
+)
+
+// Span measures the duration of an operation. A nil Span is valid and does
+// nothing, so spans are cheap if debug logging is disabled.
+type Span struct {
+	f     string
+	args  []any
+	start time.Time
+}
+
+// Start logs the start of an operation and returns a span to end it, e.g.
+//
+//	defer debug.Start("decrypting %s", name).End()
+func Start(f string, args ...any) *Span {
+	if !enabled {
+		return nil
+	}
+
+	logFn(0, "start: "+f, args...)
+
+	return &Span{
+		f:     f,
+		args:  args,
+		start: time.Now(),
+	}
+}
+
+// End logs the end of the operation with its duration.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	logFn(0, "end: "+s.f+" (took %s)", append(s.args, time.Since(s.start).Round(time.Microsecond))...)
+}
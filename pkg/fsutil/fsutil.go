@@ -45,7 +45,7 @@ func CleanPath(path string) string {
 	// for testing and experiments. In all other cases we do want to leave ~ as-is.
 	if len(path) > 1 && path[:2] == "~/" {
 		if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
-			return filepath.Clean(hd + path[2:])
+			return filepath.Join(hd, path[2:])
 		}
 	}
 
@@ -9,6 +9,7 @@ import (
 	// load storage backends.
 	_ "github.com/gopasspw/gopass/internal/backend/storage"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/event"
 	"github.com/gopasspw/gopass/internal/queue"
 	"github.com/gopasspw/gopass/internal/store/root"
 	"github.com/gopasspw/gopass/internal/tree"
@@ -17,11 +18,15 @@ import (
 
 // Gopass is a secret store implementation.
 type Gopass struct {
-	rs *root.Store
+	rs  *root.Store
+	bus *event.Bus
 }
 
-// make sure that *Gopass implements Store.
-var _ gopass.Store = &Gopass{}
+// make sure that *Gopass implements Store and Watcher.
+var (
+	_ gopass.Store   = &Gopass{}
+	_ gopass.Watcher = &Gopass{}
+)
 
 // ErrNotImplemented is returned when a method is not implemented.
 var ErrNotImplemented = fmt.Errorf("not yet implemented")
@@ -48,10 +53,25 @@ func New(ctx context.Context) (*Gopass, error) {
 	}
 
 	return &Gopass{
-		rs: store,
+		rs:  store,
+		bus: event.New(),
 	}, nil
 }
 
+// Watch calls fn for every event of the given types, or all events if none
+// are given, e.g. whenever an entry is written through this instance.
+func (g *Gopass) Watch(fn func(gopass.Event), types ...string) func() {
+	return g.bus.Subscribe(func(_ context.Context, ev event.Event) {
+		fn(ev)
+	}, types...)
+}
+
+// withBus adds the event bus of this instance to the context, so that
+// changes are reported to the watchers.
+func (g *Gopass) withBus(ctx context.Context) context.Context {
+	return event.WithBus(ctx, g.bus)
+}
+
 // List returns a list of all secrets.
 func (g *Gopass) List(ctx context.Context) ([]string, error) {
 	return g.rs.List(ctx, tree.INF) //nolint:wrapcheck
@@ -66,22 +86,22 @@ func (g *Gopass) Get(ctx context.Context, name, revision string) (gopass.Secret,
 // Set adds a new revision to an existing secret or creates a new one.
 // Create new secrets with secrets.New().
 func (g *Gopass) Set(ctx context.Context, name string, sec gopass.Byter) error {
-	return g.rs.Set(ctx, name, sec) //nolint:wrapcheck
+	return g.rs.Set(g.withBus(ctx), name, sec) //nolint:wrapcheck
 }
 
 // Remove removes a single secret.
 func (g *Gopass) Remove(ctx context.Context, name string) error {
-	return g.rs.Delete(ctx, name) //nolint:wrapcheck
+	return g.rs.Delete(g.withBus(ctx), name) //nolint:wrapcheck
 }
 
 // RemoveAll removes all secrets with a given prefix.
 func (g *Gopass) RemoveAll(ctx context.Context, prefix string) error {
-	return g.rs.Prune(ctx, prefix) //nolint:wrapcheck
+	return g.rs.Prune(g.withBus(ctx), prefix) //nolint:wrapcheck
 }
 
 // Rename move a prefix to another.
 func (g *Gopass) Rename(ctx context.Context, src, dest string) error {
-	return g.rs.Move(ctx, src, dest) //nolint:wrapcheck
+	return g.rs.Move(g.withBus(ctx), src, dest) //nolint:wrapcheck
 }
 
 // Sync synchronizes a secret with a remote.
@@ -0,0 +1,49 @@
+package gopass
+
+import "time"
+
+// Event types.
+const (
+	// EventEntryWritten is sent whenever an entry is written.
+	EventEntryWritten = "entry.written"
+	// EventEntryDeleted is sent when entries are removed. Removing a folder
+	// sends one event with all removed entries.
+	EventEntryDeleted = "entry.deleted"
+	// EventRecipientsChanged is sent when recipients are added to or removed
+	// from a store or folder.
+	EventRecipientsChanged = "recipients.changed"
+	// EventEntryCreated is sent when a user created a new entry.
+	EventEntryCreated = "entry.created"
+	// EventEntryRotated is sent when the password of an entry changed.
+	EventEntryRotated = "entry.rotated"
+	// EventFsckFailed is sent when fsck found errors.
+	EventFsckFailed = "fsck.failed"
+	// EventSyncCompleted is sent when a sync finished.
+	EventSyncCompleted = "sync.completed"
+)
+
+// Event is something that happened to a store.
+type Event struct {
+	Type string
+	Time time.Time
+	// Store is the mount point, empty for the root store.
+	Store string
+	// Folder is the full name of the folder a change is limited to, e.g. the
+	// folder that got its own recipients. Empty for changes of the whole
+	// store.
+	Folder string
+	// Entries are the full names of the affected entries, if any.
+	Entries []string
+	// Added and Removed are the changed recipients.
+	Added   []string
+	Removed []string
+	Message string
+}
+
+// Watcher is implemented by stores that report changes.
+type Watcher interface {
+	// Watch calls fn for every event of the given types, or all events if
+	// none are given, until stop is called. fn runs synchronously while the
+	// store is changed, so it must not block for long.
+	Watch(fn func(Event), types ...string) (stop func())
+}
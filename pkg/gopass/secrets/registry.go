@@ -0,0 +1,227 @@
+package secrets
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/pquerna/otp"
+)
+
+// KeyType is the type of the value of a well-known key.
+type KeyType string
+
+// Types of well-known keys.
+const (
+	// TypeString is a single line of text.
+	TypeString KeyType = "string"
+	// TypeText is free text.
+	TypeText KeyType = "text"
+	// TypeURL is an absolute URL or a bare host name.
+	TypeURL KeyType = "url"
+	// TypeEmail is an email address.
+	TypeEmail KeyType = "email"
+	// TypeDate is a date (2006-01-02) or a RFC 3339 timestamp.
+	TypeDate KeyType = "date"
+	// TypeList is a comma separated list.
+	TypeList KeyType = "list"
+	// TypeOTPAuth is an otpauth:// URL.
+	TypeOTPAuth KeyType = "otpauth"
+	// TypeOTPSecret is a base32 encoded OTP secret.
+	TypeOTPSecret KeyType = "otp-secret"
+)
+
+// ReservedNamespace is the key namespace reserved for gopass itself. Keys
+// are namespaced with a dot, e.g. acme.cost-center. Custom keys in any other
+// namespace or without one are never validated.
+const ReservedNamespace = "gopass"
+
+var (
+	// ErrInvalidValue is returned if the value of a well-known key doesn't
+	// match its type.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrReservedKey is returned for keys in the reserved namespace.
+	ErrReservedKey = errors.New("reserved key")
+)
+
+// KeySpec describes a well-known key.
+type KeySpec struct {
+	Name        string
+	Type        KeyType
+	Description string
+	// Multiple is set if the key may have several values.
+	Multiple bool
+}
+
+var registry = map[string]KeySpec{}
+
+func init() {
+	for _, ks := range []KeySpec{
+		{Name: "username", Type: TypeString, Description: "The login name"},
+		{Name: "user", Type: TypeString, Description: "The login name (legacy alias of username)"},
+		{Name: "login", Type: TypeString, Description: "The login name (alias of username)"},
+		{Name: "email", Type: TypeEmail, Description: "The email address of the account"},
+		{Name: "url", Type: TypeURL, Description: "The website or service the entry belongs to", Multiple: true},
+		{Name: "password-change-url", Type: TypeURL, Description: "Where to change the password"},
+		{Name: "otpauth", Type: TypeOTPAuth, Description: "OTP parameters as otpauth:// URL, used by gopass otp"},
+		{Name: "totp", Type: TypeOTPSecret, Description: "A bare TOTP secret, used by gopass otp"},
+		{Name: "hotp", Type: TypeOTPSecret, Description: "A bare HOTP secret, used by gopass otp"},
+		{Name: "comment", Type: TypeText, Description: "A free text comment", Multiple: true},
+		{Name: "tags", Type: TypeList, Description: "Comma separated tags, see gopass tag"},
+		{Name: "expires", Type: TypeDate, Description: "When the password expires"},
+		{Name: "unsafe-keys", Type: TypeList, Description: "Additional keys hidden by safecontent"},
+		{Name: "pending-password", Type: TypeString, Description: "The next password during a rotation, see gopass rotate"},
+	} {
+		registry[ks.Name] = ks
+	}
+}
+
+// KnownKeys returns all well-known keys, sorted by name.
+func KnownKeys() []KeySpec {
+	keys := make([]KeySpec, 0, len(registry))
+	for _, ks := range registry {
+		keys = append(keys, ks)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys
+}
+
+// LookupKey returns the spec of a well-known key. Keys are case insensitive.
+func LookupKey(key string) (KeySpec, bool) {
+	ks, found := registry[strings.ToLower(key)]
+
+	return ks, found
+}
+
+// Namespace returns the namespace of a key, i.e. everything before the first
+// dot, or an empty string.
+func Namespace(key string) string {
+	ns, _, found := strings.Cut(key, ".")
+	if !found {
+		return ""
+	}
+
+	return strings.ToLower(ns)
+}
+
+// ValidateKey checks a value about to be written to the given key. Values of
+// well-known keys must match their type, custom keys can hold anything.
+// Keys in the reserved namespace can't be written.
+func ValidateKey(key, value string) error {
+	if Namespace(key) == ReservedNamespace {
+		return fmt.Errorf("%w: the %s. namespace is reserved", ErrReservedKey, ReservedNamespace)
+	}
+
+	ks, found := LookupKey(key)
+	if !found {
+		return nil
+	}
+
+	if err := ks.Type.validate(value); err != nil {
+		return fmt.Errorf("%w for %s: %w", ErrInvalidValue, ks.Name, err)
+	}
+
+	return nil
+}
+
+// Validate checks all keys of a secret. It returns all problems joined.
+func Validate(sec gopass.Secret) error {
+	var errs []error
+
+	for _, k := range sec.Keys() {
+		values, _ := sec.Values(k)
+
+		if ks, found := LookupKey(k); found && !ks.Multiple && len(values) > 1 {
+			errs = append(errs, fmt.Errorf("%w for %s: must not have several values", ErrInvalidValue, ks.Name))
+		}
+
+		for _, v := range values {
+			if err := ValidateKey(k, v); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t KeyType) validate(v string) error {
+	if t != TypeText && strings.ContainsAny(v, "\r\n") {
+		return fmt.Errorf("must be a single line")
+	}
+
+	switch t {
+	case TypeURL:
+		return validateURL(v)
+	case TypeEmail:
+		if _, err := mail.ParseAddress(v); err != nil {
+			return fmt.Errorf("not an email address")
+		}
+	case TypeDate:
+		if _, err := time.Parse("2006-01-02", v); err == nil {
+			return nil
+		}
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return fmt.Errorf("not a date like 2006-01-02")
+		}
+	case TypeList:
+		for _, e := range strings.Split(v, ",") {
+			if strings.TrimSpace(e) == "" {
+				return fmt.Errorf("empty list element")
+			}
+		}
+	case TypeOTPAuth:
+		k, err := otp.NewKeyFromURL(v)
+		if err != nil || k.Type() == "" || k.Secret() == "" {
+			return fmt.Errorf("not an otpauth:// URL")
+		}
+
+		return validateOTPSecret(k.Secret())
+	case TypeOTPSecret:
+		return validateOTPSecret(v)
+	case TypeString, TypeText:
+	}
+
+	return nil
+}
+
+func validateURL(v string) error {
+	if strings.ContainsAny(v, " \t") {
+		return fmt.Errorf("must not contain white space")
+	}
+
+	// bare host names are common.
+	if !strings.Contains(v, "://") {
+		v = "https://" + v
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("not a URL")
+	}
+
+	return nil
+}
+
+func validateOTPSecret(v string) error {
+	v = strings.ToUpper(strings.ReplaceAll(v, " ", ""))
+	if v == "" {
+		return fmt.Errorf("empty secret")
+	}
+
+	if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(v, "=")); err != nil {
+		return fmt.Errorf("not a base32 encoded secret")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		key   string
+		value string
+		ok    bool
+	}{
+		{"username", "jane", true},
+		{"username", "jane\ndoe", false},
+		{"URL", "example.org", true},
+		{"url", "https://example.org/login", true},
+		{"url", "not a url", false},
+		{"email", "jane@example.org", true},
+		{"email", "jane", false},
+		{"expires", "2030-12-31", true},
+		{"expires", "2030-12-31T10:00:00Z", true},
+		{"expires", "tomorrow", false},
+		{"tags", "work, banking", true},
+		{"tags", "work,,banking", false},
+		{"totp", "JBSW Y3DP EHPK 3PXP", true},
+		{"totp", "not base32!", false},
+		{"otpauth", "otpauth://totp/Example:jane?secret=JBSWY3DPEHPK3PXP&issuer=Example", true},
+		{"otpauth", "otpauth://totp/Example:jane?issuer=Example", false},
+		{"comment", "several\nlines", true},
+		{"custom", "anything\ngoes", true},
+		{"acme.email", "not validated", true},
+		{"gopass.anything", "reserved", false},
+	} {
+		err := ValidateKey(tc.key, tc.value)
+		if tc.ok {
+			assert.NoError(t, err, "%s: %q", tc.key, tc.value)
+
+			continue
+		}
+		assert.Error(t, err, "%s: %q", tc.key, tc.value)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	sec := ParseAKV([]byte("secret\nurl: example.org\nurl: example.com\nemail: jane@example.org\nfoo: bar\nfoo: baz\n"))
+	require.NoError(t, Validate(sec))
+
+	sec = ParseAKV([]byte("secret\nemail: jane@example.org\nemail: joe@example.org\nexpires: soon\n"))
+	err := Validate(sec)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Contains(t, err.Error(), "several values")
+	assert.Contains(t, err.Error(), "expires")
+}
+
+func TestKnownKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := KnownKeys()
+	require.NotEmpty(t, keys)
+
+	for i, ks := range keys {
+		assert.NotEmpty(t, ks.Description, ks.Name)
+		if i > 0 {
+			assert.Less(t, keys[i-1].Name, ks.Name)
+		}
+	}
+
+	ks, found := LookupKey("Username")
+	assert.True(t, found)
+	assert.Equal(t, TypeString, ks.Type)
+	assert.Equal(t, "acme", Namespace("ACME.cost-center"))
+	assert.Equal(t, "", Namespace("username"))
+}
@@ -42,6 +42,42 @@ func Calculate(name string, sec gopass.Secret) (*otp.Key, error) {
 	return parseOTP("totp", sec.Password())
 }
 
+// CalculateKey will compute a OTP code from the otpauth URL or the TOTP (or,
+// for the key hotp, HOTP) secret in the given key of the secret.
+func CalculateKey(sec gopass.Secret, key string) (*otp.Key, error) {
+	sv, found := sec.Get(key)
+	if !found {
+		return nil, fmt.Errorf("key %q not found", key)
+	}
+
+	if strings.HasPrefix(sv, "//") {
+		sv = "otpauth:" + sv
+	}
+
+	typ := "totp"
+	if key == "hotp" {
+		typ = "hotp"
+	}
+
+	return parseOTP(typ, sv)
+}
+
+// Has returns true if the secret contains an OTP seed. Unlike Calculate it
+// doesn't fall back to the password.
+func Has(sec gopass.Secret) bool {
+	if getOTPURL(sec) != "" {
+		return true
+	}
+
+	for _, k := range []string{"totp", "hotp"} {
+		if _, found := sec.Get(k); found {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getOTPURL(sec gopass.Secret) string {
 	// check if we have a key-value entry
 	if url, found := sec.Get("otpauth"); found {
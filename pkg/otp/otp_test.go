@@ -44,6 +44,22 @@ func TestCalculate(t *testing.T) {
 	}
 }
 
+func TestCalculateKey(t *testing.T) {
+	t.Parallel()
+
+	s, err := secparse.Parse([]byte(fmt.Sprintf("%s\nwork: %s\nhome: %s", pw, totpSecret, totpURL)))
+	require.NoError(t, err)
+
+	for _, key := range []string{"work", "home"} {
+		k, err := CalculateKey(s, key)
+		require.NoError(t, err, key)
+		assert.Equal(t, "totp", k.Type(), key)
+	}
+
+	_, err = CalculateKey(s, "missing")
+	assert.Error(t, err)
+}
+
 func TestWrite(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,36 @@
+package pwgen
+
+import "strings"
+
+// answerConsonants and answerVowels build the syllables of answers to
+// security questions. Letters that are easily confused when read out, like
+// c, q and x, are left out.
+const (
+	answerConsonants = "bdfghjklmnprstvz"
+	answerVowels     = "aeiou"
+)
+
+// AnswerWords and AnswerSyllables define the shape of answers to security
+// questions. 3 words of 3 syllables have about 56 bits of entropy.
+const (
+	AnswerWords     = 3
+	AnswerSyllables = 3
+)
+
+// GenerateAnswer generates a random but pronounceable fake answer to a
+// security question, e.g. "tavomi rukelo fadesu". Fake answers can't be
+// found out by others, unlike the name of your first pet, and are still easy
+// to read out on the phone.
+func GenerateAnswer() string {
+	words := make([]string, 0, AnswerWords)
+	for i := 0; i < AnswerWords; i++ {
+		var sb strings.Builder
+		for j := 0; j < AnswerSyllables; j++ {
+			sb.WriteByte(answerConsonants[randomInteger(len(answerConsonants))])
+			sb.WriteByte(answerVowels[randomInteger(len(answerVowels))])
+		}
+		words = append(words, sb.String())
+	}
+
+	return strings.Join(words, " ")
+}
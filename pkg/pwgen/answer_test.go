@@ -0,0 +1,21 @@
+package pwgen
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAnswer(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`^([bdfghjklmnprstvz][aeiou]){3}( ([bdfghjklmnprstvz][aeiou]){3}){2}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		a := GenerateAnswer()
+		assert.Regexp(t, re, a)
+		seen[a] = true
+	}
+	assert.Greater(t, len(seen), 95)
+}
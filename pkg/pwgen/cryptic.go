@@ -1,7 +1,6 @@
 package pwgen
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -68,6 +67,7 @@ func NewCrypticForDomain(ctx context.Context, length int, domain string) *Crypti
 	}
 
 	for _, req := range r.Required {
+		req := req
 		chars := charsFromRule(req)
 		if req == "" || strings.TrimSpace(chars) == "" {
 			continue
@@ -169,6 +169,30 @@ func NewCrypticWithCrunchy(length int, symbols bool) *Cryptic {
 	return c
 }
 
+// NoAmbiguous removes visually ambiguous characters, e.g. O and 0 or l, 1
+// and I, from the generator and rejects passwords that contain ambiguous
+// sequences like rn. Use it for passwords that humans have to read and
+// transcribe.
+func (c *Cryptic) NoAmbiguous() error {
+	chars := Prune(c.Chars, Ambiq)
+	if chars == "" {
+		return fmt.Errorf("no unambiguous characters left")
+	}
+
+	c.Chars = chars
+	c.Validators = append(c.Validators, func(pw string) error {
+		for _, p := range AmbiqPairs {
+			if strings.Contains(pw, p) {
+				return fmt.Errorf("password contains ambiguous sequence %q: %w", p, ErrCrypticInvalid)
+			}
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
 // Password returns a single password from the generator.
 func (c *Cryptic) Password() string {
 	round := 0
@@ -216,10 +240,12 @@ func (c *Cryptic) isValid(pw string) bool {
 }
 
 func (c *Cryptic) randomString() string {
-	pw := &bytes.Buffer{}
-	for pw.Len() < c.Length {
-		_ = pw.WriteByte(c.Chars[randomInteger(len(c.Chars))])
+	// some sites allow non-ASCII characters, pick runes, not bytes.
+	chars := []rune(c.Chars)
+	pw := make([]rune, 0, c.Length)
+	for len(pw) < c.Length {
+		pw = append(pw, chars[randomInteger(len(chars))])
 	}
 
-	return pw.String()
+	return string(pw)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
@@ -52,3 +53,23 @@ func TestUniqueChars(t *testing.T) {
 		assert.Equal(t, out, uniqueChars(in))
 	}
 }
+
+func TestCrypticNoAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	c := NewCryptic(64, true)
+	require.NoError(t, c.NoAmbiguous())
+
+	for i := 0; i < 16; i++ {
+		pw := c.Password()
+		require.Len(t, pw, 64)
+		assert.False(t, strings.ContainsAny(pw, Ambiq), pw)
+		for _, p := range AmbiqPairs {
+			assert.NotContains(t, pw, p)
+		}
+	}
+
+	c = NewCryptic(8, false)
+	c.Chars = "0O1l"
+	assert.Error(t, c.NoAmbiguous())
+}
@@ -20,6 +20,12 @@ var (
 // GenerateExternal will invoke an external password generator,
 // if set, and return it's output.
 func GenerateExternal(pwlen int) (string, error) {
+	return GenerateExternalWith(pwlen, exec.Command)
+}
+
+// GenerateExternalWith is like GenerateExternal but uses cmdFn to create the
+// command, e.g. to confine the generator.
+func GenerateExternalWith(pwlen int, cmdFn func(string, ...string) *exec.Cmd) (string, error) {
 	c := os.Getenv("GOPASS_EXTERNAL_PWGEN")
 	if c == "" {
 		return "", ErrNoExternal
@@ -43,7 +49,7 @@ func GenerateExternal(pwlen int) (string, error) {
 
 	args = append(args, strconv.Itoa(pwlen))
 
-	out, err := exec.Command(exe, args...).Output()
+	out, err := cmdFn(exe, args...).Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to execute %s %v: %w", exe, args, err)
 	}
@@ -0,0 +1,89 @@
+package pwgen
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownLayout is returned for keyboard layouts without an exclusion table.
+var ErrUnknownLayout = errors.New("unknown keyboard layout")
+
+// layoutUnsafe lists the characters that are typed with a different key than
+// on a US QWERTY keyboard or require AltGr or dead keys on the given layout.
+// Consoles, BIOS setup screens and many KVM switches assume a US layout, so
+// these characters come out differently when typed there.
+var layoutUnsafe = map[string]string{
+	"us": "",
+	"uk": "\"#@\\|~",
+	// QWERTZ: y and z are swapped, only a few symbols stay in place.
+	"de": "yzYZ" + Prune(Syms, "!$%,."),
+	"ch": "yzYZ" + Prune(Syms, "%,."),
+	// AZERTY: digits need shift and no symbol stays in place.
+	"fr": "aqzwmAQZWM" + Digits + Syms,
+	"be": "aqzwmAQZWM" + Digits + Syms,
+	"es": Prune(Syms, "!$%,."),
+	"it": Prune(Syms, "!$%,."),
+	"pt": Prune(Syms, "!$%,."),
+	"dk": Prune(Syms, "!#%,."),
+	"fi": Prune(Syms, "!#%,."),
+	"no": Prune(Syms, "!#%,."),
+	"se": Prune(Syms, "!#%,."),
+	// only the number row and a few symbols are shared with QWERTY.
+	"dvorak": Prune(Lower+Upper, "amAM") + "-_=+[]{};:'\",<.>/?",
+}
+
+// Layouts returns the names of the supported keyboard layouts.
+func Layouts() []string {
+	layouts := make([]string, 0, len(layoutUnsafe))
+	for k := range layoutUnsafe {
+		layouts = append(layouts, k)
+	}
+	sort.Strings(layouts)
+
+	return layouts
+}
+
+// LayoutUnsafe returns the characters that are unsafe to type on any of the
+// given keyboard layouts.
+func LayoutUnsafe(layouts ...string) (string, error) {
+	var unsafe string
+	for _, l := range layouts {
+		chars, found := layoutUnsafe[strings.ToLower(strings.TrimSpace(l))]
+		if !found {
+			return "", fmt.Errorf("%w %q, use one of %s", ErrUnknownLayout, l, strings.Join(Layouts(), ", "))
+		}
+		unsafe += chars
+	}
+
+	return unsafe, nil
+}
+
+// LayoutSafe removes all characters from chars that are unsafe to type on any
+// of the given keyboard layouts.
+func LayoutSafe(chars string, layouts ...string) (string, error) {
+	unsafe, err := LayoutUnsafe(layouts...)
+	if err != nil {
+		return "", err
+	}
+
+	return Prune(chars, unsafe), nil
+}
+
+// RestrictToLayouts restricts the generator to characters that are safe to
+// type on all of the given keyboard layouts.
+func (c *Cryptic) RestrictToLayouts(layouts ...string) error {
+	chars, err := LayoutSafe(c.Chars, layouts...)
+	if err != nil {
+		return err
+	}
+
+	if chars == "" {
+		return fmt.Errorf("no characters left that are safe to type on %s", strings.Join(layouts, ", "))
+	}
+
+	c.Chars = chars
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package pwgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutSafe(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		layouts []string
+		in      string
+		out     string
+	}{
+		{layouts: []string{"us"}, in: CharAll, out: CharAll},
+		{layouts: []string{"de"}, in: "xyzXYZ019!@$%", out: "xX019!$%"},
+		{layouts: []string{"fr"}, in: "abqz019!", out: "b"},
+		{layouts: []string{"uk", "se"}, in: "a!\"#%@", out: "a!%"},
+		{layouts: []string{" DE "}, in: "yx", out: "x"},
+	} {
+		got, err := LayoutSafe(tc.in, tc.layouts...)
+		require.NoError(t, err, tc.layouts)
+		assert.Equal(t, tc.out, got, tc.layouts)
+	}
+
+	_, err := LayoutSafe(CharAll, "klingon")
+	assert.ErrorIs(t, err, ErrUnknownLayout)
+}
+
+func TestLayoutsHaveTables(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range Layouts() {
+		unsafe, err := LayoutUnsafe(l)
+		require.NoError(t, err)
+		// every layout must leave enough characters for a password.
+		assert.GreaterOrEqual(t, len(Prune(CharAll, unsafe)), 24, l)
+	}
+}
+
+func TestCrypticRestrictToLayouts(t *testing.T) {
+	t.Parallel()
+
+	c := NewCryptic(32, true)
+	require.NoError(t, c.RestrictToLayouts("de", "fr"))
+
+	pw := c.Password()
+	assert.Len(t, pw, 32)
+	assert.False(t, strings.ContainsAny(pw, "yzaqwmYZAQWM0123456789@[]{}"), pw)
+
+	c = NewCryptic(8, false)
+	c.Chars = "yz"
+	assert.Error(t, c.RestrictToLayouts("de"))
+}
@@ -37,6 +37,11 @@ func GenerateMemorablePassword(minLength int, symbols bool, capitals bool) strin
 	return sb.String()
 }
 
+// RandomWord returns a random word from the built-in english word list.
+func RandomWord() string {
+	return randomWord()
+}
+
 func randomWord() string {
 	return wordlist[randomInteger(len(wordlist))]
 }
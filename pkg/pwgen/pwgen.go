@@ -29,6 +29,10 @@ const (
 	CharAll = Digits + Upper + Lower + Syms
 )
 
+// AmbiqPairs are character sequences that are easily mistaken for a single
+// character when read, e.g. rn for m or vv for w.
+var AmbiqPairs = []string{"rn", "vv", "VV", "cl"}
+
 // GeneratePassword generates a random, hard to remember password.
 func GeneratePassword(length int, symbols bool) string {
 	chars := Digits + Upper + Lower
@@ -45,6 +45,7 @@ func TestPwgenNoCrand(t *testing.T) {
 
 	defer func() {
 		rand.Reader = old
+		fellBack.Store(false)
 	}()
 
 	oldOut := os.Stdout
@@ -63,6 +64,7 @@ func TestPwgenNoCrand(t *testing.T) {
 	mrand.Seed(1789)
 
 	n := randomInteger(1024)
+	assert.True(t, UsedFallback())
 
 	assert.NoError(t, w.Close())
 
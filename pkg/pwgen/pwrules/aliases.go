@@ -15,10 +15,18 @@ func LookupAliases(ctx context.Context, domain string) []string {
 	customAliases := loadCustomAliases(ctx)
 	aliases := make([]string, 0, len(genAliases[domain])+len(customAliases[domain]))
 	aliases = append(aliases, genAliases[domain]...)
+	if d := loadDatabase(); d != nil {
+		aliases = append(aliases, d.aliases[domain]...)
+	}
 	aliases = append(aliases, customAliases[domain]...)
-	sort.Strings(aliases)
 
-	return aliases
+	return set.Sorted(aliases)
+}
+
+// LookupCustomAliases returns the aliases of the given domain that are
+// configured by the user (domain-alias.<to>.insteadOf).
+func LookupCustomAliases(ctx context.Context, domain string) []string {
+	return loadCustomAliases(ctx)[domain]
 }
 
 // AllAliases returns all aliases.
@@ -29,6 +37,12 @@ func AllAliases(ctx context.Context) map[string][]string {
 		all[k] = append(all[k], v...)
 	}
 
+	if d := loadDatabase(); d != nil {
+		for k, v := range d.aliases {
+			all[k] = set.Sorted(append(all[k], v...))
+		}
+	}
+
 	for k, v := range customAliases {
 		all[k] = append(all[k], v...)
 	}
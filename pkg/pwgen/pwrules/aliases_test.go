@@ -32,3 +32,24 @@ func TestLoadCustomRules(t *testing.T) {
 
 	assert.Greater(t, len(AllAliases(ctx)), 256)
 }
+
+func TestLookupRuleDomain(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewNoWrites()
+	assert.NoError(t, cfg.Set("", "domain-alias.dkb.de.insteadOf", "my-bank.de"))
+	ctx := cfg.WithConfig(context.Background())
+
+	r, domain, found := LookupRuleDomain(ctx, "my-bank.de")
+	assert.True(t, found)
+	assert.Equal(t, "dkb.de", domain)
+	assert.Equal(t, 8, r.Minlen)
+	assert.Equal(t, []string{"dkb.de"}, LookupCustomAliases(ctx, "my-bank.de"))
+
+	_, domain, found = LookupRuleDomain(ctx, "dkb.de")
+	assert.True(t, found)
+	assert.Equal(t, "dkb.de", domain)
+
+	_, _, found = LookupRuleDomain(ctx, "example.org")
+	assert.False(t, found)
+}
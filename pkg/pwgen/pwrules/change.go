@@ -18,15 +18,29 @@ func init() {
 // LookupChangeURL looks up a change URL, either directly or through
 // one of it's know aliases.
 func LookupChangeURL(ctx context.Context, domain string) string {
-	if u, found := changeURLs[domain]; found {
+	if u, found := lookupChangeURL(domain); found {
 		return u
 	}
 
 	for _, alias := range LookupAliases(ctx, domain) {
-		if u, found := changeURLs[alias]; found {
+		if u, found := lookupChangeURL(alias); found {
 			return u
 		}
 	}
 
 	return ""
 }
+
+// lookupChangeURL returns the change URL of the domain from the local
+// database or the built-in ones.
+func lookupChangeURL(domain string) (string, bool) {
+	if d := loadDatabase(); d != nil {
+		if u, found := d.changes[domain]; found {
+			return u, true
+		}
+	}
+
+	u, found := changeURLs[domain]
+
+	return u, found
+}
@@ -0,0 +1,152 @@
+package pwrules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Database is the format of the update feed and the local database. The
+// built-in rules, change URLs and aliases are generated at build time.
+// `gopass updatedb` fetches newer ones into the local database which takes
+// precedence over the built-in ones, so rule coverage improves without a new
+// release. It uses the formats of
+// https://github.com/apple/password-manager-resources.
+type Database struct {
+	// Generated is the time the feed was created.
+	Generated time.Time `json:"generated"`
+	// Aliases lists groups of domains sharing their credentials.
+	Aliases [][]string `json:"aliases,omitempty"`
+	// ChangeURLs maps domains to the URL of their change password form.
+	ChangeURLs map[string]string `json:"change-urls,omitempty"`
+	// Rules maps domains to their password rules.
+	Rules map[string]DatabaseRule `json:"rules,omitempty"`
+}
+
+// DatabaseRule is a password rule in the database.
+type DatabaseRule struct {
+	Exact bool   `json:"exact-domain-match-only,omitempty"`
+	Rules string `json:"password-rules"`
+}
+
+type database struct {
+	generated time.Time
+	aliases   map[string][]string
+	changes   map[string]string
+	rules     map[string]Rule
+}
+
+var (
+	dbMu     sync.RWMutex
+	dbLoaded bool
+	db       *database
+)
+
+// DatabaseFile returns the location of the local database.
+func DatabaseFile() string {
+	return filepath.Join(appdir.UserCache(), "gopass", "pwrules.json")
+}
+
+// ParseDatabase parses a database.
+func ParseDatabase(buf []byte) (*Database, error) {
+	d := &Database{}
+	if err := json.Unmarshal(buf, d); err != nil {
+		return nil, fmt.Errorf("failed to parse password rules database: %w", err)
+	}
+
+	if d.Generated.IsZero() {
+		return nil, fmt.Errorf("password rules database has no timestamp")
+	}
+
+	return d, nil
+}
+
+// SetDatabase replaces the local database for this process. Passing nil
+// restores the built-in rules.
+func SetDatabase(d *Database) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	dbLoaded = true
+	if d == nil {
+		db = nil
+
+		return
+	}
+
+	db = &database{
+		generated: d.Generated,
+		aliases:   make(map[string][]string, len(d.Aliases)),
+		changes:   make(map[string]string, len(d.ChangeURLs)),
+		rules:     make(map[string]Rule, len(d.Rules)),
+	}
+
+	for _, as := range d.Aliases {
+		for _, a := range as {
+			db.aliases[a] = as
+		}
+	}
+
+	for k, v := range d.ChangeURLs {
+		if v == "" {
+			continue
+		}
+		db.changes[k] = v
+	}
+
+	for domain, dr := range d.Rules {
+		r := ParseRule(dr.Rules)
+		r.Exact = dr.Exact
+		db.rules[domain] = r
+	}
+}
+
+// DatabaseGenerated returns the time the local database was generated at. It
+// is zero if there is none.
+func DatabaseGenerated() time.Time {
+	d := loadDatabase()
+	if d == nil {
+		return time.Time{}
+	}
+
+	return d.generated
+}
+
+// loadDatabase reads the local database on first use.
+func loadDatabase() *database {
+	dbMu.RLock()
+	if dbLoaded {
+		defer dbMu.RUnlock()
+
+		return db
+	}
+	dbMu.RUnlock()
+
+	var d *Database
+
+	buf, err := os.ReadFile(DatabaseFile())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		debug.Log("failed to read password rules database: %s", err)
+	default:
+		d, err = ParseDatabase(buf)
+		if err != nil {
+			debug.Log("ignoring local password rules database: %s", err)
+		}
+	}
+
+	SetDatabase(d)
+
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+
+	return db
+}
@@ -2,6 +2,7 @@ package pwrules
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"sort"
 	"strconv"
@@ -16,24 +17,59 @@ var reChars = regexp.MustCompile(`(allowed|required):\s*\[(.*)\](?:;|,)`)
 
 // AllRules returns all rules.
 func AllRules() map[string]Rule {
-	return genRules
+	d := loadDatabase()
+	if d == nil {
+		return genRules
+	}
+
+	all := make(map[string]Rule, len(genRules)+len(d.rules))
+	for k, v := range genRules {
+		all[k] = v
+	}
+	for k, v := range d.rules {
+		all[k] = v
+	}
+
+	return all
+}
+
+// lookupRule returns the rule of the domain from the local database or the
+// built-in rules.
+func lookupRule(domain string) (Rule, bool) {
+	if d := loadDatabase(); d != nil {
+		if r, found := d.rules[domain]; found {
+			return r, true
+		}
+	}
+
+	r, found := genRules[domain]
+
+	return r, found
 }
 
 // LookupRule looks up a rule either directly or through one of it's know
 // aliases.
 func LookupRule(ctx context.Context, domain string) (Rule, bool) {
-	r, found := genRules[domain]
+	r, _, found := LookupRuleDomain(ctx, domain)
+
+	return r, found
+}
+
+// LookupRuleDomain looks up a rule like LookupRule and also returns the
+// domain the rule belongs to, i.e. the domain itself or one of its aliases.
+func LookupRuleDomain(ctx context.Context, domain string) (Rule, string, bool) {
+	r, found := lookupRule(domain)
 	if found {
-		return r, true
+		return r, domain, true
 	}
 
 	for _, alias := range LookupAliases(ctx, domain) {
-		if r, found := genRules[alias]; found {
-			return r, true
+		if r, found := lookupRule(alias); found {
+			return r, alias, true
 		}
 	}
 
-	return Rule{}, false
+	return Rule{}, "", false
 }
 
 // Rule is a password rule as defined by Apple at https://developer.apple.com/password-rules/
@@ -46,6 +82,33 @@ type Rule struct {
 	Exact     bool
 }
 
+// String returns the rule in the syntax of the password rules database,
+// e.g. "minlength: 8; required: digit; allowed: lower;".
+func (r Rule) String() string {
+	var parts []string
+	if r.Minlen > 0 {
+		parts = append(parts, fmt.Sprintf("minlength: %d", r.Minlen))
+	}
+	if r.Maxlen > 0 {
+		parts = append(parts, fmt.Sprintf("maxlength: %d", r.Maxlen))
+	}
+	for _, req := range r.Required {
+		parts = append(parts, "required: "+req)
+	}
+	if len(r.Allowed) > 0 {
+		parts = append(parts, "allowed: "+strings.Join(r.Allowed, ", "))
+	}
+	if r.Maxconsec > 0 {
+		parts = append(parts, fmt.Sprintf("max-consecutive: %d", r.Maxconsec))
+	}
+
+	if len(parts) < 1 {
+		return ""
+	}
+
+	return strings.Join(parts, "; ") + ";"
+}
+
 // ParseRule parses a password rule.
 // NOTE: This is not a complete parser.
 func ParseRule(in string) Rule {
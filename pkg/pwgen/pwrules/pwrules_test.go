@@ -61,3 +61,17 @@ func TestParseRule(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleString(t *testing.T) {
+	t.Parallel()
+
+	r := Rule{
+		Minlen:    8,
+		Maxlen:    20,
+		Required:  []string{"digit", "upper"},
+		Allowed:   []string{"[-_]", "lower"},
+		Maxconsec: 3,
+	}
+	assert.Equal(t, "minlength: 8; maxlength: 20; required: digit; required: upper; allowed: [-_], lower; max-consecutive: 3;", r.String())
+	assert.Equal(t, "", Rule{}.String())
+}
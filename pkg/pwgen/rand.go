@@ -2,25 +2,111 @@ package pwgen
 
 import (
 	crand "crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"math/rand"
 	"os"
+	"sync"
 	"time"
 )
 
+// UnsafeDeterministicEnv must be set to 1 to replace crypto/rand with a
+// seeded source. Passwords generated that way are predictable, this is only
+// meant for tests and demo recordings.
+const UnsafeDeterministicEnv = "GOPASS_UNSAFE_DETERMINISTIC"
+
+// ErrDeterministicDisabled is returned when trying to replace the random
+// source without setting UnsafeDeterministicEnv.
+var ErrDeterministicDisabled = errors.New("a custom random source requires " + UnsafeDeterministicEnv + "=1")
+
+var (
+	randMu sync.Mutex
+	// randSource replaces crypto/rand if set by SetSource.
+	randSource io.Reader
+)
+
 func init() {
 	// seed math/rand in case we have to fall back to using it
 	rand.Seed(time.Now().Unix() + int64(os.Getpid()+os.Getppid()))
 }
 
+// SetSource replaces the random source of all generators. It fails unless
+// UnsafeDeterministicEnv is set to 1.
+func SetSource(r io.Reader) error {
+	if os.Getenv(UnsafeDeterministicEnv) != "1" {
+		return ErrDeterministicDisabled
+	}
+
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	randSource = r
+
+	return nil
+}
+
+// Seed makes all generators return the same passwords for the same seed.
+// See SetSource.
+func Seed(seed int64) error {
+	return SetSource(rand.New(rand.NewSource(seed))) //nolint:gosec
+}
+
+// ResetSource restores crypto/rand as the random source.
+func ResetSource() {
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	randSource = nil
+}
+
+// IsDeterministic returns true if the random source was replaced.
+func IsDeterministic() bool {
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	return randSource != nil
+}
+
+// source returns the current random source. The caller must hold randMu.
+func source() io.Reader {
+	if randSource != nil {
+		return randSource
+	}
+
+	return crand.Reader
+}
+
+// readRandom fills b from the current random source.
+func readRandom(b []byte) error {
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	if _, err := io.ReadFull(source(), b); err != nil {
+		return fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return nil
+}
+
+// RandomInt returns a random number in [0, max) from the current random
+// source.
+func RandomInt(max int) int {
+	return randomInteger(max)
+}
+
 func randomInteger(max int) int {
-	i, err := crand.Int(crand.Reader, big.NewInt(int64(max)))
+	randMu.Lock()
+	i, err := crand.Int(source(), big.NewInt(int64(max)))
+	randMu.Unlock()
+
 	if err == nil {
 		return int(i.Int64())
 	}
 
 	fmt.Fprintln(os.Stderr, "WARNING: No crypto/rand available. Falling back to PRNG")
+	fellBack.Store(true)
 
 	return rand.Intn(max)
 }
@@ -0,0 +1,41 @@
+package pwgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	defer ResetSource()
+
+	t.Setenv(UnsafeDeterministicEnv, "")
+	assert.ErrorIs(t, Seed(42), ErrDeterministicDisabled)
+	assert.False(t, IsDeterministic())
+
+	t.Setenv(UnsafeDeterministicEnv, "1")
+
+	gen := func() []string {
+		t.Helper()
+
+		require.NoError(t, Seed(42))
+
+		uuid, err := GenerateUUID()
+		require.NoError(t, err)
+
+		return []string{
+			GeneratePassword(24, true),
+			GenerateMemorablePassword(20, true, true),
+			uuid,
+		}
+	}
+
+	first := gen()
+	assert.True(t, IsDeterministic())
+	assert.Equal(t, first, gen())
+
+	ResetSource()
+	assert.False(t, IsDeterministic())
+	assert.NotEqual(t, first[0], GeneratePassword(24, true))
+}
@@ -0,0 +1,78 @@
+package pwgen
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"sync/atomic"
+)
+
+// ErrInsecureRNG is returned if the system CSPRNG looks unsafe to use.
+var ErrInsecureRNG = errors.New("the system random number generator looks unsafe")
+
+// rngSampleSize is the number of bytes read to check the plausibility of the
+// CSPRNG output.
+const rngSampleSize = 4096
+
+// fellBack is set once randomInteger had to fall back to math/rand.
+var fellBack atomic.Bool
+
+// UsedFallback returns true if any password was generated with the insecure
+// math/rand fallback because crypto/rand failed.
+func UsedFallback() bool {
+	return fellBack.Load()
+}
+
+// CheckRNG verifies that the system CSPRNG is available, properly seeded
+// (e.g. getrandom(2) would not block on Linux) and that its output is
+// plausible. This catches broken containers and VMs, it can not prove the
+// randomness of the output.
+func CheckRNG() error {
+	if err := rngReady(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInsecureRNG, err)
+	}
+
+	buf := make([]byte, rngSampleSize)
+	if _, err := io.ReadFull(crand.Reader, buf); err != nil {
+		return fmt.Errorf("%w: failed to read from crypto/rand: %w", ErrInsecureRNG, err)
+	}
+
+	if err := plausible(buf); err != nil {
+		return fmt.Errorf("%w: %w", ErrInsecureRNG, err)
+	}
+
+	if UsedFallback() {
+		return fmt.Errorf("%w: crypto/rand failed before and passwords were generated with math/rand", ErrInsecureRNG)
+	}
+
+	return nil
+}
+
+// plausible performs some cheap sanity checks on random bytes. A working
+// CSPRNG fails these with negligible probability.
+func plausible(buf []byte) error {
+	// the ratio of one bits must be close to 50%. For 32768 bits the
+	// tolerance is more than 10 standard deviations.
+	var ones int
+	for _, b := range buf {
+		ones += bits.OnesCount8(b)
+	}
+
+	total := len(buf) * 8
+	if ones*100 < total*47 || ones*100 > total*53 {
+		return fmt.Errorf("biased output (%d of %d bits set)", ones, total)
+	}
+
+	// a stuck or looping generator repeats itself.
+	const block = 16
+	for i := block; i+block <= len(buf); i += block {
+		if bytes.Equal(buf[:block], buf[i:i+block]) {
+			return fmt.Errorf("repeating output at offset %d", i)
+		}
+	}
+
+	return nil
+}
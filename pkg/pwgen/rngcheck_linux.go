@@ -0,0 +1,39 @@
+//go:build linux
+// +build linux
+
+package pwgen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// rngReady checks if the kernel entropy pool is initialized. crypto/rand
+// would block otherwise.
+func rngReady() error {
+	buf := make([]byte, 16)
+
+	_, err := unix.Getrandom(buf, unix.GRND_NONBLOCK)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, unix.EAGAIN):
+		return fmt.Errorf("the kernel entropy pool is not initialized, yet (getrandom would block)")
+	case errors.Is(err, unix.ENOSYS):
+		// old kernels. crypto/rand falls back to /dev/urandom.
+		fi, err := os.Stat("/dev/urandom")
+		if err != nil {
+			return fmt.Errorf("getrandom is not available and /dev/urandom is missing: %w", err)
+		}
+		if fi.Mode()&os.ModeCharDevice == 0 {
+			return fmt.Errorf("getrandom is not available and /dev/urandom is not a character device")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("getrandom failed: %w", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package pwgen
+
+// rngReady is a no-op. Other platforms don't expose the state of their
+// CSPRNG and never block.
+func rngReady() error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package pwgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRNG(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckRNG())
+}
+
+func TestPlausible(t *testing.T) {
+	t.Parallel()
+
+	assert.Error(t, plausible(make([]byte, rngSampleSize)))
+	assert.Error(t, plausible(bytes.Repeat([]byte{0xff}, rngSampleSize)))
+	// balanced but repeating.
+	assert.Error(t, plausible(bytes.Repeat([]byte{0x0f, 0xf0, 0x55, 0xaa}, rngSampleSize/4)))
+}
@@ -0,0 +1,74 @@
+package pwgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token formats. Many stored "passwords" are actually API keys or other
+// tokens that have to follow a certain format.
+const (
+	TokenUUID   = "uuid"
+	TokenHex    = "hex"
+	TokenBase64 = "base64"
+	TokenBase58 = "b58"
+)
+
+const (
+	hexChars = "0123456789abcdef"
+	// base64Chars is the URL safe alphabet of RFC 4648 so tokens can be used
+	// in URLs and file names as is.
+	base64Chars = Upper + Lower + Digits + "-_"
+	// base58Chars is the Bitcoin alphabet. It lacks 0, O, I and l.
+	base58Chars = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// TokenFormats returns the supported token formats.
+func TokenFormats() []string {
+	return []string{TokenUUID, TokenHex, TokenBase64, TokenBase58}
+}
+
+// IsTokenFormat returns true if format is a supported token format.
+func IsTokenFormat(format string) bool {
+	for _, f := range TokenFormats() {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GenerateToken generates a random token in the given format. The length is
+// the number of characters and ignored for UUIDs.
+func GenerateToken(format string, length int) (string, error) {
+	if length < 1 {
+		length = 32
+	}
+
+	switch format {
+	case TokenUUID:
+		return GenerateUUID()
+	case TokenHex:
+		return GeneratePasswordCharset(length, hexChars), nil
+	case TokenBase64:
+		return GeneratePasswordCharset(length, base64Chars), nil
+	case TokenBase58:
+		return GeneratePasswordCharset(length, base58Chars), nil
+	default:
+		return "", fmt.Errorf("unknown token format %q, use one of %s", format, strings.Join(TokenFormats(), ", "))
+	}
+}
+
+// GenerateUUID generates a random (version 4) UUID.
+func GenerateUUID() (string, error) {
+	var b [16]byte
+	if err := readRandom(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,35 @@
+package pwgen
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateToken(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		format string
+		length int
+		re     string
+	}{
+		{format: TokenUUID, length: 8, re: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{format: TokenHex, length: 40, re: `^[0-9a-f]{40}$`},
+		{format: TokenBase64, length: 43, re: `^[A-Za-z0-9_-]{43}$`},
+		{format: TokenBase58, length: 22, re: `^[1-9A-HJ-NP-Za-km-z]{22}$`},
+		{format: TokenHex, length: 0, re: `^[0-9a-f]{32}$`},
+	} {
+		tok, err := GenerateToken(tc.format, tc.length)
+		require.NoError(t, err, tc.format)
+		assert.Regexp(t, regexp.MustCompile(tc.re), tok, tc.format)
+	}
+
+	_, err := GenerateToken("rot13", 8)
+	assert.Error(t, err)
+
+	assert.True(t, IsTokenFormat(TokenBase58))
+	assert.False(t, IsTokenFormat("xkcd"))
+}
@@ -0,0 +1,65 @@
+package pwgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Username styles. All of them combine an adjective, a noun and a number,
+// e.g. brave-otter-42.
+const (
+	UsernameKebab = "kebab"
+	UsernameSnake = "snake"
+	UsernameCamel = "camel"
+	UsernamePlain = "plain"
+)
+
+// usernameAdjectives and usernameNouns are short and neutral, so the
+// generated usernames look like ones a human would pick.
+var (
+	usernameAdjectives = strings.Fields(`
+able agile amber ancient bold brave breezy bright brisk calm candid clever
+cosmic crisp curious daring dusty eager early easy electric fancy fearless
+fierce fluffy frosty gentle giddy golden grand happy hardy hidden humble icy
+jolly keen kind lively lucky lunar mellow merry mighty misty modest noble
+nimble odd patient plucky polar proud quick quiet rapid rare restless robust
+rosy rustic sandy scarlet shiny silent silver sleepy sly smooth snowy solar
+spicy steady stormy sunny swift tidy tiny tranquil velvet vivid wandering
+warm wild windy wise witty young zesty`)
+	usernameNouns = strings.Fields(`
+acorn badger beacon bear beaver bison breeze brook canyon cedar comet coral
+cougar coyote crane crow dolphin dragon eagle ember falcon fern finch fjord
+fox gecko glacier harbor hawk heron island jaguar koala lark lemur leopard
+lion llama lynx maple meadow meteor moose moth narwhal nebula oak ocelot orca
+otter owl panda panther pebble pelican penguin pine planet puffin quail
+rabbit raven reef river robin rocket salmon sparrow spruce squirrel stone
+summit swan tiger toucan tundra turtle valley viper walrus willow wolf wombat
+yak zebra`)
+)
+
+// UsernameStyles returns the supported username styles.
+func UsernameStyles() []string {
+	return []string{UsernameKebab, UsernameSnake, UsernameCamel, UsernamePlain}
+}
+
+// GenerateUsername generates a random but human-looking username in the
+// given style, e.g. brave-otter-42. An empty style selects kebab.
+func GenerateUsername(style string) (string, error) {
+	adj := usernameAdjectives[randomInteger(len(usernameAdjectives))]
+	noun := usernameNouns[randomInteger(len(usernameNouns))]
+	num := strconv.Itoa(10 + randomInteger(990))
+
+	switch style {
+	case "", UsernameKebab:
+		return adj + "-" + noun + "-" + num, nil
+	case UsernameSnake:
+		return adj + "_" + noun + "_" + num, nil
+	case UsernameCamel:
+		return strings.ToUpper(adj[:1]) + adj[1:] + strings.ToUpper(noun[:1]) + noun[1:] + num, nil
+	case UsernamePlain:
+		return adj + noun + num, nil
+	default:
+		return "", fmt.Errorf("unknown username style %q. Supported styles: %s", style, strings.Join(UsernameStyles(), ", "))
+	}
+}
@@ -0,0 +1,31 @@
+package pwgen
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateUsername(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		style string
+		re    string
+	}{
+		{style: "", re: `^[a-z]+-[a-z]+-[0-9]{2,3}$`},
+		{style: UsernameKebab, re: `^[a-z]+-[a-z]+-[0-9]{2,3}$`},
+		{style: UsernameSnake, re: `^[a-z]+_[a-z]+_[0-9]{2,3}$`},
+		{style: UsernameCamel, re: `^[A-Z][a-z]+[A-Z][a-z]+[0-9]{2,3}$`},
+		{style: UsernamePlain, re: `^[a-z]+[0-9]{2,3}$`},
+	} {
+		u, err := GenerateUsername(tc.style)
+		require.NoError(t, err, tc.style)
+		assert.Regexp(t, regexp.MustCompile(tc.re), u, tc.style)
+	}
+
+	_, err := GenerateUsername("leet")
+	assert.Error(t, err)
+}
@@ -1,7 +1,12 @@
 package pwgen
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
 )
 
 // containsAllClasses validates that the password contains at least one
@@ -54,3 +59,66 @@ func containsMaxConsecutive(pw string, n int) bool {
 
 	return true
 }
+
+// ValidateRule checks that the password complies with the given site rule,
+// i.e. its length, the required and allowed characters and the maximum
+// number of identical consecutive characters. It returns one error per
+// violation.
+func ValidateRule(r pwrules.Rule, pw string) error {
+	var errs []error
+
+	if n := utf8.RuneCountInString(pw); r.Minlen > 0 && n < r.Minlen {
+		errs = append(errs, fmt.Errorf("shorter than %d characters", r.Minlen))
+	} else if r.Maxlen > 0 && n > r.Maxlen {
+		errs = append(errs, fmt.Errorf("longer than %d characters", r.Maxlen))
+	}
+
+	for _, req := range r.Required {
+		chars := charsFromRule(req)
+		if strings.TrimSpace(chars) == "" {
+			continue
+		}
+
+		if !strings.ContainsAny(pw, chars) {
+			errs = append(errs, fmt.Errorf("no character of the required class %s", req))
+		}
+	}
+
+	if allowed := charsFromRule(append(r.Required, r.Allowed...)...); allowed != "" {
+		for _, c := range pw {
+			if !strings.ContainsRune(allowed, c) {
+				errs = append(errs, fmt.Errorf("contains characters that are not allowed"))
+
+				break
+			}
+		}
+	}
+
+	if r.Maxconsec > 0 && maxConsecutive(pw) > r.Maxconsec {
+		errs = append(errs, fmt.Errorf("more than %d identical characters in a row", r.Maxconsec))
+	}
+
+	return errors.Join(errs...)
+}
+
+// maxConsecutive returns the length of the longest run of identical
+// characters.
+func maxConsecutive(pw string) int {
+	var maxN, n int
+
+	var last rune
+	for i, c := range []rune(pw) {
+		if i > 0 && c == last {
+			n++
+		} else {
+			n = 1
+		}
+
+		last = c
+		if n > maxN {
+			maxN = n
+		}
+	}
+
+	return maxN
+}
@@ -1,8 +1,11 @@
 package pwgen
 
 import (
+	"context"
 	"testing"
 
+	"github.com/gopasspw/gopass/pkg/pwgen/pwrules"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +50,43 @@ func TestContainsOnly(t *testing.T) {
 		assert.False(t, containsOnlyClasses(tc, Upper+Lower))
 	}
 }
+
+func TestValidateRule(t *testing.T) {
+	t.Parallel()
+
+	r := pwrules.Rule{
+		Minlen:    8,
+		Maxlen:    12,
+		Required:  []string{"digit", "upper"},
+		Allowed:   []string{"lower", "[-_]"},
+		Maxconsec: 2,
+	}
+
+	assert.NoError(t, ValidateRule(r, "abc-Def_12"))
+	assert.Error(t, ValidateRule(r, "aB1"), "too short")
+	assert.Error(t, ValidateRule(r, "abcDef_12abcDef"), "too long")
+	assert.Error(t, ValidateRule(r, "abc-def_12"), "no upper")
+	assert.Error(t, ValidateRule(r, "abc!Def_12"), "not allowed")
+	assert.Error(t, ValidateRule(r, "abbbDef_12"), "consecutive")
+
+	// unparseable classes are ignored.
+	assert.NoError(t, ValidateRule(pwrules.Rule{Required: []string{".:"}}, "foo"))
+}
+
+func TestCrypticForDomainValid(t *testing.T) {
+	t.Parallel()
+
+	for domain, r := range pwrules.AllRules() {
+		length := r.Minlen
+		if length < 16 {
+			length = 16
+		}
+
+		pw := NewCrypticForDomain(context.Background(), length, domain).Password()
+		if pw == "" {
+			continue
+		}
+
+		assert.NoError(t, ValidateRule(r, pw), domain)
+	}
+}
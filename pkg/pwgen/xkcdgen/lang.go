@@ -0,0 +1,120 @@
+package xkcdgen
+
+import (
+	"embed"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// DefaultLang is used if the language of the locale has no word list.
+const DefaultLang = "en"
+
+// builtinLangs are provided by xkcdpwgen.
+var builtinLangs = []string{"de", "en"}
+
+// wordlists contains the word lists of the languages not provided by
+// xkcdpwgen, one lower case word per line.
+//
+//go:embed wordlists/*.txt
+var wordlists embed.FS
+
+// Languages returns all languages with a word list.
+func Languages() []string {
+	langs := append([]string{}, builtinLangs...)
+
+	entries, err := wordlists.ReadDir("wordlists")
+	if err != nil {
+		return langs
+	}
+
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+// wordlist returns the embedded word list for lang. It returns nil for the
+// languages provided by xkcdpwgen.
+func wordlist(lang string) []string {
+	buf, err := wordlists.ReadFile(path.Join("wordlists", lang+".txt"))
+	if err != nil {
+		return nil
+	}
+
+	return strings.Fields(string(buf))
+}
+
+// DetectLang returns the language of the current locale, e.g. fr for
+// fr_FR.UTF-8, or DefaultLang if there is no word list for it.
+func DetectLang() string {
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(k)
+		if v == "" {
+			continue
+		}
+
+		lang := strings.ToLower(v)
+		if i := strings.IndexAny(lang, "_.@-"); i > 0 {
+			lang = lang[:i]
+		}
+
+		for _, l := range Languages() {
+			if l == lang {
+				debug.Log("using lang %s from %s=%s", lang, k, v)
+
+				return lang
+			}
+		}
+
+		break
+	}
+
+	return DefaultLang
+}
+
+// capitalize upper cases the first letter of the word.
+func capitalize(w string) string {
+	r, n := utf8.DecodeRuneInString(w)
+	if r == utf8.RuneError {
+		return w
+	}
+
+	return string(unicode.ToUpper(r)) + w[n:]
+}
+
+// diacritics maps lower case letters with diacritics to their base letters.
+var diacritics = []string{
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ÿ", "y",
+	"æ", "ae", "œ", "oe", "ß", "ss",
+}
+
+var diacriticsReplacer = func() *strings.Replacer {
+	pairs := make([]string, 0, 2*len(diacritics))
+	for i := 0; i < len(diacritics); i += 2 {
+		pairs = append(pairs, diacritics[i], diacritics[i+1])
+		if up := strings.ToUpper(diacritics[i]); up != diacritics[i] {
+			pairs = append(pairs, up, capitalize(diacritics[i+1]))
+		}
+	}
+
+	return strings.NewReplacer(pairs...)
+}()
+
+// stripDiacritics replaces letters with diacritics by their base letters,
+// e.g. é by e, for sites that only accept ASCII passwords.
+func stripDiacritics(w string) string {
+	return diacriticsReplacer.Replace(w)
+}
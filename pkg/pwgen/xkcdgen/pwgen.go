@@ -2,7 +2,10 @@ package xkcdgen
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/pwgen"
 	"github.com/martinhoefling/goxkcdpwgen/xkcdpwgen"
 )
 
@@ -22,14 +25,87 @@ func RandomLength(length int, lang string) (string, error) {
 // RandomLengthDelim returns a random passphrase combined from the desired number
 // of words and the given delimiter. Words are drawn from lang.
 func RandomLengthDelim(length int, delim, lang string) (string, error) {
-	g := xkcdpwgen.NewGenerator()
-	g.SetNumWords(length)
-	g.SetDelimiter(delim)
-	g.SetCapitalize(delim == "")
+	return RandomLengthOpts(length, Options{Delim: delim, Lang: lang})
+}
+
+// Options configure the generated passphrase.
+type Options struct {
+	// Delim separates the words. Words are capitalized if it is empty.
+	Delim string
+	// Lang selects the word list. The language of the locale is used if
+	// empty, see DetectLang.
+	Lang string
+	// Capitalize capitalizes the first letter of each word.
+	Capitalize bool
+	// ASCII replaces letters with diacritics by their base letters, e.g. é
+	// by e, for sites that only accept ASCII passwords.
+	ASCII bool
+}
+
+// RandomLengthOpts returns a random passphrase combined from the desired
+// number of words.
+func RandomLengthOpts(length int, opts Options) (string, error) {
+	if opts.Lang == "" {
+		opts.Lang = DetectLang()
+	}
+	if opts.Delim == "" {
+		opts.Capitalize = true
+	}
+
+	var pw string
+	if wl := wordlist(opts.Lang); len(wl) > 0 {
+		pw = fromWordlist(wl, length, opts)
+	} else if pwgen.IsDeterministic() {
+		pw = deterministic(length, opts)
+	} else {
+		g := xkcdpwgen.NewGenerator()
+		g.SetNumWords(length)
+		g.SetDelimiter(opts.Delim)
+		g.SetCapitalize(opts.Capitalize)
+
+		if err := g.UseLangWordlist(opts.Lang); err != nil {
+			return "", fmt.Errorf("failed to use wordlist for lang %s: %w", opts.Lang, err)
+		}
+
+		pw = string(g.GeneratePassword())
+	}
+
+	if opts.ASCII {
+		pw = stripDiacritics(pw)
+	}
+
+	return pw, nil
+}
+
+// fromWordlist draws the words from one of the embedded word lists using the
+// random source of pwgen.
+func fromWordlist(wl []string, length int, opts Options) string {
+	words := make([]string, 0, length)
+	for i := 0; i < length; i++ {
+		w := wl[pwgen.RandomInt(len(wl))]
+		if opts.Capitalize {
+			w = capitalize(w)
+		}
+		words = append(words, w)
+	}
+
+	return strings.Join(words, opts.Delim)
+}
+
+// deterministic draws the words from the seeded source of pwgen. The word
+// lists of xkcdpwgen always use crypto/rand, so the built-in english word list
+// is used instead of them.
+func deterministic(length int, opts Options) string {
+	debug.Log("deterministic mode, using the built-in english word list instead of %s", opts.Lang)
 
-	if err := g.UseLangWordlist(lang); err != nil {
-		return "", fmt.Errorf("failed to use wordlist for lang %s: %w", lang, err)
+	words := make([]string, 0, length)
+	for i := 0; i < length; i++ {
+		w := pwgen.RandomWord()
+		if opts.Capitalize {
+			w = capitalize(w)
+		}
+		words = append(words, w)
 	}
 
-	return string(g.GeneratePassword()), nil
+	return strings.Join(words, opts.Delim)
 }
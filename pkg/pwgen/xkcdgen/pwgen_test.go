@@ -4,7 +4,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gopasspw/gopass/pkg/pwgen"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRandom(t *testing.T) {
@@ -26,3 +28,94 @@ func TestRandomLengthDelim(t *testing.T) {
 	_, err := RandomLengthDelim(10, " ", "cn_ZH")
 	assert.Error(t, err)
 }
+
+func TestDeterministic(t *testing.T) {
+	defer pwgen.ResetSource()
+
+	t.Setenv(pwgen.UnsafeDeterministicEnv, "1")
+
+	require.NoError(t, pwgen.Seed(42))
+	first, err := RandomLengthDelim(4, "", "de")
+	require.NoError(t, err)
+
+	require.NoError(t, pwgen.Seed(42))
+	second, err := RandomLengthDelim(4, "", "de")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestWordlists(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"de", "en", "es", "fr", "it", "nl", "pt"}, Languages())
+
+	for _, lang := range []string{"es", "fr", "it", "nl", "pt"} {
+		wl := wordlist(lang)
+		seen := make(map[string]bool, len(wl))
+		for _, w := range wl {
+			assert.Equal(t, strings.ToLower(w), w, lang)
+			assert.False(t, seen[w], "%s: %s is listed twice", lang, w)
+			for _, r := range stripDiacritics(w) {
+				assert.Less(t, r, rune(128), "%s: %s", lang, w)
+			}
+			seen[w] = true
+		}
+		// at least 10 bits of entropy per word.
+		assert.GreaterOrEqual(t, len(seen), 1024, lang)
+	}
+
+	assert.Nil(t, wordlist("en"))
+}
+
+func TestDetectLang(t *testing.T) {
+	for _, tc := range []struct {
+		lcAll string
+		lang  string
+		want  string
+	}{
+		{lang: "fr_FR.UTF-8", want: "fr"},
+		{lang: "pt_BR", want: "pt"},
+		{lcAll: "nl_NL.UTF-8", lang: "fr_FR.UTF-8", want: "nl"},
+		{lcAll: "C", lang: "es_ES.UTF-8", want: "en"},
+		{lang: "ja_JP.UTF-8", want: "en"},
+		{want: "en"},
+	} {
+		t.Setenv("LC_ALL", tc.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tc.lang)
+
+		assert.Equal(t, tc.want, DetectLang(), tc)
+	}
+}
+
+func TestRandomLengthOpts(t *testing.T) {
+	defer pwgen.ResetSource()
+	t.Setenv(pwgen.UnsafeDeterministicEnv, "1")
+
+	require.NoError(t, pwgen.Seed(42))
+	first, err := RandomLengthOpts(4, Options{Delim: " ", Lang: "fr"})
+	require.NoError(t, err)
+
+	words := strings.Fields(first)
+	require.Len(t, words, 4)
+	for _, w := range words {
+		assert.Contains(t, wordlist("fr"), w)
+	}
+
+	require.NoError(t, pwgen.Seed(42))
+	second, err := RandomLengthOpts(4, Options{Delim: " ", Lang: "fr", Capitalize: true})
+	require.NoError(t, err)
+	for i, w := range strings.Fields(second) {
+		assert.Equal(t, capitalize(words[i]), w)
+	}
+
+	assert.Equal(t, "Ecole-Garcon-Noel", stripDiacritics("École-Garçon-Noël"))
+	assert.Equal(t, "Éclair", capitalize("éclair"))
+
+	pw, err := RandomLengthOpts(6, Options{Lang: "es", ASCII: true})
+	require.NoError(t, err)
+	for _, r := range pw {
+		assert.Less(t, r, rune(128), pw)
+	}
+}
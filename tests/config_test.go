@@ -18,6 +18,7 @@ func TestBaseConfig(t *testing.T) {
 	wanted := `core.autopush = true
 core.autosync = true
 core.cliptimeout = 45
+core.completionindex = true
 core.exportkeys = false
 core.notifications = true
 `
@@ -69,6 +70,7 @@ func TestMountConfig(t *testing.T) {
 	wanted := `core.autopush = true
 core.autosync = true
 core.cliptimeout = 45
+core.completionindex = true
 core.exportkeys = false
 core.notifications = true
 `